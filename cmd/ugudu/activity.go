@@ -1,10 +1,13 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 	"text/tabwriter"
 	"time"
 
@@ -12,12 +15,17 @@ import (
 	"github.com/spf13/cobra"
 )
 
+// activityFollowInterval is how often --follow checks for new entries
+const activityFollowInterval = 2 * time.Second
+
 func activityCmd() *cobra.Command {
+	var project string
 	var role string
 	var activityType string
 	var taskID string
 	var since string
 	var limit int
+	var follow bool
 	var outputJSON bool
 
 	cmd := &cobra.Command{
@@ -29,13 +37,23 @@ Activity types: tool_call, delegation, task_update, message, progress, error
 
 Examples:
   ugudu activity my-project                    # Recent activity
+  ugudu activity --project my-project          # Same, with a flag
   ugudu activity my-project --role engineer    # Filter by role
   ugudu activity my-project --type tool_call   # Filter by type
+  ugudu activity my-project --type tool_call,error
   ugudu activity my-project --since 1h         # Last hour
-  ugudu activity my-project --limit 50         # Last 50 entries`,
-		Args: cobra.ExactArgs(1),
+  ugudu activity my-project --limit 50         # Last 50 entries
+  ugudu activity my-project --follow           # Keep printing new entries`,
+		Args: cobra.MaximumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
-			projectName := args[0]
+			projectName := project
+			if len(args) == 1 {
+				projectName = args[0]
+			}
+			if projectName == "" {
+				fmt.Fprintln(os.Stderr, "Error: project name required (argument or --project)")
+				os.Exit(1)
+			}
 
 			// Load workspace
 			ws, err := workspace.New(projectName)
@@ -44,47 +62,33 @@ Examples:
 				os.Exit(1)
 			}
 
-			// Build query options
-			opts := workspace.QueryOptions{
-				Limit: limit,
-			}
-
-			// Parse since duration
-			if since != "" {
-				duration, err := parseDuration(since)
-				if err != nil {
-					fmt.Fprintf(os.Stderr, "Invalid duration: %v\n", err)
-					os.Exit(1)
-				}
-				opts.Since = time.Now().Add(-duration)
-			}
-
-			// Parse activity type filter
-			if activityType != "" {
-				opts.Types = []workspace.ActivityType{workspace.ActivityType(activityType)}
-			}
-
-			// Task ID filter
-			if taskID != "" {
-				opts.TaskID = taskID
+			opts, err := activityQuery(limit, since, activityType, taskID, time.Now())
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
 			}
 
 			// Query activity
-			var entries []workspace.ActivityEntry
-			if role != "" {
-				// Query single role log
-				logPath := ws.ActivityPath(role)
-				entries, err = workspace.QueryActivityLog(logPath, opts)
-			} else {
+			query := func(opts workspace.QueryOptions) ([]workspace.ActivityEntry, error) {
+				if role != "" {
+					// Query single role log
+					return workspace.QueryActivityLog(ws.ActivityPath(role), opts)
+				}
 				// Query all activity
-				entries, err = workspace.QueryProjectActivity(ws, opts)
+				return workspace.QueryProjectActivity(ws, opts)
 			}
 
+			entries, err := query(opts)
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "Error querying activity: %v\n", err)
 				os.Exit(1)
 			}
 
+			if follow {
+				followActivity(entries, opts, query, outputJSON)
+				return
+			}
+
 			if len(entries) == 0 {
 				fmt.Println("No activity found.")
 				return
@@ -100,16 +104,116 @@ Examples:
 		},
 	}
 
+	cmd.Flags().StringVarP(&project, "project", "p", "", "project name")
 	cmd.Flags().StringVarP(&role, "role", "r", "", "filter by role (engineer, pm, qa, ba)")
-	cmd.Flags().StringVarP(&activityType, "type", "t", "", "filter by activity type")
+	cmd.Flags().StringVarP(&activityType, "type", "t", "", "filter by activity type (comma-separated for several)")
 	cmd.Flags().StringVar(&taskID, "task", "", "filter by task ID")
 	cmd.Flags().StringVarP(&since, "since", "s", "", "show activity since duration (e.g., 1h, 30m, 1d)")
 	cmd.Flags().IntVarP(&limit, "limit", "n", 20, "maximum number of entries")
-	cmd.Flags().BoolVar(&outputJSON, "json", false, "output as JSON")
+	cmd.Flags().BoolVarP(&follow, "follow", "f", false, "keep printing new entries until interrupted")
+	cmd.Flags().BoolVar(&outputJSON, "json", false, "output as JSON (one entry per line with --follow)")
 
 	return cmd
 }
 
+// activityQuery builds activity query options from the command's flags
+func activityQuery(limit int, since, activityType, taskID string, now time.Time) (workspace.QueryOptions, error) {
+	if limit < 0 {
+		return workspace.QueryOptions{}, fmt.Errorf("limit must not be negative, got %d", limit)
+	}
+	opts := workspace.QueryOptions{
+		Limit:  limit,
+		TaskID: taskID,
+	}
+
+	// Parse since duration
+	if since != "" {
+		duration, err := parseDuration(since)
+		if err != nil {
+			return opts, fmt.Errorf("invalid duration: %w", err)
+		}
+		opts.Since = now.Add(-duration)
+	}
+
+	// Parse activity type filter
+	for _, t := range strings.Split(activityType, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			opts.Types = append(opts.Types, workspace.ActivityType(t))
+		}
+	}
+
+	return opts, nil
+}
+
+// followActivity prints entries oldest first, then polls for new ones until
+// interrupted
+func followActivity(entries []workspace.ActivityEntry, opts workspace.QueryOptions, query func(workspace.QueryOptions) ([]workspace.ActivityEntry, error), outputJSON bool) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Polls ask for entries at or after the latest one printed, so only
+	// entries sharing that timestamp can come back twice
+	var latest time.Time
+	seen := make(map[string]bool) // IDs printed at latest
+	emit := func(entries []workspace.ActivityEntry) {
+		// Queries return the most recent first
+		for i := len(entries) - 1; i >= 0; i-- {
+			e := entries[i]
+			if e.Timestamp.Before(latest) || seen[e.ID] {
+				continue
+			}
+			if e.Timestamp.After(latest) {
+				latest = e.Timestamp
+				seen = make(map[string]bool)
+			}
+			seen[e.ID] = true
+			if outputJSON {
+				data, _ := json.Marshal(e)
+				fmt.Println(string(data))
+			} else {
+				printActivityLine(e)
+			}
+		}
+	}
+	emit(entries)
+
+	// New entries only, however many arrive between polls
+	opts.Limit = 0
+	ticker := time.NewTicker(activityFollowInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if !latest.IsZero() {
+			opts.Since = latest
+		}
+		entries, err := query(opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error querying activity: %v\n", err)
+			continue
+		}
+		emit(entries)
+	}
+}
+
+// printActivityLine prints one entry in the table's columns, for output that
+// grows as entries arrive
+func printActivityLine(e workspace.ActivityEntry) {
+	status := "OK"
+	if !e.Success {
+		status = "FAIL"
+	}
+	details := formatActivityDetails(e)
+	if len(details) > 50 {
+		details = details[:47] + "..."
+	}
+	fmt.Printf("%s  %-10s  %-12s  %-50s  %s\n", e.Timestamp.Format("15:04:05"), e.AgentRole, e.Type, details, status)
+}
+
 func printActivityTable(entries []workspace.ActivityEntry) {
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
 	fmt.Fprintln(w, "TIMESTAMP\tROLE\tTYPE\tDETAILS\tSTATUS")
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/arcslash/ugudu/internal/workspace"
+)
+
+func TestActivityQuery(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		limit     int
+		since     string
+		actType   string
+		taskID    string
+		want      workspace.QueryOptions
+		wantError bool
+	}{
+		{
+			name:  "limit applies",
+			limit: 5,
+			want:  workspace.QueryOptions{Limit: 5},
+		},
+		{
+			name:  "since in hours",
+			limit: 20,
+			since: "2h",
+			want:  workspace.QueryOptions{Limit: 20, Since: now.Add(-2 * time.Hour)},
+		},
+		{
+			name:  "since in days",
+			since: "1d",
+			want:  workspace.QueryOptions{Since: now.Add(-24 * time.Hour)},
+		},
+		{
+			name:    "several types and a task",
+			actType: "tool_call, error,",
+			taskID:  "task-1",
+			want: workspace.QueryOptions{
+				Types:  []workspace.ActivityType{workspace.ActivityToolCall, workspace.ActivityError},
+				TaskID: "task-1",
+			},
+		},
+		{
+			name:      "bad duration",
+			since:     "yesterday",
+			wantError: true,
+		},
+		{
+			name:      "negative limit",
+			limit:     -1,
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := activityQuery(tt.limit, tt.since, tt.actType, tt.taskID, now)
+			if tt.wantError {
+				if err == nil {
+					t.Errorf("Expected an error, got %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("activityQuery failed: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
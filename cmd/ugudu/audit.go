@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/arcslash/ugudu/internal/manager"
+	"github.com/spf13/cobra"
+)
+
+func debugAuditCmd() *cobra.Command {
+	var filter manager.AuditFilter
+	var since, until string
+	var outputJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Show the audit log",
+		Long: `Show the daemon's append-only audit log: chat requests and responses, tool
+calls, team lifecycle and settings changes.
+
+Examples:
+  ugudu debug audit                          # Last 100 entries
+  ugudu debug audit --team my-team --since 24h
+  ugudu debug audit --action tool            # tool.execute and tool.denied
+  ugudu debug audit --request-id 5f1c...     # Everything one chat caused
+  ugudu debug audit --json > audit.json`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			var err error
+			now := time.Now()
+			if filter.Since, err = auditTime(since, now); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: --since: %v\n", err)
+				os.Exit(1)
+			}
+			if filter.Until, err = auditTime(until, now); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: --until: %v\n", err)
+				os.Exit(1)
+			}
+
+			client, err := requireDaemon()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+
+			entries, err := client.AuditLog(ctx, filter)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			if outputJSON {
+				data, _ := json.MarshalIndent(entries, "", "  ")
+				fmt.Println(string(data))
+				return
+			}
+
+			if len(entries) == 0 {
+				fmt.Println("No audit entries found.")
+				return
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "TIME\tACTION\tTEAM\tACTOR\tREQUEST\tDETAILS")
+			for _, e := range entries {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+					e.Timestamp.Local().Format("2006-01-02 15:04:05"), e.Action,
+					orDash(e.Team), orDash(e.Actor), orDash(e.RequestID), auditDetails(e.Details))
+			}
+			w.Flush()
+		},
+	}
+
+	cmd.Flags().StringVar(&filter.Team, "team", "", "only entries for this team")
+	cmd.Flags().StringVar(&filter.Action, "action", "", "only this action, or a group such as \"team\"")
+	cmd.Flags().StringVar(&filter.Actor, "actor", "", "only entries by this actor (client, a member ID, daemon)")
+	cmd.Flags().StringVar(&filter.RequestID, "request-id", "", "only entries for this request")
+	cmd.Flags().StringVar(&since, "since", "", "only entries from this long ago (e.g. 24h) or this RFC 3339 time")
+	cmd.Flags().StringVar(&until, "until", "", "only entries before this long ago or this RFC 3339 time")
+	cmd.Flags().IntVarP(&filter.Limit, "limit", "n", 100, "number of entries to show (newest)")
+	cmd.Flags().BoolVar(&outputJSON, "json", false, "output as JSON")
+
+	return cmd
+}
+
+// auditTime reads a --since or --until value: a duration before now or an
+// RFC 3339 time. Empty is the zero time.
+func auditTime(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is neither a duration nor an RFC 3339 time", value)
+	}
+	return t, nil
+}
+
+// auditDetails renders an entry's details on one line, long values cut short
+func auditDetails(details map[string]interface{}) string {
+	keys := make([]string, 0, len(details))
+	for k := range details {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		value := fmt.Sprint(details[k])
+		if data, err := json.Marshal(details[k]); err == nil && !strings.HasPrefix(string(data), "\"") {
+			value = string(data)
+		}
+		value = strings.Join(strings.Fields(value), " ")
+		if runes := []rune(value); len(runes) > 60 {
+			value = string(runes[:57]) + "..."
+		}
+		parts[i] = k + "=" + value
+	}
+	return strings.Join(parts, " ")
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,276 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/arcslash/ugudu/internal/daemon"
+	"github.com/spf13/cobra"
+)
+
+func chatCmd() *cobra.Command {
+	var toMember string
+	var timeout int
+	var verbose bool
+
+	cmd := &cobra.Command{
+		Use:   "chat [team-name]",
+		Short: "Start an interactive chat session with a team",
+		Long: `Open an interactive session with a team. Each line you type is sent to
+the team and responses print as they arrive. The team's conversation is
+persisted, so context carries across turns and across sessions.
+
+Commands:
+  /to <role>         Send following messages to a specific role
+  /to                Go back to the client-facing member
+  /clear             Clear the conversation history
+  /tokenmode <mode>  Set token mode: normal, low or minimal
+  /status            Show team members and what they're doing
+  /continue          Pick up the rest of a turn that timed out
+  /help              Show these commands
+  /quit              Leave the session (Ctrl-D also works)
+
+Press Ctrl-C to cancel the current turn without leaving the session.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			client, err := requireDaemon()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			session := &chatSession{
+				client:  client,
+				team:    args[0],
+				to:      toMember,
+				timeout: time.Duration(timeout) * time.Second,
+			}
+			if verbose {
+				session.verbosity = "full"
+				session.verbose = true
+			}
+
+			if err := session.run(); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&toMember, "to", "", "send to specific role")
+	cmd.Flags().IntVar(&timeout, "timeout", 600, "timeout in seconds for each turn")
+	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "include internal delegation results, tool summaries and the model behind each reply")
+
+	return cmd
+}
+
+// chatSession holds the state of an interactive chat
+type chatSession struct {
+	client    *daemon.Client
+	team      string
+	to        string
+	verbosity string
+	verbose   bool // Show the model behind each reply
+	timeout   time.Duration
+
+	// Token for the last turn that timed out while the team was working
+	continuation string
+
+	// Cancels the turn in flight, nil while waiting for input
+	cancelTurn context.CancelFunc
+	mu         sync.Mutex
+}
+
+func (s *chatSession) run() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	err := s.client.StartTeam(ctx, s.team)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	// Ctrl-C cancels the current turn rather than exiting
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+	defer signal.Stop(interrupts)
+	go func() {
+		for range interrupts {
+			s.mu.Lock()
+			cancelTurn := s.cancelTurn
+			s.mu.Unlock()
+
+			if cancelTurn != nil {
+				cancelTurn()
+				continue
+			}
+			fmt.Print("\n(type /quit to leave)\n" + s.prompt())
+		}
+	}()
+
+	fmt.Printf("Chatting with %s. Type /help for commands, /quit to leave.\n", s.team)
+
+	scanner := bufio.NewScanner(os.Stdin)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for {
+		fmt.Print(s.prompt())
+		if !scanner.Scan() {
+			fmt.Println()
+			return scanner.Err()
+		}
+
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "/") {
+			if quit := s.command(line); quit {
+				return nil
+			}
+			continue
+		}
+		s.send(line)
+	}
+}
+
+func (s *chatSession) prompt() string {
+	if s.to != "" {
+		return fmt.Sprintf("%s/%s> ", s.team, s.to)
+	}
+	return s.team + "> "
+}
+
+// send runs one turn, printing responses as they stream in
+func (s *chatSession) send(message string) {
+	s.turn(func(ctx context.Context, opts daemon.ChatOptions, onResponse func(map[string]interface{})) (daemon.ChatOutcome, error) {
+		return s.client.ChatStream(ctx, s.team, message, opts, onResponse)
+	})
+}
+
+// resume continues the last turn that timed out
+func (s *chatSession) resume() {
+	token := s.continuation
+	if token == "" {
+		fmt.Println("Nothing to continue.")
+		return
+	}
+	s.turn(func(ctx context.Context, opts daemon.ChatOptions, onResponse func(map[string]interface{})) (daemon.ChatOutcome, error) {
+		return s.client.ContinueChat(ctx, s.team, token, opts, onResponse)
+	})
+}
+
+// turn runs a streamed request that Ctrl-C can cancel. The daemon is asked
+// to give up slightly before we do, so a slow team comes back with a
+// continuation token rather than a dropped connection.
+func (s *chatSession) turn(do func(context.Context, daemon.ChatOptions, func(map[string]interface{})) (daemon.ChatOutcome, error)) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout+15*time.Second)
+	defer cancel()
+
+	s.mu.Lock()
+	s.cancelTurn = cancel
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.cancelTurn = nil
+		s.mu.Unlock()
+	}()
+
+	opts := daemon.ChatOptions{To: s.to, Verbosity: s.verbosity, Timeout: s.timeout}
+	outcome, err := do(ctx, opts, func(resp map[string]interface{}) {
+		from, _ := resp["from"].(string)
+		content, _ := resp["content"].(string)
+		if internal, _ := resp["internal"].(bool); internal {
+			fmt.Printf("  [internal] %s: %s\n", from, content)
+			return
+		}
+		if progress, _ := resp["progress"].(bool); progress {
+			fmt.Printf("  ... %s\n", content)
+			return
+		}
+		fmt.Printf("\n%s: %s\n", from, content)
+		if served := servedBy(resp); s.verbose && served != "" {
+			fmt.Printf("  (%s)\n", served)
+		}
+		fmt.Println()
+	})
+
+	s.continuation = ""
+	switch {
+	case ctx.Err() == context.Canceled:
+		fmt.Println("\n(cancelled)")
+	case outcome.TimedOut && outcome.ContinuationToken != "":
+		s.continuation = outcome.ContinuationToken
+		fmt.Println("\n(timed out waiting for the team; it may still be working. Type /continue to pick up the rest)")
+	case ctx.Err() == context.DeadlineExceeded || outcome.TimedOut:
+		fmt.Println("\n(timed out waiting for the team)")
+	case err != nil:
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	case outcome.Restarted:
+		fmt.Println("(the timed out turn had expired, so the team was asked to pick up where it left off)")
+	}
+}
+
+// command handles a /meta-command, returning true when the session should end
+func (s *chatSession) command(line string) bool {
+	fields := strings.Fields(line)
+	name, args := fields[0], fields[1:]
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	switch name {
+	case "/quit", "/exit":
+		return true
+
+	case "/help":
+		fmt.Println("/to <role>, /to, /clear, /tokenmode <normal|low|minimal>, /status, /continue, /quit")
+
+	case "/to":
+		if len(args) == 0 {
+			s.to = ""
+			fmt.Println("Sending to the client-facing member.")
+			return false
+		}
+		s.to = args[0]
+		fmt.Printf("Sending to %s.\n", s.to)
+
+	case "/clear":
+		if err := s.client.ClearConversation(ctx, s.team); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return false
+		}
+		fmt.Println("Conversation cleared. The next message starts a fresh context.")
+
+	case "/tokenmode":
+		if len(args) != 1 {
+			fmt.Println("Usage: /tokenmode <normal|low|minimal>")
+			return false
+		}
+		if err := s.client.SetTokenMode(ctx, s.team, args[0], false); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return false
+		}
+		fmt.Printf("Token mode set to %s.\n", args[0])
+
+	case "/continue":
+		s.resume()
+
+	case "/status":
+		members, err := s.client.TeamMembers(ctx, s.team)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return false
+		}
+		printTeamMembers(os.Stdout, s.team, members, true)
+
+	default:
+		fmt.Printf("Unknown command %s. Type /help for commands.\n", name)
+	}
+
+	return false
+}
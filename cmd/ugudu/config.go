@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/arcslash/ugudu/internal/config"
 	"github.com/spf13/cobra"
@@ -153,11 +154,10 @@ This will guide you through setting up:
 			fmt.Println()
 			fmt.Printf("Configuration saved to %s\n", config.ConfigPath())
 			fmt.Println()
-			fmt.Println("Directory structure created:")
-			fmt.Printf("  %s/\n", config.UguduHome())
-			fmt.Println("  ├── config.yaml    # Your configuration")
-			fmt.Println("  ├── specs/         # Spec YAML files (blueprints)")
-			fmt.Println("  └── data/          # Database")
+			fmt.Println("Directories created:")
+			fmt.Printf("  %s    # Your configuration\n", config.ConfigPath())
+			fmt.Printf("  %s/    # Spec YAML files (blueprints)\n", config.SpecsDir())
+			fmt.Printf("  %s/    # Database\n", config.DataDir())
 			fmt.Println()
 			fmt.Println("Next: Start the daemon with 'ugudu daemon'")
 		},
@@ -218,6 +218,7 @@ Keys:
   ollama.url           Ollama URL
   defaults.provider    Default AI provider
   defaults.model       Default model
+  defaults.timezone    IANA timezone members work in, e.g. Europe/London
   daemon.tcp_addr      Daemon HTTP address
 
 Examples:
@@ -254,13 +255,19 @@ Examples:
 				cfg.Defaults.Provider = value
 			case "defaults.model":
 				cfg.Defaults.Model = value
+			case "defaults.timezone":
+				if _, err := time.LoadLocation(value); err != nil {
+					fmt.Fprintf(os.Stderr, "Error: unknown timezone %q, use an IANA name such as Europe/London\n", value)
+					os.Exit(1)
+				}
+				cfg.Defaults.Timezone = value
 			case "daemon.tcp_addr":
 				cfg.Daemon.TCPAddr = value
 			default:
 				fmt.Fprintf(os.Stderr, "Unknown key: %s\n", key)
 				fmt.Println("\nAvailable keys:")
 				fmt.Println("  anthropic.api_key, openai.api_key, groq.api_key")
-				fmt.Println("  ollama.url, defaults.provider, defaults.model")
+				fmt.Println("  ollama.url, defaults.provider, defaults.model, defaults.timezone")
 				fmt.Println("  daemon.tcp_addr")
 				os.Exit(1)
 			}
@@ -24,6 +24,8 @@ This means your chat history with teams survives crashes and restarts.`,
 
 	cmd.AddCommand(conversationListCmd())
 	cmd.AddCommand(conversationShowCmd())
+	cmd.AddCommand(conversationSummaryCmd())
+	cmd.AddCommand(conversationRenameCmd())
 	cmd.AddCommand(conversationClearCmd())
 
 	return cmd
@@ -58,21 +60,21 @@ func conversationListCmd() *cobra.Command {
 			}
 
 			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-			fmt.Fprintln(w, "ID\tSTARTED\tLAST MESSAGE\tSTATUS")
-			fmt.Fprintln(w, "──\t───────\t────────────\t──────")
+			fmt.Fprintln(w, "ID\tTITLE\tSTARTED\tLAST MESSAGE\tSTATUS")
+			fmt.Fprintln(w, "──\t─────\t───────\t────────────\t──────")
 
 			for _, conv := range conversations {
 				id, _ := conv["id"].(string)
+				title, _ := conv["title"].(string)
 				startedAt, _ := conv["started_at"].(string)
 				lastMsg, _ := conv["last_message_at"].(string)
 				status, _ := conv["status"].(string)
 
-				// Truncate ID for display
-				if len(id) > 20 {
-					id = id[:17] + "..."
+				if title == "" {
+					title = "-"
 				}
 
-				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", id, startedAt, lastMsg, status)
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", id, title, startedAt, lastMsg, status)
 			}
 			w.Flush()
 		},
@@ -140,6 +142,90 @@ func conversationShowCmd() *cobra.Command {
 	return cmd
 }
 
+func conversationSummaryCmd() *cobra.Command {
+	var outputJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "summary [conversation-id]",
+		Short: "Summarize a conversation",
+		Long: `Summarize a conversation's key decisions, open questions and artifacts.
+
+The summary is written by the model the conversation's team talks to you
+with, or a small model from a configured provider if the team isn't loaded,
+and is reused until the conversation has new messages.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			client, err := requireDaemon()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+			defer cancel()
+
+			summary, err := client.SummarizeConversation(ctx, args[0])
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			if outputJSON {
+				data, _ := json.MarshalIndent(summary, "", "  ")
+				fmt.Println(string(data))
+				return
+			}
+
+			fmt.Println(summary.Summary)
+			printSummaryList("Key decisions", summary.Decisions)
+			printSummaryList("Open questions", summary.OpenQuestions)
+			printSummaryList("Artifacts", summary.Artifacts)
+			fmt.Printf("\n(%d messages, summarized by %s/%s)\n", summary.Sequence, summary.Provider, summary.Model)
+		},
+	}
+
+	cmd.Flags().BoolVar(&outputJSON, "json", false, "output as JSON")
+
+	return cmd
+}
+
+func printSummaryList(heading string, items []string) {
+	if len(items) == 0 {
+		return
+	}
+	fmt.Printf("\n%s:\n", heading)
+	for _, item := range items {
+		fmt.Printf("  - %s\n", item)
+	}
+}
+
+func conversationRenameCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rename [conversation-id] [title]",
+		Short: "Set a conversation's title",
+		Long: `Set the title shown for a conversation in 'conversation list'.
+
+New conversations are titled after their first message.`,
+		Args: cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			client, err := requireDaemon()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+
+			if err := client.RenameConversation(ctx, args[0], args[1]); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Printf("Renamed %s to %q.\n", args[0], args[1])
+		},
+	}
+}
+
 func conversationClearCmd() *cobra.Command {
 	var force bool
 
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/arcslash/ugudu/internal/config"
+	"github.com/arcslash/ugudu/internal/templates"
+	"github.com/arcslash/ugudu/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+// initOptions controls what `ugudu init` sets up
+type initOptions struct {
+	project string // Project workspace to create for the directory
+	spec    string // Built-in template to copy into the specs directory
+	team    string // Team the project uses, defaulting to the spec
+	yes     bool   // Don't prompt; skip anything not asked for by flags
+}
+
+func initCmd() *cobra.Command {
+	var opts initOptions
+
+	cmd := &cobra.Command{
+		Use:   "init",
+		Short: "Set up the current directory for Ugudu",
+		Long: `Set up the current directory as a project for Ugudu teams:
+
+  - export the example team templates to ./teams/
+  - write a .ugudu-ignore listing paths agents may not read or write
+  - optionally copy a template into your specs (--spec)
+  - optionally create a project workspace with this directory as its
+    source (--with-project)
+
+Anything that already exists is left alone, so init is safe to run again.
+It asks about the spec and project unless -y is given.
+
+Examples:
+  ugudu init
+  ugudu init -y --with-project my-app --spec dev-team`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			dir, err := os.Getwd()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			if err := scaffold(dir, opts, bufio.NewReader(os.Stdin)); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.project, "with-project", "", "create a project workspace with this name for the current directory")
+	cmd.Flags().StringVar(&opts.spec, "spec", "", "copy this built-in template into your specs")
+	cmd.Flags().StringVar(&opts.team, "team", "", "team the project uses (default: the --spec template, or dev-team)")
+	cmd.Flags().BoolVarP(&opts.yes, "yes", "y", false, "don't prompt")
+
+	return cmd
+}
+
+// scaffold sets dir up for Ugudu. Existing files, specs and projects are
+// never overwritten.
+func scaffold(dir string, opts initOptions, reader *bufio.Reader) error {
+	teamsDir := filepath.Join(dir, "teams")
+	if err := os.MkdirAll(teamsDir, 0755); err != nil {
+		return fmt.Errorf("create teams directory: %w", err)
+	}
+
+	// Export embedded templates
+	names, _ := templates.List()
+	for _, name := range names {
+		content, _ := templates.Get(name)
+		writeIfMissing(filepath.Join(teamsDir, name+".yaml"), content)
+	}
+
+	writeIfMissing(filepath.Join(dir, workspace.IgnoreFile), []byte(workspace.DefaultIgnore))
+
+	if opts.spec == "" && !opts.yes {
+		opts.spec = prompt(reader, fmt.Sprintf("Copy a template into your specs? (%s, blank to skip)", strings.Join(names, ", ")), "")
+	}
+	if opts.spec != "" {
+		content, err := templates.Get(opts.spec)
+		if err != nil {
+			return fmt.Errorf("unknown template %q (available: %s)", opts.spec, strings.Join(names, ", "))
+		}
+		if err := os.MkdirAll(config.SpecsDir(), 0755); err != nil {
+			return fmt.Errorf("create specs directory: %w", err)
+		}
+		writeIfMissing(filepath.Join(config.SpecsDir(), opts.spec+".yaml"), content)
+	}
+
+	if opts.project == "" && !opts.yes {
+		opts.project = prompt(reader, "Create a project workspace for this directory? (project name, blank to skip)", "")
+	}
+	if opts.project != "" {
+		team := opts.team
+		if team == "" {
+			team = opts.spec
+		}
+		if team == "" {
+			team = "dev-team"
+		}
+		if err := initProject(opts.project, dir, team); err != nil {
+			return err
+		}
+	}
+
+	fmt.Println("\nDone!")
+	fmt.Println("\nNext steps:")
+	fmt.Println("  1. Start the daemon: ugudu daemon --tcp :8080")
+	fmt.Println("  2. Create a team: ugudu team create teams/dev-team.yaml")
+	fmt.Println("  3. Start it: ugudu team start dev-team")
+	fmt.Println("  4. Ask it something: ugudu ask dev-team \"Hello team!\"")
+	return nil
+}
+
+// initProject creates a project workspace for dir unless one with that name
+// already exists
+func initProject(name, dir, team string) error {
+	if _, err := os.Stat(filepath.Join(config.ProjectsDir(), name)); err == nil {
+		fmt.Printf("  Skipping project %s (already exists)\n", name)
+		return nil
+	}
+
+	ws, err := workspace.Init(name, dir, team)
+	if err != nil {
+		return fmt.Errorf("create project: %w", err)
+	}
+	fmt.Printf("  Created project %s (%s)\n", name, ws.Path)
+	return nil
+}
+
+// writeIfMissing writes a file unless something is already at path
+func writeIfMissing(path string, content []byte) {
+	if _, err := os.Stat(path); err == nil {
+		fmt.Printf("  Skipping %s (already exists)\n", path)
+		return
+	}
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", path, err)
+		return
+	}
+	fmt.Printf("  Created %s\n", path)
+}
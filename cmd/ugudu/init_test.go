@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/arcslash/ugudu/internal/config"
+	"github.com/arcslash/ugudu/internal/workspace"
+)
+
+func TestScaffold_RunTwiceKeepsExistingFiles(t *testing.T) {
+	t.Setenv("UGUDU_HOME", t.TempDir())
+	t.Setenv("UGUDU_PROJECTS", t.TempDir())
+	dir := t.TempDir()
+
+	opts := initOptions{project: "my-app", spec: "dev-team", yes: true}
+	if err := scaffold(dir, opts, bufio.NewReader(strings.NewReader(""))); err != nil {
+		t.Fatalf("First init failed: %v", err)
+	}
+
+	ws, err := workspace.New("my-app")
+	if err != nil {
+		t.Fatalf("Expected the project to be created: %v", err)
+	}
+	if ws.Config.Source.Path != dir || ws.Config.Team != "dev-team" {
+		t.Errorf("Expected project for %s using dev-team, got source %s team %s", dir, ws.Config.Source.Path, ws.Config.Team)
+	}
+
+	// Edit everything init created, then run it again
+	edited := map[string]string{
+		filepath.Join(dir, workspace.IgnoreFile):          "secrets/\n",
+		filepath.Join(dir, "teams", "dev-team.yaml"):      "# my team\n",
+		filepath.Join(config.SpecsDir(), "dev-team.yaml"): "# my spec\n",
+	}
+	for path, content := range edited {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Edit %s failed: %v", path, err)
+		}
+	}
+
+	if err := scaffold(dir, opts, bufio.NewReader(strings.NewReader(""))); err != nil {
+		t.Fatalf("Second init failed: %v", err)
+	}
+
+	for path, want := range edited {
+		got, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("Read %s failed: %v", path, err)
+		}
+		if string(got) != want {
+			t.Errorf("%s was overwritten: got %q", path, got)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/arcslash/ugudu/internal/logger"
+	"github.com/spf13/cobra"
+)
+
+// logsFollowInterval is how often --follow asks the daemon for new records
+const logsFollowInterval = 2 * time.Second
+
+func logsCmd() *cobra.Command {
+	var filter logger.Filter
+	var level, since string
+	var follow bool
+	var outputJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "logs",
+		Short: "Show the daemon's recent logs",
+		Long: `Show the daemon's most recent log lines, kept in memory so they can be read
+without access to its output, e.g. when it runs as a service. The daemon
+keeps the last 1000 (daemon.log_buffer_size in ~/.ugudu/config.yaml).
+
+Examples:
+  ugudu logs                          # Last 100 lines
+  ugudu logs --level error --follow   # Errors, as they happen
+  ugudu logs --team my-team --since 1h
+  ugudu logs --json > logs.json`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			var err error
+			if level != "" {
+				if filter.Level, err = logger.ParseLevel(level); err != nil {
+					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+					os.Exit(1)
+				}
+			}
+			if filter.Since, err = auditTime(since, time.Now()); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: --since: %v\n", err)
+				os.Exit(1)
+			}
+
+			client, err := requireDaemon()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			records, err := client.Logs(ctx, filter)
+			cancel()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			if !follow {
+				if outputJSON {
+					data, _ := json.MarshalIndent(records, "", "  ")
+					fmt.Println(string(data))
+					return
+				}
+				if len(records) == 0 {
+					fmt.Println("No log records found.")
+					return
+				}
+				for _, r := range records {
+					printLogRecord(r)
+				}
+				return
+			}
+
+			sigCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			emit := func(records []logger.Record) {
+				for _, r := range records {
+					if outputJSON {
+						data, _ := json.Marshal(r)
+						fmt.Println(string(data))
+					} else {
+						printLogRecord(r)
+					}
+					filter.After = r.Seq
+				}
+			}
+			emit(records)
+
+			// New records only, however many arrive between polls
+			filter.Since = time.Time{}
+			filter.Limit = 10000
+			ticker := time.NewTicker(logsFollowInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-sigCtx.Done():
+					return
+				case <-ticker.C:
+				}
+
+				ctx, cancel := context.WithTimeout(sigCtx, 30*time.Second)
+				records, err := client.Logs(ctx, filter)
+				cancel()
+				if err != nil {
+					if sigCtx.Err() == nil {
+						fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+					}
+					continue
+				}
+				emit(records)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&level, "level", "l", "", "only this level and above (debug, info, warn, error)")
+	cmd.Flags().StringVar(&filter.Team, "team", "", "only lines about this team")
+	cmd.Flags().StringVar(&since, "since", "", "only lines from this long ago (e.g. 1h) or this RFC 3339 time")
+	cmd.Flags().IntVarP(&filter.Limit, "limit", "n", 100, "number of lines to show (newest)")
+	cmd.Flags().BoolVarP(&follow, "follow", "f", false, "keep printing new lines until interrupted")
+	cmd.Flags().BoolVar(&outputJSON, "json", false, "output as JSON (one record per line with --follow)")
+
+	return cmd
+}
+
+// printLogRecord prints a record the way the daemon writes it to its output
+func printLogRecord(r logger.Record) {
+	keys := make([]string, 0, len(r.Fields))
+	for k := range r.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var fields strings.Builder
+	for _, k := range keys {
+		fmt.Fprintf(&fields, " %s=%s", k, r.Fields[k])
+	}
+	fmt.Printf("%s %s %s%s\n", r.Time.Local().Format("2006-01-02 15:04:05"), strings.ToUpper(r.Level), r.Message, fields.String())
+}
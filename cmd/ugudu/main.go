@@ -1,20 +1,30 @@
 package main
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"syscall"
 	"text/tabwriter"
 	"time"
+	"unicode/utf8"
 
+	"github.com/arcslash/ugudu/internal/api"
 	"github.com/arcslash/ugudu/internal/config"
 	"github.com/arcslash/ugudu/internal/daemon"
 	"github.com/arcslash/ugudu/internal/logger"
 	"github.com/arcslash/ugudu/internal/mcp"
+	"github.com/arcslash/ugudu/internal/team"
 	"github.com/arcslash/ugudu/internal/templates"
+	"github.com/arcslash/ugudu/internal/version"
 	"github.com/joho/godotenv"
 	"github.com/spf13/cobra"
 )
@@ -30,7 +40,9 @@ func main() {
 
 	// Load Ugudu config and apply to environment
 	if cfg, err := config.Load(); err == nil {
-		cfg.ApplyToEnvironment()
+		if err := cfg.ApplyToEnvironment(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
 	}
 
 	root := &cobra.Command{
@@ -68,11 +80,14 @@ Examples:
 	root.AddCommand(projectCmd())
 	root.AddCommand(standupCmd())
 	root.AddCommand(activityCmd())
+	root.AddCommand(logsCmd())
 	root.AddCommand(askCmd())
+	root.AddCommand(chatCmd())
 	root.AddCommand(statusCmd())
 	root.AddCommand(providerCmd())
 	root.AddCommand(initCmd())
 	root.AddCommand(versionCmd())
+	root.AddCommand(whoamiCmd())
 	root.AddCommand(debugCmd())
 	root.AddCommand(templatesCmd())
 	root.AddCommand(conversationCmd())
@@ -86,10 +101,11 @@ Examples:
 
 // getClient returns a daemon client
 func getClient() (*daemon.Client, error) {
-	if remoteAddr != "" {
-		return daemon.NewRemoteClient(remoteAddr), nil
+	target := resolveDaemonTarget(remoteAddr, socketPath, daemon.FindSocket)
+	if target.Host != "" {
+		return daemon.NewRemoteClient(target.Host), nil
 	}
-	return daemon.NewClient(socketPath)
+	return daemon.NewClient(target.Socket)
 }
 
 // requireDaemon ensures daemon is running before executing a command
@@ -117,6 +133,7 @@ func daemonCmd() *cobra.Command {
 	var dataDir string
 	var tcpAddr string
 	var foreground bool
+	limits := api.DefaultChatLimits()
 
 	cmd := &cobra.Command{
 		Use:   "daemon",
@@ -136,8 +153,7 @@ Examples:
   ugudu daemon --data ~/.ugudu    # Custom data directory`,
 		Run: func(cmd *cobra.Command, args []string) {
 			if dataDir == "" {
-				home, _ := os.UserHomeDir()
-				dataDir = filepath.Join(home, ".ugudu", "data")
+				dataDir = config.DataDir()
 			}
 
 			cfg := daemon.Config{
@@ -145,6 +161,7 @@ Examples:
 				SocketPath: socketPath,
 				TCPAddr:    tcpAddr,
 				LogLevel:   "info",
+				ChatLimits: &limits,
 			}
 
 			d, err := daemon.New(cfg)
@@ -172,16 +189,103 @@ Examples:
 		},
 	}
 
-	home, _ := os.UserHomeDir()
-	defaultDataDir := filepath.Join(home, ".ugudu", "data")
-
-	cmd.Flags().StringVar(&dataDir, "data", defaultDataDir, "data directory")
+	cmd.Flags().StringVar(&dataDir, "data", config.DataDir(), "data directory")
 	cmd.Flags().StringVar(&tcpAddr, "tcp", ":9741", "TCP address for HTTP/Web UI (default :9741)")
 	cmd.Flags().BoolVar(&foreground, "foreground", true, "run in foreground (default)")
+	cmd.Flags().IntVar(&limits.MaxConcurrent, "max-chats", limits.MaxConcurrent, "max concurrent chats across all teams (0 = unlimited)")
+	cmd.Flags().IntVar(&limits.MaxConcurrentPerTeam, "max-team-chats", limits.MaxConcurrentPerTeam, "max concurrent chats per team (0 = unlimited)")
+	cmd.Flags().IntVar(&limits.RequestsPerMinute, "chats-per-minute", limits.RequestsPerMinute, "max chats started per minute across all teams (0 = unlimited)")
+	cmd.Flags().IntVar(&limits.RequestsPerMinutePerTeam, "team-chats-per-minute", limits.RequestsPerMinutePerTeam, "max chats started per minute per team (0 = unlimited)")
+
+	cmd.AddCommand(daemonStatusCmd())
+
+	return cmd
+}
+
+func daemonStatusCmd() *cobra.Command {
+	var outputJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show daemon uptime, version and resource usage",
+		Run: func(cmd *cobra.Command, args []string) {
+			client, err := requireDaemon()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+
+			status, err := client.Status(ctx)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			info, _ := status["daemon"].(map[string]interface{})
+			if outputJSON {
+				data, _ := json.MarshalIndent(info, "", "  ")
+				fmt.Println(string(data))
+				return
+			}
+
+			fmt.Println("Daemon: running")
+			fmt.Printf("Socket: %s\n", client.GetSocketPath())
+			printDaemonInfo(info)
+		},
+	}
+
+	cmd.Flags().BoolVar(&outputJSON, "json", false, "output as JSON")
 
 	return cmd
 }
 
+// printDaemonInfo prints the daemon section of /api/status. Older daemons
+// don't report it, so nothing is printed for them.
+func printDaemonInfo(info map[string]interface{}) {
+	if info == nil {
+		return
+	}
+	fmt.Printf("Version: %v (%v, %v)\n", info["version"], daemonBuild(info).ShortCommit(), info["go_version"])
+	fmt.Printf("Uptime: %v\n", info["uptime"])
+	fmt.Printf("Goroutines: %v\n", info["goroutines"])
+	if mem, ok := info["memory"].(map[string]interface{}); ok {
+		alloc, _ := mem["alloc_bytes"].(float64)
+		sys, _ := mem["sys_bytes"].(float64)
+		fmt.Printf("Memory: %s in use, %s from OS\n", formatBytes(int64(alloc)), formatBytes(int64(sys)))
+	}
+	fmt.Printf("Active requests: %v\n", info["active_requests"])
+	size, _ := info["data_dir_bytes"].(float64)
+	fmt.Printf("Data: %v (%s)\n", info["data_dir"], formatBytes(int64(size)))
+}
+
+// daemonBuild reads the daemon's build from its status. Older daemons don't
+// report a commit.
+func daemonBuild(info map[string]interface{}) version.Info {
+	build := version.Info{Commit: version.Unknown}
+	build.Version, _ = info["version"].(string)
+	if commit, ok := info["commit"].(string); ok && commit != "" {
+		build.Commit = commit
+	}
+	return build
+}
+
+// formatBytes renders a byte count in binary units
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
 // ============================================================================
 // Team Commands
 // ============================================================================
@@ -196,9 +300,22 @@ func teamCmd() *cobra.Command {
 	cmd.AddCommand(teamCreateCmd())
 	cmd.AddCommand(teamStartCmd())
 	cmd.AddCommand(teamStopCmd())
+	cmd.AddCommand(teamRestartCmd())
+	cmd.AddCommand(teamPauseCmd())
+	cmd.AddCommand(teamResumeCmd())
+	cmd.AddCommand(teamQuestionsCmd())
+	cmd.AddCommand(teamAnswerCmd())
+	cmd.AddCommand(teamApprovalsCmd())
+	cmd.AddCommand(teamResetMemberCmd())
 	cmd.AddCommand(teamDeleteCmd())
 	cmd.AddCommand(teamListCmd())
 	cmd.AddCommand(teamPsCmd())
+	cmd.AddCommand(teamContinueCmd())
+	cmd.AddCommand(teamDiffCmd())
+	cmd.AddCommand(teamSpecCmd())
+	cmd.AddCommand(teamPromptCmd())
+	cmd.AddCommand(teamPerfCmd())
+	cmd.AddCommand(teamBenchCmd())
 
 	return cmd
 }
@@ -206,6 +323,12 @@ func teamCmd() *cobra.Command {
 func teamCreateCmd() *cobra.Command {
 	var fromSpec string
 	var fromTemplate string
+	var contextFile string
+	var contextConversation string
+	var templateVars []string
+	var interactive bool
+	var roleCounts []string
+	var force bool
 
 	cmd := &cobra.Command{
 		Use:   "create <team-name>",
@@ -218,9 +341,17 @@ Examples:
   ugudu team create alpha --spec dev-team      # Create "alpha" team from dev-team spec
   ugudu team create beta --spec dev-team       # Create "beta" team from same spec
   ugudu team create gamma --template dev-team  # Create from built-in template
+  ugudu team create delta --spec dev-team --context-file notes.md  # Start with background
+  ugudu team create games --template dev-team --var DOMAIN="mobile games" --var PROVIDER=ollama --var MODEL=llama3
+  ugudu team create big --spec dev-team --count engineer=3 --count qa=2  # Resize roles for this team
+  ugudu team create alpha --spec dev-team --force  # Replace the existing "alpha" team
+
+Templates declare variables, e.g. the provider and model of their lead
+roles. Set them with --var, or use -i to be asked for each one. The team
+name fills TEAM_NAME.
 
 List available specs with: ugudu spec list
-List templates with: ugudu templates list`,
+List templates and their variables with: ugudu templates list`,
 		Args: cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			teamName := args[0]
@@ -231,6 +362,23 @@ List templates with: ugudu templates list`,
 				os.Exit(1)
 			}
 
+			counts, err := parseRoleCounts(roleCounts)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			// Check before writing the spec file, which would overwrite the team's
+			if !force {
+				ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+				_, err := client.GetTeam(ctx, teamName)
+				cancel()
+				if err == nil {
+					fmt.Fprintf(os.Stderr, "Error: team '%s' already exists (use --force to replace it)\n", teamName)
+					os.Exit(1)
+				}
+			}
+
 			var specPath string
 			var specContent []byte
 
@@ -245,8 +393,21 @@ List templates with: ugudu templates list`,
 					}
 					os.Exit(1)
 				}
-				specContent, _ = templates.Get(fromTemplate)
+				values, err := templateValues(fromTemplate, teamName, templateVars, interactive, bufio.NewReader(os.Stdin))
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+					os.Exit(1)
+				}
+				specContent, err = templates.Render(fromTemplate, values)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+					os.Exit(1)
+				}
 			} else if fromSpec != "" {
+				if len(templateVars) > 0 {
+					fmt.Fprintln(os.Stderr, "Error: --var only applies to --template")
+					os.Exit(1)
+				}
 				// Use spec from ~/.ugudu/teams/
 				specPath = resolveSpecPath(fromSpec)
 				if _, err := os.Stat(specPath); os.IsNotExist(err) {
@@ -269,20 +430,43 @@ List templates with: ugudu templates list`,
 				os.Exit(1)
 			}
 
-			// Modify the spec to use the provided team name
-			modifiedSpec := replaceTeamName(string(specContent), teamName)
+			// Templates take the team name as TEAM_NAME; a spec is renamed
+			modifiedSpec := specContent
+			if fromSpec != "" {
+				modifiedSpec, err = team.RenameSpec(specContent, teamName)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+					os.Exit(1)
+				}
+			}
 
 			// Write to persistent spec file in ~/.ugudu/specs/
 			specFile := filepath.Join(config.SpecsDir(), teamName+".yaml")
-			if err := os.WriteFile(specFile, []byte(modifiedSpec), 0644); err != nil {
+			if err := os.WriteFile(specFile, modifiedSpec, 0644); err != nil {
 				fmt.Fprintf(os.Stderr, "Error writing spec file: %v\n", err)
 				os.Exit(1)
 			}
+			// Saved in the team's spec file, so the counts survive restarts
+			if err := team.SetRoleCounts(specFile, counts); err != nil {
+				os.Remove(specFile)
+				fmt.Fprintf(os.Stderr, "Error: --count: %v\n", err)
+				os.Exit(1)
+			}
+
+			var seed string
+			if contextFile != "" {
+				data, err := os.ReadFile(contextFile)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error reading context file: %v\n", err)
+					os.Exit(1)
+				}
+				seed = string(data)
+			}
 
 			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 			defer cancel()
 
-			result, err := client.CreateTeam(ctx, specFile)
+			result, err := client.CreateSeededTeam(ctx, specFile, seed, contextConversation, force)
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "Error creating team: %v\n", err)
 				os.Exit(1)
@@ -299,23 +483,56 @@ List templates with: ugudu templates list`,
 
 	cmd.Flags().StringVarP(&fromSpec, "spec", "s", "", "spec name (from ~/.ugudu/specs/)")
 	cmd.Flags().StringVarP(&fromTemplate, "template", "t", "", "built-in template name")
+	cmd.Flags().StringVar(&contextFile, "context-file", "", "file of background every member starts with")
+	cmd.Flags().StringVar(&contextConversation, "context-conversation", "", "conversation ID whose client messages every member starts with")
+	cmd.Flags().StringArrayVar(&templateVars, "var", nil, "template variable as KEY=VALUE (repeatable)")
+	cmd.Flags().BoolVarP(&interactive, "interactive", "i", false, "ask for each template variable not set with --var")
+	cmd.Flags().StringArrayVar(&roleCounts, "count", nil, "members of a role as ROLE=N, overriding the spec (repeatable)")
+	cmd.Flags().BoolVarP(&force, "force", "f", false, "replace a team of the same name, deleting its history")
 
 	return cmd
 }
 
-// replaceTeamName modifies the spec YAML to use the given team name
-func replaceTeamName(specContent, teamName string) string {
-	lines := strings.Split(specContent, "\n")
-	for i, line := range lines {
-		trimmed := strings.TrimSpace(line)
-		if strings.HasPrefix(trimmed, "name:") {
-			// Find indentation
-			indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
-			lines[i] = indent + "name: " + teamName
-			break
+// templateValues collects a template's variable values from --var flags and
+// the team name, asking for the rest interactively, or for required ones
+// with no default
+func templateValues(name, teamName string, vars []string, interactive bool, reader *bufio.Reader) (map[string]string, error) {
+	values := map[string]string{"TEAM_NAME": teamName}
+	for _, kv := range vars {
+		key, value, ok := strings.Cut(kv, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --var %q, expected KEY=VALUE", kv)
+		}
+		values[key] = value
+	}
+
+	declared, err := templates.Variables(name)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range declared {
+		if _, ok := values[v.Name]; ok || (!interactive && v.Default != "") {
+			continue
+		}
+		if value := prompt(reader, fmt.Sprintf("%s - %s", v.Name, v.Description), v.Default); value != "" {
+			values[v.Name] = value
+		}
+	}
+	return values, nil
+}
+
+// parseRoleCounts reads --count ROLE=N flags
+func parseRoleCounts(flags []string) (map[string]int, error) {
+	counts := make(map[string]int, len(flags))
+	for _, kv := range flags {
+		role, value, ok := strings.Cut(kv, "=")
+		n, err := strconv.Atoi(value)
+		if !ok || role == "" || err != nil {
+			return nil, fmt.Errorf("invalid --count %q, expected ROLE=N", kv)
 		}
+		counts[role] = n
 	}
-	return strings.Join(lines, "\n")
+	return counts, nil
 }
 
 // resolveSpecPath resolves a spec name to its file path
@@ -402,11 +619,15 @@ func teamStopCmd() *cobra.Command {
 	}
 }
 
-func teamDeleteCmd() *cobra.Command {
+func teamRestartCmd() *cobra.Command {
 	return &cobra.Command{
-		Use:   "delete [team-name]",
-		Short: "Delete a team",
-		Args:  cobra.ExactArgs(1),
+		Use:   "restart [team-name]",
+		Short: "Stop and start a team, keeping its context",
+		Long: `Restart a team, e.g. after changing provider keys. Any request in progress
+is cancelled and the members are given time to stop before the team starts
+again. Members reload their saved conversation context, so they carry on
+where they left off.`,
+		Args: cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			client, err := requireDaemon()
 			if err != nil {
@@ -414,24 +635,28 @@ func teamDeleteCmd() *cobra.Command {
 				os.Exit(1)
 			}
 
-			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			// Longer than the daemon waits for members to stop
+			ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
 			defer cancel()
 
-			if err := client.DeleteTeam(ctx, args[0]); err != nil {
-				fmt.Fprintf(os.Stderr, "Error deleting team: %v\n", err)
+			if err := client.RestartTeam(ctx, args[0]); err != nil {
+				fmt.Fprintf(os.Stderr, "Error restarting team: %v\n", err)
 				os.Exit(1)
 			}
 
-			fmt.Printf("Team '%s' deleted.\n", args[0])
+			fmt.Printf("Team '%s' restarted.\n", args[0])
 		},
 	}
 }
 
-func teamListCmd() *cobra.Command {
+func teamPauseCmd() *cobra.Command {
 	return &cobra.Command{
-		Use:     "list",
-		Aliases: []string{"ls"},
-		Short:   "List all teams",
+		Use:   "pause [team-name]",
+		Short: "Stop a team accepting new work",
+		Long: `Pause a team. Asks sent to a paused team are rejected, but its members
+keep running: work already in progress finishes and their context is kept.
+Use 'ugudu team resume' to accept asks again.`,
+		Args: cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			client, err := requireDaemon()
 			if err != nil {
@@ -442,41 +667,45 @@ func teamListCmd() *cobra.Command {
 			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 			defer cancel()
 
-			teams, err := client.ListTeams(ctx)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			if err := client.PauseTeam(ctx, args[0]); err != nil {
+				fmt.Fprintf(os.Stderr, "Error pausing team: %v\n", err)
 				os.Exit(1)
 			}
 
-			if len(teams) == 0 {
-				fmt.Println("No teams found.")
-				fmt.Println("\nCreate one with: ugudu team create <spec.yaml>")
-				return
+			fmt.Printf("Team '%s' paused.\n", args[0])
+		},
+	}
+}
+
+func teamResumeCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "resume [team-name]",
+		Short: "Resume a paused team",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			client, err := requireDaemon()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
 			}
 
-			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-			fmt.Fprintln(w, "NAME\tSTATUS\tMEMBERS\tDESCRIPTION")
-			fmt.Fprintln(w, "────\t──────\t───────\t───────────")
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
 
-			for _, t := range teams {
-				name, _ := t["name"].(string)
-				status, _ := t["status"].(string)
-				members := int(t["member_count"].(float64))
-				desc, _ := t["description"].(string)
-				if len(desc) > 40 {
-					desc = desc[:40] + "..."
-				}
-				fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", name, status, members, desc)
+			if err := client.ResumeTeam(ctx, args[0]); err != nil {
+				fmt.Fprintf(os.Stderr, "Error resuming team: %v\n", err)
+				os.Exit(1)
 			}
-			w.Flush()
+
+			fmt.Printf("Team '%s' resumed.\n", args[0])
 		},
 	}
 }
 
-func teamPsCmd() *cobra.Command {
+func teamQuestionsCmd() *cobra.Command {
 	return &cobra.Command{
-		Use:   "ps [team-name]",
-		Short: "Show team members and their status",
+		Use:   "questions [team-name]",
+		Short: "List questions members are waiting on you to answer",
 		Args:  cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			client, err := requireDaemon()
@@ -488,58 +717,33 @@ func teamPsCmd() *cobra.Command {
 			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 			defer cancel()
 
-			members, err := client.TeamMembers(ctx, args[0])
+			questions, err := client.PendingQuestions(ctx, args[0])
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				fmt.Fprintf(os.Stderr, "Error listing questions: %v\n", err)
 				os.Exit(1)
 			}
 
-			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-			fmt.Fprintf(w, "Team: %s\n\n", args[0])
-			fmt.Fprintln(w, "NAME\tROLE\tSTATUS\tVISIBILITY")
-			fmt.Fprintln(w, "────\t────\t──────\t──────────")
-
-			for _, m := range members {
-				name, _ := m["name"].(string)
-				title, _ := m["title"].(string)
-				status, _ := m["status"].(string)
-				visibility, _ := m["visibility"].(string)
-
-				// Use display_name if available, otherwise format name + title
-				displayName := name
-				if displayName == "" || displayName == title {
-					displayName = title
-				} else {
-					displayName = fmt.Sprintf("%s (%s)", name, title)
+			if len(questions) == 0 {
+				fmt.Println("No pending questions.")
+				return
+			}
+			for _, q := range questions {
+				qm, ok := q.(map[string]interface{})
+				if !ok {
+					continue
 				}
-
-				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", displayName, title, status, visibility)
+				fmt.Printf("[%v] %v asks: %v\n", qm["id"], qm["from_member"], qm["content"])
 			}
-			w.Flush()
+			fmt.Printf("\nAnswer with: ugudu team answer %s <question-id> \"<answer>\"\n", args[0])
 		},
 	}
 }
 
-// ============================================================================
-// Ask Command
-// ============================================================================
-
-func askCmd() *cobra.Command {
-	var toMember string
-	var timeout int
-	var lowToken bool
-	var minimalToken bool
-
-	cmd := &cobra.Command{
-		Use:   "ask [team-name] [message]",
-		Short: "Send a message to a team",
-		Long: `Send a message to a team. The message goes to the primary
-client-facing member (usually a PM or lead).
-
-Use --to to send to a specific role.
-Use --low-token to reduce token consumption (shorter prompts, cheaper models).
-Use --minimal-token for bare minimum token usage.`,
-		Args: cobra.MinimumNArgs(2),
+func teamAnswerCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "answer [team-name] [question-id] [answer]",
+		Short: "Answer a question a member is waiting on",
+		Args:  cobra.ExactArgs(3),
 		Run: func(cmd *cobra.Command, args []string) {
 			client, err := requireDaemon()
 			if err != nil {
@@ -547,55 +751,43 @@ Use --minimal-token for bare minimum token usage.`,
 				os.Exit(1)
 			}
 
-			teamName := args[0]
-			message := strings.Join(args[1:], " ")
-
-			ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 			defer cancel()
 
-			// Start team if not running
-			_ = client.StartTeam(ctx, teamName)
-
-			// Set token mode if specified
-			if minimalToken {
-				_ = client.SetTokenMode(ctx, teamName, "minimal")
-			} else if lowToken {
-				_ = client.SetTokenMode(ctx, teamName, "low")
-			}
-
-			responses, err := client.Chat(ctx, teamName, message, toMember)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			if err := client.AnswerQuestion(ctx, args[0], args[1], args[2]); err != nil {
+				fmt.Fprintf(os.Stderr, "Error answering question: %v\n", err)
 				os.Exit(1)
 			}
 
-			for _, resp := range responses {
-				from, _ := resp["from"].(string)
-				content, _ := resp["content"].(string)
-				fmt.Printf("\n%s: %s\n", from, content)
-			}
+			fmt.Printf("Answered question %s.\n", args[1])
 		},
 	}
-
-	cmd.Flags().StringVar(&toMember, "to", "", "send to specific role")
-	cmd.Flags().IntVar(&timeout, "timeout", 600, "timeout in seconds (default: 10 minutes for complex tasks)")
-	cmd.Flags().BoolVar(&lowToken, "low-token", false, "use low token mode (condensed prompts, reduced context)")
-	cmd.Flags().BoolVar(&minimalToken, "minimal-token", false, "use minimal token mode (bare minimum)")
-
-	return cmd
 }
 
-// ============================================================================
-// Status Command
-// ============================================================================
-
-func statusCmd() *cobra.Command {
-	var outputJSON bool
+func teamApprovalsCmd() *cobra.Command {
+	var approve, deny, reason string
+	var history bool
 
 	cmd := &cobra.Command{
-		Use:   "status",
-		Short: "Show overall status",
+		Use:   "approvals [team-name]",
+		Short: "List and decide tool calls waiting on your approval",
+		Long: `List the tool calls members are waiting on you to approve, for tools the
+spec lists under settings.requires_approval. Approve or deny one with
+--approve or --deny; a denied member is told the reason and carries on
+without running the tool.
+
+Examples:
+  ugudu team approvals dev-team
+  ugudu team approvals dev-team --approve 1a2b3c4d
+  ugudu team approvals dev-team --deny 1a2b3c4d --reason "don't touch prod"
+  ugudu team approvals dev-team --history`,
+		Args: cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
+			if approve != "" && deny != "" {
+				fmt.Fprintln(os.Stderr, "Error: use either --approve or --deny")
+				os.Exit(1)
+			}
+
 			client, err := requireDaemon()
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
@@ -605,67 +797,930 @@ func statusCmd() *cobra.Command {
 			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 			defer cancel()
 
-			status, err := client.Status(ctx)
+			if approve != "" || deny != "" {
+				id := approve + deny
+				if err := client.DecideApproval(ctx, args[0], id, approve != "", reason); err != nil {
+					fmt.Fprintf(os.Stderr, "Error deciding approval: %v\n", err)
+					os.Exit(1)
+				}
+				if approve != "" {
+					fmt.Printf("Approved %s.\n", id)
+				} else {
+					fmt.Printf("Denied %s.\n", id)
+				}
+				return
+			}
+
+			approvals, err := client.Approvals(ctx, args[0], history)
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				fmt.Fprintf(os.Stderr, "Error listing approvals: %v\n", err)
 				os.Exit(1)
 			}
 
-			if outputJSON {
-				data, _ := json.MarshalIndent(status, "", "  ")
-				fmt.Println(string(data))
+			if len(approvals) == 0 {
+				if history {
+					fmt.Println("No approvals recorded.")
+				} else {
+					fmt.Println("No pending approvals.")
+				}
 				return
 			}
-
-			fmt.Println("╔══════════════════════════════════════════╗")
-			fmt.Println("║           U G U D U                      ║")
-			fmt.Println("║   AI Team Orchestration System           ║")
-			fmt.Println("╚══════════════════════════════════════════╝")
-			fmt.Println()
-			fmt.Println("Daemon: running")
-			fmt.Printf("Socket: %s\n", client.GetSocketPath())
-			fmt.Println()
-
-			// Teams
-			if teams, ok := status["teams"].([]interface{}); ok {
-				fmt.Printf("Teams: %d\n", len(teams))
-				for _, t := range teams {
-					if tm, ok := t.(map[string]interface{}); ok {
-						fmt.Printf("  - %s (%v members)\n", tm["name"], tm["member_count"])
-					}
+			for _, a := range approvals {
+				am, ok := a.(map[string]interface{})
+				if !ok {
+					continue
 				}
-			}
-			fmt.Println()
-
-			// Providers
-			if providers, ok := status["providers"].([]interface{}); ok {
-				fmt.Printf("Providers: %d configured\n", len(providers))
-				for _, p := range providers {
-					if pm, ok := p.(map[string]interface{}); ok {
-						fmt.Printf("  - %s\n", pm["name"])
+				callArgs, _ := json.Marshal(am["args"])
+				fmt.Printf("[%v] %v wants %v %s", am["id"], am["member"], am["tool"], callArgs)
+				if history {
+					fmt.Printf(" (%v", am["status"])
+					if r, ok := am["reason"].(string); ok && r != "" {
+						fmt.Printf(": %s", r)
 					}
+					fmt.Print(")")
 				}
+				fmt.Println()
+			}
+			if !history {
+				fmt.Printf("\nDecide with: ugudu team approvals %s --approve <id> (or --deny <id> --reason \"...\")\n", args[0])
 			}
 		},
 	}
 
-	cmd.Flags().BoolVar(&outputJSON, "json", false, "output as JSON")
+	cmd.Flags().StringVar(&approve, "approve", "", "Approve the tool call with this ID")
+	cmd.Flags().StringVar(&deny, "deny", "", "Deny the tool call with this ID")
+	cmd.Flags().StringVar(&reason, "reason", "", "Why, passed back to the member")
+	cmd.Flags().BoolVar(&history, "history", false, "List recorded approvals and decisions")
 
 	return cmd
 }
 
-// ============================================================================
-// Provider Commands
-// ============================================================================
+func teamResetMemberCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "reset-member [team-name] [member-id]",
+		Short: "Clear one member's conversation context",
+		Long: `Clear the conversation context of a single member, e.g. an engineer that
+went down the wrong path. The rest of the team keeps its context and the
+conversation stays open. A role with a single member uses the role name as
+its ID, e.g. 'ugudu team reset-member my-team engineer'.`,
+		Args: cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			client, err := requireDaemon()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
 
-func providerCmd() *cobra.Command {
-	cmd := &cobra.Command{
-		Use:   "provider",
-		Short: "Manage AI providers",
-	}
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
 
-	cmd.AddCommand(providerListCmd())
-	cmd.AddCommand(providerTestCmd())
+			if err := client.ClearMemberContext(ctx, args[0], args[1]); err != nil {
+				fmt.Fprintf(os.Stderr, "Error resetting member: %v\n", err)
+				os.Exit(1)
+			}
+
+			fmt.Printf("Cleared context for %s in team '%s'.\n", args[1], args[0])
+		},
+	}
+}
+
+func teamDeleteCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete [team-name]",
+		Short: "Delete a team",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			client, err := requireDaemon()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+
+			if err := client.DeleteTeam(ctx, args[0]); err != nil {
+				fmt.Fprintf(os.Stderr, "Error deleting team: %v\n", err)
+				os.Exit(1)
+			}
+
+			fmt.Printf("Team '%s' deleted.\n", args[0])
+		},
+	}
+}
+
+func teamListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List all teams",
+		Run: func(cmd *cobra.Command, args []string) {
+			client, err := requireDaemon()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+
+			teams, err := client.ListTeams(ctx)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			if len(teams) == 0 {
+				fmt.Println("No teams found.")
+				fmt.Println("\nCreate one with: ugudu team create <spec.yaml>")
+				return
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "NAME\tSTATUS\tMEMBERS\tDESCRIPTION")
+			fmt.Fprintln(w, "────\t──────\t───────\t───────────")
+
+			for _, t := range teams {
+				name, _ := t["name"].(string)
+				status, _ := t["status"].(string)
+				members := int(t["member_count"].(float64))
+				desc, _ := t["description"].(string)
+				if len(desc) > 40 {
+					desc = desc[:40] + "..."
+				}
+				fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", name, status, members, desc)
+			}
+			w.Flush()
+		},
+	}
+}
+
+func teamDiffCmd() *cobra.Command {
+	var outputJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "diff <team-a> <team-b>",
+		Short: "Compare two teams' specs",
+		Long: `Show how team-b's spec differs from team-a's: roles only one of them
+has, and the fields (model, persona, can_delegate, ...) that differ in
+roles they share. Useful for working out why two teams created from
+different versions of a spec behave differently.
+
+Examples:
+  ugudu team diff alpha beta
+  ugudu team diff alpha beta --json`,
+		Args: cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			client, err := requireDaemon()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+
+			diff, err := client.DiffTeams(ctx, args[0], args[1])
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			if outputJSON {
+				data, _ := json.MarshalIndent(diff, "", "  ")
+				fmt.Println(string(data))
+				return
+			}
+			printSpecDiff(args[0], args[1], diff)
+		},
+	}
+
+	cmd.Flags().BoolVar(&outputJSON, "json", false, "output as JSON")
+
+	return cmd
+}
+
+func teamSpecCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "spec <name>",
+		Short: "Show the spec a running team is using",
+		Long: `Print the spec a team was created from, as YAML, with extends and
+includes resolved and defaults (count, visibility, ...) filled in.
+
+Unlike 'ugudu spec show', which reads the spec file, this shows what the
+team is actually running. The file may have been edited since.
+
+Examples:
+  ugudu team spec alpha
+  ugudu team spec alpha > alpha-running.yaml`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			client, err := requireDaemon()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+
+			spec, err := client.GetTeamSpec(ctx, args[0], true)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Print(spec)
+		},
+	}
+}
+
+func teamPromptCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "prompt <team> <member-id>",
+		Short: "Show the system prompt a member is given",
+		Long: `Print the exact system prompt a member sends the model, under the
+team's current token mode, followed by the tools offered with it.
+
+Use it to see why a member behaves the way it does, or to check a
+persona edit or token mode change.
+
+Examples:
+  ugudu team prompt alpha pm
+  ugudu team prompt alpha engineer-2`,
+		Args: cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			client, err := requireDaemon()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+
+			prompt, err := client.MemberPrompt(ctx, args[0], args[1])
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			fmt.Printf("# %s (token mode: %s)\n\n", prompt.Member, prompt.TokenMode)
+			fmt.Print(prompt.System)
+			if len(prompt.Tools) > 0 {
+				fmt.Printf("\n# Tools\n%s\n", strings.Join(prompt.Tools, ", "))
+			}
+		},
+	}
+}
+
+// printSpecDiff prints how team b's spec differs from team a's
+func printSpecDiff(a, b string, diff *team.SpecDiff) {
+	if diff.Empty() {
+		fmt.Printf("%s and %s have the same spec.\n", a, b)
+		return
+	}
+
+	for _, role := range diff.RemovedRoles {
+		fmt.Printf("- %s (only in %s)\n", role, a)
+	}
+	for _, role := range diff.AddedRoles {
+		fmt.Printf("+ %s (only in %s)\n", role, b)
+	}
+	for _, rc := range diff.ChangedRoles {
+		fmt.Printf("~ %s\n", rc.Role)
+		printFieldChanges(rc.Fields)
+	}
+	if len(diff.Team) > 0 {
+		fmt.Println("~ team")
+		printFieldChanges(diff.Team)
+	}
+}
+
+func printFieldChanges(changes []team.FieldChange) {
+	for _, c := range changes {
+		fmt.Printf("    %s: %s -> %s\n", c.Field, diffValue(c.From), diffValue(c.To))
+	}
+}
+
+// diffValue shortens a field value to fit on a line, marking unset ones
+func diffValue(v string) string {
+	if v == "" {
+		return "(unset)"
+	}
+	v = strings.Join(strings.Fields(v), " ")
+	if r := []rune(v); len(r) > 60 {
+		v = string(r[:57]) + "..."
+	}
+	return strconv.Quote(v)
+}
+
+func teamPsCmd() *cobra.Command {
+	var watch bool
+	var interval time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "ps [team-name]",
+		Short: "Show team members and their status",
+		Long: `Show team members and their status.
+
+Use --watch to keep the table on screen and redraw it as members change
+status. Updates are pushed from the daemon's event stream; if that isn't
+reachable the table is refreshed every --interval instead.
+
+Examples:
+  ugudu team ps alpha
+  ugudu team ps alpha --watch
+  ugudu team ps alpha -w --interval 5s`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			client, err := requireDaemon()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			if watch {
+				ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+				defer stop()
+				if err := watchTeamMembers(ctx, os.Stdout, client, args[0], interval); err != nil {
+					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+					os.Exit(1)
+				}
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+
+			members, err := client.TeamMembers(ctx, args[0])
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			printTeamMembers(os.Stdout, args[0], members, false)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&watch, "watch", "w", false, "Keep refreshing member status until interrupted")
+	cmd.Flags().DurationVar(&interval, "interval", 2*time.Second, "Refresh interval when watching")
+
+	return cmd
+}
+
+// watchTeamMembers redraws the member table on out whenever the team changes
+// until ctx is done, e.g. the user hits Ctrl-C. Status events from the daemon
+// trigger an immediate refresh; the ticker keeps elapsed times current and
+// covers the case where the event stream is unavailable.
+func watchTeamMembers(ctx context.Context, out io.Writer, client *daemon.Client, teamName string, interval time.Duration) error {
+	if interval <= 0 {
+		interval = 2 * time.Second
+	}
+
+	events, err := client.Subscribe(ctx)
+	if err != nil {
+		events = nil // nil channel never fires; fall back to polling
+	}
+
+	refresh := func() error {
+		reqCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+
+		members, err := client.TeamMembers(reqCtx, teamName)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		fmt.Fprint(out, "\033[H\033[2J") // clear screen and home cursor
+		printTeamMembers(out, teamName, members, true)
+		mode := "live"
+		if events == nil {
+			mode = fmt.Sprintf("polling every %s", interval)
+		}
+		fmt.Fprintf(out, "\nUpdated %s (%s) - press Ctrl-C to exit\n", time.Now().Format("15:04:05"), mode)
+		return nil
+	}
+
+	if err := refresh(); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			fmt.Fprintln(out)
+			return nil
+
+		case <-ticker.C:
+			if err := refresh(); err != nil {
+				return err
+			}
+
+		case event, ok := <-events:
+			if !ok {
+				events = nil
+				continue
+			}
+			if team, _ := event["team"].(string); team != teamName {
+				continue
+			}
+			if err := refresh(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// printTeamMembers renders the member table to out. The detailed view adds
+// the current task and how long each member has been in its status.
+func printTeamMembers(out io.Writer, teamName string, members []map[string]interface{}, detailed bool) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Team: %s\n\n", teamName)
+	if detailed {
+		fmt.Fprintln(w, "NAME\tROLE\tSTATUS\tFOR\tTASK")
+		fmt.Fprintln(w, "────\t────\t──────\t───\t────")
+	} else {
+		fmt.Fprintln(w, "NAME\tROLE\tSTATUS\tVISIBILITY")
+		fmt.Fprintln(w, "────\t────\t──────\t──────────")
+	}
+
+	for _, m := range members {
+		name, _ := m["name"].(string)
+		title, _ := m["title"].(string)
+		status, _ := m["status"].(string)
+		visibility, _ := m["visibility"].(string)
+
+		// Use display_name if available, otherwise format name + title
+		displayName := name
+		if displayName == "" || displayName == title {
+			displayName = title
+		} else {
+			displayName = fmt.Sprintf("%s (%s)", name, title)
+		}
+
+		if !detailed {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", displayName, title, status, visibility)
+			continue
+		}
+
+		elapsed := "-"
+		if since, _ := m["status_since"].(string); since != "" {
+			if t, err := time.Parse(time.RFC3339Nano, since); err == nil {
+				elapsed = time.Since(t).Truncate(time.Second).String()
+			}
+		}
+
+		task, _ := m["task"].(string)
+		task = truncateRunes(strings.Join(strings.Fields(task), " "), 50)
+		if task == "" {
+			task = "-"
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", displayName, title, status, elapsed, task)
+	}
+	w.Flush()
+}
+
+// truncateRunes shortens s to at most n characters, ending in "..." if it
+// was cut. It counts runes, so a multi-byte character is never split.
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n-3]) + "..."
+}
+
+// ============================================================================
+// Ask Command
+// ============================================================================
+
+func askCmd() *cobra.Command {
+	var toMember string
+	var teams string
+	var timeout int
+	var lowToken bool
+	var minimalToken bool
+	var persist bool
+	var verbose bool
+	var noTools bool
+
+	cmd := &cobra.Command{
+		Use:   "ask [team-name] [message]",
+		Short: "Send a message to a team",
+		Long: `Send a message to a team. The message goes to the primary
+client-facing member (usually a PM or lead).
+
+Use --teams to ask several teams the same question at once and compare
+their answers; the team name is then left out.
+
+Use --to to send to a specific role, or to one member of it by ID
+(e.g. --to engineer-2).
+Use --low-token to reduce token consumption (shorter prompts, cheaper models).
+Use --minimal-token for bare minimum token usage.
+Add --persist to keep that token mode after the daemon restarts.
+Use --verbose to also see delegation results and tool calls.
+Use --no-tools for a quick conversational answer: the model isn't offered
+any tools, which saves a turn and the tokens describing them.
+
+If the team is still working when --timeout runs out, ask prints a token
+to pick up the rest with 'ugudu team continue'.
+
+Examples:
+  ugudu ask dev-team "Add a health check endpoint"
+  ugudu ask --teams alpha,beta "Which database should we use?"
+  ugudu ask dev-team --no-tools "What's a good name for the login service?"`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if teams != "" {
+				return cobra.MinimumNArgs(1)(cmd, args)
+			}
+			return cobra.MinimumNArgs(2)(cmd, args)
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			client, err := requireDaemon()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			var teamNames []string
+			if teams != "" {
+				for _, name := range strings.Split(teams, ",") {
+					if name = strings.TrimSpace(name); name != "" {
+						teamNames = append(teamNames, name)
+					}
+				}
+			} else {
+				teamNames, args = args[:1], args[1:]
+			}
+			message := strings.Join(args, " ")
+
+			if persist && !lowToken && !minimalToken {
+				fmt.Fprintln(os.Stderr, "Error: --persist needs --low-token or --minimal-token")
+				os.Exit(1)
+			}
+
+			// The daemon gives up first so a slow team comes back with a
+			// continuation token rather than a dropped connection
+			wait := time.Duration(timeout) * time.Second
+			ctx, cancel := context.WithTimeout(context.Background(), wait+15*time.Second)
+			defer cancel()
+
+			// Set token mode if specified
+			mode := ""
+			if minimalToken {
+				mode = "minimal"
+			} else if lowToken {
+				mode = "low"
+			}
+			for _, teamName := range teamNames {
+				// Start team if not running
+				_ = client.StartTeam(ctx, teamName)
+
+				if mode != "" {
+					if err := client.SetTokenMode(ctx, teamName, mode, persist); err != nil && persist {
+						fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+						os.Exit(1)
+					}
+				}
+			}
+
+			verbosity := "summary"
+			if verbose {
+				verbosity = "full"
+			}
+
+			opts := daemon.ChatOptions{To: toMember, Verbosity: verbosity, Timeout: wait, NoTools: noTools}
+			if teams != "" {
+				askTeams(ctx, client, teamNames, message, opts, verbose)
+				return
+			}
+
+			teamName := teamNames[0]
+			outcome, err := client.ChatStream(ctx, teamName, message, opts, chatResponsePrinter(verbose))
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			exitOnChatTimeout(teamName, outcome)
+		},
+	}
+
+	cmd.Flags().StringVar(&toMember, "to", "", "send to a specific role or member ID (e.g. engineer-2)")
+	cmd.Flags().StringVar(&teams, "teams", "", "comma-separated teams to ask the same question")
+	cmd.Flags().IntVar(&timeout, "timeout", 600, "timeout in seconds (default: 10 minutes for complex tasks)")
+	cmd.Flags().BoolVar(&lowToken, "low-token", false, "use low token mode (condensed prompts, reduced context)")
+	cmd.Flags().BoolVar(&minimalToken, "minimal-token", false, "use minimal token mode (bare minimum)")
+	cmd.Flags().BoolVar(&persist, "persist", false, "keep the token mode after the daemon restarts")
+	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "include internal delegation results, tool summaries and the model behind each reply")
+	cmd.Flags().BoolVar(&noTools, "no-tools", false, "answer without tools, for a quicker, cheaper reply")
+
+	return cmd
+}
+
+// askTeams asks several teams the same question and prints each team's
+// answer in turn. It exits non-zero if any team failed or timed out.
+func askTeams(ctx context.Context, client *daemon.Client, teams []string, message string, opts daemon.ChatOptions, verbose bool) {
+	results, err := client.ChatBulk(ctx, teams, message, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	printResponse := chatResponsePrinter(verbose)
+	failed := false
+	for _, teamName := range teams {
+		result, ok := results[teamName]
+		if !ok {
+			continue
+		}
+		fmt.Printf("\n=== %s ===\n", teamName)
+		for _, resp := range result.Responses {
+			printResponse(resp)
+		}
+		switch {
+		case result.Err != nil:
+			fmt.Fprintf(os.Stderr, "Error: %v\n", result.Err)
+			failed = true
+		case result.TimedOut:
+			fmt.Fprintln(os.Stderr, "\nTimed out waiting for the team. It may still be working.")
+			if result.ContinuationToken != "" {
+				fmt.Fprintf(os.Stderr, "Continue with: ugudu team continue %s %s\n", teamName, result.ContinuationToken)
+			}
+			failed = true
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// chatResponsePrinter returns a function that prints one response from a
+// team. Verbose output also names the model behind each reply.
+func chatResponsePrinter(verbose bool) func(map[string]interface{}) {
+	return func(resp map[string]interface{}) {
+		from, _ := resp["from"].(string)
+		content, _ := resp["content"].(string)
+		if internal, _ := resp["internal"].(bool); internal {
+			fmt.Printf("  [internal] %s: %s\n", from, content)
+			return
+		}
+		if progress, _ := resp["progress"].(bool); progress {
+			fmt.Printf("  ... %s\n", content)
+			return
+		}
+		fmt.Printf("\n%s: %s\n", from, content)
+		if served := servedBy(resp); verbose && served != "" {
+			fmt.Printf("  (%s)\n", served)
+		}
+	}
+}
+
+// servedBy describes the model and provider that produced a response, or
+// returns "" if the daemon didn't report one
+func servedBy(resp map[string]interface{}) string {
+	model, _ := resp["model"].(string)
+	prov, _ := resp["provider"].(string)
+	switch {
+	case model == "":
+		return ""
+	case prov == "":
+		return model
+	default:
+		return fmt.Sprintf("%s via %s", model, prov)
+	}
+}
+
+// exitOnChatTimeout reports a chat that timed out, with how to pick it up
+// again if the team is still working
+func exitOnChatTimeout(teamName string, outcome daemon.ChatOutcome) {
+	if !outcome.TimedOut {
+		return
+	}
+	fmt.Fprintln(os.Stderr, "\nTimed out waiting for the team. It may still be working.")
+	if outcome.ContinuationToken != "" {
+		fmt.Fprintf(os.Stderr, "Continue with: ugudu team continue %s %s\n", teamName, outcome.ContinuationToken)
+	}
+	os.Exit(1)
+}
+
+func teamContinueCmd() *cobra.Command {
+	var toMember string
+	var timeout int
+	var verbose bool
+
+	cmd := &cobra.Command{
+		Use:   "continue [team-name] [token]",
+		Short: "Pick up the rest of an ask that timed out",
+		Long: `Reattach to a request that timed out while the team was still working.
+
+When 'ugudu ask' times out it prints a continuation token. Passing it here
+prints everything the team has said since, then waits for the rest. Tokens
+last 30 minutes and can be used once. If the token has expired (or the
+daemon restarted) the team is asked to resume from its saved conversation.
+
+Examples:
+  ugudu team continue alpha 3f2c9a1e-...
+  ugudu team continue alpha 3f2c9a1e-... --timeout 1200`,
+		Args: cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			client, err := requireDaemon()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			wait := time.Duration(timeout) * time.Second
+			ctx, cancel := context.WithTimeout(context.Background(), wait+15*time.Second)
+			defer cancel()
+
+			verbosity := "summary"
+			if verbose {
+				verbosity = "full"
+			}
+
+			opts := daemon.ChatOptions{To: toMember, Verbosity: verbosity, Timeout: wait}
+			outcome, err := client.ContinueChat(ctx, args[0], args[1], opts, chatResponsePrinter(verbose))
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			if outcome.Restarted {
+				fmt.Fprintln(os.Stderr, "\n(The original request had expired, so the team was asked to pick up where it left off.)")
+			}
+			exitOnChatTimeout(args[0], outcome)
+		},
+	}
+
+	cmd.Flags().StringVar(&toMember, "to", "", "role to re-ask if the token has expired")
+	cmd.Flags().IntVar(&timeout, "timeout", 600, "timeout in seconds")
+	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "include internal delegation results, tool summaries and the model behind each reply")
+
+	return cmd
+}
+
+// ============================================================================
+// Status Command
+// ============================================================================
+
+func statusCmd() *cobra.Command {
+	var outputJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show overall status",
+		Run: func(cmd *cobra.Command, args []string) {
+			client, err := requireDaemon()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+
+			status, err := client.Status(ctx)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			health, err := client.HealthDeep(ctx)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: health check failed: %v\n", err)
+			} else {
+				status["health"] = health
+			}
+
+			if outputJSON {
+				data, _ := json.MarshalIndent(status, "", "  ")
+				fmt.Println(string(data))
+				return
+			}
+
+			fmt.Println("╔══════════════════════════════════════════╗")
+			fmt.Println("║           U G U D U                      ║")
+			fmt.Println("║   AI Team Orchestration System           ║")
+			fmt.Println("╚══════════════════════════════════════════╝")
+			fmt.Println()
+			fmt.Println("Daemon: running")
+			fmt.Printf("Socket: %s\n", client.GetSocketPath())
+			daemonInfo, _ := status["daemon"].(map[string]interface{})
+			printDaemonInfo(daemonInfo)
+			if daemonInfo != nil {
+				warnVersionMismatch(daemonBuild(daemonInfo))
+			}
+			if health != nil {
+				fmt.Printf("Health: %v\n", health["status"])
+				if store, ok := health["store"].(map[string]interface{}); ok && store["status"] != "ok" {
+					fmt.Printf("  store: %v (%v)\n", store["status"], store["error"])
+				}
+			}
+			fmt.Println()
+
+			// Teams
+			if teams, ok := status["teams"].([]interface{}); ok {
+				fmt.Printf("Teams: %d\n", len(teams))
+				for _, t := range teams {
+					if tm, ok := t.(map[string]interface{}); ok {
+						fmt.Printf("  - %s (%v members)\n", tm["name"], tm["member_count"])
+					}
+				}
+			}
+			fmt.Println()
+
+			// Providers (with reachability when the health check ran)
+			providerHealth := make(map[string]map[string]interface{})
+			if health != nil {
+				if list, ok := health["providers"].([]interface{}); ok {
+					for _, p := range list {
+						if ph, ok := p.(map[string]interface{}); ok {
+							id, _ := ph["id"].(string)
+							providerHealth[id] = ph
+						}
+					}
+				}
+			}
+			if providers, ok := status["providers"].([]interface{}); ok {
+				fmt.Printf("Providers: %d configured\n", len(providers))
+				for _, p := range providers {
+					if pm, ok := p.(map[string]interface{}); ok {
+						id, _ := pm["id"].(string)
+						ph, checked := providerHealth[id]
+						switch {
+						case !checked:
+							fmt.Printf("  - %s\n", pm["name"])
+						case ph["status"] == "ok":
+							fmt.Printf("  - %s: ok (%vms)\n", pm["name"], ph["latency_ms"])
+						default:
+							fmt.Printf("  - %s: %v (%v)\n", pm["name"], ph["status"], ph["error"])
+						}
+						if stats, ok := pm["stats"].(map[string]interface{}); ok {
+							if load := providerLoad(stats); load != "" {
+								fmt.Printf("      %s\n", load)
+							}
+						}
+					}
+				}
+			}
+		},
+	}
+
+	cmd.Flags().BoolVar(&outputJSON, "json", false, "output as JSON")
+
+	return cmd
+}
+
+// providerLoad summarises a provider's stats for status output, leaving out
+// anything idle. It returns "" for a provider with nothing worth showing.
+func providerLoad(stats map[string]interface{}) string {
+	num := func(key string) float64 {
+		v, _ := stats[key].(float64)
+		return v
+	}
+
+	var parts []string
+	if limited, _ := stats["rate_limited"].(bool); limited {
+		parts = append(parts, fmt.Sprintf("rate limited, resumes in %s", time.Duration(num("time_until_resume_seconds"))*time.Second))
+	}
+	if pending := num("pending_requests"); pending > 0 {
+		parts = append(parts, fmt.Sprintf("%.0f queued", pending))
+	}
+	if c, ok := stats["concurrency"].(map[string]interface{}); ok {
+		if limit, _ := c["limit"].(float64); limit > 0 {
+			inUse, _ := c["in_use"].(float64)
+			parts = append(parts, fmt.Sprintf("%.0f/%.0f in flight", inUse, limit))
+		}
+	}
+	if rate := num("error_rate"); rate > 0 {
+		parts = append(parts, fmt.Sprintf("%.0f%% of recent calls failed", rate*100))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	if serve := num("estimated_serve_seconds"); serve > 0 {
+		parts = append(parts, fmt.Sprintf("~%s to serve", (time.Duration(serve)*time.Second).Round(time.Second)))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ============================================================================
+// Provider Commands
+// ============================================================================
+
+func providerCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "provider",
+		Short: "Manage AI providers",
+	}
+
+	cmd.AddCommand(providerListCmd())
+	cmd.AddCommand(providerTestCmd())
 	cmd.AddCommand(providerModelsCmd())
 
 	return cmd
@@ -703,12 +1758,16 @@ func providerListCmd() *cobra.Command {
 			}
 
 			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-			fmt.Fprintln(w, "ID\tNAME")
-			fmt.Fprintln(w, "──\t────")
+			fmt.Fprintln(w, "ID\tNAME\tSTATUS")
+			fmt.Fprintln(w, "──\t────\t──────")
 			for _, p := range providers {
 				id, _ := p["id"].(string)
 				name, _ := p["name"].(string)
-				fmt.Fprintf(w, "%s\t%s\n", id, name)
+				status, _ := p["status"].(string)
+				if reason, _ := p["status_reason"].(string); reason != "" {
+					status = fmt.Sprintf("%s (%s)", status, reason)
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\n", id, name, status)
 			}
 			w.Flush()
 		},
@@ -735,6 +1794,9 @@ func providerTestCmd() *cobra.Command {
 			if err := client.TestProvider(ctx, args[0]); err != nil {
 				fmt.Printf(" FAILED\n")
 				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				if errors.Is(err, daemon.ErrBadCredentials) {
+					fmt.Fprintln(os.Stderr, "Run 'ugudu config init' to update your API key.")
+				}
 				os.Exit(1)
 			}
 
@@ -801,8 +1863,16 @@ func templatesCmd() *cobra.Command {
 			fmt.Println("Available templates:")
 			for _, n := range names {
 				fmt.Printf("  - %s\n", n)
+				vars, _ := templates.Variables(n)
+				for _, v := range vars {
+					if v.Default != "" {
+						fmt.Printf("      %-10s %s (default: %s)\n", v.Name, v.Description, v.Default)
+					} else {
+						fmt.Printf("      %-10s %s (required)\n", v.Name, v.Description)
+					}
+				}
 			}
-			fmt.Println("\nUse with: ugudu team create --template <name>")
+			fmt.Println("\nUse with: ugudu team create <name> --template <template> [--var KEY=VALUE]")
 		},
 	})
 
@@ -823,49 +1893,6 @@ func templatesCmd() *cobra.Command {
 	return cmd
 }
 
-// ============================================================================
-// Init Command
-// ============================================================================
-
-func initCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:   "init",
-		Short: "Initialize Ugudu with example templates",
-		Run: func(cmd *cobra.Command, args []string) {
-			// Create teams directory
-			teamsDir := "teams"
-			if err := os.MkdirAll(teamsDir, 0755); err != nil {
-				fmt.Fprintf(os.Stderr, "Error creating teams directory: %v\n", err)
-				os.Exit(1)
-			}
-
-			// Export embedded templates
-			names, _ := templates.List()
-			for _, name := range names {
-				path := filepath.Join(teamsDir, name+".yaml")
-				if _, err := os.Stat(path); err == nil {
-					fmt.Printf("  Skipping %s (already exists)\n", path)
-					continue
-				}
-
-				content, _ := templates.Get(name)
-				if err := os.WriteFile(path, content, 0644); err != nil {
-					fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", path, err)
-					continue
-				}
-				fmt.Printf("  Created %s\n", path)
-			}
-
-			fmt.Println("\nDone! Example team templates created in ./teams/")
-			fmt.Println("\nNext steps:")
-			fmt.Println("  1. Start the daemon: ugudu daemon --tcp :8080")
-			fmt.Println("  2. Create a team: ugudu team create teams/dev-team.yaml")
-			fmt.Println("  3. Start it: ugudu team start dev-team")
-			fmt.Println("  4. Ask it something: ugudu ask dev-team \"Hello team!\"")
-		},
-	}
-}
-
 // ============================================================================
 // Create Command (Easy entry point)
 // ============================================================================
@@ -905,15 +1932,43 @@ func versionCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:   "version",
 		Short: "Show version",
+		Long: `Show the CLI's version, commit, build date and Go version. If a daemon
+is running, its version is shown too, with a warning when it was built from
+something else.`,
 		Run: func(cmd *cobra.Command, args []string) {
-			fmt.Println("Ugudu v0.1.0")
-			fmt.Println("AI Team Orchestration System")
-			fmt.Println()
-			fmt.Println("Architecture: daemon + client (like Docker)")
+			build := version.Get()
+			fmt.Printf("Ugudu %s\n", build.Version)
+			fmt.Printf("  Commit: %s\n", build.Commit)
+			fmt.Printf("  Built:  %s\n", build.Date)
+			fmt.Printf("  Go:     %s\n", build.GoVersion)
+
+			client, err := getClient()
+			if err != nil {
+				return
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			remote, err := client.Version(ctx)
+			if err != nil {
+				return
+			}
+			fmt.Printf("\nDaemon: %s (%s)\n", remote.Version, remote.ShortCommit())
+			warnVersionMismatch(remote)
 		},
 	}
 }
 
+// warnVersionMismatch warns when the daemon wasn't built from the same
+// version as the CLI, whose requests it may not understand
+func warnVersionMismatch(daemonBuild version.Info) {
+	build := version.Get()
+	if !version.Mismatch(build, daemonBuild) {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Warning: CLI is %s (%s) but the daemon is %s (%s); restart the daemon to match\n",
+		build.Version, build.ShortCommit(), daemonBuild.Version, daemonBuild.ShortCommit())
+}
+
 // ============================================================================
 // MCP Command (Model Context Protocol)
 // ============================================================================
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/arcslash/ugudu/internal/daemon"
+	"github.com/gorilla/websocket"
+)
+
+func TestTruncateRunes(t *testing.T) {
+	tests := []struct {
+		in   string
+		n    int
+		want string
+	}{
+		{"Write the release notes", 50, "Write the release notes"},
+		{"Translate the onboarding flow", 12, "Translate..."},
+		{"日本語のテキストを翻訳してレビューする", 10, "日本語のテキス..."},
+	}
+	for _, tt := range tests {
+		got := truncateRunes(tt.in, tt.n)
+		if got != tt.want || !utf8.ValidString(got) {
+			t.Errorf("truncateRunes(%q, %d) = %q, want %q", tt.in, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestWatchTeamMembers(t *testing.T) {
+	var fetches atomic.Int32
+	upgrader := websocket.Upgrader{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/teams/alpha/members":
+			fetches.Add(1)
+			json.NewEncoder(w).Encode(map[string]interface{}{"members": []map[string]interface{}{
+				{"name": "Sarah", "title": "Project Manager", "status": "working", "task": "Plan the 日本語 launch"},
+			}})
+		case "/api/ws":
+			conn, err := upgrader.Upgrade(w, r, nil)
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+			// Another team's event is ignored; this team's redraws at once
+			conn.WriteJSON(map[string]interface{}{"type": "member_status", "team": "beta"})
+			conn.WriteJSON(map[string]interface{}{"type": "member_status", "team": "alpha"})
+			conn.ReadMessage() // Hold the stream open until the client goes
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+	client := daemon.NewRemoteClient(strings.TrimPrefix(ts.URL, "http://"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var out bytes.Buffer
+	done := make(chan error, 1)
+	go func() { done <- watchTeamMembers(ctx, &out, client, "alpha", time.Hour) }()
+
+	// The first draw, then one for alpha's event, long before the ticker
+	for deadline := time.Now().Add(5 * time.Second); fetches.Load() < 2; time.Sleep(5 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected a redraw on the team's event, got %d fetches", fetches.Load())
+		}
+	}
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("watchTeamMembers failed: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected watching to stop once the context is done")
+	}
+
+	if n := fetches.Load(); n != 2 {
+		t.Errorf("Expected 2 draws, got %d", n)
+	}
+	if s := out.String(); !strings.Contains(s, "Sarah (Project Manager)") || !strings.Contains(s, "(live)") {
+		t.Errorf("Expected the live member table, got:\n%s", s)
+	}
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/arcslash/ugudu/internal/manager"
+	"github.com/spf13/cobra"
+)
+
+func teamPerfCmd() *cobra.Command {
+	var since string
+	var outputJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "perf <team-name>",
+		Short: "Show where a team's time goes in model calls",
+		Long: `Show the latency and outcome of a team's model calls, by member and by
+model, slowest first. Use it to find the member or model holding a team up.
+
+Examples:
+  ugudu team perf my-team
+  ugudu team perf my-team --since 1h
+  ugudu team perf my-team --json`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			from, err := auditTime(since, time.Now())
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: --since: %v\n", err)
+				os.Exit(1)
+			}
+
+			client, err := requireDaemon()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+
+			perf, err := client.TeamPerformance(ctx, args[0], from)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			if outputJSON {
+				data, _ := json.MarshalIndent(perf, "", "  ")
+				fmt.Println(string(data))
+				return
+			}
+
+			if perf.Total.Calls == 0 {
+				fmt.Printf("No model calls recorded for team '%s'.\n", perf.Team)
+				return
+			}
+
+			t := perf.Total
+			fmt.Printf("Team '%s': %d calls, %s total, %s average, %s slowest\n",
+				perf.Team, t.Calls, perfDuration(t.TotalMs), perfDuration(t.AvgMs), perfDuration(t.MaxMs))
+			fmt.Printf("Outcomes: %s\n\n", perfOutcomes(t.Outcomes))
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "MEMBER\tCALLS\tTOTAL\tAVG\tMAX\tTOKENS IN/OUT\tFAILED")
+			for _, m := range perf.Members {
+				fmt.Fprintf(w, "%s\t%s\n", m.MemberID, perfRow(m.CallStats))
+			}
+			w.Flush()
+			fmt.Println()
+
+			w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "MODEL\tCALLS\tTOTAL\tAVG\tMAX\tTOKENS IN/OUT\tFAILED")
+			for _, m := range perf.Models {
+				fmt.Fprintf(w, "%s/%s\t%s\n", m.Provider, orDash(m.Model), perfRow(m.CallStats))
+			}
+			w.Flush()
+		},
+	}
+
+	cmd.Flags().StringVar(&since, "since", "", "only calls from this long ago (e.g. 1h) or this RFC 3339 time")
+	cmd.Flags().BoolVar(&outputJSON, "json", false, "output as JSON")
+
+	return cmd
+}
+
+// perfRow renders the stats columns of a member or model row
+func perfRow(s manager.CallStats) string {
+	return fmt.Sprintf("%d\t%s\t%s\t%s\t%d/%d\t%d",
+		s.Calls, perfDuration(s.TotalMs), perfDuration(s.AvgMs), perfDuration(s.MaxMs),
+		s.PromptTokens, s.CompletionTokens, s.Calls-s.Outcomes["ok"])
+}
+
+// perfDuration renders milliseconds at a precision that suits their size
+func perfDuration(ms int64) string {
+	d := time.Duration(ms) * time.Millisecond
+	if d < time.Second {
+		return d.String()
+	}
+	return d.Round(100 * time.Millisecond).String()
+}
+
+// perfOutcomes renders call counts by outcome, e.g. "ok 40, rate_limited 2"
+func perfOutcomes(outcomes map[string]int) string {
+	names := make([]string, 0, len(outcomes))
+	for name := range outcomes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s %d", name, outcomes[name])
+	}
+	return strings.Join(parts, ", ")
+}
+
+func teamBenchCmd() *cobra.Command {
+	var opts manager.BenchOptions
+	var promptFile string
+	var timeout int
+	var outputJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "bench <team-name>",
+		Short: "Time a team on a standard prompt",
+		Long: `Ask a team a standard prompt, or your own, and report how long it took end to
+end, the model calls and tokens it used, and each member's and model's share.
+Run it on teams built from different specs, or with different models, to
+compare them. With --iterations it asks several times, one after another, and
+reports latency percentiles.
+
+The asks go into members' context like any other, so bench a team that
+isn't in use, e.g. one created from the same spec under another name.
+
+Examples:
+  ugudu team bench my-team
+  ugudu team bench my-team -n 5
+  ugudu team bench my-team --prompt "Write a haiku about release day"
+  ugudu team bench my-team --prompt-file bench.txt --to engineer --json`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if promptFile != "" {
+				data, err := os.ReadFile(promptFile)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+					os.Exit(1)
+				}
+				opts.Prompt = strings.TrimSpace(string(data))
+			}
+			if opts.Iterations < 1 || opts.Iterations > manager.MaxBenchIterations {
+				fmt.Fprintf(os.Stderr, "Error: --iterations must be from 1 to %d\n", manager.MaxBenchIterations)
+				os.Exit(1)
+			}
+			opts.Timeout = time.Duration(timeout) * time.Second
+
+			client, err := requireDaemon()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+			defer cancel()
+
+			if !outputJSON {
+				fmt.Printf("Benchmarking team '%s' (%d run(s))...\n", args[0], opts.Iterations)
+			}
+			report, err := client.BenchTeam(ctx, args[0], opts)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			if outputJSON {
+				data, _ := json.MarshalIndent(report, "", "  ")
+				fmt.Println(string(data))
+				return
+			}
+
+			fmt.Println()
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "RUN\tLATENCY\tCALLS\tTOKENS IN/OUT\tFAILED\tERROR")
+			for i, run := range report.Runs {
+				fmt.Fprintf(w, "%d\t%s\t%d\t%d/%d\t%d\t%s\n", i+1, perfDuration(run.LatencyMs),
+					run.Calls, run.PromptTokens, run.CompletionTokens, run.Failed, orDash(run.Error))
+			}
+			w.Flush()
+
+			l := report.Latency
+			fmt.Printf("\nLatency: min %s, avg %s, p50 %s, p90 %s, p99 %s, max %s\n",
+				perfDuration(l.MinMs), perfDuration(l.AvgMs), perfDuration(l.P50Ms),
+				perfDuration(l.P90Ms), perfDuration(l.P99Ms), perfDuration(l.MaxMs))
+			t := report.Total
+			fmt.Printf("Model calls: %d, tokens %d in / %d out\n\n", t.Calls, t.PromptTokens, t.CompletionTokens)
+
+			if t.Calls == 0 {
+				return
+			}
+			w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "MEMBER\tCALLS\tTOTAL\tAVG\tMAX\tTOKENS IN/OUT\tFAILED")
+			for _, m := range report.Members {
+				fmt.Fprintf(w, "%s\t%s\n", m.MemberID, perfRow(m.CallStats))
+			}
+			w.Flush()
+			fmt.Println()
+
+			w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "MODEL\tCALLS\tTOTAL\tAVG\tMAX\tTOKENS IN/OUT\tFAILED")
+			for _, m := range report.Models {
+				fmt.Fprintf(w, "%s/%s\t%s\n", m.Provider, orDash(m.Model), perfRow(m.CallStats))
+			}
+			w.Flush()
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.Prompt, "prompt", "p", "", "prompt to send (default: a short planning ask)")
+	cmd.Flags().StringVar(&promptFile, "prompt-file", "", "read the prompt from a file")
+	cmd.Flags().StringVar(&opts.To, "to", "", "role or member to ask (default: the client-facing member)")
+	cmd.Flags().IntVarP(&opts.Iterations, "iterations", "n", 1, "number of runs")
+	cmd.Flags().IntVar(&timeout, "timeout", 300, "seconds to wait for each run")
+	cmd.Flags().BoolVar(&outputJSON, "json", false, "output as JSON")
+
+	return cmd
+}
@@ -5,9 +5,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 	"text/tabwriter"
 	"time"
 
+	"github.com/arcslash/ugudu/internal/daemon"
 	"github.com/arcslash/ugudu/internal/workspace"
 	"github.com/spf13/cobra"
 )
@@ -28,13 +31,17 @@ Examples:
   ugudu project create my-app --source ~/code/my-app --team dev-team
   ugudu project list
   ugudu project show my-app
-  ugudu project delete my-app`,
+  ugudu project artifacts my-app --download ./out
+  ugudu project delete my-app
+  ugudu project run dev-team "Build a todo app with login"`,
 	}
 
 	cmd.AddCommand(projectCreateCmd())
 	cmd.AddCommand(projectListCmd())
 	cmd.AddCommand(projectShowCmd())
+	cmd.AddCommand(projectArtifactsCmd())
 	cmd.AddCommand(projectDeleteCmd())
+	cmd.AddCommand(projectRunCmd())
 
 	return cmd
 }
@@ -104,6 +111,103 @@ Examples:
 	return cmd
 }
 
+func projectRunCmd() *cobra.Command {
+	var timeout int
+
+	cmd := &cobra.Command{
+		Use:   "run [team-name] [request]",
+		Short: "Run a request through the team's project workflow",
+		Long: `Start a project on a team and follow it through planning, task breakdown,
+execution and review. Phase and story changes print as they happen. The
+command returns when the project completes, or when it is blocked on
+questions for you; answer them with 'ugudu team answer' and it continues.
+
+Example:
+  ugudu project run dev-team "Build a todo app with login"`,
+		Args: cobra.MinimumNArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			client, err := requireDaemon()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			teamName := args[0]
+			request := strings.Join(args[1:], " ")
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Minute)
+			defer cancel()
+
+			started, err := client.StartProject(ctx, teamName, request)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error starting project: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Printf("Started project %v on team '%s'\n", started["id"], teamName)
+
+			var last string
+			status, err := client.WaitForProject(ctx, teamName, func(status map[string]interface{}) bool {
+				if summary := projectRunSummary(status); summary != last {
+					fmt.Println(summary)
+					last = summary
+				}
+				phase, _ := status["phase"].(string)
+				return phase == "complete" || phase == "blocked"
+			})
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				if ctx.Err() != nil {
+					fmt.Fprintf(os.Stderr, "The project is still running on team '%s'.\n", teamName)
+				}
+				os.Exit(1)
+			}
+
+			if status["phase"] == "blocked" {
+				fmt.Println("\nThe team has questions before it can continue:")
+				questions, _ := client.PendingQuestions(ctx, teamName)
+				for _, q := range questions {
+					if qm, ok := q.(map[string]interface{}); ok {
+						fmt.Printf("  [%v] %v\n", qm["id"], qm["content"])
+					}
+				}
+				fmt.Printf("\nAnswer with: ugudu team answer %s <question-id> \"<answer>\"\n", teamName)
+				return
+			}
+			fmt.Println("\nProject complete.")
+		},
+	}
+
+	cmd.Flags().IntVar(&timeout, "timeout", 60, "minutes to follow the project before giving up")
+
+	return cmd
+}
+
+// projectRunSummary describes a project status in one line: its phase and
+// how many stories are in each state
+func projectRunSummary(status map[string]interface{}) string {
+	summary := fmt.Sprintf("  phase: %v", status["phase"])
+
+	stories, _ := status["stories"].([]interface{})
+	if len(stories) == 0 {
+		return summary
+	}
+	counts := make(map[string]int)
+	var order []string
+	for _, st := range stories {
+		sm, _ := st.(map[string]interface{})
+		state := fmt.Sprint(sm["status"])
+		if counts[state] == 0 {
+			order = append(order, state)
+		}
+		counts[state]++
+	}
+	parts := make([]string, 0, len(order))
+	for _, state := range order {
+		parts = append(parts, fmt.Sprintf("%d %s", counts[state], state))
+	}
+	return fmt.Sprintf("%s  stories: %s", summary, strings.Join(parts, ", "))
+}
+
 func projectListCmd() *cobra.Command {
 	var outputJSON bool
 
@@ -212,6 +316,117 @@ func projectShowCmd() *cobra.Command {
 	return cmd
 }
 
+func projectArtifactsCmd() *cobra.Command {
+	var downloadDir string
+	var outputJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "artifacts [project-name]",
+		Short: "List or download the files a team produced",
+		Long: `List the files a team produced in a project: those its members wrote and
+the reports, specs and test results in the project's artifacts directory.
+Files deleted since they were written are marked stale.
+
+With --download, copies every artifact that still exists into a directory,
+keeping its relative path.
+
+Examples:
+  ugudu project artifacts my-app
+  ugudu project artifacts my-app --download ./out`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			projectName := args[0]
+
+			client, err := requireDaemon()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+
+			artifacts, err := client.ProjectArtifacts(ctx, projectName)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error listing artifacts: %v\n", err)
+				os.Exit(1)
+			}
+
+			if downloadDir != "" {
+				downloaded, err := downloadArtifacts(ctx, client, projectName, artifacts, downloadDir)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error downloading artifacts: %v\n", err)
+					os.Exit(1)
+				}
+				fmt.Printf("Downloaded %d artifact(s) to %s\n", downloaded, downloadDir)
+				return
+			}
+
+			if outputJSON {
+				data, _ := json.MarshalIndent(artifacts, "", "  ")
+				fmt.Println(string(data))
+				return
+			}
+
+			if len(artifacts) == 0 {
+				fmt.Println("No artifacts yet.")
+				return
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "ID\tPATH\tTYPE\tSIZE\tBY")
+			for _, a := range artifacts {
+				size := fmt.Sprintf("%.0f", a["size"])
+				if stale, _ := a["stale"].(bool); stale {
+					size = "(stale)"
+				}
+				fmt.Fprintf(w, "%v\t%v\t%v\t%s\t%v\n", a["id"], a["path"], a["type"], size, a["created_by"])
+			}
+			w.Flush()
+		},
+	}
+
+	cmd.Flags().StringVar(&downloadDir, "download", "", "copy the artifacts into this directory")
+	cmd.Flags().BoolVar(&outputJSON, "json", false, "output as JSON")
+
+	return cmd
+}
+
+// downloadArtifacts copies the artifacts that still exist into dir, at their
+// relative paths, returning how many it copied. Stale ones are skipped.
+func downloadArtifacts(ctx context.Context, client *daemon.Client, projectName string, artifacts []map[string]interface{}, dir string) (int, error) {
+	downloaded := 0
+	for _, a := range artifacts {
+		if stale, _ := a["stale"].(bool); stale {
+			continue
+		}
+		id, _ := a["id"].(string)
+		rel, _ := a["path"].(string)
+
+		dest := filepath.Join(dir, filepath.FromSlash(rel))
+		if r, err := filepath.Rel(dir, dest); err != nil || r == ".." || strings.HasPrefix(r, ".."+string(filepath.Separator)) {
+			return downloaded, fmt.Errorf("artifact %s: path %q is outside %s", id, rel, dir)
+		}
+		if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+			return downloaded, err
+		}
+
+		f, err := os.Create(dest)
+		if err != nil {
+			return downloaded, err
+		}
+		err = client.DownloadArtifact(ctx, projectName, id, f)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return downloaded, fmt.Errorf("%s: %w", rel, err)
+		}
+		downloaded++
+	}
+	return downloaded, nil
+}
+
 func projectDeleteCmd() *cobra.Command {
 	var force bool
 
@@ -3,7 +3,9 @@ package main
 import (
 	"bufio"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -15,7 +17,9 @@ import (
 	"github.com/arcslash/ugudu/internal/config"
 	"github.com/arcslash/ugudu/internal/provider"
 	"github.com/arcslash/ugudu/internal/specgen"
+	"github.com/arcslash/ugudu/internal/team"
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 )
 
 func specCmd() *cobra.Command {
@@ -33,14 +37,24 @@ Examples:
   ugudu spec ai "mobile app"     # Start with your idea
   ugudu spec list                # List available specs
   ugudu spec show my-team        # Show spec contents
-  ugudu spec delete my-team      # Delete a spec`,
+  ugudu spec lint my-team        # Check a spec for weak personas
+  ugudu spec delete my-team      # Delete a spec
+  ugudu spec add-specialist my-team devops  # Add a specialist role
+  ugudu spec history my-team     # Show saved versions
+  ugudu spec restore my-team 3   # Restore version 3
+  ugudu spec from-team alpha     # Save a running team's spec`,
 	}
 
 	cmd.AddCommand(specNewCmd())
 	cmd.AddCommand(specAICmd())
 	cmd.AddCommand(specListCmd())
 	cmd.AddCommand(specShowCmd())
+	cmd.AddCommand(specLintCmd())
 	cmd.AddCommand(specDeleteCmd())
+	cmd.AddCommand(specAddSpecialistCmd())
+	cmd.AddCommand(specHistoryCmd())
+	cmd.AddCommand(specRestoreCmd())
+	cmd.AddCommand(specFromTeamCmd())
 
 	return cmd
 }
@@ -222,6 +236,10 @@ func specAICmd() *cobra.Command {
 	var specName string
 	var providerID string
 	var model string
+	var maxTurns int
+	var nonInteractive bool
+	var outPath string
+	var force bool
 
 	cmd := &cobra.Command{
 		Use:   "ai [description]",
@@ -233,20 +251,34 @@ Just describe what you want to build and the AI will:
 - Suggest an appropriate team structure
 - Generate a complete specification
 
+After --max-turns replies it stops asking and generates a best-effort spec,
+showing how confident it is and what it assumed.
+
 This is perfect if you're new to Ugudu or unsure what team structure you need.
 
+With --non-interactive, the spec is generated from the description alone,
+with no questions, and written to --out or the specs directory. It exits
+non-zero if no valid spec could be generated, so it can run in scripts.
+
 Examples:
   ugudu spec ai                              # Start a conversation
   ugudu spec ai "mobile app for fitness"     # Start with your idea
-  ugudu spec ai "e-commerce site" --name shop-team`,
+  ugudu spec ai "e-commerce site" --name shop-team
+  ugudu spec ai "e-commerce site" --non-interactive --out shop.yaml`,
 		Run: func(cmd *cobra.Command, args []string) {
 			reader := bufio.NewReader(os.Stdin)
 
-			fmt.Println("╔══════════════════════════════════════════╗")
-			fmt.Println("║       U G U D U   S P E C   A I          ║")
-			fmt.Println("║     AI-Powered Team Design               ║")
-			fmt.Println("╚══════════════════════════════════════════╝")
-			fmt.Println()
+			if nonInteractive && len(args) == 0 {
+				fmt.Fprintln(os.Stderr, "Error: --non-interactive needs a description of the team")
+				os.Exit(1)
+			}
+			if !nonInteractive {
+				fmt.Println("╔══════════════════════════════════════════╗")
+				fmt.Println("║       U G U D U   S P E C   A I          ║")
+				fmt.Println("║     AI-Powered Team Design               ║")
+				fmt.Println("╚══════════════════════════════════════════╝")
+				fmt.Println()
+			}
 
 			// Load config to get API keys
 			cfg, err := config.Load()
@@ -254,13 +286,16 @@ Examples:
 				fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
 				os.Exit(1)
 			}
-			cfg.ApplyToEnvironment()
+			if err := cfg.ApplyToEnvironment(); err != nil {
+				fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+				os.Exit(1)
+			}
 
 			// Get provider
 			var llmProvider provider.Provider
-			apiKey := os.Getenv("ANTHROPIC_API_KEY")
+			apiKey := config.Getenv("ANTHROPIC_API_KEY")
 			if apiKey == "" {
-				apiKey = os.Getenv("OPENAI_API_KEY")
+				apiKey = config.Getenv("OPENAI_API_KEY")
 				if apiKey == "" {
 					fmt.Fprintln(os.Stderr, "Error: No API key configured.")
 					fmt.Fprintln(os.Stderr, "\nRun 'ugudu config init' to set up your API keys.")
@@ -280,6 +315,20 @@ Examples:
 			}
 
 			generator := specgen.NewGenerator(llmProvider, model)
+			generator.SetMaxTurns(maxTurns)
+
+			if nonInteractive {
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+				defer cancel()
+
+				path, err := generateSpecFile(ctx, generator, strings.Join(args, " "), specName, outPath, providerID, model, force)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+					os.Exit(1)
+				}
+				fmt.Println(path)
+				return
+			}
 
 			// Initial input
 			initialInput := ""
@@ -358,10 +407,50 @@ Examples:
 	cmd.Flags().StringVarP(&specName, "name", "n", "", "spec name (default: derived from project)")
 	cmd.Flags().StringVar(&providerID, "provider", "", "AI provider (anthropic, openai)")
 	cmd.Flags().StringVar(&model, "model", "", "model to use")
+	cmd.Flags().IntVar(&maxTurns, "max-turns", specgen.DefaultMaxTurns, "replies before a spec is generated with what's known (0 = no limit)")
+	cmd.Flags().BoolVar(&nonInteractive, "non-interactive", false, "generate from the description alone, without questions")
+	cmd.Flags().StringVarP(&outPath, "out", "o", "", "file to write the spec to with --non-interactive (default: the specs directory)")
+	cmd.Flags().BoolVar(&force, "force", false, "overwrite an existing spec with --non-interactive")
 
 	return cmd
 }
 
+// generateSpecFile generates a spec for description in one shot and writes
+// it to out, or to the specs directory under name (default: the name the
+// model chose). It returns the path written.
+func generateSpecFile(ctx context.Context, generator *specgen.Generator, description, name, out, providerID, model string, force bool) (string, error) {
+	spec, err := generator.Generate(ctx, description)
+	if err != nil {
+		return "", err
+	}
+	if name != "" {
+		spec.Name = name
+	}
+	if spec.Name == "" {
+		return "", fmt.Errorf("the generated spec has no name; pass --name")
+	}
+
+	content := []byte(spec.ToYAML(providerID, model))
+	if err := team.ValidateSpec(content); err != nil {
+		return "", fmt.Errorf("generated spec is invalid: %w", err)
+	}
+
+	path := out
+	if path == "" {
+		path = filepath.Join(config.SpecsDir(), spec.Name+".yaml")
+	}
+	if _, err := os.Stat(path); err == nil && !force {
+		return "", fmt.Errorf("%s already exists (use --force to overwrite)", path)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("create directory: %w", err)
+	}
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		return "", fmt.Errorf("write spec: %w", err)
+	}
+	return path, nil
+}
+
 func showAndSaveSpec(reader *bufio.Reader, spec *specgen.TeamSpec, specName, providerID, model string) {
 	// Use provided name or spec's name
 	name := specName
@@ -473,20 +562,91 @@ func specListCmd() *cobra.Command {
 }
 
 func specShowCmd() *cobra.Command {
-	return &cobra.Command{
+	var format string
+
+	cmd := &cobra.Command{
 		Use:   "show [spec-name]",
-		Short: "Show spec contents",
-		Args:  cobra.ExactArgs(1),
+		Short: "Show a spec file's contents (see 'team spec' for a running team)",
+		Long: `Show a spec file's contents.
+
+With --format json, the spec is loaded and printed in the same form as
+GET /api/specs/{name}, for scripts.`,
+		Args: cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			specPath := resolveSpecPath(args[0])
+			if _, err := os.Stat(specPath); err != nil {
+				fmt.Fprintf(os.Stderr, "Spec not found: %s\n", args[0])
+				os.Exit(1)
+			}
+
+			if err := showSpec(os.Stdout, specPath, format); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		},
+	}
 
-			content, err := os.ReadFile(specPath)
+	cmd.Flags().StringVar(&format, "format", "yaml", "output format: yaml or json")
+	return cmd
+}
+
+// showSpec writes a spec file as-is (yaml), or loaded and converted like
+// the API's spec endpoint (json)
+func showSpec(w io.Writer, specPath, format string) error {
+	switch format {
+	case "yaml":
+		content, err := os.ReadFile(specPath)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, string(content))
+		return nil
+	case "json":
+		spec, err := team.LoadSpec(specPath)
+		if err != nil {
+			return err
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(team.NewSpecView(spec))
+	}
+	return fmt.Errorf("unknown format %q (use yaml or json)", format)
+}
+
+func specLintCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "lint [spec-name]",
+		Short: "Check a spec for weak or contradictory roles",
+		Long: `Check a spec for problems that load fine but make a team behave badly:
+
+  no-delegation      a client-facing role in a multi-role team with no can_delegate
+  self-report        a role that reports_to itself
+  duplicate-persona  two roles with the same persona
+  no-instruction     a persona that never says what the member should do
+
+These are warnings, not errors. Teams are still created from specs that
+have them.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			specPath := resolveSpecPath(args[0])
+
+			spec, err := team.LoadSpec(specPath)
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "Spec not found: %s\n", args[0])
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 				os.Exit(1)
 			}
 
-			fmt.Println(string(content))
+			warnings := team.LintSpec(spec)
+			if len(warnings) == 0 {
+				fmt.Printf("No issues found in spec '%s'.\n", args[0])
+				return
+			}
+
+			for _, w := range warnings {
+				fmt.Printf("Warning: %s\n", w)
+			}
+			fmt.Println()
+			fmt.Printf("%d warning(s) in spec '%s'. These don't block team creation.\n", len(warnings), args[0])
 		},
 	}
 }
@@ -529,6 +689,216 @@ func specDeleteCmd() *cobra.Command {
 	return cmd
 }
 
+func specAddSpecialistCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "add-specialist [spec-name] [specialist]",
+		Short: "Add a specialist role to a spec",
+		Long: `Add a specialist role template to an existing spec.
+
+Specialists live in ~/.ugudu/specs/specialists/<name>.yaml. The specialist's
+role is added to the spec and the team lead (first client-facing role) is
+allowed to delegate to it.
+
+Examples:
+  ugudu spec add-specialist my-team devops
+  ugudu spec add-specialist my-team security-engineer`,
+		Args: cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			specPath := resolveSpecPath(args[0])
+
+			if _, err := os.Stat(specPath); os.IsNotExist(err) {
+				fmt.Fprintf(os.Stderr, "Spec not found: %s\n", args[0])
+				os.Exit(1)
+			}
+
+			result, err := team.AddSpecialist(specPath, args[1])
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			fmt.Printf("Added %s (%s) to spec '%s'.\n", result.Title, result.RoleID, args[0])
+			if result.Lead != "" {
+				fmt.Printf("%s can now delegate to %s.\n", result.Lead, result.RoleID)
+			}
+		},
+	}
+}
+
+func specHistoryCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "history [spec-name]",
+		Short: "Show saved versions of a spec",
+		Long: `Show the versions of a spec saved by the daemon.
+
+A version is recorded every time the spec is saved through the API or web UI.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			client, err := requireDaemon()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+
+			versions, err := client.ListSpecVersions(ctx, args[0])
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			if len(versions) == 0 {
+				fmt.Printf("No saved versions of spec '%s'.\n", args[0])
+				return
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "VERSION\tSAVED\tSIZE")
+			fmt.Fprintln(w, "───────\t─────\t────")
+
+			for _, v := range versions {
+				saved, _ := v["created_at"].(string)
+				if t, err := time.Parse(time.RFC3339Nano, saved); err == nil {
+					saved = t.Local().Format("2006-01-02 15:04:05")
+				}
+				fmt.Fprintf(w, "%v\t%s\t%v bytes\n", v["version"], saved, v["size"])
+			}
+			w.Flush()
+
+			fmt.Println()
+			fmt.Printf("Restore a version with: ugudu spec restore %s <version>\n", args[0])
+		},
+	}
+}
+
+func specRestoreCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "restore [spec-name] [version]",
+		Short: "Restore a saved version of a spec",
+		Long: `Replace a spec with one of its saved versions.
+
+The current content is kept in the history, so a restore can be undone by
+restoring again.`,
+		Args: cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			version, err := strconv.Atoi(args[1])
+			if err != nil || version < 1 {
+				fmt.Fprintf(os.Stderr, "Error: invalid version: %s\n", args[1])
+				os.Exit(1)
+			}
+
+			client, err := requireDaemon()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+
+			result, err := client.RestoreSpecVersion(ctx, args[0], version)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			fmt.Printf("Restored spec '%s' to version %d (saved as version %v).\n", args[0], version, result["version"])
+		},
+	}
+}
+
+func specFromTeamCmd() *cobra.Command {
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "from-team <team> [spec-name]",
+		Short: "Save a running team's spec as a new spec",
+		Long: `Save the spec a running team is using to the specs directory, so a team
+whose configuration has changed since it was created can be reused as a
+blueprint.
+
+Inheritance is resolved in the saved spec and defaults are filled in. The
+spec name defaults to the team's name.
+
+Examples:
+  ugudu spec from-team alpha
+  ugudu spec from-team alpha web-blueprint
+  ugudu spec from-team alpha --force   # Overwrite an existing spec`,
+		Args: cobra.RangeArgs(1, 2),
+		Run: func(cmd *cobra.Command, args []string) {
+			name := args[0]
+			if len(args) > 1 {
+				name = args[1]
+			}
+
+			client, err := requireDaemon()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+
+			content, err := client.GetTeamSpec(ctx, args[0], false)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			specPath := filepath.Join(config.SpecsDir(), name+".yaml")
+			if _, err := os.Stat(specPath); err == nil && !force {
+				reader := bufio.NewReader(os.Stdin)
+				overwrite := prompt(reader, fmt.Sprintf("Spec '%s' exists. Overwrite? [y/N]", name), "n")
+				if strings.ToLower(overwrite) != "y" {
+					fmt.Fprintln(os.Stderr, "Not saved. Give another spec name, or use --force to overwrite.")
+					os.Exit(1)
+				}
+			}
+
+			if err := writeTeamSpec(specPath, name, []byte(content)); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			fmt.Printf("Saved team '%s' as spec '%s' (%s)\n", args[0], name, specPath)
+			fmt.Printf("Create a team from it with: ugudu team create <name> --spec %s\n", name)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&force, "force", "f", false, "overwrite an existing spec without asking")
+
+	return cmd
+}
+
+// writeTeamSpec writes a running team's spec to path under a new name. The
+// daemon has already taken its global prompt out of the spec, so a team
+// created from it doesn't get the prompt twice.
+func writeTeamSpec(path, name string, content []byte) error {
+	var spec team.TeamSpec
+	if err := yaml.Unmarshal(content, &spec); err != nil {
+		return fmt.Errorf("decode team spec: %w", err)
+	}
+	spec.Metadata.Name = name
+
+	data, err := yaml.Marshal(&spec)
+	if err != nil {
+		return fmt.Errorf("encode spec: %w", err)
+	}
+	if err := team.ValidateSpec(data); err != nil {
+		return fmt.Errorf("team spec is invalid: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("create directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("write spec: %w", err)
+	}
+	return nil
+}
+
 // TeamConfig holds the configuration for generating a team
 type TeamConfig struct {
 	APIVersion   string
@@ -0,0 +1,280 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/arcslash/ugudu/internal/provider"
+	"github.com/arcslash/ugudu/internal/specgen"
+	"github.com/arcslash/ugudu/internal/team"
+	"gopkg.in/yaml.v3"
+)
+
+func TestShowSpec_JSON(t *testing.T) {
+	specPath := filepath.Join(t.TempDir(), "web-team.yaml")
+	os.WriteFile(specPath, []byte(`apiVersion: ugudu/v1
+kind: Team
+metadata:
+  name: web-team
+  description: Builds websites
+client_facing: [pm]
+roles:
+  pm:
+    title: Project Manager
+    name: Sarah
+    persona: Keeps the client happy.
+    model:
+      provider: anthropic
+      model: claude-sonnet-4-20250514
+  engineer:
+    title: Engineer
+    names: [Alex, Sam]
+    count: 2
+    model:
+      provider: openai
+      model: gpt-4o
+`), 0644)
+
+	spec, err := team.LoadSpec(specPath)
+	if err != nil {
+		t.Fatalf("LoadSpec failed: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := showSpec(&out, specPath, "json"); err != nil {
+		t.Fatalf("showSpec failed: %v", err)
+	}
+	var got team.SpecView
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("Output isn't JSON: %v\n%s", err, out.String())
+	}
+
+	if got.Name != spec.Metadata.Name || got.Description != spec.Metadata.Description {
+		t.Errorf("Expected %s (%s), got %s (%s)", spec.Metadata.Name, spec.Metadata.Description, got.Name, got.Description)
+	}
+	if !reflect.DeepEqual(got.ClientFacing, spec.ClientFacing) {
+		t.Errorf("Expected client_facing %v, got %v", spec.ClientFacing, got.ClientFacing)
+	}
+	if len(got.Roles) != len(spec.Roles) {
+		t.Fatalf("Expected %d roles, got %d", len(spec.Roles), len(got.Roles))
+	}
+	for id, role := range spec.Roles {
+		want := team.RoleView{
+			Title:    role.Title,
+			Name:     role.Name,
+			Names:    role.Names,
+			Count:    role.Count,
+			Persona:  role.Persona,
+			Provider: role.Model.Provider,
+			Model:    role.Model.Model,
+		}
+		if !reflect.DeepEqual(got.Roles[id], want) {
+			t.Errorf("Role %s: expected %+v, got %+v", id, want, got.Roles[id])
+		}
+	}
+	// The first role by ID with a provider stands for the team
+	if got.Provider != "openai" || got.Model != "gpt-4o" {
+		t.Errorf("Expected the engineer's model as the team's, got %s/%s", got.Provider, got.Model)
+	}
+
+	if err := showSpec(&out, specPath, "toml"); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+}
+
+// specProvider replies to every request with a fixed spec
+type specProvider struct{ requests int }
+
+func (p *specProvider) ID() string                   { return "stub" }
+func (p *specProvider) Name() string                 { return "Stub" }
+func (p *specProvider) Ping(_ context.Context) error { return nil }
+func (p *specProvider) ListModels(_ context.Context) ([]provider.ModelInfo, error) {
+	return nil, nil
+}
+func (p *specProvider) Stream(_ context.Context, _ *provider.ChatRequest) (<-chan provider.StreamChunk, error) {
+	ch := make(chan provider.StreamChunk)
+	close(ch)
+	return ch, nil
+}
+func (p *specProvider) Chat(_ context.Context, _ *provider.ChatRequest) (*provider.ChatResponse, error) {
+	p.requests++
+	return &provider.ChatResponse{Content: "```json\n" + `{"ready": true, "spec": {
+  "name": "shop-team",
+  "description": "Runs the shop",
+  "roles": [
+    {"id": "pm", "title": "PM", "visibility": "client", "persona": "You are the PM.", "can_delegate": ["engineer"]},
+    {"id": "engineer", "title": "Engineer", "visibility": "internal", "persona": "You are an engineer."}
+  ],
+  "client_facing": ["pm"]
+}}` + "\n```"}, nil
+}
+
+func TestGenerateSpecFile(t *testing.T) {
+	t.Setenv("UGUDU_HOME", t.TempDir())
+	stub := &specProvider{}
+	generator := specgen.NewGenerator(stub, "stub-model")
+
+	path, err := generateSpecFile(context.Background(), generator, "An online shop", "", "", "anthropic", "claude-sonnet-4-20250514", false)
+	if err != nil {
+		t.Fatalf("generateSpecFile failed: %v", err)
+	}
+	if stub.requests != 1 {
+		t.Errorf("Expected one request with no follow-up questions, got %d", stub.requests)
+	}
+	if want := filepath.Join(os.Getenv("UGUDU_HOME"), "specs", "shop-team.yaml"); path != want {
+		t.Errorf("Expected the spec written to %s, got %s", want, path)
+	}
+	spec, err := team.LoadSpec(path)
+	if err != nil {
+		t.Fatalf("Written spec doesn't load: %v", err)
+	}
+	if spec.Metadata.Name != "shop-team" || len(spec.Roles) != 2 {
+		t.Errorf("Unexpected spec: %+v", spec)
+	}
+
+	// An existing spec is only replaced with force
+	if _, err := generateSpecFile(context.Background(), generator, "An online shop", "", "", "anthropic", "", false); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("Expected an error for an existing spec, got %v", err)
+	}
+
+	out := filepath.Join(t.TempDir(), "ci", "team.yaml")
+	if path, err := generateSpecFile(context.Background(), generator, "An online shop", "ci-team", out, "anthropic", "", false); err != nil || path != out {
+		t.Fatalf("Expected the spec written to --out, got %s, %v", path, err)
+	}
+	if spec, err := team.LoadSpec(out); err != nil || spec.Metadata.Name != "ci-team" {
+		t.Errorf("Expected --name to set the spec's name, got %v, %v", spec, err)
+	}
+}
+
+func TestWriteTeamSpec(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "base.yaml"), []byte(`apiVersion: ugudu/v1
+kind: Team
+metadata:
+  name: base
+client_facing: [pm]
+roles:
+  pm:
+    title: Project Manager
+    persona: Keeps the client happy.
+    model:
+      provider: anthropic
+      model: claude-sonnet-4-20250514
+`), 0644)
+	os.WriteFile(filepath.Join(dir, "alpha.yaml"), []byte(`apiVersion: ugudu/v1
+kind: Team
+extends: base
+metadata:
+  name: alpha
+roles:
+  engineer:
+    title: Engineer
+    names: [Alex, Sam, Kim]
+    count: 3
+    persona: Writes the code.
+    model:
+      provider: openai
+      model: gpt-4o
+settings:
+  system_prefix: Follow the style guide.
+`), 0644)
+
+	running, err := team.LoadSpec(filepath.Join(dir, "alpha.yaml"))
+	if err != nil {
+		t.Fatalf("LoadSpec failed: %v", err)
+	}
+	content, err := yaml.Marshal(running)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	path := filepath.Join(dir, "specs", "blueprint.yaml")
+	if err := writeTeamSpec(path, "blueprint", content); err != nil {
+		t.Fatalf("writeTeamSpec failed: %v", err)
+	}
+	saved, err := team.LoadSpec(path)
+	if err != nil {
+		t.Fatalf("LoadSpec of the saved spec failed: %v", err)
+	}
+
+	if saved.Metadata.Name != "blueprint" {
+		t.Errorf("Expected the spec to be named blueprint, got %s", saved.Metadata.Name)
+	}
+	if saved.Extends != "" {
+		t.Errorf("Expected inheritance resolved, got extends %q", saved.Extends)
+	}
+	if !reflect.DeepEqual(saved.ClientFacing, running.ClientFacing) {
+		t.Errorf("Expected client_facing %v, got %v", running.ClientFacing, saved.ClientFacing)
+	}
+	if len(saved.Roles) != len(running.Roles) {
+		t.Fatalf("Expected %d roles, got %d", len(running.Roles), len(saved.Roles))
+	}
+	for id, role := range running.Roles {
+		got, ok := saved.Roles[id]
+		if !ok {
+			t.Errorf("Expected role %s in the saved spec", id)
+			continue
+		}
+		if got.Count != role.Count || !reflect.DeepEqual(got.Names, role.Names) || got.Model.Model != role.Model.Model {
+			t.Errorf("Role %s: expected count %d, names %v, model %s, got %d, %v, %s",
+				id, role.Count, role.Names, role.Model.Model, got.Count, got.Names, got.Model.Model)
+		}
+	}
+	if saved.Settings.SystemPrefix != "Follow the style guide." {
+		t.Errorf("Expected the team's own prefix kept, got %q", saved.Settings.SystemPrefix)
+	}
+}
+
+func TestWriteTeamSpecKeepsSecretReferences(t *testing.T) {
+	dir := t.TempDir()
+	tokenPath := filepath.Join(dir, "deploy-token")
+	os.WriteFile(tokenPath, []byte("s3cret-deploy-token\n"), 0600)
+	os.WriteFile(filepath.Join(dir, "alpha.yaml"), []byte(`apiVersion: ugudu/v1
+kind: Team
+metadata:
+  name: alpha
+client_facing: [pm]
+roles:
+  pm:
+    title: Project Manager
+    model:
+      provider: anthropic
+      model: claude-sonnet-4-20250514
+settings:
+  env:
+    DEPLOY_TOKEN: ${file:`+tokenPath+`}
+`), 0644)
+
+	// The daemon serves the spec as written, before secrets are expanded
+	running, err := team.LoadRawSpec(filepath.Join(dir, "alpha.yaml"))
+	if err != nil {
+		t.Fatalf("LoadRawSpec failed: %v", err)
+	}
+	content, err := yaml.Marshal(running)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	path := filepath.Join(dir, "specs", "blueprint.yaml")
+	if err := writeTeamSpec(path, "blueprint", content); err != nil {
+		t.Fatalf("writeTeamSpec failed: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), "s3cret-deploy-token") {
+		t.Errorf("Expected the secret kept out of the saved spec, got:\n%s", data)
+	}
+
+	saved, err := team.LoadSpec(path)
+	if err != nil {
+		t.Fatalf("LoadSpec of the saved spec failed: %v", err)
+	}
+	if got := saved.Settings.Env["DEPLOY_TOKEN"]; got != "s3cret-deploy-token" {
+		t.Errorf("Expected the saved spec to still resolve the secret, got %q", got)
+	}
+}
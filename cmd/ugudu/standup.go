@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/arcslash/ugudu/internal/config"
+	"github.com/arcslash/ugudu/internal/provider"
 	"github.com/arcslash/ugudu/internal/workspace"
 	"github.com/spf13/cobra"
 )
@@ -13,6 +15,7 @@ func standupCmd() *cobra.Command {
 	var period string
 	var detailed bool
 	var outputJSON bool
+	var summarize bool
 
 	cmd := &cobra.Command{
 		Use:   "standup [project-name]",
@@ -24,6 +27,7 @@ Examples:
   ugudu standup my-project                 # Daily standup report
   ugudu standup my-project --period weekly # Weekly summary
   ugudu standup my-project --detailed      # Include per-member breakdown
+  ugudu standup my-project --summarize     # Add a written summary from a model
   ugudu standup my-project --json          # Output as JSON`,
 		Args: cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
@@ -47,8 +51,17 @@ Examples:
 				standupPeriod = workspace.PeriodDaily
 			}
 
+			var opts []workspace.StandupOption
+			if summarize {
+				if p, model, ok := summaryProvider(); ok {
+					opts = append(opts, workspace.WithSummarizer(p, model))
+				} else {
+					fmt.Fprintln(os.Stderr, "Warning: no provider configured, skipping the summary")
+				}
+			}
+
 			// Generate report
-			generator := workspace.NewStandupGenerator(ws)
+			generator := workspace.NewStandupGenerator(ws, opts...)
 			report, err := generator.Generate(standupPeriod)
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "Error generating report: %v\n", err)
@@ -69,6 +82,21 @@ Examples:
 	cmd.Flags().StringVarP(&period, "period", "p", "daily", "report period (daily, weekly)")
 	cmd.Flags().BoolVarP(&detailed, "detailed", "d", false, "include per-member breakdown")
 	cmd.Flags().BoolVar(&outputJSON, "json", false, "output as JSON")
+	cmd.Flags().BoolVar(&summarize, "summarize", false, "add a prose summary written by a cheap model")
 
 	return cmd
 }
+
+// summaryProvider picks a configured provider and cheap model to write
+// standup summaries with
+func summaryProvider() (provider.Provider, string, bool) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, "", false
+	}
+	cfg.ApplyToEnvironment()
+
+	registry := provider.NewRegistry()
+	registry.AutoDiscover()
+	return registry.SmallModel()
+}
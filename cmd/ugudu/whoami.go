@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/arcslash/ugudu/internal/config"
+	"github.com/arcslash/ugudu/internal/daemon"
+	"github.com/arcslash/ugudu/internal/provider"
+	"github.com/spf13/cobra"
+)
+
+// daemonTarget is the daemon the CLI talks to, and why
+type daemonTarget struct {
+	Host   string // Remote daemon address, if any
+	Socket string // Unix socket path otherwise; "" if none was found
+	Source string // --host, --socket, auto-detected or not found
+}
+
+// resolveDaemonTarget picks the daemon from the --host and --socket flags,
+// in that order, falling back to looking for a local socket
+func resolveDaemonTarget(host, socket string, find func() string) daemonTarget {
+	switch {
+	case host != "":
+		return daemonTarget{Host: host, Source: "--host"}
+	case socket != "":
+		return daemonTarget{Socket: socket, Source: "--socket"}
+	}
+	if found := find(); found != "" {
+		return daemonTarget{Socket: found, Source: "auto-detected"}
+	}
+	return daemonTarget{Source: "not found"}
+}
+
+func (t daemonTarget) String() string {
+	switch {
+	case t.Host != "":
+		return fmt.Sprintf("http://%s (%s)", t.Host, t.Source)
+	case t.Socket != "":
+		return fmt.Sprintf("%s (%s)", t.Socket, t.Source)
+	}
+	return "no socket found"
+}
+
+func whoamiCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "whoami",
+		Short: "Show which daemon and config the CLI is using",
+		Long: `Show what the CLI resolved: the daemon it talks to and how it was
+chosen, whether it's reachable, the config file, the configured providers
+and the data directory.
+
+Exits non-zero if the daemon can't be reached.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			target := resolveDaemonTarget(remoteAddr, socketPath, daemon.FindSocket)
+			fmt.Printf("Daemon:     %s\n", target)
+
+			var status map[string]interface{}
+			var providers []string
+			reachErr := fmt.Errorf("no socket found; start it with: ugudu daemon")
+			if client, err := getClient(); err == nil {
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+				if reachErr = client.Ping(ctx); reachErr == nil {
+					status, _ = client.Status(ctx)
+					if list, err := client.ListProviders(ctx); err == nil {
+						for _, p := range list {
+							if id, ok := p["id"].(string); ok {
+								providers = append(providers, id)
+							}
+						}
+					}
+				}
+			}
+
+			daemonInfo, _ := status["daemon"].(map[string]interface{})
+			if reachErr != nil {
+				fmt.Printf("Reachable:  no (%v)\n", reachErr)
+			} else if daemonInfo != nil {
+				fmt.Printf("Reachable:  yes (version %v, up %v)\n", daemonInfo["version"], daemonInfo["uptime"])
+			} else {
+				fmt.Println("Reachable:  yes")
+			}
+
+			configPath := config.ConfigPath()
+			if _, err := os.Stat(configPath); os.IsNotExist(err) {
+				fmt.Printf("Config:     %s (not created; using environment only)\n", configPath)
+			} else if _, err := config.Load(); err != nil {
+				fmt.Printf("Config:     %s (%v)\n", configPath, err)
+			} else {
+				fmt.Printf("Config:     %s\n", configPath)
+			}
+
+			// The daemon's providers are what teams use; without it, show what
+			// this environment would configure
+			providersFrom := "daemon"
+			if reachErr != nil {
+				registry := provider.NewRegistry()
+				registry.AutoDiscover()
+				for _, p := range registry.List() {
+					providers = append(providers, p.ID())
+				}
+				providersFrom = "this environment"
+			}
+			sort.Strings(providers)
+			if len(providers) == 0 {
+				providers = []string{"none"}
+			}
+			fmt.Printf("Providers:  %s (%s)\n", strings.Join(providers, ", "), providersFrom)
+
+			if dataDir, ok := daemonInfo["data_dir"].(string); ok {
+				fmt.Printf("Data:       %s\n", dataDir)
+			} else {
+				fmt.Printf("Data:       %s\n", config.DataDir())
+			}
+
+			if reachErr != nil {
+				os.Exit(1)
+			}
+		},
+	}
+}
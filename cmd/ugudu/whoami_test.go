@@ -0,0 +1,28 @@
+package main
+
+import "testing"
+
+func TestResolveDaemonTarget(t *testing.T) {
+	found := func() string { return "/home/me/.ugudu/ugudu.sock" }
+	none := func() string { return "" }
+
+	tests := []struct {
+		name   string
+		host   string
+		socket string
+		find   func() string
+		want   daemonTarget
+	}{
+		{"host wins over socket", "remote:8080", "/tmp/ugudu.sock", found, daemonTarget{Host: "remote:8080", Source: "--host"}},
+		{"socket flag wins over detection", "", "/tmp/ugudu.sock", found, daemonTarget{Socket: "/tmp/ugudu.sock", Source: "--socket"}},
+		{"auto-detected", "", "", found, daemonTarget{Socket: "/home/me/.ugudu/ugudu.sock", Source: "auto-detected"}},
+		{"nothing found", "", "", none, daemonTarget{Source: "not found"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resolveDaemonTarget(tt.host, tt.socket, tt.find); got != tt.want {
+				t.Errorf("Expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/arcslash/ugudu/internal/team"
+)
+
+// bulkChatResult is one team's part of a bulk chat reply. A team that fails
+// reports its error here rather than failing the whole batch.
+type bulkChatResult struct {
+	Responses         []map[string]interface{} `json:"responses"`
+	Error             *APIError                `json:"error,omitempty"`
+	Timeout           bool                     `json:"timeout,omitempty"`
+	ContinuationToken string                   `json:"continuation_token,omitempty"`
+}
+
+// handleChatBulk asks several teams the same question at once and returns
+// each team's responses once they have all finished or timed out
+func (s *Server) handleChatBulk(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		s.error(w, http.StatusMethodNotAllowed, "POST required")
+		return
+	}
+
+	var req struct {
+		Teams     []string `json:"teams"`
+		Message   string   `json:"message"`
+		To        string   `json:"to,omitempty"`        // Optional: the same role in every team
+		Verbosity string   `json:"verbosity,omitempty"` // quiet, summary (default) or full
+		NoTools   bool     `json:"no_tools,omitempty"`  // Answer without offering the model any tools
+
+		// How long to wait for each team before returning a continuation
+		// token for it (max 600)
+		TimeoutSeconds int `json:"timeout_seconds,omitempty"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	// Drop blanks and repeats so each team is asked once
+	seen := make(map[string]bool)
+	var teams []string
+	for _, name := range req.Teams {
+		if name != "" && !seen[name] {
+			seen[name] = true
+			teams = append(teams, name)
+		}
+	}
+	if len(teams) == 0 || req.Message == "" {
+		s.error(w, http.StatusBadRequest, "teams and message required")
+		return
+	}
+
+	verbosity, ok := parseVerbosity(req.Verbosity)
+	if !ok {
+		s.error(w, http.StatusBadRequest, "verbosity must be quiet, summary or full")
+		return
+	}
+
+	requestID := chatRequestID(w, r)
+	s.logger.Debug("bulk chat request", "teams", teams, "to", req.To, "request_id", requestID)
+
+	opts := []team.AskOption{team.WithVerbosity(verbosity), team.WithRequestID(requestID)}
+	if req.NoTools {
+		opts = append(opts, team.WithoutTools())
+	}
+	timeout := chatTimeout(req.TimeoutSeconds)
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	results := make(map[string]bulkChatResult, len(teams))
+	for _, name := range teams {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			result := s.askForBulk(r.Context(), name, req.To, req.Message, timeout, opts)
+			mu.Lock()
+			results[name] = result
+			mu.Unlock()
+		}(name)
+	}
+	wg.Wait()
+
+	s.json(w, http.StatusOK, map[string]interface{}{"results": results})
+}
+
+// askForBulk asks one team on behalf of a bulk chat and collects its
+// responses, parking the ask if the team is still working at the timeout
+func (s *Server) askForBulk(ctx context.Context, teamName, to, message string, timeout time.Duration, opts []team.AskOption) bulkChatResult {
+	result := bulkChatResult{Responses: []map[string]interface{}{}}
+
+	release, _, ok := s.chats.acquire(teamName)
+	if !ok {
+		result.Error = &APIError{Code: CodeRateLimited, Message: "too many chat requests, retry later"}
+		return result
+	}
+	defer release()
+
+	_ = s.manager.StartTeam(teamName)
+
+	var respChan <-chan team.Message
+	var err error
+	if to != "" {
+		respChan, err = s.manager.AskMember(teamName, to, message, opts...)
+	} else {
+		respChan, err = s.manager.Ask(teamName, message, opts...)
+	}
+	if err != nil {
+		_, apiErr := errorFor(err)
+		result.Error = &apiErr
+		return result
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for {
+		select {
+		case <-ctx.Done():
+			result.Timeout = true
+			if t, err := s.manager.GetTeam(teamName); err == nil {
+				result.ContinuationToken = t.SuspendAsk(respChan, to)
+			}
+			return result
+
+		case msg, ok := <-respChan:
+			if !ok {
+				return result
+			}
+			result.Responses = append(result.Responses, responseEntry(msg))
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package api
+
+import (
+	"io/fs"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"github.com/arcslash/ugudu/internal/version"
+)
+
+// daemonStatus reports on the daemon process itself: how long it has been up,
+// what it's running and what it's using. It helps spot leaks and capacity
+// problems that team status doesn't show.
+func (s *Server) daemonStatus() map[string]interface{} {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	uptime := time.Since(s.started)
+	dataDir := s.manager.DataDir()
+	build := version.Get()
+
+	return map[string]interface{}{
+		"version":        build.Version,
+		"commit":         build.Commit,
+		"build_date":     build.Date,
+		"go_version":     build.GoVersion,
+		"started_at":     s.started,
+		"uptime":         uptime.Round(time.Second).String(),
+		"uptime_seconds": int64(uptime.Seconds()),
+		"goroutines":     runtime.NumGoroutine(),
+		"memory": map[string]interface{}{
+			"alloc_bytes":  mem.Alloc,
+			"sys_bytes":    mem.Sys,
+			"heap_objects": mem.HeapObjects,
+			"num_gc":       mem.NumGC,
+		},
+		// In-flight API requests, including the one asking
+		"active_requests": s.activeRequests.Load(),
+		"data_dir":        dataDir,
+		"data_dir_bytes":  dirSize(dataDir),
+	}
+}
+
+// dirSize sums the sizes of the regular files under dir. Unreadable entries
+// are skipped, so the result is a lower bound.
+func dirSize(dir string) int64 {
+	var total int64
+	filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.Type().IsRegular() {
+			if info, err := d.Info(); err == nil {
+				total += info.Size()
+			}
+		}
+		return nil
+	})
+	return total
+}
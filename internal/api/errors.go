@@ -0,0 +1,131 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/arcslash/ugudu/internal/manager"
+	"github.com/arcslash/ugudu/internal/provider"
+	"github.com/arcslash/ugudu/internal/team"
+	"github.com/arcslash/ugudu/internal/workspace"
+)
+
+// ErrorCode is a stable, machine-readable error category. Messages may be
+// reworded between releases; codes may not.
+type ErrorCode string
+
+const (
+	CodeNotFound      ErrorCode = "NOT_FOUND"      // The named team, spec, project, etc. doesn't exist
+	CodeValidation    ErrorCode = "VALIDATION"     // The request was malformed or failed validation
+	CodeProviderError ErrorCode = "PROVIDER_ERROR" // An LLM provider failed or rejected the call
+	CodeRateLimited   ErrorCode = "RATE_LIMITED"   // Too many requests, retry later
+	CodeConflict      ErrorCode = "CONFLICT"       // The team's current state doesn't allow the request
+	CodeInternal      ErrorCode = "INTERNAL"       // Anything else
+)
+
+// APIError is the body of every error response: {"error": {...}}
+type APIError struct {
+	Code    ErrorCode              `json:"code"`
+	Message string                 `json:"message"`
+	Details map[string]interface{} `json:"details,omitempty"`
+}
+
+// codeForStatus picks the code for errors reported by status alone
+func codeForStatus(status int) ErrorCode {
+	switch {
+	case status == http.StatusNotFound:
+		return CodeNotFound
+	case status == http.StatusTooManyRequests:
+		return CodeRateLimited
+	case status == http.StatusBadGateway:
+		return CodeProviderError
+	case status == http.StatusConflict:
+		return CodeConflict
+	case status >= 400 && status < 500:
+		return CodeValidation
+	default:
+		return CodeInternal
+	}
+}
+
+// writeError sends an error response with an explicit code
+func (s *Server) writeError(w http.ResponseWriter, status int, code ErrorCode, message string, details map[string]interface{}) {
+	s.json(w, status, map[string]interface{}{
+		"error": APIError{Code: code, Message: message, Details: details},
+	})
+}
+
+// notFound reports a missing resource, naming its kind ("team", "spec", ...)
+// so clients can tell which lookup failed
+func (s *Server) notFound(w http.ResponseWriter, resource, message string) {
+	s.writeError(w, http.StatusNotFound, CodeNotFound, message, map[string]interface{}{
+		"resource": resource,
+	})
+}
+
+// fail reports an error returned by the manager or a provider, choosing the
+// status and code from what went wrong
+func (s *Server) fail(w http.ResponseWriter, err error) {
+	status, apiErr := errorFor(err)
+	s.json(w, status, map[string]interface{}{"error": apiErr})
+}
+
+// errorFor maps an error returned by the manager or a provider to its status
+// and response body
+func errorFor(err error) (int, APIError) {
+	notFound := func(resource string) (int, APIError) {
+		return http.StatusNotFound, APIError{Code: CodeNotFound, Message: err.Error(), Details: map[string]interface{}{
+			"resource": resource,
+		}}
+	}
+
+	switch {
+	case errors.Is(err, manager.ErrTeamNotFound):
+		return notFound("team")
+	case errors.Is(err, manager.ErrMemberNotFound):
+		return notFound("member")
+	case errors.Is(err, manager.ErrConversationNotFound):
+		return notFound("conversation")
+	case errors.Is(err, team.ErrNoProject):
+		return notFound("project")
+	case errors.Is(err, team.ErrQuestionNotFound):
+		return notFound("question")
+	case errors.Is(err, team.ErrApprovalNotFound):
+		return notFound("approval")
+	case errors.Is(err, workspace.ErrArtifactNotFound):
+		return notFound("artifact")
+	case errors.Is(err, workspace.ErrAccessDenied):
+		return http.StatusForbidden, APIError{Code: CodeValidation, Message: err.Error()}
+	case errors.Is(err, team.ErrTooManyMembers):
+		return http.StatusBadRequest, APIError{Code: CodeValidation, Message: err.Error()}
+	case errors.Is(err, team.ErrTeamPaused), errors.Is(err, manager.ErrTeamExists):
+		return http.StatusConflict, APIError{Code: CodeConflict, Message: err.Error()}
+	case errors.Is(err, manager.ErrNoSummaryProvider):
+		return http.StatusServiceUnavailable, APIError{Code: CodeProviderError, Message: err.Error()}
+	case provider.IsAuthError(err):
+		return http.StatusBadGateway, APIError{Code: CodeProviderError, Message: err.Error(), Details: map[string]interface{}{
+			"reason": "bad_credentials",
+		}}
+	}
+
+	var missing *provider.NotFoundError
+	if errors.As(err, &missing) {
+		details := map[string]interface{}{
+			"provider":  missing.ID,
+			"available": missing.Available,
+		}
+		if missing.EnvVar != "" {
+			details["env_var"] = missing.EnvVar
+		}
+		return http.StatusBadRequest, APIError{Code: CodeValidation, Message: err.Error(), Details: details}
+	}
+
+	if info, ok := provider.IsRateLimitError(err); ok {
+		details := map[string]interface{}{}
+		if info != nil && !info.ResetAt.IsZero() {
+			details["reset_at"] = info.ResetAt
+		}
+		return http.StatusTooManyRequests, APIError{Code: CodeRateLimited, Message: err.Error(), Details: details}
+	}
+	return http.StatusInternalServerError, APIError{Code: CodeInternal, Message: err.Error()}
+}
@@ -0,0 +1,135 @@
+package api
+
+import (
+	"sync"
+	"time"
+)
+
+// ChatLimits caps how many chats the API will run at once and how fast they
+// may arrive. A zero value disables that particular limit.
+type ChatLimits struct {
+	MaxConcurrent            int // Chats in flight across all teams
+	MaxConcurrentPerTeam     int // Chats in flight for a single team
+	RequestsPerMinute        int // Chats started per minute across all teams
+	RequestsPerMinutePerTeam int // Chats started per minute for a single team
+}
+
+// DefaultChatLimits returns limits generous enough for interactive use
+// while still stopping a runaway script
+func DefaultChatLimits() ChatLimits {
+	return ChatLimits{
+		MaxConcurrent:            16,
+		MaxConcurrentPerTeam:     4,
+		RequestsPerMinute:        60,
+		RequestsPerMinutePerTeam: 30,
+	}
+}
+
+// concurrencyRetryAfter is suggested to clients rejected for concurrency,
+// since there's no way to know when a running chat will finish
+const concurrencyRetryAfter = 5 * time.Second
+
+// chatLimiter enforces ChatLimits for the chat endpoint
+type chatLimiter struct {
+	limits ChatLimits
+
+	active     int
+	teamActive map[string]int
+
+	// Start times within the last minute, for the request-rate limits
+	recent     []time.Time
+	teamRecent map[string][]time.Time
+
+	now func() time.Time
+	mu  sync.Mutex
+}
+
+func newChatLimiter(limits ChatLimits) *chatLimiter {
+	return &chatLimiter{
+		limits:     limits,
+		teamActive: make(map[string]int),
+		teamRecent: make(map[string][]time.Time),
+		now:        time.Now,
+	}
+}
+
+// acquire reserves a chat slot for a team. When a limit is hit it returns
+// ok=false and how long the client should wait before retrying; otherwise the
+// returned release func must be called once the chat finishes.
+func (l *chatLimiter) acquire(teamName string) (release func(), retryAfter time.Duration, ok bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.recent = pruneWindow(l.recent, now)
+
+	// Prune every team's window, not just this one's, and forget teams with
+	// no chats in it, so names that are never asked again don't pile up
+	for name, times := range l.teamRecent {
+		if times = pruneWindow(times, now); len(times) > 0 {
+			l.teamRecent[name] = times
+		} else {
+			delete(l.teamRecent, name)
+		}
+	}
+
+	if l.limits.MaxConcurrent > 0 && l.active >= l.limits.MaxConcurrent {
+		return nil, concurrencyRetryAfter, false
+	}
+	if l.limits.MaxConcurrentPerTeam > 0 && l.teamActive[teamName] >= l.limits.MaxConcurrentPerTeam {
+		return nil, concurrencyRetryAfter, false
+	}
+	if l.limits.RequestsPerMinute > 0 && len(l.recent) >= l.limits.RequestsPerMinute {
+		return nil, l.recent[0].Add(time.Minute).Sub(now), false
+	}
+	if recent := l.teamRecent[teamName]; l.limits.RequestsPerMinutePerTeam > 0 && len(recent) >= l.limits.RequestsPerMinutePerTeam {
+		return nil, recent[0].Add(time.Minute).Sub(now), false
+	}
+
+	l.active++
+	l.teamActive[teamName]++
+	l.recent = append(l.recent, now)
+	l.teamRecent[teamName] = append(l.teamRecent[teamName], now)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			l.active--
+			if l.teamActive[teamName]--; l.teamActive[teamName] <= 0 {
+				delete(l.teamActive, teamName)
+			}
+		})
+	}, 0, true
+}
+
+// stats reports current chat concurrency for the status endpoint
+func (l *chatLimiter) stats() map[string]interface{} {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	perTeam := make(map[string]int, len(l.teamActive))
+	for name, n := range l.teamActive {
+		perTeam[name] = n
+	}
+
+	return map[string]interface{}{
+		"active":                       l.active,
+		"active_per_team":              perTeam,
+		"max_concurrent":               l.limits.MaxConcurrent,
+		"max_concurrent_per_team":      l.limits.MaxConcurrentPerTeam,
+		"requests_per_minute":          l.limits.RequestsPerMinute,
+		"requests_per_minute_per_team": l.limits.RequestsPerMinutePerTeam,
+	}
+}
+
+// pruneWindow drops start times older than a minute
+func pruneWindow(times []time.Time, now time.Time) []time.Time {
+	cutoff := now.Add(-time.Minute)
+	i := 0
+	for i < len(times) && !times[i].After(cutoff) {
+		i++
+	}
+	return times[i:]
+}
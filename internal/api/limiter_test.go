@@ -0,0 +1,144 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestChatLimiter_ConcurrentChats(t *testing.T) {
+	limiter := newChatLimiter(ChatLimits{MaxConcurrent: 5})
+
+	var mu sync.Mutex
+	var releases []func()
+	rejected := 0
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			release, retryAfter, ok := limiter.acquire("team")
+
+			mu.Lock()
+			defer mu.Unlock()
+			if !ok {
+				if retryAfter <= 0 {
+					t.Errorf("Expected positive retry-after, got %v", retryAfter)
+				}
+				rejected++
+				return
+			}
+			releases = append(releases, release)
+		}()
+	}
+	wg.Wait()
+
+	if len(releases) != 5 || rejected != 15 {
+		t.Fatalf("Expected 5 accepted and 15 rejected, got %d and %d", len(releases), rejected)
+	}
+
+	// Releasing frees the slots; releasing twice doesn't double-count
+	for _, release := range releases {
+		release()
+		release()
+	}
+	if stats := limiter.stats(); stats["active"] != 0 {
+		t.Errorf("Expected 0 active chats after release, got %v", stats["active"])
+	}
+	if _, _, ok := limiter.acquire("team"); !ok {
+		t.Error("Expected acquire to succeed after release")
+	}
+}
+
+func TestChatLimiter_PerTeam(t *testing.T) {
+	limiter := newChatLimiter(ChatLimits{MaxConcurrentPerTeam: 1})
+
+	if _, _, ok := limiter.acquire("alpha"); !ok {
+		t.Fatal("Expected first alpha chat to be accepted")
+	}
+	if _, _, ok := limiter.acquire("alpha"); ok {
+		t.Error("Expected second alpha chat to be rejected")
+	}
+	if _, _, ok := limiter.acquire("beta"); !ok {
+		t.Error("Expected beta chat to be accepted")
+	}
+}
+
+func TestChatLimiter_RequestRate(t *testing.T) {
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	limiter := newChatLimiter(ChatLimits{RequestsPerMinute: 2})
+	limiter.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		release, _, ok := limiter.acquire("team")
+		if !ok {
+			t.Fatalf("Expected chat %d to be accepted", i)
+		}
+		release()
+		now = now.Add(10 * time.Second)
+	}
+
+	_, retryAfter, ok := limiter.acquire("team")
+	if ok {
+		t.Fatal("Expected third chat within a minute to be rejected")
+	}
+	if retryAfter != 40*time.Second {
+		t.Errorf("Expected retry-after 40s, got %v", retryAfter)
+	}
+
+	now = now.Add(retryAfter)
+	if _, _, ok := limiter.acquire("team"); !ok {
+		t.Error("Expected chat to be accepted once the window passes")
+	}
+}
+
+func TestChatLimiter_ForgetsIdleTeams(t *testing.T) {
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	limiter := newChatLimiter(ChatLimits{RequestsPerMinutePerTeam: 2})
+	limiter.now = func() time.Time { return now }
+
+	for i := 0; i < 100; i++ {
+		release, _, ok := limiter.acquire(fmt.Sprintf("made-up-%d", i))
+		if !ok {
+			t.Fatalf("Expected chat %d to be accepted", i)
+		}
+		release()
+	}
+
+	now = now.Add(time.Minute)
+	release, _, ok := limiter.acquire("team")
+	if !ok {
+		t.Fatal("Expected chat to be accepted")
+	}
+	release()
+
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+	if len(limiter.teamRecent) != 1 || len(limiter.teamActive) != 0 {
+		t.Errorf("Expected only the latest team tracked, got %d recent and %d active", len(limiter.teamRecent), len(limiter.teamActive))
+	}
+}
+
+func TestHandleChat_RateLimited(t *testing.T) {
+	s := newTestServer(t)
+	s.SetChatLimits(ChatLimits{MaxConcurrentPerTeam: 1})
+
+	// Occupy the team's only slot
+	release, _, _ := s.chats.acquire("alpha")
+	defer release()
+
+	rec := serve(s, "POST", "/api/chat", `{"team":"alpha","message":"hello"}`)
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("Expected 429, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec.Header().Get("Retry-After") == "" {
+		t.Error("Expected Retry-After header")
+	}
+	if !strings.Contains(rec.Body.String(), `"code":"RATE_LIMITED"`) {
+		t.Errorf("Expected RATE_LIMITED code, got %s", rec.Body.String())
+	}
+}
@@ -0,0 +1,57 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Bounds for list endpoints' ?limit=
+const (
+	defaultActivityLimit     = 50
+	maxActivityLimit         = 1000
+	defaultConversationLimit = 10
+	maxConversationLimit     = 100
+	defaultApprovalLimit     = 50
+	maxApprovalLimit         = 500
+	defaultAuditLimit        = 100
+	maxAuditLimit            = 10000
+	defaultLogLimit          = 100
+	maxLogLimit              = 10000
+)
+
+// parseIntParam reads a positive integer query parameter. A missing value
+// gives def and values above max are capped at max; anything that isn't an
+// integer of at least 1 is an error the caller should report as a 400.
+func parseIntParam(r *http.Request, name string, def, max int) (int, error) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return def, nil
+	}
+
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("%s must be a whole number from 1 to %d", name, max)
+	}
+	if n > max {
+		n = max
+	}
+	return n, nil
+}
+
+// parseTimeParam reads a time query parameter, either RFC 3339 or a
+// duration before now such as "24h". A missing value gives the zero time.
+func parseTimeParam(r *http.Request, name string) (time.Time, error) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
+	}
+	if d, err := time.ParseDuration(raw); err == nil && d >= 0 {
+		return time.Now().Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("%s must be an RFC 3339 time or a duration such as 24h", name)
+}
@@ -0,0 +1,37 @@
+package api
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseIntParam(t *testing.T) {
+	tests := []struct {
+		query     string
+		want      int
+		wantError bool
+	}{
+		{query: "", want: 10},
+		{query: "?limit=5", want: 5},
+		{query: "?limit=1", want: 1},
+		{query: "?limit=500", want: 100},
+		{query: "?limit=0", wantError: true},
+		{query: "?limit=-3", wantError: true},
+		{query: "?limit=ten", wantError: true},
+		{query: "?limit=2.5", wantError: true},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", "/api/things"+tt.query, nil)
+		got, err := parseIntParam(r, "limit", 10, 100)
+		if tt.wantError {
+			if err == nil {
+				t.Errorf("%q: expected an error, got %d", tt.query, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("%q: expected %d, got %d (%v)", tt.query, tt.want, got, err)
+		}
+	}
+}
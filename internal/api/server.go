@@ -4,18 +4,29 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
 	"net/http"
+	"net/url"
 	"os"
+	"path"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/arcslash/ugudu/internal/config"
 	"github.com/arcslash/ugudu/internal/logger"
 	"github.com/arcslash/ugudu/internal/manager"
+	"github.com/arcslash/ugudu/internal/provider"
 	"github.com/arcslash/ugudu/internal/team"
+	"github.com/arcslash/ugudu/internal/version"
 	"github.com/arcslash/ugudu/internal/workspace"
+	"github.com/google/uuid"
+	"gopkg.in/yaml.v3"
 )
 
 // Server is the HTTP API server
@@ -25,6 +36,10 @@ type Server struct {
 	server  *http.Server
 	mux     *http.ServeMux
 	wsHub   *WSHub
+	chats   *chatLimiter
+
+	started        time.Time
+	activeRequests atomic.Int64
 }
 
 // NewServer creates a new API server
@@ -34,38 +49,53 @@ func NewServer(mgr *manager.Manager, log *logger.Logger) *Server {
 		logger:  log,
 		mux:     http.NewServeMux(),
 		wsHub:   NewWSHub(),
+		chats:   newChatLimiter(DefaultChatLimits()),
+		started: time.Now(),
 	}
 	go s.wsHub.Run()
 	s.setupRoutes()
 
 	// Wire up activity callback to broadcast via WebSocket
-	mgr.SetActivityCallback(func(teamName, memberID, activityType, message string) {
+	mgr.SetActivityCallback(func(teamName, memberID, activityType, message, requestID string, extra map[string]interface{}) {
 		if activityType == "status_change" {
 			// Broadcast as member status update
 			s.wsHub.BroadcastMemberStatus(teamName, memberID, message, "")
 		} else {
 			// Broadcast as activity
-			s.wsHub.BroadcastActivity(teamName, memberID, message, map[string]string{
-				"type": activityType,
-			})
+			data := map[string]interface{}{"type": activityType}
+			for k, v := range extra {
+				data[k] = v
+			}
+			if requestID != "" {
+				data["request_id"] = requestID
+			}
+			s.wsHub.BroadcastActivity(teamName, memberID, message, data)
 		}
 	})
 
 	return s
 }
 
+// SetChatLimits replaces the limits applied to /api/chat
+func (s *Server) SetChatLimits(limits ChatLimits) {
+	s.chats = newChatLimiter(limits)
+}
+
 func (s *Server) setupRoutes() {
 	// CORS middleware wrapper
 	cors := func(h http.HandlerFunc) http.HandlerFunc {
 		return func(w http.ResponseWriter, r *http.Request) {
 			w.Header().Set("Access-Control-Allow-Origin", "*")
-			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Request-ID")
+			w.Header().Set("Access-Control-Expose-Headers", "X-Request-ID")
 
 			if r.Method == "OPTIONS" {
 				w.WriteHeader(http.StatusOK)
 				return
 			}
+			s.activeRequests.Add(1)
+			defer s.activeRequests.Add(-1)
 			h(w, r)
 		}
 	}
@@ -75,6 +105,7 @@ func (s *Server) setupRoutes() {
 
 	// Status
 	s.mux.HandleFunc("/api/status", cors(s.handleStatus))
+	s.mux.HandleFunc("/api/version", cors(s.handleVersion))
 
 	// Providers
 	s.mux.HandleFunc("/api/providers", cors(s.handleProviders))
@@ -93,6 +124,7 @@ func (s *Server) setupRoutes() {
 
 	// Chat/Ask
 	s.mux.HandleFunc("/api/chat", cors(s.handleChat))
+	s.mux.HandleFunc("/api/chat/bulk", cors(s.handleChatBulk))
 
 	// Projects
 	s.mux.HandleFunc("/api/projects", cors(s.handleProjects))
@@ -101,6 +133,12 @@ func (s *Server) setupRoutes() {
 	// Conversations
 	s.mux.HandleFunc("/api/conversations/", cors(s.handleConversation))
 
+	// Audit log (read-only)
+	s.mux.HandleFunc("/api/audit", cors(s.handleAudit))
+
+	// The daemon's recent log lines (read-only)
+	s.mux.HandleFunc("/api/logs", cors(s.handleLogs))
+
 	// Serve static files (images)
 	s.mux.HandleFunc("/api/static/", s.handleStatic)
 
@@ -142,10 +180,23 @@ func (s *Server) Handler() http.Handler {
 // ============================================================================
 
 func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
-	s.json(w, http.StatusOK, map[string]interface{}{
-		"status": "ok",
-		"time":   time.Now().Format(time.RFC3339),
-	})
+	// Shallow check stays fast for liveness probes
+	if deep := r.URL.Query().Get("deep"); deep == "" || deep == "0" || deep == "false" {
+		s.json(w, http.StatusOK, map[string]interface{}{
+			"status": "ok",
+			"time":   time.Now().Format(time.RFC3339),
+		})
+		return
+	}
+
+	health := s.manager.CheckHealth(r.Context())
+	health["time"] = time.Now().Format(time.RFC3339)
+
+	status := http.StatusOK
+	if health["status"] == manager.HealthUnhealthy {
+		status = http.StatusServiceUnavailable
+	}
+	s.json(w, status, health)
 }
 
 func (s *Server) handleStatic(w http.ResponseWriter, r *http.Request) {
@@ -172,20 +223,44 @@ func (s *Server) handleStatic(w http.ResponseWriter, r *http.Request) {
 	http.NotFound(w, r)
 }
 
+// handleVersion reports the daemon's build, so clients can tell when they
+// were built from something else
+func (s *Server) handleVersion(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		s.error(w, http.StatusMethodNotAllowed, "GET required")
+		return
+	}
+	s.json(w, http.StatusOK, version.Get())
+}
+
 func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
 	status := s.manager.Status()
+	status["chats"] = s.chats.stats()
+	status["daemon"] = s.daemonStatus()
 	s.json(w, http.StatusOK, status)
 }
 
 func (s *Server) handleProviders(w http.ResponseWriter, r *http.Request) {
-	providers := s.manager.Providers().List()
+	registry := s.manager.Providers()
+	providers := registry.List()
 
 	result := make([]map[string]interface{}, 0, len(providers))
 	for _, p := range providers {
-		result = append(result, map[string]interface{}{
-			"id":   p.ID(),
-			"name": p.Name(),
-		})
+		health := registry.Health(p.ID())
+		entry := map[string]interface{}{
+			"id":     p.ID(),
+			"name":   p.Name(),
+			"status": health.Status,
+		}
+		if health.Reason != "" {
+			entry["status_reason"] = health.Reason
+		}
+		if c, ok := p.(provider.ConcurrencyReporter); ok {
+			if inUse, limit := c.Concurrency(); limit > 0 {
+				entry["concurrency"] = map[string]int{"in_use": inUse, "limit": limit}
+			}
+		}
+		result = append(result, entry)
 	}
 
 	s.json(w, http.StatusOK, map[string]interface{}{
@@ -205,7 +280,7 @@ func (s *Server) handleProviderByID(w http.ResponseWriter, r *http.Request) {
 
 	p, err := s.manager.Providers().Get(providerID)
 	if err != nil {
-		s.error(w, http.StatusNotFound, "provider not found")
+		s.notFound(w, "provider", "provider not found")
 		return
 	}
 
@@ -218,7 +293,7 @@ func (s *Server) handleProviderByID(w http.ResponseWriter, r *http.Request) {
 
 			models, err := p.ListModels(ctx)
 			if err != nil {
-				s.error(w, http.StatusInternalServerError, err.Error())
+				s.writeError(w, http.StatusBadGateway, CodeProviderError, err.Error(), nil)
 				return
 			}
 			s.json(w, http.StatusOK, map[string]interface{}{"models": models})
@@ -228,22 +303,47 @@ func (s *Server) handleProviderByID(w http.ResponseWriter, r *http.Request) {
 			ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
 			defer cancel()
 
-			if err := p.Ping(ctx); err != nil {
+			// Rechecking right away re-enables a provider that has recovered
+			_, err := s.manager.Providers().Check(ctx, p.ID())
+			if err != nil {
+				apiErr := APIError{Code: CodeProviderError, Message: err.Error()}
+				if provider.IsAuthError(err) {
+					apiErr.Message = "bad credentials: " + apiErr.Message
+					apiErr.Details = map[string]interface{}{"reason": "bad_credentials"}
+				}
 				s.json(w, http.StatusOK, map[string]interface{}{
 					"status": "error",
-					"error":  err.Error(),
+					"error":  apiErr,
 				})
 				return
 			}
 			s.json(w, http.StatusOK, map[string]interface{}{"status": "ok"})
 			return
+
+		case "stats":
+			stats, err := s.manager.Providers().Stats(p.ID())
+			if err != nil {
+				s.notFound(w, "provider", err.Error())
+				return
+			}
+			s.json(w, http.StatusOK, stats)
+			return
 		}
 	}
 
-	s.json(w, http.StatusOK, map[string]interface{}{
-		"id":   p.ID(),
-		"name": p.Name(),
-	})
+	health := s.manager.Providers().Health(p.ID())
+	result := map[string]interface{}{
+		"id":            p.ID(),
+		"name":          p.Name(),
+		"status":        health.Status,
+		"status_reason": health.Reason,
+	}
+	if c, ok := p.(provider.ConcurrencyReporter); ok {
+		if inUse, limit := c.Concurrency(); limit > 0 {
+			result["concurrency"] = map[string]int{"in_use": inUse, "limit": limit}
+		}
+	}
+	s.json(w, http.StatusOK, result)
 }
 
 func (s *Server) handleSpecs(w http.ResponseWriter, r *http.Request) {
@@ -360,12 +460,14 @@ func (s *Server) handleSpecs(w http.ResponseWriter, r *http.Request) {
 			yaml.WriteString("\n")
 		}
 
-		// Write to file
+		// Write to file, keeping the previous content in the history
 		specPath := filepath.Join(specsDir, req.Name+".yaml")
+		s.versionSpec(req.Name, specPath)
 		if err := os.WriteFile(specPath, []byte(yaml.String()), 0644); err != nil {
 			s.error(w, http.StatusInternalServerError, "failed to save spec: "+err.Error())
 			return
 		}
+		s.versionSpec(req.Name, specPath)
 
 		s.json(w, http.StatusOK, map[string]interface{}{"status": "ok", "path": specPath})
 
@@ -381,7 +483,9 @@ func (s *Server) handleSpecs(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Server) handleSpecByName(w http.ResponseWriter, r *http.Request) {
-	name := strings.TrimPrefix(r.URL.Path, "/api/specs/")
+	path := strings.TrimPrefix(r.URL.Path, "/api/specs/")
+	parts := strings.Split(path, "/")
+	name := parts[0]
 	if name == "" {
 		s.error(w, http.StatusBadRequest, "spec name required")
 		return
@@ -389,48 +493,34 @@ func (s *Server) handleSpecByName(w http.ResponseWriter, r *http.Request) {
 
 	specPath := filepath.Join(config.SpecsDir(), name+".yaml")
 
+	if len(parts) > 1 {
+		switch parts[1] {
+		case "preview":
+			s.handleSpecPreview(w, r, name, specPath)
+			return
+		case "specialists":
+			s.handleSpecSpecialists(w, r, name, specPath)
+			return
+		case "versions":
+			s.handleSpecVersions(w, r, name, specPath, parts[2:])
+			return
+		}
+	}
+
 	switch r.Method {
 	case "GET":
 		spec, err := team.LoadSpec(specPath)
 		if err != nil {
-			s.error(w, http.StatusNotFound, "spec not found")
+			s.notFound(w, "spec", "spec not found")
 			return
 		}
 
-		// Convert to JSON-friendly format
-		roles := make(map[string]interface{})
-		defaultProvider := ""
-		defaultModel := ""
-		for id, role := range spec.Roles {
-			// Track first role's model config as default
-			if defaultProvider == "" && role.Model.Provider != "" {
-				defaultProvider = role.Model.Provider
-				defaultModel = role.Model.Model
-			}
-			roles[id] = map[string]interface{}{
-				"title":    role.Title,
-				"name":     role.Name,
-				"names":    role.Names,
-				"count":    role.Count,
-				"persona":  role.Persona,
-				"provider": role.Model.Provider,
-				"model":    role.Model.Model,
-			}
-		}
-
-		s.json(w, http.StatusOK, map[string]interface{}{
-			"name":          spec.Metadata.Name,
-			"description":   spec.Metadata.Description,
-			"roles":         roles,
-			"client_facing": spec.ClientFacing,
-			"provider":      defaultProvider,
-			"model":         defaultModel,
-		})
+		s.json(w, http.StatusOK, team.NewSpecView(spec))
 
 	case "DELETE":
 		if err := os.Remove(specPath); err != nil {
 			if os.IsNotExist(err) {
-				s.error(w, http.StatusNotFound, "spec not found")
+				s.notFound(w, "spec", "spec not found")
 			} else {
 				s.error(w, http.StatusInternalServerError, "failed to delete: "+err.Error())
 			}
@@ -444,6 +534,170 @@ func (s *Server) handleSpecByName(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// handleSpecPreview returns the effective spec after extends/includes are resolved
+func (s *Server) handleSpecPreview(w http.ResponseWriter, r *http.Request, name, specPath string) {
+	if r.Method != "GET" {
+		s.error(w, http.StatusMethodNotAllowed, "GET required")
+		return
+	}
+
+	spec, err := team.LoadSpec(specPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			s.notFound(w, "spec", "spec not found")
+		} else {
+			s.error(w, http.StatusBadRequest, err.Error())
+		}
+		return
+	}
+
+	data, err := yaml.Marshal(spec)
+	if err != nil {
+		s.error(w, http.StatusInternalServerError, "failed to render spec: "+err.Error())
+		return
+	}
+
+	s.json(w, http.StatusOK, map[string]interface{}{
+		"name": name,
+		"yaml": string(data),
+	})
+}
+
+// handleSpecSpecialists adds a specialist role template to a spec
+func (s *Server) handleSpecSpecialists(w http.ResponseWriter, r *http.Request, name, specPath string) {
+	if r.Method != "POST" {
+		s.error(w, http.StatusMethodNotAllowed, "POST required")
+		return
+	}
+
+	var req struct {
+		Specialist string `json:"specialist"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.Specialist == "" {
+		s.error(w, http.StatusBadRequest, "specialist is required")
+		return
+	}
+	if err := team.ValidateSpecialistName(req.Specialist); err != nil {
+		s.error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if _, err := os.Stat(specPath); os.IsNotExist(err) {
+		s.notFound(w, "spec", "spec not found")
+		return
+	}
+
+	s.versionSpec(name, specPath)
+	result, err := team.AddSpecialist(specPath, req.Specialist)
+	if err != nil {
+		s.error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	s.versionSpec(name, specPath)
+
+	s.json(w, http.StatusOK, map[string]interface{}{
+		"status":  "added",
+		"spec":    name,
+		"role_id": result.RoleID,
+		"title":   result.Title,
+		"lead":    result.Lead,
+	})
+	s.wsHub.BroadcastSpecUpdate("updated", name, nil)
+}
+
+// handleSpecVersions serves a spec's version history:
+//
+//	GET  /api/specs/{name}/versions              list versions
+//	GET  /api/specs/{name}/versions/{n}          get one version's content
+//	POST /api/specs/{name}/versions/{n}/restore  make version n current
+func (s *Server) handleSpecVersions(w http.ResponseWriter, r *http.Request, name, specPath string, rest []string) {
+	store := s.manager.Store()
+
+	if len(rest) == 0 || rest[0] == "" {
+		if r.Method != "GET" {
+			s.error(w, http.StatusMethodNotAllowed, "GET required")
+			return
+		}
+		versions, err := store.ListSpecVersions(name)
+		if err != nil {
+			s.fail(w, err)
+			return
+		}
+		if versions == nil {
+			versions = []manager.SpecVersion{}
+		}
+		s.json(w, http.StatusOK, map[string]interface{}{
+			"spec":     name,
+			"versions": versions,
+		})
+		return
+	}
+
+	version, err := strconv.Atoi(rest[0])
+	if err != nil || version < 1 {
+		s.error(w, http.StatusBadRequest, "invalid version: "+rest[0])
+		return
+	}
+	v, err := store.GetSpecVersion(name, version)
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+	if v == nil {
+		s.notFound(w, "spec_version", fmt.Sprintf("version %d of spec %s not found", version, name))
+		return
+	}
+
+	if len(rest) == 1 {
+		if r.Method != "GET" {
+			s.error(w, http.StatusMethodNotAllowed, "GET required")
+			return
+		}
+		s.json(w, http.StatusOK, v)
+		return
+	}
+
+	if rest[1] != "restore" || r.Method != "POST" {
+		s.error(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	// Keep whatever is there now, then write the old content back
+	s.versionSpec(name, specPath)
+	if err := os.WriteFile(specPath, []byte(v.Content), 0644); err != nil {
+		s.error(w, http.StatusInternalServerError, "failed to restore spec: "+err.Error())
+		return
+	}
+	current, err := s.manager.RecordSpecVersion(name, v.Content)
+	if err != nil {
+		s.logger.Warn("failed to record spec version", "spec", name, "error", err)
+	}
+
+	s.json(w, http.StatusOK, map[string]interface{}{
+		"status":           "restored",
+		"spec":             name,
+		"restored_version": version,
+		"version":          current,
+	})
+	s.wsHub.BroadcastSpecUpdate("updated", name, nil)
+}
+
+// versionSpec records the spec file's current content in its history.
+// A missing file is skipped, and content matching the latest version isn't duplicated.
+func (s *Server) versionSpec(name, specPath string) {
+	data, err := os.ReadFile(specPath)
+	if err != nil {
+		return
+	}
+	if _, err := s.manager.RecordSpecVersion(name, string(data)); err != nil {
+		s.logger.Warn("failed to record spec version", "spec", name, "error", err)
+	}
+}
+
 func (s *Server) handleTeams(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 	case "GET":
@@ -459,6 +713,14 @@ func (s *Server) handleTeams(w http.ResponseWriter, r *http.Request) {
 			Name     string `json:"name"`      // Team instance name
 			Spec     string `json:"spec"`      // Spec name (will look in ~/.ugudu/specs/)
 			SpecPath string `json:"spec_path"` // Direct path (legacy)
+
+			// Background the members start with: text, and/or the client
+			// transcript of an earlier conversation
+			Seed             string `json:"seed"`
+			SeedConversation string `json:"seed_conversation"`
+
+			// Replace a team of the same name rather than failing with 409
+			Force bool `json:"force"`
 		}
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 			s.error(w, http.StatusBadRequest, "invalid request body")
@@ -476,9 +738,23 @@ func (s *Server) handleTeams(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		t, err := s.manager.CreateTeamWithName(req.Name, specPath)
+		seed := req.Seed
+		if req.SeedConversation != "" {
+			transcript, err := s.manager.ConversationTranscript(req.SeedConversation)
+			if err != nil {
+				s.fail(w, err)
+				return
+			}
+			seed = strings.TrimSpace(seed + "\n\n" + transcript)
+		}
+
+		create := s.manager.CreateSeededTeam
+		if req.Force {
+			create = s.manager.ReplaceTeam
+		}
+		t, err := create(req.Name, specPath, seed)
 		if err != nil {
-			s.error(w, http.StatusInternalServerError, err.Error())
+			s.fail(w, err)
 			return
 		}
 
@@ -515,7 +791,7 @@ func (s *Server) handleTeamByName(w http.ResponseWriter, r *http.Request) {
 				return
 			}
 			if err := s.manager.StartTeam(teamName); err != nil {
-				s.error(w, http.StatusInternalServerError, err.Error())
+				s.fail(w, err)
 				return
 			}
 			s.json(w, http.StatusOK, map[string]interface{}{"status": "started"})
@@ -528,17 +804,64 @@ func (s *Server) handleTeamByName(w http.ResponseWriter, r *http.Request) {
 				return
 			}
 			if err := s.manager.StopTeam(teamName); err != nil {
-				s.error(w, http.StatusInternalServerError, err.Error())
+				s.fail(w, err)
 				return
 			}
 			s.json(w, http.StatusOK, map[string]interface{}{"status": "stopped"})
 			s.wsHub.BroadcastTeamUpdate("stopped", teamName, nil)
 			return
 
+		case "restart":
+			if r.Method != "POST" {
+				s.error(w, http.StatusMethodNotAllowed, "POST required")
+				return
+			}
+			if err := s.manager.RestartTeam(teamName); err != nil {
+				s.fail(w, err)
+				return
+			}
+			s.json(w, http.StatusOK, map[string]interface{}{"status": "restarted"})
+			s.wsHub.BroadcastTeamUpdate("restarted", teamName, nil)
+			return
+
+		case "pause":
+			if r.Method != "POST" {
+				s.error(w, http.StatusMethodNotAllowed, "POST required")
+				return
+			}
+			if err := s.manager.PauseTeam(teamName); err != nil {
+				s.fail(w, err)
+				return
+			}
+			s.json(w, http.StatusOK, map[string]interface{}{"status": "paused"})
+			s.wsHub.BroadcastTeamUpdate("paused", teamName, nil)
+			return
+
+		case "resume":
+			if r.Method != "POST" {
+				s.error(w, http.StatusMethodNotAllowed, "POST required")
+				return
+			}
+			if err := s.manager.ResumeTeam(teamName); err != nil {
+				s.fail(w, err)
+				return
+			}
+			s.json(w, http.StatusOK, map[string]interface{}{"status": "resumed"})
+			s.wsHub.BroadcastTeamUpdate("resumed", teamName, nil)
+			return
+
 		case "members":
+			if len(parts) == 4 && parts[3] == "context" {
+				s.handleMemberContext(w, r, teamName, parts[2])
+				return
+			}
+			if len(parts) == 4 && parts[3] == "prompt" {
+				s.handleMemberPrompt(w, r, teamName, parts[2])
+				return
+			}
 			t, err := s.manager.GetTeam(teamName)
 			if err != nil {
-				s.error(w, http.StatusNotFound, "team not found")
+				s.notFound(w, "team", "team not found")
 				return
 			}
 			members := make([]map[string]interface{}, 0)
@@ -551,12 +874,19 @@ func (s *Server) handleTeamByName(w http.ResponseWriter, r *http.Request) {
 						break
 					}
 				}
+				currentTask := ""
+				if task := m.GetCurrentTask(); task != nil {
+					currentTask = task.Content
+				}
 				members = append(members, map[string]interface{}{
 					"id":            m.ID,
 					"name":          m.Name,
 					"role":          m.RoleName,
 					"title":         m.Role.Title,
 					"status":        m.GetStatus(),
+					"status_since":  m.StatusSince(),
+					"task":          currentTask,
+					"progress":      m.Progress(),
 					"visibility":    m.Role.Visibility,
 					"client_facing": isClientFacing,
 					"provider":      m.Role.Model.Provider,
@@ -573,6 +903,38 @@ func (s *Server) handleTeamByName(w http.ResponseWriter, r *http.Request) {
 		case "token-mode":
 			s.handleTeamTokenMode(w, r, teamName)
 			return
+
+		case "continue":
+			s.handleTeamContinue(w, r, teamName)
+			return
+
+		case "questions":
+			s.handleTeamQuestions(w, r, teamName, parts[2:])
+			return
+
+		case "approvals":
+			s.handleTeamApprovals(w, r, teamName, parts[2:])
+			return
+
+		case "performance":
+			s.handleTeamPerformance(w, r, teamName)
+			return
+
+		case "bench":
+			s.handleTeamBench(w, r, teamName)
+			return
+
+		case "project":
+			s.handleTeamProject(w, r, teamName, parts[2:])
+			return
+
+		case "diff":
+			s.handleTeamDiff(w, r, teamName, parts[2:])
+			return
+
+		case "spec":
+			s.handleTeamSpec(w, r, teamName)
+			return
 		}
 	}
 
@@ -581,14 +943,14 @@ func (s *Server) handleTeamByName(w http.ResponseWriter, r *http.Request) {
 	case "GET":
 		t, err := s.manager.GetTeam(teamName)
 		if err != nil {
-			s.error(w, http.StatusNotFound, "team not found")
+			s.notFound(w, "team", "team not found")
 			return
 		}
 		s.json(w, http.StatusOK, t.Status())
 
 	case "DELETE":
 		if err := s.manager.DeleteTeam(teamName); err != nil {
-			s.error(w, http.StatusInternalServerError, err.Error())
+			s.fail(w, err)
 			return
 		}
 		s.json(w, http.StatusOK, map[string]interface{}{"status": "deleted"})
@@ -599,6 +961,67 @@ func (s *Server) handleTeamByName(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// handleTeamDiff compares a team's spec with another team's:
+// GET /api/teams/{name}/diff/{other}
+func (s *Server) handleTeamDiff(w http.ResponseWriter, r *http.Request, teamName string, rest []string) {
+	if r.Method != "GET" {
+		s.error(w, http.StatusMethodNotAllowed, "GET required")
+		return
+	}
+	if len(rest) != 1 || rest[0] == "" {
+		s.error(w, http.StatusBadRequest, "team to compare with required")
+		return
+	}
+
+	a, err := s.manager.GetTeam(teamName)
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+	b, err := s.manager.GetTeam(rest[0])
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+	s.json(w, http.StatusOK, team.DiffSpecs(a.ExportSpec(), b.ExportSpec()))
+}
+
+// handleTeamSpec returns the spec a running team was created from as YAML,
+// with inheritance resolved and defaults applied. The spec file may have
+// changed since. With ?global_prompt=false the daemon's global prompt is
+// left out, for saving as a new spec. GET /api/teams/{name}/spec
+func (s *Server) handleTeamSpec(w http.ResponseWriter, r *http.Request, teamName string) {
+	if r.Method != "GET" {
+		s.error(w, http.StatusMethodNotAllowed, "GET required")
+		return
+	}
+
+	var spec *team.TeamSpec
+	if global, err := strconv.ParseBool(r.URL.Query().Get("global_prompt")); err == nil && !global {
+		exported, err := s.manager.ExportTeamSpec(teamName)
+		if err != nil {
+			s.fail(w, err)
+			return
+		}
+		spec = exported
+	} else {
+		t, err := s.manager.GetTeam(teamName)
+		if err != nil {
+			s.fail(w, err)
+			return
+		}
+		spec = t.ExportSpec()
+	}
+	data, err := yaml.Marshal(spec)
+	if err != nil {
+		s.error(w, http.StatusInternalServerError, "failed to encode spec: "+err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/yaml")
+	w.Write(data)
+}
+
 func (s *Server) handleChat(w http.ResponseWriter, r *http.Request) {
 	if r.Method != "POST" {
 		s.error(w, http.StatusMethodNotAllowed, "POST required")
@@ -606,9 +1029,15 @@ func (s *Server) handleChat(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req struct {
-		Team    string `json:"team"`
-		Message string `json:"message"`
-		To      string `json:"to,omitempty"` // Optional: specific role
+		Team      string `json:"team"`
+		Message   string `json:"message"`
+		To        string `json:"to,omitempty"`        // Optional: specific role
+		Verbosity string `json:"verbosity,omitempty"` // quiet, summary (default) or full
+		Stream    bool   `json:"stream,omitempty"`    // Send responses as NDJSON lines as they arrive
+		NoTools   bool   `json:"no_tools,omitempty"`  // Answer without offering the model any tools
+
+		// How long to wait before returning a continuation token (max 600)
+		TimeoutSeconds int `json:"timeout_seconds,omitempty"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -621,6 +1050,27 @@ func (s *Server) handleChat(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	verbosity, ok := parseVerbosity(req.Verbosity)
+	if !ok {
+		s.error(w, http.StatusBadRequest, "verbosity must be quiet, summary or full")
+		return
+	}
+
+	// Released on every return path, including timeouts
+	release, retryAfter, acquired := s.chats.acquire(req.Team)
+	if !acquired {
+		seconds := int(math.Ceil(retryAfter.Seconds()))
+		w.Header().Set("Retry-After", strconv.Itoa(seconds))
+		s.writeError(w, http.StatusTooManyRequests, CodeRateLimited, "too many chat requests, retry later", map[string]interface{}{
+			"retry_after_seconds": seconds,
+		})
+		return
+	}
+	defer release()
+
+	requestID := chatRequestID(w, r)
+	s.logger.Debug("chat request", "team", req.Team, "to", req.To, "request_id", requestID)
+
 	// Start team if not running
 	_ = s.manager.StartTeam(req.Team)
 
@@ -644,13 +1094,18 @@ func (s *Server) handleChat(w http.ResponseWriter, r *http.Request) {
 	// Broadcast the user's message so all UI instances see it
 	s.wsHub.BroadcastChat(req.Team, targetRole, "user", "You", req.Message)
 
+	opts := []team.AskOption{team.WithVerbosity(verbosity), team.WithRequestID(requestID)}
+	if req.NoTools {
+		opts = append(opts, team.WithoutTools())
+	}
+
 	var respChan <-chan team.Message
 	var err error
 
 	if req.To != "" {
-		respChan, err = s.manager.AskMember(req.Team, req.To, req.Message)
+		respChan, err = s.manager.AskMember(req.Team, req.To, req.Message, opts...)
 	} else {
-		respChan, err = s.manager.Ask(req.Team, req.Message)
+		respChan, err = s.manager.Ask(req.Team, req.Message, opts...)
 	}
 
 	if err != nil {
@@ -658,69 +1113,255 @@ func (s *Server) handleChat(w http.ResponseWriter, r *http.Request) {
 		if targetRole != "" {
 			s.wsHub.BroadcastMemberStatus(req.Team, targetRole, "idle", "")
 		}
-		s.error(w, http.StatusInternalServerError, err.Error())
+		s.fail(w, err)
 		return
 	}
 
-	// Collect responses (with timeout) - 10 minutes for complex multi-agent tasks with tools
-	ctx, cancel := context.WithTimeout(r.Context(), 600*time.Second)
-	defer cancel()
+	s.relayResponses(w, r, t, relay{
+		team:         req.Team,
+		to:           req.To,
+		activeMember: targetRole,
+		stream:       req.Stream,
+		timeout:      chatTimeout(req.TimeoutSeconds),
+	}, respChan)
+}
 
-	var responses []map[string]interface{}
-	activeMember := targetRole // Track currently active member
-	for {
-		select {
-		case <-ctx.Done():
-			// Reset all busy members to idle on timeout
-			if activeMember != "" {
-				s.wsHub.BroadcastMemberStatus(req.Team, activeMember, "idle", "")
-			}
-			s.json(w, http.StatusOK, map[string]interface{}{
-				"responses": responses,
-				"timeout":   true,
-			})
-			return
+// parseVerbosity reads a chat request's verbosity, defaulting to summary
+func parseVerbosity(v string) (team.Verbosity, bool) {
+	switch team.Verbosity(v) {
+	case "", team.VerbositySummary:
+		return team.VerbositySummary, true
+	case team.VerbosityQuiet, team.VerbosityFull:
+		return team.Verbosity(v), true
+	}
+	return "", false
+}
 
-		case msg, ok := <-respChan:
-			if !ok {
+// maxChatTimeout is the longest a chat request waits for the team - 10
+// minutes for complex multi-agent tasks with tools
+const maxChatTimeout = 600 * time.Second
+
+// chatTimeout returns the wait for a request asking for the given number of
+// seconds, capped at maxChatTimeout
+func chatTimeout(seconds int) time.Duration {
+	if seconds <= 0 || time.Duration(seconds)*time.Second > maxChatTimeout {
+		return maxChatTimeout
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// maxRequestIDLength bounds client-supplied request IDs
+const maxRequestIDLength = 128
+
+// chatRequestID returns the request ID for a chat: the client's X-Request-ID
+// if it sent a usable one, otherwise a new one. The ID is echoed in the
+// response header and tags every log line and activity event the chat causes.
+func chatRequestID(w http.ResponseWriter, r *http.Request) string {
+	id := strings.TrimSpace(r.Header.Get("X-Request-ID"))
+	if id == "" || len(id) > maxRequestIDLength || strings.ContainsAny(id, " \t\r\n") {
+		id = uuid.New().String()
+	}
+	w.Header().Set("X-Request-ID", id)
+	return id
+}
+
+// relay describes how to pass a team's responses back to the client
+type relay struct {
+	team         string
+	to           string // Role the ask was sent to, empty for the client-facing member
+	activeMember string // Member currently working, for status broadcasts
+	stream       bool
+	timeout      time.Duration
+	restarted    bool // A continuation that had to re-ask the team
+}
+
+// relayResponses writes the responses from an ask, as one JSON object or as
+// NDJSON lines. If the wait times out or the client goes away the ask is
+// suspended on the team and the reply carries a continuation token.
+func (s *Server) relayResponses(w http.ResponseWriter, r *http.Request, t *team.Team, rl relay, respChan <-chan team.Message) {
+	ctx, cancel := context.WithTimeout(r.Context(), rl.timeout)
+	defer cancel()
+
+	var stream *json.Encoder
+	flusher, _ := w.(http.Flusher)
+	if rl.stream {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.WriteHeader(http.StatusOK)
+		stream = json.NewEncoder(w)
+	}
+
+	// finish writes the final reply; extra is merged into it
+	var responses []map[string]interface{}
+	finish := func(extra map[string]interface{}) {
+		if rl.restarted {
+			extra["restarted"] = true
+		}
+		if stream != nil {
+			extra["done"] = true
+			stream.Encode(extra)
+			return
+		}
+		extra["responses"] = responses
+		s.json(w, http.StatusOK, extra)
+	}
+
+	activeMember := rl.activeMember
+	for {
+		select {
+		case <-ctx.Done():
+			// Reset all busy members to idle on timeout
+			if activeMember != "" {
+				s.wsHub.BroadcastMemberStatus(rl.team, activeMember, "idle", "")
+			}
+			// The team may still be working; park the ask so it can be continued
+			result := map[string]interface{}{"timeout": true}
+			if t != nil {
+				result["continuation_token"] = t.SuspendAsk(respChan, rl.to)
+			}
+			finish(result)
+			return
+
+		case msg, ok := <-respChan:
+			if !ok {
 				// Channel closed - all done, reset to idle
 				if activeMember != "" {
-					s.wsHub.BroadcastMemberStatus(req.Team, activeMember, "idle", "")
+					s.wsHub.BroadcastMemberStatus(rl.team, activeMember, "idle", "")
 				}
-				s.json(w, http.StatusOK, map[string]interface{}{
-					"responses": responses,
-				})
+				finish(map[string]interface{}{})
 				return
 			}
 
 			content, _ := msg.Content.(string)
-			responses = append(responses, map[string]interface{}{
-				"from":    msg.From,
-				"content": content,
-				"type":    msg.Type,
-			})
+			entry := responseEntry(msg)
+			if stream != nil {
+				stream.Encode(entry)
+				if flusher != nil {
+					flusher.Flush()
+				}
+			} else {
+				responses = append(responses, entry)
+			}
 
 			// Broadcast activity update
-			s.wsHub.BroadcastActivity(req.Team, msg.From, content, nil)
+			s.wsHub.BroadcastActivity(rl.team, msg.From, content, nil)
 
 			// Broadcast agent chat message so all UI instances see it
-			if content != "" && msg.Type != "internal" {
-				s.wsHub.BroadcastChat(req.Team, msg.From, "agent", msg.From, content)
+			if content != "" && msg.Type != team.MsgInternal && msg.Type != team.MsgProgress {
+				s.wsHub.BroadcastChat(rl.team, msg.From, "agent", msg.From, content)
 			}
 
 			// Check for delegation patterns in the message
 			if msg.Type == "delegation" || strings.Contains(content, "delegating to") || strings.Contains(content, "asking") {
 				// Previous member is now idle, new member is busy
 				if activeMember != "" && activeMember != msg.From {
-					s.wsHub.BroadcastMemberStatus(req.Team, activeMember, "idle", "")
+					s.wsHub.BroadcastMemberStatus(rl.team, activeMember, "idle", "")
 				}
-				s.wsHub.BroadcastMemberStatus(req.Team, msg.From, "busy", "Working on delegated task...")
+				s.wsHub.BroadcastMemberStatus(rl.team, msg.From, "busy", "Working on delegated task...")
 				activeMember = msg.From
 			}
 		}
 	}
 }
 
+// responseEntry is how a team message is sent to chat clients
+func responseEntry(msg team.Message) map[string]interface{} {
+	content, _ := msg.Content.(string)
+	entry := map[string]interface{}{
+		"from":    msg.From,
+		"content": content,
+		"type":    msg.Type,
+	}
+	switch msg.Type {
+	case team.MsgInternal:
+		entry["internal"] = true
+	case team.MsgProgress:
+		entry["progress"] = true
+	}
+	if msg.Model != "" {
+		entry["model"] = msg.Model
+		entry["provider"] = msg.Provider
+	}
+	if len(msg.Delegation) > 0 {
+		entry["delegation"] = msg.Delegation
+	}
+	return entry
+}
+
+// continuePrompt re-asks the team when a continuation token is no longer
+// live, e.g. after a daemon restart. The member's persisted context still
+// holds the original request.
+const continuePrompt = "My last request timed out before you finished. Please continue where you left off and report the result."
+
+// handleTeamContinue reattaches to an ask that timed out, streaming or
+// returning whatever the team has produced since
+func (s *Server) handleTeamContinue(w http.ResponseWriter, r *http.Request, teamName string) {
+	if r.Method != "POST" {
+		s.error(w, http.StatusMethodNotAllowed, "POST required")
+		return
+	}
+
+	var req struct {
+		Token          string `json:"token"`
+		To             string `json:"to,omitempty"`        // Role to re-ask if the token has expired
+		Verbosity      string `json:"verbosity,omitempty"` // Used when re-asking
+		Stream         bool   `json:"stream,omitempty"`
+		TimeoutSeconds int    `json:"timeout_seconds,omitempty"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			s.error(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+	}
+
+	t, err := s.manager.GetTeam(teamName)
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+
+	release, retryAfter, ok := s.chats.acquire(teamName)
+	if !ok {
+		seconds := int(math.Ceil(retryAfter.Seconds()))
+		w.Header().Set("Retry-After", strconv.Itoa(seconds))
+		s.writeError(w, http.StatusTooManyRequests, CodeRateLimited, "too many chat requests, retry later", map[string]interface{}{
+			"retry_after_seconds": seconds,
+		})
+		return
+	}
+	defer release()
+
+	requestID := chatRequestID(w, r)
+
+	rl := relay{
+		team:    teamName,
+		stream:  req.Stream,
+		timeout: chatTimeout(req.TimeoutSeconds),
+	}
+
+	respChan, to, resumed := t.ContinueAsk(req.Token)
+	if !resumed {
+		// The original ask is gone; ask the team to pick it back up from
+		// its persisted conversation
+		_ = s.manager.StartTeam(teamName)
+		verbosity := team.VerbositySummary
+		switch team.Verbosity(req.Verbosity) {
+		case team.VerbosityQuiet, team.VerbosityFull:
+			verbosity = team.Verbosity(req.Verbosity)
+		}
+		to = req.To
+		if to != "" {
+			respChan = t.AskMember(to, continuePrompt, team.WithVerbosity(verbosity), team.WithRequestID(requestID))
+		} else {
+			respChan = t.Ask(continuePrompt, team.WithVerbosity(verbosity), team.WithRequestID(requestID))
+		}
+		rl.restarted = true
+	}
+	rl.to = to
+
+	s.relayResponses(w, r, t, rl, respChan)
+}
+
 // ============================================================================
 // Helpers
 // ============================================================================
@@ -731,10 +1372,10 @@ func (s *Server) json(w http.ResponseWriter, status int, data interface{}) {
 	json.NewEncoder(w).Encode(data)
 }
 
+// error sends an error response, deriving the code from the status. Use
+// notFound, fail or writeError when a more specific code applies.
 func (s *Server) error(w http.ResponseWriter, status int, message string) {
-	s.json(w, status, map[string]interface{}{
-		"error": message,
-	})
+	s.writeError(w, status, codeForStatus(status), message, nil)
 }
 
 // APIResponse is a standard response wrapper
@@ -763,7 +1404,7 @@ func (s *Server) handleProjects(w http.ResponseWriter, r *http.Request) {
 	case "GET":
 		projects, err := workspace.ListProjects()
 		if err != nil {
-			s.error(w, http.StatusInternalServerError, err.Error())
+			s.fail(w, err)
 			return
 		}
 		s.json(w, http.StatusOK, map[string]interface{}{"projects": projects})
@@ -787,7 +1428,7 @@ func (s *Server) handleProjects(w http.ResponseWriter, r *http.Request) {
 
 		ws, err := workspace.Init(req.Name, req.SourcePath, req.Team)
 		if err != nil {
-			s.error(w, http.StatusInternalServerError, err.Error())
+			s.fail(w, err)
 			return
 		}
 
@@ -831,6 +1472,9 @@ func (s *Server) handleProjectByName(w http.ResponseWriter, r *http.Request) {
 		case "tasks":
 			s.handleProjectTasks(w, r, projectName)
 			return
+		case "artifacts":
+			s.handleProjectArtifacts(w, r, projectName, parts[2:])
+			return
 		}
 	}
 
@@ -839,14 +1483,14 @@ func (s *Server) handleProjectByName(w http.ResponseWriter, r *http.Request) {
 	case "GET":
 		ws, err := workspace.New(projectName)
 		if err != nil {
-			s.error(w, http.StatusNotFound, "project not found")
+			s.notFound(w, "project", "project not found")
 			return
 		}
 		s.json(w, http.StatusOK, ws.Config)
 
 	case "DELETE":
 		if err := workspace.Delete(projectName); err != nil {
-			s.error(w, http.StatusInternalServerError, err.Error())
+			s.fail(w, err)
 			return
 		}
 		s.json(w, http.StatusOK, map[string]interface{}{"status": "deleted"})
@@ -864,7 +1508,7 @@ func (s *Server) handleProjectStandup(w http.ResponseWriter, r *http.Request, pr
 
 	ws, err := workspace.New(projectName)
 	if err != nil {
-		s.error(w, http.StatusNotFound, "project not found")
+		s.notFound(w, "project", "project not found")
 		return
 	}
 
@@ -874,10 +1518,17 @@ func (s *Server) handleProjectStandup(w http.ResponseWriter, r *http.Request, pr
 		period = workspace.PeriodWeekly
 	}
 
-	generator := workspace.NewStandupGenerator(ws)
+	var opts []workspace.StandupOption
+	if summarize, _ := strconv.ParseBool(r.URL.Query().Get("summarize")); summarize {
+		if p, model, ok := s.manager.Providers().SmallModel(); ok {
+			opts = append(opts, workspace.WithSummarizer(p, model))
+		}
+	}
+
+	generator := workspace.NewStandupGenerator(ws, opts...)
 	report, err := generator.Generate(period)
 	if err != nil {
-		s.error(w, http.StatusInternalServerError, err.Error())
+		s.fail(w, err)
 		return
 	}
 
@@ -892,20 +1543,19 @@ func (s *Server) handleProjectActivity(w http.ResponseWriter, r *http.Request, p
 
 	ws, err := workspace.New(projectName)
 	if err != nil {
-		s.error(w, http.StatusNotFound, "project not found")
+		s.notFound(w, "project", "project not found")
 		return
 	}
 
-	// Build query options from query params
-	opts := workspace.QueryOptions{
-		Limit: 50,
+	limit, err := parseIntParam(r, "limit", defaultActivityLimit, maxActivityLimit)
+	if err != nil {
+		s.error(w, http.StatusBadRequest, err.Error())
+		return
 	}
 
-	if limit := r.URL.Query().Get("limit"); limit != "" {
-		var l int
-		if _, err := json.Number(limit).Int64(); err == nil {
-			opts.Limit = l
-		}
+	// Build query options from query params
+	opts := workspace.QueryOptions{
+		Limit: limit,
 	}
 
 	if since := r.URL.Query().Get("since"); since != "" {
@@ -920,7 +1570,7 @@ func (s *Server) handleProjectActivity(w http.ResponseWriter, r *http.Request, p
 
 	entries, err := workspace.QueryProjectActivity(ws, opts)
 	if err != nil {
-		s.error(w, http.StatusInternalServerError, err.Error())
+		s.fail(w, err)
 		return
 	}
 
@@ -933,7 +1583,7 @@ func (s *Server) handleProjectActivity(w http.ResponseWriter, r *http.Request, p
 func (s *Server) handleProjectTasks(w http.ResponseWriter, r *http.Request, projectName string) {
 	ws, err := workspace.New(projectName)
 	if err != nil {
-		s.error(w, http.StatusNotFound, "project not found")
+		s.notFound(w, "project", "project not found")
 		return
 	}
 
@@ -943,7 +1593,7 @@ func (s *Server) handleProjectTasks(w http.ResponseWriter, r *http.Request, proj
 	case "GET":
 		tasks, err := taskStore.List()
 		if err != nil {
-			s.error(w, http.StatusInternalServerError, err.Error())
+			s.fail(w, err)
 			return
 		}
 
@@ -980,7 +1630,7 @@ func (s *Server) handleProjectTasks(w http.ResponseWriter, r *http.Request, proj
 		}
 
 		if err := taskStore.Create(&task); err != nil {
-			s.error(w, http.StatusInternalServerError, err.Error())
+			s.fail(w, err)
 			return
 		}
 
@@ -991,6 +1641,59 @@ func (s *Server) handleProjectTasks(w http.ResponseWriter, r *http.Request, proj
 	}
 }
 
+// projectArtifact is an artifact with the link to download it
+type projectArtifact struct {
+	workspace.Artifact
+	URL string `json:"url,omitempty"`
+}
+
+// handleProjectArtifacts lists the files a team produced in a project, or
+// downloads one: GET /api/projects/{name}/artifacts[/{id}]
+func (s *Server) handleProjectArtifacts(w http.ResponseWriter, r *http.Request, projectName string, rest []string) {
+	if r.Method != "GET" {
+		s.error(w, http.StatusMethodNotAllowed, "GET required")
+		return
+	}
+
+	ws, err := workspace.New(projectName)
+	if err != nil {
+		s.notFound(w, "project", "project not found")
+		return
+	}
+
+	if len(rest) > 0 && rest[0] != "" {
+		f, artifact, err := ws.OpenArtifact(rest[0])
+		if err != nil {
+			s.fail(w, err)
+			return
+		}
+		defer f.Close()
+
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", path.Base(artifact.Path)))
+		http.ServeContent(w, r, artifact.Path, artifact.UpdatedAt, f)
+		return
+	}
+
+	artifacts, err := ws.Artifacts()
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+
+	entries := make([]projectArtifact, 0, len(artifacts))
+	for _, a := range artifacts {
+		entry := projectArtifact{Artifact: a}
+		if !a.Stale {
+			entry.URL = "/api/projects/" + url.PathEscape(projectName) + "/artifacts/" + a.ID
+		}
+		entries = append(entries, entry)
+	}
+	s.json(w, http.StatusOK, map[string]interface{}{
+		"artifacts": entries,
+		"count":     len(entries),
+	})
+}
+
 // ============================================================================
 // Conversation Handlers
 // ============================================================================
@@ -1005,20 +1708,15 @@ func (s *Server) handleTeamConversations(w http.ResponseWriter, r *http.Request,
 	switch r.Method {
 	case "GET":
 		// List conversations for team
-		limit := 10
-		if l := r.URL.Query().Get("limit"); l != "" {
-			var parsed int
-			if n, err := json.Number(l).Int64(); err == nil {
-				parsed = int(n)
-				if parsed > 0 {
-					limit = parsed
-				}
-			}
+		limit, err := parseIntParam(r, "limit", defaultConversationLimit, maxConversationLimit)
+		if err != nil {
+			s.error(w, http.StatusBadRequest, err.Error())
+			return
 		}
 
 		conversations, err := store.ListConversations(teamName, limit)
 		if err != nil {
-			s.error(w, http.StatusInternalServerError, err.Error())
+			s.fail(w, err)
 			return
 		}
 
@@ -1030,6 +1728,7 @@ func (s *Server) handleTeamConversations(w http.ResponseWriter, r *http.Request,
 				"started_at":      conv.StartedAt.Format(time.RFC3339),
 				"last_message_at": conv.LastMessageAt.Format(time.RFC3339),
 				"status":          conv.Status,
+				"title":           conv.Title,
 			}
 		}
 
@@ -1042,13 +1741,13 @@ func (s *Server) handleTeamConversations(w http.ResponseWriter, r *http.Request,
 		// Close active conversation and clear agent context
 		conv, err := store.GetActiveConversation(teamName)
 		if err != nil {
-			s.error(w, http.StatusInternalServerError, err.Error())
+			s.fail(w, err)
 			return
 		}
 
 		if conv != nil {
 			if err := store.CloseConversation(conv.ID); err != nil {
-				s.error(w, http.StatusInternalServerError, err.Error())
+				s.fail(w, err)
 				return
 			}
 		}
@@ -1073,13 +1772,18 @@ func (s *Server) handleTeamConversations(w http.ResponseWriter, r *http.Request,
 }
 
 func (s *Server) handleTeamTokenMode(w http.ResponseWriter, r *http.Request, teamName string) {
+	if r.Method == "GET" {
+		s.writeTokenMode(w, teamName)
+		return
+	}
 	if r.Method != "POST" {
-		s.error(w, http.StatusMethodNotAllowed, "POST required")
+		s.error(w, http.StatusMethodNotAllowed, "GET or POST required")
 		return
 	}
 
 	var req struct {
-		Mode string `json:"mode"` // "normal", "low", "minimal"
+		Mode    string `json:"mode"`    // "normal", "low", "minimal"
+		Persist bool   `json:"persist"` // Keep the mode across daemon restarts
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -1087,37 +1791,307 @@ func (s *Server) handleTeamTokenMode(w http.ResponseWriter, r *http.Request, tea
 		return
 	}
 
+	// Validate token mode
+	mode := team.TokenMode(req.Mode)
+	switch mode {
+	case "":
+		mode = team.TokenModeNormal
+	case team.TokenModeNormal, team.TokenModeLow, team.TokenModeMinimal:
+	default:
+		s.error(w, http.StatusBadRequest, "invalid token mode (use: normal, low, minimal)")
+		return
+	}
+
+	if err := s.manager.SetTokenMode(teamName, mode, req.Persist); err != nil {
+		s.fail(w, err)
+		return
+	}
+	s.writeTokenMode(w, teamName)
+}
+
+// writeTokenMode responds with a team's token mode, its effective limits and
+// whether the mode is kept across restarts
+func (s *Server) writeTokenMode(w http.ResponseWriter, teamName string) {
 	t, err := s.manager.GetTeam(teamName)
 	if err != nil {
-		s.error(w, http.StatusNotFound, "team not found")
+		s.fail(w, err)
 		return
 	}
 
-	// Validate and set token mode
-	switch req.Mode {
-	case "normal", "":
-		t.SetTokenMode(team.TokenModeNormal)
-	case "low":
-		t.SetTokenMode(team.TokenModeLow)
-	case "minimal":
-		t.SetTokenMode(team.TokenModeMinimal)
-	default:
-		s.error(w, http.StatusBadRequest, "invalid token mode (use: normal, low, minimal)")
-		return
+	settings := t.GetTokenSettings()
+	persisted := false
+	if saved, err := s.manager.Store().GetTeam(teamName); err == nil && saved != nil {
+		persisted = saved.TokenMode == string(t.GetTokenMode())
 	}
 
 	s.json(w, http.StatusOK, map[string]interface{}{
-		"status": "ok",
-		"mode":   req.Mode,
+		"mode":            t.GetTokenMode(),
+		"max_tokens":      settings.MaxTokens,
+		"context_history": settings.ContextHistory,
+		"context_bytes":   settings.ContextBytes,
+		"persisted":       persisted,
 	})
 }
 
+// handleTeamProject starts a project on the team (POST /project/start) and
+// reports on it (GET /project/status)
+func (s *Server) handleTeamProject(w http.ResponseWriter, r *http.Request, teamName string, parts []string) {
+	action := ""
+	if len(parts) > 0 {
+		action = parts[0]
+	}
+
+	switch action {
+	case "start":
+		if r.Method != "POST" {
+			s.error(w, http.StatusMethodNotAllowed, "POST required")
+			return
+		}
+		var req struct {
+			Request string `json:"request"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			s.error(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+		if req.Request == "" {
+			s.error(w, http.StatusBadRequest, "request is required")
+			return
+		}
+
+		_ = s.manager.StartTeam(teamName)
+		status, err := s.manager.StartProject(teamName, req.Request)
+		if err != nil {
+			s.fail(w, err)
+			return
+		}
+		s.json(w, http.StatusOK, status)
+
+	case "status":
+		if r.Method != "GET" {
+			s.error(w, http.StatusMethodNotAllowed, "GET required")
+			return
+		}
+		status, err := s.manager.ProjectStatus(teamName)
+		if err != nil {
+			s.fail(w, err)
+			return
+		}
+		s.json(w, http.StatusOK, status)
+
+	default:
+		s.notFound(w, "route", "use /project/start or /project/status")
+	}
+}
+
+// handleMemberContext clears one member's context, leaving the rest of the
+// team and the active conversation alone
+func (s *Server) handleMemberContext(w http.ResponseWriter, r *http.Request, teamName, memberID string) {
+	if r.Method != "DELETE" {
+		s.error(w, http.StatusMethodNotAllowed, "DELETE required")
+		return
+	}
+	if err := s.manager.ClearMemberContext(teamName, memberID); err != nil {
+		s.fail(w, err)
+		return
+	}
+	s.json(w, http.StatusOK, map[string]interface{}{"status": "cleared", "member": memberID})
+}
+
+// handleMemberPrompt shows the system prompt and tools a member is given
+func (s *Server) handleMemberPrompt(w http.ResponseWriter, r *http.Request, teamName, memberID string) {
+	if r.Method != "GET" {
+		s.error(w, http.StatusMethodNotAllowed, "GET required")
+		return
+	}
+	prompt, err := s.manager.MemberPrompt(teamName, memberID)
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+	s.json(w, http.StatusOK, prompt)
+}
+
+// handleTeamQuestions lists the questions members are waiting on the client
+// to answer (GET /questions), and takes answers (POST /questions/{id}/answer)
+func (s *Server) handleTeamQuestions(w http.ResponseWriter, r *http.Request, teamName string, parts []string) {
+	if len(parts) == 0 || parts[0] == "" {
+		if r.Method != "GET" {
+			s.error(w, http.StatusMethodNotAllowed, "GET required")
+			return
+		}
+		questions, err := s.manager.PendingQuestions(teamName)
+		if err != nil {
+			s.fail(w, err)
+			return
+		}
+		s.json(w, http.StatusOK, map[string]interface{}{"questions": questions})
+		return
+	}
+
+	if len(parts) != 2 || parts[1] != "answer" {
+		s.notFound(w, "route", "use /questions or /questions/{id}/answer")
+		return
+	}
+	if r.Method != "POST" {
+		s.error(w, http.StatusMethodNotAllowed, "POST required")
+		return
+	}
+
+	var req struct {
+		Answer string `json:"answer"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.Answer == "" {
+		s.error(w, http.StatusBadRequest, "answer is required")
+		return
+	}
+	if err := s.manager.AnswerQuestion(teamName, parts[0], req.Answer); err != nil {
+		s.fail(w, err)
+		return
+	}
+	s.json(w, http.StatusOK, map[string]interface{}{"acknowledged": true})
+}
+
+// handleTeamApprovals lists the tool calls waiting on a human (GET
+// /approvals, or every recorded decision with ?history=true) and takes
+// decisions (POST /approvals/{id}/approve or /approvals/{id}/deny)
+// handleTeamPerformance reports where a team's time went in model calls
+// (GET /api/teams/{name}/performance?since=24h)
+func (s *Server) handleTeamPerformance(w http.ResponseWriter, r *http.Request, teamName string) {
+	if r.Method != "GET" {
+		s.error(w, http.StatusMethodNotAllowed, "GET required")
+		return
+	}
+	since, err := parseTimeParam(r, "since")
+	if err != nil {
+		s.error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	perf, err := s.manager.TeamPerformance(teamName, since)
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+	s.json(w, http.StatusOK, perf)
+}
+
+// handleTeamBench handles POST /api/teams/{name}/bench, asking the team a
+// standard prompt and reporting its latency and model calls
+func (s *Server) handleTeamBench(w http.ResponseWriter, r *http.Request, teamName string) {
+	if r.Method != "POST" {
+		s.error(w, http.StatusMethodNotAllowed, "POST required")
+		return
+	}
+
+	var req struct {
+		manager.BenchOptions
+		TimeoutSeconds int `json:"timeout_seconds,omitempty"` // Per run (max 600)
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			s.error(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+	}
+	if req.Iterations < 0 || req.Iterations > manager.MaxBenchIterations {
+		s.error(w, http.StatusBadRequest, fmt.Sprintf("iterations must be from 1 to %d", manager.MaxBenchIterations))
+		return
+	}
+	req.Timeout = manager.DefaultBenchTimeout
+	if req.TimeoutSeconds > 0 {
+		req.Timeout = chatTimeout(req.TimeoutSeconds)
+	}
+
+	release, retryAfter, acquired := s.chats.acquire(teamName)
+	if !acquired {
+		seconds := int(math.Ceil(retryAfter.Seconds()))
+		w.Header().Set("Retry-After", strconv.Itoa(seconds))
+		s.writeError(w, http.StatusTooManyRequests, CodeRateLimited, "too many chat requests, retry later", map[string]interface{}{
+			"retry_after_seconds": seconds,
+		})
+		return
+	}
+	defer release()
+
+	_ = s.manager.StartTeam(teamName)
+
+	// The whole bench has to finish within the server's write timeout
+	ctx, cancel := context.WithTimeout(r.Context(), maxChatTimeout)
+	defer cancel()
+	report, err := s.manager.BenchTeam(ctx, teamName, req.BenchOptions)
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+	s.json(w, http.StatusOK, report)
+}
+
+func (s *Server) handleTeamApprovals(w http.ResponseWriter, r *http.Request, teamName string, parts []string) {
+	if len(parts) == 0 || parts[0] == "" {
+		if r.Method != "GET" {
+			s.error(w, http.StatusMethodNotAllowed, "GET required")
+			return
+		}
+		var approvals []team.Approval
+		var err error
+		if r.URL.Query().Get("history") == "true" {
+			limit, perr := parseIntParam(r, "limit", defaultApprovalLimit, maxApprovalLimit)
+			if perr != nil {
+				s.error(w, http.StatusBadRequest, perr.Error())
+				return
+			}
+			approvals, err = s.manager.ApprovalHistory(teamName, limit)
+		} else {
+			approvals, err = s.manager.PendingApprovals(teamName)
+		}
+		if err != nil {
+			s.fail(w, err)
+			return
+		}
+		if approvals == nil {
+			approvals = []team.Approval{}
+		}
+		s.json(w, http.StatusOK, map[string]interface{}{"approvals": approvals})
+		return
+	}
+
+	if len(parts) != 2 || (parts[1] != "approve" && parts[1] != "deny") {
+		s.notFound(w, "route", "use /approvals, /approvals/{id}/approve or /approvals/{id}/deny")
+		return
+	}
+	if r.Method != "POST" {
+		s.error(w, http.StatusMethodNotAllowed, "POST required")
+		return
+	}
+
+	// The reason is optional, so an empty body is fine
+	var req struct {
+		Reason string `json:"reason"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			s.error(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+	}
+	approval, err := s.manager.DecideApproval(teamName, parts[0], parts[1] == "approve", req.Reason)
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+	s.json(w, http.StatusOK, approval)
+}
+
 func (s *Server) handleSettings(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 	case "GET":
 		cfg, err := config.Load()
 		if err != nil {
-			s.error(w, http.StatusInternalServerError, err.Error())
+			s.fail(w, err)
 			return
 		}
 
@@ -1221,8 +2195,29 @@ func (s *Server) handleSettings(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		// Audit which settings changed, never their values
+		var changed []string
+		for name, set := range map[string]bool{
+			"anthropic":  req.Providers.Anthropic != nil,
+			"openai":     req.Providers.OpenAI != nil,
+			"openrouter": req.Providers.OpenRouter != nil,
+			"groq":       req.Providers.Groq != nil,
+			"ollama":     req.Providers.Ollama != nil,
+			"defaults":   req.Defaults != nil,
+		} {
+			if set {
+				changed = append(changed, name)
+			}
+		}
+		sort.Strings(changed)
+		s.manager.RecordAudit(manager.AuditEntry{Action: manager.AuditSettings, Actor: "client", RequestID: r.Header.Get("X-Request-ID"), Details: map[string]interface{}{
+			"changed": changed,
+		}})
+
 		// Apply to environment so providers can be discovered
-		cfg.ApplyToEnvironment()
+		if err := cfg.ApplyToEnvironment(); err != nil {
+			s.logger.Warn("failed to apply settings", "error", err)
+		}
 
 		// Re-initialize providers
 		s.manager.Providers().AutoDiscover()
@@ -1251,20 +2246,142 @@ func (s *Server) handleConversation(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if strings.HasSuffix(path, "/summary") {
+		s.handleConversationSummary(w, r, strings.TrimSuffix(path, "/summary"))
+		return
+	}
+
 	switch r.Method {
 	case "GET":
-		// Get conversation history
-		messages, err := store.GetConversationHistory(path)
+		// Prefer the client transcript; conversations recorded before it
+		// existed only have per-agent context
+		messages, err := store.GetClientMessages(path)
 		if err != nil {
-			s.error(w, http.StatusInternalServerError, err.Error())
+			s.fail(w, err)
 			return
 		}
+		if len(messages) == 0 {
+			messages, err = store.GetConversationHistory(path)
+			if err != nil {
+				s.fail(w, err)
+				return
+			}
+		}
 
 		s.json(w, http.StatusOK, map[string]interface{}{
 			"messages": messages,
 		})
 
+	case "PATCH":
+		var req struct {
+			Title string `json:"title"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			s.error(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+		if strings.TrimSpace(req.Title) == "" {
+			s.error(w, http.StatusBadRequest, "title is required")
+			return
+		}
+		if err := s.manager.RenameConversation(path, req.Title); err != nil {
+			s.fail(w, err)
+			return
+		}
+		s.json(w, http.StatusOK, map[string]interface{}{"id": path, "title": strings.TrimSpace(req.Title)})
+
 	default:
 		s.error(w, http.StatusMethodNotAllowed, "method not allowed")
 	}
 }
+
+// handleAudit handles GET /api/audit, the append-only audit log
+func (s *Server) handleAudit(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		s.error(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	q := r.URL.Query()
+	filter := manager.AuditFilter{
+		Team:      q.Get("team"),
+		Action:    q.Get("action"),
+		Actor:     q.Get("actor"),
+		RequestID: q.Get("request_id"),
+	}
+	var err error
+	if filter.Limit, err = parseIntParam(r, "limit", defaultAuditLimit, maxAuditLimit); err != nil {
+		s.error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if filter.Since, err = parseTimeParam(r, "since"); err != nil {
+		s.error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if filter.Until, err = parseTimeParam(r, "until"); err != nil {
+		s.error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	entries, err := s.manager.AuditLog(filter)
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+	if entries == nil {
+		entries = []manager.AuditEntry{}
+	}
+	s.json(w, http.StatusOK, map[string]interface{}{"entries": entries})
+}
+
+// handleLogs handles GET /api/logs, the daemon's most recent log records
+func (s *Server) handleLogs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		s.error(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	q := r.URL.Query()
+	filter := logger.Filter{Team: q.Get("team")}
+	var err error
+	if level := q.Get("level"); level != "" {
+		if filter.Level, err = logger.ParseLevel(level); err != nil {
+			s.error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	if filter.Limit, err = parseIntParam(r, "limit", defaultLogLimit, maxLogLimit); err != nil {
+		s.error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if filter.Since, err = parseTimeParam(r, "since"); err != nil {
+		s.error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if after := q.Get("after"); after != "" {
+		if filter.After, err = strconv.ParseUint(after, 10, 64); err != nil {
+			s.error(w, http.StatusBadRequest, "after must be a record's seq")
+			return
+		}
+	}
+
+	records := []logger.Record{}
+	if buffer := s.logger.Buffer(); buffer != nil {
+		records = buffer.Records(filter)
+	}
+	s.json(w, http.StatusOK, map[string]interface{}{"records": records})
+}
+
+// handleConversationSummary handles GET /api/conversations/{id}/summary
+func (s *Server) handleConversationSummary(w http.ResponseWriter, r *http.Request, id string) {
+	if r.Method != "GET" {
+		s.error(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	summary, err := s.manager.SummarizeConversation(r.Context(), id)
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+	s.json(w, http.StatusOK, summary)
+}
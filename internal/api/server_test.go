@@ -0,0 +1,1391 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arcslash/ugudu/internal/logger"
+	"github.com/arcslash/ugudu/internal/manager"
+	"github.com/arcslash/ugudu/internal/provider"
+	"github.com/arcslash/ugudu/internal/team"
+	"github.com/arcslash/ugudu/internal/tools"
+	"github.com/arcslash/ugudu/internal/workspace"
+	"github.com/gorilla/websocket"
+	"gopkg.in/yaml.v3"
+)
+
+func newTestServer(t *testing.T) *Server {
+	t.Helper()
+
+	home := t.TempDir()
+	t.Setenv("UGUDU_HOME", home)
+	if err := os.MkdirAll(filepath.Join(home, "specs"), 0755); err != nil {
+		t.Fatalf("Failed to create specs dir: %v", err)
+	}
+
+	mgr, err := manager.New(manager.Config{DataDir: filepath.Join(home, "data")}, logger.New("error"))
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	t.Cleanup(mgr.Stop)
+
+	return NewServer(mgr, logger.New("error"))
+}
+
+func serve(s *Server, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, req)
+	return rec
+}
+
+func TestSpecVersions(t *testing.T) {
+	s := newTestServer(t)
+
+	for _, desc := range []string{"first", "second"} {
+		body := `{"name":"my-team","description":"` + desc + `","provider":"anthropic","model":"claude-sonnet-4-20250514","roles":{"pm":{"title":"PM"}}}`
+		if rec := serve(s, "POST", "/api/specs", body); rec.Code != http.StatusOK {
+			t.Fatalf("Save spec failed: %d %s", rec.Code, rec.Body.String())
+		}
+	}
+
+	rec := serve(s, "GET", "/api/specs/my-team/versions", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("List versions failed: %d %s", rec.Code, rec.Body.String())
+	}
+	var list struct {
+		Versions []manager.SpecVersion `json:"versions"`
+	}
+	json.NewDecoder(rec.Body).Decode(&list)
+	if len(list.Versions) != 2 {
+		t.Fatalf("Expected 2 versions after two saves, got %d", len(list.Versions))
+	}
+
+	// Restoring version 1 brings back the first description as a new version
+	rec = serve(s, "POST", "/api/specs/my-team/versions/1/restore", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Restore failed: %d %s", rec.Code, rec.Body.String())
+	}
+	var restored map[string]interface{}
+	json.NewDecoder(rec.Body).Decode(&restored)
+	if restored["version"] != float64(3) {
+		t.Errorf("Expected restore to be saved as version 3, got %v", restored["version"])
+	}
+
+	data, _ := os.ReadFile(filepath.Join(os.Getenv("UGUDU_HOME"), "specs", "my-team.yaml"))
+	if !strings.Contains(string(data), "description: first") {
+		t.Errorf("Expected restored spec content, got:\n%s", data)
+	}
+
+	if rec := serve(s, "POST", "/api/specs/my-team/versions/9/restore", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 restoring a missing version, got %d", rec.Code)
+	}
+}
+
+// stubProvider answers every chat request with a fixed reply
+type stubProvider struct {
+	id    string // Defaults to "stub"
+	reply string
+	gate  chan struct{}      // If set, Chat waits for it to close
+	call  *provider.ToolCall // If set, requested before replying
+}
+
+func (p *stubProvider) ID() string {
+	if p.id != "" {
+		return p.id
+	}
+	return "stub"
+}
+
+func (p *stubProvider) Name() string { return "Stub" }
+
+func (p *stubProvider) Chat(ctx context.Context, req *provider.ChatRequest) (*provider.ChatResponse, error) {
+	if p.gate != nil {
+		select {
+		case <-p.gate:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	if p.call != nil && req.Messages[len(req.Messages)-1].ToolCallID != p.call.ID {
+		return &provider.ChatResponse{ToolCalls: []provider.ToolCall{*p.call}, Provider: p.ID(), Model: req.Model}, nil
+	}
+	return &provider.ChatResponse{Content: p.reply, Provider: p.ID(), Model: req.Model}, nil
+}
+
+func (p *stubProvider) Stream(ctx context.Context, req *provider.ChatRequest) (<-chan provider.StreamChunk, error) {
+	return nil, nil
+}
+
+func (p *stubProvider) ListModels(ctx context.Context) ([]provider.ModelInfo, error) {
+	return nil, nil
+}
+
+func (p *stubProvider) Ping(ctx context.Context) error { return nil }
+
+func TestHandleChat_Stream(t *testing.T) {
+	s := newTestServer(t)
+	if err := s.manager.Start(context.Background()); err != nil {
+		t.Fatalf("Failed to start manager: %v", err)
+	}
+	s.manager.Providers().Register(&stubProvider{reply: "Happy to help with that."})
+
+	spec := `
+apiVersion: ugudu/v1
+kind: Team
+metadata:
+  name: stream-test
+
+client_facing:
+  - lead
+
+roles:
+  lead:
+    title: Team Lead
+    visibility: client
+    model:
+      provider: stub
+      model: stub-model
+`
+	specPath := filepath.Join(t.TempDir(), "stream-test.yaml")
+	os.WriteFile(specPath, []byte(spec), 0644)
+	if _, err := s.manager.CreateTeam(specPath); err != nil {
+		t.Fatalf("CreateTeam failed: %v", err)
+	}
+
+	rec := serve(s, "POST", "/api/chat", `{"team":"stream-test","to":"lead","message":"hello","stream":true}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/x-ndjson" {
+		t.Errorf("Expected NDJSON content type, got %q", ct)
+	}
+	if rec.Header().Get("X-Request-ID") == "" {
+		t.Error("Expected a generated X-Request-ID header")
+	}
+
+	var lines []map[string]interface{}
+	scanner := bufio.NewScanner(rec.Body)
+	for scanner.Scan() {
+		var line map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			t.Fatalf("Invalid NDJSON line %q: %v", scanner.Text(), err)
+		}
+		lines = append(lines, line)
+	}
+
+	if len(lines) != 2 {
+		t.Fatalf("Expected a response line and a done line, got %v", lines)
+	}
+	if lines[0]["content"] != "Happy to help with that." {
+		t.Errorf("Unexpected response line: %v", lines[0])
+	}
+	if lines[1]["done"] != true {
+		t.Errorf("Expected final done line, got %v", lines[1])
+	}
+}
+
+func TestProgressBroadcast(t *testing.T) {
+	s := newTestServer(t)
+	if err := s.manager.Start(context.Background()); err != nil {
+		t.Fatalf("Failed to start manager: %v", err)
+	}
+	s.manager.Providers().Register(&stubProvider{reply: "Done.", call: &provider.ToolCall{
+		ID:        "call-1",
+		Name:      "report_progress",
+		Arguments: `{"status":"in_progress","percent_complete":40,"message":"Schema drafted"}`,
+	}})
+
+	spec := `
+apiVersion: ugudu/v1
+kind: Team
+metadata:
+  name: progress-test
+
+client_facing:
+  - lead
+
+roles:
+  lead:
+    title: Team Lead
+    visibility: client
+    model:
+      provider: stub
+      model: stub-model
+`
+	specPath := filepath.Join(t.TempDir(), "progress-test.yaml")
+	os.WriteFile(specPath, []byte(spec), 0644)
+	if _, err := s.manager.CreateTeam(specPath); err != nil {
+		t.Fatalf("CreateTeam failed: %v", err)
+	}
+
+	ts := httptest.NewServer(s.Handler())
+	defer ts.Close()
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(ts.URL, "http")+"/api/ws", nil)
+	if err != nil {
+		t.Fatalf("Dial failed: %v", err)
+	}
+	defer conn.Close()
+
+	// Wait for the hub to register the connection so no event is missed
+	for deadline := time.Now().Add(2 * time.Second); ; time.Sleep(5 * time.Millisecond) {
+		s.wsHub.mu.RLock()
+		n := len(s.wsHub.clients)
+		s.wsHub.mu.RUnlock()
+		if n == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("WebSocket client was never registered")
+		}
+	}
+
+	rec := serve(s, "POST", "/api/chat", `{"team":"progress-test","to":"lead","message":"design the schema"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for {
+		var event WSEvent
+		if err := conn.ReadJSON(&event); err != nil {
+			t.Fatalf("No progress event received: %v", err)
+		}
+		data, _ := event.Data.(map[string]interface{})
+		if event.Type != "activity" || data["type"] != "progress" {
+			continue
+		}
+		if data["percent"] != float64(40) || data["status"] != "in_progress" {
+			t.Errorf("Unexpected progress data: %v", data)
+		}
+		if event.Message != "Schema drafted" || event.MemberID == "" {
+			t.Errorf("Unexpected progress event: %+v", event)
+		}
+		break
+	}
+
+	// Progress only describes the work in hand, so it's gone once idle
+	rec = serve(s, "GET", "/api/teams/progress-test/members", "")
+	if strings.Contains(rec.Body.String(), `"progress":{`) {
+		t.Errorf("Expected progress cleared after the task, got %s", rec.Body.String())
+	}
+}
+
+func TestHandleChat_TimeoutThenContinue(t *testing.T) {
+	s := newTestServer(t)
+	if err := s.manager.Start(context.Background()); err != nil {
+		t.Fatalf("Failed to start manager: %v", err)
+	}
+	gate := make(chan struct{})
+	s.manager.Providers().Register(&stubProvider{reply: "Finished the report.", gate: gate})
+
+	spec := `
+apiVersion: ugudu/v1
+kind: Team
+metadata:
+  name: slow-test
+
+client_facing:
+  - lead
+
+roles:
+  lead:
+    title: Team Lead
+    visibility: client
+    model:
+      provider: stub
+      model: stub-model
+`
+	specPath := filepath.Join(t.TempDir(), "slow-test.yaml")
+	os.WriteFile(specPath, []byte(spec), 0644)
+	if _, err := s.manager.CreateTeam(specPath); err != nil {
+		t.Fatalf("CreateTeam failed: %v", err)
+	}
+
+	// The lead is still working when the chat gives up
+	rec := serve(s, "POST", "/api/chat", `{"team":"slow-test","to":"lead","message":"write the report","timeout_seconds":1}`)
+	var timedOut struct {
+		Timeout           bool   `json:"timeout"`
+		ContinuationToken string `json:"continuation_token"`
+	}
+	json.NewDecoder(rec.Body).Decode(&timedOut)
+	if !timedOut.Timeout || timedOut.ContinuationToken == "" {
+		t.Fatalf("Expected timeout with continuation token, got %d: %+v", rec.Code, timedOut)
+	}
+
+	// The lead finishes in the background; continuing picks up the reply
+	close(gate)
+	rec = serve(s, "POST", "/api/teams/slow-test/continue", `{"token":"`+timedOut.ContinuationToken+`"}`)
+	var continued struct {
+		Responses []map[string]interface{} `json:"responses"`
+		Timeout   bool                     `json:"timeout"`
+		Restarted bool                     `json:"restarted"`
+	}
+	json.NewDecoder(rec.Body).Decode(&continued)
+	if rec.Code != http.StatusOK || continued.Timeout || continued.Restarted {
+		t.Fatalf("Expected continued chat to complete, got %d: %+v", rec.Code, continued)
+	}
+	if len(continued.Responses) != 1 || continued.Responses[0]["content"] != "Finished the report." {
+		t.Errorf("Expected the lead's reply, got %v", continued.Responses)
+	}
+
+	// A used token re-asks the team from its saved conversation
+	rec = serve(s, "POST", "/api/teams/slow-test/continue", `{"token":"`+timedOut.ContinuationToken+`","to":"lead"}`)
+	continued.Restarted = false
+	json.NewDecoder(rec.Body).Decode(&continued)
+	if !continued.Restarted {
+		t.Errorf("Expected a used token to restart the ask, got %s", rec.Body.String())
+	}
+}
+
+func TestHandleChatBulk(t *testing.T) {
+	s := newTestServer(t)
+	if err := s.manager.Start(context.Background()); err != nil {
+		t.Fatalf("Failed to start manager: %v", err)
+	}
+	s.manager.Providers().Register(&stubProvider{id: "stub-a", reply: "Use Postgres."})
+	s.manager.Providers().Register(&stubProvider{id: "stub-b", reply: "Use SQLite."})
+
+	for _, name := range []string{"team-a", "team-b"} {
+		spec := `
+apiVersion: ugudu/v1
+kind: Team
+metadata:
+  name: ` + name + `
+
+client_facing:
+  - lead
+
+roles:
+  lead:
+    title: Team Lead
+    visibility: client
+    model:
+      provider: stub-` + strings.TrimPrefix(name, "team-") + `
+      model: stub-model
+`
+		specPath := filepath.Join(t.TempDir(), name+".yaml")
+		os.WriteFile(specPath, []byte(spec), 0644)
+		if _, err := s.manager.CreateTeam(specPath); err != nil {
+			t.Fatalf("CreateTeam %s failed: %v", name, err)
+		}
+	}
+
+	rec := serve(s, "POST", "/api/chat/bulk", `{"teams":["team-a","team-b","missing"],"to":"lead","message":"Which database?"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var body struct {
+		Results map[string]struct {
+			Responses []map[string]interface{} `json:"responses"`
+			Error     *APIError                `json:"error"`
+		} `json:"results"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("Invalid response: %v", err)
+	}
+
+	for name, want := range map[string]string{"team-a": "Use Postgres.", "team-b": "Use SQLite."} {
+		result := body.Results[name]
+		if result.Error != nil || len(result.Responses) != 1 || result.Responses[0]["content"] != want {
+			t.Errorf("Expected %s to answer %q, got %+v", name, want, result)
+		}
+	}
+
+	// An unknown team fails on its own without failing the batch
+	if missing := body.Results["missing"]; missing.Error == nil || missing.Error.Code != CodeNotFound {
+		t.Errorf("Expected NOT_FOUND for the missing team, got %+v", missing)
+	}
+
+	if rec := serve(s, "POST", "/api/chat/bulk", `{"teams":[],"message":"hi"}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 without teams, got %d", rec.Code)
+	}
+}
+
+func TestErrorCodes(t *testing.T) {
+	s := newTestServer(t)
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		body     string
+		status   int
+		code     ErrorCode
+		resource string
+	}{
+		{"missing team", "GET", "/api/teams/missing", "", http.StatusNotFound, CodeNotFound, "team"},
+		{"start missing team", "POST", "/api/teams/missing/start", "", http.StatusNotFound, CodeNotFound, "team"},
+		{"chat with missing team", "POST", "/api/chat", `{"team":"missing","message":"hi"}`, http.StatusNotFound, CodeNotFound, "team"},
+		{"missing spec", "GET", "/api/specs/missing", "", http.StatusNotFound, CodeNotFound, "spec"},
+		{"missing provider", "GET", "/api/providers/missing", "", http.StatusNotFound, CodeNotFound, "provider"},
+		{"malformed body", "POST", "/api/chat", `{`, http.StatusBadRequest, CodeValidation, ""},
+		{"missing fields", "POST", "/api/chat", `{"team":"alpha"}`, http.StatusBadRequest, CodeValidation, ""},
+		{"wrong method", "GET", "/api/chat", "", http.StatusMethodNotAllowed, CodeValidation, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(s, tt.method, tt.path, tt.body)
+			if rec.Code != tt.status {
+				t.Fatalf("Expected %d, got %d: %s", tt.status, rec.Code, rec.Body.String())
+			}
+
+			var body struct {
+				Error APIError `json:"error"`
+			}
+			if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+				t.Fatalf("Failed to decode error body: %v", err)
+			}
+			if body.Error.Code != tt.code {
+				t.Errorf("Expected code %s, got %s", tt.code, body.Error.Code)
+			}
+			if body.Error.Message == "" {
+				t.Error("Expected a human-readable message")
+			}
+			if tt.resource != "" && body.Error.Details["resource"] != tt.resource {
+				t.Errorf("Expected resource %q, got %v", tt.resource, body.Error.Details["resource"])
+			}
+		})
+	}
+}
+
+func TestCreateTeam_MissingProvider(t *testing.T) {
+	s := newTestServer(t)
+
+	spec := `
+metadata:
+  name: groq-team
+
+roles:
+  lead:
+    title: Team Lead
+    model:
+      provider: groq
+      model: llama-3.3-70b-versatile
+`
+	specPath := filepath.Join(t.TempDir(), "groq-team.yaml")
+	os.WriteFile(specPath, []byte(spec), 0644)
+
+	rec := serve(s, "POST", "/api/teams", `{"spec_path":"`+specPath+`"}`)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("Expected 400, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var body struct {
+		Error APIError `json:"error"`
+	}
+	json.NewDecoder(rec.Body).Decode(&body)
+	if body.Error.Code != CodeValidation || !strings.Contains(body.Error.Message, "GROQ_API_KEY") {
+		t.Errorf("Expected a validation error naming GROQ_API_KEY, got %+v", body.Error)
+	}
+	if body.Error.Details["provider"] != "groq" || body.Error.Details["env_var"] != "GROQ_API_KEY" {
+		t.Errorf("Unexpected details: %v", body.Error.Details)
+	}
+}
+
+func TestFail_ProviderErrors(t *testing.T) {
+	s := newTestServer(t)
+
+	tests := []struct {
+		err    error
+		status int
+		code   ErrorCode
+	}{
+		{&provider.AuthError{Provider: "Anthropic Claude", StatusCode: 401}, http.StatusBadGateway, CodeProviderError},
+		{&provider.RateLimitError{Info: &provider.RateLimitInfo{}, Message: "slow down"}, http.StatusTooManyRequests, CodeRateLimited},
+		{fmt.Errorf("%w: configure one", manager.ErrNoSummaryProvider), http.StatusServiceUnavailable, CodeProviderError},
+		{os.ErrPermission, http.StatusInternalServerError, CodeInternal},
+	}
+
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		s.fail(rec, tt.err)
+		if rec.Code != tt.status {
+			t.Errorf("%v: expected %d, got %d", tt.err, tt.status, rec.Code)
+		}
+
+		var body struct {
+			Error APIError `json:"error"`
+		}
+		json.NewDecoder(rec.Body).Decode(&body)
+		if body.Error.Code != tt.code {
+			t.Errorf("%v: expected code %s, got %s", tt.err, tt.code, body.Error.Code)
+		}
+	}
+}
+
+func TestHandleStatus_Daemon(t *testing.T) {
+	s := newTestServer(t)
+	os.WriteFile(filepath.Join(s.manager.DataDir(), "extra.log"), make([]byte, 2048), 0644)
+
+	rec := serve(s, "GET", "/api/status", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var status struct {
+		Daemon struct {
+			Version        string    `json:"version"`
+			GoVersion      string    `json:"go_version"`
+			StartedAt      time.Time `json:"started_at"`
+			UptimeSeconds  int64     `json:"uptime_seconds"`
+			Goroutines     int       `json:"goroutines"`
+			ActiveRequests int64     `json:"active_requests"`
+			DataDir        string    `json:"data_dir"`
+			DataDirBytes   int64     `json:"data_dir_bytes"`
+			Memory         struct {
+				AllocBytes uint64 `json:"alloc_bytes"`
+				SysBytes   uint64 `json:"sys_bytes"`
+			} `json:"memory"`
+		} `json:"daemon"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &status); err != nil {
+		t.Fatalf("Invalid status JSON: %v", err)
+	}
+
+	d := status.Daemon
+	if d.Version == "" || d.GoVersion == "" {
+		t.Errorf("Expected version info, got %q and %q", d.Version, d.GoVersion)
+	}
+	if d.StartedAt.IsZero() || d.StartedAt.After(time.Now()) || d.UptimeSeconds < 0 {
+		t.Errorf("Expected a past start time and non-negative uptime, got %v and %d", d.StartedAt, d.UptimeSeconds)
+	}
+	if d.Goroutines <= 0 {
+		t.Errorf("Expected goroutines to be counted, got %d", d.Goroutines)
+	}
+	if d.Memory.AllocBytes == 0 || d.Memory.SysBytes < d.Memory.AllocBytes {
+		t.Errorf("Expected sane memory stats, got %+v", d.Memory)
+	}
+	// The status request itself is in flight
+	if d.ActiveRequests != 1 {
+		t.Errorf("Expected 1 active request, got %d", d.ActiveRequests)
+	}
+	if d.DataDir != s.manager.DataDir() || d.DataDirBytes < 2048 {
+		t.Errorf("Expected data dir usage of at least 2048 bytes in %s, got %d in %s", s.manager.DataDir(), d.DataDirBytes, d.DataDir)
+	}
+}
+
+func TestHandleProjectArtifacts(t *testing.T) {
+	s := newTestServer(t)
+	home := t.TempDir()
+	t.Setenv("HOME", home) // write_file only writes under the home directory
+	t.Setenv("UGUDU_PROJECTS", filepath.Join(home, "projects"))
+
+	ws, err := workspace.Init("artifacts-test", t.TempDir(), "test-team")
+	if err != nil {
+		t.Fatalf("Init workspace failed: %v", err)
+	}
+	registry := tools.NewSandboxedRegistry(tools.NewRegistry(), ws, "engineer", "engineer-1")
+	args := map[string]interface{}{"path": "docs/notes.md", "content": "# Release notes"}
+	if _, err := registry.Execute(context.Background(), "write_file", args); err != nil {
+		t.Fatalf("write_file failed: %v", err)
+	}
+
+	type listing struct {
+		Artifacts []struct {
+			ID        string `json:"id"`
+			Path      string `json:"path"`
+			CreatedBy string `json:"created_by"`
+			Stale     bool   `json:"stale"`
+			URL       string `json:"url"`
+		} `json:"artifacts"`
+	}
+	list := func() listing {
+		t.Helper()
+		rec := serve(s, "GET", "/api/projects/artifacts-test/artifacts", "")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		var got listing
+		json.Unmarshal(rec.Body.Bytes(), &got)
+		return got
+	}
+
+	got := list()
+	if len(got.Artifacts) != 1 {
+		t.Fatalf("Expected the written file as the only artifact, got %+v", got.Artifacts)
+	}
+	a := got.Artifacts[0]
+	if a.Path != "workspaces/engineer/docs/notes.md" || a.CreatedBy != "engineer-1" || a.Stale || a.URL == "" {
+		t.Errorf("Unexpected artifact: %+v", a)
+	}
+
+	rec := serve(s, "GET", a.URL, "")
+	if rec.Code != http.StatusOK || rec.Body.String() != "# Release notes" {
+		t.Fatalf("Expected the file's contents, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if cd := rec.Header().Get("Content-Disposition"); !strings.Contains(cd, "notes.md") {
+		t.Errorf("Expected an attachment named notes.md, got %q", cd)
+	}
+
+	// Deleted since: still listed, but stale and not downloadable
+	if err := os.Remove(filepath.Join(ws.GetSandboxPath("engineer"), "docs", "notes.md")); err != nil {
+		t.Fatalf("Remove failed: %v", err)
+	}
+	if got := list(); len(got.Artifacts) != 1 || !got.Artifacts[0].Stale || got.Artifacts[0].URL != "" {
+		t.Errorf("Expected a stale artifact without a url, got %+v", got.Artifacts)
+	}
+	if rec := serve(s, "GET", a.URL, ""); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for a deleted artifact, got %d", rec.Code)
+	}
+	if rec := serve(s, "GET", "/api/projects/artifacts-test/artifacts/nope", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown artifact, got %d", rec.Code)
+	}
+}
+
+func TestHandleProjectActivity_Limit(t *testing.T) {
+	s := newTestServer(t)
+	t.Setenv("UGUDU_PROJECTS", t.TempDir())
+
+	ws, err := workspace.Init("activity-test", t.TempDir(), "test-team")
+	if err != nil {
+		t.Fatalf("Init workspace failed: %v", err)
+	}
+	log, err := workspace.NewActivityLogger(ws, "engineer")
+	if err != nil {
+		t.Fatalf("NewActivityLogger failed: %v", err)
+	}
+	for i := 0; i < 8; i++ {
+		log.Log(workspace.NewActivityEntry(workspace.ActivityProgress, "engineer-1", "engineer"))
+	}
+	log.Close()
+
+	for query, want := range map[string]int{"?limit=5": 5, "": 8} {
+		rec := serve(s, "GET", "/api/projects/activity-test/activity"+query, "")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		var result struct {
+			Count int `json:"count"`
+		}
+		json.Unmarshal(rec.Body.Bytes(), &result)
+		if result.Count != want {
+			t.Errorf("Expected %d entries for %q, got %d", want, query, result.Count)
+		}
+	}
+
+	if rec := serve(s, "GET", "/api/projects/activity-test/activity?limit=0", ""); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an out-of-range limit, got %d", rec.Code)
+	}
+}
+
+func TestHandleTeamSpec(t *testing.T) {
+	s := newTestServer(t)
+	s.manager.Providers().Register(&stubProvider{})
+
+	// count and visibility are left to their defaults
+	spec := `
+metadata:
+  name: spec-test
+
+roles:
+  lead:
+    title: Team Lead
+    model:
+      provider: stub
+      model: stub-model
+`
+	specPath := filepath.Join(t.TempDir(), "spec-test.yaml")
+	os.WriteFile(specPath, []byte(spec), 0644)
+	if _, err := s.manager.CreateTeam(specPath); err != nil {
+		t.Fatalf("CreateTeam failed: %v", err)
+	}
+
+	// Later edits to the file don't change what the team runs
+	os.WriteFile(specPath, []byte(strings.Replace(spec, "Team Lead", "Edited Lead", 1)), 0644)
+
+	rec := serve(s, "GET", "/api/teams/spec-test/spec", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/yaml" {
+		t.Errorf("Expected YAML content type, got %q", ct)
+	}
+
+	var running team.TeamSpec
+	if err := yaml.Unmarshal(rec.Body.Bytes(), &running); err != nil {
+		t.Fatalf("Invalid YAML %q: %v", rec.Body.String(), err)
+	}
+	lead := running.Roles["lead"]
+	if lead.Count != 1 || lead.Visibility != "internal" {
+		t.Errorf("Expected defaults count=1 and visibility=internal, got count=%d visibility=%q", lead.Count, lead.Visibility)
+	}
+	if lead.Title != "Team Lead" || running.APIVersion != team.APIVersionV1 || running.Kind != team.KindTeam {
+		t.Errorf("Unexpected running spec: %s", rec.Body.String())
+	}
+
+	if rec := serve(s, "GET", "/api/teams/missing/spec", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for a missing team, got %d", rec.Code)
+	}
+}
+
+func TestHandleTeamSpecWithoutGlobalPrompt(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("UGUDU_HOME", home)
+	mgr, err := manager.New(manager.Config{
+		DataDir:      filepath.Join(home, "data"),
+		SystemPrefix: "Never share secrets.",
+		SystemSuffix: "Be brief.",
+	}, logger.New("error"))
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	t.Cleanup(mgr.Stop)
+	s := NewServer(mgr, logger.New("error"))
+	mgr.Providers().Register(&stubProvider{})
+
+	spec := `
+metadata:
+  name: prompt-test
+
+roles:
+  lead:
+    title: Team Lead
+    model:
+      provider: stub
+      model: stub-model
+
+settings:
+  system_prefix: Follow the style guide.
+`
+	specPath := filepath.Join(home, "prompt-test.yaml")
+	os.WriteFile(specPath, []byte(spec), 0644)
+	if _, err := mgr.CreateTeam(specPath); err != nil {
+		t.Fatalf("CreateTeam failed: %v", err)
+	}
+
+	for _, tt := range []struct {
+		query          string
+		prefix, suffix string
+	}{
+		{"", "Never share secrets.\n\nFollow the style guide.", "Be brief."},
+		{"?global_prompt=false", "Follow the style guide.", ""},
+	} {
+		rec := serve(s, "GET", "/api/teams/prompt-test/spec"+tt.query, "")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%q: expected 200, got %d: %s", tt.query, rec.Code, rec.Body.String())
+		}
+		var got team.TeamSpec
+		if err := yaml.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+			t.Fatalf("Invalid YAML %q: %v", rec.Body.String(), err)
+		}
+		if got.Settings.SystemPrefix != tt.prefix || got.Settings.SystemSuffix != tt.suffix {
+			t.Errorf("%q: expected prefix %q and suffix %q, got %q and %q",
+				tt.query, tt.prefix, tt.suffix, got.Settings.SystemPrefix, got.Settings.SystemSuffix)
+		}
+	}
+
+	if rec := serve(s, "GET", "/api/teams/missing/spec?global_prompt=false", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for a missing team, got %d", rec.Code)
+	}
+}
+
+func TestHandleTeamPause(t *testing.T) {
+	s := newTestServer(t)
+	if err := s.manager.Start(context.Background()); err != nil {
+		t.Fatalf("Failed to start manager: %v", err)
+	}
+	s.manager.Providers().Register(&stubProvider{reply: "Back at it."})
+
+	spec := `
+apiVersion: ugudu/v1
+kind: Team
+metadata:
+  name: pause-test
+
+client_facing:
+  - lead
+
+roles:
+  lead:
+    title: Team Lead
+    model:
+      provider: stub
+      model: stub-model
+`
+	specPath := filepath.Join(t.TempDir(), "pause-test.yaml")
+	os.WriteFile(specPath, []byte(spec), 0644)
+	if _, err := s.manager.CreateTeam(specPath); err != nil {
+		t.Fatalf("CreateTeam failed: %v", err)
+	}
+
+	if rec := serve(s, "POST", "/api/teams/pause-test/pause", ""); rec.Code != http.StatusOK {
+		t.Fatalf("Pause failed: %d %s", rec.Code, rec.Body.String())
+	}
+
+	rec := serve(s, "GET", "/api/teams/pause-test", "")
+	var status map[string]interface{}
+	json.Unmarshal(rec.Body.Bytes(), &status)
+	if status["status"] != "paused" {
+		t.Errorf("Expected paused team status, got %v", status["status"])
+	}
+
+	chat := `{"team":"pause-test","to":"lead","message":"hello"}`
+	rec = serve(s, "POST", "/api/chat", chat)
+	if rec.Code != http.StatusConflict || !strings.Contains(rec.Body.String(), `"CONFLICT"`) {
+		t.Errorf("Expected 409 CONFLICT while paused, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	if rec := serve(s, "POST", "/api/teams/pause-test/resume", ""); rec.Code != http.StatusOK {
+		t.Fatalf("Resume failed: %d %s", rec.Code, rec.Body.String())
+	}
+	rec = serve(s, "POST", "/api/chat", chat)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "Back at it.") {
+		t.Errorf("Expected chat to succeed after resume, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	if rec := serve(s, "POST", "/api/teams/missing/pause", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 pausing an unknown team, got %d", rec.Code)
+	}
+}
+
+func TestHandleChat_ReportsServingModel(t *testing.T) {
+	s := newTestServer(t)
+	if err := s.manager.Start(context.Background()); err != nil {
+		t.Fatalf("Failed to start manager: %v", err)
+	}
+	s.manager.Providers().Register(&stubProvider{reply: "Done."})
+
+	spec := `
+apiVersion: ugudu/v1
+kind: Team
+metadata:
+  name: model-test
+
+client_facing:
+  - lead
+
+roles:
+  lead:
+    title: Team Lead
+    model:
+      provider: stub
+      model: stub-large
+      low_token_model: stub-small
+`
+	specPath := filepath.Join(t.TempDir(), "model-test.yaml")
+	os.WriteFile(specPath, []byte(spec), 0644)
+	if _, err := s.manager.CreateTeam(specPath); err != nil {
+		t.Fatalf("CreateTeam failed: %v", err)
+	}
+
+	tests := []struct {
+		mode  string
+		model string
+	}{
+		{"normal", "stub-large"},
+		{"low", "stub-small"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			if rec := serve(s, "POST", "/api/teams/model-test/token-mode", `{"mode":"`+tt.mode+`"}`); rec.Code != http.StatusOK {
+				t.Fatalf("Set token mode failed: %d %s", rec.Code, rec.Body.String())
+			}
+
+			rec := serve(s, "POST", "/api/chat", `{"team":"model-test","to":"lead","message":"hello"}`)
+			var body struct {
+				Responses []map[string]interface{} `json:"responses"`
+			}
+			json.Unmarshal(rec.Body.Bytes(), &body)
+			if len(body.Responses) != 1 {
+				t.Fatalf("Expected 1 response, got %d: %s", len(body.Responses), rec.Body.String())
+			}
+			if body.Responses[0]["model"] != tt.model || body.Responses[0]["provider"] != "stub" {
+				t.Errorf("Expected %s via stub, got %v via %v", tt.model, body.Responses[0]["model"], body.Responses[0]["provider"])
+			}
+		})
+	}
+}
+
+func TestHandleTeamQuestions(t *testing.T) {
+	s := newTestServer(t)
+	s.manager.Providers().Register(&stubProvider{reply: "ok"})
+
+	spec := `
+apiVersion: ugudu/v1
+kind: Team
+metadata:
+  name: questions-test
+
+roles:
+  lead:
+    title: Team Lead
+    visibility: client
+    model:
+      provider: stub
+      model: stub-model
+`
+	specPath := filepath.Join(t.TempDir(), "questions-test.yaml")
+	os.WriteFile(specPath, []byte(spec), 0644)
+	if _, err := s.manager.CreateTeam(specPath); err != nil {
+		t.Fatalf("CreateTeam failed: %v", err)
+	}
+
+	rec := serve(s, "GET", "/api/teams/questions-test/questions", "")
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"questions":[]`) {
+		t.Errorf("Expected no pending questions, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	tests := []struct {
+		name string
+		path string
+		body string
+		want int
+	}{
+		{"unknown question", "/api/teams/questions-test/questions/nope/answer", `{"answer":"8081"}`, http.StatusNotFound},
+		{"missing answer", "/api/teams/questions-test/questions/nope/answer", `{}`, http.StatusBadRequest},
+		{"unknown team", "/api/teams/missing/questions/nope/answer", `{"answer":"8081"}`, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rec := serve(s, "POST", tt.path, tt.body); rec.Code != tt.want {
+				t.Errorf("Expected %d, got %d: %s", tt.want, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandleMemberPrompt(t *testing.T) {
+	s := newTestServer(t)
+	s.manager.Providers().Register(&stubProvider{})
+
+	spec := `
+metadata:
+  name: prompt-test
+
+roles:
+  lead:
+    title: Team Lead
+    persona: |
+      You lead the team.
+      You review every change in detail before it ships.
+    persona_condensed: Lead the team; review changes.
+    responsibilities:
+      - Review pull requests
+    can_delegate: [dev]
+    model:
+      provider: stub
+      model: stub-model
+  dev:
+    title: Developer
+    model:
+      provider: stub
+      model: stub-model
+`
+	specPath := filepath.Join(t.TempDir(), "prompt-test.yaml")
+	os.WriteFile(specPath, []byte(spec), 0644)
+	tm, err := s.manager.CreateTeam(specPath)
+	if err != nil {
+		t.Fatalf("CreateTeam failed: %v", err)
+	}
+
+	get := func() team.MemberPrompt {
+		t.Helper()
+		rec := serve(s, "GET", "/api/teams/prompt-test/members/lead/prompt", "")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		var prompt team.MemberPrompt
+		if err := json.Unmarshal(rec.Body.Bytes(), &prompt); err != nil {
+			t.Fatalf("Invalid response %q: %v", rec.Body.String(), err)
+		}
+		return prompt
+	}
+
+	normal := get()
+	if normal.TokenMode != team.TokenModeNormal || !strings.Contains(normal.System, "in detail") || !strings.Contains(normal.System, "Review pull requests") {
+		t.Errorf("Expected the full persona and responsibilities in normal mode, got %q", normal.System)
+	}
+	if !strings.Contains(normal.System, "DELEGATE PARALLEL:") || len(normal.Tools) == 0 {
+		t.Errorf("Expected delegation instructions and tools, got %q with tools %v", normal.System, normal.Tools)
+	}
+
+	// Low token mode uses the condensed persona and drops responsibilities
+	tm.SetTokenMode(team.TokenModeLow)
+	low := get()
+	if low.TokenMode != team.TokenModeLow || !strings.HasPrefix(low.System, "Lead the team; review changes.\n") {
+		t.Errorf("Expected the condensed persona in low mode, got %q", low.System)
+	}
+	if strings.Contains(low.System, "Review pull requests") || !strings.Contains(low.System, "\nTools available: ") {
+		t.Errorf("Expected the condensed prompt in low mode, got %q", low.System)
+	}
+	if len(low.System) >= len(normal.System) {
+		t.Errorf("Expected the low mode prompt shorter than normal (%d), got %d", len(normal.System), len(low.System))
+	}
+
+	if rec := serve(s, "GET", "/api/teams/prompt-test/members/nobody/prompt", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for a missing member, got %d", rec.Code)
+	}
+}
+
+func TestWebSocket_ReconnectReplaysMissedEvents(t *testing.T) {
+	s := newTestServer(t)
+	ts := httptest.NewServer(s.Handler())
+	defer ts.Close()
+
+	clients := func() int {
+		s.wsHub.mu.RLock()
+		defer s.wsHub.mu.RUnlock()
+		return len(s.wsHub.clients)
+	}
+	waitClients := func(n int) {
+		t.Helper()
+		for deadline := time.Now().Add(2 * time.Second); clients() != n; time.Sleep(5 * time.Millisecond) {
+			if time.Now().After(deadline) {
+				t.Fatalf("Expected %d WebSocket clients, got %d", n, clients())
+			}
+		}
+	}
+	dial := func(query string) *websocket.Conn {
+		t.Helper()
+		conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(ts.URL, "http")+"/api/ws"+query, nil)
+		if err != nil {
+			t.Fatalf("Dial failed: %v", err)
+		}
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		return conn
+	}
+	read := func(conn *websocket.Conn) WSEvent {
+		t.Helper()
+		var event WSEvent
+		if err := conn.ReadJSON(&event); err != nil {
+			t.Fatalf("ReadJSON failed: %v", err)
+		}
+		return event
+	}
+
+	conn := dial("")
+	waitClients(1)
+	s.wsHub.BroadcastTeamUpdate("created", "alpha", nil)
+	seen := read(conn)
+	if seen.Seq == 0 || seen.Message != "created" {
+		t.Fatalf("Expected a sequenced event, got %+v", seen)
+	}
+
+	// Events sent while the client is gone are replayed from its cursor
+	conn.Close()
+	waitClients(0)
+	s.wsHub.BroadcastTeamUpdate("started", "alpha", nil)
+	s.wsHub.BroadcastTeamUpdate("stopped", "alpha", nil)
+
+	conn = dial("?since=" + strconv.FormatUint(seen.Seq, 10) + "&epoch=" + seen.Epoch)
+	defer conn.Close()
+	for i, want := range []string{"started", "stopped"} {
+		event := read(conn)
+		if event.Message != want || event.Seq != seen.Seq+uint64(i)+1 {
+			t.Errorf("Expected %q with seq %d, got %+v", want, seen.Seq+uint64(i)+1, event)
+		}
+	}
+
+	// A cursor the hub can't serve gets a resync
+	stale := dial("?since=1000000&epoch=" + seen.Epoch)
+	defer stale.Close()
+	if event := read(stale); event.Type != "resync" {
+		t.Errorf("Expected a resync event, got %+v", event)
+	}
+
+	// So does one from another epoch, e.g. before a daemon restart, even if
+	// its seq is one this hub has handed out; nothing is replayed after it
+	restarted := dial("?since=" + strconv.FormatUint(seen.Seq, 10) + "&epoch=before-restart")
+	defer restarted.Close()
+	if event := read(restarted); event.Type != "resync" || event.Epoch != seen.Epoch {
+		t.Errorf("Expected a resync carrying the current epoch, got %+v", event)
+	}
+	s.wsHub.BroadcastTeamUpdate("deleted", "alpha", nil)
+	if event := read(restarted); event.Message != "deleted" {
+		t.Errorf("Expected live events after the resync, not a replay, got %+v", event)
+	}
+}
+
+func TestHandleConversationSummary(t *testing.T) {
+	s := newTestServer(t)
+	if err := s.manager.Start(context.Background()); err != nil {
+		t.Fatalf("Failed to start manager: %v", err)
+	}
+	// The summary comes from the team's client-facing model, which answers
+	// every request the same way
+	reply := `Here's the summary: {"summary": "A login page was requested.", "decisions": ["Use OAuth"], "open_questions": [], "artifacts": ["login.tsx"]}`
+	s.manager.Providers().Register(&stubProvider{reply: reply})
+
+	spec := `
+apiVersion: ugudu/v1
+kind: Team
+metadata:
+  name: summary-test
+
+client_facing:
+  - lead
+
+roles:
+  lead:
+    title: Team Lead
+    visibility: client
+    model:
+      provider: stub
+      model: stub-model
+`
+	specPath := filepath.Join(t.TempDir(), "summary-test.yaml")
+	os.WriteFile(specPath, []byte(spec), 0644)
+	tm, err := s.manager.CreateTeam(specPath)
+	if err != nil {
+		t.Fatalf("CreateTeam failed: %v", err)
+	}
+
+	chat := func() {
+		t.Helper()
+		if rec := serve(s, "POST", "/api/chat", `{"team":"summary-test","to":"lead","message":"Build a login page"}`); rec.Code != http.StatusOK {
+			t.Fatalf("Chat failed: %d %s", rec.Code, rec.Body.String())
+		}
+	}
+	summarize := func() manager.ConversationSummary {
+		t.Helper()
+		rec := serve(s, "GET", "/api/conversations/"+tm.GetConversationID()+"/summary", "")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Summary failed: %d %s", rec.Code, rec.Body.String())
+		}
+		var summary manager.ConversationSummary
+		json.NewDecoder(rec.Body).Decode(&summary)
+		return summary
+	}
+
+	chat()
+	first := summarize()
+	if first.Summary != "A login page was requested." || len(first.Decisions) != 1 || first.Decisions[0] != "Use OAuth" {
+		t.Errorf("Unexpected summary: %+v", first)
+	}
+	if first.OpenQuestions == nil || len(first.Artifacts) != 1 {
+		t.Errorf("Expected empty open questions and one artifact, got %+v", first)
+	}
+	if first.Sequence != 2 || first.Provider != "stub" || first.Model != "stub-model" {
+		t.Errorf("Expected 2 messages summarized by stub/stub-model, got %+v", first)
+	}
+
+	// Unchanged conversations are answered from cache
+	if again := summarize(); !again.GeneratedAt.Equal(first.GeneratedAt) {
+		t.Errorf("Expected the cached summary, got one generated at %v", again.GeneratedAt)
+	}
+
+	// New messages make it stale
+	chat()
+	if next := summarize(); next.Sequence != 4 {
+		t.Errorf("Expected a new summary of 4 messages, got %+v", next)
+	}
+
+	if rec := serve(s, "GET", "/api/conversations/conv-missing/summary", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown conversation, got %d", rec.Code)
+	}
+}
+
+func TestHandleAudit_Chat(t *testing.T) {
+	s := newTestServer(t)
+	if err := s.manager.Start(context.Background()); err != nil {
+		t.Fatalf("Failed to start manager: %v", err)
+	}
+	s.manager.Providers().Register(&stubProvider{reply: "Done.", call: &provider.ToolCall{
+		ID:        "call-1",
+		Name:      "report_progress",
+		Arguments: `{"status":"in_progress","percent_complete":40,"message":"Schema drafted"}`,
+	}})
+
+	spec := `
+apiVersion: ugudu/v1
+kind: Team
+metadata:
+  name: audit-test
+
+client_facing:
+  - lead
+
+roles:
+  lead:
+    title: Team Lead
+    visibility: client
+    model:
+      provider: stub
+      model: stub-model
+`
+	specPath := filepath.Join(t.TempDir(), "audit-test.yaml")
+	os.WriteFile(specPath, []byte(spec), 0644)
+	if _, err := s.manager.CreateTeam(specPath); err != nil {
+		t.Fatalf("CreateTeam failed: %v", err)
+	}
+
+	req := httptest.NewRequest("POST", "/api/chat", strings.NewReader(`{"team":"audit-test","to":"lead","message":"design the schema"}`))
+	req.Header.Set("X-Request-ID", "audit-req-1")
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Chat failed: %d %s", rec.Code, rec.Body.String())
+	}
+
+	audit := func(query string) []manager.AuditEntry {
+		t.Helper()
+		rec := serve(s, "GET", "/api/audit?"+query, "")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Audit failed: %d %s", rec.Code, rec.Body.String())
+		}
+		var body struct {
+			Entries []manager.AuditEntry `json:"entries"`
+		}
+		json.NewDecoder(rec.Body).Decode(&body)
+		return body.Entries
+	}
+
+	entries := audit("request_id=audit-req-1")
+	var actions []string
+	for _, e := range entries {
+		actions = append(actions, e.Action)
+	}
+	if strings.Join(actions, ",") != "chat.request,tool.execute,chat.response" {
+		t.Fatalf("Expected the request, tool call and response, got %v", actions)
+	}
+	if e := entries[0]; e.Actor != "client" || e.Team != "audit-test" || e.Details["content"] != "design the schema" || e.Details["to"] != "lead" {
+		t.Errorf("Unexpected request entry: %+v", e)
+	}
+	if e := entries[1]; e.Actor == "client" || e.Details["tool"] != "report_progress" || e.Details["args"] == nil {
+		t.Errorf("Unexpected tool entry: %+v", e)
+	}
+	if e := entries[2]; e.Details["content"] != "Done." || e.Details["model"] != "stub-model" {
+		t.Errorf("Unexpected response entry: %+v", e)
+	}
+
+	// Lifecycle entries outlive the team
+	if rec := serve(s, "DELETE", "/api/teams/audit-test", ""); rec.Code != http.StatusOK {
+		t.Fatalf("Delete failed: %d %s", rec.Code, rec.Body.String())
+	}
+	actions = nil
+	for _, e := range audit("team=audit-test&action=team") {
+		actions = append(actions, e.Action)
+	}
+	if strings.Join(actions, ",") != "team.create,team.start,team.delete" {
+		t.Errorf("Expected the team created, started by the chat and deleted, got %v", actions)
+	}
+
+	if rec := serve(s, "GET", "/api/audit?since=yesterday", ""); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a bad since, got %d", rec.Code)
+	}
+	if rec := serve(s, "POST", "/api/audit", ""); rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected the audit log to be read-only, got %d", rec.Code)
+	}
+}
+
+func TestSpecSpecialistsRejectsPaths(t *testing.T) {
+	s := newTestServer(t)
+
+	body := `{"name":"my-team","provider":"anthropic","model":"claude-sonnet-4-20250514","roles":{"pm":{"title":"PM"}}}`
+	if rec := serve(s, "POST", "/api/specs", body); rec.Code != http.StatusOK {
+		t.Fatalf("Save spec failed: %d %s", rec.Code, rec.Body.String())
+	}
+
+	rec := serve(s, "POST", "/api/specs/my-team/specialists", `{"specialist":"../../outside"}`)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a specialist name outside the directory, got %d %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestHandleTeamSpecKeepsSecretsOut(t *testing.T) {
+	s := newTestServer(t)
+	s.manager.Providers().Register(&stubProvider{})
+
+	dir := t.TempDir()
+	tokenPath := filepath.Join(dir, "deploy-token")
+	os.WriteFile(tokenPath, []byte("s3cret-deploy-token\n"), 0600)
+
+	spec := `
+metadata:
+  name: secret-test
+
+roles:
+  lead:
+    title: Team Lead
+    model:
+      provider: stub
+      model: stub-model
+    env:
+      DEPLOY_TOKEN: ${file:` + tokenPath + `}
+
+settings:
+  env:
+    REGION: eu-west-1
+`
+	specPath := filepath.Join(dir, "secret-test.yaml")
+	os.WriteFile(specPath, []byte(spec), 0644)
+	tm, err := s.manager.CreateTeam(specPath)
+	if err != nil {
+		t.Fatalf("CreateTeam failed: %v", err)
+	}
+	if got := tm.Spec.Roles["lead"].Env["DEPLOY_TOKEN"]; got != "s3cret-deploy-token" {
+		t.Fatalf("Expected the running team to have the secret expanded, got %q", got)
+	}
+
+	rec := serve(s, "GET", "/api/teams/secret-test/spec", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if strings.Contains(rec.Body.String(), "s3cret-deploy-token") {
+		t.Errorf("Expected the secret kept out of the spec, got:\n%s", rec.Body.String())
+	}
+	var running team.TeamSpec
+	if err := yaml.Unmarshal(rec.Body.Bytes(), &running); err != nil {
+		t.Fatalf("Invalid YAML %q: %v", rec.Body.String(), err)
+	}
+	if got := running.Roles["lead"].Env["DEPLOY_TOKEN"]; got != "${file:"+tokenPath+"}" {
+		t.Errorf("Expected the secret reference as written, got %q", got)
+	}
+	if running.Settings.Env["REGION"] != "eu-west-1" || running.Metadata.Name != "secret-test" {
+		t.Errorf("Unexpected spec: %s", rec.Body.String())
+	}
+}
+
+func TestHandleTeamDiffKeepsSecretsOut(t *testing.T) {
+	s := newTestServer(t)
+	s.manager.Providers().Register(&stubProvider{})
+
+	dir := t.TempDir()
+	tokenPath := filepath.Join(dir, "deploy-token")
+	os.WriteFile(tokenPath, []byte("s3cret-deploy-token\n"), 0600)
+
+	spec := `
+metadata:
+  name: %s
+
+roles:
+  lead:
+    title: Team Lead
+    model:
+      provider: stub
+      model: stub-model
+%s`
+	for name, env := range map[string]string{
+		"plain":  "",
+		"secret": "    env:\n      DEPLOY_TOKEN: ${file:" + tokenPath + "}\n",
+	} {
+		specPath := filepath.Join(dir, name+".yaml")
+		os.WriteFile(specPath, []byte(fmt.Sprintf(spec, name, env)), 0644)
+		if _, err := s.manager.CreateTeam(specPath); err != nil {
+			t.Fatalf("CreateTeam %s failed: %v", name, err)
+		}
+	}
+
+	rec := serve(s, "GET", "/api/teams/plain/diff/secret", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if strings.Contains(rec.Body.String(), "s3cret-deploy-token") {
+		t.Errorf("Expected the secret kept out of the diff, got %s", rec.Body.String())
+	}
+	if !strings.Contains(rec.Body.String(), "env.DEPLOY_TOKEN") {
+		t.Errorf("Expected the env change in the diff, got %s", rec.Body.String())
+	}
+}
@@ -3,9 +3,11 @@ package api
 import (
 	"encoding/json"
 	"net/http"
+	"strconv"
 	"sync"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
 )
 
@@ -17,9 +19,15 @@ var upgrader = websocket.Upgrader{
 	},
 }
 
+// wsReplaySize is how many recent events the hub keeps for clients that
+// reconnect after a brief drop
+const wsReplaySize = 500
+
 // WSEvent represents a WebSocket event
 type WSEvent struct {
-	Type      string      `json:"type"`      // "member_status", "activity", "task_update"
+	Seq       uint64      `json:"seq"`   // Increases by one per event; reconnect with ?since=<seq>&epoch=<epoch>
+	Epoch     string      `json:"epoch"` // Identifies the hub seq counts in; new each time the daemon starts
+	Type      string      `json:"type"`  // "member_status", "activity", "task_update", "resync"
 	Team      string      `json:"team"`
 	MemberID  string      `json:"member_id,omitempty"`
 	Status    string      `json:"status,omitempty"`
@@ -32,9 +40,27 @@ type WSEvent struct {
 type WSHub struct {
 	clients    map[*websocket.Conn]bool
 	broadcast  chan WSEvent
-	register   chan *websocket.Conn
+	register   chan wsRegistration
 	unregister chan *websocket.Conn
 	mu         sync.RWMutex
+
+	// Seqs restart at 1 with each hub, so a cursor is only meaningful
+	// alongside the epoch it came from
+	epoch string
+
+	// Owned by Run: the last sequence number handed out, and the most
+	// recent events in order, oldest first
+	seq    uint64
+	recent []WSEvent
+}
+
+// wsRegistration is a connecting client and, if it's reconnecting, the last
+// event it saw
+type wsRegistration struct {
+	conn      *websocket.Conn
+	since     uint64
+	epoch     string
+	reconnect bool
 }
 
 // NewWSHub creates a new WebSocket hub
@@ -42,8 +68,9 @@ func NewWSHub() *WSHub {
 	return &WSHub{
 		clients:    make(map[*websocket.Conn]bool),
 		broadcast:  make(chan WSEvent, 256),
-		register:   make(chan *websocket.Conn),
+		register:   make(chan wsRegistration),
 		unregister: make(chan *websocket.Conn),
+		epoch:      uuid.New().String()[:8],
 	}
 }
 
@@ -51,9 +78,15 @@ func NewWSHub() *WSHub {
 func (h *WSHub) Run() {
 	for {
 		select {
-		case conn := <-h.register:
+		case reg := <-h.register:
+			// Replay before adding the client, so it gets every event after
+			// its cursor exactly once and in order
+			if reg.reconnect && !h.replay(reg.conn, reg.since, reg.epoch) {
+				reg.conn.Close()
+				continue
+			}
 			h.mu.Lock()
-			h.clients[conn] = true
+			h.clients[reg.conn] = true
 			h.mu.Unlock()
 
 		case conn := <-h.unregister:
@@ -65,7 +98,15 @@ func (h *WSHub) Run() {
 			h.mu.Unlock()
 
 		case event := <-h.broadcast:
-			h.mu.RLock()
+			h.seq++
+			event.Seq = h.seq
+			event.Epoch = h.epoch
+			h.recent = append(h.recent, event)
+			if len(h.recent) > wsReplaySize {
+				h.recent = h.recent[len(h.recent)-wsReplaySize:]
+			}
+
+			h.mu.Lock()
 			data, _ := json.Marshal(event)
 			for conn := range h.clients {
 				if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
@@ -73,9 +114,44 @@ func (h *WSHub) Run() {
 					delete(h.clients, conn)
 				}
 			}
-			h.mu.RUnlock()
+			h.mu.Unlock()
+		}
+	}
+}
+
+// replay sends a reconnecting client the buffered events after since. If
+// some of them are no longer buffered, it sends a resync event first so the
+// client reloads its state. A cursor from another epoch, e.g. from before a
+// daemon restart, says nothing about which events the client has, so it only
+// gets the resync. It reports whether the writes succeeded.
+func (h *WSHub) replay(conn *websocket.Conn, since uint64, epoch string) bool {
+	missed := h.recent
+	if epoch != h.epoch {
+		missed = nil
+	}
+	for len(missed) > 0 && missed[0].Seq <= since {
+		missed = missed[1:]
+	}
+
+	gap := epoch != h.epoch || since > h.seq || (len(missed) > 0 && missed[0].Seq > since+1)
+	if gap {
+		// Not an event in the sequence, so it has no seq of its own
+		resync := WSEvent{
+			Type:      "resync",
+			Epoch:     h.epoch,
+			Message:   "missed events are no longer available; reload state",
+			Timestamp: time.Now(),
+		}
+		if err := conn.WriteJSON(resync); err != nil {
+			return false
+		}
+	}
+	for _, event := range missed {
+		if err := conn.WriteJSON(event); err != nil {
+			return false
 		}
 	}
+	return true
 }
 
 // Broadcast sends an event to all connected clients
@@ -170,7 +246,14 @@ func (s *Server) HandleWS(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	s.wsHub.register <- conn
+	// A reconnecting client passes the seq and epoch of the last event it saw
+	reg := wsRegistration{conn: conn, epoch: r.URL.Query().Get("epoch")}
+	if since := r.URL.Query().Get("since"); since != "" {
+		if n, err := strconv.ParseUint(since, 10, 64); err == nil {
+			reg.since, reg.reconnect = n, true
+		}
+	}
+	s.wsHub.register <- reg
 
 	// Keep connection alive and handle incoming messages
 	go func() {
@@ -5,11 +5,15 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
 
-// UguduHome returns the Ugudu home directory
+// UguduHome returns the Ugudu home directory: $UGUDU_HOME, or ~/.ugudu.
+// Config, data and the socket live here unless the matching XDG variable
+// points them elsewhere.
 func UguduHome() string {
 	// Check UGUDU_HOME environment variable first
 	if home := os.Getenv("UGUDU_HOME"); home != "" {
@@ -24,24 +28,70 @@ func UguduHome() string {
 	return filepath.Join(userHome, ".ugudu")
 }
 
+// ConfigDir returns the directory holding config.yaml: $UGUDU_HOME, then
+// ~/.ugudu if it already has a config.yaml, then $XDG_CONFIG_HOME/ugudu,
+// then ~/.ugudu
+func ConfigDir() string {
+	return xdgDir("XDG_CONFIG_HOME", "config.yaml")
+}
+
+// DataHome returns the directory holding specs and runtime data:
+// $UGUDU_HOME, then ~/.ugudu if it already has specs or data, then
+// $XDG_DATA_HOME/ugudu, then ~/.ugudu
+func DataHome() string {
+	return xdgDir("XDG_DATA_HOME", "specs", "data")
+}
+
+// RuntimeDir returns the directory holding the daemon socket: $UGUDU_HOME,
+// then $XDG_RUNTIME_DIR/ugudu, then ~/.ugudu
+func RuntimeDir() string {
+	return xdgDir("XDG_RUNTIME_DIR")
+}
+
+// xdgDir resolves one of Ugudu's directories. UGUDU_HOME keeps everything
+// in one place; otherwise the XDG variable named by env is used when set,
+// unless ~/.ugudu already holds any of existing, so setting the variable
+// doesn't abandon a setup from before it was honored. The XDG spec says
+// relative paths are invalid, so they're ignored.
+func xdgDir(env string, existing ...string) string {
+	if os.Getenv("UGUDU_HOME") != "" {
+		return UguduHome()
+	}
+	dir := os.Getenv(env)
+	if !filepath.IsAbs(dir) {
+		return UguduHome()
+	}
+	for _, name := range existing {
+		if _, err := os.Stat(filepath.Join(UguduHome(), name)); err == nil {
+			return UguduHome()
+		}
+	}
+	return filepath.Join(dir, "ugudu")
+}
+
 // SpecsDir returns the specs (blueprints) directory
 func SpecsDir() string {
-	return filepath.Join(UguduHome(), "specs")
+	return filepath.Join(DataHome(), "specs")
+}
+
+// SpecialistsDir returns the directory of specialist role templates
+func SpecialistsDir() string {
+	return filepath.Join(SpecsDir(), "specialists")
 }
 
 // DataDir returns the data directory
 func DataDir() string {
-	return filepath.Join(UguduHome(), "data")
+	return filepath.Join(DataHome(), "data")
 }
 
 // ConfigPath returns the config file path
 func ConfigPath() string {
-	return filepath.Join(UguduHome(), "config.yaml")
+	return filepath.Join(ConfigDir(), "config.yaml")
 }
 
 // SocketPath returns the socket path
 func SocketPath() string {
-	return filepath.Join(UguduHome(), "ugudu.sock")
+	return filepath.Join(RuntimeDir(), "ugudu.sock")
 }
 
 // ProjectsDir returns the centralized projects directory
@@ -74,6 +124,15 @@ type Config struct {
 
 	// Daemon settings
 	Daemon DaemonConfig `yaml:"daemon"`
+
+	// Redaction of sensitive data before it is sent to providers
+	Redaction RedactionConfig `yaml:"redaction,omitempty"`
+
+	// Reuse replies to identical provider requests, for dev and test loops
+	ResponseCache ResponseCacheConfig `yaml:"response_cache,omitempty"`
+
+	// External MCP servers, by name, whose tools team roles can use
+	MCPServers map[string]MCPServerConfig `yaml:"mcp_servers,omitempty"`
 }
 
 // ProvidersConfig holds provider API keys
@@ -87,7 +146,14 @@ type ProvidersConfig struct {
 
 // AnthropicConfig holds Anthropic settings
 type AnthropicConfig struct {
-	APIKey string `yaml:"api_key,omitempty"`
+	APIKey  string `yaml:"api_key,omitempty"`
+	BaseURL string `yaml:"base_url,omitempty"` // e.g. a gateway; defaults to Anthropic's API
+
+	// Maximum requests in flight at once; 0 means unlimited
+	MaxConcurrency int `yaml:"max_concurrency,omitempty"`
+
+	// Cache system prompts and tool definitions between turns
+	PromptCaching bool `yaml:"prompt_caching,omitempty"`
 }
 
 // OpenAIConfig holds OpenAI settings
@@ -98,7 +164,8 @@ type OpenAIConfig struct {
 
 // GroqConfig holds Groq settings
 type GroqConfig struct {
-	APIKey string `yaml:"api_key,omitempty"`
+	APIKey  string `yaml:"api_key,omitempty"`
+	BaseURL string `yaml:"base_url,omitempty"`
 }
 
 // OllamaConfig holds Ollama settings
@@ -109,19 +176,103 @@ type OllamaConfig struct {
 // OpenRouterConfig holds OpenRouter settings
 type OpenRouterConfig struct {
 	APIKey   string `yaml:"api_key,omitempty"`
+	BaseURL  string `yaml:"base_url,omitempty"`
 	SiteName string `yaml:"site_name,omitempty"`
 	SiteURL  string `yaml:"site_url,omitempty"`
+
+	// Default upstream provider routing; a role's model.openrouter overrides it
+	Provider OpenRouterRouting `yaml:"provider,omitempty"`
+}
+
+// OpenRouterRouting holds OpenRouter provider routing preferences
+type OpenRouterRouting struct {
+	Order          []string `yaml:"order,omitempty"`
+	AllowFallbacks *bool    `yaml:"allow_fallbacks,omitempty"`
+	DataCollection string   `yaml:"data_collection,omitempty"` // "allow" or "deny"
 }
 
 // DefaultsConfig holds default settings
 type DefaultsConfig struct {
 	Provider string `yaml:"provider,omitempty"`
 	Model    string `yaml:"model,omitempty"`
+
+	// Wrapped around every member's system prompt unless a team opts out
+	SystemPrefix string `yaml:"system_prefix,omitempty"`
+	SystemSuffix string `yaml:"system_suffix,omitempty"`
+
+	// IANA timezone members are told the time in unless a spec sets one
+	Timezone string `yaml:"timezone,omitempty"`
 }
 
 // DaemonConfig holds daemon settings
 type DaemonConfig struct {
-	TCPAddr string `yaml:"tcp_addr,omitempty"`
+	TCPAddr         string `yaml:"tcp_addr,omitempty"`
+	MaxSpecVersions int    `yaml:"max_spec_versions,omitempty"` // Spec history kept per spec (default 20)
+
+	MaxMembersPerTeam int `yaml:"max_members_per_team,omitempty"` // Members a team may have (default 25)
+	MemberInboxSize   int `yaml:"member_inbox_size,omitempty"`    // Buffered messages per member inbox (default 100)
+	MemberOutboxSize  int `yaml:"member_outbox_size,omitempty"`   // Buffered messages per member outbox (default 100)
+
+	MaxToolResultBytes int `yaml:"max_tool_result_bytes,omitempty"` // Bytes of one tool result sent to a model (default 65536)
+
+	ClientChannelSize      int    `yaml:"client_channel_size,omitempty"`      // Buffered replies waiting for the client, per team (default 100)
+	InternalChannelSize    int    `yaml:"internal_channel_size,omitempty"`    // Buffered messages between members, per team (default 1000)
+	ChannelOverflow        string `yaml:"channel_overflow,omitempty"`         // Full inbox or internal channel: drop (default), block or error
+	ClientChannelOverflow  string `yaml:"client_channel_overflow,omitempty"`  // Full client channel: block (default), drop or error
+	ChannelOverflowSeconds int    `yaml:"channel_overflow_seconds,omitempty"` // How long block waits for room (default 30)
+
+	ProviderCheckSeconds int `yaml:"provider_check_seconds,omitempty"` // Seconds between provider health checks (default 60, -1 turns them off)
+
+	ModelConversationTitles bool `yaml:"model_conversation_titles,omitempty"` // Title conversations with a small model instead of the first message
+
+	IdleStopSeconds int `yaml:"idle_stop_seconds,omitempty"` // Stop teams idle this long until their next ask (default 0, off)
+
+	LogBufferSize int `yaml:"log_buffer_size,omitempty"` // Recent log records kept for GET /api/logs (default 1000)
+}
+
+// RedactionConfig controls redaction of sensitive data from requests to
+// providers. Matches are replaced with placeholders like [EMAIL_1].
+type RedactionConfig struct {
+	Enabled bool `yaml:"enabled,omitempty"`
+
+	// Built-in patterns to apply: email, card, ssn. Empty means all of them;
+	// [none] applies only the custom patterns.
+	Builtins []string `yaml:"builtins,omitempty"`
+
+	Patterns []RedactionPattern `yaml:"patterns,omitempty"`
+
+	// Put the original values back into provider replies
+	Restore bool `yaml:"restore,omitempty"`
+}
+
+// ResponseCacheConfig controls the provider response cache. It's off by
+// default: only turn it on where a repeated prompt should get the same reply.
+type ResponseCacheConfig struct {
+	Enabled    bool `yaml:"enabled,omitempty"`
+	TTLSeconds int  `yaml:"ttl_seconds,omitempty"` // How long a reply is reused (default 600)
+	MaxEntries int  `yaml:"max_entries,omitempty"` // Replies kept (default 500)
+
+	// Requests above this temperature, or without one, aren't cached
+	// (default 0.3)
+	MaxTemperature float64 `yaml:"max_temperature,omitempty"`
+
+	// Also cache requests with tools or a high temperature
+	Force bool `yaml:"force,omitempty"`
+}
+
+// MCPServerConfig is an external MCP server. Command runs it as a subprocess
+// over stdio; URL reaches it over HTTP.
+type MCPServerConfig struct {
+	Command []string          `yaml:"command,omitempty"`
+	Env     map[string]string `yaml:"env,omitempty"`
+	URL     string            `yaml:"url,omitempty"`
+	Headers map[string]string `yaml:"headers,omitempty"`
+}
+
+// RedactionPattern is a named regular expression to redact
+type RedactionPattern struct {
+	Name    string `yaml:"name"`
+	Pattern string `yaml:"pattern"`
 }
 
 // Load reads the config file
@@ -165,7 +316,7 @@ func Save(cfg *Config) error {
 // EnsureDirectories creates the Ugudu directory structure
 func EnsureDirectories() error {
 	dirs := []string{
-		UguduHome(),
+		ConfigDir(),
 		SpecsDir(),
 		DataDir(),
 		ProjectsDir(),
@@ -181,23 +332,46 @@ func EnsureDirectories() error {
 }
 
 // ApplyToEnvironment sets environment variables from config
-// This allows the provider registry to auto-discover from config
-func (c *Config) ApplyToEnvironment() {
-	if c.Providers.Anthropic.APIKey != "" && os.Getenv("ANTHROPIC_API_KEY") == "" {
-		os.Setenv("ANTHROPIC_API_KEY", c.Providers.Anthropic.APIKey)
+// This allows the provider registry to auto-discover from config.
+// Variables already in the environment are kept. Values that are secret
+// references are resolved but not exported; read them with Getenv. The
+// first reference that can't be resolved is returned once the rest are
+// applied.
+func (c *Config) ApplyToEnvironment() error {
+	type envVar struct{ name, value string }
+	p := c.Providers
+	vars := []envVar{
+		{"ANTHROPIC_API_KEY", p.Anthropic.APIKey},
+		{"ANTHROPIC_BASE_URL", p.Anthropic.BaseURL},
+		{"OPENAI_API_KEY", p.OpenAI.APIKey},
+		{"OPENAI_BASE_URL", p.OpenAI.BaseURL},
+		{"GROQ_API_KEY", p.Groq.APIKey},
+		{"GROQ_BASE_URL", p.Groq.BaseURL},
+		{"OLLAMA_URL", p.Ollama.URL},
+		{"OPENROUTER_API_KEY", p.OpenRouter.APIKey},
+		{"OPENROUTER_BASE_URL", p.OpenRouter.BaseURL},
+		{"OPENROUTER_SITE_NAME", p.OpenRouter.SiteName},
+		{"OPENROUTER_SITE_URL", p.OpenRouter.SiteURL},
+		{"OPENROUTER_PROVIDER_ORDER", strings.Join(p.OpenRouter.Provider.Order, ",")},
+		{"OPENROUTER_DATA_COLLECTION", p.OpenRouter.Provider.DataCollection},
 	}
-	if c.Providers.OpenAI.APIKey != "" && os.Getenv("OPENAI_API_KEY") == "" {
-		os.Setenv("OPENAI_API_KEY", c.Providers.OpenAI.APIKey)
+	if p.Anthropic.MaxConcurrency > 0 {
+		vars = append(vars, envVar{"ANTHROPIC_MAX_CONCURRENCY", strconv.Itoa(p.Anthropic.MaxConcurrency)})
 	}
-	if c.Providers.Groq.APIKey != "" && os.Getenv("GROQ_API_KEY") == "" {
-		os.Setenv("GROQ_API_KEY", c.Providers.Groq.APIKey)
+	if p.Anthropic.PromptCaching {
+		vars = append(vars, envVar{"ANTHROPIC_PROMPT_CACHING", "true"})
 	}
-	if c.Providers.Ollama.URL != "" && os.Getenv("OLLAMA_URL") == "" {
-		os.Setenv("OLLAMA_URL", c.Providers.Ollama.URL)
+	if p.OpenRouter.Provider.AllowFallbacks != nil {
+		vars = append(vars, envVar{"OPENROUTER_ALLOW_FALLBACKS", strconv.FormatBool(*p.OpenRouter.Provider.AllowFallbacks)})
 	}
-	if c.Providers.OpenRouter.APIKey != "" && os.Getenv("OPENROUTER_API_KEY") == "" {
-		os.Setenv("OPENROUTER_API_KEY", c.Providers.OpenRouter.APIKey)
+
+	var firstErr error
+	for _, v := range vars {
+		if err := setenv(v.name, v.value); err != nil && firstErr == nil {
+			firstErr = err
+		}
 	}
+	return firstErr
 }
 
 // DefaultConfig returns a config with example values (commented out)
@@ -1,8 +1,10 @@
 package config
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -59,6 +61,74 @@ func TestConfigPath(t *testing.T) {
 	}
 }
 
+func TestXDGDirs(t *testing.T) {
+	os.Unsetenv("UGUDU_HOME")
+	t.Setenv("HOME", t.TempDir())
+	dataHome := t.TempDir()
+	configHome := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", dataHome)
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	t.Setenv("XDG_RUNTIME_DIR", "")
+
+	if dir := DataDir(); dir != filepath.Join(dataHome, "ugudu", "data") {
+		t.Errorf("Expected the data dir under XDG_DATA_HOME, got %s", dir)
+	}
+	if dir := SpecsDir(); dir != filepath.Join(dataHome, "ugudu", "specs") {
+		t.Errorf("Expected the specs dir under XDG_DATA_HOME, got %s", dir)
+	}
+	if path := ConfigPath(); path != filepath.Join(configHome, "ugudu", "config.yaml") {
+		t.Errorf("Expected the config under XDG_CONFIG_HOME, got %s", path)
+	}
+	if path := SocketPath(); path != filepath.Join(UguduHome(), "ugudu.sock") {
+		t.Errorf("Expected the socket in ~/.ugudu without XDG_RUNTIME_DIR, got %s", path)
+	}
+
+	// UGUDU_HOME keeps everything in one place
+	t.Setenv("UGUDU_HOME", "/tmp/ugudu-test")
+	if dir := DataDir(); dir != "/tmp/ugudu-test/data" {
+		t.Errorf("Expected UGUDU_HOME to win over XDG_DATA_HOME, got %s", dir)
+	}
+
+	// Relative XDG paths are invalid and ignored
+	os.Unsetenv("UGUDU_HOME")
+	t.Setenv("XDG_DATA_HOME", "relative/data")
+	if dir := SpecsDir(); dir != filepath.Join(UguduHome(), "specs") {
+		t.Errorf("Expected a relative XDG_DATA_HOME ignored, got %s", dir)
+	}
+}
+
+func TestXDGDirsKeepExistingHome(t *testing.T) {
+	os.Unsetenv("UGUDU_HOME")
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	dataHome := t.TempDir()
+	configHome := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", dataHome)
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+
+	// Both ~/.ugudu and the XDG directories exist, but only ~/.ugudu has
+	// specs and a config, so they stay where they are
+	legacy := filepath.Join(home, ".ugudu")
+	os.MkdirAll(filepath.Join(legacy, "specs"), 0755)
+	os.WriteFile(filepath.Join(legacy, "config.yaml"), []byte("defaults: {}\n"), 0644)
+	os.MkdirAll(filepath.Join(dataHome, "ugudu"), 0755)
+	os.MkdirAll(filepath.Join(configHome, "ugudu"), 0755)
+
+	if dir := SpecsDir(); dir != filepath.Join(legacy, "specs") {
+		t.Errorf("Expected the existing specs in ~/.ugudu kept, got %s", dir)
+	}
+	if path := ConfigPath(); path != filepath.Join(legacy, "config.yaml") {
+		t.Errorf("Expected the existing config in ~/.ugudu kept, got %s", path)
+	}
+
+	// Each directory is decided on its own: specs in ~/.ugudu don't keep the
+	// config there
+	os.Remove(filepath.Join(legacy, "config.yaml"))
+	if path := ConfigPath(); path != filepath.Join(configHome, "ugudu", "config.yaml") {
+		t.Errorf("Expected the config under XDG_CONFIG_HOME, got %s", path)
+	}
+}
+
 func TestEnsureDirectories(t *testing.T) {
 	// Use temp directory
 	tmpDir := t.TempDir()
@@ -140,10 +210,11 @@ func TestApplyToEnvironment(t *testing.T) {
 	// Clear any existing env vars
 	os.Unsetenv("ANTHROPIC_API_KEY")
 	os.Unsetenv("OPENAI_API_KEY")
+	t.Setenv("ANTHROPIC_BASE_URL", "")
 
 	cfg := &Config{
 		Providers: ProvidersConfig{
-			Anthropic: AnthropicConfig{APIKey: "test-anthropic-key"},
+			Anthropic: AnthropicConfig{APIKey: "test-anthropic-key", BaseURL: "https://gateway.example.com/anthropic"},
 			OpenAI:    OpenAIConfig{APIKey: "test-openai-key"},
 		},
 	}
@@ -158,6 +229,10 @@ func TestApplyToEnvironment(t *testing.T) {
 		t.Error("OPENAI_API_KEY not set correctly")
 	}
 
+	if os.Getenv("ANTHROPIC_BASE_URL") != "https://gateway.example.com/anthropic" {
+		t.Error("ANTHROPIC_BASE_URL not set correctly")
+	}
+
 	// Cleanup
 	os.Unsetenv("ANTHROPIC_API_KEY")
 	os.Unsetenv("OPENAI_API_KEY")
@@ -182,6 +257,70 @@ func TestApplyToEnvironmentNoOverwrite(t *testing.T) {
 	}
 }
 
+func TestApplyToEnvironment_SecretFile(t *testing.T) {
+	t.Setenv("GROQ_API_KEY", "")
+	t.Cleanup(func() {
+		resolved.Lock()
+		delete(resolved.vars, "GROQ_API_KEY")
+		resolved.Unlock()
+	})
+
+	keyFile := filepath.Join(t.TempDir(), "groq.key")
+	os.WriteFile(keyFile, []byte("gsk-from-file\n"), 0600)
+
+	cfg := &Config{Providers: ProvidersConfig{Groq: GroqConfig{APIKey: "${file:" + keyFile + "}"}}}
+	if err := cfg.ApplyToEnvironment(); err != nil {
+		t.Fatalf("ApplyToEnvironment failed: %v", err)
+	}
+
+	if got := Getenv("GROQ_API_KEY"); got != "gsk-from-file" {
+		t.Errorf("Expected the key read from the file, got %q", got)
+	}
+	if got := os.Getenv("GROQ_API_KEY"); got != "" {
+		t.Errorf("Expected the key kept out of the environment, got %q", got)
+	}
+
+	cfg.Providers.Groq.APIKey = "${file:" + filepath.Join(t.TempDir(), "missing") + "}"
+	if err := cfg.ApplyToEnvironment(); err == nil || !strings.Contains(err.Error(), "GROQ_API_KEY") {
+		t.Errorf("Expected an error naming the variable for a missing file, got %v", err)
+	}
+}
+
+func TestExpand_SecretReferences(t *testing.T) {
+	keyring := map[string]string{"ugudu/openai": "sk-from-keyring"}
+	orig := keyringLookup
+	keyringLookup = func(service, account string) (string, error) {
+		if secret, ok := keyring[service+"/"+account]; ok {
+			return secret, nil
+		}
+		return "", errors.New("not found")
+	}
+	defer func() { keyringLookup = orig }()
+
+	keyFile := filepath.Join(t.TempDir(), "token")
+	os.WriteFile(keyFile, []byte("tok-from-file"), 0600)
+	t.Setenv("UGUDU_TEST_REGION", "eu-west-1")
+
+	got, err := Expand("key: ${keyring:ugudu/openai}\ntoken: ${file:" + keyFile + "}\nregion: $UGUDU_TEST_REGION/${UGUDU_TEST_REGION}\n")
+	if err != nil {
+		t.Fatalf("Expand failed: %v", err)
+	}
+	want := "key: sk-from-keyring\ntoken: tok-from-file\nregion: eu-west-1/eu-west-1\n"
+	if got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+
+	for _, s := range []string{"${keyring:ugudu/nobody}", "${keyring:no-account}", "${file:" + keyFile + ".missing}"} {
+		if _, err := Expand(s); err == nil {
+			t.Errorf("Expected an error expanding %s", s)
+		}
+	}
+
+	if plain, err := ResolveSecret("sk-plain"); err != nil || plain != "sk-plain" {
+		t.Errorf("Expected a plain value returned as is, got %q, %v", plain, err)
+	}
+}
+
 func TestDefaultConfig(t *testing.T) {
 	cfg := DefaultConfig()
 
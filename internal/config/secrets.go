@@ -0,0 +1,166 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// Secret references
+//
+// A config value or spec can name a secret rather than hold it:
+// ${file:/path/to/key} reads a file, with a leading ~/ meaning the home
+// directory, and ${keyring:service/account} asks the OS keyring (macOS
+// Keychain, or the Secret Service through secret-tool elsewhere). Provider
+// keys read this way stay inside the process instead of being exported, so
+// the commands members run don't inherit them.
+
+var secretRef = regexp.MustCompile(`^\$\{((?:file|keyring):[^}]+)\}$`)
+
+// keyringLookup reads a secret from the OS keyring; tests replace it
+var keyringLookup = lookupKeyring
+
+// resolved holds the provider variables ApplyToEnvironment resolved from
+// secret references, in place of the environment
+var resolved = struct {
+	sync.RWMutex
+	vars map[string]string
+}{vars: make(map[string]string)}
+
+// IsSecretRef reports whether a whole value is a ${file:...} or
+// ${keyring:...} reference
+func IsSecretRef(s string) bool {
+	return secretRef.MatchString(strings.TrimSpace(s))
+}
+
+// ResolveSecret returns the secret s refers to, or s unchanged if it isn't
+// a reference
+func ResolveSecret(s string) (string, error) {
+	m := secretRef.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return s, nil
+	}
+	return resolveRef(m[1])
+}
+
+// Getenv returns an environment variable, falling back to the provider
+// variables the config resolved from secret references. The environment
+// wins, as it does in ApplyToEnvironment.
+func Getenv(name string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	resolved.RLock()
+	defer resolved.RUnlock()
+	return resolved.vars[name]
+}
+
+// Expand replaces ${VAR} and $VAR in s like os.ExpandEnv, using Getenv, and
+// ${file:...} and ${keyring:...} with the secrets they name
+func Expand(s string) (string, error) {
+	var firstErr error
+	out := os.Expand(s, func(name string) string {
+		if !strings.HasPrefix(name, "file:") && !strings.HasPrefix(name, "keyring:") {
+			return Getenv(name)
+		}
+		secret, err := resolveRef(name)
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+		return secret
+	})
+	if firstErr != nil {
+		return "", firstErr
+	}
+	return out, nil
+}
+
+// resolveRef reads the secret a reference names, given without its ${ }
+func resolveRef(ref string) (string, error) {
+	kind, arg := ref, ""
+	if i := strings.IndexByte(ref, ':'); i >= 0 {
+		kind, arg = ref[:i], strings.TrimSpace(ref[i+1:])
+	}
+
+	var secret string
+	switch kind {
+	case "file":
+		path := arg
+		if strings.HasPrefix(path, "~/") {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return "", fmt.Errorf("secret file %s: %w", arg, err)
+			}
+			path = filepath.Join(home, path[2:])
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("read secret file: %w", err)
+		}
+		secret = strings.TrimRight(string(data), "\r\n")
+	case "keyring":
+		service, account, ok := strings.Cut(arg, "/")
+		if !ok || service == "" || account == "" {
+			return "", fmt.Errorf("keyring reference %q must be service/account", arg)
+		}
+		var err error
+		if secret, err = keyringLookup(service, account); err != nil {
+			return "", fmt.Errorf("keyring %s/%s: %w", service, account, err)
+		}
+	default:
+		return "", fmt.Errorf("unknown secret reference %q", ref)
+	}
+
+	if secret == "" {
+		return "", fmt.Errorf("secret %s is empty", ref)
+	}
+	return secret, nil
+}
+
+// lookupKeyring asks the OS keyring for a generic password
+func lookupKeyring(service, account string) (string, error) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("security", "find-generic-password", "-s", service, "-a", account, "-w")
+	case "windows":
+		return "", fmt.Errorf("keyring references aren't supported on windows")
+	default:
+		cmd = exec.Command("secret-tool", "lookup", "service", service, "account", account)
+	}
+
+	out, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("%s: %s", cmd.Args[0], strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", err
+	}
+	return strings.TrimRight(string(out), "\r\n"), nil
+}
+
+// setenv applies a config value to a provider variable unless the
+// environment already has one. A secret reference is resolved and kept for
+// Getenv rather than exported.
+func setenv(name, value string) error {
+	if value == "" || os.Getenv(name) != "" {
+		return nil
+	}
+	if !IsSecretRef(value) {
+		return os.Setenv(name, value)
+	}
+
+	secret, err := ResolveSecret(value)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	resolved.Lock()
+	resolved.vars[name] = secret
+	resolved.Unlock()
+	return nil
+}
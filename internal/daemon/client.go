@@ -9,9 +9,19 @@ import (
 	"io"
 	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/arcslash/ugudu/internal/config"
+	"github.com/arcslash/ugudu/internal/logger"
+	"github.com/arcslash/ugudu/internal/manager"
+	"github.com/arcslash/ugudu/internal/team"
+	"github.com/arcslash/ugudu/internal/version"
+	"github.com/gorilla/websocket"
 )
 
 // Client connects to the Ugudu daemon
@@ -25,7 +35,7 @@ type Client struct {
 func NewClient(socketPath string) (*Client, error) {
 	// Determine socket path
 	if socketPath == "" {
-		socketPath = findSocket()
+		socketPath = FindSocket()
 	}
 
 	if socketPath == "" {
@@ -57,8 +67,10 @@ func NewRemoteClient(addr string) *Client {
 	}
 }
 
-// findSocket looks for the daemon socket in common locations
-func findSocket() string {
+// FindSocket looks for the daemon socket in common locations: $UGUDU_SOCKET,
+// the system path, the user's runtime dir (see config.SocketPath), then
+// ~/.ugudu. It returns "" if there's none.
+func FindSocket() string {
 	// Check environment variable first
 	if sock := os.Getenv("UGUDU_SOCKET"); sock != "" {
 		if _, err := os.Stat(sock); err == nil {
@@ -71,7 +83,13 @@ func findSocket() string {
 		return DefaultSocketPath
 	}
 
-	// Check user home
+	// Check the user's runtime dir
+	if _, err := os.Stat(config.SocketPath()); err == nil {
+		return config.SocketPath()
+	}
+
+	// Check user home, where a daemon started without XDG_RUNTIME_DIR or
+	// UGUDU_HOME puts it
 	if home, err := os.UserHomeDir(); err == nil {
 		userSock := filepath.Join(home, UserSocketPath)
 		if _, err := os.Stat(userSock); err == nil {
@@ -96,6 +114,46 @@ func (c *Client) Ping(ctx context.Context) error {
 	return nil
 }
 
+// Version returns the daemon's build
+func (c *Client) Version(ctx context.Context) (version.Info, error) {
+	resp, err := c.get(ctx, "/api/version")
+	if err != nil {
+		return version.Info{}, err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		version.Info
+		Error interface{} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return version.Info{}, err
+	}
+	if err := responseError(resp.StatusCode, result.Error); err != nil {
+		return version.Info{}, err
+	}
+	return result.Info, nil
+}
+
+// HealthDeep runs the daemon's deep health check, pinging the store and
+// every provider. An unhealthy daemon still returns its report.
+func (c *Client) HealthDeep(ctx context.Context) (map[string]interface{}, error) {
+	resp, err := c.get(ctx, "/api/health?deep=1")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	if err := responseError(resp.StatusCode, result["error"]); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 // Status returns daemon and manager status
 func (c *Client) Status(ctx context.Context) (map[string]interface{}, error) {
 	resp, err := c.get(ctx, "/api/status")
@@ -130,7 +188,24 @@ func (c *Client) ListTeams(ctx context.Context) ([]map[string]interface{}, error
 
 // CreateTeam creates a team from a spec file
 func (c *Client) CreateTeam(ctx context.Context, specPath string) (map[string]interface{}, error) {
-	body := map[string]string{"spec_path": specPath}
+	return c.CreateSeededTeam(ctx, specPath, "", "", false)
+}
+
+// CreateSeededTeam creates a team whose members start with background: seed
+// text and/or the client transcript of an earlier conversation. With force a
+// team of the same name is replaced; otherwise creating it fails with
+// ErrConflict.
+func (c *Client) CreateSeededTeam(ctx context.Context, specPath, seed, seedConversation string, force bool) (map[string]interface{}, error) {
+	body := map[string]interface{}{"spec_path": specPath}
+	if seed != "" {
+		body["seed"] = seed
+	}
+	if seedConversation != "" {
+		body["seed_conversation"] = seedConversation
+	}
+	if force {
+		body["force"] = true
+	}
 	resp, err := c.post(ctx, "/api/teams", body)
 	if err != nil {
 		return nil, err
@@ -142,8 +217,8 @@ func (c *Client) CreateTeam(ctx context.Context, specPath string) (map[string]in
 		return nil, err
 	}
 
-	if errMsg, ok := result["error"].(string); ok && errMsg != "" {
-		return nil, fmt.Errorf("%s", errMsg)
+	if err := responseError(resp.StatusCode, result["error"]); err != nil {
+		return nil, err
 	}
 
 	return result, nil
@@ -162,8 +237,8 @@ func (c *Client) GetTeam(ctx context.Context, name string) (map[string]interface
 		return nil, err
 	}
 
-	if errMsg, ok := result["error"].(string); ok && errMsg != "" {
-		return nil, fmt.Errorf("%s", errMsg)
+	if err := responseError(resp.StatusCode, result["error"]); err != nil {
+		return nil, err
 	}
 
 	return result, nil
@@ -182,8 +257,8 @@ func (c *Client) StartTeam(ctx context.Context, name string) error {
 		return err
 	}
 
-	if errMsg, ok := result["error"].(string); ok && errMsg != "" {
-		return fmt.Errorf("%s", errMsg)
+	if err := responseError(resp.StatusCode, result["error"]); err != nil {
+		return err
 	}
 
 	return nil
@@ -202,13 +277,45 @@ func (c *Client) StopTeam(ctx context.Context, name string) error {
 		return err
 	}
 
-	if errMsg, ok := result["error"].(string); ok && errMsg != "" {
-		return fmt.Errorf("%s", errMsg)
+	if err := responseError(resp.StatusCode, result["error"]); err != nil {
+		return err
 	}
 
 	return nil
 }
 
+// RestartTeam stops a team, cancelling any request in progress, and starts
+// it again with its saved context
+func (c *Client) RestartTeam(ctx context.Context, name string) error {
+	return c.teamAction(ctx, name, "restart")
+}
+
+// PauseTeam stops a team accepting new asks
+func (c *Client) PauseTeam(ctx context.Context, name string) error {
+	return c.teamAction(ctx, name, "pause")
+}
+
+// ResumeTeam lets a paused team accept asks again
+func (c *Client) ResumeTeam(ctx context.Context, name string) error {
+	return c.teamAction(ctx, name, "resume")
+}
+
+// teamAction posts to a team's action endpoint, e.g. /api/teams/{name}/pause
+func (c *Client) teamAction(ctx context.Context, name, action string) error {
+	resp, err := c.post(ctx, "/api/teams/"+name+"/"+action, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var result map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return err
+	}
+
+	return responseError(resp.StatusCode, result["error"])
+}
+
 // DeleteTeam deletes a team
 func (c *Client) DeleteTeam(ctx context.Context, name string) error {
 	req, err := http.NewRequestWithContext(ctx, "DELETE", c.baseURL+"/api/teams/"+name, nil)
@@ -227,8 +334,8 @@ func (c *Client) DeleteTeam(ctx context.Context, name string) error {
 		return err
 	}
 
-	if errMsg, ok := result["error"].(string); ok && errMsg != "" {
-		return fmt.Errorf("%s", errMsg)
+	if err := responseError(resp.StatusCode, result["error"]); err != nil {
+		return err
 	}
 
 	return nil
@@ -253,6 +360,13 @@ func (c *Client) TeamMembers(ctx context.Context, name string) ([]map[string]int
 
 // Chat sends a message to a team
 func (c *Client) Chat(ctx context.Context, team, message, to string) ([]map[string]interface{}, error) {
+	return c.ChatWithVerbosity(ctx, team, message, to, "")
+}
+
+// ChatWithVerbosity sends a message to a team. With verbosity "full" the
+// responses also include the team's internal work, marked "internal": true.
+// Progress updates, marked "progress": true, are left out with "quiet".
+func (c *Client) ChatWithVerbosity(ctx context.Context, team, message, to, verbosity string) ([]map[string]interface{}, error) {
 	body := map[string]string{
 		"team":    team,
 		"message": message,
@@ -260,6 +374,9 @@ func (c *Client) Chat(ctx context.Context, team, message, to string) ([]map[stri
 	if to != "" {
 		body["to"] = to
 	}
+	if verbosity != "" {
+		body["verbosity"] = verbosity
+	}
 
 	resp, err := c.post(ctx, "/api/chat", body)
 	if err != nil {
@@ -269,19 +386,322 @@ func (c *Client) Chat(ctx context.Context, team, message, to string) ([]map[stri
 
 	var result struct {
 		Responses []map[string]interface{} `json:"responses"`
-		Error     string                   `json:"error,omitempty"`
+		Error     interface{}              `json:"error,omitempty"`
 	}
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
 		return nil, err
 	}
 
-	if result.Error != "" {
-		return nil, fmt.Errorf("%s", result.Error)
+	if err := responseError(resp.StatusCode, result.Error); err != nil {
+		return nil, err
 	}
 
 	return result.Responses, nil
 }
 
+// DiffTeams compares the specs two teams were created from
+func (c *Client) DiffTeams(ctx context.Context, a, b string) (*team.SpecDiff, error) {
+	resp, err := c.get(ctx, "/api/teams/"+a+"/diff/"+b)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		team.SpecDiff
+		Error interface{} `json:"error,omitempty"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	if err := responseError(resp.StatusCode, result.Error); err != nil {
+		return nil, err
+	}
+	return &result.SpecDiff, nil
+}
+
+// MemberPrompt returns the system prompt and tools a member is given
+func (c *Client) MemberPrompt(ctx context.Context, teamName, memberID string) (*team.MemberPrompt, error) {
+	resp, err := c.get(ctx, "/api/teams/"+teamName+"/members/"+memberID+"/prompt")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		team.MemberPrompt
+		Error interface{} `json:"error,omitempty"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	if err := responseError(resp.StatusCode, result.Error); err != nil {
+		return nil, err
+	}
+	return &result.MemberPrompt, nil
+}
+
+// TeamPerformance summarizes the latency and outcome of a team's model
+// calls, those since since if it isn't zero
+func (c *Client) TeamPerformance(ctx context.Context, name string, since time.Time) (*manager.TeamPerformance, error) {
+	path := "/api/teams/" + name + "/performance"
+	if !since.IsZero() {
+		path += "?since=" + url.QueryEscape(since.Format(time.RFC3339))
+	}
+	resp, err := c.get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		manager.TeamPerformance
+		Error interface{} `json:"error,omitempty"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	if err := responseError(resp.StatusCode, result.Error); err != nil {
+		return nil, err
+	}
+	return &result.TeamPerformance, nil
+}
+
+// BenchTeam asks a team a standard prompt opts.Iterations times and reports
+// its latency and model calls
+func (c *Client) BenchTeam(ctx context.Context, name string, opts manager.BenchOptions) (*manager.BenchReport, error) {
+	body := struct {
+		manager.BenchOptions
+		TimeoutSeconds int `json:"timeout_seconds,omitempty"`
+	}{opts, int(opts.Timeout.Seconds())}
+	resp, err := c.post(ctx, "/api/teams/"+name+"/bench", body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		manager.BenchReport
+		Error interface{} `json:"error,omitempty"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	if err := responseError(resp.StatusCode, result.Error); err != nil {
+		return nil, err
+	}
+	return &result.BenchReport, nil
+}
+
+// GetTeamSpec returns the YAML spec a running team is using, with
+// inheritance resolved and defaults filled in. Unless globalPrompt is set,
+// the daemon's global prompt is left out, as for saving it as a new spec.
+func (c *Client) GetTeamSpec(ctx context.Context, name string, globalPrompt bool) (string, error) {
+	path := "/api/teams/" + name + "/spec"
+	if !globalPrompt {
+		path += "?global_prompt=false"
+	}
+	resp, err := c.get(ctx, path)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var result struct {
+			Error interface{} `json:"error"`
+		}
+		json.NewDecoder(resp.Body).Decode(&result)
+		if err := responseError(resp.StatusCode, result.Error); err != nil {
+			return "", err
+		}
+		return "", fmt.Errorf("daemon returned %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// ChatOptions are the optional settings for a streamed chat
+type ChatOptions struct {
+	To        string        // Role to send to; empty for the client-facing member
+	Verbosity string        // "quiet", "summary" (default) or "full"
+	Timeout   time.Duration // How long the daemon waits before giving up; zero for its default
+	NoTools   bool          // Answer without offering the model any tools
+}
+
+// ChatOutcome reports how a streamed chat ended
+type ChatOutcome struct {
+	TimedOut bool
+
+	// Set when the daemon timed out while the team was still working; pass
+	// it to ContinueChat to pick up the rest
+	ContinuationToken string
+
+	// The continued ask had expired, so the team was asked to resume from
+	// its saved conversation instead
+	Restarted bool
+}
+
+// ChatStream sends a message to a team and calls onResponse for each
+// response as it arrives instead of waiting for the whole exchange. It
+// returns once the team is done or the daemon gives up waiting. Cancelling
+// ctx abandons the turn.
+func (c *Client) ChatStream(ctx context.Context, team, message string, opts ChatOptions, onResponse func(map[string]interface{})) (ChatOutcome, error) {
+	body := map[string]interface{}{
+		"team":    team,
+		"message": message,
+		"stream":  true,
+	}
+	if opts.To != "" {
+		body["to"] = opts.To
+	}
+	if opts.Verbosity != "" {
+		body["verbosity"] = opts.Verbosity
+	}
+	if opts.Timeout > 0 {
+		body["timeout_seconds"] = int(opts.Timeout.Seconds())
+	}
+	if opts.NoTools {
+		body["no_tools"] = true
+	}
+
+	return c.stream(ctx, "/api/chat", body, onResponse)
+}
+
+// ContinueChat reattaches to a chat that timed out, streaming the responses
+// the team produced since and any that follow. opts.To and opts.Verbosity
+// only matter if the token has expired and the team has to be re-asked.
+func (c *Client) ContinueChat(ctx context.Context, team, token string, opts ChatOptions, onResponse func(map[string]interface{})) (ChatOutcome, error) {
+	body := map[string]interface{}{
+		"token":  token,
+		"stream": true,
+	}
+	if opts.To != "" {
+		body["to"] = opts.To
+	}
+	if opts.Verbosity != "" {
+		body["verbosity"] = opts.Verbosity
+	}
+	if opts.Timeout > 0 {
+		body["timeout_seconds"] = int(opts.Timeout.Seconds())
+	}
+
+	return c.stream(ctx, "/api/teams/"+team+"/continue", body, onResponse)
+}
+
+// BulkChatResult is one team's answer to a bulk chat
+type BulkChatResult struct {
+	Responses []map[string]interface{}
+
+	// Set when asking this team failed; the other teams are unaffected
+	Err error
+
+	// Set when the daemon stopped waiting for this team; pass the token to
+	// ContinueChat to pick up the rest
+	TimedOut          bool
+	ContinuationToken string
+}
+
+// ChatBulk asks several teams the same question at once and returns each
+// team's responses by name. opts.Timeout applies to each team separately.
+// One team failing doesn't fail the call; its result carries the error.
+func (c *Client) ChatBulk(ctx context.Context, teams []string, message string, opts ChatOptions) (map[string]BulkChatResult, error) {
+	body := map[string]interface{}{
+		"teams":   teams,
+		"message": message,
+	}
+	if opts.To != "" {
+		body["to"] = opts.To
+	}
+	if opts.Verbosity != "" {
+		body["verbosity"] = opts.Verbosity
+	}
+	if opts.Timeout > 0 {
+		body["timeout_seconds"] = int(opts.Timeout.Seconds())
+	}
+	if opts.NoTools {
+		body["no_tools"] = true
+	}
+
+	resp, err := c.post(ctx, "/api/chat/bulk", body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Results map[string]struct {
+			Responses         []map[string]interface{} `json:"responses"`
+			Error             interface{}              `json:"error,omitempty"`
+			Timeout           bool                     `json:"timeout"`
+			ContinuationToken string                   `json:"continuation_token"`
+		} `json:"results"`
+		Error interface{} `json:"error,omitempty"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	if err := responseError(resp.StatusCode, result.Error); err != nil {
+		return nil, err
+	}
+
+	results := make(map[string]BulkChatResult, len(result.Results))
+	for name, r := range result.Results {
+		results[name] = BulkChatResult{
+			Responses:         r.Responses,
+			Err:               responseError(resp.StatusCode, r.Error),
+			TimedOut:          r.Timeout,
+			ContinuationToken: r.ContinuationToken,
+		}
+	}
+	return results, nil
+}
+
+// stream posts a request answered with NDJSON responses and a final done line
+func (c *Client) stream(ctx context.Context, path string, body interface{}, onResponse func(map[string]interface{})) (ChatOutcome, error) {
+	var outcome ChatOutcome
+
+	resp, err := c.post(ctx, path, body)
+	if err != nil {
+		return outcome, err
+	}
+	defer resp.Body.Close()
+
+	// Failures before the stream starts come back as a plain JSON error
+	if resp.StatusCode != http.StatusOK {
+		var result map[string]interface{}
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			return outcome, fmt.Errorf("chat failed: %s", resp.Status)
+		}
+		if err := responseError(resp.StatusCode, result["error"]); err != nil {
+			return outcome, err
+		}
+		return outcome, &APIError{Status: resp.StatusCode, Code: CodeInternal, Message: "chat failed: " + resp.Status}
+	}
+
+	dec := json.NewDecoder(resp.Body)
+	for {
+		var entry map[string]interface{}
+		if err := dec.Decode(&entry); err != nil {
+			if err == io.EOF {
+				return outcome, fmt.Errorf("chat stream ended unexpectedly")
+			}
+			return outcome, err
+		}
+		if done, _ := entry["done"].(bool); done {
+			outcome.TimedOut, _ = entry["timeout"].(bool)
+			outcome.ContinuationToken, _ = entry["continuation_token"].(string)
+			outcome.Restarted, _ = entry["restarted"].(bool)
+			return outcome, nil
+		}
+		onResponse(entry)
+	}
+}
+
 // ListProviders returns available providers
 func (c *Client) ListProviders(ctx context.Context) ([]map[string]interface{}, error) {
 	resp, err := c.get(ctx, "/api/providers")
@@ -312,15 +732,39 @@ func (c *Client) TestProvider(ctx context.Context, id string) error {
 		return err
 	}
 
-	if status, ok := result["status"].(string); ok && status == "error" {
-		if errMsg, ok := result["error"].(string); ok {
-			return fmt.Errorf("%s", errMsg)
+	if err := responseError(resp.StatusCode, result["error"]); err != nil {
+		// The daemon reached the provider but the provider failed. Older
+		// daemons send a bare message, without a code saying so.
+		if status, _ := result["status"].(string); status == "error" {
+			if message, ok := result["error"].(string); ok {
+				return &APIError{Status: resp.StatusCode, Code: CodeProviderError, Message: message}
+			}
 		}
+		return err
 	}
 
 	return nil
 }
 
+// ProviderStats returns a provider's queue depth, rate limit, concurrency,
+// recent error rate and estimated time to serve a new request
+func (c *Client) ProviderStats(ctx context.Context, id string) (map[string]interface{}, error) {
+	resp, err := c.get(ctx, "/api/providers/"+id+"/stats")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	if err := responseError(resp.StatusCode, result["error"]); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 // ProviderModels returns available models for a provider
 func (c *Client) ProviderModels(ctx context.Context, id string) ([]string, error) {
 	resp, err := c.get(ctx, "/api/providers/"+id+"/models")
@@ -343,6 +787,74 @@ func (c *Client) GetSocketPath() string {
 	return c.socketPath
 }
 
+// ============================================================================
+// Spec Methods
+// ============================================================================
+
+// AddSpecialist adds a specialist role template to a spec
+func (c *Client) AddSpecialist(ctx context.Context, spec, specialist string) (map[string]interface{}, error) {
+	body := map[string]string{"specialist": specialist}
+	resp, err := c.post(ctx, "/api/specs/"+spec+"/specialists", body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+
+	if err := responseError(resp.StatusCode, result["error"]); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ListSpecVersions returns a spec's saved versions, newest first
+func (c *Client) ListSpecVersions(ctx context.Context, spec string) ([]map[string]interface{}, error) {
+	resp, err := c.get(ctx, "/api/specs/"+spec+"/versions")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Versions []map[string]interface{} `json:"versions"`
+		Error    interface{}              `json:"error,omitempty"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+
+	if err := responseError(resp.StatusCode, result.Error); err != nil {
+		return nil, err
+	}
+
+	return result.Versions, nil
+}
+
+// RestoreSpecVersion makes a saved version of a spec the current one
+func (c *Client) RestoreSpecVersion(ctx context.Context, spec string, version int) (map[string]interface{}, error) {
+	resp, err := c.post(ctx, fmt.Sprintf("/api/specs/%s/versions/%d/restore", spec, version), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+
+	if err := responseError(resp.StatusCode, result["error"]); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
 // ============================================================================
 // Project Methods
 // ============================================================================
@@ -386,8 +898,8 @@ func (c *Client) CreateProject(ctx context.Context, name, sourcePath, team strin
 		return nil, err
 	}
 
-	if errMsg, ok := result["error"].(string); ok && errMsg != "" {
-		return nil, fmt.Errorf("%s", errMsg)
+	if err := responseError(resp.StatusCode, result["error"]); err != nil {
+		return nil, err
 	}
 
 	return result, nil
@@ -406,8 +918,8 @@ func (c *Client) GetProject(ctx context.Context, name string) (map[string]interf
 		return nil, err
 	}
 
-	if errMsg, ok := result["error"].(string); ok && errMsg != "" {
-		return nil, fmt.Errorf("%s", errMsg)
+	if err := responseError(resp.StatusCode, result["error"]); err != nil {
+		return nil, err
 	}
 
 	return result, nil
@@ -431,8 +943,8 @@ func (c *Client) DeleteProject(ctx context.Context, name string) error {
 		return err
 	}
 
-	if errMsg, ok := result["error"].(string); ok && errMsg != "" {
-		return fmt.Errorf("%s", errMsg)
+	if err := responseError(resp.StatusCode, result["error"]); err != nil {
+		return err
 	}
 
 	return nil
@@ -456,8 +968,8 @@ func (c *Client) ProjectStandup(ctx context.Context, name, period string) (map[s
 		return nil, err
 	}
 
-	if errMsg, ok := result["error"].(string); ok && errMsg != "" {
-		return nil, fmt.Errorf("%s", errMsg)
+	if err := responseError(resp.StatusCode, result["error"]); err != nil {
+		return nil, err
 	}
 
 	return result, nil
@@ -510,6 +1022,133 @@ func (c *Client) ProjectTasks(ctx context.Context, name string) ([]map[string]in
 	return result.Tasks, nil
 }
 
+// ProjectArtifacts lists the files a team produced in a project. Deleted
+// ones are marked stale and have no download url.
+func (c *Client) ProjectArtifacts(ctx context.Context, name string) ([]map[string]interface{}, error) {
+	resp, err := c.get(ctx, "/api/projects/"+name+"/artifacts")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Artifacts []map[string]interface{} `json:"artifacts"`
+		Error     interface{}              `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+
+	if err := responseError(resp.StatusCode, result.Error); err != nil {
+		return nil, err
+	}
+
+	return result.Artifacts, nil
+}
+
+// DownloadArtifact copies a project artifact's contents to w
+func (c *Client) DownloadArtifact(ctx context.Context, name, artifactID string, w io.Writer) error {
+	resp, err := c.get(ctx, "/api/projects/"+name+"/artifacts/"+artifactID)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var result struct {
+			Error interface{} `json:"error"`
+		}
+		json.NewDecoder(resp.Body).Decode(&result)
+		if err := responseError(resp.StatusCode, result.Error); err != nil {
+			return err
+		}
+		return fmt.Errorf("daemon returned %s", resp.Status)
+	}
+
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
+
+// AuditLog returns the audit entries matching filter, oldest first
+func (c *Client) AuditLog(ctx context.Context, filter manager.AuditFilter) ([]manager.AuditEntry, error) {
+	q := url.Values{}
+	for name, value := range map[string]string{
+		"team":       filter.Team,
+		"action":     filter.Action,
+		"actor":      filter.Actor,
+		"request_id": filter.RequestID,
+	} {
+		if value != "" {
+			q.Set(name, value)
+		}
+	}
+	if !filter.Since.IsZero() {
+		q.Set("since", filter.Since.Format(time.RFC3339))
+	}
+	if !filter.Until.IsZero() {
+		q.Set("until", filter.Until.Format(time.RFC3339))
+	}
+	if filter.Limit > 0 {
+		q.Set("limit", strconv.Itoa(filter.Limit))
+	}
+
+	resp, err := c.get(ctx, "/api/audit?"+q.Encode())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Entries []manager.AuditEntry `json:"entries"`
+		Error   interface{}          `json:"error,omitempty"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	if err := responseError(resp.StatusCode, result.Error); err != nil {
+		return nil, err
+	}
+	return result.Entries, nil
+}
+
+// Logs returns the daemon's recent log records matching filter, oldest first
+func (c *Client) Logs(ctx context.Context, filter logger.Filter) ([]logger.Record, error) {
+	q := url.Values{}
+	if filter.Level != logger.LevelDebug {
+		q.Set("level", filter.Level.String())
+	}
+	if filter.Team != "" {
+		q.Set("team", filter.Team)
+	}
+	if !filter.Since.IsZero() {
+		q.Set("since", filter.Since.Format(time.RFC3339))
+	}
+	if filter.After > 0 {
+		q.Set("after", strconv.FormatUint(filter.After, 10))
+	}
+	if filter.Limit > 0 {
+		q.Set("limit", strconv.Itoa(filter.Limit))
+	}
+
+	resp, err := c.get(ctx, "/api/logs?"+q.Encode())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Records []logger.Record `json:"records"`
+		Error   interface{}     `json:"error,omitempty"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	if err := responseError(resp.StatusCode, result.Error); err != nil {
+		return nil, err
+	}
+	return result.Records, nil
+}
+
 // ============================================================================
 // Conversation Methods
 // ============================================================================
@@ -532,6 +1171,53 @@ func (c *Client) ListConversations(ctx context.Context, teamName string, limit i
 	return result.Conversations, nil
 }
 
+// RenameConversation sets a conversation's title
+func (c *Client) RenameConversation(ctx context.Context, conversationID, title string) error {
+	data, err := json.Marshal(map[string]string{"title": title})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, "PATCH", c.baseURL+"/api/conversations/"+conversationID, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var result map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return err
+	}
+	return responseError(resp.StatusCode, result["error"])
+}
+
+// SummarizeConversation returns a summary of a conversation's decisions,
+// open questions and artifacts
+func (c *Client) SummarizeConversation(ctx context.Context, conversationID string) (*manager.ConversationSummary, error) {
+	resp, err := c.get(ctx, "/api/conversations/"+conversationID+"/summary")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		manager.ConversationSummary
+		Error interface{} `json:"error,omitempty"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	if err := responseError(resp.StatusCode, result.Error); err != nil {
+		return nil, err
+	}
+	return &result.ConversationSummary, nil
+}
+
 // GetConversationHistory returns messages from a conversation
 func (c *Client) GetConversationHistory(ctx context.Context, conversationID string) ([]map[string]interface{}, error) {
 	resp, err := c.get(ctx, "/api/conversations/"+conversationID)
@@ -549,6 +1235,28 @@ func (c *Client) GetConversationHistory(ctx context.Context, conversationID stri
 	return result.Messages, nil
 }
 
+// ClearMemberContext clears one member's conversation context, leaving the
+// rest of the team's alone
+func (c *Client) ClearMemberContext(ctx context.Context, teamName, memberID string) error {
+	req, err := http.NewRequestWithContext(ctx, "DELETE", c.baseURL+"/api/teams/"+teamName+"/members/"+memberID+"/context", nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var result map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return err
+	}
+
+	return responseError(resp.StatusCode, result["error"])
+}
+
 // ClearConversation clears conversation history for a team
 func (c *Client) ClearConversation(ctx context.Context, teamName string) error {
 	req, err := http.NewRequestWithContext(ctx, "DELETE", c.baseURL+"/api/teams/"+teamName+"/conversations", nil)
@@ -567,17 +1275,19 @@ func (c *Client) ClearConversation(ctx context.Context, teamName string) error {
 		return err
 	}
 
-	if errMsg, ok := result["error"].(string); ok && errMsg != "" {
-		return fmt.Errorf("%s", errMsg)
+	if err := responseError(resp.StatusCode, result["error"]); err != nil {
+		return err
 	}
 
 	return nil
 }
 
-// SetTokenMode sets the token consumption mode for a team
-func (c *Client) SetTokenMode(ctx context.Context, teamName, mode string) error {
+// SetTokenMode sets the token consumption mode for a team. With persist
+// the team keeps it after the daemon restarts.
+func (c *Client) SetTokenMode(ctx context.Context, teamName, mode string, persist bool) error {
 	body := map[string]interface{}{
-		"mode": mode,
+		"mode":    mode,
+		"persist": persist,
 	}
 
 	resp, err := c.post(ctx, "/api/teams/"+teamName+"/token-mode", body)
@@ -591,8 +1301,8 @@ func (c *Client) SetTokenMode(ctx context.Context, teamName, mode string) error
 		return err
 	}
 
-	if errMsg, ok := result["error"].(string); ok && errMsg != "" {
-		return fmt.Errorf("%s", errMsg)
+	if err := responseError(resp.StatusCode, result["error"]); err != nil {
+		return err
 	}
 
 	return nil
@@ -619,8 +1329,8 @@ func (c *Client) StartProject(ctx context.Context, team, request string) (map[st
 		return nil, err
 	}
 
-	if errMsg, ok := result["error"].(string); ok && errMsg != "" {
-		return nil, fmt.Errorf("%s", errMsg)
+	if err := responseError(resp.StatusCode, result["error"]); err != nil {
+		return nil, err
 	}
 
 	return result, nil
@@ -639,16 +1349,56 @@ func (c *Client) ProjectStatus(ctx context.Context, team string) (map[string]int
 		return nil, err
 	}
 
-	if errMsg, ok := result["error"].(string); ok && errMsg != "" {
-		return nil, fmt.Errorf("%s", errMsg)
+	if err := responseError(resp.StatusCode, result["error"]); err != nil {
+		return nil, err
 	}
 
 	return result, nil
 }
 
+// Backoff between project status polls
+var (
+	projectPollInterval    = 500 * time.Millisecond
+	projectPollMaxInterval = 10 * time.Second
+)
+
+// WaitForProject polls a team's project status until until returns true or
+// ctx is done, waiting twice as long after each poll up to a limit. It
+// returns the last status it saw, along with ctx's error if it gave up.
+func (c *Client) WaitForProject(ctx context.Context, team string, until func(status map[string]interface{}) bool) (map[string]interface{}, error) {
+	interval := projectPollInterval
+	var status map[string]interface{}
+	for {
+		latest, err := c.ProjectStatus(ctx, team)
+		if err != nil {
+			if ctx.Err() != nil {
+				return status, ctx.Err()
+			}
+			return status, err
+		}
+		status = latest
+		if until(status) {
+			return status, nil
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return status, ctx.Err()
+		case <-timer.C:
+		}
+
+		interval *= 2
+		if interval > projectPollMaxInterval {
+			interval = projectPollMaxInterval
+		}
+	}
+}
+
 // PendingQuestions returns questions awaiting client response
 func (c *Client) PendingQuestions(ctx context.Context, team string) ([]interface{}, error) {
-	resp, err := c.get(ctx, "/api/teams/"+team+"/project/questions")
+	resp, err := c.get(ctx, "/api/teams/"+team+"/questions")
 	if err != nil {
 		return nil, err
 	}
@@ -656,22 +1406,26 @@ func (c *Client) PendingQuestions(ctx context.Context, team string) ([]interface
 
 	var result struct {
 		Questions []interface{} `json:"questions"`
+		Error     interface{}   `json:"error"`
 	}
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
 		return nil, err
 	}
 
+	if err := responseError(resp.StatusCode, result.Error); err != nil {
+		return nil, err
+	}
+
 	return result.Questions, nil
 }
 
 // AnswerQuestion provides an answer to a pending question
 func (c *Client) AnswerQuestion(ctx context.Context, team, questionID, answer string) error {
 	body := map[string]interface{}{
-		"question_id": questionID,
-		"answer":      answer,
+		"answer": answer,
 	}
 
-	resp, err := c.post(ctx, "/api/teams/"+team+"/project/answer", body)
+	resp, err := c.post(ctx, "/api/teams/"+team+"/questions/"+questionID+"/answer", body)
 	if err != nil {
 		return err
 	}
@@ -682,13 +1436,65 @@ func (c *Client) AnswerQuestion(ctx context.Context, team, questionID, answer st
 		return err
 	}
 
-	if errMsg, ok := result["error"].(string); ok && errMsg != "" {
-		return fmt.Errorf("%s", errMsg)
+	if err := responseError(resp.StatusCode, result["error"]); err != nil {
+		return err
 	}
 
 	return nil
 }
 
+// Approvals returns the tool calls waiting on a human to approve them, or
+// with history every recorded approval and decision
+func (c *Client) Approvals(ctx context.Context, team string, history bool) ([]interface{}, error) {
+	path := "/api/teams/" + team + "/approvals"
+	if history {
+		path += "?history=true"
+	}
+	resp, err := c.get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Approvals []interface{} `json:"approvals"`
+		Error     interface{}   `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+
+	if err := responseError(resp.StatusCode, result.Error); err != nil {
+		return nil, err
+	}
+
+	return result.Approvals, nil
+}
+
+// DecideApproval approves or denies a tool call a member is waiting on
+func (c *Client) DecideApproval(ctx context.Context, team, approvalID string, approve bool, reason string) error {
+	decision := "deny"
+	if approve {
+		decision = "approve"
+	}
+	body := map[string]interface{}{
+		"reason": reason,
+	}
+
+	resp, err := c.post(ctx, "/api/teams/"+team+"/approvals/"+approvalID+"/"+decision, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var result map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return err
+	}
+
+	return responseError(resp.StatusCode, result["error"])
+}
+
 // ProjectCommunications returns the communication log for a project
 func (c *Client) ProjectCommunications(ctx context.Context, team string, limit int) ([]interface{}, error) {
 	path := fmt.Sprintf("/api/teams/%s/project/communications?limit=%d", team, limit)
@@ -712,6 +1518,52 @@ func (c *Client) ProjectCommunications(ctx context.Context, team string, limit i
 // HTTP Helpers
 // ============================================================================
 
+// ============================================================================
+// Event Stream
+// ============================================================================
+
+// Subscribe connects to the daemon's WebSocket event stream. Events are
+// delivered on the returned channel until ctx is cancelled or the connection
+// drops, at which point the channel is closed.
+func (c *Client) Subscribe(ctx context.Context) (<-chan map[string]interface{}, error) {
+	dialer := websocket.Dialer{HandshakeTimeout: 10 * time.Second}
+	if c.socketPath != "" {
+		socketPath := c.socketPath
+		dialer.NetDialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", socketPath)
+		}
+	}
+
+	wsURL := "ws" + strings.TrimPrefix(c.baseURL, "http") + "/api/ws"
+	conn, _, err := dialer.DialContext(ctx, wsURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("connect event stream: %w", err)
+	}
+
+	events := make(chan map[string]interface{}, 64)
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+	go func() {
+		defer close(events)
+		for {
+			var event map[string]interface{}
+			if err := conn.ReadJSON(&event); err != nil {
+				return
+			}
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return events, nil
+}
+
 func (c *Client) get(ctx context.Context, path string) (*http.Response, error) {
 	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+path, nil)
 	if err != nil {
@@ -0,0 +1,163 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// fakeProjectDaemon serves a project whose phase moves through phases, one
+// per status request, then stays on the last
+func fakeProjectDaemon(t *testing.T, phases ...string) (*Client, *atomic.Int32) {
+	t.Helper()
+
+	var calls atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/teams/alpha/project/status" {
+			http.NotFound(w, r)
+			return
+		}
+		n := int(calls.Add(1)) - 1
+		if n >= len(phases) {
+			n = len(phases) - 1
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"id": "project-1", "phase": phases[n]})
+	}))
+	t.Cleanup(ts.Close)
+
+	interval, max := projectPollInterval, projectPollMaxInterval
+	projectPollInterval, projectPollMaxInterval = time.Millisecond, 4*time.Millisecond
+	t.Cleanup(func() { projectPollInterval, projectPollMaxInterval = interval, max })
+
+	return NewRemoteClient(strings.TrimPrefix(ts.URL, "http://")), &calls
+}
+
+func phaseIs(phases ...string) func(map[string]interface{}) bool {
+	return func(status map[string]interface{}) bool {
+		for _, p := range phases {
+			if status["phase"] == p {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func TestClient_WaitForProject(t *testing.T) {
+	client, calls := fakeProjectDaemon(t, "planning", "planning", "execution", "complete")
+
+	status, err := client.WaitForProject(context.Background(), "alpha", phaseIs("complete", "blocked"))
+	if err != nil {
+		t.Fatalf("WaitForProject failed: %v", err)
+	}
+	if status["phase"] != "complete" {
+		t.Errorf("Expected the complete status, got %v", status)
+	}
+	if n := calls.Load(); n != 4 {
+		t.Errorf("Expected 4 polls, got %d", n)
+	}
+}
+
+func TestClient_WaitForProjectGivesUp(t *testing.T) {
+	client, _ := fakeProjectDaemon(t, "planning", "execution")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	status, err := client.WaitForProject(ctx, "alpha", phaseIs("complete"))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected DeadlineExceeded, got %v", err)
+	}
+	if status["phase"] != "execution" {
+		t.Errorf("Expected the last status seen, got %v", status)
+	}
+}
+
+// fakeEventDaemon streams events on /api/ws, then holds the connection open
+// until the client goes, or closes it when hold is false
+func fakeEventDaemon(t *testing.T, hold bool, events ...map[string]interface{}) *Client {
+	t.Helper()
+
+	upgrader := websocket.Upgrader{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/ws" {
+			http.NotFound(w, r)
+			return
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for _, event := range events {
+			conn.WriteJSON(event)
+		}
+		if hold {
+			conn.ReadMessage()
+		}
+	}))
+	t.Cleanup(ts.Close)
+
+	return NewRemoteClient(strings.TrimPrefix(ts.URL, "http://"))
+}
+
+func TestClient_Subscribe(t *testing.T) {
+	client := fakeEventDaemon(t, false,
+		map[string]interface{}{"type": "member_status", "team": "alpha"},
+		map[string]interface{}{"type": "task_complete", "team": "alpha"},
+	)
+
+	events, err := client.Subscribe(context.Background())
+	if err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+
+	var got []string
+	timeout := time.After(5 * time.Second)
+	for done := false; !done; {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				done = true
+				break
+			}
+			got = append(got, event["type"].(string))
+		case <-timeout:
+			t.Fatal("Expected the channel to close when the daemon drops the stream")
+		}
+	}
+	if strings.Join(got, ",") != "member_status,task_complete" {
+		t.Errorf("Expected both events in order, got %v", got)
+	}
+}
+
+func TestClient_SubscribeStopsWithContext(t *testing.T) {
+	client := fakeEventDaemon(t, true, map[string]interface{}{"type": "member_status", "team": "alpha"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events, err := client.Subscribe(ctx)
+	if err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+	if event := <-events; event["type"] != "member_status" {
+		t.Fatalf("Expected member_status, got %v", event)
+	}
+
+	cancel()
+	select {
+	case _, ok := <-events:
+		if ok {
+			t.Error("Expected no further events once the context is done")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the channel to close once the context is done")
+	}
+}
@@ -17,6 +17,9 @@ import (
 	"github.com/arcslash/ugudu/internal/config"
 	"github.com/arcslash/ugudu/internal/logger"
 	"github.com/arcslash/ugudu/internal/manager"
+	"github.com/arcslash/ugudu/internal/provider"
+	"github.com/arcslash/ugudu/internal/team"
+	"github.com/arcslash/ugudu/internal/tools"
 )
 
 const (
@@ -54,24 +57,28 @@ type Config struct {
 	SocketPath string
 	TCPAddr    string // Optional: "host:port" for HTTP access
 	LogLevel   string
+
+	// Limits on /api/chat; nil uses api.DefaultChatLimits
+	ChatLimits *api.ChatLimits
 }
 
 // New creates a new daemon instance
 func New(cfg Config) (*Daemon, error) {
 	// Load Ugudu config and apply to environment for provider auto-discovery
-	if uguduCfg, err := config.Load(); err == nil {
-		uguduCfg.ApplyToEnvironment()
+	uguduCfg, err := config.Load()
+	if err != nil {
+		uguduCfg = &config.Config{}
 	}
+	secretsErr := uguduCfg.ApplyToEnvironment()
 
 	// Determine socket path
 	socketPath := cfg.SocketPath
 	if socketPath == "" {
-		// Try system path first, fall back to user home
+		// Try system path first, fall back to the user's runtime dir
 		if os.Geteuid() == 0 {
 			socketPath = DefaultSocketPath
 		} else {
-			home, _ := os.UserHomeDir()
-			socketPath = filepath.Join(home, UserSocketPath)
+			socketPath = config.SocketPath()
 		}
 	}
 
@@ -84,18 +91,55 @@ func New(cfg Config) (*Daemon, error) {
 	// Setup data directory
 	dataDir := cfg.DataDir
 	if dataDir == "" {
-		home, _ := os.UserHomeDir()
-		dataDir = filepath.Join(home, ".ugudu", "data")
+		dataDir = config.DataDir()
 	}
 
 	// Create logger
 	log := logger.New(cfg.LogLevel, os.Stdout)
+	log.SetBuffer(logger.NewBuffer(uguduCfg.Daemon.LogBufferSize))
+	if secretsErr != nil {
+		log.Warn("failed to resolve a provider secret", "error", secretsErr)
+	}
+
+	redactor, err := redactorFromConfig(uguduCfg.Redaction)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redaction config: %w", err)
+	}
+
+	teamLimits, err := teamLimitsFromConfig(uguduCfg.Daemon)
+	if err != nil {
+		return nil, fmt.Errorf("invalid daemon config: %w", err)
+	}
+
+	mcpServers, err := mcpServersFromConfig(uguduCfg.MCPServers)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mcp_servers config: %w", err)
+	}
 
 	// Create manager
 	mgrCfg := manager.Config{
 		DataDir:   dataDir,
 		LogLevel:  cfg.LogLevel,
 		LogFormat: "text",
+
+		SystemPrefix: uguduCfg.Defaults.SystemPrefix,
+		SystemSuffix: uguduCfg.Defaults.SystemSuffix,
+		Timezone:     uguduCfg.Defaults.Timezone,
+
+		MaxSpecVersions: uguduCfg.Daemon.MaxSpecVersions,
+
+		TeamLimits: teamLimits,
+
+		ProviderCheckInterval: time.Duration(uguduCfg.Daemon.ProviderCheckSeconds) * time.Second,
+
+		Redactor:      redactor,
+		ResponseCache: responseCacheFromConfig(uguduCfg.ResponseCache),
+
+		MCPServers: mcpServers,
+
+		ModelTitles: uguduCfg.Daemon.ModelConversationTitles,
+
+		IdleStop: time.Duration(uguduCfg.Daemon.IdleStopSeconds) * time.Second,
 	}
 	mgr, err := manager.New(mgrCfg, log)
 	if err != nil {
@@ -104,6 +148,9 @@ func New(cfg Config) (*Daemon, error) {
 
 	// Create API server
 	apiServer := api.NewServer(mgr, log)
+	if cfg.ChatLimits != nil {
+		apiServer.SetChatLimits(*cfg.ChatLimits)
+	}
 
 	ctx, cancel := context.WithCancel(context.Background())
 
@@ -119,6 +166,105 @@ func New(cfg Config) (*Daemon, error) {
 	}, nil
 }
 
+// teamLimitsFromConfig reads the per-team resource limits
+func teamLimitsFromConfig(cfg config.DaemonConfig) (team.Limits, error) {
+	overflow, err := team.ParseOverflowPolicy(cfg.ChannelOverflow)
+	if err != nil {
+		return team.Limits{}, fmt.Errorf("channel_overflow: %w", err)
+	}
+	clientOverflow, err := team.ParseOverflowPolicy(cfg.ClientChannelOverflow)
+	if err != nil {
+		return team.Limits{}, fmt.Errorf("client_channel_overflow: %w", err)
+	}
+
+	return team.Limits{
+		MaxMembers: cfg.MaxMembersPerTeam,
+		InboxSize:  cfg.MemberInboxSize,
+		OutboxSize: cfg.MemberOutboxSize,
+
+		MaxToolResult: cfg.MaxToolResultBytes,
+
+		ClientChanSize:   cfg.ClientChannelSize,
+		InternalChanSize: cfg.InternalChannelSize,
+		Overflow:         overflow,
+		ClientOverflow:   clientOverflow,
+		OverflowTimeout:  time.Duration(cfg.ChannelOverflowSeconds) * time.Second,
+	}, nil
+}
+
+// mcpServersFromConfig reads the external MCP servers teams may use,
+// resolving secret references in their env and headers
+func mcpServersFromConfig(cfg map[string]config.MCPServerConfig) (map[string]tools.MCPServerConfig, error) {
+	servers := make(map[string]tools.MCPServerConfig, len(cfg))
+	for name, s := range cfg {
+		env, err := resolveSecrets(s.Env)
+		if err != nil {
+			return nil, fmt.Errorf("%s: env.%w", name, err)
+		}
+		headers, err := resolveSecrets(s.Headers)
+		if err != nil {
+			return nil, fmt.Errorf("%s: headers.%w", name, err)
+		}
+		servers[name] = tools.MCPServerConfig{Command: s.Command, Env: env, URL: s.URL, Headers: headers}
+	}
+	return servers, nil
+}
+
+// resolveSecrets returns values with any secret references resolved
+func resolveSecrets(values map[string]string) (map[string]string, error) {
+	if len(values) == 0 {
+		return values, nil
+	}
+	out := make(map[string]string, len(values))
+	for k, v := range values {
+		secret, err := config.ResolveSecret(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", k, err)
+		}
+		out[k] = secret
+	}
+	return out, nil
+}
+
+// redactorFromConfig builds the provider redactor, or nil when redaction is
+// off
+func redactorFromConfig(cfg config.RedactionConfig) (*provider.Redactor, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	builtins := provider.BuiltinRedactions
+	if len(cfg.Builtins) > 0 {
+		builtins = nil
+		for _, name := range cfg.Builtins {
+			if name != "none" {
+				builtins = append(builtins, name)
+			}
+		}
+	}
+
+	patterns := make([]provider.RedactionPattern, len(cfg.Patterns))
+	for i, p := range cfg.Patterns {
+		patterns[i] = provider.RedactionPattern{Name: p.Name, Pattern: p.Pattern}
+	}
+
+	return provider.NewRedactor(builtins, patterns, cfg.Restore)
+}
+
+// responseCacheFromConfig builds the provider response cache, or nil when
+// it's off
+func responseCacheFromConfig(cfg config.ResponseCacheConfig) *provider.ResponseCache {
+	if !cfg.Enabled {
+		return nil
+	}
+	return provider.NewResponseCache(provider.CacheOptions{
+		TTL:            time.Duration(cfg.TTLSeconds) * time.Second,
+		MaxEntries:     cfg.MaxEntries,
+		MaxTemperature: cfg.MaxTemperature,
+		Force:          cfg.Force,
+	})
+}
+
 // Start begins the daemon
 func (d *Daemon) Start() error {
 	// Check if already running
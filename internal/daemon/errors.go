@@ -0,0 +1,113 @@
+package daemon
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Error codes returned by the daemon API. They mirror the codes in the api
+// package and stay stable even when messages are reworded.
+const (
+	CodeNotFound      = "NOT_FOUND"
+	CodeValidation    = "VALIDATION"
+	CodeProviderError = "PROVIDER_ERROR"
+	CodeRateLimited   = "RATE_LIMITED"
+	CodeConflict      = "CONFLICT"
+	CodeInternal      = "INTERNAL"
+)
+
+// Sentinel errors for errors.Is checks against errors returned by Client
+var (
+	ErrNotFound        = errors.New("not found")
+	ErrTeamNotFound    = errors.New("team not found")
+	ErrSpecNotFound    = errors.New("spec not found")
+	ErrProjectNotFound = errors.New("project not found")
+	ErrValidation      = errors.New("invalid request")
+	ErrProviderError   = errors.New("provider error")
+	ErrBadCredentials  = errors.New("bad credentials")
+	ErrRateLimited     = errors.New("rate limited")
+	ErrConflict        = errors.New("conflict")
+)
+
+// APIError is an error response from the daemon
+type APIError struct {
+	Status  int
+	Code    string
+	Message string
+	Details map[string]interface{}
+}
+
+func (e *APIError) Error() string {
+	return e.Message
+}
+
+// Is matches the sentinel for the error's code. Not-found errors also match
+// the sentinel for the kind of resource that was missing, and provider errors
+// caused by a rejected API key match ErrBadCredentials.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.Code == CodeNotFound
+	case ErrTeamNotFound:
+		return e.Code == CodeNotFound && e.Details["resource"] == "team"
+	case ErrSpecNotFound:
+		return e.Code == CodeNotFound && e.Details["resource"] == "spec"
+	case ErrProjectNotFound:
+		return e.Code == CodeNotFound && e.Details["resource"] == "project"
+	case ErrValidation:
+		return e.Code == CodeValidation
+	case ErrProviderError:
+		return e.Code == CodeProviderError
+	case ErrBadCredentials:
+		return e.Code == CodeProviderError && e.Details["reason"] == "bad_credentials"
+	case ErrRateLimited:
+		return e.Code == CodeRateLimited
+	case ErrConflict:
+		return e.Code == CodeConflict
+	}
+	return false
+}
+
+// responseError converts the "error" field of a response body into an
+// *APIError, or returns nil if there isn't one. Daemons predating error codes
+// send a bare string, so the code is inferred from the status for those.
+func responseError(status int, raw interface{}) error {
+	switch v := raw.(type) {
+	case map[string]interface{}:
+		e := &APIError{Status: status}
+		e.Code, _ = v["code"].(string)
+		e.Message, _ = v["message"].(string)
+		e.Details, _ = v["details"].(map[string]interface{})
+		if e.Code == "" {
+			e.Code = codeForStatus(status)
+		}
+		if e.Message == "" {
+			e.Message = fmt.Sprintf("daemon returned %s", e.Code)
+		}
+		return e
+	case string:
+		if v == "" {
+			return nil
+		}
+		return &APIError{Status: status, Code: codeForStatus(status), Message: v}
+	}
+	return nil
+}
+
+func codeForStatus(status int) string {
+	switch {
+	case status == http.StatusNotFound:
+		return CodeNotFound
+	case status == http.StatusTooManyRequests:
+		return CodeRateLimited
+	case status == http.StatusBadGateway:
+		return CodeProviderError
+	case status == http.StatusConflict:
+		return CodeConflict
+	case status >= 400 && status < 500:
+		return CodeValidation
+	default:
+		return CodeInternal
+	}
+}
@@ -0,0 +1,116 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/arcslash/ugudu/internal/api"
+	"github.com/arcslash/ugudu/internal/logger"
+	"github.com/arcslash/ugudu/internal/manager"
+	"github.com/arcslash/ugudu/internal/provider"
+)
+
+func TestClient_TypedErrors(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("UGUDU_HOME", home)
+
+	mgr, err := manager.New(manager.Config{DataDir: filepath.Join(home, "data")}, logger.New("error"))
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	defer mgr.Stop()
+
+	ts := httptest.NewServer(api.NewServer(mgr, logger.New("error")).Handler())
+	defer ts.Close()
+	client := NewRemoteClient(strings.TrimPrefix(ts.URL, "http://"))
+
+	ctx := context.Background()
+
+	_, err = client.GetTeam(ctx, "missing")
+	if !errors.Is(err, ErrTeamNotFound) || !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrTeamNotFound from GetTeam, got %v", err)
+	}
+	if errors.Is(err, ErrSpecNotFound) {
+		t.Error("Missing team shouldn't match ErrSpecNotFound")
+	}
+
+	if err := client.StartTeam(ctx, "missing"); !errors.Is(err, ErrTeamNotFound) {
+		t.Errorf("Expected ErrTeamNotFound from StartTeam, got %v", err)
+	}
+
+	_, err = client.Chat(ctx, "missing", "hello", "")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected *APIError from Chat, got %T: %v", err, err)
+	}
+	if apiErr.Code != CodeNotFound || apiErr.Status != 404 || apiErr.Message == "" {
+		t.Errorf("Unexpected error: %+v", apiErr)
+	}
+
+	if _, err := client.AddSpecialist(ctx, "missing", "security"); !errors.Is(err, ErrSpecNotFound) {
+		t.Errorf("Expected ErrSpecNotFound from AddSpecialist, got %v", err)
+	}
+}
+
+// pingProvider is a provider whose Ping fails with err
+type pingProvider struct {
+	id  string
+	err error
+}
+
+func (p *pingProvider) ID() string   { return p.id }
+func (p *pingProvider) Name() string { return p.id }
+func (p *pingProvider) Chat(ctx context.Context, req *provider.ChatRequest) (*provider.ChatResponse, error) {
+	return nil, p.err
+}
+func (p *pingProvider) Stream(ctx context.Context, req *provider.ChatRequest) (<-chan provider.StreamChunk, error) {
+	return nil, p.err
+}
+func (p *pingProvider) ListModels(ctx context.Context) ([]provider.ModelInfo, error) { return nil, nil }
+func (p *pingProvider) Ping(ctx context.Context) error                               { return p.err }
+
+func TestClient_TestProviderBadCredentials(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("UGUDU_HOME", home)
+
+	mgr, err := manager.New(manager.Config{DataDir: filepath.Join(home, "data")}, logger.New("error"))
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	defer mgr.Stop()
+	mgr.Providers().Register(&pingProvider{id: "bad-key", err: &provider.AuthError{Provider: "bad-key", StatusCode: 401}})
+	mgr.Providers().Register(&pingProvider{id: "down", err: errors.New("connection refused")})
+
+	ts := httptest.NewServer(api.NewServer(mgr, logger.New("error")).Handler())
+	defer ts.Close()
+	client := NewRemoteClient(strings.TrimPrefix(ts.URL, "http://"))
+
+	ctx := context.Background()
+
+	err = client.TestProvider(ctx, "bad-key")
+	if !errors.Is(err, ErrBadCredentials) || !errors.Is(err, ErrProviderError) {
+		t.Errorf("Expected ErrBadCredentials from a rejected key, got %v", err)
+	}
+
+	err = client.TestProvider(ctx, "down")
+	if !errors.Is(err, ErrProviderError) {
+		t.Errorf("Expected ErrProviderError from an unreachable provider, got %v", err)
+	}
+	if errors.Is(err, ErrBadCredentials) {
+		t.Error("Unreachable provider shouldn't match ErrBadCredentials")
+	}
+}
+
+func TestResponseError_LegacyString(t *testing.T) {
+	err := responseError(429, "slow down")
+	if !errors.Is(err, ErrRateLimited) || err.Error() != "slow down" {
+		t.Errorf("Expected rate limited error from bare string, got %v", err)
+	}
+	if responseError(200, nil) != nil || responseError(200, "") != nil {
+		t.Error("Expected nil for responses without an error")
+	}
+}
@@ -0,0 +1,120 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultBufferSize is how many records a Buffer keeps when given no size
+const DefaultBufferSize = 1000
+
+// Record is one log line kept in a Buffer
+type Record struct {
+	Seq     uint64            `json:"seq"` // Increases by one per record, for polling with After
+	Time    time.Time         `json:"time"`
+	Level   string            `json:"level"`
+	Message string            `json:"message"`
+	Fields  map[string]string `json:"fields,omitempty"`
+}
+
+// Filter selects records from a Buffer. Zero values match everything.
+type Filter struct {
+	Level Level     // Records at this level or above
+	Team  string    // Records with this team field
+	Since time.Time // Records logged at or after this
+	After uint64    // Records with a higher Seq
+	Limit int       // Keep only the most recent this many
+}
+
+// Buffer keeps the most recent log records in memory, so they can be read
+// back without access to the process's output
+type Buffer struct {
+	mu      sync.Mutex
+	records []Record // Ring; next is the oldest once it's full
+	next    int
+	full    bool
+	seq     uint64
+}
+
+// NewBuffer creates a buffer of the size most recent records, or
+// DefaultBufferSize if size isn't positive
+func NewBuffer(size int) *Buffer {
+	if size <= 0 {
+		size = DefaultBufferSize
+	}
+	return &Buffer{records: make([]Record, size)}
+}
+
+// add keeps r, dropping the oldest record if the buffer is full
+func (b *Buffer) add(r Record) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.seq++
+	r.Seq = b.seq
+	b.records[b.next] = r
+	b.next = (b.next + 1) % len(b.records)
+	if b.next == 0 {
+		b.full = true
+	}
+}
+
+// Records returns the records matching f, oldest first
+func (b *Buffer) Records(f Filter) []Record {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var ordered []Record
+	if b.full {
+		ordered = append(ordered, b.records[b.next:]...)
+	}
+	ordered = append(ordered, b.records[:b.next]...)
+
+	out := []Record{}
+	for _, r := range ordered {
+		if r.Seq <= f.After || r.Time.Before(f.Since) {
+			continue
+		}
+		if level, _ := ParseLevel(r.Level); level < f.Level {
+			continue
+		}
+		if f.Team != "" && r.Fields["team"] != f.Team {
+			continue
+		}
+		out = append(out, r)
+	}
+	if f.Limit > 0 && len(out) > f.Limit {
+		out = out[len(out)-f.Limit:]
+	}
+	return out
+}
+
+// ParseLevel reads a level name: debug, info, warn (or warning) or error
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToLower(s) {
+	case "debug":
+		return LevelDebug, nil
+	case "info":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "error":
+		return LevelError, nil
+	}
+	return LevelInfo, fmt.Errorf("unknown log level %q (use debug, info, warn or error)", s)
+}
+
+// String returns the level's name, as ParseLevel reads it
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "debug"
+	case LevelWarn:
+		return "warn"
+	case LevelError:
+		return "error"
+	default:
+		return "info"
+	}
+}
@@ -0,0 +1,53 @@
+package logger
+
+import (
+	"io"
+	"testing"
+	"time"
+)
+
+func TestBuffer_KeepsAndFiltersRecords(t *testing.T) {
+	buffer := NewBuffer(3)
+	log := New("info", io.Discard)
+	log.SetBuffer(buffer)
+
+	start := time.Now()
+	log.Debug("not logged at info")
+	log.Info("daemon started")
+	teamLog := log.With("team", "alpha")
+	teamLog.Warn("provider slow", "provider", "anthropic")
+	teamLog.Error("request failed", "error", io.ErrUnexpectedEOF)
+	log.With("team", "beta").Error("team failed to start")
+
+	// The buffer holds 3, so "daemon started" has been dropped
+	all := buffer.Records(Filter{})
+	if len(all) != 3 || all[0].Message != "provider slow" || all[2].Message != "team failed to start" {
+		t.Fatalf("Expected the 3 most recent records, oldest first, got %+v", all)
+	}
+
+	errors := buffer.Records(Filter{Level: LevelError})
+	if len(errors) != 2 || errors[0].Level != "error" || errors[1].Level != "error" {
+		t.Errorf("Expected the 2 error records, got %+v", errors)
+	}
+	if got := errors[0].Fields; got["team"] != "alpha" || got["error"] != "unexpected EOF" {
+		t.Errorf("Expected the logger's and the line's fields, got %v", got)
+	}
+
+	alpha := buffer.Records(Filter{Team: "alpha"})
+	if len(alpha) != 2 || alpha[0].Message != "provider slow" || alpha[1].Message != "request failed" {
+		t.Errorf("Expected alpha's 2 records, got %+v", alpha)
+	}
+
+	if got := buffer.Records(Filter{Level: LevelError, Team: "beta", Since: start}); len(got) != 1 || got[0].Message != "team failed to start" {
+		t.Errorf("Expected beta's error, got %+v", got)
+	}
+	if got := buffer.Records(Filter{After: errors[0].Seq}); len(got) != 1 || got[0].Message != "team failed to start" {
+		t.Errorf("Expected only the record after the first error, got %+v", got)
+	}
+	if got := buffer.Records(Filter{Limit: 1}); len(got) != 1 || got[0].Message != "team failed to start" {
+		t.Errorf("Expected only the newest record, got %+v", got)
+	}
+	if got := buffer.Records(Filter{Since: time.Now().Add(time.Hour)}); len(got) != 0 {
+		t.Errorf("Expected no records from the future, got %+v", got)
+	}
+}
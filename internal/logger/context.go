@@ -0,0 +1,32 @@
+package logger
+
+import "context"
+
+type requestIDKey struct{}
+
+// ContextWithRequestID returns a context carrying the request ID that
+// correlates log lines for one request
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	if id == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID carried by ctx, or "" if there is none
+func RequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// WithContext returns a logger tagged with the request ID carried by ctx
+func (l *Logger) WithContext(ctx context.Context) *Logger {
+	id := RequestID(ctx)
+	if id == "" {
+		return l
+	}
+	return l.With("request_id", id)
+}
@@ -25,7 +25,8 @@ type Logger struct {
 	level  Level
 	output io.Writer
 	fields map[string]interface{}
-	mu     sync.Mutex
+	mu     *sync.Mutex // Shared with loggers derived by With
+	buffer *Buffer     // Also keeps records here if set; shared with loggers derived by With
 }
 
 // New creates a new logger
@@ -34,25 +35,25 @@ func New(level string, output ...io.Writer) *Logger {
 	if len(output) > 0 && output[0] != nil {
 		out = output[0]
 	}
+	lvl, _ := ParseLevel(level)
 	l := &Logger{
-		level:  parseLevel(level),
+		level:  lvl,
 		output: out,
 		fields: make(map[string]interface{}),
+		mu:     &sync.Mutex{},
 	}
 	return l
 }
 
-func parseLevel(s string) Level {
-	switch strings.ToLower(s) {
-	case "debug":
-		return LevelDebug
-	case "warn", "warning":
-		return LevelWarn
-	case "error":
-		return LevelError
-	default:
-		return LevelInfo
-	}
+// SetBuffer keeps a copy of each record the logger, and loggers it derives
+// afterwards, write in b
+func (l *Logger) SetBuffer(b *Buffer) {
+	l.buffer = b
+}
+
+// Buffer returns the buffer records are kept in, or nil
+func (l *Logger) Buffer() *Buffer {
+	return l.buffer
 }
 
 // With returns a new logger with additional fields
@@ -61,6 +62,8 @@ func (l *Logger) With(keyvals ...interface{}) *Logger {
 		level:  l.level,
 		output: l.output,
 		fields: make(map[string]interface{}),
+		mu:     l.mu,
+		buffer: l.buffer,
 	}
 
 	// Copy existing fields
@@ -81,36 +84,37 @@ func (l *Logger) With(keyvals ...interface{}) *Logger {
 // Debug logs at debug level
 func (l *Logger) Debug(msg string, keyvals ...interface{}) {
 	if l.level <= LevelDebug {
-		l.log("DEBUG", msg, keyvals...)
+		l.log(LevelDebug, msg, keyvals...)
 	}
 }
 
 // Info logs at info level
 func (l *Logger) Info(msg string, keyvals ...interface{}) {
 	if l.level <= LevelInfo {
-		l.log("INFO", msg, keyvals...)
+		l.log(LevelInfo, msg, keyvals...)
 	}
 }
 
 // Warn logs at warn level
 func (l *Logger) Warn(msg string, keyvals ...interface{}) {
 	if l.level <= LevelWarn {
-		l.log("WARN", msg, keyvals...)
+		l.log(LevelWarn, msg, keyvals...)
 	}
 }
 
 // Error logs at error level
 func (l *Logger) Error(msg string, keyvals ...interface{}) {
 	if l.level <= LevelError {
-		l.log("ERROR", msg, keyvals...)
+		l.log(LevelError, msg, keyvals...)
 	}
 }
 
-func (l *Logger) log(level, msg string, keyvals ...interface{}) {
+func (l *Logger) log(level Level, msg string, keyvals ...interface{}) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
-	timestamp := time.Now().Format("15:04:05")
+	now := time.Now()
+	timestamp := now.Format("15:04:05")
 
 	// Build fields string
 	var fields strings.Builder
@@ -127,5 +131,26 @@ func (l *Logger) log(level, msg string, keyvals ...interface{}) {
 		}
 	}
 
-	fmt.Fprintf(l.output, "%s %s %s%s\n", timestamp, level, msg, fields.String())
+	fmt.Fprintf(l.output, "%s %s %s%s\n", timestamp, strings.ToUpper(level.String()), msg, fields.String())
+
+	if l.buffer != nil {
+		l.buffer.add(Record{Time: now, Level: level.String(), Message: msg, Fields: recordFields(l.fields, keyvals)})
+	}
+}
+
+// recordFields merges a logger's fields with a line's, as strings
+func recordFields(stored map[string]interface{}, keyvals []interface{}) map[string]string {
+	if len(stored) == 0 && len(keyvals) < 2 {
+		return nil
+	}
+	fields := make(map[string]string, len(stored)+len(keyvals)/2)
+	for k, v := range stored {
+		fields[k] = fmt.Sprint(v)
+	}
+	for i := 0; i < len(keyvals)-1; i += 2 {
+		if key, ok := keyvals[i].(string); ok {
+			fields[key] = fmt.Sprint(keyvals[i+1])
+		}
+	}
+	return fields
 }
@@ -0,0 +1,66 @@
+package manager
+
+import "github.com/arcslash/ugudu/internal/team"
+
+// Audit log actions
+const (
+	AuditChatRequest  = "chat.request"        // The client asked a member something
+	AuditChatResponse = "chat.response"       // A member answered the client
+	AuditToolExecute  = team.AuditToolExecute // Recorded by the team
+	AuditToolDenied   = team.AuditToolDenied  // Recorded by the team
+	AuditHandoff      = team.AuditHandoff     // Recorded by the team
+	AuditTeamCreate   = "team.create"
+	AuditTeamStart    = "team.start"
+	AuditTeamStop     = "team.stop"
+	AuditTeamRestart  = "team.restart"
+	AuditTeamAutoStop = "team.auto_stop"
+	AuditTeamPause    = "team.pause"
+	AuditTeamResume   = "team.resume"
+	AuditTeamDelete   = "team.delete"
+	AuditTokenMode    = "team.token_mode"
+	AuditSettings     = "config.settings" // Provider keys or defaults changed
+)
+
+// RecordAudit appends an entry to the audit log, timestamped now if it
+// isn't already. Failures are logged rather than returned so they never
+// stop the action being audited.
+func (m *Manager) RecordAudit(e AuditEntry) {
+	if m.store == nil {
+		return
+	}
+	if e.Timestamp.IsZero() {
+		e.Timestamp = m.now()
+	}
+	if err := m.store.AppendAudit(e); err != nil {
+		m.logger.Warn("failed to write audit entry", "action", e.Action, "team", e.Team, "error", err)
+	}
+}
+
+// AuditLog returns the audit entries matching f, oldest first
+func (m *Manager) AuditLog(f AuditFilter) ([]AuditEntry, error) {
+	return m.store.ListAudit(f)
+}
+
+// auditMessage records a client request or a member's answer to it
+func (m *Manager) auditMessage(teamName, conversationID, content string, msg team.Message) {
+	e := AuditEntry{Team: teamName, RequestID: msg.RequestID, Details: map[string]interface{}{
+		"content": content,
+	}}
+	if conversationID != "" {
+		e.Details["conversation_id"] = conversationID
+	}
+	switch {
+	case msg.From == "client":
+		e.Action, e.Actor = AuditChatRequest, "client"
+		e.Details["to"] = msg.To
+	case msg.To == "client":
+		e.Action, e.Actor = AuditChatResponse, msg.From
+		if msg.Model != "" {
+			e.Details["provider"] = msg.Provider
+			e.Details["model"] = msg.Model
+		}
+	default:
+		return
+	}
+	m.RecordAudit(e)
+}
@@ -0,0 +1,186 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/arcslash/ugudu/internal/team"
+	"github.com/google/uuid"
+)
+
+// DefaultBenchPrompt is the ask a bench sends when it isn't given one. It
+// needs planning and a little delegation in most teams, without tools doing
+// anything that would differ between runs.
+const DefaultBenchPrompt = "Plan how to add a /health endpoint to a small web service: the steps, who on the team would do each, and how to test it. Reply with the plan only, in under 200 words."
+
+// Bounds on a bench
+const (
+	MaxBenchIterations  = 20
+	DefaultBenchTimeout = 5 * time.Minute // Per run
+)
+
+// BenchOptions is what a bench asks and how often
+type BenchOptions struct {
+	Prompt     string        `json:"prompt,omitempty"`     // Default DefaultBenchPrompt
+	To         string        `json:"to,omitempty"`         // Role or member to ask; default the team's client-facing member
+	Iterations int           `json:"iterations,omitempty"` // Runs, one after another (default 1)
+	Timeout    time.Duration `json:"-"`                    // Per run (default DefaultBenchTimeout)
+}
+
+// BenchRun is one ask of a bench
+type BenchRun struct {
+	RequestID        string `json:"request_id"`
+	LatencyMs        int64  `json:"latency_ms"` // From the ask to the team's last reply
+	Calls            int    `json:"calls"`
+	PromptTokens     int    `json:"prompt_tokens"`
+	CompletionTokens int    `json:"completion_tokens"`
+	Failed           int    `json:"failed,omitempty"` // Model calls that didn't succeed
+	Error            string `json:"error,omitempty"`  // Why the run didn't finish
+}
+
+// BenchLatency summarizes the end-to-end latency of a bench's runs
+type BenchLatency struct {
+	MinMs int64 `json:"min_ms"`
+	AvgMs int64 `json:"avg_ms"`
+	P50Ms int64 `json:"p50_ms"`
+	P90Ms int64 `json:"p90_ms"`
+	P99Ms int64 `json:"p99_ms"`
+	MaxMs int64 `json:"max_ms"`
+}
+
+// BenchReport is how a team did on a bench: end-to-end latency per run and
+// overall, and what each member and model contributed in calls and tokens
+type BenchReport struct {
+	Team       string              `json:"team"`
+	Prompt     string              `json:"prompt"`
+	Iterations int                 `json:"iterations"`
+	Runs       []BenchRun          `json:"runs"`
+	Latency    BenchLatency        `json:"latency"`
+	Total      CallStats           `json:"total"`
+	Members    []MemberPerformance `json:"members"`
+	Models     []ModelPerformance  `json:"models"`
+}
+
+// BenchTeam asks a team the same thing opts.Iterations times, one after
+// another, and reports how long each took and the model calls it caused.
+// The asks go into members' context like any other, so bench a team that
+// isn't in use.
+func (m *Manager) BenchTeam(ctx context.Context, name string, opts BenchOptions) (*BenchReport, error) {
+	if opts.Prompt == "" {
+		opts.Prompt = DefaultBenchPrompt
+	}
+	if opts.Iterations == 0 {
+		opts.Iterations = 1
+	}
+	if opts.Iterations < 1 || opts.Iterations > MaxBenchIterations {
+		return nil, fmt.Errorf("iterations must be from 1 to %d, got %d", MaxBenchIterations, opts.Iterations)
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultBenchTimeout
+	}
+	if _, err := m.GetTeam(name); err != nil {
+		return nil, err
+	}
+
+	report := &BenchReport{Team: name, Prompt: opts.Prompt, Iterations: opts.Iterations}
+	var requestIDs []string
+	for i := 0; i < opts.Iterations; i++ {
+		run, err := m.benchRun(ctx, name, opts)
+		if err != nil {
+			return nil, err
+		}
+		report.Runs = append(report.Runs, run)
+		requestIDs = append(requestIDs, run.RequestID)
+		if run.Error != "" {
+			// The unfinished ask would overlap the next run
+			break
+		}
+	}
+
+	perf, err := m.store.RequestPerformance(name, requestIDs)
+	if err != nil {
+		return nil, err
+	}
+	report.Total, report.Members, report.Models = perf.Total, perf.Members, perf.Models
+	report.Latency = benchLatency(report.Runs)
+	return report, nil
+}
+
+// benchRun makes one ask of a bench and waits for the team to finish it
+func (m *Manager) benchRun(ctx context.Context, name string, opts BenchOptions) (BenchRun, error) {
+	run := BenchRun{RequestID: "bench-" + uuid.New().String()}
+	askOpts := []team.AskOption{team.WithVerbosity(team.VerbosityQuiet), team.WithRequestID(run.RequestID)}
+
+	start := time.Now()
+	var replies <-chan team.Message
+	var err error
+	if opts.To != "" {
+		replies, err = m.AskMember(name, opts.To, opts.Prompt, askOpts...)
+	} else {
+		replies, err = m.Ask(name, opts.Prompt, askOpts...)
+	}
+	if err != nil {
+		return run, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+wait:
+	for {
+		select {
+		case <-ctx.Done():
+			run.Error = "timed out waiting for the team to finish"
+			if ctx.Err() == context.Canceled {
+				run.Error = "canceled"
+			}
+			break wait
+		case _, ok := <-replies:
+			if !ok {
+				break wait
+			}
+		}
+	}
+	run.LatencyMs = time.Since(start).Milliseconds()
+
+	perf, err := m.store.RequestPerformance(name, []string{run.RequestID})
+	if err != nil {
+		return run, err
+	}
+	run.Calls = perf.Total.Calls
+	run.PromptTokens = perf.Total.PromptTokens
+	run.CompletionTokens = perf.Total.CompletionTokens
+	run.Failed = perf.Total.Calls - perf.Total.Outcomes[team.CallOK]
+	return run, nil
+}
+
+// benchLatency summarizes runs' latencies, percentiles by nearest rank
+func benchLatency(runs []BenchRun) BenchLatency {
+	if len(runs) == 0 {
+		return BenchLatency{}
+	}
+	ms := make([]int64, len(runs))
+	var total int64
+	for i, r := range runs {
+		ms[i] = r.LatencyMs
+		total += r.LatencyMs
+	}
+	sort.Slice(ms, func(i, j int) bool { return ms[i] < ms[j] })
+
+	percentile := func(p int) int64 {
+		rank := (p*len(ms) + 99) / 100
+		if rank < 1 {
+			rank = 1
+		}
+		return ms[rank-1]
+	}
+	return BenchLatency{
+		MinMs: ms[0],
+		AvgMs: total / int64(len(ms)),
+		P50Ms: percentile(50),
+		P90Ms: percentile(90),
+		P99Ms: percentile(99),
+		MaxMs: ms[len(ms)-1],
+	}
+}
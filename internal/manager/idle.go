@@ -0,0 +1,109 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/arcslash/ugudu/internal/team"
+)
+
+// idleCheckInterval is how often running teams are checked for idleness
+const idleCheckInterval = 30 * time.Second
+
+// idleStop returns how long a team may sit idle before it's stopped: its
+// spec's idle_stop, or else the daemon's. Zero keeps it running.
+func (m *Manager) idleStop(t *team.Team) time.Duration {
+	window := m.config.IdleStop
+	if s := t.Spec.Settings.IdleStop; s != 0 {
+		window = s
+	}
+	if window < 0 {
+		return 0
+	}
+	return window
+}
+
+// touch records an ask or activity on a team, restarting its idle window
+func (m *Manager) touch(name string) {
+	m.activeMu.Lock()
+	m.lastActive[name] = m.now()
+	m.activeMu.Unlock()
+}
+
+// wake records an ask on a team, first starting it again if it was stopped
+// for being idle. Its members reload their saved context as it starts.
+func (m *Manager) wake(name string, t *team.Team) error {
+	m.idleMu.Lock()
+	defer m.idleMu.Unlock()
+
+	m.touch(name)
+	if t.State() != team.StateAutoStopped {
+		return nil
+	}
+	if err := m.StartTeam(name); err != nil {
+		return err
+	}
+	m.logger.Info("team restarted after auto-stop", "name", name)
+	return nil
+}
+
+// watchIdle stops idle teams until ctx is done
+func (m *Manager) watchIdle(ctx context.Context) {
+	ticker := time.NewTicker(idleCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.stopIdleTeams()
+		}
+	}
+}
+
+// stopIdleTeams stops every running team that has had no asks or activity
+// for its idle window and isn't in the middle of work
+func (m *Manager) stopIdleTeams() {
+	m.idleMu.Lock()
+	defer m.idleMu.Unlock()
+
+	now := m.now()
+	for _, t := range m.ListTeams() {
+		window := m.idleStop(t)
+		if window == 0 || t.State() != team.StateRunning {
+			continue
+		}
+		if t.Busy() {
+			m.touch(t.Name)
+			continue
+		}
+
+		m.activeMu.Lock()
+		last, ok := m.lastActive[t.Name]
+		if !ok {
+			last = now
+			m.lastActive[t.Name] = now
+		}
+		m.activeMu.Unlock()
+		if now.Sub(last) < window {
+			continue
+		}
+
+		t.AutoStop()
+		m.store.UpdateTeamStatus(t.Name, team.StateAutoStopped)
+		m.logger.Info("stopped idle team", "name", t.Name, "idle_for", now.Sub(last).Round(time.Second))
+		m.RecordAudit(AuditEntry{Action: AuditTeamAutoStop, Team: t.Name, Actor: "daemon", Details: map[string]interface{}{
+			"idle_for": now.Sub(last).Round(time.Second).String(),
+		}})
+
+		m.mu.RLock()
+		cb := m.onActivity
+		m.mu.RUnlock()
+		if cb != nil {
+			cb(t.Name, "", "team_auto_stopped",
+				fmt.Sprintf("Team %s stopped after %s idle; its next ask starts it again", t.Name, window),
+				"", map[string]interface{}{"idle_stop": window.String()})
+		}
+	}
+}
@@ -4,19 +4,41 @@ package manager
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/arcslash/ugudu/internal/config"
 	"github.com/arcslash/ugudu/internal/logger"
 	"github.com/arcslash/ugudu/internal/provider"
 	"github.com/arcslash/ugudu/internal/team"
+	"github.com/arcslash/ugudu/internal/tools"
 )
 
+// ErrTeamNotFound is returned (wrapped with the team name) when an operation
+// names a team the manager doesn't know
+var ErrTeamNotFound = errors.New("team not found")
+
+// ErrMemberNotFound is returned for a member ID that isn't on the team
+var ErrMemberNotFound = errors.New("member not found")
+
+// ErrConversationNotFound is returned for a conversation with no messages
+var ErrConversationNotFound = errors.New("conversation not found")
+
+// ErrTeamExists is returned (wrapped with the team name) when creating a team
+// under a name that's already taken
+var ErrTeamExists = errors.New("team already exists")
+
+// restartDrainTimeout bounds how long RestartTeam waits for members to
+// give up their work once stopped
+const restartDrainTimeout = 30 * time.Second
+
 // ActivityCallback is called when team activity occurs
-type ActivityCallback func(teamName, memberID, activityType, message string)
+type ActivityCallback func(teamName, memberID, activityType, message, requestID string, data map[string]interface{})
 
 // Manager is the central controller for all teams
 type Manager struct {
@@ -30,6 +52,17 @@ type Manager struct {
 	cancel context.CancelFunc
 	logger *logger.Logger
 	mu     sync.RWMutex
+
+	// When each team last had an ask or activity, for stopping idle teams
+	lastActive map[string]time.Time
+	activeMu   sync.Mutex
+	idleMu     sync.Mutex // Held while stopping idle teams or waking one
+	now        func() time.Time
+	teamClock  func() time.Time // Members' clock, if not now
+
+	// Conversation summaries, by conversation ID
+	summaries map[string]*ConversationSummary
+	summaryMu sync.Mutex
 }
 
 // SetActivityCallback sets the callback for team activity events
@@ -45,14 +78,50 @@ type Config struct {
 	SocketPath string `yaml:"socket_path"`
 	LogLevel   string `yaml:"log_level"`
 	LogFormat  string `yaml:"log_format"`
+
+	// Global system prompt prefix/suffix applied to every team
+	SystemPrefix string `yaml:"system_prefix"`
+	SystemSuffix string `yaml:"system_suffix"`
+
+	// Timezone members work in when a spec doesn't set one (empty: local)
+	Timezone string `yaml:"timezone"`
+
+	// Saved versions kept per spec (0 uses DefaultMaxSpecVersions)
+	MaxSpecVersions int `yaml:"max_spec_versions"`
+
+	// Per-team resource limits (zero fields use the team defaults)
+	TeamLimits team.Limits `yaml:"team_limits"`
+
+	// How often providers are pinged in the background (0 uses
+	// provider.DefaultHealthCheckInterval, negative turns it off)
+	ProviderCheckInterval time.Duration `yaml:"provider_check_interval"`
+
+	// Redacts sensitive data from every provider request when set
+	Redactor *provider.Redactor `yaml:"-"`
+
+	// Answers repeated provider requests from cache when set
+	ResponseCache *provider.ResponseCache `yaml:"-"`
+
+	// External MCP servers available to every team's roles
+	MCPServers map[string]tools.MCPServerConfig `yaml:"-"`
+
+	// Have a small model title new conversations instead of using their
+	// first message
+	ModelTitles bool `yaml:"model_titles"`
+
+	// Stop teams after this long without asks or activity, until their next
+	// ask (0 keeps them running). A team's settings.idle_stop overrides it.
+	IdleStop time.Duration `yaml:"idle_stop"`
 }
 
+// DefaultMaxSpecVersions is how many versions of each spec are kept by default
+const DefaultMaxSpecVersions = 20
+
 // DefaultConfig returns sensible defaults
 func DefaultConfig() Config {
-	homeDir, _ := os.UserHomeDir()
 	return Config{
-		DataDir:    filepath.Join(homeDir, ".ugudu"),
-		SocketPath: filepath.Join(homeDir, ".ugudu", "ugudu.sock"),
+		DataDir:    config.DataHome(),
+		SocketPath: config.SocketPath(),
 		LogLevel:   "info",
 		LogFormat:  "text",
 	}
@@ -67,8 +136,24 @@ func New(cfg Config, log *logger.Logger) (*Manager, error) {
 
 	// Initialize provider registry
 	providers := provider.NewRegistry()
+	if cfg.Redactor != nil {
+		providers.SetRedactor(cfg.Redactor)
+	}
+	if cfg.ResponseCache != nil {
+		providers.SetResponseCache(cfg.ResponseCache)
+	}
 	providers.AutoDiscover()
 
+	// UGUDU_CASSETTE records or replays provider calls, for tests
+	cassette, err := provider.CassetteFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	if cassette != nil {
+		providers.SetCassette(cassette)
+		log.Info("provider cassette enabled", "mode", cassette.Mode())
+	}
+
 	// Initialize store
 	store, err := NewStore(filepath.Join(cfg.DataDir, "ugudu.db"))
 	if err != nil {
@@ -76,11 +161,21 @@ func New(cfg Config, log *logger.Logger) (*Manager, error) {
 	}
 
 	m := &Manager{
-		teams:     make(map[string]*team.Team),
-		providers: providers,
-		store:     store,
-		config:    cfg,
-		logger:    log,
+		teams:      make(map[string]*team.Team),
+		providers:  providers,
+		store:      store,
+		config:     cfg,
+		logger:     log,
+		lastActive: make(map[string]time.Time),
+		now:        time.Now,
+		summaries:  make(map[string]*ConversationSummary),
+	}
+
+	// Members see the time in their prompts and tools, so a cassette only
+	// matches with the clock stopped at the time it was recorded
+	if cassette != nil && !cassette.RecordedAt().IsZero() {
+		recordedAt := cassette.RecordedAt()
+		m.teamClock = func() time.Time { return recordedAt }
 	}
 
 	return m, nil
@@ -95,10 +190,26 @@ func (m *Manager) Start(ctx context.Context) error {
 		m.logger.Warn("failed to restore teams", "error", err)
 	}
 
+	if m.config.ProviderCheckInterval >= 0 {
+		go m.providers.MonitorHealth(m.ctx, m.config.ProviderCheckInterval, m.logProviderHealth)
+	}
+	go m.watchIdle(m.ctx)
+
 	m.logger.Info("manager started", "data_dir", m.config.DataDir)
 	return nil
 }
 
+// logProviderHealth logs a provider being disabled or re-enabled by the
+// background health checks
+func (m *Manager) logProviderHealth(id string, h provider.Health) {
+	switch h.Status {
+	case provider.HealthUnhealthy:
+		m.logger.Warn("provider unhealthy, members will use fallbacks", "provider", id, "reason", h.Reason, "failures", h.Failures)
+	case provider.HealthOK:
+		m.logger.Info("provider healthy", "provider", id)
+	}
+}
+
 // Stop halts the manager and all teams
 func (m *Manager) Stop() {
 	m.mu.Lock()
@@ -122,67 +233,172 @@ func (m *Manager) Stop() {
 
 // CreateTeam creates and registers a new team from a spec file
 func (m *Manager) CreateTeam(specPath string) (*team.Team, error) {
+	return m.createTeam("", specPath, "", false)
+}
+
+// CreateTeamWithName creates a team with a custom instance name
+func (m *Manager) CreateTeamWithName(name, specPath string) (*team.Team, error) {
+	return m.CreateSeededTeam(name, specPath, "")
+}
+
+// CreateSeededTeam creates a team whose members start with seed, e.g. project
+// background, in their context. The seed is kept with the team, so members
+// are seeded again whenever they start a fresh conversation.
+func (m *Manager) CreateSeededTeam(name, specPath, seed string) (*team.Team, error) {
+	return m.createTeam(name, specPath, seed, false)
+}
+
+// ReplaceTeam creates a team like CreateSeededTeam, first deleting any team
+// of the same name along with its history. The old team is left alone if the
+// new one can't be created.
+func (m *Manager) ReplaceTeam(name, specPath, seed string) (*team.Team, error) {
+	return m.createTeam(name, specPath, seed, true)
+}
+
+func (m *Manager) createTeam(name, specPath, seed string, replace bool) (*team.Team, error) {
 	spec, err := team.LoadSpec(specPath)
 	if err != nil {
 		return nil, fmt.Errorf("load spec: %w", err)
 	}
 
+	// Override the name if provided
+	if name != "" {
+		spec.Metadata.Name = name
+	}
+
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	if _, exists := m.teams[spec.Metadata.Name]; exists {
-		return nil, fmt.Errorf("team %s already exists", spec.Metadata.Name)
+	_, exists := m.teams[spec.Metadata.Name]
+	if exists && !replace {
+		return nil, fmt.Errorf("%w: %s", ErrTeamExists, spec.Metadata.Name)
 	}
 
-	t, err := team.NewTeamWithPersistence(spec, m.providers, m.logger, m.createPersistenceCallbacks())
+	m.applyGlobalPrompt(spec)
+	opts := append(m.teamOptions(), team.WithSeed(seed), team.WithRawSpec(m.loadRawSpec(specPath, spec.Metadata.Name)))
+	t, err := team.NewTeamWithPersistence(spec, m.providers, m.logger, m.createPersistenceCallbacks(), opts...)
 	if err != nil {
 		return nil, fmt.Errorf("create team: %w", err)
 	}
 
+	if exists {
+		m.deleteTeamLocked(spec.Metadata.Name)
+	}
 	m.teams[spec.Metadata.Name] = t
 
 	// Persist
 	if err := m.store.SaveTeam(spec.Metadata.Name, specPath); err != nil {
 		m.logger.Warn("failed to persist team", "error", err)
 	}
+	if seed != "" {
+		if err := m.store.SetTeamSeed(spec.Metadata.Name, seed); err != nil {
+			m.logger.Warn("failed to persist team seed", "error", err)
+		}
+	}
 
-	m.logger.Info("team created", "name", spec.Metadata.Name)
+	m.RecordAudit(AuditEntry{Action: AuditTeamCreate, Team: spec.Metadata.Name, Details: map[string]interface{}{"spec_path": specPath, "replaced": exists}})
+	m.logger.Info("team created", "name", spec.Metadata.Name, "replaced", exists)
 	return t, nil
 }
 
-// CreateTeamWithName creates a team with a custom instance name
-func (m *Manager) CreateTeamWithName(name, specPath string) (*team.Team, error) {
-	spec, err := team.LoadSpec(specPath)
+// ConversationTranscript renders the client-visible messages of a
+// conversation as text, to seed a new team with
+func (m *Manager) ConversationTranscript(conversationID string) (string, error) {
+	messages, err := m.store.GetClientMessages(conversationID)
 	if err != nil {
-		return nil, fmt.Errorf("load spec: %w", err)
+		return "", err
+	}
+	if len(messages) == 0 {
+		return "", fmt.Errorf("%w: %s", ErrConversationNotFound, conversationID)
 	}
 
-	// Override the name if provided
-	if name != "" {
-		spec.Metadata.Name = name
+	var sb strings.Builder
+	sb.WriteString("Transcript of an earlier conversation with the client:\n")
+	for _, msg := range messages {
+		speaker := "client"
+		if msg["role"] == "assistant" {
+			speaker = fmt.Sprint(msg["member_id"])
+		}
+		fmt.Fprintf(&sb, "\n%s: %s\n", speaker, msg["content"])
 	}
+	return sb.String(), nil
+}
 
-	m.mu.Lock()
-	defer m.mu.Unlock()
+// applyGlobalPrompt wraps the configured global prefix/suffix around the
+// team's own, unless the spec opts out
+func (m *Manager) applyGlobalPrompt(spec *team.TeamSpec) {
+	if spec.Settings.IgnoreGlobalPrompt {
+		return
+	}
+	spec.Settings.SystemPrefix = joinPrompt(m.config.SystemPrefix, spec.Settings.SystemPrefix)
+	spec.Settings.SystemSuffix = joinPrompt(spec.Settings.SystemSuffix, m.config.SystemSuffix)
+}
 
-	if _, exists := m.teams[spec.Metadata.Name]; exists {
-		return nil, fmt.Errorf("team %s already exists", spec.Metadata.Name)
+// ExportTeamSpec returns the spec to save a running team as: the one it
+// shows, with the global prefix/suffix the daemon wrapped around its own
+// taken back out, so a team created from it doesn't get them twice
+func (m *Manager) ExportTeamSpec(name string) (*team.TeamSpec, error) {
+	t, err := m.GetTeam(name)
+	if err != nil {
+		return nil, err
 	}
+	spec := *t.ExportSpec()
+	if !spec.Settings.IgnoreGlobalPrompt {
+		spec.Settings.SystemPrefix = unjoinPrompt(m.config.SystemPrefix, spec.Settings.SystemPrefix, false)
+		spec.Settings.SystemSuffix = unjoinPrompt(m.config.SystemSuffix, spec.Settings.SystemSuffix, true)
+	}
+	return &spec, nil
+}
 
-	t, err := team.NewTeamWithPersistence(spec, m.providers, m.logger, m.createPersistenceCallbacks())
+// loadRawSpec loads a team's spec without expanding its secrets, named and
+// prompted like the running spec. It returns nil if the spec can't be loaded
+// that way, e.g. a reference stands in for a number; the team then redacts
+// its running spec instead.
+func (m *Manager) loadRawSpec(specPath, name string) *team.TeamSpec {
+	spec, err := team.LoadRawSpec(specPath)
 	if err != nil {
-		return nil, fmt.Errorf("create team: %w", err)
+		m.logger.Debug("failed to load unexpanded team spec", "name", name, "error", err)
+		return nil
 	}
+	spec.Metadata.Name = name
+	m.applyGlobalPrompt(spec)
+	return spec
+}
 
-	m.teams[spec.Metadata.Name] = t
+func joinPrompt(first, second string) string {
+	switch {
+	case first == "":
+		return second
+	case second == "":
+		return first
+	}
+	return strings.TrimRight(first, "\n") + "\n\n" + second
+}
 
-	// Persist
-	if err := m.store.SaveTeam(spec.Metadata.Name, specPath); err != nil {
-		m.logger.Warn("failed to persist team", "error", err)
+// unjoinPrompt takes global back out of a prompt joinPrompt put it at the
+// start of, or the end of if suffix is set
+func unjoinPrompt(global, prompt string, suffix bool) string {
+	if global == "" || prompt == global {
+		return strings.TrimPrefix(prompt, global)
+	}
+	if suffix {
+		return strings.TrimSuffix(prompt, "\n\n"+global)
 	}
+	return strings.TrimPrefix(prompt, strings.TrimRight(global, "\n")+"\n\n")
+}
 
-	m.logger.Info("team created", "name", spec.Metadata.Name)
-	return t, nil
+// teamOptions are the options every team is created with
+func (m *Manager) teamOptions() []team.TeamOption {
+	clock := m.now
+	if m.teamClock != nil {
+		clock = m.teamClock
+	}
+	return []team.TeamOption{
+		team.WithLimits(m.config.TeamLimits),
+		team.WithMCPServers(m.config.MCPServers),
+		team.WithClock(clock),
+		team.WithDefaultTimezone(m.config.Timezone),
+	}
 }
 
 // createPersistenceCallbacks returns callbacks for team persistence
@@ -222,12 +438,65 @@ func (m *Manager) createPersistenceCallbacks() *team.PersistenceCallbacks {
 			}
 			return conv.ID, nil
 		},
-		OnActivity: func(teamName, memberID, activityType, message string) {
+		SaveClientMember: func(teamName, conversationID, memberID string) error {
+			return m.store.SetConversationClientMember(conversationID, memberID)
+		},
+		LoadClientMember: func(teamName, conversationID string) (string, error) {
+			return m.store.ConversationClientMember(conversationID)
+		},
+		SaveMessage: func(teamName, conversationID string, msg team.Message) error {
+			content, ok := msg.Content.(string)
+			if !ok {
+				data, err := json.Marshal(msg.Content)
+				if err != nil {
+					return fmt.Errorf("encode message content: %w", err)
+				}
+				content = string(data)
+			}
+			m.auditMessage(teamName, conversationID, content, msg)
+
+			var convID interface{}
+			if conversationID != "" {
+				convID = conversationID
+			}
+			if err := m.store.SaveMessage(teamName, map[string]interface{}{
+				"id":              msg.ID,
+				"type":            string(msg.Type),
+				"from":            msg.From,
+				"to":              msg.To,
+				"content":         content,
+				"task_id":         msg.TaskID,
+				"conversation_id": convID,
+			}); err != nil {
+				return err
+			}
+
+			if conversationID == "" {
+				return nil
+			}
+			if msg.Type == team.MsgClientRequest {
+				m.titleConversation(conversationID, content)
+			}
+			return m.store.UpdateConversationTimestamp(conversationID)
+		},
+		SaveApproval: func(teamName string, approval team.Approval) error {
+			return m.store.SaveApproval(teamName, approval)
+		},
+		Audit: func(teamName, memberID, action, requestID string, details map[string]interface{}) {
+			m.RecordAudit(AuditEntry{Action: action, Team: teamName, Actor: memberID, RequestID: requestID, Details: details})
+		},
+		RecordCall: func(teamName string, call team.ProviderCall) {
+			if err := m.store.AppendProviderCall(teamName, call); err != nil {
+				m.logger.Warn("failed to record provider call", "team", teamName, "member", call.MemberID, "error", err)
+			}
+		},
+		OnActivity: func(teamName, memberID, activityType, message, requestID string, data map[string]interface{}) {
+			m.touch(teamName)
 			m.mu.RLock()
 			cb := m.onActivity
 			m.mu.RUnlock()
 			if cb != nil {
-				cb(teamName, memberID, activityType, message)
+				cb(teamName, memberID, activityType, message, requestID, data)
 			}
 		},
 	}
@@ -240,15 +509,17 @@ func (m *Manager) StartTeam(name string) error {
 	m.mu.RUnlock()
 
 	if !ok {
-		return fmt.Errorf("team not found: %s", name)
+		return fmt.Errorf("%w: %s", ErrTeamNotFound, name)
 	}
 
 	if err := t.Start(m.ctx); err != nil {
 		return fmt.Errorf("start team: %w", err)
 	}
+	m.touch(name)
 
 	// Update store
 	m.store.UpdateTeamStatus(name, "running")
+	m.RecordAudit(AuditEntry{Action: AuditTeamStart, Team: name})
 
 	return nil
 }
@@ -260,12 +531,47 @@ func (m *Manager) StopTeam(name string) error {
 	m.mu.RUnlock()
 
 	if !ok {
-		return fmt.Errorf("team not found: %s", name)
+		return fmt.Errorf("%w: %s", ErrTeamNotFound, name)
 	}
 
 	t.Stop()
 	m.store.UpdateTeamStatus(name, "stopped")
+	m.RecordAudit(AuditEntry{Action: AuditTeamStop, Team: name})
+
+	return nil
+}
+
+// RestartTeam stops a team, cancelling any request in flight, waits for its
+// members to finish, and starts it again. Members reload their saved
+// context, so conversations carry on where they left off. If they don't
+// finish within restartDrainTimeout the team is left stopped.
+func (m *Manager) RestartTeam(name string) error {
+	t, err := m.GetTeam(name)
+	if err != nil {
+		return err
+	}
+
+	// Idle stops and wakes would otherwise see the team half restarted
+	m.idleMu.Lock()
+	defer m.idleMu.Unlock()
+
+	t.Stop()
+	ctx, cancel := context.WithTimeout(context.Background(), restartDrainTimeout)
+	defer cancel()
+	if err := t.Wait(ctx); err != nil {
+		m.store.UpdateTeamStatus(name, "stopped")
+		return fmt.Errorf("restart team: %w; the team is stopped, start it once its members finish", err)
+	}
+
+	if err := t.Start(m.ctx); err != nil {
+		m.store.UpdateTeamStatus(name, "stopped")
+		return fmt.Errorf("start team: %w", err)
+	}
+	m.touch(name)
+	m.store.UpdateTeamStatus(name, "running")
+	m.RecordAudit(AuditEntry{Action: AuditTeamRestart, Team: name})
 
+	m.logger.Info("team restarted", "name", name)
 	return nil
 }
 
@@ -274,17 +580,26 @@ func (m *Manager) DeleteTeam(name string) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	t, ok := m.teams[name]
-	if !ok {
-		return fmt.Errorf("team not found: %s", name)
+	if _, ok := m.teams[name]; !ok {
+		return fmt.Errorf("%w: %s", ErrTeamNotFound, name)
 	}
 
-	t.Stop()
+	m.deleteTeamLocked(name)
+	return nil
+}
+
+// deleteTeamLocked stops and forgets a team. m.mu must be held.
+func (m *Manager) deleteTeamLocked(name string) {
+	m.teams[name].Stop()
 	delete(m.teams, name)
 	m.store.DeleteTeam(name)
 
+	m.activeMu.Lock()
+	delete(m.lastActive, name)
+	m.activeMu.Unlock()
+
+	m.RecordAudit(AuditEntry{Action: AuditTeamDelete, Team: name})
 	m.logger.Info("team deleted", "name", name)
-	return nil
 }
 
 // GetTeam returns a team by name
@@ -294,11 +609,32 @@ func (m *Manager) GetTeam(name string) (*team.Team, error) {
 
 	t, ok := m.teams[name]
 	if !ok {
-		return nil, fmt.Errorf("team not found: %s", name)
+		return nil, fmt.Errorf("%w: %s", ErrTeamNotFound, name)
 	}
 	return t, nil
 }
 
+// SetTokenMode changes a team's token mode. With persist the team keeps the
+// mode across daemon restarts; otherwise it lasts until the next one.
+func (m *Manager) SetTokenMode(teamName string, mode team.TokenMode, persist bool) error {
+	t, err := m.GetTeam(teamName)
+	if err != nil {
+		return err
+	}
+	t.SetTokenMode(mode)
+
+	m.RecordAudit(AuditEntry{Action: AuditTokenMode, Team: teamName, Details: map[string]interface{}{
+		"mode": string(mode), "persist": persist,
+	}})
+
+	if persist {
+		if err := m.store.SetTeamTokenMode(teamName, string(mode)); err != nil {
+			return fmt.Errorf("save token mode: %w", err)
+		}
+	}
+	return nil
+}
+
 // ListTeams returns all teams
 func (m *Manager) ListTeams() []*team.Team {
 	m.mu.RLock()
@@ -312,23 +648,180 @@ func (m *Manager) ListTeams() []*team.Team {
 }
 
 // Ask sends a message to a team
-func (m *Manager) Ask(teamName, message string) (<-chan team.Message, error) {
+func (m *Manager) Ask(teamName, message string, opts ...team.AskOption) (<-chan team.Message, error) {
 	t, err := m.GetTeam(teamName)
 	if err != nil {
 		return nil, err
 	}
 
-	return t.Ask(message), nil
+	if t.Paused() {
+		return nil, fmt.Errorf("%w: %s", team.ErrTeamPaused, teamName)
+	}
+	if err := m.wake(teamName, t); err != nil {
+		return nil, err
+	}
+
+	return t.Ask(message, opts...), nil
 }
 
 // AskMember sends a message to a specific team member
-func (m *Manager) AskMember(teamName, role, message string) (<-chan team.Message, error) {
+func (m *Manager) AskMember(teamName, role, message string, opts ...team.AskOption) (<-chan team.Message, error) {
 	t, err := m.GetTeam(teamName)
 	if err != nil {
 		return nil, err
 	}
 
-	return t.AskMember(role, message), nil
+	if t.Paused() {
+		return nil, fmt.Errorf("%w: %s", team.ErrTeamPaused, teamName)
+	}
+	if err := m.wake(teamName, t); err != nil {
+		return nil, err
+	}
+
+	return t.AskMember(role, message, opts...), nil
+}
+
+// PauseTeam stops a team accepting new asks while keeping its members and
+// their context
+func (m *Manager) PauseTeam(name string) error {
+	t, err := m.GetTeam(name)
+	if err != nil {
+		return err
+	}
+	t.Pause()
+	m.RecordAudit(AuditEntry{Action: AuditTeamPause, Team: name})
+	return nil
+}
+
+// ResumeTeam lets a paused team accept asks again
+func (m *Manager) ResumeTeam(name string) error {
+	t, err := m.GetTeam(name)
+	if err != nil {
+		return err
+	}
+	t.Resume()
+	m.RecordAudit(AuditEntry{Action: AuditTeamResume, Team: name})
+	return nil
+}
+
+// ClearMemberContext resets one member's conversation context, in memory and
+// in the store, so it starts fresh on its next message. The rest of the team
+// and the active conversation are left as they are.
+func (m *Manager) ClearMemberContext(teamName, memberID string) error {
+	t, err := m.GetTeam(teamName)
+	if err != nil {
+		return err
+	}
+	member := t.GetMember(memberID)
+	if member == nil {
+		return fmt.Errorf("%w: %s", ErrMemberNotFound, memberID)
+	}
+
+	if m.store != nil {
+		if err := m.store.ClearAgentContext(teamName, memberID); err != nil {
+			return fmt.Errorf("clear stored context: %w", err)
+		}
+	}
+	member.ClearContext()
+	m.logger.Info("member context cleared", "team", teamName, "member", memberID)
+	return nil
+}
+
+// MemberPrompt returns the system prompt and tools a member is currently
+// given, for debugging personas and token modes
+func (m *Manager) MemberPrompt(teamName, memberID string) (team.MemberPrompt, error) {
+	t, err := m.GetTeam(teamName)
+	if err != nil {
+		return team.MemberPrompt{}, err
+	}
+	member := t.GetMember(memberID)
+	if member == nil {
+		return team.MemberPrompt{}, fmt.Errorf("%w: %s", ErrMemberNotFound, memberID)
+	}
+	return member.Prompt(), nil
+}
+
+// StartProject starts a project on a team for a client request
+func (m *Manager) StartProject(teamName, request string) (map[string]interface{}, error) {
+	t, err := m.GetTeam(teamName)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.wake(teamName, t); err != nil {
+		return nil, err
+	}
+	return t.StartProject(request)
+}
+
+// ProjectStatus returns the status of a team's active project
+func (m *Manager) ProjectStatus(teamName string) (map[string]interface{}, error) {
+	t, err := m.GetTeam(teamName)
+	if err != nil {
+		return nil, err
+	}
+	return t.ProjectStatus()
+}
+
+// PendingQuestions returns the questions a team's members are waiting on the
+// client to answer
+func (m *Manager) PendingQuestions(name string) ([]team.Question, error) {
+	t, err := m.GetTeam(name)
+	if err != nil {
+		return nil, err
+	}
+	return t.PendingQuestions(), nil
+}
+
+// AnswerQuestion answers a pending question, resuming the member that asked it
+func (m *Manager) AnswerQuestion(name, questionID, answer string) error {
+	t, err := m.GetTeam(name)
+	if err != nil {
+		return err
+	}
+	return t.AnswerQuestion(questionID, answer)
+}
+
+// PendingApprovals returns the tool calls a team's members are waiting on a
+// human to approve
+func (m *Manager) PendingApprovals(name string) ([]team.Approval, error) {
+	t, err := m.GetTeam(name)
+	if err != nil {
+		return nil, err
+	}
+	return t.PendingApprovals(), nil
+}
+
+// ApprovalHistory returns a team's most recent approvals and decisions,
+// including those from earlier runs
+func (m *Manager) ApprovalHistory(name string, limit int) ([]team.Approval, error) {
+	if _, err := m.GetTeam(name); err != nil {
+		return nil, err
+	}
+	return m.store.ListApprovals(name, limit)
+}
+
+// TeamPerformance summarizes the latency and outcome of a team's model
+// calls, those since since if it's set, including earlier runs
+func (m *Manager) TeamPerformance(name string, since time.Time) (*TeamPerformance, error) {
+	if _, err := m.GetTeam(name); err != nil {
+		return nil, err
+	}
+	return m.store.TeamPerformance(name, since)
+}
+
+// DecideApproval approves or denies a pending tool call, resuming the member
+// waiting on it
+func (m *Manager) DecideApproval(name, approvalID string, approved bool, reason string) (team.Approval, error) {
+	t, err := m.GetTeam(name)
+	if err != nil {
+		return team.Approval{}, err
+	}
+	return t.DecideApproval(approvalID, approved, reason)
+}
+
+// DataDir returns the directory holding the manager's database and state
+func (m *Manager) DataDir() string {
+	return m.config.DataDir
 }
 
 // Status returns overall manager status
@@ -343,10 +836,14 @@ func (m *Manager) Status() map[string]interface{} {
 
 	providers := make([]map[string]interface{}, 0)
 	for _, p := range m.providers.List() {
-		providers = append(providers, map[string]interface{}{
+		entry := map[string]interface{}{
 			"id":   p.ID(),
 			"name": p.Name(),
-		})
+		}
+		if stats, err := m.providers.Stats(p.ID()); err == nil {
+			entry["stats"] = stats
+		}
+		providers = append(providers, entry)
 	}
 
 	return map[string]interface{}{
@@ -357,6 +854,86 @@ func (m *Manager) Status() map[string]interface{} {
 	}
 }
 
+// Health check states
+const (
+	HealthOK        = "ok"
+	HealthDegraded  = "degraded"
+	HealthUnhealthy = "unhealthy"
+)
+
+// healthCheckTimeout bounds each component check in CheckHealth
+const healthCheckTimeout = 5 * time.Second
+
+// CheckHealth pings the store and every provider and returns an aggregate
+// status with per-component detail. The daemon is unhealthy when the store
+// is down or no provider is reachable, and degraded when only some are.
+func (m *Manager) CheckHealth(ctx context.Context) map[string]interface{} {
+	store := map[string]interface{}{"status": HealthOK}
+	if err := m.store.Ping(ctx); err != nil {
+		store = map[string]interface{}{"status": HealthUnhealthy, "error": err.Error()}
+	}
+
+	list := m.providers.List()
+	providers := make([]map[string]interface{}, len(list))
+	var wg sync.WaitGroup
+	for i, p := range list {
+		wg.Add(1)
+		go func(i int, p provider.Provider) {
+			defer wg.Done()
+
+			pingCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+			defer cancel()
+
+			start := time.Now()
+			_, err := m.providers.Check(pingCtx, p.ID())
+
+			result := map[string]interface{}{
+				"id":         p.ID(),
+				"name":       p.Name(),
+				"status":     HealthOK,
+				"latency_ms": time.Since(start).Milliseconds(),
+			}
+			if err != nil {
+				result["status"] = HealthUnhealthy
+				result["error"] = err.Error()
+			}
+			providers[i] = result
+		}(i, p)
+	}
+	wg.Wait()
+
+	healthy := 0
+	for _, p := range providers {
+		if p["status"] == HealthOK {
+			healthy++
+		}
+	}
+
+	status := HealthOK
+	switch {
+	case store["status"] != HealthOK || healthy == 0:
+		status = HealthUnhealthy
+	case healthy < len(providers):
+		status = HealthDegraded
+	}
+
+	return map[string]interface{}{
+		"status":    status,
+		"store":     store,
+		"providers": providers,
+	}
+}
+
+// RecordSpecVersion saves spec content to the spec's version history and
+// returns the latest version number
+func (m *Manager) RecordSpecVersion(name, content string) (int, error) {
+	keep := m.config.MaxSpecVersions
+	if keep == 0 {
+		keep = DefaultMaxSpecVersions
+	}
+	return m.store.SaveSpecVersion(name, content, keep)
+}
+
 // Providers returns the provider registry
 func (m *Manager) Providers() *provider.Registry {
 	return m.providers
@@ -393,12 +970,21 @@ func (m *Manager) restoreTeams() error {
 		spec.Metadata.Name = saved.Name
 
 		// Create team with persistence callbacks for context restoration
-		t, err := team.NewTeamWithPersistence(spec, m.providers, m.logger, m.createPersistenceCallbacks())
+		m.applyGlobalPrompt(spec)
+		opts := append(m.teamOptions(), team.WithSeed(saved.Seed), team.WithRawSpec(m.loadRawSpec(saved.SpecPath, saved.Name)))
+		t, err := team.NewTeamWithPersistence(spec, m.providers, m.logger, m.createPersistenceCallbacks(), opts...)
 		if err != nil {
 			m.logger.Warn("failed to restore team", "name", saved.Name, "error", err)
 			continue
 		}
 
+		if saved.TokenMode != "" {
+			t.SetTokenMode(team.TokenMode(saved.TokenMode))
+		}
+		if saved.Status == team.StateAutoStopped {
+			t.AutoStop() // Started again by its next ask
+		}
+
 		m.teams[saved.Name] = t
 		m.logger.Info("team restored", "name", saved.Name)
 
@@ -420,9 +1006,11 @@ func (m *Manager) restoreTeams() error {
 
 // SavedTeam represents a persisted team record
 type SavedTeam struct {
-	Name     string `json:"name"`
-	SpecPath string `json:"spec_path"`
-	Status   string `json:"status"`
+	Name      string `json:"name"`
+	SpecPath  string `json:"spec_path"`
+	Status    string `json:"status"`
+	TokenMode string `json:"token_mode,omitempty"` // Set when a token mode change was persisted
+	Seed      string `json:"seed,omitempty"`       // Background the team's members start with
 }
 
 // ToJSON serializes manager status to JSON
@@ -2,14 +2,43 @@ package manager
 
 import (
 	"context"
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/arcslash/ugudu/internal/logger"
+	"github.com/arcslash/ugudu/internal/provider"
+	"github.com/arcslash/ugudu/internal/team"
 )
 
+// stubProvider answers every chat request with a fixed reply
+type stubProvider struct {
+	id      string
+	reply   string
+	pingErr error
+}
+
+func (p *stubProvider) ID() string   { return p.id }
+func (p *stubProvider) Name() string { return "Stub " + p.id }
+
+func (p *stubProvider) Chat(ctx context.Context, req *provider.ChatRequest) (*provider.ChatResponse, error) {
+	return &provider.ChatResponse{Content: p.reply, Provider: "stub", Model: req.Model}, nil
+}
+
+func (p *stubProvider) Stream(ctx context.Context, req *provider.ChatRequest) (<-chan provider.StreamChunk, error) {
+	return nil, nil
+}
+
+func (p *stubProvider) ListModels(ctx context.Context) ([]provider.ModelInfo, error) {
+	return nil, nil
+}
+
+func (p *stubProvider) Ping(ctx context.Context) error { return p.pingErr }
+
 func TestManager_BasicLifecycle(t *testing.T) {
 	tmpDir := t.TempDir()
 	log := logger.New("error")
@@ -42,6 +71,28 @@ func TestManager_BasicLifecycle(t *testing.T) {
 	mgr.Stop()
 }
 
+func TestManager_CassetteStopsTeamClock(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("UGUDU_CASSETTE", filepath.Join(tmpDir, "flow.json"))
+	t.Setenv("UGUDU_CASSETTE_MODE", provider.CassetteRecord)
+
+	mgr, err := New(Config{DataDir: tmpDir, SocketPath: filepath.Join(tmpDir, "test.sock")}, logger.New("error"))
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	defer mgr.store.Close()
+
+	tm, err := team.NewTeam(&team.TeamSpec{Metadata: team.Metadata{Name: "clock-team"}}, mgr.providers, logger.New("error"), mgr.teamOptions()...)
+	if err != nil {
+		t.Fatalf("NewTeam failed: %v", err)
+	}
+	first := tm.Now()
+	time.Sleep(10 * time.Millisecond)
+	if !tm.Now().Equal(first) || time.Since(first) > time.Minute {
+		t.Errorf("Expected members' clock stopped at the recording time, got %v then %v", first, tm.Now())
+	}
+}
+
 func TestManager_PersistenceCallbacks(t *testing.T) {
 	tmpDir := t.TempDir()
 	log := logger.New("error")
@@ -314,3 +365,975 @@ func TestManager_ConcurrentAccess(t *testing.T) {
 		}
 	}
 }
+
+func TestManager_ClientTranscript(t *testing.T) {
+	tmpDir := t.TempDir()
+	log := logger.New("error")
+
+	specContent := `
+apiVersion: ugudu/v1
+kind: Team
+metadata:
+  name: transcript-test
+
+client_facing:
+  - lead
+
+roles:
+  lead:
+    title: Team Lead
+    visibility: client
+    model:
+      provider: stub
+      model: stub-model
+    persona: You are the team lead.
+`
+	specPath := filepath.Join(tmpDir, "transcript-test.yaml")
+	os.WriteFile(specPath, []byte(specContent), 0644)
+
+	cfg := Config{
+		DataDir:    tmpDir,
+		SocketPath: filepath.Join(tmpDir, "test.sock"),
+		LogLevel:   "error",
+	}
+
+	mgr, err := New(cfg, log)
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	defer mgr.Stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	mgr.Start(ctx)
+	mgr.Providers().Register(&stubProvider{id: "stub", reply: "Happy to help with that."})
+
+	tm, err := mgr.CreateTeam(specPath)
+	if err != nil {
+		t.Fatalf("CreateTeam failed: %v", err)
+	}
+	if err := mgr.StartTeam("transcript-test"); err != nil {
+		t.Fatalf("StartTeam failed: %v", err)
+	}
+
+	responses, err := mgr.Ask("transcript-test", "Can you build a login page?")
+	if err != nil {
+		t.Fatalf("Ask failed: %v", err)
+	}
+
+	select {
+	case msg := <-responses:
+		if msg.Content != "Happy to help with that." {
+			t.Fatalf("Unexpected response: %v", msg.Content)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for response")
+	}
+
+	transcript, err := mgr.Store().GetClientMessages(tm.GetConversationID())
+	if err != nil {
+		t.Fatalf("GetClientMessages failed: %v", err)
+	}
+
+	expected := []struct {
+		role    string
+		content string
+	}{
+		{"user", "Can you build a login page?"},
+		{"assistant", "Happy to help with that."},
+	}
+	if len(transcript) != len(expected) {
+		t.Fatalf("Expected %d persisted messages, got %d: %v", len(expected), len(transcript), transcript)
+	}
+	for i, want := range expected {
+		if transcript[i]["role"] != want.role || transcript[i]["content"] != want.content {
+			t.Errorf("Message %d: expected %s %q, got %v", i, want.role, want.content, transcript[i])
+		}
+	}
+
+	// The conversation is titled after the client's first message
+	conversations, err := mgr.Store().ListConversations("transcript-test", 10)
+	if err != nil || len(conversations) != 1 {
+		t.Fatalf("Expected 1 conversation, got %v (%v)", conversations, err)
+	}
+	if conversations[0].Title != "Can you build a login page?" {
+		t.Errorf("Expected the first message as the title, got %q", conversations[0].Title)
+	}
+	if err := mgr.RenameConversation(tm.GetConversationID(), "Login page"); err != nil {
+		t.Fatalf("RenameConversation failed: %v", err)
+	}
+	if err := mgr.RenameConversation("no-such-conversation", "Login page"); !errors.Is(err, ErrConversationNotFound) {
+		t.Errorf("Expected ErrConversationNotFound renaming an unknown conversation, got %v", err)
+	}
+
+	// A new team can start from the conversation, and keeps its seed
+	seed, err := mgr.ConversationTranscript(tm.GetConversationID())
+	if err != nil {
+		t.Fatalf("ConversationTranscript failed: %v", err)
+	}
+	if !strings.Contains(seed, "client: Can you build a login page?") || !strings.Contains(seed, "lead: Happy to help with that.") {
+		t.Errorf("Unexpected transcript: %q", seed)
+	}
+	if _, err := mgr.CreateSeededTeam("follow-up", specPath, seed); err != nil {
+		t.Fatalf("CreateSeededTeam failed: %v", err)
+	}
+	if saved, _ := mgr.Store().GetTeam("follow-up"); saved == nil || saved.Seed != seed {
+		t.Errorf("Expected the seed persisted, got %+v", saved)
+	}
+
+	if _, err := mgr.ConversationTranscript("no-such-conversation"); !errors.Is(err, ErrConversationNotFound) {
+		t.Errorf("Expected ErrConversationNotFound, got %v", err)
+	}
+}
+
+func TestManager_CreateTeamTwice(t *testing.T) {
+	tmpDir := t.TempDir()
+	log := logger.New("error")
+
+	specContent := `apiVersion: ugudu/v1
+kind: Team
+metadata:
+  name: twice-test
+roles:
+  lead:
+    title: Team Lead
+    visibility: client
+    model:
+      provider: stub
+      model: stub-model
+    persona: You are the team lead.
+`
+	specPath := filepath.Join(tmpDir, "twice-test.yaml")
+	os.WriteFile(specPath, []byte(specContent), 0644)
+
+	cfg := Config{
+		DataDir:    tmpDir,
+		SocketPath: filepath.Join(tmpDir, "test.sock"),
+		LogLevel:   "error",
+	}
+
+	mgr, err := New(cfg, log)
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	defer mgr.Stop()
+	mgr.Providers().Register(&stubProvider{id: "stub", reply: "Hello."})
+
+	first, err := mgr.CreateTeam(specPath)
+	if err != nil {
+		t.Fatalf("CreateTeam failed: %v", err)
+	}
+	if err := mgr.Store().SetTeamSeed("twice-test", "old background"); err != nil {
+		t.Fatalf("SetTeamSeed failed: %v", err)
+	}
+
+	if _, err := mgr.CreateTeam(specPath); !errors.Is(err, ErrTeamExists) {
+		t.Errorf("Expected ErrTeamExists creating the team again, got %v", err)
+	}
+	if _, err := mgr.CreateTeamWithName("twice-test", specPath); !errors.Is(err, ErrTeamExists) {
+		t.Errorf("Expected ErrTeamExists from CreateTeamWithName, got %v", err)
+	}
+	if got, _ := mgr.GetTeam("twice-test"); got != first {
+		t.Error("Expected the first team left in place")
+	}
+
+	// A replacement that can't be created leaves the old team alone
+	if _, err := mgr.ReplaceTeam("twice-test", filepath.Join(tmpDir, "missing.yaml"), ""); err == nil {
+		t.Error("Expected an error replacing with a missing spec")
+	}
+	if got, _ := mgr.GetTeam("twice-test"); got != first {
+		t.Error("Expected the first team kept after a failed replace")
+	}
+
+	second, err := mgr.ReplaceTeam("twice-test", specPath, "")
+	if err != nil {
+		t.Fatalf("ReplaceTeam failed: %v", err)
+	}
+	if got, _ := mgr.GetTeam("twice-test"); got != second || second == first {
+		t.Error("Expected the team replaced")
+	}
+	if saved, _ := mgr.Store().GetTeam("twice-test"); saved == nil || saved.Seed != "" {
+		t.Errorf("Expected the replaced team saved without the old seed, got %+v", saved)
+	}
+}
+
+func TestManager_CheckHealth(t *testing.T) {
+	tmpDir := t.TempDir()
+	log := logger.New("error")
+
+	cfg := Config{
+		DataDir:    tmpDir,
+		SocketPath: filepath.Join(tmpDir, "test.sock"),
+		LogLevel:   "error",
+	}
+
+	mgr, err := New(cfg, log)
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	defer mgr.Stop()
+
+	mgr.Providers().Register(&stubProvider{id: "up"})
+	mgr.Providers().Register(&stubProvider{id: "down", pingErr: errors.New("connection refused")})
+
+	health := mgr.CheckHealth(context.Background())
+	if health["status"] != HealthDegraded {
+		t.Errorf("Expected degraded status with one provider down, got %v", health["status"])
+	}
+
+	store := health["store"].(map[string]interface{})
+	if store["status"] != HealthOK {
+		t.Errorf("Expected store ok, got %v", store["status"])
+	}
+
+	statuses := make(map[string]interface{})
+	for _, p := range health["providers"].([]map[string]interface{}) {
+		statuses[p["id"].(string)] = p["status"]
+	}
+	if statuses["up"] != HealthOK || statuses["down"] != HealthUnhealthy {
+		t.Errorf("Unexpected provider statuses: %v", statuses)
+	}
+
+	// A broken store is critical
+	mgr.Store().Close()
+	health = mgr.CheckHealth(context.Background())
+	if health["status"] != HealthUnhealthy {
+		t.Errorf("Expected unhealthy status with store down, got %v", health["status"])
+	}
+}
+
+// historyProvider replies like stubProvider and records how many messages
+// each request carried, keyed by its last message
+type historyProvider struct {
+	stubProvider
+	mu   sync.Mutex
+	seen map[string]int
+}
+
+func (p *historyProvider) Chat(ctx context.Context, req *provider.ChatRequest) (*provider.ChatResponse, error) {
+	p.mu.Lock()
+	p.seen[req.Messages[len(req.Messages)-1].Content] = len(req.Messages)
+	p.mu.Unlock()
+	return p.stubProvider.Chat(ctx, req)
+}
+
+func TestManager_ClearMemberContext(t *testing.T) {
+	tmpDir := t.TempDir()
+	log := logger.New("error")
+
+	specContent := `
+apiVersion: ugudu/v1
+kind: Team
+metadata:
+  name: reset-test
+
+client_facing:
+  - lead
+
+roles:
+  lead:
+    title: Team Lead
+    visibility: client
+    model:
+      provider: stub
+      model: stub-model
+  engineer:
+    title: Engineer
+    model:
+      provider: stub
+      model: stub-model
+`
+	specPath := filepath.Join(tmpDir, "reset-test.yaml")
+	os.WriteFile(specPath, []byte(specContent), 0644)
+
+	mgr, err := New(Config{DataDir: tmpDir, SocketPath: filepath.Join(tmpDir, "test.sock"), LogLevel: "error"}, log)
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	defer mgr.Stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	mgr.Start(ctx)
+	prov := &historyProvider{stubProvider: stubProvider{id: "stub", reply: "On it."}, seen: make(map[string]int)}
+	mgr.Providers().Register(prov)
+
+	if _, err := mgr.CreateTeam(specPath); err != nil {
+		t.Fatalf("CreateTeam failed: %v", err)
+	}
+	if err := mgr.StartTeam("reset-test"); err != nil {
+		t.Fatalf("StartTeam failed: %v", err)
+	}
+
+	ask := func(role, content string) {
+		t.Helper()
+		responses, err := mgr.AskMember("reset-test", role, content)
+		if err != nil {
+			t.Fatalf("AskMember failed: %v", err)
+		}
+		select {
+		case <-responses:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Timed out waiting for %s", role)
+		}
+	}
+	ask("lead", "Plan the release")
+	ask("engineer", "Fix the flaky test")
+
+	if err := mgr.ClearMemberContext("reset-test", "engineer"); err != nil {
+		t.Fatalf("ClearMemberContext failed: %v", err)
+	}
+
+	stored := func(member string) int {
+		msgs, err := mgr.Store().LoadAgentContext("reset-test", member, 50)
+		if err != nil {
+			t.Fatalf("LoadAgentContext failed: %v", err)
+		}
+		return len(msgs)
+	}
+	if n := stored("engineer"); n != 0 {
+		t.Errorf("Expected the engineer's stored context to be cleared, got %d messages", n)
+	}
+	if n := stored("lead"); n != 2 {
+		t.Errorf("Expected the lead's stored context to be kept, got %d messages", n)
+	}
+
+	// In memory too: the engineer starts over, the lead still has its history
+	ask("engineer", "Start again")
+	ask("lead", "Any update?")
+	prov.mu.Lock()
+	defer prov.mu.Unlock()
+	if n := prov.seen["Start again"]; n != 2 {
+		t.Errorf("Expected the engineer's next request to have only the system prompt and message, got %d messages", n)
+	}
+	if n := prov.seen["Any update?"]; n != 4 {
+		t.Errorf("Expected the lead's next request to include its history, got %d messages", n)
+	}
+
+	if err := mgr.ClearMemberContext("reset-test", "nobody"); !errors.Is(err, ErrMemberNotFound) {
+		t.Errorf("Expected ErrMemberNotFound, got %v", err)
+	}
+}
+
+func TestManager_RestartTeam(t *testing.T) {
+	tmpDir := t.TempDir()
+	log := logger.New("error")
+
+	specContent := `
+apiVersion: ugudu/v1
+kind: Team
+metadata:
+  name: restart-test
+
+client_facing:
+  - lead
+
+roles:
+  lead:
+    title: Team Lead
+    visibility: client
+    model:
+      provider: stub
+      model: stub-model
+`
+	specPath := filepath.Join(tmpDir, "restart-test.yaml")
+	os.WriteFile(specPath, []byte(specContent), 0644)
+
+	mgr, err := New(Config{DataDir: tmpDir, SocketPath: filepath.Join(tmpDir, "test.sock"), LogLevel: "error"}, log)
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	defer mgr.Stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	mgr.Start(ctx)
+	prov := &historyProvider{stubProvider: stubProvider{id: "stub", reply: "On it."}, seen: make(map[string]int)}
+	mgr.Providers().Register(prov)
+
+	if _, err := mgr.CreateTeam(specPath); err != nil {
+		t.Fatalf("CreateTeam failed: %v", err)
+	}
+	if err := mgr.StartTeam("restart-test"); err != nil {
+		t.Fatalf("StartTeam failed: %v", err)
+	}
+
+	ask := func(content string) {
+		t.Helper()
+		responses, err := mgr.AskMember("restart-test", "lead", content)
+		if err != nil {
+			t.Fatalf("AskMember failed: %v", err)
+		}
+		select {
+		case <-responses:
+		case <-time.After(5 * time.Second):
+			t.Fatal("Timed out waiting for the lead")
+		}
+	}
+	ask("Plan the release")
+
+	if err := mgr.RestartTeam("restart-test"); err != nil {
+		t.Fatalf("RestartTeam failed: %v", err)
+	}
+	ask("Any update?")
+	prov.mu.Lock()
+	n := prov.seen["Any update?"]
+	prov.mu.Unlock()
+	if n != 4 {
+		t.Errorf("Expected the lead's context reloaded after the restart, got %d messages", n)
+	}
+
+	if err := mgr.RestartTeam("nobody"); !errors.Is(err, ErrTeamNotFound) {
+		t.Errorf("Expected ErrTeamNotFound, got %v", err)
+	}
+}
+
+// blockingProvider holds every chat until its context is cancelled
+type blockingProvider struct {
+	stubProvider
+	started   chan struct{}
+	cancelled chan struct{}
+	once      sync.Once
+}
+
+func (p *blockingProvider) Chat(ctx context.Context, req *provider.ChatRequest) (*provider.ChatResponse, error) {
+	select {
+	case p.started <- struct{}{}:
+	default:
+	}
+	<-ctx.Done()
+	p.once.Do(func() { close(p.cancelled) })
+	return nil, ctx.Err()
+}
+
+func TestManager_RestartTeamCancelsInFlight(t *testing.T) {
+	tmpDir := t.TempDir()
+	specPath := filepath.Join(tmpDir, "restart-test.yaml")
+	os.WriteFile(specPath, []byte(`
+apiVersion: ugudu/v1
+kind: Team
+metadata:
+  name: restart-test
+
+client_facing:
+  - lead
+
+roles:
+  lead:
+    title: Team Lead
+    visibility: client
+    model:
+      provider: stub
+      model: stub-model
+`), 0644)
+
+	mgr, err := New(Config{DataDir: tmpDir, SocketPath: filepath.Join(tmpDir, "test.sock"), LogLevel: "error"}, logger.New("error"))
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	defer mgr.Stop()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	mgr.Start(ctx)
+	prov := &blockingProvider{stubProvider: stubProvider{id: "stub"}, started: make(chan struct{}, 1), cancelled: make(chan struct{})}
+	mgr.Providers().Register(prov)
+
+	if _, err := mgr.CreateTeam(specPath); err != nil {
+		t.Fatalf("CreateTeam failed: %v", err)
+	}
+	if err := mgr.StartTeam("restart-test"); err != nil {
+		t.Fatalf("StartTeam failed: %v", err)
+	}
+	if _, err := mgr.AskMember("restart-test", "lead", "Plan the release"); err != nil {
+		t.Fatalf("AskMember failed: %v", err)
+	}
+	select {
+	case <-prov.started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for the model call")
+	}
+
+	if err := mgr.RestartTeam("restart-test"); err != nil {
+		t.Fatalf("RestartTeam failed: %v", err)
+	}
+	select {
+	case <-prov.cancelled:
+	default:
+		t.Error("Expected the model call in flight to be cancelled by the restart")
+	}
+
+	tm, _ := mgr.GetTeam("restart-test")
+	if state := tm.State(); state != team.StateRunning {
+		t.Errorf("Expected the team running again, got %s", state)
+	}
+	saved, err := mgr.store.GetTeam("restart-test")
+	if err != nil || saved.Status != "running" {
+		t.Errorf("Expected the team stored as running, got %+v, %v", saved, err)
+	}
+}
+
+// personaProvider replies according to the persona in the system prompt
+type personaProvider struct {
+	stubProvider
+	replies map[string]string
+}
+
+func (p *personaProvider) Chat(ctx context.Context, req *provider.ChatRequest) (*provider.ChatResponse, error) {
+	for persona, reply := range p.replies {
+		if strings.Contains(req.Messages[0].Content, persona) {
+			return &provider.ChatResponse{Content: reply}, nil
+		}
+	}
+	return p.stubProvider.Chat(ctx, req)
+}
+
+func TestManager_HandoffSurvivesRestart(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	specContent := `
+metadata:
+  name: support
+client_facing: [triage]
+roles:
+  triage:
+    title: Triage
+    visibility: client
+    persona: You triage support requests.
+    can_handoff: [security]
+    model:
+      provider: stub
+  security:
+    title: Security
+    persona: You handle security incidents.
+    model:
+      provider: stub
+`
+	specPath := filepath.Join(tmpDir, "support.yaml")
+	os.WriteFile(specPath, []byte(specContent), 0644)
+
+	cfg := Config{DataDir: tmpDir, SocketPath: filepath.Join(tmpDir, "test.sock"), LogLevel: "error"}
+
+	// start runs a daemon session; the provider has to be there before
+	// saved teams are restored
+	start := func() *Manager {
+		t.Helper()
+		mgr, err := New(cfg, logger.New("error"))
+		if err != nil {
+			t.Fatalf("Failed to create manager: %v", err)
+		}
+		mgr.Providers().Register(&personaProvider{stubProvider: stubProvider{id: "stub", reply: "Security here."}, replies: map[string]string{
+			"You triage": "HANDOFF TO security: The client leaked a key.",
+		}})
+		mgr.Start(context.Background())
+		return mgr
+	}
+
+	mgr := start()
+	tm, err := mgr.CreateTeam(specPath)
+	if err != nil {
+		t.Fatalf("CreateTeam failed: %v", err)
+	}
+	if err := mgr.StartTeam("support"); err != nil {
+		t.Fatalf("StartTeam failed: %v", err)
+	}
+	for range tm.Ask("I pushed our key to GitHub") {
+	}
+	security := tm.GetMemberByRole("security").ID
+	if id := tm.ClientMemberID(); id != security {
+		t.Fatalf("Expected the conversation handed to %s, got %q", security, id)
+	}
+	mgr.Stop()
+
+	mgr = start()
+	defer mgr.Stop()
+	if err := mgr.StartTeam("support"); err != nil {
+		t.Fatalf("StartTeam failed: %v", err)
+	}
+	restored, _ := mgr.GetTeam("support")
+	if id := restored.ClientMemberID(); id != security {
+		t.Errorf("Expected the conversation still with %s after the restart, got %q", security, id)
+	}
+}
+
+func TestManager_TokenModeSurvivesRestart(t *testing.T) {
+	tmpDir := t.TempDir()
+	log := logger.New("error")
+
+	specContent := `
+metadata:
+  name: thrifty
+roles:
+  lead:
+    title: Team Lead
+    visibility: client
+    model:
+      provider: stub
+      model: stub-model
+`
+	specPath := filepath.Join(tmpDir, "thrifty.yaml")
+	os.WriteFile(specPath, []byte(specContent), 0644)
+	cfg := Config{DataDir: tmpDir, SocketPath: filepath.Join(tmpDir, "test.sock"), LogLevel: "error"}
+
+	// start runs a manager session; the provider has to be there before
+	// saved teams are restored
+	start := func() *Manager {
+		t.Helper()
+		mgr, err := New(cfg, log)
+		if err != nil {
+			t.Fatalf("Failed to create manager: %v", err)
+		}
+		mgr.Providers().Register(&stubProvider{id: "stub", reply: "ok"})
+		mgr.Start(context.Background())
+		return mgr
+	}
+
+	mgr := start()
+	if _, err := mgr.CreateTeam(specPath); err != nil {
+		t.Fatalf("CreateTeam failed: %v", err)
+	}
+	if err := mgr.SetTokenMode("thrifty", team.TokenModeLow, true); err != nil {
+		t.Fatalf("SetTokenMode failed: %v", err)
+	}
+	// A change that isn't persisted only lasts until the restart
+	if err := mgr.SetTokenMode("thrifty", team.TokenModeMinimal, false); err != nil {
+		t.Fatalf("SetTokenMode failed: %v", err)
+	}
+	mgr.Stop()
+
+	mgr = start()
+	defer mgr.Stop()
+
+	restored, err := mgr.GetTeam("thrifty")
+	if err != nil {
+		t.Fatalf("Expected the team to be restored: %v", err)
+	}
+	if mode := restored.GetTokenMode(); mode != team.TokenModeLow {
+		t.Errorf("Expected the persisted low mode after restart, got %s", mode)
+	}
+	if status := restored.Status(); status["token_mode"] != team.TokenModeLow {
+		t.Errorf("Expected status to report low mode, got %v", status["token_mode"])
+	}
+
+	if err := mgr.SetTokenMode("missing", team.TokenModeLow, true); !errors.Is(err, ErrTeamNotFound) {
+		t.Errorf("Expected ErrTeamNotFound, got %v", err)
+	}
+}
+
+func TestManager_IdleAutoStop(t *testing.T) {
+	tmpDir := t.TempDir()
+	log := logger.New("error")
+
+	spec := func(name, settings string) string {
+		content := `
+apiVersion: ugudu/v1
+kind: Team
+metadata:
+  name: ` + name + `
+
+client_facing:
+  - lead
+
+roles:
+  lead:
+    title: Team Lead
+    visibility: client
+    model:
+      provider: stub
+      model: stub-model
+` + settings
+		path := filepath.Join(tmpDir, name+".yaml")
+		os.WriteFile(path, []byte(content), 0644)
+		return path
+	}
+
+	mgr, err := New(Config{DataDir: tmpDir, LogLevel: "error", IdleStop: 10 * time.Minute}, log)
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	defer mgr.Stop()
+
+	var clockMu sync.Mutex
+	clock := time.Now()
+	mgr.now = func() time.Time {
+		clockMu.Lock()
+		defer clockMu.Unlock()
+		return clock
+	}
+	advance := func(d time.Duration) {
+		clockMu.Lock()
+		clock = clock.Add(d)
+		clockMu.Unlock()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	mgr.Start(ctx)
+	prov := &historyProvider{stubProvider: stubProvider{id: "stub", reply: "On it."}, seen: make(map[string]int)}
+	mgr.Providers().Register(prov)
+
+	idle, err := mgr.CreateTeam(spec("idle-test", ""))
+	if err != nil {
+		t.Fatalf("CreateTeam failed: %v", err)
+	}
+	// A team can opt out of the daemon's idle window
+	kept, err := mgr.CreateTeam(spec("keep-test", "settings:\n  idle_stop: -1s\n"))
+	if err != nil {
+		t.Fatalf("CreateTeam failed: %v", err)
+	}
+	for _, name := range []string{"idle-test", "keep-test"} {
+		if err := mgr.StartTeam(name); err != nil {
+			t.Fatalf("StartTeam failed: %v", err)
+		}
+	}
+
+	ask := func(content string) {
+		t.Helper()
+		responses, err := mgr.Ask("idle-test", content)
+		if err != nil {
+			t.Fatalf("Ask failed: %v", err)
+		}
+		select {
+		case <-responses:
+		case <-time.After(5 * time.Second):
+			t.Fatal("Timed out waiting for a response")
+		}
+		for deadline := time.Now().Add(5 * time.Second); idle.Busy(); time.Sleep(5 * time.Millisecond) {
+			if time.Now().After(deadline) {
+				t.Fatal("Timed out waiting for the team to go idle")
+			}
+		}
+	}
+	ask("Plan the release")
+
+	advance(9 * time.Minute)
+	mgr.stopIdleTeams()
+	if state := idle.State(); state != team.StateRunning {
+		t.Fatalf("Expected the team running inside its idle window, got %s", state)
+	}
+
+	advance(2 * time.Minute)
+	mgr.stopIdleTeams()
+	if state := idle.State(); state != team.StateAutoStopped {
+		t.Fatalf("Expected the team auto-stopped after its idle window, got %s", state)
+	}
+	if saved, _ := mgr.Store().GetTeam("idle-test"); saved == nil || saved.Status != team.StateAutoStopped {
+		t.Errorf("Expected the auto-stop persisted, got %+v", saved)
+	}
+	if state := kept.State(); state != team.StateRunning {
+		t.Errorf("Expected the opted-out team still running, got %s", state)
+	}
+
+	// The next ask starts it again, with the conversation so far
+	ask("Any update?")
+	if state := idle.State(); state != team.StateRunning {
+		t.Errorf("Expected the ask to restart the team, got %s", state)
+	}
+	prov.mu.Lock()
+	defer prov.mu.Unlock()
+	if n := prov.seen["Any update?"]; n != 4 {
+		t.Errorf("Expected the restarted lead's request to include its history, got %d messages", n)
+	}
+}
+
+// slowProvider takes delay to answer, reporting token usage
+type slowProvider struct {
+	stubProvider
+	delay time.Duration
+}
+
+func (p *slowProvider) Chat(ctx context.Context, req *provider.ChatRequest) (*provider.ChatResponse, error) {
+	time.Sleep(p.delay)
+	resp, err := p.stubProvider.Chat(ctx, req)
+	resp.Usage = provider.Usage{PromptTokens: 120, CompletionTokens: 30}
+	return resp, err
+}
+
+func TestManager_TeamPerformance(t *testing.T) {
+	tmpDir := t.TempDir()
+	log := logger.New("error")
+
+	specContent := `
+apiVersion: ugudu/v1
+kind: Team
+metadata:
+  name: perf-test
+
+client_facing:
+  - lead
+
+roles:
+  lead:
+    title: Team Lead
+    visibility: client
+    model:
+      provider: stub
+      model: stub-model
+`
+	specPath := filepath.Join(tmpDir, "perf-test.yaml")
+	os.WriteFile(specPath, []byte(specContent), 0644)
+
+	mgr, err := New(Config{DataDir: tmpDir, SocketPath: filepath.Join(tmpDir, "test.sock"), LogLevel: "error"}, log)
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	defer mgr.Stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	mgr.Start(ctx)
+	mgr.Providers().Register(&slowProvider{stubProvider: stubProvider{id: "stub", reply: "On it."}, delay: 20 * time.Millisecond})
+
+	if _, err := mgr.CreateTeam(specPath); err != nil {
+		t.Fatalf("CreateTeam failed: %v", err)
+	}
+	if err := mgr.StartTeam("perf-test"); err != nil {
+		t.Fatalf("StartTeam failed: %v", err)
+	}
+	responses, err := mgr.AskMember("perf-test", "lead", "Plan the release")
+	if err != nil {
+		t.Fatalf("AskMember failed: %v", err)
+	}
+	select {
+	case <-responses:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for the lead")
+	}
+
+	perf, err := mgr.TeamPerformance("perf-test", time.Time{})
+	if err != nil {
+		t.Fatalf("TeamPerformance failed: %v", err)
+	}
+	if perf.Total.Calls != 1 || perf.Total.Outcomes["ok"] != 1 {
+		t.Fatalf("Expected one successful call, got %+v", perf.Total)
+	}
+	if ms := perf.Total.TotalMs; ms < 20 || ms > 5000 {
+		t.Errorf("Expected a latency of at least the provider's 20ms, got %dms", ms)
+	}
+	if perf.Total.PromptTokens != 120 || perf.Total.CompletionTokens != 30 {
+		t.Errorf("Expected the call's token usage recorded, got %d/%d", perf.Total.PromptTokens, perf.Total.CompletionTokens)
+	}
+	if len(perf.Members) != 1 || perf.Members[0].MemberID != "lead" {
+		t.Errorf("Expected the call attributed to the lead, got %+v", perf.Members)
+	}
+	if len(perf.Models) != 1 || perf.Models[0].Model != "stub-model" {
+		t.Errorf("Expected the call attributed to stub-model, got %+v", perf.Models)
+	}
+
+	if perf, err := mgr.TeamPerformance("perf-test", time.Now().Add(time.Hour)); err != nil || perf.Total.Calls != 0 {
+		t.Errorf("Expected no calls since an hour from now, got %+v, %v", perf, err)
+	}
+	if _, err := mgr.TeamPerformance("nobody", time.Time{}); !errors.Is(err, ErrTeamNotFound) {
+		t.Errorf("Expected ErrTeamNotFound, got %v", err)
+	}
+}
+
+// benchProvider has the lead delegate to the engineer and report back,
+// each call taking a little time and using some tokens
+type benchProvider struct {
+	stubProvider
+}
+
+func (p *benchProvider) Chat(ctx context.Context, req *provider.ChatRequest) (*provider.ChatResponse, error) {
+	time.Sleep(10 * time.Millisecond)
+	reply := "Drafted the plan."
+	if strings.Contains(req.Messages[0].Content, "Team Lead") {
+		reply = "DELEGATE TO engineer: draft the plan"
+		for _, msg := range req.Messages[1:] {
+			if strings.Contains(msg.Content, "Drafted the plan.") {
+				reply = "COMPLETE: Here is the plan."
+			}
+		}
+	}
+	return &provider.ChatResponse{Content: reply, Model: req.Model, Usage: provider.Usage{PromptTokens: 100, CompletionTokens: 20}}, nil
+}
+
+func TestManager_BenchTeam(t *testing.T) {
+	tmpDir := t.TempDir()
+	log := logger.New("error")
+
+	specContent := `
+apiVersion: ugudu/v1
+kind: Team
+metadata:
+  name: bench-test
+
+client_facing:
+  - lead
+
+roles:
+  lead:
+    title: Team Lead
+    visibility: client
+    can_delegate: [engineer]
+    model:
+      provider: stub
+      model: stub-model
+  engineer:
+    title: Engineer
+    model:
+      provider: stub
+      model: stub-model
+`
+	specPath := filepath.Join(tmpDir, "bench-test.yaml")
+	os.WriteFile(specPath, []byte(specContent), 0644)
+
+	mgr, err := New(Config{DataDir: tmpDir, SocketPath: filepath.Join(tmpDir, "test.sock"), LogLevel: "error"}, log)
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	defer mgr.Stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	mgr.Start(ctx)
+	mgr.Providers().Register(&benchProvider{stubProvider{id: "stub"}})
+
+	if _, err := mgr.CreateTeam(specPath); err != nil {
+		t.Fatalf("CreateTeam failed: %v", err)
+	}
+	if err := mgr.StartTeam("bench-test"); err != nil {
+		t.Fatalf("StartTeam failed: %v", err)
+	}
+
+	report, err := mgr.BenchTeam(ctx, "bench-test", BenchOptions{Iterations: 3, Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("BenchTeam failed: %v", err)
+	}
+
+	if report.Prompt != DefaultBenchPrompt || report.Iterations != 3 || len(report.Runs) != 3 {
+		t.Fatalf("Expected 3 runs of the default prompt, got %+v", report)
+	}
+	for i, run := range report.Runs {
+		// Lead delegates, engineer drafts, lead reports back
+		if run.Error != "" || run.Calls != 3 || run.PromptTokens != 300 || run.CompletionTokens != 60 || run.Failed != 0 {
+			t.Errorf("Run %d: expected 3 successful calls and their tokens, got %+v", i, run)
+		}
+		if run.LatencyMs < 30 {
+			t.Errorf("Run %d: expected a latency of at least the calls' 30ms, got %dms", i, run.LatencyMs)
+		}
+	}
+	l := report.Latency
+	if l.MinMs < 30 || l.MinMs > l.P50Ms || l.P50Ms > l.P90Ms || l.P90Ms > l.P99Ms || l.P99Ms != l.MaxMs || l.AvgMs < l.MinMs {
+		t.Errorf("Expected ordered latency percentiles, got %+v", l)
+	}
+	if report.Total.Calls != 9 || report.Total.PromptTokens != 900 || report.Total.CompletionTokens != 180 {
+		t.Errorf("Expected the 9 calls' totals, got %+v", report.Total)
+	}
+	calls := make(map[string]int)
+	for _, m := range report.Members {
+		calls[m.MemberID] = m.Calls
+	}
+	if len(calls) != 2 || calls["lead"] != 6 || calls["engineer"] != 3 {
+		t.Errorf("Expected 6 calls by the lead and 3 by the engineer, got %v", calls)
+	}
+	if len(report.Models) != 1 || report.Models[0].Model != "stub-model" {
+		t.Errorf("Expected the calls attributed to stub-model, got %+v", report.Models)
+	}
+
+	if _, err := mgr.BenchTeam(ctx, "bench-test", BenchOptions{Iterations: MaxBenchIterations + 1}); err == nil {
+		t.Error("Expected an error for too many iterations")
+	}
+	if _, err := mgr.BenchTeam(ctx, "nobody", BenchOptions{}); !errors.Is(err, ErrTeamNotFound) {
+		t.Errorf("Expected ErrTeamNotFound, got %v", err)
+	}
+}
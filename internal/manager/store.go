@@ -1,10 +1,15 @@
 package manager
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
+	"github.com/arcslash/ugudu/internal/team"
 	_ "github.com/mattn/go-sqlite3"
 )
 
@@ -32,6 +37,12 @@ func NewStore(dbPath string) (*Store, error) {
 	return s, nil
 }
 
+// Ping verifies the database is reachable and answering queries
+func (s *Store) Ping(ctx context.Context) error {
+	var one int
+	return s.db.QueryRowContext(ctx, `SELECT 1`).Scan(&one)
+}
+
 // Close closes the database connection
 func (s *Store) Close() error {
 	return s.db.Close()
@@ -54,6 +65,7 @@ func (s *Store) migrate() error {
 			to_member TEXT,
 			content TEXT,
 			task_id TEXT,
+			conversation_id TEXT,
 			timestamp DATETIME DEFAULT CURRENT_TIMESTAMP,
 			FOREIGN KEY (team_name) REFERENCES teams(name)
 		)`,
@@ -93,12 +105,68 @@ func (s *Store) migrate() error {
 			FOREIGN KEY (team_name) REFERENCES teams(name),
 			FOREIGN KEY (conversation_id) REFERENCES conversations(id)
 		)`,
+		// Spec history - every saved version of a spec file
+		`CREATE TABLE IF NOT EXISTS spec_versions (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			spec_name TEXT NOT NULL,
+			version INTEGER NOT NULL,
+			content TEXT NOT NULL,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE (spec_name, version)
+		)`,
+		// Tool calls held for a human's approval, and the decisions
+		`CREATE TABLE IF NOT EXISTS approvals (
+			id TEXT PRIMARY KEY,
+			team_name TEXT NOT NULL,
+			member_id TEXT NOT NULL,
+			tool TEXT NOT NULL,
+			args TEXT,
+			task_id TEXT,
+			status TEXT NOT NULL,
+			reason TEXT,
+			created_at DATETIME NOT NULL,
+			decided_at DATETIME,
+			FOREIGN KEY (team_name) REFERENCES teams(name)
+		)`,
+		// Audit log - append-only, so it has no foreign keys and nothing
+		// updates or deletes its rows, including deleting the team
+		`CREATE TABLE IF NOT EXISTS audit_log (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			timestamp DATETIME NOT NULL,
+			action TEXT NOT NULL,
+			team_name TEXT,
+			actor TEXT,
+			request_id TEXT,
+			details TEXT
+		)`,
+		`CREATE TRIGGER IF NOT EXISTS audit_log_no_update BEFORE UPDATE ON audit_log
+			BEGIN SELECT RAISE(ABORT, 'audit log is append-only'); END`,
+		`CREATE TRIGGER IF NOT EXISTS audit_log_no_delete BEFORE DELETE ON audit_log
+			BEGIN SELECT RAISE(ABORT, 'audit log is append-only'); END`,
+		// Timing and outcome of members' model calls
+		`CREATE TABLE IF NOT EXISTS provider_calls (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			team_name TEXT NOT NULL,
+			member_id TEXT NOT NULL,
+			provider TEXT NOT NULL,
+			model TEXT,
+			started_at DATETIME NOT NULL,
+			duration_ms INTEGER NOT NULL,
+			prompt_tokens INTEGER NOT NULL DEFAULT 0,
+			completion_tokens INTEGER NOT NULL DEFAULT 0,
+			outcome TEXT NOT NULL,
+			request_id TEXT
+		)`,
 		`CREATE INDEX IF NOT EXISTS idx_tasks_team ON tasks(team_name)`,
 		`CREATE INDEX IF NOT EXISTS idx_tasks_status ON tasks(status)`,
 		`CREATE INDEX IF NOT EXISTS idx_messages_team ON team_messages(team_name)`,
 		`CREATE INDEX IF NOT EXISTS idx_conversations_team ON conversations(team_name)`,
 		`CREATE INDEX IF NOT EXISTS idx_agent_context_member ON agent_context(team_name, member_id)`,
 		`CREATE INDEX IF NOT EXISTS idx_agent_context_conv ON agent_context(conversation_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_approvals_team ON approvals(team_name)`,
+		`CREATE INDEX IF NOT EXISTS idx_audit_team ON audit_log(team_name, timestamp)`,
+		`CREATE INDEX IF NOT EXISTS idx_audit_request ON audit_log(request_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_provider_calls_team ON provider_calls(team_name, started_at)`,
 	}
 
 	for _, m := range migrations {
@@ -107,6 +175,59 @@ func (s *Store) migrate() error {
 		}
 	}
 
+	// Columns added after the initial schema
+	if err := s.addColumn("team_messages", "conversation_id", "TEXT"); err != nil {
+		return err
+	}
+	if err := s.addColumn("teams", "token_mode", "TEXT"); err != nil {
+		return err
+	}
+	if err := s.addColumn("teams", "seed", "TEXT"); err != nil {
+		return err
+	}
+	if err := s.addColumn("conversations", "title", "TEXT"); err != nil {
+		return err
+	}
+	if err := s.addColumn("conversations", "client_member", "TEXT"); err != nil {
+		return err
+	}
+	if err := s.backfillConversationTitles(); err != nil {
+		return err
+	}
+	if _, err := s.db.Exec(`CREATE INDEX IF NOT EXISTS idx_messages_conv ON team_messages(conversation_id)`); err != nil {
+		return fmt.Errorf("execute migration: %w", err)
+	}
+
+	return nil
+}
+
+// addColumn adds a column to an existing table if it isn't there yet
+func (s *Store) addColumn(table, column, colType string) error {
+	rows, err := s.db.Query(fmt.Sprintf(`PRAGMA table_info(%s)`, table))
+	if err != nil {
+		return fmt.Errorf("inspect %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var cid, notNull, pk int
+		var name, ctype string
+		var dflt sql.NullString
+		if err := rows.Scan(&cid, &name, &ctype, &notNull, &dflt, &pk); err != nil {
+			return fmt.Errorf("inspect %s: %w", table, err)
+		}
+		if name == column {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("inspect %s: %w", table, err)
+	}
+	rows.Close()
+
+	if _, err := s.db.Exec(fmt.Sprintf(`ALTER TABLE %s ADD COLUMN %s %s`, table, column, colType)); err != nil {
+		return fmt.Errorf("add column %s.%s: %w", table, column, err)
+	}
 	return nil
 }
 
@@ -131,6 +252,25 @@ func (s *Store) UpdateTeamStatus(name, status string) error {
 	return err
 }
 
+// SetTeamTokenMode saves the token mode a team uses after a restart. An
+// empty mode clears it.
+func (s *Store) SetTeamTokenMode(name, mode string) error {
+	_, err := s.db.Exec(`
+		UPDATE teams SET token_mode = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE name = ?
+	`, mode, name)
+	return err
+}
+
+// SetTeamSeed saves the background a team's members start with
+func (s *Store) SetTeamSeed(name, seed string) error {
+	_, err := s.db.Exec(`
+		UPDATE teams SET seed = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE name = ?
+	`, seed, name)
+	return err
+}
+
 // DeleteTeam removes a team from the store
 func (s *Store) DeleteTeam(name string) error {
 	tx, err := s.db.Begin()
@@ -146,6 +286,9 @@ func (s *Store) DeleteTeam(name string) error {
 	if _, err := tx.Exec(`DELETE FROM team_messages WHERE team_name = ?`, name); err != nil {
 		return err
 	}
+	if _, err := tx.Exec(`DELETE FROM provider_calls WHERE team_name = ?`, name); err != nil {
+		return err
+	}
 	if _, err := tx.Exec(`DELETE FROM teams WHERE name = ?`, name); err != nil {
 		return err
 	}
@@ -157,8 +300,8 @@ func (s *Store) DeleteTeam(name string) error {
 func (s *Store) GetTeam(name string) (*SavedTeam, error) {
 	var team SavedTeam
 	err := s.db.QueryRow(`
-		SELECT name, spec_path, status FROM teams WHERE name = ?
-	`, name).Scan(&team.Name, &team.SpecPath, &team.Status)
+		SELECT name, spec_path, status, COALESCE(token_mode, ''), COALESCE(seed, '') FROM teams WHERE name = ?
+	`, name).Scan(&team.Name, &team.SpecPath, &team.Status, &team.TokenMode, &team.Seed)
 
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -173,7 +316,7 @@ func (s *Store) GetTeam(name string) (*SavedTeam, error) {
 // ListTeams returns all saved teams
 func (s *Store) ListTeams() ([]SavedTeam, error) {
 	rows, err := s.db.Query(`
-		SELECT name, spec_path, status FROM teams ORDER BY name
+		SELECT name, spec_path, status, COALESCE(token_mode, ''), COALESCE(seed, '') FROM teams ORDER BY name
 	`)
 	if err != nil {
 		return nil, err
@@ -183,7 +326,7 @@ func (s *Store) ListTeams() ([]SavedTeam, error) {
 	var teams []SavedTeam
 	for rows.Next() {
 		var team SavedTeam
-		if err := rows.Scan(&team.Name, &team.SpecPath, &team.Status); err != nil {
+		if err := rows.Scan(&team.Name, &team.SpecPath, &team.Status, &team.TokenMode, &team.Seed); err != nil {
 			return nil, err
 		}
 		teams = append(teams, team)
@@ -195,8 +338,8 @@ func (s *Store) ListTeams() ([]SavedTeam, error) {
 // SaveMessage persists a team message
 func (s *Store) SaveMessage(teamName string, msg map[string]interface{}) error {
 	_, err := s.db.Exec(`
-		INSERT INTO team_messages (id, team_name, type, from_member, to_member, content, task_id)
-		VALUES (?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO team_messages (id, team_name, type, from_member, to_member, content, task_id, conversation_id)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
 	`,
 		msg["id"],
 		teamName,
@@ -205,6 +348,7 @@ func (s *Store) SaveMessage(teamName string, msg map[string]interface{}) error {
 		msg["to"],
 		msg["content"],
 		msg["task_id"],
+		msg["conversation_id"],
 	)
 	return err
 }
@@ -346,6 +490,7 @@ type Conversation struct {
 	StartedAt     time.Time `json:"started_at"`
 	LastMessageAt time.Time `json:"last_message_at"`
 	Status        string    `json:"status"`
+	Title         string    `json:"title"`
 }
 
 // AgentMessage represents a message in an agent's LLM context
@@ -381,12 +526,31 @@ func (s *Store) CreateConversation(teamName string) (*Conversation, error) {
 func (s *Store) GetActiveConversation(teamName string) (*Conversation, error) {
 	var conv Conversation
 	err := s.db.QueryRow(`
-		SELECT id, team_name, started_at, last_message_at, status
+		SELECT id, team_name, started_at, last_message_at, status, COALESCE(title, '')
 		FROM conversations
 		WHERE team_name = ? AND status = 'active'
 		ORDER BY last_message_at DESC
 		LIMIT 1
-	`, teamName).Scan(&conv.ID, &conv.TeamName, &conv.StartedAt, &conv.LastMessageAt, &conv.Status)
+	`, teamName).Scan(&conv.ID, &conv.TeamName, &conv.StartedAt, &conv.LastMessageAt, &conv.Status, &conv.Title)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &conv, nil
+}
+
+// GetConversation returns a conversation by ID, or nil if there's none
+func (s *Store) GetConversation(conversationID string) (*Conversation, error) {
+	var conv Conversation
+	err := s.db.QueryRow(`
+		SELECT id, team_name, started_at, last_message_at, status, COALESCE(title, '')
+		FROM conversations
+		WHERE id = ?
+	`, conversationID).Scan(&conv.ID, &conv.TeamName, &conv.StartedAt, &conv.LastMessageAt, &conv.Status, &conv.Title)
 
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -406,6 +570,103 @@ func (s *Store) UpdateConversationTimestamp(conversationID string) error {
 	return err
 }
 
+// TitleConversation sets a conversation's title unless it already has one.
+// It reports whether the title was set.
+func (s *Store) TitleConversation(conversationID, title string) (bool, error) {
+	res, err := s.db.Exec(`
+		UPDATE conversations SET title = ? WHERE id = ? AND COALESCE(title, '') = ''
+	`, title, conversationID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// ReplaceConversationTitle changes a conversation's title if it's still
+// old, so a rename in the meantime wins. It reports whether it changed.
+func (s *Store) ReplaceConversationTitle(conversationID, old, title string) (bool, error) {
+	res, err := s.db.Exec(`
+		UPDATE conversations SET title = ? WHERE id = ? AND COALESCE(title, '') = ?
+	`, title, conversationID, old)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// SetConversationClientMember records the member a conversation was handed
+// off to, so it keeps taking the client's requests after a restart
+func (s *Store) SetConversationClientMember(conversationID, memberID string) error {
+	_, err := s.db.Exec(`UPDATE conversations SET client_member = ? WHERE id = ?`, memberID, conversationID)
+	return err
+}
+
+// ConversationClientMember returns the member a conversation was last handed
+// off to, or "" if it hasn't been
+func (s *Store) ConversationClientMember(conversationID string) (string, error) {
+	var memberID sql.NullString
+	err := s.db.QueryRow(`SELECT client_member FROM conversations WHERE id = ?`, conversationID).Scan(&memberID)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return memberID.String, err
+}
+
+// RenameConversation sets a conversation's title. It reports whether the
+// conversation exists.
+func (s *Store) RenameConversation(conversationID, title string) (bool, error) {
+	res, err := s.db.Exec(`UPDATE conversations SET title = ? WHERE id = ?`, title, conversationID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// backfillConversationTitles titles conversations from before titles
+// existed after their first client message
+func (s *Store) backfillConversationTitles() error {
+	rows, err := s.db.Query(`
+		SELECT c.id, (
+			SELECT content FROM team_messages
+			WHERE conversation_id = c.id AND type = 'client_request'
+			ORDER BY timestamp ASC, rowid ASC
+			LIMIT 1
+		)
+		FROM conversations c
+		WHERE COALESCE(c.title, '') = ''
+	`)
+	if err != nil {
+		return fmt.Errorf("backfill conversation titles: %w", err)
+	}
+	defer rows.Close()
+
+	titles := make(map[string]string)
+	for rows.Next() {
+		var id string
+		var first sql.NullString
+		if err := rows.Scan(&id, &first); err != nil {
+			return fmt.Errorf("backfill conversation titles: %w", err)
+		}
+		if title := conversationTitle(first.String); title != "" {
+			titles[id] = title
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("backfill conversation titles: %w", err)
+	}
+	rows.Close()
+
+	for id, title := range titles {
+		if _, err := s.TitleConversation(id, title); err != nil {
+			return fmt.Errorf("backfill conversation titles: %w", err)
+		}
+	}
+	return nil
+}
+
 // CloseConversation marks a conversation as closed
 func (s *Store) CloseConversation(conversationID string) error {
 	_, err := s.db.Exec(`
@@ -501,10 +762,54 @@ func (s *Store) GetConversationHistory(conversationID string) ([]map[string]inte
 	return messages, rows.Err()
 }
 
+// GetClientMessages returns the client-visible transcript of a conversation:
+// requests from the client and the responses sent back to it. Messages use
+// the same shape as GetConversationHistory.
+func (s *Store) GetClientMessages(conversationID string) ([]map[string]interface{}, error) {
+	rows, err := s.db.Query(`
+		SELECT type, from_member, to_member, content, timestamp
+		FROM team_messages
+		WHERE conversation_id = ? AND (from_member = 'client' OR to_member = 'client')
+		ORDER BY timestamp ASC, rowid ASC
+	`, conversationID)
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages []map[string]interface{}
+	for rows.Next() {
+		var msgType string
+		var fromMember, toMember, content sql.NullString
+		var timestamp time.Time
+
+		if err := rows.Scan(&msgType, &fromMember, &toMember, &content, &timestamp); err != nil {
+			return nil, err
+		}
+
+		// Client requests read as user turns addressed to a member
+		role, memberID := "assistant", fromMember.String
+		if fromMember.String == "client" {
+			role, memberID = "user", toMember.String
+		}
+
+		messages = append(messages, map[string]interface{}{
+			"member_id":  memberID,
+			"role":       role,
+			"type":       msgType,
+			"content":    content.String,
+			"created_at": timestamp,
+		})
+	}
+
+	return messages, rows.Err()
+}
+
 // ListConversations returns recent conversations for a team
 func (s *Store) ListConversations(teamName string, limit int) ([]Conversation, error) {
 	rows, err := s.db.Query(`
-		SELECT id, team_name, started_at, last_message_at, status
+		SELECT id, team_name, started_at, last_message_at, status, COALESCE(title, '')
 		FROM conversations
 		WHERE team_name = ?
 		ORDER BY last_message_at DESC
@@ -519,7 +824,7 @@ func (s *Store) ListConversations(teamName string, limit int) ([]Conversation, e
 	var conversations []Conversation
 	for rows.Next() {
 		var conv Conversation
-		if err := rows.Scan(&conv.ID, &conv.TeamName, &conv.StartedAt, &conv.LastMessageAt, &conv.Status); err != nil {
+		if err := rows.Scan(&conv.ID, &conv.TeamName, &conv.StartedAt, &conv.LastMessageAt, &conv.Status, &conv.Title); err != nil {
 			return nil, err
 		}
 		conversations = append(conversations, conv)
@@ -527,3 +832,430 @@ func (s *Store) ListConversations(teamName string, limit int) ([]Conversation, e
 
 	return conversations, rows.Err()
 }
+
+// SpecVersion is a saved revision of a spec file
+type SpecVersion struct {
+	Name      string    `json:"name"`
+	Version   int       `json:"version"`
+	Content   string    `json:"content,omitempty"`
+	Size      int       `json:"size"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// SaveSpecVersion records content as the next version of a spec and prunes
+// all but the newest keep versions (keep <= 0 keeps everything). Content
+// identical to the latest version is not recorded again; the latest version
+// number is returned either way.
+func (s *Store) SaveSpecVersion(name, content string, keep int) (int, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var latest int
+	var latestContent sql.NullString
+	err = tx.QueryRow(`
+		SELECT version, content FROM spec_versions
+		WHERE spec_name = ?
+		ORDER BY version DESC
+		LIMIT 1
+	`, name).Scan(&latest, &latestContent)
+	if err != nil && err != sql.ErrNoRows {
+		return 0, err
+	}
+	if latestContent.Valid && latestContent.String == content {
+		return latest, nil
+	}
+
+	version := latest + 1
+	if _, err := tx.Exec(`
+		INSERT INTO spec_versions (spec_name, version, content, created_at)
+		VALUES (?, ?, ?, ?)
+	`, name, version, content, time.Now()); err != nil {
+		return 0, err
+	}
+
+	if keep > 0 {
+		if _, err := tx.Exec(`
+			DELETE FROM spec_versions WHERE spec_name = ? AND version <= ?
+		`, name, version-keep); err != nil {
+			return 0, err
+		}
+	}
+
+	return version, tx.Commit()
+}
+
+// ListSpecVersions returns a spec's saved versions, newest first, without content
+func (s *Store) ListSpecVersions(name string) ([]SpecVersion, error) {
+	rows, err := s.db.Query(`
+		SELECT version, length(CAST(content AS BLOB)), created_at
+		FROM spec_versions
+		WHERE spec_name = ?
+		ORDER BY version DESC
+	`, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var versions []SpecVersion
+	for rows.Next() {
+		v := SpecVersion{Name: name}
+		if err := rows.Scan(&v.Version, &v.Size, &v.CreatedAt); err != nil {
+			return nil, err
+		}
+		versions = append(versions, v)
+	}
+
+	return versions, rows.Err()
+}
+
+// GetSpecVersion returns one saved version of a spec, or nil if it doesn't exist
+func (s *Store) GetSpecVersion(name string, version int) (*SpecVersion, error) {
+	v := SpecVersion{Name: name, Version: version}
+	err := s.db.QueryRow(`
+		SELECT content, created_at
+		FROM spec_versions
+		WHERE spec_name = ? AND version = ?
+	`, name, version).Scan(&v.Content, &v.CreatedAt)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	v.Size = len(v.Content)
+	return &v, nil
+}
+
+// SaveApproval records a tool call held for approval, or its decision
+func (s *Store) SaveApproval(teamName string, a team.Approval) error {
+	args, err := json.Marshal(a.Args)
+	if err != nil {
+		return fmt.Errorf("encode approval args: %w", err)
+	}
+	var decidedAt interface{}
+	if !a.DecidedAt.IsZero() {
+		decidedAt = a.DecidedAt
+	}
+	_, err = s.db.Exec(`
+		INSERT INTO approvals (id, team_name, member_id, tool, args, task_id, status, reason, created_at, decided_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			status = excluded.status,
+			reason = excluded.reason,
+			decided_at = excluded.decided_at
+	`, a.ID, teamName, a.Member, a.Tool, string(args), a.TaskID, a.Status, a.Reason, a.CreatedAt, decidedAt)
+	return err
+}
+
+// ListApprovals returns a team's most recent approvals, newest first
+func (s *Store) ListApprovals(teamName string, limit int) ([]team.Approval, error) {
+	rows, err := s.db.Query(`
+		SELECT id, member_id, tool, args, task_id, status, reason, created_at, decided_at
+		FROM approvals
+		WHERE team_name = ?
+		ORDER BY created_at DESC
+		LIMIT ?
+	`, teamName, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var approvals []team.Approval
+	for rows.Next() {
+		var a team.Approval
+		var args, taskID, reason sql.NullString
+		var decidedAt sql.NullTime
+		if err := rows.Scan(&a.ID, &a.Member, &a.Tool, &args, &taskID, &a.Status, &reason, &a.CreatedAt, &decidedAt); err != nil {
+			return nil, err
+		}
+		if args.Valid {
+			json.Unmarshal([]byte(args.String), &a.Args)
+		}
+		a.TaskID = taskID.String
+		a.Reason = reason.String
+		a.DecidedAt = decidedAt.Time
+		approvals = append(approvals, a)
+	}
+
+	return approvals, rows.Err()
+}
+
+// ============================================================================
+// Audit Log
+// ============================================================================
+
+// AuditEntry is one record in the audit log
+type AuditEntry struct {
+	ID        int64                  `json:"id"`
+	Timestamp time.Time              `json:"timestamp"`
+	Action    string                 `json:"action"` // e.g. "chat.request", "tool.execute", "team.start"
+	Team      string                 `json:"team,omitempty"`
+	Actor     string                 `json:"actor,omitempty"` // "client", a member ID, or "daemon"
+	RequestID string                 `json:"request_id,omitempty"`
+	Details   map[string]interface{} `json:"details,omitempty"`
+}
+
+// AuditFilter selects audit entries. Zero fields match everything; Action
+// also matches its sub-actions, so "team" matches "team.start".
+type AuditFilter struct {
+	Team      string
+	Action    string
+	Actor     string
+	RequestID string
+	Since     time.Time
+	Until     time.Time
+	Limit     int
+}
+
+// AppendAudit adds an entry to the audit log
+func (s *Store) AppendAudit(e AuditEntry) error {
+	var details interface{}
+	if len(e.Details) > 0 {
+		data, err := json.Marshal(e.Details)
+		if err != nil {
+			return fmt.Errorf("encode audit details: %w", err)
+		}
+		details = string(data)
+	}
+	_, err := s.db.Exec(`
+		INSERT INTO audit_log (timestamp, action, team_name, actor, request_id, details)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, e.Timestamp, e.Action, e.Team, e.Actor, e.RequestID, details)
+	return err
+}
+
+// ListAudit returns the audit entries matching f, oldest first. With a
+// limit, it's the newest that many.
+func (s *Store) ListAudit(f AuditFilter) ([]AuditEntry, error) {
+	query := `SELECT id, timestamp, action, team_name, actor, request_id, details FROM audit_log WHERE 1 = 1`
+	var args []interface{}
+	if f.Team != "" {
+		query += ` AND team_name = ?`
+		args = append(args, f.Team)
+	}
+	if f.Action != "" {
+		query += ` AND (action = ? OR action LIKE ?)`
+		args = append(args, f.Action, f.Action+".%")
+	}
+	if f.Actor != "" {
+		query += ` AND actor = ?`
+		args = append(args, f.Actor)
+	}
+	if f.RequestID != "" {
+		query += ` AND request_id = ?`
+		args = append(args, f.RequestID)
+	}
+	if !f.Since.IsZero() {
+		query += ` AND timestamp >= ?`
+		args = append(args, f.Since)
+	}
+	if !f.Until.IsZero() {
+		query += ` AND timestamp < ?`
+		args = append(args, f.Until)
+	}
+	query += ` ORDER BY id DESC`
+	if f.Limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, f.Limit)
+	}
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []AuditEntry
+	for rows.Next() {
+		var e AuditEntry
+		var teamName, actor, requestID, details sql.NullString
+		if err := rows.Scan(&e.ID, &e.Timestamp, &e.Action, &teamName, &actor, &requestID, &details); err != nil {
+			return nil, err
+		}
+		e.Team = teamName.String
+		e.Actor = actor.String
+		e.RequestID = requestID.String
+		if details.Valid {
+			json.Unmarshal([]byte(details.String), &e.Details)
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Newest were selected first so the limit keeps them
+	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+		entries[i], entries[j] = entries[j], entries[i]
+	}
+	return entries, nil
+}
+
+// ============================================================================
+// Provider Calls
+// ============================================================================
+
+// CallStats summarizes a set of model calls
+type CallStats struct {
+	Calls            int            `json:"calls"`
+	TotalMs          int64          `json:"total_ms"`
+	AvgMs            int64          `json:"avg_ms"`
+	MaxMs            int64          `json:"max_ms"`
+	PromptTokens     int            `json:"prompt_tokens"`
+	CompletionTokens int            `json:"completion_tokens"`
+	Outcomes         map[string]int `json:"outcomes"` // Calls by outcome, e.g. "ok" or "rate_limited"
+}
+
+func (c *CallStats) add(durationMs int64, promptTokens, completionTokens int, outcome string) {
+	if c.Outcomes == nil {
+		c.Outcomes = make(map[string]int)
+	}
+	c.Calls++
+	c.TotalMs += durationMs
+	if durationMs > c.MaxMs {
+		c.MaxMs = durationMs
+	}
+	c.AvgMs = c.TotalMs / int64(c.Calls)
+	c.PromptTokens += promptTokens
+	c.CompletionTokens += completionTokens
+	c.Outcomes[outcome]++
+}
+
+// MemberPerformance is one member's share of a team's model calls
+type MemberPerformance struct {
+	MemberID string `json:"member_id"`
+	CallStats
+}
+
+// ModelPerformance is one model's share of a team's model calls
+type ModelPerformance struct {
+	Provider string `json:"provider"`
+	Model    string `json:"model"`
+	CallStats
+}
+
+// TeamPerformance summarizes where a team's time went in model calls.
+// Members and models are ordered by total latency, slowest first.
+type TeamPerformance struct {
+	Team    string              `json:"team"`
+	Since   *time.Time          `json:"since,omitempty"`
+	Total   CallStats           `json:"total"`
+	Members []MemberPerformance `json:"members"`
+	Models  []ModelPerformance  `json:"models"`
+}
+
+// AppendProviderCall records a member's model call
+func (s *Store) AppendProviderCall(teamName string, c team.ProviderCall) error {
+	_, err := s.db.Exec(`
+		INSERT INTO provider_calls (team_name, member_id, provider, model, started_at, duration_ms, prompt_tokens, completion_tokens, outcome, request_id)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, teamName, c.MemberID, c.Provider, c.Model, c.StartedAt, c.Duration.Milliseconds(), c.PromptTokens, c.CompletionTokens, c.Outcome, c.RequestID)
+	return err
+}
+
+// TeamPerformance summarizes the team's model calls, those started since
+// since if it's set
+func (s *Store) TeamPerformance(teamName string, since time.Time) (*TeamPerformance, error) {
+	where := ""
+	var args []interface{}
+	if !since.IsZero() {
+		where = ` AND started_at >= ?`
+		args = append(args, since)
+	}
+	perf, err := s.callPerformance(teamName, where, args)
+	if err != nil {
+		return nil, err
+	}
+	if !since.IsZero() {
+		perf.Since = &since
+	}
+	return perf, nil
+}
+
+// RequestPerformance summarizes the team's model calls made for the given
+// requests
+func (s *Store) RequestPerformance(teamName string, requestIDs []string) (*TeamPerformance, error) {
+	if len(requestIDs) == 0 {
+		return &TeamPerformance{Team: teamName, Total: CallStats{Outcomes: map[string]int{}}, Members: []MemberPerformance{}, Models: []ModelPerformance{}}, nil
+	}
+	args := make([]interface{}, len(requestIDs))
+	for i, id := range requestIDs {
+		args[i] = id
+	}
+	where := ` AND request_id IN (?` + strings.Repeat(`, ?`, len(requestIDs)-1) + `)`
+	return s.callPerformance(teamName, where, args)
+}
+
+// callPerformance summarizes the team's model calls matching where, an
+// AND clause with args for its placeholders
+func (s *Store) callPerformance(teamName, where string, args []interface{}) (*TeamPerformance, error) {
+	query := `SELECT member_id, provider, model, duration_ms, prompt_tokens, completion_tokens, outcome FROM provider_calls WHERE team_name = ?` + where
+	rows, err := s.db.Query(query, append([]interface{}{teamName}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	perf := &TeamPerformance{Team: teamName, Total: CallStats{Outcomes: map[string]int{}}}
+	members := make(map[string]*MemberPerformance)
+	models := make(map[[2]string]*ModelPerformance)
+	for rows.Next() {
+		var memberID, prov, outcome string
+		var model sql.NullString
+		var durationMs int64
+		var promptTokens, completionTokens int
+		if err := rows.Scan(&memberID, &prov, &model, &durationMs, &promptTokens, &completionTokens, &outcome); err != nil {
+			return nil, err
+		}
+		perf.Total.add(durationMs, promptTokens, completionTokens, outcome)
+
+		mp := members[memberID]
+		if mp == nil {
+			mp = &MemberPerformance{MemberID: memberID}
+			members[memberID] = mp
+		}
+		mp.add(durationMs, promptTokens, completionTokens, outcome)
+
+		key := [2]string{prov, model.String}
+		mo := models[key]
+		if mo == nil {
+			mo = &ModelPerformance{Provider: prov, Model: model.String}
+			models[key] = mo
+		}
+		mo.add(durationMs, promptTokens, completionTokens, outcome)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	perf.Members = make([]MemberPerformance, 0, len(members))
+	for _, mp := range members {
+		perf.Members = append(perf.Members, *mp)
+	}
+	sort.Slice(perf.Members, func(i, j int) bool {
+		a, b := perf.Members[i], perf.Members[j]
+		if a.TotalMs != b.TotalMs {
+			return a.TotalMs > b.TotalMs
+		}
+		return a.MemberID < b.MemberID
+	})
+	perf.Models = make([]ModelPerformance, 0, len(models))
+	for _, mo := range models {
+		perf.Models = append(perf.Models, *mo)
+	}
+	sort.Slice(perf.Models, func(i, j int) bool {
+		a, b := perf.Models[i], perf.Models[j]
+		if a.TotalMs != b.TotalMs {
+			return a.TotalMs > b.TotalMs
+		}
+		return a.Provider+"/"+a.Model < b.Provider+"/"+b.Model
+	})
+	return perf, nil
+}
@@ -3,6 +3,7 @@ package manager
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -231,6 +232,48 @@ func TestStore_ConversationHistory(t *testing.T) {
 	}
 }
 
+func TestStore_ClientMessages(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "test.db")
+
+	store, err := NewStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	store.SaveTeam("test-team", "/path/to/spec.yaml")
+	conv, _ := store.CreateConversation("test-team")
+	other, _ := store.CreateConversation("test-team")
+
+	messages := []map[string]interface{}{
+		{"id": "1", "type": "client_request", "from": "client", "to": "pm-1", "content": "Build a login page", "conversation_id": conv.ID},
+		{"id": "2", "type": "task_assignment", "from": "pm-1", "to": "engineer-1", "content": "Implement login", "conversation_id": conv.ID},
+		{"id": "3", "type": "client_response", "from": "pm-1", "to": "client", "content": "Login page is ready", "conversation_id": conv.ID},
+		{"id": "4", "type": "client_request", "from": "client", "to": "pm-1", "content": "Another session", "conversation_id": other.ID},
+	}
+	for _, msg := range messages {
+		if err := store.SaveMessage("test-team", msg); err != nil {
+			t.Fatalf("SaveMessage failed: %v", err)
+		}
+	}
+
+	transcript, err := store.GetClientMessages(conv.ID)
+	if err != nil {
+		t.Fatalf("GetClientMessages failed: %v", err)
+	}
+	if len(transcript) != 2 {
+		t.Fatalf("Expected 2 client messages, got %d", len(transcript))
+	}
+
+	if transcript[0]["role"] != "user" || transcript[0]["member_id"] != "pm-1" || transcript[0]["content"] != "Build a login page" {
+		t.Errorf("Unexpected first message: %v", transcript[0])
+	}
+	if transcript[1]["role"] != "assistant" || transcript[1]["member_id"] != "pm-1" || transcript[1]["content"] != "Login page is ready" {
+		t.Errorf("Unexpected second message: %v", transcript[1])
+	}
+}
+
 func TestStore_MultipleConversations(t *testing.T) {
 	tmpDir := t.TempDir()
 	dbPath := filepath.Join(tmpDir, "test.db")
@@ -352,3 +395,169 @@ func TestStore_DBFileCreation(t *testing.T) {
 		}
 	}
 }
+
+func TestStore_SpecVersions(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "test.db")
+
+	store, err := NewStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	v1, err := store.SaveSpecVersion("my-team", "name: v1\n", 0)
+	if err != nil {
+		t.Fatalf("SaveSpecVersion failed: %v", err)
+	}
+	v2, _ := store.SaveSpecVersion("my-team", "name: v2\n", 0)
+	if v1 != 1 || v2 != 2 {
+		t.Errorf("Expected versions 1 and 2, got %d and %d", v1, v2)
+	}
+
+	// Saving unchanged content doesn't add a version
+	if v, _ := store.SaveSpecVersion("my-team", "name: v2\n", 0); v != 2 {
+		t.Errorf("Expected unchanged save to stay at version 2, got %d", v)
+	}
+
+	versions, err := store.ListSpecVersions("my-team")
+	if err != nil {
+		t.Fatalf("ListSpecVersions failed: %v", err)
+	}
+	if len(versions) != 2 || versions[0].Version != 2 || versions[1].Version != 1 {
+		t.Fatalf("Expected versions [2 1], got %+v", versions)
+	}
+
+	v, err := store.GetSpecVersion("my-team", 1)
+	if err != nil || v == nil {
+		t.Fatalf("GetSpecVersion failed: %v", err)
+	}
+	if v.Content != "name: v1\n" {
+		t.Errorf("Expected version 1 content, got %q", v.Content)
+	}
+	if v, _ := store.GetSpecVersion("my-team", 9); v != nil {
+		t.Error("Expected nil for missing version")
+	}
+
+	// Only the newest versions are kept
+	store.SaveSpecVersion("my-team", "name: v3\n", 2)
+	versions, _ = store.ListSpecVersions("my-team")
+	if len(versions) != 2 || versions[1].Version != 2 {
+		t.Errorf("Expected versions [3 2] after pruning, got %+v", versions)
+	}
+}
+
+func TestStore_ConversationTitles(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "test.db")
+
+	store, err := NewStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+
+	store.SaveTeam("test-team", "/path/to/spec.yaml")
+	conv, _ := store.CreateConversation("test-team")
+	store.SaveMessage("test-team", map[string]interface{}{
+		"id":              "msg-1",
+		"type":            "client_request",
+		"from":            "client",
+		"to":              "lead",
+		"content":         "Plan the Q3 launch\nwith details to follow",
+		"conversation_id": conv.ID,
+	})
+	store.Close()
+
+	// Reopening runs the migration, which backfills the missing title
+	store, err = NewStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to reopen store: %v", err)
+	}
+	defer store.Close()
+
+	active, _ := store.GetActiveConversation("test-team")
+	if active == nil || active.Title != "Plan the Q3 launch" {
+		t.Fatalf("Expected the backfilled title, got %+v", active)
+	}
+
+	// A title is only set once; a rename replaces it
+	if set, _ := store.TitleConversation(conv.ID, "Something else"); set {
+		t.Error("Expected an existing title to be kept")
+	}
+	if ok, err := store.RenameConversation(conv.ID, "Launch plan"); !ok || err != nil {
+		t.Fatalf("RenameConversation failed: %v, %v", ok, err)
+	}
+	if ok, _ := store.RenameConversation("no-such-conversation", "Launch plan"); ok {
+		t.Error("Expected renaming an unknown conversation to report it")
+	}
+
+	conversations, _ := store.ListConversations("test-team", 10)
+	if len(conversations) != 1 || conversations[0].Title != "Launch plan" {
+		t.Errorf("Expected the renamed title, got %+v", conversations)
+	}
+}
+
+func TestStore_AuditLog(t *testing.T) {
+	store, err := NewStore(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	start := time.Now().Add(-time.Hour)
+	for i, e := range []AuditEntry{
+		{Action: AuditTeamCreate, Team: "alpha"},
+		{Action: AuditChatRequest, Team: "alpha", Actor: "client", RequestID: "req-1", Details: map[string]interface{}{"content": "hi"}},
+		{Action: AuditTeamStart, Team: "beta"},
+	} {
+		e.Timestamp = start.Add(time.Duration(i) * time.Minute)
+		if err := store.AppendAudit(e); err != nil {
+			t.Fatalf("AppendAudit failed: %v", err)
+		}
+	}
+
+	entries, err := store.ListAudit(AuditFilter{Action: "team"})
+	if err != nil || len(entries) != 2 || entries[0].Action != AuditTeamCreate || entries[1].Team != "beta" {
+		t.Errorf("Expected both team actions, oldest first, got %+v (%v)", entries, err)
+	}
+	entries, _ = store.ListAudit(AuditFilter{RequestID: "req-1"})
+	if len(entries) != 1 || entries[0].Details["content"] != "hi" {
+		t.Errorf("Expected the request's entry with its details, got %+v", entries)
+	}
+	entries, _ = store.ListAudit(AuditFilter{Limit: 1})
+	if len(entries) != 1 || entries[0].Team != "beta" {
+		t.Errorf("Expected the limit to keep the newest entry, got %+v", entries)
+	}
+
+	// Deleting a team leaves its audit entries, and nothing can rewrite them
+	store.SaveTeam("alpha", "/specs/alpha.yaml")
+	if err := store.DeleteTeam("alpha"); err != nil {
+		t.Fatalf("DeleteTeam failed: %v", err)
+	}
+	if entries, _ := store.ListAudit(AuditFilter{Team: "alpha"}); len(entries) != 2 {
+		t.Errorf("Expected the deleted team's 2 entries kept, got %d", len(entries))
+	}
+	if _, err := store.db.Exec(`UPDATE audit_log SET actor = 'someone'`); err == nil {
+		t.Error("Expected updating the audit log to fail")
+	}
+	if _, err := store.db.Exec(`DELETE FROM audit_log`); err == nil {
+		t.Error("Expected deleting from the audit log to fail")
+	}
+}
+
+func TestConversationTitle(t *testing.T) {
+	tests := []struct {
+		message string
+		want    string
+	}{
+		{"Build a login page", "Build a login page"},
+		{"  Build a\tlogin   page  \n\nwith OAuth", "Build a login page"},
+		{"", ""},
+		{strings.Repeat("word ", 20), "word word word word word word word word word word word word…"},
+	}
+	for _, tt := range tests {
+		if got := conversationTitle(tt.message); got != tt.want {
+			t.Errorf("conversationTitle(%q) = %q, want %q", tt.message, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/arcslash/ugudu/internal/provider"
+)
+
+// ErrNoSummaryProvider is returned when no provider is available to
+// summarize a conversation with
+var ErrNoSummaryProvider = errors.New("no provider available to summarize with")
+
+const (
+	summaryMaxTokens     = 1024
+	maxSummaryTranscript = 100000 // Bytes of transcript sent; older messages are dropped first
+)
+
+const summaryPrompt = `Summarize the conversation between a client and an AI team that the user sends. Reply with a JSON object only:
+{"summary": "two or three sentences", "decisions": ["..."], "open_questions": ["..."], "artifacts": ["files, documents or other deliverables mentioned"]}
+Use empty lists where there's nothing to report.`
+
+// ConversationSummary condenses a conversation into what was decided, what's
+// still open and what was produced
+type ConversationSummary struct {
+	ConversationID string    `json:"conversation_id"`
+	Summary        string    `json:"summary"`
+	Decisions      []string  `json:"decisions"`
+	OpenQuestions  []string  `json:"open_questions"`
+	Artifacts      []string  `json:"artifacts"`
+	Sequence       int       `json:"sequence"` // Messages summarized
+	Provider       string    `json:"provider"`
+	Model          string    `json:"model"`
+	GeneratedAt    time.Time `json:"generated_at"`
+}
+
+// SummarizeConversation summarizes a conversation's transcript. Summaries
+// are cached until the conversation has new messages.
+func (m *Manager) SummarizeConversation(ctx context.Context, conversationID string) (*ConversationSummary, error) {
+	messages, err := m.store.GetClientMessages(conversationID)
+	if err != nil {
+		return nil, err
+	}
+	if len(messages) == 0 {
+		if messages, err = m.store.GetConversationHistory(conversationID); err != nil {
+			return nil, err
+		}
+	}
+	if len(messages) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrConversationNotFound, conversationID)
+	}
+
+	// Transcripts are only appended to, so the message count says whether
+	// a cached summary is stale
+	m.summaryMu.Lock()
+	cached := m.summaries[conversationID]
+	m.summaryMu.Unlock()
+	if cached != nil && cached.Sequence == len(messages) {
+		return cached, nil
+	}
+
+	p, model, err := m.summaryModel(conversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	maxTokens := summaryMaxTokens
+	resp, err := p.Chat(ctx, &provider.ChatRequest{
+		Model:     model,
+		MaxTokens: &maxTokens,
+		Messages: []provider.Message{
+			{Role: "system", Content: summaryPrompt},
+			{Role: "user", Content: summaryTranscript(messages)},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("summarize conversation: %w", err)
+	}
+
+	summary := parseSummary(resp.Content)
+	summary.ConversationID = conversationID
+	summary.Sequence = len(messages)
+	summary.Provider = p.ID()
+	summary.Model = model
+	summary.GeneratedAt = m.now()
+
+	m.summaryMu.Lock()
+	m.summaries[conversationID] = summary
+	m.summaryMu.Unlock()
+	return summary, nil
+}
+
+// summaryModel picks the provider and model to summarize with: the one the
+// conversation's team talks to the client with, or a small model if the
+// team isn't loaded
+func (m *Manager) summaryModel(conversationID string) (provider.Provider, string, error) {
+	conv, err := m.store.GetConversation(conversationID)
+	if err != nil {
+		return nil, "", err
+	}
+	if conv != nil {
+		m.mu.RLock()
+		t := m.teams[conv.TeamName]
+		m.mu.RUnlock()
+		if t != nil && len(t.ClientFacing) > 0 {
+			if role, ok := t.Spec.Roles[t.ClientFacing[0]]; ok {
+				if p, err := m.providers.Get(role.Model.Provider); err == nil {
+					return p, role.Model.Model, nil
+				}
+			}
+		}
+	}
+
+	if p, model, ok := m.providers.SmallModel(); ok {
+		return p, model, nil
+	}
+	return nil, "", fmt.Errorf("%w: configure a provider such as anthropic, openai or ollama", ErrNoSummaryProvider)
+}
+
+// summaryTranscript renders messages as text for the summary prompt,
+// dropping the oldest if it's over maxSummaryTranscript
+func summaryTranscript(messages []map[string]interface{}) string {
+	lines := make([]string, len(messages))
+	for i, msg := range messages {
+		speaker := "client"
+		if msg["role"] == "assistant" {
+			speaker = fmt.Sprint(msg["member_id"])
+		}
+		lines[i] = fmt.Sprintf("%s: %s\n", speaker, msg["content"])
+	}
+
+	size, start := 0, len(lines)
+	for start > 0 && size+len(lines[start-1]) <= maxSummaryTranscript {
+		start--
+		size += len(lines[start])
+	}
+	if start == len(lines) {
+		// A single message over the limit still gets summarized
+		start--
+	}
+
+	var sb strings.Builder
+	if start > 0 {
+		fmt.Fprintf(&sb, "(%d earlier messages left out)\n\n", start)
+	}
+	for _, line := range lines[start:] {
+		sb.WriteString(line)
+	}
+	return sb.String()
+}
+
+// parseSummary reads the model's JSON reply. A reply that isn't JSON is
+// kept whole as the summary rather than failing.
+func parseSummary(content string) *ConversationSummary {
+	summary := &ConversationSummary{}
+	start, end := strings.IndexByte(content, '{'), strings.LastIndexByte(content, '}')
+	if start < 0 || end < start || json.Unmarshal([]byte(content[start:end+1]), summary) != nil {
+		summary = &ConversationSummary{Summary: strings.TrimSpace(content)}
+	}
+	for _, list := range []*[]string{&summary.Decisions, &summary.OpenQuestions, &summary.Artifacts} {
+		if *list == nil {
+			*list = []string{}
+		}
+	}
+	return summary
+}
@@ -0,0 +1,105 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/arcslash/ugudu/internal/provider"
+)
+
+const (
+	maxTitleRunes  = 60 // Caps a conversation title taken from its first message
+	titleMaxTokens = 30 // Caps a model-written title
+)
+
+// conversationTitle makes a title from a conversation's first message: its
+// first line, with whitespace collapsed, cut to maxTitleRunes
+func conversationTitle(message string) string {
+	line := strings.TrimSpace(message)
+	if i := strings.IndexByte(line, '\n'); i >= 0 {
+		line = line[:i]
+	}
+	line = strings.Join(strings.Fields(line), " ")
+
+	runes := []rune(line)
+	if len(runes) <= maxTitleRunes {
+		return line
+	}
+	cut := string(runes[:maxTitleRunes])
+	if i := strings.LastIndexByte(cut, ' '); i > maxTitleRunes/2 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, " ,.;:") + "…"
+}
+
+// titleConversation titles a new conversation after its first client
+// message. With model titles on, a small model then replaces the title with
+// a summary in the background.
+func (m *Manager) titleConversation(conversationID, message string) {
+	title := conversationTitle(message)
+	if title == "" {
+		return
+	}
+	set, err := m.store.TitleConversation(conversationID, title)
+	if err != nil {
+		m.logger.Warn("failed to title conversation", "conversation", conversationID, "error", err)
+		return
+	}
+	if set && m.config.ModelTitles {
+		go m.modelTitle(conversationID, message, title)
+	}
+}
+
+// modelTitle asks a small model for a conversation title, keeping the
+// truncated one if none is available or the call fails
+func (m *Manager) modelTitle(conversationID, message, fallback string) {
+	p, model, ok := m.providers.SmallModel()
+	if !ok {
+		return
+	}
+
+	ctx := m.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultRequestTimeout)
+	defer cancel()
+
+	maxTokens := titleMaxTokens
+	resp, err := p.Chat(ctx, &provider.ChatRequest{
+		Model:     model,
+		MaxTokens: &maxTokens,
+		Messages: []provider.Message{
+			{Role: "system", Content: "Write a title of at most six words for a conversation that starts with the user's message. Reply with the title only, without quotes."},
+			{Role: "user", Content: message},
+		},
+	})
+	if err != nil {
+		m.logger.Debug("model title failed, keeping the first message", "conversation", conversationID, "error", err)
+		return
+	}
+	title := conversationTitle(strings.Trim(resp.Content, "\"' \n"))
+	if title == "" {
+		return
+	}
+	if _, err := m.store.ReplaceConversationTitle(conversationID, fallback, title); err != nil {
+		m.logger.Warn("failed to save model title", "conversation", conversationID, "error", err)
+	}
+}
+
+// RenameConversation sets a conversation's title
+func (m *Manager) RenameConversation(conversationID, title string) error {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return fmt.Errorf("title is required")
+	}
+	ok, err := m.store.RenameConversation(conversationID, title)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrConversationNotFound, conversationID)
+	}
+	return nil
+}
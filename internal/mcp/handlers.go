@@ -10,6 +10,7 @@ import (
 
 	"github.com/arcslash/ugudu/internal/config"
 	"github.com/arcslash/ugudu/internal/daemon"
+	"github.com/arcslash/ugudu/internal/team"
 )
 
 func (s *Server) handleListTeams(ctx context.Context, args map[string]interface{}) (interface{}, error) {
@@ -49,11 +50,22 @@ func (s *Server) handleCreateTeam(ctx context.Context, args map[string]interface
 
 	name, _ := args["name"].(string)
 	spec, _ := args["spec"].(string)
+	force, _ := args["force"].(bool)
 
 	if name == "" || spec == "" {
 		return nil, fmt.Errorf("both 'name' and 'spec' are required")
 	}
 
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	// Check before writing the spec file, which would overwrite the team's
+	if !force {
+		if _, err := s.client.GetTeam(ctx, name); err == nil {
+			return nil, fmt.Errorf("team '%s' already exists. Pick another name, or set force to replace it", name)
+		}
+	}
+
 	// Resolve spec path
 	specPath := filepath.Join(config.SpecsDir(), spec+".yaml")
 	if _, err := os.Stat(specPath); os.IsNotExist(err) {
@@ -67,18 +79,18 @@ func (s *Server) handleCreateTeam(ctx context.Context, args map[string]interface
 	}
 
 	// Replace name in spec
-	modifiedSpec := replaceTeamNameInSpec(string(content), name)
+	modifiedSpec, err := team.RenameSpec(content, name)
+	if err != nil {
+		return nil, err
+	}
 
 	// Write to persistent spec file in ~/.ugudu/specs/
 	specFile := filepath.Join(config.SpecsDir(), name+".yaml")
-	if err := os.WriteFile(specFile, []byte(modifiedSpec), 0644); err != nil {
+	if err := os.WriteFile(specFile, modifiedSpec, 0644); err != nil {
 		return nil, fmt.Errorf("failed to write spec file: %w", err)
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
-	defer cancel()
-
-	result, err := s.client.CreateTeam(ctx, specFile)
+	result, err := s.client.CreateSeededTeam(ctx, specFile, "", "", force)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create team: %w", err)
 	}
@@ -101,22 +113,34 @@ func (s *Server) handleAsk(ctx context.Context, args map[string]interface{}) (in
 		return nil, fmt.Errorf("both 'team' and 'message' are required")
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, 120*time.Second)
+	askCtx, cancel := context.WithTimeout(ctx, s.askTimeout)
 	defer cancel()
 
 	// Start team if not running
-	_ = s.client.StartTeam(ctx, team)
-
-	responses, err := s.client.Chat(ctx, team, message, "")
-	if err != nil {
-		return nil, fmt.Errorf("failed to send message: %w", err)
-	}
+	_ = s.client.StartTeam(askCtx, team)
 
+	// Stream responses so that whatever arrived before the timeout can
+	// still be reported
 	var sb strings.Builder
-	for _, resp := range responses {
+	var progress string
+	outcome, err := s.client.ChatStream(askCtx, team, message, daemon.ChatOptions{Timeout: s.askTimeout}, func(resp map[string]interface{}) {
 		from, _ := resp["from"].(string)
 		content, _ := resp["content"].(string)
+		if p, _ := resp["progress"].(bool); p {
+			// Only worth reporting if the answer doesn't arrive in time
+			progress = content
+			return
+		}
 		sb.WriteString(fmt.Sprintf("%s: %s\n", from, content))
+	})
+	if outcome.TimedOut || (askCtx.Err() == context.DeadlineExceeded && ctx.Err() == nil) {
+		if progress != "" {
+			sb.WriteString(fmt.Sprintf("Last update: %s\n", progress))
+		}
+		return stillWorking(team, s.askTimeout, sb.String()), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to send message: %w", err)
 	}
 
 	if sb.Len() == 0 {
@@ -126,6 +150,76 @@ func (s *Server) handleAsk(ctx context.Context, args map[string]interface{}) (in
 	return sb.String(), nil
 }
 
+// stillWorking reports an ask that outlasted its timeout. The team carries
+// on in the background, so point the caller at the tools that show progress
+// rather than failing the call.
+func stillWorking(team string, waited time.Duration, partial string) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("The team is still working on this (no final reply after %s).\n\n", waited))
+	if partial != "" {
+		sb.WriteString("Responses so far:\n")
+		sb.WriteString(partial)
+		sb.WriteString("\n")
+	}
+	sb.WriteString(fmt.Sprintf("Check back with ugudu_recent_messages team=%s to read replies as they arrive, or ugudu_team_status team=%s to see who is busy.", team, team))
+	return sb.String()
+}
+
+func (s *Server) handleRecentMessages(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	if err := s.ensureClient(); err != nil {
+		return nil, err
+	}
+
+	team, _ := args["team"].(string)
+	if team == "" {
+		return nil, fmt.Errorf("'team' is required")
+	}
+
+	limit := 10
+	if l, ok := args["limit"].(float64); ok && l > 0 {
+		limit = int(l)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	conversations, err := s.client.ListConversations(ctx, team, 1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list conversations: %w", err)
+	}
+	if len(conversations) == 0 {
+		return "No messages yet for this team.", nil
+	}
+
+	id, _ := conversations[0]["id"].(string)
+	messages, err := s.client.GetConversationHistory(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get messages: %w", err)
+	}
+	if len(messages) == 0 {
+		return "No messages yet for this team.", nil
+	}
+	if len(messages) > limit {
+		messages = messages[len(messages)-limit:]
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Recent messages for %s:\n\n", team))
+	for _, m := range messages {
+		member, _ := m["member_id"].(string)
+		role, _ := m["role"].(string)
+		content, _ := m["content"].(string)
+
+		from := member
+		if role == "user" {
+			from = "client → " + member
+		}
+		sb.WriteString(fmt.Sprintf("%s: %s\n", from, content))
+	}
+
+	return sb.String(), nil
+}
+
 func (s *Server) handleTeamStatus(ctx context.Context, args map[string]interface{}) (interface{}, error) {
 	if err := s.ensureClient(); err != nil {
 		return nil, err
@@ -229,6 +323,19 @@ func (s *Server) handleDaemonStatus(ctx context.Context, args map[string]interfa
 	var sb strings.Builder
 	sb.WriteString("Daemon is running.\n\n")
 
+	if info, ok := status["daemon"].(map[string]interface{}); ok {
+		sb.WriteString(fmt.Sprintf("Version: %v\n", info["version"]))
+		sb.WriteString(fmt.Sprintf("Uptime: %v\n", info["uptime"]))
+		sb.WriteString(fmt.Sprintf("Goroutines: %v\n", info["goroutines"]))
+		if mem, ok := info["memory"].(map[string]interface{}); ok {
+			alloc, _ := mem["alloc_bytes"].(float64)
+			sb.WriteString(fmt.Sprintf("Memory in use: %.1f MiB\n", alloc/(1<<20)))
+		}
+		sb.WriteString(fmt.Sprintf("Active requests: %v\n", info["active_requests"]))
+		size, _ := info["data_dir_bytes"].(float64)
+		sb.WriteString(fmt.Sprintf("Data dir: %v (%.1f MiB)\n\n", info["data_dir"], size/(1<<20)))
+	}
+
 	if teams, ok := status["teams"].([]interface{}); ok {
 		sb.WriteString(fmt.Sprintf("Teams: %d\n", len(teams)))
 	}
@@ -305,20 +412,6 @@ func (s *Server) handleDeleteTeam(ctx context.Context, args map[string]interface
 	return fmt.Sprintf("Team '%s' deleted.", team), nil
 }
 
-// Helper function to replace team name in YAML
-func replaceTeamNameInSpec(specContent, teamName string) string {
-	lines := strings.Split(specContent, "\n")
-	for i, line := range lines {
-		trimmed := strings.TrimSpace(line)
-		if strings.HasPrefix(trimmed, "name:") {
-			indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
-			lines[i] = indent + "name: " + teamName
-			break
-		}
-	}
-	return strings.Join(lines, "\n")
-}
-
 // ============================================================================
 // Project Workflow Handlers
 // ============================================================================
@@ -335,12 +428,15 @@ func (s *Server) handleStartProject(ctx context.Context, args map[string]interfa
 		return nil, fmt.Errorf("both 'team' and 'request' are required")
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, 600*time.Second)
+	projectCtx, cancel := context.WithTimeout(ctx, s.projectTimeout)
 	defer cancel()
 
 	// Start project via the daemon
-	result, err := s.client.StartProject(ctx, team, request)
+	result, err := s.client.StartProject(projectCtx, team, request)
 	if err != nil {
+		if projectCtx.Err() == context.DeadlineExceeded && ctx.Err() == nil {
+			return fmt.Sprintf("The team is still setting up the project (no reply after %s).\n\nCheck back with ugudu_project_status team=%s.", s.projectTimeout, team), nil
+		}
 		return nil, fmt.Errorf("failed to start project: %w", err)
 	}
 
@@ -515,6 +611,7 @@ func (s *Server) handleSetTokenMode(ctx context.Context, args map[string]interfa
 
 	team, _ := args["team"].(string)
 	mode, _ := args["mode"].(string)
+	persist, _ := args["persist"].(bool)
 
 	if team == "" || mode == "" {
 		return nil, fmt.Errorf("team and mode are required")
@@ -523,7 +620,7 @@ func (s *Server) handleSetTokenMode(ctx context.Context, args map[string]interfa
 	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
-	if err := s.client.SetTokenMode(ctx, team, mode); err != nil {
+	if err := s.client.SetTokenMode(ctx, team, mode, persist); err != nil {
 		return nil, fmt.Errorf("failed to set token mode: %w", err)
 	}
 
@@ -558,7 +655,7 @@ func (s *Server) handleClearConversation(ctx context.Context, args map[string]in
 
 func (s *Server) handleListSpecialists(ctx context.Context, args map[string]interface{}) (interface{}, error) {
 	// List specialist templates from ~/.ugudu/specs/specialists/
-	specsDir := filepath.Join(config.SpecsDir(), "specialists")
+	specsDir := config.SpecialistsDir()
 	entries, err := os.ReadDir(specsDir)
 	if err != nil {
 		return "No specialists found. Create them in ~/.ugudu/specs/specialists/", nil
@@ -583,11 +680,41 @@ func (s *Server) handleListSpecialists(ctx context.Context, args map[string]inte
 		desc := getSpecialistDescription(name)
 		sb.WriteString(fmt.Sprintf("  - %s: %s\n", name, desc))
 	}
-	sb.WriteString("\nTo add a specialist to a team, include them in the team spec's roles section.")
+	sb.WriteString("\nTo add a specialist to a team spec, use ugudu_add_specialist.")
 
 	return sb.String(), nil
 }
 
+func (s *Server) handleAddSpecialist(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	if err := s.ensureClient(); err != nil {
+		return nil, err
+	}
+
+	spec, _ := args["spec"].(string)
+	specialist, _ := args["specialist"].(string)
+	if spec == "" || specialist == "" {
+		return nil, fmt.Errorf("spec and specialist are required")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	result, err := s.client.AddSpecialist(ctx, spec, specialist)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add specialist: %w", err)
+	}
+
+	roleID, _ := result["role_id"].(string)
+	title, _ := result["title"].(string)
+	msg := fmt.Sprintf("Added %s (%s) to spec '%s'.", title, roleID, spec)
+	if lead, _ := result["lead"].(string); lead != "" {
+		msg += fmt.Sprintf(" %s can now delegate to %s.", lead, roleID)
+	}
+	msg += "\nRecreate or restart teams built from this spec to pick up the new role."
+
+	return msg, nil
+}
+
 func getSpecialistDescription(name string) string {
 	descriptions := map[string]string{
 		"healthcare-sme":    "FHIR, HIPAA, clinical workflows expert",
@@ -8,10 +8,19 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strconv"
 	"sync"
+	"time"
 
 	"github.com/arcslash/ugudu/internal/daemon"
 	"github.com/arcslash/ugudu/internal/logger"
+	"github.com/arcslash/ugudu/internal/version"
+)
+
+// Default time a tool waits on the team before reporting back
+const (
+	DefaultAskTimeout     = 120 * time.Second
+	DefaultProjectTimeout = 600 * time.Second
 )
 
 // Server implements an MCP server for Ugudu
@@ -21,6 +30,45 @@ type Server struct {
 	tools   map[string]Tool
 	running bool
 	mu      sync.RWMutex
+
+	askTimeout     time.Duration
+	projectTimeout time.Duration
+
+	// connect dials the daemon; retryDelay is how long ensureClient waits
+	// before its one retry when that fails
+	connect    func() (*daemon.Client, error)
+	retryDelay time.Duration
+}
+
+// ServerOption configures a Server
+type ServerOption func(*Server)
+
+// WithAskTimeout sets how long ugudu_ask waits for the team before returning
+// what it has so far. Overrides UGUDU_MCP_ASK_TIMEOUT.
+func WithAskTimeout(d time.Duration) ServerOption {
+	return func(s *Server) {
+		if d > 0 {
+			s.askTimeout = d
+		}
+	}
+}
+
+// WithProjectTimeout sets how long ugudu_start_project waits for the daemon.
+// Overrides UGUDU_MCP_PROJECT_TIMEOUT.
+func WithProjectTimeout(d time.Duration) ServerOption {
+	return func(s *Server) {
+		if d > 0 {
+			s.projectTimeout = d
+		}
+	}
+}
+
+// WithClient uses an existing daemon client instead of finding the local socket
+func WithClient(client *daemon.Client) ServerOption {
+	return func(s *Server) {
+		s.client = client
+		s.connect = func() (*daemon.Client, error) { return client, nil }
+	}
 }
 
 // Tool represents an MCP tool
@@ -49,24 +97,49 @@ type RPCError struct {
 }
 
 // NewServer creates a new MCP server
-func NewServer(log *logger.Logger) (*Server, error) {
-	// Try to connect to daemon
-	client, err := daemon.NewClient("")
-	if err != nil {
-		// Daemon not running - will try to connect later
-		log.Warn("daemon not running, some tools will be unavailable")
+func NewServer(log *logger.Logger, opts ...ServerOption) (*Server, error) {
+	s := &Server{
+		logger:         log,
+		tools:          make(map[string]Tool),
+		askTimeout:     envDuration("UGUDU_MCP_ASK_TIMEOUT", DefaultAskTimeout),
+		projectTimeout: envDuration("UGUDU_MCP_PROJECT_TIMEOUT", DefaultProjectTimeout),
+		connect:        func() (*daemon.Client, error) { return daemon.NewClient("") },
+		retryDelay:     time.Second,
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
 
-	s := &Server{
-		client: client,
-		logger: log,
-		tools:  make(map[string]Tool),
+	if s.client == nil {
+		// Try to connect to daemon
+		client, err := s.connect()
+		if err != nil {
+			// Daemon not running - will try to connect later
+			log.Warn("daemon not running, some tools will be unavailable")
+		}
+		s.client = client
 	}
 
 	s.registerTools()
 	return s, nil
 }
 
+// envDuration reads a timeout from the environment, either as a Go
+// duration ("90s", "5m") or a number of seconds
+func envDuration(key string, def time.Duration) time.Duration {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	if d, err := time.ParseDuration(v); err == nil && d > 0 {
+		return d
+	}
+	if n, err := strconv.Atoi(v); err == nil && n > 0 {
+		return time.Duration(n) * time.Second
+	}
+	return def
+}
+
 func (s *Server) registerTools() {
 	// Team management tools
 	s.tools["ugudu_list_teams"] = Tool{
@@ -93,6 +166,10 @@ func (s *Server) registerTools() {
 					"type":        "string",
 					"description": "Name of the spec to use (from ugudu_list_specs)",
 				},
+				"force": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Replace a team of the same name, deleting its history (default false)",
+				},
 			},
 			"required": []string{"name", "spec"},
 		},
@@ -119,6 +196,26 @@ func (s *Server) registerTools() {
 		Handler: s.handleAsk,
 	}
 
+	s.tools["ugudu_recent_messages"] = Tool{
+		Name:        "ugudu_recent_messages",
+		Description: "Read the latest messages between the client and a team. Use this to pick up replies after ugudu_ask reports the team is still working.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"team": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the team",
+				},
+				"limit": map[string]interface{}{
+					"type":        "integer",
+					"description": "Number of messages to return (default: 10)",
+				},
+			},
+			"required": []string{"team"},
+		},
+		Handler: s.handleRecentMessages,
+	}
+
 	s.tools["ugudu_team_status"] = Tool{
 		Name:        "ugudu_team_status",
 		Description: "Get the status of a specific team including its members and their current state",
@@ -316,6 +413,10 @@ func (s *Server) registerTools() {
 					"description": "Token mode: 'normal' (default), 'low' (reduced prompts/context), or 'minimal' (bare minimum)",
 					"enum":        []string{"normal", "low", "minimal"},
 				},
+				"persist": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Keep the mode after the daemon restarts (default false)",
+				},
 			},
 			"required": []string{"team", "mode"},
 		},
@@ -349,6 +450,26 @@ func (s *Server) registerTools() {
 		},
 		Handler: s.handleListSpecialists,
 	}
+
+	s.tools["ugudu_add_specialist"] = Tool{
+		Name:        "ugudu_add_specialist",
+		Description: "Add a specialist template as a new role in a team spec. The team lead is allowed to delegate to it.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the spec to modify",
+				},
+				"specialist": map[string]interface{}{
+					"type":        "string",
+					"description": "Specialist template name (see ugudu_list_specialists)",
+				},
+			},
+			"required": []string{"spec", "specialist"},
+		},
+		Handler: s.handleAddSpecialist,
+	}
 }
 
 // Run starts the MCP server on stdin/stdout
@@ -423,7 +544,7 @@ func (s *Server) handleInitialize(msg Message) *Message {
 			},
 			"serverInfo": map[string]interface{}{
 				"name":    "ugudu",
-				"version": "0.1.0",
+				"version": version.Version,
 			},
 		},
 	}
@@ -519,20 +640,41 @@ func (s *Server) handleToolsCall(msg Message) *Message {
 	}
 }
 
-// ensureClient ensures we have a connection to the daemon
+// ensureClient ensures we have a connection to the daemon. A daemon that is
+// restarting or briefly busy gets one retry before the tool call fails.
 func (s *Server) ensureClient() error {
+	err := s.tryConnect()
+	if err == nil {
+		return nil
+	}
+
+	s.logger.Debug("daemon connection failed, retrying", "error", err)
+	time.Sleep(s.retryDelay)
+	if err := s.tryConnect(); err != nil {
+		return fmt.Errorf("daemon not running. Start it with: ugudu daemon")
+	}
+	return nil
+}
+
+// tryConnect pings the current client, reconnecting if it doesn't answer
+func (s *Server) tryConnect() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
 	if s.client != nil {
 		// Test connection
-		ctx := context.Background()
 		if err := s.client.Ping(ctx); err == nil {
 			return nil
 		}
 	}
 
 	// Try to reconnect
-	client, err := daemon.NewClient("")
+	client, err := s.connect()
 	if err != nil {
-		return fmt.Errorf("daemon not running. Start it with: ugudu daemon")
+		return err
+	}
+	if err := client.Ping(ctx); err != nil {
+		return err
 	}
 	s.client = client
 	return nil
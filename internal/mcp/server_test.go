@@ -0,0 +1,140 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arcslash/ugudu/internal/daemon"
+	"github.com/arcslash/ugudu/internal/logger"
+)
+
+// slowDaemon answers health checks and streams one chat response, then
+// keeps the team "working" until the caller gives up
+func slowDaemon(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/health", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"status":"ok"}`)
+	})
+	mux.HandleFunc("/api/teams/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"status":"started"}`)
+	})
+	mux.HandleFunc("/api/chat", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		fmt.Fprintln(w, `{"from":"PM","content":"On it, looping in engineering."}`)
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	})
+
+	ts := httptest.NewServer(mux)
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func TestHandleAsk_TimeoutReportsProgress(t *testing.T) {
+	ts := slowDaemon(t)
+	client := daemon.NewRemoteClient(strings.TrimPrefix(ts.URL, "http://"))
+
+	s, err := NewServer(logger.New("error"), WithClient(client), WithAskTimeout(200*time.Millisecond))
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+
+	result, err := s.handleAsk(context.Background(), map[string]interface{}{
+		"team":    "alpha",
+		"message": "build a todo API",
+	})
+	if err != nil {
+		t.Fatalf("Expected a progress message rather than an error, got %v", err)
+	}
+
+	text, _ := result.(string)
+	for _, want := range []string{
+		"still working",
+		"200ms",
+		"PM: On it, looping in engineering.",
+		"ugudu_recent_messages team=alpha",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected %q in result, got:\n%s", want, text)
+		}
+	}
+}
+
+func TestNewServer_TimeoutsFromEnv(t *testing.T) {
+	unreachable := daemon.NewRemoteClient("127.0.0.1:1")
+
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", DefaultAskTimeout},
+		{"90", 90 * time.Second},
+		{"5m", 5 * time.Minute},
+		{"soon", DefaultAskTimeout},
+		{"-3", DefaultAskTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Setenv("UGUDU_MCP_ASK_TIMEOUT", tt.value)
+		s, err := NewServer(logger.New("error"), WithClient(unreachable))
+		if err != nil {
+			t.Fatalf("NewServer failed: %v", err)
+		}
+		if s.askTimeout != tt.want {
+			t.Errorf("UGUDU_MCP_ASK_TIMEOUT=%q: expected %v, got %v", tt.value, tt.want, s.askTimeout)
+		}
+	}
+
+	// Options win over the environment
+	t.Setenv("UGUDU_MCP_PROJECT_TIMEOUT", "30s")
+	s, _ := NewServer(logger.New("error"), WithClient(unreachable), WithProjectTimeout(time.Hour))
+	if s.projectTimeout != time.Hour {
+		t.Errorf("Expected option to override env, got %v", s.projectTimeout)
+	}
+}
+
+func TestEnsureClient_RetriesOnce(t *testing.T) {
+	ts := slowDaemon(t)
+	client := daemon.NewRemoteClient(strings.TrimPrefix(ts.URL, "http://"))
+	unreachable := daemon.NewRemoteClient("127.0.0.1:1")
+
+	attempts := 0
+	s, _ := NewServer(logger.New("error"), WithClient(unreachable))
+	s.retryDelay = time.Millisecond
+	s.connect = func() (*daemon.Client, error) {
+		attempts++
+		if attempts == 1 {
+			return nil, errors.New("connection refused")
+		}
+		return client, nil
+	}
+
+	if err := s.ensureClient(); err != nil {
+		t.Fatalf("Expected retry to connect, got %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("Expected 2 connection attempts, got %d", attempts)
+	}
+
+	// A daemon that stays down fails after a single retry
+	attempts = 0
+	s.client = nil
+	s.connect = func() (*daemon.Client, error) {
+		attempts++
+		return nil, errors.New("connection refused")
+	}
+	if err := s.ensureClient(); err == nil || !strings.Contains(err.Error(), "ugudu daemon") {
+		t.Errorf("Expected daemon-not-running error, got %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("Expected 2 connection attempts, got %d", attempts)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"sync"
 	"time"
 )
@@ -30,6 +31,12 @@ type Anthropic struct {
 	workerMu       sync.Mutex
 	stopWorker     chan struct{}
 
+	// Caps simultaneous HTTP calls; nil means unlimited
+	slots *concurrencyLimit
+
+	// Mark system prompt and tools as cacheable unless a request says otherwise
+	promptCaching bool
+
 	// Callbacks
 	onRateLimited func(RateLimitInfo)
 	onResume      func()
@@ -59,6 +66,24 @@ func WithResumeCallback(cb func()) AnthropicOption {
 	}
 }
 
+// WithMaxConcurrency caps how many requests may be in flight at once, so a
+// burst of parallel calls is smoothed out instead of tripping rate limits.
+// Zero or less means unlimited.
+func WithMaxConcurrency(n int) AnthropicOption {
+	return func(a *Anthropic) {
+		a.slots = newConcurrencyLimit(n)
+	}
+}
+
+// WithPromptCaching marks the system prompt and tool definitions with
+// cache_control so repeated turns read them from Anthropic's prompt cache.
+// A request's PromptCaching field overrides this.
+func WithPromptCaching(enabled bool) AnthropicOption {
+	return func(a *Anthropic) {
+		a.promptCaching = enabled
+	}
+}
+
 // NewAnthropic creates a new Anthropic provider
 func NewAnthropic(apiKey, baseURL string, opts ...AnthropicOption) *Anthropic {
 	if baseURL == "" {
@@ -68,7 +93,7 @@ func NewAnthropic(apiKey, baseURL string, opts ...AnthropicOption) *Anthropic {
 	a := &Anthropic{
 		apiKey:         apiKey,
 		baseURL:        baseURL,
-		client:         &http.Client{Timeout: 120 * time.Second},
+		client:         &http.Client{}, // Calls are bounded by their context
 		rateLimitState: NewRateLimitState(),
 		requestQueue:   NewRequestQueue(100),
 		autoResume:     true, // Enabled by default
@@ -90,9 +115,26 @@ func NewAnthropic(apiKey, baseURL string, opts ...AnthropicOption) *Anthropic {
 	return a
 }
 
+// anthropicBaseURL turns ANTHROPIC_BASE_URL into the API URL. Like in
+// Anthropic's own SDKs it's the host, without the /v1 the API lives under,
+// so that's added; a URL that already ends in /v1 is left as it is.
+func anthropicBaseURL(base string) string {
+	if base == "" {
+		return ""
+	}
+	base = strings.TrimRight(base, "/")
+	if strings.HasSuffix(base, "/v1") {
+		return base
+	}
+	return base + "/v1"
+}
+
 func (a *Anthropic) ID() string   { return "anthropic" }
 func (a *Anthropic) Name() string { return "Anthropic Claude" }
 
+// ImageToolResults reports that Claude can see the images a tool returned
+func (a *Anthropic) ImageToolResults() bool { return true }
+
 // RateLimitStatus returns current rate limit information
 func (a *Anthropic) RateLimitStatus() *RateLimitState {
 	return a.rateLimitState
@@ -113,6 +155,18 @@ func (a *Anthropic) PendingRequests() int {
 	return a.requestQueue.Len()
 }
 
+// QueueState returns the number of queued requests and how long until the
+// rate limit lifts
+func (a *Anthropic) QueueState() (pending int, resumeIn time.Duration) {
+	return a.requestQueue.Len(), a.rateLimitState.TimeUntilResume()
+}
+
+// Concurrency returns how many requests are in flight and the configured
+// maximum (0 when unlimited)
+func (a *Anthropic) Concurrency() (inUse, limit int) {
+	return a.slots.usage()
+}
+
 func (a *Anthropic) Chat(ctx context.Context, req *ChatRequest) (*ChatResponse, error) {
 	// Check if we're currently rate limited
 	if a.rateLimitState.IsRateLimited() {
@@ -150,8 +204,15 @@ func (a *Anthropic) Chat(ctx context.Context, req *ChatRequest) (*ChatResponse,
 	return resp, nil
 }
 
-// doChat performs the actual API call
+// doChat performs the actual API call, waiting for a free slot first when
+// concurrency is limited. Queued requests replayed by the resume worker come
+// through here too, so they share the same cap.
 func (a *Anthropic) doChat(ctx context.Context, req *ChatRequest) (*ChatResponse, error) {
+	if err := a.slots.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer a.slots.release()
+
 	anthropicReq := a.convertRequest(req)
 
 	body, err := json.Marshal(anthropicReq)
@@ -186,6 +247,9 @@ func (a *Anthropic) doChat(ctx context.Context, req *ChatRequest) (*ChatResponse
 	}
 
 	if resp.StatusCode != http.StatusOK {
+		if isAuthStatus(resp.StatusCode) {
+			return nil, newAuthError(a.Name(), resp.StatusCode, bodyBytes)
+		}
 		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(bodyBytes))
 	}
 
@@ -377,6 +441,10 @@ func (a *Anthropic) Stream(ctx context.Context, req *ChatRequest) (<-chan Stream
 
 		if resp.StatusCode != http.StatusOK {
 			bodyBytes, _ := io.ReadAll(resp.Body)
+			if isAuthStatus(resp.StatusCode) {
+				ch <- StreamChunk{Error: newAuthError(a.Name(), resp.StatusCode, bodyBytes)}
+				return
+			}
 			ch <- StreamChunk{Error: fmt.Errorf("API error %d: %s", resp.StatusCode, string(bodyBytes))}
 			return
 		}
@@ -417,9 +485,9 @@ func (a *Anthropic) Stream(ctx context.Context, req *ChatRequest) (<-chan Stream
 
 func (a *Anthropic) ListModels(ctx context.Context) ([]ModelInfo, error) {
 	return []ModelInfo{
-		{ID: "claude-sonnet-4-20250514", Name: "Claude Sonnet 4", Provider: "anthropic", MaxTokens: 8192},
-		{ID: "claude-opus-4-20250514", Name: "Claude Opus 4", Provider: "anthropic", MaxTokens: 8192},
-		{ID: "claude-3-5-haiku-20241022", Name: "Claude 3.5 Haiku", Provider: "anthropic", MaxTokens: 8192},
+		{ID: "claude-sonnet-4-20250514", Name: "Claude Sonnet 4", Provider: "anthropic", MaxTokens: 200000, MaxOutputTokens: 64000},
+		{ID: "claude-opus-4-20250514", Name: "Claude Opus 4", Provider: "anthropic", MaxTokens: 200000, MaxOutputTokens: 32000},
+		{ID: "claude-3-5-haiku-20241022", Name: "Claude 3.5 Haiku", Provider: "anthropic", MaxTokens: 200000, MaxOutputTokens: 8192},
 	}, nil
 }
 
@@ -457,8 +525,10 @@ type anthropicContent struct {
 }
 
 type anthropicUsage struct {
-	InputTokens  int `json:"input_tokens"`
-	OutputTokens int `json:"output_tokens"`
+	InputTokens              int `json:"input_tokens"`
+	OutputTokens             int `json:"output_tokens"`
+	CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
+	CacheReadInputTokens     int `json:"cache_read_input_tokens"`
 }
 
 func (a *Anthropic) convertRequest(req *ChatRequest) map[string]interface{} {
@@ -474,13 +544,31 @@ func (a *Anthropic) convertRequest(req *ChatRequest) map[string]interface{} {
 
 		// Handle tool result messages
 		if msg.Role == "tool" {
+			var content interface{} = msg.Content
+			if len(msg.Images) > 0 {
+				var blocks []map[string]interface{}
+				if msg.Content != "" {
+					blocks = append(blocks, map[string]interface{}{"type": "text", "text": msg.Content})
+				}
+				for _, img := range msg.Images {
+					blocks = append(blocks, map[string]interface{}{
+						"type": "image",
+						"source": map[string]interface{}{
+							"type":       "base64",
+							"media_type": img.MediaType,
+							"data":       img.Data,
+						},
+					})
+				}
+				content = blocks
+			}
 			messages = append(messages, map[string]interface{}{
 				"role": "user",
 				"content": []map[string]interface{}{
 					{
 						"type":        "tool_result",
 						"tool_use_id": msg.ToolCallID,
-						"content":     msg.Content,
+						"content":     content,
 					},
 				},
 			})
@@ -531,8 +619,23 @@ func (a *Anthropic) convertRequest(req *ChatRequest) map[string]interface{} {
 		"max_tokens": 4096,
 	}
 
+	caching := a.promptCaching
+	if req.PromptCaching != nil {
+		caching = *req.PromptCaching
+	}
+
 	if systemPrompt != "" {
-		result["system"] = systemPrompt
+		if caching {
+			result["system"] = []map[string]interface{}{
+				{
+					"type":          "text",
+					"text":          systemPrompt,
+					"cache_control": ephemeralCache(),
+				},
+			}
+		} else {
+			result["system"] = systemPrompt
+		}
 	}
 
 	if req.MaxTokens != nil {
@@ -543,6 +646,14 @@ func (a *Anthropic) convertRequest(req *ChatRequest) map[string]interface{} {
 		result["temperature"] = *req.Temperature
 	}
 
+	if req.TopP != nil {
+		result["top_p"] = *req.TopP
+	}
+
+	if len(req.Stop) > 0 {
+		result["stop_sequences"] = req.Stop
+	}
+
 	if len(req.Tools) > 0 {
 		tools := make([]map[string]interface{}, len(req.Tools))
 		for i, t := range req.Tools {
@@ -561,12 +672,20 @@ func (a *Anthropic) convertRequest(req *ChatRequest) map[string]interface{} {
 				"input_schema": inputSchema,
 			}
 		}
+		// A breakpoint on the last tool caches the whole tool list
+		if caching {
+			tools[len(tools)-1]["cache_control"] = ephemeralCache()
+		}
 		result["tools"] = tools
 	}
 
 	return result
 }
 
+func ephemeralCache() map[string]interface{} {
+	return map[string]interface{}{"type": "ephemeral"}
+}
+
 func (a *Anthropic) convertResponse(resp *anthropicResponse, model string) *ChatResponse {
 	var content string
 	var toolCalls []ToolCall
@@ -591,10 +710,20 @@ func (a *Anthropic) convertResponse(resp *anthropicResponse, model string) *Chat
 		Model:        model,
 		Provider:     "anthropic",
 		FinishReason: resp.StopReason,
-		Usage: Usage{
-			PromptTokens:     resp.Usage.InputTokens,
-			CompletionTokens: resp.Usage.OutputTokens,
-			TotalTokens:      resp.Usage.InputTokens + resp.Usage.OutputTokens,
-		},
+		Usage: anthropicToUsage(resp.Usage),
+	}
+}
+
+// anthropicToUsage folds cached tokens into the prompt count. Anthropic
+// reports them separately from input_tokens, which only counts the uncached
+// part of the prompt.
+func anthropicToUsage(u anthropicUsage) Usage {
+	prompt := u.InputTokens + u.CacheCreationInputTokens + u.CacheReadInputTokens
+	return Usage{
+		PromptTokens:     prompt,
+		CompletionTokens: u.OutputTokens,
+		TotalTokens:      prompt + u.OutputTokens,
+		CacheReadTokens:  u.CacheReadInputTokens,
+		CacheWriteTokens: u.CacheCreationInputTokens,
 	}
 }
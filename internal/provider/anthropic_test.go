@@ -3,8 +3,10 @@ package provider
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -329,3 +331,236 @@ func TestAnthropic_SuccessfulRequestClearsRateLimit(t *testing.T) {
 		t.Error("Should not be rate limited after successful request")
 	}
 }
+
+func TestAnthropic_MaxConcurrency(t *testing.T) {
+	var inFlight, maxInFlight int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+				break
+			}
+		}
+
+		time.Sleep(20 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":          "msg_123",
+			"type":        "message",
+			"role":        "assistant",
+			"content":     []map[string]string{{"type": "text", "text": "OK"}},
+			"stop_reason": "end_turn",
+			"usage":       map[string]int{"input_tokens": 1, "output_tokens": 1},
+		})
+	}))
+	defer server.Close()
+
+	const limit = 2
+	provider := NewAnthropic("test-key", server.URL, WithMaxConcurrency(limit))
+
+	req := &ChatRequest{
+		Model:    "claude-3-5-haiku-20241022",
+		Messages: []Message{{Role: "user", Content: "Hello"}},
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := provider.Chat(context.Background(), req); err != nil {
+				t.Errorf("Expected success, got: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&maxInFlight); got > limit {
+		t.Errorf("Expected at most %d concurrent requests, got %d", limit, got)
+	}
+	if inUse, max := provider.Concurrency(); inUse != 0 || max != limit {
+		t.Errorf("Expected 0/%d slots in use after burst, got %d/%d", limit, inUse, max)
+	}
+}
+
+func TestAnthropic_MaxConcurrencyRespectsContext(t *testing.T) {
+	provider := NewAnthropic("test-key", "http://unused.invalid", WithMaxConcurrency(1))
+
+	// Hold the only slot so the next call has to wait
+	if err := provider.slots.acquire(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer provider.slots.release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := provider.Chat(ctx, &ChatRequest{Messages: []Message{{Role: "user", Content: "Hello"}}})
+	if err != context.DeadlineExceeded {
+		t.Errorf("Expected deadline exceeded while waiting for a slot, got: %v", err)
+	}
+}
+
+func TestAnthropic_PromptCaching(t *testing.T) {
+	var body map[string]interface{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body = nil
+		json.NewDecoder(r.Body).Decode(&body)
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":          "msg_123",
+			"type":        "message",
+			"role":        "assistant",
+			"content":     []map[string]string{{"type": "text", "text": "OK"}},
+			"stop_reason": "end_turn",
+			"usage": map[string]int{
+				"input_tokens":                20,
+				"output_tokens":               5,
+				"cache_creation_input_tokens": 100,
+				"cache_read_input_tokens":     900,
+			},
+		})
+	}))
+	defer server.Close()
+
+	req := &ChatRequest{
+		Model: "claude-3-5-haiku-20241022",
+		Messages: []Message{
+			{Role: "system", Content: "You are a helpful engineer."},
+			{Role: "user", Content: "Hello"},
+		},
+		Tools: []Tool{
+			{Name: "read_file", Description: "Read a file"},
+			{Name: "write_file", Description: "Write a file"},
+		},
+	}
+
+	provider := NewAnthropic("test-key", server.URL, WithPromptCaching(true))
+	resp, err := provider.Chat(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Chat failed: %v", err)
+	}
+
+	system, ok := body["system"].([]interface{})
+	if !ok || len(system) != 1 {
+		t.Fatalf("Expected system as a single content block, got %#v", body["system"])
+	}
+	block := system[0].(map[string]interface{})
+	if block["text"] != "You are a helpful engineer." || !isEphemeral(block["cache_control"]) {
+		t.Errorf("Expected cached system block, got %#v", block)
+	}
+
+	tools := body["tools"].([]interface{})
+	if _, marked := tools[0].(map[string]interface{})["cache_control"]; marked {
+		t.Error("Expected only the last tool to carry a cache breakpoint")
+	}
+	if !isEphemeral(tools[1].(map[string]interface{})["cache_control"]) {
+		t.Errorf("Expected cache breakpoint on last tool, got %#v", tools[1])
+	}
+
+	usage := resp.Usage
+	if usage.PromptTokens != 1020 || usage.TotalTokens != 1025 {
+		t.Errorf("Expected cached tokens counted in prompt, got %+v", usage)
+	}
+	if usage.CacheReadTokens != 900 || usage.CacheWriteTokens != 100 || usage.UncachedPromptTokens() != 20 {
+		t.Errorf("Expected cache read/write split, got %+v", usage)
+	}
+
+	// A request can opt out even when the provider caches by default
+	off := false
+	req.PromptCaching = &off
+	if _, err := provider.Chat(context.Background(), req); err != nil {
+		t.Fatalf("Chat failed: %v", err)
+	}
+	if _, ok := body["system"].(string); !ok {
+		t.Errorf("Expected plain system prompt with caching off, got %#v", body["system"])
+	}
+	if _, marked := body["tools"].([]interface{})[1].(map[string]interface{})["cache_control"]; marked {
+		t.Error("Expected no cache breakpoint with caching off")
+	}
+}
+
+func isEphemeral(v interface{}) bool {
+	cc, ok := v.(map[string]interface{})
+	return ok && cc["type"] == "ephemeral"
+}
+
+func TestAnthropic_UsesContextDeadline(t *testing.T) {
+	// The reply takes a while, as a long completion would
+	delay := 300 * time.Millisecond
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"content":     []map[string]string{{"type": "text", "text": "Finally done."}},
+			"stop_reason": "end_turn",
+			"usage":       map[string]int{"input_tokens": 10, "output_tokens": 5},
+		})
+	}))
+	defer server.Close()
+
+	provider := NewAnthropic("test-key", server.URL, WithAutoResume(false))
+
+	req := &ChatRequest{Model: "claude-3-5-haiku-20241022", Messages: []Message{{Role: "user", Content: "Hello"}}}
+
+	// A generous deadline lets the slow reply through
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	resp, err := provider.Chat(ctx, req)
+	if err != nil || resp.Content != "Finally done." {
+		t.Fatalf("Expected the slow reply within the deadline, got %v, %v", resp, err)
+	}
+
+	// A short one cancels the call
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := provider.Chat(ctx, req); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed >= delay {
+		t.Errorf("Expected the call to end at the deadline, took %v", elapsed)
+	}
+}
+
+func TestAnthropic_ImageToolResults(t *testing.T) {
+	a := NewAnthropic("test-key", "")
+	if !TakesImageToolResults(a) {
+		t.Error("Expected Anthropic to take images in tool results")
+	}
+
+	body := a.convertRequest(&ChatRequest{
+		Model: "claude-3-5-haiku-20241022",
+		Messages: []Message{
+			{Role: "user", Content: "Check the page"},
+			{Role: "tool", ToolCallID: "call-1", Content: "Login page", Images: []Image{{MediaType: "image/png", Data: "iVBORw=="}}},
+			{Role: "tool", ToolCallID: "call-2", Content: "plain"},
+		},
+	})
+	messages := body["messages"].([]map[string]interface{})
+
+	result := messages[1]["content"].([]map[string]interface{})[0]
+	blocks, ok := result["content"].([]map[string]interface{})
+	if !ok || len(blocks) != 2 {
+		t.Fatalf("Expected a text and an image block, got %#v", result["content"])
+	}
+	if blocks[0]["type"] != "text" || blocks[0]["text"] != "Login page" {
+		t.Errorf("Unexpected text block: %#v", blocks[0])
+	}
+	source := blocks[1]["source"].(map[string]interface{})
+	if blocks[1]["type"] != "image" || source["type"] != "base64" || source["media_type"] != "image/png" || source["data"] != "iVBORw==" {
+		t.Errorf("Unexpected image block: %#v", blocks[1])
+	}
+
+	// Text-only results are sent as a string, as before
+	if content := messages[2]["content"].([]map[string]interface{})[0]["content"]; content != "plain" {
+		t.Errorf("Expected a plain string result, got %#v", content)
+	}
+}
@@ -0,0 +1,217 @@
+package provider
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// Defaults for a response cache's options left at zero
+const (
+	DefaultCacheTTL            = 10 * time.Minute
+	DefaultCacheMaxEntries     = 500
+	DefaultCacheMaxTemperature = 0.3
+)
+
+// CacheOptions configures a ResponseCache
+type CacheOptions struct {
+	TTL        time.Duration // How long a response is reused
+	MaxEntries int           // Least recently used responses are evicted past this
+
+	// Requests sampled above this temperature, or left at the provider's
+	// default, aren't cached: the caller likely wants a fresh answer
+	MaxTemperature float64
+
+	// Cache every request, even with tools or a high temperature
+	Force bool
+}
+
+// CacheStats counts a provider's requests answered from the cache and the
+// cacheable ones that weren't
+type CacheStats struct {
+	Hits   int64 `json:"hits"`
+	Misses int64 `json:"misses"`
+}
+
+// ResponseCache returns a stored reply for a request identical to one made
+// recently, so dev and test loops don't pay for the same prompt twice. It's
+// opt-in: a repeated prompt usually wants the same answer only while
+// iterating. Requests with tools or a high temperature are passed through
+// unless Force is set, and streamed requests always are.
+type ResponseCache struct {
+	opts CacheOptions
+	now  func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*list.Element // key -> element holding *cacheEntry
+	lru     *list.List               // Most recently used first
+	stats   map[string]*CacheStats   // By provider ID
+}
+
+type cacheEntry struct {
+	key     string
+	resp    ChatResponse
+	expires time.Time
+}
+
+// NewResponseCache creates a response cache, filling in defaults for options
+// left at zero
+func NewResponseCache(opts CacheOptions) *ResponseCache {
+	if opts.TTL <= 0 {
+		opts.TTL = DefaultCacheTTL
+	}
+	if opts.MaxEntries <= 0 {
+		opts.MaxEntries = DefaultCacheMaxEntries
+	}
+	if opts.MaxTemperature == 0 {
+		opts.MaxTemperature = DefaultCacheMaxTemperature
+	}
+	return &ResponseCache{
+		opts:    opts,
+		now:     time.Now,
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+		stats:   make(map[string]*CacheStats),
+	}
+}
+
+// Wrap returns p with its Chat replies cached
+func (c *ResponseCache) Wrap(p Provider) Provider {
+	if cp, ok := p.(*cachingProvider); ok {
+		p = cp.Provider
+	}
+	return &cachingProvider{Provider: p, cache: c}
+}
+
+// Stats returns a provider's hit and miss counts
+func (c *ResponseCache) Stats(providerID string) CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if s, ok := c.stats[providerID]; ok {
+		return *s
+	}
+	return CacheStats{}
+}
+
+// cacheable reports whether a request's reply may be reused
+func (c *ResponseCache) cacheable(req *ChatRequest) bool {
+	if c.opts.Force {
+		return true
+	}
+	if len(req.Tools) > 0 {
+		return false
+	}
+	return req.Temperature != nil && *req.Temperature <= c.opts.MaxTemperature
+}
+
+// cacheKey hashes everything that shapes a reply: the provider, model,
+// messages, tools and sampling parameters
+func cacheKey(providerID string, req *ChatRequest) (string, error) {
+	data, err := json.Marshal(struct {
+		Provider string       `json:"provider"`
+		Request  *ChatRequest `json:"request"`
+	}{providerID, req})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// get returns a live cached reply and counts the lookup
+func (c *ResponseCache) get(providerID, key string) (*ChatResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := c.stats[providerID]
+	if stats == nil {
+		stats = &CacheStats{}
+		c.stats[providerID] = stats
+	}
+
+	if el, ok := c.entries[key]; ok {
+		entry := el.Value.(*cacheEntry)
+		if c.now().Before(entry.expires) {
+			c.lru.MoveToFront(el)
+			stats.Hits++
+			resp := entry.resp
+			resp.Usage = Usage{} // Nothing was spent on this reply
+			return &resp, true
+		}
+		c.lru.Remove(el)
+		delete(c.entries, key)
+	}
+	stats.Misses++
+	return nil, false
+}
+
+// put stores a reply, evicting the least recently used past MaxEntries
+func (c *ResponseCache) put(key string, resp *ChatResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := &cacheEntry{key: key, resp: *resp, expires: c.now().Add(c.opts.TTL)}
+	if el, ok := c.entries[key]; ok {
+		el.Value = entry
+		c.lru.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.lru.PushFront(entry)
+	for c.lru.Len() > c.opts.MaxEntries {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+// cachingProvider answers repeated requests from a ResponseCache
+type cachingProvider struct {
+	Provider
+	cache *ResponseCache
+}
+
+// Chat returns the cached reply for an identical cacheable request, or
+// calls the provider and caches its reply
+func (p *cachingProvider) Chat(ctx context.Context, req *ChatRequest) (*ChatResponse, error) {
+	if !p.cache.cacheable(req) {
+		return p.Provider.Chat(ctx, req)
+	}
+	key, err := cacheKey(p.ID(), req)
+	if err != nil {
+		return p.Provider.Chat(ctx, req)
+	}
+	if resp, ok := p.cache.get(p.ID(), key); ok {
+		return resp, nil
+	}
+
+	resp, err := p.Provider.Chat(ctx, req)
+	if err == nil && resp != nil {
+		p.cache.put(key, resp)
+	}
+	return resp, err
+}
+
+// Concurrency reports the wrapped provider's limit, if it has one
+func (p *cachingProvider) Concurrency() (inUse, limit int) {
+	if c, ok := p.Provider.(ConcurrencyReporter); ok {
+		return c.Concurrency()
+	}
+	return 0, 0
+}
+
+// ImageToolResults reports whether the wrapped provider takes images
+func (p *cachingProvider) ImageToolResults() bool {
+	return TakesImageToolResults(p.Provider)
+}
+
+// QueueState reports the wrapped provider's queue, if it has one
+func (p *cachingProvider) QueueState() (pending int, resumeIn time.Duration) {
+	if q, ok := p.Provider.(QueueReporter); ok {
+		return q.QueueState()
+	}
+	return 0, 0
+}
@@ -0,0 +1,113 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestResponseCache(t *testing.T) {
+	calls := 0
+	inner := &echoProvider{reply: func(req *ChatRequest) string {
+		calls++
+		return fmt.Sprintf("reply %d", calls)
+	}}
+	cache := NewResponseCache(CacheOptions{TTL: time.Minute})
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+	p := cache.Wrap(inner)
+
+	zero, hot := 0.0, 0.9
+	request := func(content string, temperature *float64, tools ...Tool) *ChatRequest {
+		return &ChatRequest{
+			Model:       "m",
+			Messages:    []Message{{Role: "user", Content: content}},
+			Tools:       tools,
+			Temperature: temperature,
+		}
+	}
+	chat := func(req *ChatRequest) string {
+		t.Helper()
+		resp, err := p.Chat(context.Background(), req)
+		if err != nil {
+			t.Fatalf("Chat failed: %v", err)
+		}
+		return resp.Content
+	}
+
+	// Miss, then a hit for the identical request
+	if got := chat(request("hello", &zero)); got != "reply 1" {
+		t.Fatalf("Expected the provider's reply, got %q", got)
+	}
+	if got := chat(request("hello", &zero)); got != "reply 1" {
+		t.Errorf("Expected the cached reply, got %q", got)
+	}
+	// A different prompt misses
+	if got := chat(request("goodbye", &zero)); got != "reply 2" {
+		t.Errorf("Expected a different prompt to miss, got %q", got)
+	}
+
+	// High temperature, no temperature and tools aren't cached at all
+	for _, req := range []*ChatRequest{
+		request("hello", &hot),
+		request("hello", nil),
+		request("hello", &zero, Tool{Name: "read_file"}),
+	} {
+		before := calls
+		chat(req)
+		chat(req)
+		if calls != before+2 {
+			t.Errorf("Expected %+v to bypass the cache", req)
+		}
+	}
+
+	// Once the TTL passes the provider is asked again
+	now = now.Add(2 * time.Minute)
+	if got := chat(request("hello", &zero)); got == "reply 1" {
+		t.Errorf("Expected an expired entry to miss, got %q", got)
+	}
+
+	if stats := cache.Stats("echo"); stats.Hits != 1 || stats.Misses != 3 {
+		t.Errorf("Expected 1 hit and 3 misses, got %+v", stats)
+	}
+}
+
+func TestResponseCache_ForceAndEviction(t *testing.T) {
+	calls := 0
+	inner := &echoProvider{reply: func(req *ChatRequest) string {
+		calls++
+		return req.Messages[0].Content
+	}}
+	p := NewResponseCache(CacheOptions{Force: true, MaxEntries: 1}).Wrap(inner)
+
+	req := func(content string) *ChatRequest {
+		return &ChatRequest{Messages: []Message{{Role: "user", Content: content}}, Tools: []Tool{{Name: "read_file"}}}
+	}
+	for _, content := range []string{"a", "a", "b", "a"} {
+		p.Chat(context.Background(), req(content))
+	}
+	// "a" is cached even with tools, then evicted by "b"
+	if calls != 3 {
+		t.Errorf("Expected 3 provider calls, got %d", calls)
+	}
+}
+
+func TestRegistry_ResponseCacheStats(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(&echoProvider{reply: func(*ChatRequest) string { return "ok" }})
+
+	if stats, _ := registry.Stats("echo"); stats.Cache != nil {
+		t.Errorf("Expected no cache stats with the cache off, got %+v", stats.Cache)
+	}
+
+	registry.SetResponseCache(NewResponseCache(CacheOptions{}))
+	zero := 0.0
+	p, _ := registry.Get("echo")
+	for i := 0; i < 3; i++ {
+		p.Chat(context.Background(), &ChatRequest{Messages: []Message{{Role: "user", Content: "hi"}}, Temperature: &zero})
+	}
+	if stats, _ := registry.Stats("echo"); stats.Cache == nil || stats.Cache.Hits != 2 || stats.Cache.Misses != 1 {
+		t.Errorf("Expected 2 hits and 1 miss, got %+v", stats.Cache)
+	}
+}
@@ -0,0 +1,371 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cassette modes
+const (
+	CassetteRecord = "record" // Call the provider and save each exchange
+	CassetteReplay = "replay" // Serve saved exchanges; never call the provider
+)
+
+// ErrCassetteMiss is returned in replay mode for a request the cassette has
+// no recorded response for
+var ErrCassetteMiss = errors.New("no recorded response")
+
+// cassetteVersion is the file format version written by record mode
+const cassetteVersion = 1
+
+// Cassette records provider exchanges to a file and replays them, so flows
+// that involve several members and model calls can be tested without API
+// keys and give the same answers every run. Requests are matched by a hash
+// of the provider ID and the request; a request made more than once replays
+// its responses in the order they were recorded.
+type Cassette struct {
+	path       string
+	mode       string
+	recordedAt time.Time
+
+	mu           sync.Mutex
+	interactions []cassetteInteraction
+	byKey        map[string][]int // key -> indexes into interactions
+	played       map[string]int   // key -> responses replayed so far
+}
+
+// cassetteInteraction is one saved exchange. The request is kept alongside
+// its key so cassettes can be read and diffed.
+type cassetteInteraction struct {
+	Key      string          `json:"key"`
+	Provider string          `json:"provider"`
+	Request  json.RawMessage `json:"request"`
+	Response json.RawMessage `json:"response"`
+}
+
+type cassetteFile struct {
+	Version      int                   `json:"version"`
+	RecordedAt   time.Time             `json:"recorded_at"`
+	Interactions []cassetteInteraction `json:"interactions"`
+}
+
+// OpenCassette opens a cassette in record or replay mode. Record mode starts
+// an empty cassette, replacing any file at path when the first exchange is
+// saved. Replay mode loads path, which must exist.
+func OpenCassette(path, mode string) (*Cassette, error) {
+	c := &Cassette{
+		path:   path,
+		mode:   mode,
+		byKey:  make(map[string][]int),
+		played: make(map[string]int),
+	}
+
+	switch mode {
+	case CassetteRecord:
+		c.recordedAt = time.Now().UTC().Truncate(time.Second)
+		return c, nil
+	case CassetteReplay:
+	default:
+		return nil, fmt.Errorf("unknown cassette mode %q (use %s or %s)", mode, CassetteRecord, CassetteReplay)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read cassette: %w", err)
+	}
+	var f cassetteFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("parse cassette %s: %w", path, err)
+	}
+	if f.Version != cassetteVersion {
+		return nil, fmt.Errorf("cassette %s has version %d, expected %d", path, f.Version, cassetteVersion)
+	}
+	for i, in := range f.Interactions {
+		c.byKey[in.Key] = append(c.byKey[in.Key], i)
+	}
+	c.interactions = f.Interactions
+	c.recordedAt = f.RecordedAt
+	return c, nil
+}
+
+// CassetteFromEnv opens the cassette named by UGUDU_CASSETTE, in the mode
+// set by UGUDU_CASSETTE_MODE (replay by default). It returns nil if
+// UGUDU_CASSETTE isn't set.
+func CassetteFromEnv() (*Cassette, error) {
+	path := os.Getenv("UGUDU_CASSETTE")
+	if path == "" {
+		return nil, nil
+	}
+	mode := os.Getenv("UGUDU_CASSETTE_MODE")
+	if mode == "" {
+		mode = CassetteReplay
+	}
+	return OpenCassette(path, mode)
+}
+
+// Mode returns CassetteRecord or CassetteReplay
+func (c *Cassette) Mode() string {
+	return c.mode
+}
+
+// RecordedAt returns when the cassette was recorded, or the zero time for
+// one recorded before this was saved. Members' prompts carry the date and
+// get_current_time answers with the time, so whoever records or replays a
+// cassette should fix the team's clock to it for requests to match.
+func (c *Cassette) RecordedAt() time.Time {
+	return c.recordedAt
+}
+
+// Providers returns the IDs of the providers with recorded exchanges
+func (c *Cassette) Providers() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	seen := make(map[string]bool)
+	var ids []string
+	for _, in := range c.interactions {
+		if !seen[in.Provider] {
+			seen[in.Provider] = true
+			ids = append(ids, in.Provider)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Wrap returns p with its exchanges recorded to or replayed from the cassette
+func (c *Cassette) Wrap(p Provider) Provider {
+	if cp, ok := p.(*cassetteProvider); ok {
+		p = cp.Provider
+	}
+	return &cassetteProvider{Provider: p, cassette: c}
+}
+
+// cassetteKey identifies a request to a provider
+func cassetteKey(providerID string, req *ChatRequest) (string, error) {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(append([]byte(providerID+"\n"), data...))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// replay returns the next recorded response for key. Once a key's responses
+// run out, the last one is repeated.
+func (c *Cassette) replay(key string) (*ChatResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	indexes := c.byKey[key]
+	if len(indexes) == 0 {
+		return nil, ErrCassetteMiss
+	}
+	n := c.played[key]
+	if n >= len(indexes) {
+		n = len(indexes) - 1
+	}
+	c.played[key]++
+
+	var resp ChatResponse
+	if err := json.Unmarshal(c.interactions[indexes[n]].Response, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// replayError explains a failed replay, pointing misses at record mode
+func (c *Cassette) replayError(providerID, key string, err error) error {
+	if errors.Is(err, ErrCassetteMiss) {
+		return fmt.Errorf("cassette %s: %w for %s request %s (record it again with UGUDU_CASSETTE_MODE=record)", c.path, err, providerID, key[:12])
+	}
+	return fmt.Errorf("cassette %s: %w", c.path, err)
+}
+
+// record saves an exchange and rewrites the cassette file, so a recording
+// cut short still keeps everything captured so far
+func (c *Cassette) record(providerID, key string, req *ChatRequest, resp *ChatResponse) error {
+	reqJSON, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	respJSON, err := json.Marshal(resp)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.byKey[key] = append(c.byKey[key], len(c.interactions))
+	c.interactions = append(c.interactions, cassetteInteraction{
+		Key:      key,
+		Provider: providerID,
+		Request:  reqJSON,
+		Response: respJSON,
+	})
+
+	data, err := json.MarshalIndent(cassetteFile{Version: cassetteVersion, RecordedAt: c.recordedAt, Interactions: c.interactions}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(c.path); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, c.path)
+}
+
+// cassetteProvider applies a Cassette to the provider it wraps
+type cassetteProvider struct {
+	Provider
+	cassette *Cassette
+}
+
+// Chat serves the request from the cassette in replay mode, or calls the
+// provider and records the exchange in record mode
+func (p *cassetteProvider) Chat(ctx context.Context, req *ChatRequest) (*ChatResponse, error) {
+	key, err := cassetteKey(p.ID(), req)
+	if err != nil {
+		return nil, fmt.Errorf("cassette: %w", err)
+	}
+
+	if p.cassette.mode == CassetteReplay {
+		resp, err := p.cassette.replay(key)
+		if err != nil {
+			return nil, p.cassette.replayError(p.ID(), key, err)
+		}
+		return resp, nil
+	}
+
+	resp, err := p.Provider.Chat(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.cassette.record(p.ID(), key, req, resp); err != nil {
+		return nil, fmt.Errorf("cassette: %w", err)
+	}
+	return resp, nil
+}
+
+// Stream replays a recorded response as a single chunk, or records the
+// streamed response once it completes
+func (p *cassetteProvider) Stream(ctx context.Context, req *ChatRequest) (<-chan StreamChunk, error) {
+	key, err := cassetteKey(p.ID(), req)
+	if err != nil {
+		return nil, fmt.Errorf("cassette: %w", err)
+	}
+
+	if p.cassette.mode == CassetteReplay {
+		resp, err := p.cassette.replay(key)
+		if err != nil {
+			return nil, p.cassette.replayError(p.ID(), key, err)
+		}
+		out := make(chan StreamChunk, 1)
+		out <- StreamChunk{Content: resp.Content, ToolCalls: resp.ToolCalls, Done: true, FinishReason: resp.FinishReason}
+		close(out)
+		return out, nil
+	}
+
+	chunks, err := p.Provider.Stream(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan StreamChunk)
+	go func() {
+		defer close(out)
+
+		var content strings.Builder
+		resp := &ChatResponse{Provider: p.ID(), Model: req.Model}
+		failed := false
+		for chunk := range chunks {
+			content.WriteString(chunk.Content)
+			resp.ToolCalls = append(resp.ToolCalls, chunk.ToolCalls...)
+			if chunk.FinishReason != "" {
+				resp.FinishReason = chunk.FinishReason
+			}
+			if chunk.Error != nil {
+				failed = true
+			}
+			select {
+			case out <- chunk:
+			case <-ctx.Done():
+				return
+			}
+		}
+		if failed {
+			return
+		}
+		resp.Content = content.String()
+		if err := p.cassette.record(p.ID(), key, req, resp); err != nil {
+			select {
+			case out <- StreamChunk{Done: true, Error: fmt.Errorf("cassette: %w", err)}:
+			case <-ctx.Done():
+			}
+		}
+	}()
+	return out, nil
+}
+
+// Concurrency reports the wrapped provider's limit, if it has one
+func (p *cassetteProvider) Concurrency() (inUse, limit int) {
+	if c, ok := p.Provider.(ConcurrencyReporter); ok {
+		return c.Concurrency()
+	}
+	return 0, 0
+}
+
+// ImageToolResults reports whether the wrapped provider takes images
+func (p *cassetteProvider) ImageToolResults() bool {
+	return TakesImageToolResults(p.Provider)
+}
+
+// QueueState reports the wrapped provider's queue, if it has one
+func (p *cassetteProvider) QueueState() (pending int, resumeIn time.Duration) {
+	if q, ok := p.Provider.(QueueReporter); ok {
+		return q.QueueState()
+	}
+	return 0, 0
+}
+
+// replayOnlyProvider stands in for a provider that has recorded exchanges
+// but isn't configured, so a cassette can be replayed without API keys.
+// Only its ID is used; the cassette answers every call.
+type replayOnlyProvider struct {
+	id string
+}
+
+func (p *replayOnlyProvider) ID() string   { return p.id }
+func (p *replayOnlyProvider) Name() string { return p.id + " (replay)" }
+
+func (p *replayOnlyProvider) Chat(context.Context, *ChatRequest) (*ChatResponse, error) {
+	return nil, fmt.Errorf("provider %s is only available from a cassette", p.id)
+}
+
+func (p *replayOnlyProvider) Stream(context.Context, *ChatRequest) (<-chan StreamChunk, error) {
+	return nil, fmt.Errorf("provider %s is only available from a cassette", p.id)
+}
+
+func (p *replayOnlyProvider) ListModels(context.Context) ([]ModelInfo, error) {
+	return nil, nil
+}
+
+func (p *replayOnlyProvider) Ping(context.Context) error {
+	return nil
+}
@@ -0,0 +1,128 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCassette_RecordThenReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassettes", "flow.json")
+	ctx := context.Background()
+	ask := func(content string) *ChatRequest {
+		return &ChatRequest{Model: "m", Messages: []Message{{Role: "user", Content: content}}}
+	}
+
+	calls := 0
+	inner := &echoProvider{reply: func(req *ChatRequest) string {
+		calls++
+		return strings.ToUpper(req.Messages[0].Content) + strings.Repeat("!", calls)
+	}}
+
+	recorder, err := OpenCassette(path, CassetteRecord)
+	if err != nil {
+		t.Fatalf("OpenCassette failed: %v", err)
+	}
+	p := recorder.Wrap(inner)
+	for _, content := range []string{"hello", "bye", "hello"} {
+		if _, err := p.Chat(ctx, ask(content)); err != nil {
+			t.Fatalf("Chat failed: %v", err)
+		}
+	}
+
+	player, err := OpenCassette(path, CassetteReplay)
+	if err != nil {
+		t.Fatalf("OpenCassette failed: %v", err)
+	}
+	if at := player.RecordedAt(); at.IsZero() || !at.Equal(recorder.RecordedAt()) {
+		t.Errorf("Expected the recording time %v to be saved, got %v", recorder.RecordedAt(), at)
+	}
+	p = player.Wrap(inner)
+	calls = 0
+
+	// A repeated request replays its responses in recorded order
+	for _, want := range []struct{ ask, reply string }{
+		{"bye", "BYE!!"},
+		{"hello", "HELLO!"},
+		{"hello", "HELLO!!!"},
+		{"hello", "HELLO!!!"},
+	} {
+		resp, err := p.Chat(ctx, ask(want.ask))
+		if err != nil {
+			t.Fatalf("Replay of %q failed: %v", want.ask, err)
+		}
+		if resp.Content != want.reply {
+			t.Errorf("Expected %q for %q, got %q", want.reply, want.ask, resp.Content)
+		}
+	}
+	if calls != 0 {
+		t.Errorf("Expected replay not to call the provider, got %d calls", calls)
+	}
+
+	if _, err := p.Chat(ctx, ask("something new")); !errors.Is(err, ErrCassetteMiss) {
+		t.Errorf("Expected a cassette miss, got %v", err)
+	}
+
+	chunks, err := p.Stream(ctx, ask("bye"))
+	if err != nil {
+		t.Fatalf("Stream replay failed: %v", err)
+	}
+	if c := <-chunks; c.Content != "BYE!!" || !c.Done {
+		t.Errorf("Expected the recorded reply as one chunk, got %+v", c)
+	}
+}
+
+func TestCassette_Invalid(t *testing.T) {
+	if _, err := OpenCassette(filepath.Join(t.TempDir(), "missing.json"), CassetteReplay); err == nil {
+		t.Error("Expected an error replaying a missing cassette")
+	}
+	if _, err := OpenCassette("x.json", "rewind"); err == nil {
+		t.Error("Expected an error for an unknown mode")
+	}
+}
+
+func TestRegistry_SetCassette(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "flow.json")
+	req := &ChatRequest{Messages: []Message{{Role: "user", Content: "mail bob@example.com"}}}
+
+	// Record through a redactor: the cassette only ever sees placeholders
+	registry := NewRegistry()
+	registry.Register(&echoProvider{reply: func(req *ChatRequest) string { return "sent to " + req.Messages[0].Content[5:] }})
+	redactor, _ := NewRedactor([]string{RedactEmail}, nil, true)
+	registry.SetRedactor(redactor)
+	recorder, _ := OpenCassette(path, CassetteRecord)
+	registry.SetCassette(recorder)
+
+	p, _ := registry.Get("echo")
+	resp, err := p.Chat(context.Background(), req)
+	if err != nil || resp.Content != "sent to bob@example.com" {
+		t.Fatalf("Expected the restored reply, got %v, %v", resp, err)
+	}
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), "bob@example.com") || !strings.Contains(string(data), "[EMAIL_1]") {
+		t.Errorf("Expected the cassette to hold redacted content, got %s", data)
+	}
+
+	// Replay needs no configured provider
+	registry = NewRegistry()
+	registry.SetRedactor(redactor)
+	player, _ := OpenCassette(path, CassetteReplay)
+	registry.SetCassette(player)
+
+	p, err = registry.Get("echo")
+	if err != nil {
+		t.Fatalf("Expected a stand-in for the recorded provider: %v", err)
+	}
+	resp, err = p.Chat(context.Background(), req)
+	if err != nil || resp.Content != "sent to bob@example.com" {
+		t.Errorf("Expected the replayed reply, got %v, %v", resp, err)
+	}
+
+	registry.SetCassette(nil)
+	if registry.Has("echo") {
+		t.Error("Expected the stand-in to be removed with the cassette")
+	}
+}
@@ -0,0 +1,52 @@
+package provider
+
+import "context"
+
+// ConcurrencyReporter is implemented by providers that cap how many requests
+// they send at once
+type ConcurrencyReporter interface {
+	Concurrency() (inUse, limit int)
+}
+
+// concurrencyLimit is a counting semaphore guarding outbound API calls. A nil
+// limit allows everything through, so callers needn't check whether one is
+// configured.
+type concurrencyLimit struct {
+	slots chan struct{}
+}
+
+func newConcurrencyLimit(n int) *concurrencyLimit {
+	if n <= 0 {
+		return nil
+	}
+	return &concurrencyLimit{slots: make(chan struct{}, n)}
+}
+
+// acquire blocks until a slot is free or ctx is done
+func (l *concurrencyLimit) acquire(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// release frees a slot taken by acquire
+func (l *concurrencyLimit) release() {
+	if l == nil {
+		return
+	}
+	<-l.slots
+}
+
+// usage reports the slots in use and the total available
+func (l *concurrencyLimit) usage() (inUse, limit int) {
+	if l == nil {
+		return 0, 0
+	}
+	return len(l.slots), cap(l.slots)
+}
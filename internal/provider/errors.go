@@ -0,0 +1,125 @@
+package provider
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// AuthError indicates a provider rejected the configured credentials
+type AuthError struct {
+	Provider   string
+	StatusCode int
+	Message    string
+}
+
+func (e *AuthError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("%s rejected the API key (HTTP %d): %s", e.Provider, e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("%s rejected the API key (HTTP %d)", e.Provider, e.StatusCode)
+}
+
+// IsAuthError reports whether err is (or wraps) an AuthError
+func IsAuthError(err error) bool {
+	var authErr *AuthError
+	return errors.As(err, &authErr)
+}
+
+// ErrProviderNotFound is returned when no provider is registered under an ID
+var ErrProviderNotFound = errors.New("provider not found")
+
+// providerSetup is how each provider Ugudu ships with is configured: an
+// environment variable and the matching key in the config file
+var providerSetup = map[string]struct{ env, config string }{
+	"anthropic":  {"ANTHROPIC_API_KEY", "providers.anthropic.api_key"},
+	"openai":     {"OPENAI_API_KEY", "providers.openai.api_key"},
+	"groq":       {"GROQ_API_KEY", "providers.groq.api_key"},
+	"openrouter": {"OPENROUTER_API_KEY", "providers.openrouter.api_key"},
+	"ollama":     {"OLLAMA_URL", "providers.ollama.url"},
+}
+
+// NotFoundError says which provider is missing, which ones are configured
+// and, when it's one Ugudu ships with, how to configure it
+type NotFoundError struct {
+	ID        string
+	Available []string // Configured provider IDs, sorted
+	EnvVar    string   // Variable that enables ID; empty for unknown providers
+	ConfigKey string   // Config file key that enables ID
+}
+
+func (e *NotFoundError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "provider not found: %s", e.ID)
+	if len(e.Available) > 0 {
+		fmt.Fprintf(&b, " (configured: %s)", strings.Join(e.Available, ", "))
+	} else {
+		b.WriteString(" (no providers are configured)")
+	}
+	if e.EnvVar != "" {
+		fmt.Fprintf(&b, "; set %s or %s in ~/.ugudu/config.yaml and restart the daemon", e.EnvVar, e.ConfigKey)
+	} else {
+		fmt.Fprintf(&b, "; supported providers are %s", strings.Join(knownProviders(), ", "))
+	}
+	return b.String()
+}
+
+func (e *NotFoundError) Unwrap() error { return ErrProviderNotFound }
+
+// newNotFoundError describes a missing provider given the configured ones
+func newNotFoundError(id string, available []string) *NotFoundError {
+	sort.Strings(available)
+	e := &NotFoundError{ID: id, Available: available}
+	if setup, ok := providerSetup[id]; ok {
+		e.EnvVar, e.ConfigKey = setup.env, setup.config
+	}
+	return e
+}
+
+func knownProviders() []string {
+	ids := make([]string, 0, len(providerSetup))
+	for id := range providerSetup {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// isAuthStatus reports whether an HTTP status means the credentials were rejected
+func isAuthStatus(statusCode int) bool {
+	return statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden
+}
+
+// newAuthError builds an AuthError, pulling the message out of the provider's error body
+func newAuthError(providerName string, statusCode int, body []byte) *AuthError {
+	return &AuthError{
+		Provider:   providerName,
+		StatusCode: statusCode,
+		Message:    errorMessage(body),
+	}
+}
+
+// errorMessage extracts a human-readable message from a provider error body.
+// Providers use either {"error": {"message": "..."}} or {"error": "..."}.
+func errorMessage(body []byte) string {
+	var nested struct {
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &nested); err == nil && nested.Error.Message != "" {
+		return nested.Error.Message
+	}
+
+	var flat struct {
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(body, &flat); err == nil && flat.Error != "" {
+		return flat.Error
+	}
+
+	return ""
+}
@@ -0,0 +1,92 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAuthErrorFromProviders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error": map[string]string{
+				"type":    "authentication_error",
+				"message": "invalid x-api-key",
+			},
+		})
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		provider Provider
+	}{
+		{"anthropic", NewAnthropic("bad-key", server.URL, WithAutoResume(false))},
+		{"openai", NewOpenAI("bad-key", server.URL)},
+	}
+
+	req := &ChatRequest{
+		Model:    "test-model",
+		Messages: []Message{{Role: "user", Content: "Hello"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.provider.Chat(context.Background(), req)
+			if err == nil {
+				t.Fatal("Expected auth error")
+			}
+			if !IsAuthError(err) {
+				t.Fatalf("Expected AuthError, got %T: %v", err, err)
+			}
+
+			var authErr *AuthError
+			errors.As(err, &authErr)
+			if authErr.StatusCode != http.StatusUnauthorized {
+				t.Errorf("Expected status 401, got %d", authErr.StatusCode)
+			}
+			if authErr.Message != "invalid x-api-key" {
+				t.Errorf("Expected parsed message, got '%s'", authErr.Message)
+			}
+			if strings.Contains(err.Error(), "{") {
+				t.Errorf("Error should not contain raw JSON: %s", err.Error())
+			}
+
+			// Ping reports the same typed error
+			if err := tt.provider.Ping(context.Background()); !IsAuthError(err) {
+				t.Errorf("Expected Ping to return AuthError, got %v", err)
+			}
+		})
+	}
+}
+
+func TestRegistryRecordResult(t *testing.T) {
+	reg := NewRegistry()
+	reg.Register(&mockProvider{id: "test", name: "Test"})
+
+	if h := reg.Health("test"); h.Status != HealthUnknown {
+		t.Errorf("Expected unknown health, got %s", h.Status)
+	}
+
+	reg.RecordResult("test", &AuthError{Provider: "Test", StatusCode: 401})
+	h := reg.Health("test")
+	if h.Status != HealthUnhealthy || h.Reason != "bad credentials" {
+		t.Errorf("Expected unhealthy/bad credentials, got %s/%s", h.Status, h.Reason)
+	}
+
+	// Transient errors don't change health
+	reg.RecordResult("test", errors.New("connection reset"))
+	if h := reg.Health("test"); h.Status != HealthUnhealthy {
+		t.Errorf("Expected health to stay unhealthy, got %s", h.Status)
+	}
+
+	reg.RecordResult("test", nil)
+	if h := reg.Health("test"); h.Status != HealthOK {
+		t.Errorf("Expected ok health after success, got %s", h.Status)
+	}
+}
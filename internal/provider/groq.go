@@ -13,15 +13,20 @@ const groqAPIURL = "https://api.groq.com/openai/v1"
 
 // Groq implements the Provider interface for Groq's API (OpenAI-compatible)
 type Groq struct {
-	apiKey string
-	client *http.Client
+	apiKey  string
+	baseURL string
+	client  *http.Client
 }
 
 // NewGroq creates a new Groq provider
-func NewGroq(apiKey string) *Groq {
+func NewGroq(apiKey, baseURL string) *Groq {
+	if baseURL == "" {
+		baseURL = groqAPIURL
+	}
 	return &Groq{
-		apiKey: apiKey,
-		client: &http.Client{},
+		apiKey:  apiKey,
+		baseURL: baseURL,
+		client:  &http.Client{},
 	}
 }
 
@@ -36,7 +41,7 @@ func (g *Groq) Chat(ctx context.Context, req *ChatRequest) (*ChatResponse, error
 		return nil, fmt.Errorf("marshal request: %w", err)
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", groqAPIURL+"/chat/completions", bytes.NewReader(body))
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", g.baseURL+"/chat/completions", bytes.NewReader(body))
 	if err != nil {
 		return nil, fmt.Errorf("create request: %w", err)
 	}
@@ -52,6 +57,9 @@ func (g *Groq) Chat(ctx context.Context, req *ChatRequest) (*ChatResponse, error
 
 	if resp.StatusCode != http.StatusOK {
 		bodyBytes, _ := io.ReadAll(resp.Body)
+		if isAuthStatus(resp.StatusCode) {
+			return nil, newAuthError(g.Name(), resp.StatusCode, bodyBytes)
+		}
 		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(bodyBytes))
 	}
 
@@ -75,7 +83,7 @@ func (g *Groq) Stream(ctx context.Context, req *ChatRequest) (<-chan StreamChunk
 		return ch, fmt.Errorf("marshal request: %w", err)
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", groqAPIURL+"/chat/completions", bytes.NewReader(body))
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", g.baseURL+"/chat/completions", bytes.NewReader(body))
 	if err != nil {
 		close(ch)
 		return ch, fmt.Errorf("create request: %w", err)
@@ -96,6 +104,10 @@ func (g *Groq) Stream(ctx context.Context, req *ChatRequest) (<-chan StreamChunk
 
 		if resp.StatusCode != http.StatusOK {
 			bodyBytes, _ := io.ReadAll(resp.Body)
+			if isAuthStatus(resp.StatusCode) {
+				ch <- StreamChunk{Error: newAuthError(g.Name(), resp.StatusCode, bodyBytes)}
+				return
+			}
 			ch <- StreamChunk{Error: fmt.Errorf("API error %d: %s", resp.StatusCode, string(bodyBytes))}
 			return
 		}
@@ -139,10 +151,10 @@ func (g *Groq) Stream(ctx context.Context, req *ChatRequest) (<-chan StreamChunk
 
 func (g *Groq) ListModels(ctx context.Context) ([]ModelInfo, error) {
 	return []ModelInfo{
-		{ID: "llama-3.3-70b-versatile", Name: "Llama 3.3 70B", Provider: "groq", MaxTokens: 32768},
-		{ID: "llama-3.1-8b-instant", Name: "Llama 3.1 8B", Provider: "groq", MaxTokens: 8192},
-		{ID: "mixtral-8x7b-32768", Name: "Mixtral 8x7B", Provider: "groq", MaxTokens: 32768},
-		{ID: "gemma2-9b-it", Name: "Gemma 2 9B", Provider: "groq", MaxTokens: 8192},
+		{ID: "llama-3.3-70b-versatile", Name: "Llama 3.3 70B", Provider: "groq", MaxTokens: 131072, MaxOutputTokens: 32768},
+		{ID: "llama-3.1-8b-instant", Name: "Llama 3.1 8B", Provider: "groq", MaxTokens: 131072, MaxOutputTokens: 8192},
+		{ID: "mixtral-8x7b-32768", Name: "Mixtral 8x7B", Provider: "groq", MaxTokens: 32768, MaxOutputTokens: 32768},
+		{ID: "gemma2-9b-it", Name: "Gemma 2 9B", Provider: "groq", MaxTokens: 8192, MaxOutputTokens: 8192},
 	}, nil
 }
 
@@ -215,6 +227,10 @@ func (g *Groq) convertRequest(req *ChatRequest) map[string]interface{} {
 		result["temperature"] = *req.Temperature
 	}
 
+	if req.TopP != nil {
+		result["top_p"] = *req.TopP
+	}
+
 	if len(req.Stop) > 0 {
 		result["stop"] = req.Stop
 	}
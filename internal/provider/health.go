@@ -0,0 +1,86 @@
+package provider
+
+import (
+	"context"
+	"time"
+)
+
+// Health check defaults
+const (
+	// DefaultHealthCheckInterval is how often MonitorHealth pings providers
+	DefaultHealthCheckInterval = time.Minute
+
+	// UnhealthyAfter is how many health checks in a row must fail before a
+	// provider is marked unhealthy. Rejected credentials mark it at once.
+	UnhealthyAfter = 3
+
+	healthCheckTimeout = 10 * time.Second
+)
+
+// Healthy reports whether a provider can be used. Providers that haven't
+// been checked yet count as healthy.
+func (r *Registry) Healthy(id string) bool {
+	return r.Health(id).Status != HealthUnhealthy
+}
+
+// Check pings a provider and updates its health. A provider is marked
+// unhealthy after UnhealthyAfter failed checks in a row, and healthy again
+// as soon as one passes.
+func (r *Registry) Check(ctx context.Context, id string) (Health, error) {
+	p, err := r.Get(id)
+	if err != nil {
+		return Health{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+	pingErr := p.Ping(ctx)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	h := r.health[id]
+	h.CheckedAt = time.Now()
+	switch {
+	case pingErr == nil:
+		h = Health{Status: HealthOK, CheckedAt: h.CheckedAt}
+	case IsAuthError(pingErr):
+		h.Failures++
+		h.Status, h.Reason = HealthUnhealthy, "bad credentials"
+	default:
+		h.Failures++
+		if h.Failures >= UnhealthyAfter {
+			h.Status, h.Reason = HealthUnhealthy, pingErr.Error()
+		} else if h.Status == "" {
+			h.Status = HealthUnknown
+		}
+	}
+	r.health[id] = h
+	return h, pingErr
+}
+
+// MonitorHealth checks every registered provider each interval until ctx is
+// done. onChange, if set, is called when a provider's status changes.
+func (r *Registry) MonitorHealth(ctx context.Context, interval time.Duration, onChange func(id string, h Health)) {
+	if interval <= 0 {
+		interval = DefaultHealthCheckInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		for _, p := range r.List() {
+			before := r.Health(p.ID()).Status
+			h, _ := r.Check(ctx, p.ID())
+			if onChange != nil && h.Status != before {
+				onChange(p.ID(), h)
+			}
+		}
+	}
+}
@@ -53,6 +53,9 @@ func (o *Ollama) Chat(ctx context.Context, req *ChatRequest) (*ChatResponse, err
 
 	if resp.StatusCode != http.StatusOK {
 		bodyBytes, _ := io.ReadAll(resp.Body)
+		if isAuthStatus(resp.StatusCode) {
+			return nil, newAuthError(o.Name(), resp.StatusCode, bodyBytes)
+		}
 		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(bodyBytes))
 	}
 
@@ -96,6 +99,10 @@ func (o *Ollama) Stream(ctx context.Context, req *ChatRequest) (<-chan StreamChu
 
 		if resp.StatusCode != http.StatusOK {
 			bodyBytes, _ := io.ReadAll(resp.Body)
+			if isAuthStatus(resp.StatusCode) {
+				ch <- StreamChunk{Error: newAuthError(o.Name(), resp.StatusCode, bodyBytes)}
+				return
+			}
 			ch <- StreamChunk{Error: fmt.Errorf("API error %d: %s", resp.StatusCode, string(bodyBytes))}
 			return
 		}
@@ -223,10 +230,21 @@ func (o *Ollama) convertRequest(req *ChatRequest) map[string]interface{} {
 		"messages": messages,
 	}
 
+	options := make(map[string]interface{})
 	if req.Temperature != nil {
-		result["options"] = map[string]interface{}{
-			"temperature": *req.Temperature,
-		}
+		options["temperature"] = *req.Temperature
+	}
+	if req.TopP != nil {
+		options["top_p"] = *req.TopP
+	}
+	if req.MaxTokens != nil {
+		options["num_predict"] = *req.MaxTokens
+	}
+	if len(req.Stop) > 0 {
+		options["stop"] = req.Stop
+	}
+	if len(options) > 0 {
+		result["options"] = options
 	}
 
 	return result
@@ -57,6 +57,9 @@ func (o *OpenAI) Chat(ctx context.Context, req *ChatRequest) (*ChatResponse, err
 
 	if resp.StatusCode != http.StatusOK {
 		bodyBytes, _ := io.ReadAll(resp.Body)
+		if isAuthStatus(resp.StatusCode) {
+			return nil, newAuthError(o.Name(), resp.StatusCode, bodyBytes)
+		}
 		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(bodyBytes))
 	}
 
@@ -101,6 +104,10 @@ func (o *OpenAI) Stream(ctx context.Context, req *ChatRequest) (<-chan StreamChu
 
 		if resp.StatusCode != http.StatusOK {
 			bodyBytes, _ := io.ReadAll(resp.Body)
+			if isAuthStatus(resp.StatusCode) {
+				ch <- StreamChunk{Error: newAuthError(o.Name(), resp.StatusCode, bodyBytes)}
+				return
+			}
 			ch <- StreamChunk{Error: fmt.Errorf("API error %d: %s", resp.StatusCode, string(bodyBytes))}
 			return
 		}
@@ -147,11 +154,11 @@ func (o *OpenAI) Stream(ctx context.Context, req *ChatRequest) (<-chan StreamChu
 
 func (o *OpenAI) ListModels(ctx context.Context) ([]ModelInfo, error) {
 	return []ModelInfo{
-		{ID: "gpt-4o", Name: "GPT-4o", Provider: "openai", MaxTokens: 128000},
-		{ID: "gpt-4o-mini", Name: "GPT-4o Mini", Provider: "openai", MaxTokens: 128000},
-		{ID: "gpt-4-turbo", Name: "GPT-4 Turbo", Provider: "openai", MaxTokens: 128000},
-		{ID: "o1", Name: "o1", Provider: "openai", MaxTokens: 200000},
-		{ID: "o1-mini", Name: "o1 Mini", Provider: "openai", MaxTokens: 128000},
+		{ID: "gpt-4o", Name: "GPT-4o", Provider: "openai", MaxTokens: 128000, MaxOutputTokens: 16384},
+		{ID: "gpt-4o-mini", Name: "GPT-4o Mini", Provider: "openai", MaxTokens: 128000, MaxOutputTokens: 16384},
+		{ID: "gpt-4-turbo", Name: "GPT-4 Turbo", Provider: "openai", MaxTokens: 128000, MaxOutputTokens: 4096},
+		{ID: "o1", Name: "o1", Provider: "openai", MaxTokens: 200000, MaxOutputTokens: 100000},
+		{ID: "o1-mini", Name: "o1 Mini", Provider: "openai", MaxTokens: 128000, MaxOutputTokens: 65536},
 	}, nil
 }
 
@@ -234,6 +241,10 @@ func (o *OpenAI) convertRequest(req *ChatRequest) map[string]interface{} {
 		result["temperature"] = *req.Temperature
 	}
 
+	if req.TopP != nil {
+		result["top_p"] = *req.TopP
+	}
+
 	if len(req.Stop) > 0 {
 		result["stop"] = req.Stop
 	}
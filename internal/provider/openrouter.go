@@ -9,7 +9,10 @@ import (
 	"net/http"
 )
 
-const openrouterAPIURL = "https://openrouter.ai/api/v1"
+const (
+	openrouterAPIURL  = "https://openrouter.ai/api/v1"
+	openrouterSiteURL = "https://github.com/arcslash/ugudu"
+)
 
 // OpenRouter implements the Provider interface for OpenRouter's API
 // OpenRouter provides access to many models including Claude, GPT, Gemini, Mistral, DeepSeek, etc.
@@ -19,20 +22,82 @@ type OpenRouter struct {
 	client   *http.Client
 	siteName string // For OpenRouter attribution
 	siteURL  string // For OpenRouter attribution
+
+	// Routing applied to every request unless the request overrides it
+	routing *OpenRouterRouting
+}
+
+// OpenRouterRouting maps to the "provider" object in an OpenRouter request,
+// which controls which upstream providers serve a model
+type OpenRouterRouting struct {
+	// Upstream providers to try, in order (e.g. ["Anthropic", "Together"])
+	Order []string `yaml:"order,omitempty" json:"order,omitempty"`
+
+	// Whether OpenRouter may use providers outside Order when they fail
+	AllowFallbacks *bool `yaml:"allow_fallbacks,omitempty" json:"allow_fallbacks,omitempty"`
+
+	// "deny" restricts routing to providers that don't store or train on prompts
+	DataCollection string `yaml:"data_collection,omitempty" json:"data_collection,omitempty"`
+}
+
+// merge overlays the fields set on override onto r
+func (r *OpenRouterRouting) merge(override *OpenRouterRouting) *OpenRouterRouting {
+	if r == nil {
+		return override
+	}
+	if override == nil {
+		return r
+	}
+
+	out := *r
+	if len(override.Order) > 0 {
+		out.Order = override.Order
+	}
+	if override.AllowFallbacks != nil {
+		out.AllowFallbacks = override.AllowFallbacks
+	}
+	if override.DataCollection != "" {
+		out.DataCollection = override.DataCollection
+	}
+	return &out
+}
+
+func (r *OpenRouterRouting) isZero() bool {
+	return r == nil || (len(r.Order) == 0 && r.AllowFallbacks == nil && r.DataCollection == "")
+}
+
+// OpenRouterOption configures the OpenRouter provider
+type OpenRouterOption func(*OpenRouter)
+
+// WithOpenRouterRouting sets the default provider routing for every request
+func WithOpenRouterRouting(routing OpenRouterRouting) OpenRouterOption {
+	return func(o *OpenRouter) {
+		o.routing = &routing
+	}
 }
 
 // NewOpenRouter creates a new OpenRouter provider
-func NewOpenRouter(apiKey, siteName, siteURL string) *OpenRouter {
+func NewOpenRouter(apiKey, baseURL, siteName, siteURL string, opts ...OpenRouterOption) *OpenRouter {
+	if baseURL == "" {
+		baseURL = openrouterAPIURL
+	}
 	if siteName == "" {
 		siteName = "Ugudu"
 	}
-	return &OpenRouter{
+	if siteURL == "" {
+		siteURL = openrouterSiteURL
+	}
+	o := &OpenRouter{
 		apiKey:   apiKey,
-		baseURL:  openrouterAPIURL,
+		baseURL:  baseURL,
 		client:   &http.Client{},
 		siteName: siteName,
 		siteURL:  siteURL,
 	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
 }
 
 func (o *OpenRouter) ID() string   { return "openrouter" }
@@ -53,8 +118,7 @@ func (o *OpenRouter) Chat(ctx context.Context, req *ChatRequest) (*ChatResponse,
 
 	httpReq.Header.Set("Content-Type", "application/json")
 	httpReq.Header.Set("Authorization", "Bearer "+o.apiKey)
-	httpReq.Header.Set("HTTP-Referer", o.siteURL)
-	httpReq.Header.Set("X-Title", o.siteName)
+	o.setAttribution(httpReq)
 
 	resp, err := o.client.Do(httpReq)
 	if err != nil {
@@ -64,15 +128,18 @@ func (o *OpenRouter) Chat(ctx context.Context, req *ChatRequest) (*ChatResponse,
 
 	if resp.StatusCode != http.StatusOK {
 		bodyBytes, _ := io.ReadAll(resp.Body)
+		if isAuthStatus(resp.StatusCode) {
+			return nil, newAuthError(o.Name(), resp.StatusCode, bodyBytes)
+		}
 		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(bodyBytes))
 	}
 
-	var openaiResp openaiResponse
-	if err := json.NewDecoder(resp.Body).Decode(&openaiResp); err != nil {
+	var openrouterResp openrouterResponse
+	if err := json.NewDecoder(resp.Body).Decode(&openrouterResp); err != nil {
 		return nil, fmt.Errorf("decode response: %w", err)
 	}
 
-	return o.convertResponse(&openaiResp), nil
+	return o.convertResponse(&openrouterResp, req.Model), nil
 }
 
 func (o *OpenRouter) Stream(ctx context.Context, req *ChatRequest) (<-chan StreamChunk, error) {
@@ -95,8 +162,7 @@ func (o *OpenRouter) Stream(ctx context.Context, req *ChatRequest) (<-chan Strea
 
 	httpReq.Header.Set("Content-Type", "application/json")
 	httpReq.Header.Set("Authorization", "Bearer "+o.apiKey)
-	httpReq.Header.Set("HTTP-Referer", o.siteURL)
-	httpReq.Header.Set("X-Title", o.siteName)
+	o.setAttribution(httpReq)
 
 	go func() {
 		defer close(ch)
@@ -110,6 +176,10 @@ func (o *OpenRouter) Stream(ctx context.Context, req *ChatRequest) (<-chan Strea
 
 		if resp.StatusCode != http.StatusOK {
 			bodyBytes, _ := io.ReadAll(resp.Body)
+			if isAuthStatus(resp.StatusCode) {
+				ch <- StreamChunk{Error: newAuthError(o.Name(), resp.StatusCode, bodyBytes)}
+				return
+			}
 			ch <- StreamChunk{Error: fmt.Errorf("API error %d: %s", resp.StatusCode, string(bodyBytes))}
 			return
 		}
@@ -194,6 +264,12 @@ func (o *OpenRouter) Ping(ctx context.Context) error {
 	return err
 }
 
+// setAttribution adds the headers OpenRouter uses to credit requests to an app
+func (o *OpenRouter) setAttribution(req *http.Request) {
+	req.Header.Set("HTTP-Referer", o.siteURL)
+	req.Header.Set("X-Title", o.siteName)
+}
+
 func (o *OpenRouter) convertRequest(req *ChatRequest) map[string]interface{} {
 	messages := make([]map[string]interface{}, len(req.Messages))
 	for i, msg := range req.Messages {
@@ -240,10 +316,18 @@ func (o *OpenRouter) convertRequest(req *ChatRequest) map[string]interface{} {
 		result["temperature"] = *req.Temperature
 	}
 
+	if req.TopP != nil {
+		result["top_p"] = *req.TopP
+	}
+
 	if len(req.Stop) > 0 {
 		result["stop"] = req.Stop
 	}
 
+	if routing := o.routing.merge(req.OpenRouter); !routing.isZero() {
+		result["provider"] = routing
+	}
+
 	if len(req.Tools) > 0 {
 		tools := make([]map[string]interface{}, len(req.Tools))
 		for i, t := range req.Tools {
@@ -270,9 +354,22 @@ func (o *OpenRouter) convertRequest(req *ChatRequest) map[string]interface{} {
 	return result
 }
 
-func (o *OpenRouter) convertResponse(resp *openaiResponse) *ChatResponse {
+// openrouterResponse is an OpenAI-style response plus the upstream provider
+// that served it. Model is the model that actually ran, which can differ from
+// the one requested when routing falls back.
+type openrouterResponse struct {
+	openaiResponse
+	Provider string `json:"provider"`
+}
+
+func (o *OpenRouter) convertResponse(resp *openrouterResponse, requested string) *ChatResponse {
+	model := resp.Model
+	if model == "" {
+		model = requested
+	}
+
 	if len(resp.Choices) == 0 {
-		return &ChatResponse{Provider: "openrouter", Model: resp.Model}
+		return &ChatResponse{Provider: "openrouter", Model: model, ServedBy: resp.Provider}
 	}
 
 	choice := resp.Choices[0]
@@ -288,8 +385,9 @@ func (o *OpenRouter) convertResponse(resp *openaiResponse) *ChatResponse {
 	return &ChatResponse{
 		Content:      choice.Message.Content,
 		ToolCalls:    toolCalls,
-		Model:        resp.Model,
+		Model:        model,
 		Provider:     "openrouter",
+		ServedBy:     resp.Provider,
 		FinishReason: choice.FinishReason,
 		Usage: Usage{
 			PromptTokens:     resp.Usage.PromptTokens,
@@ -0,0 +1,138 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestOpenRouter_RoutingRequestBody(t *testing.T) {
+	deny := false
+	allow := true
+
+	tests := []struct {
+		name     string
+		defaults *OpenRouterRouting
+		request  *OpenRouterRouting
+		want     map[string]interface{} // nil means no "provider" key
+	}{
+		{
+			name: "no routing",
+		},
+		{
+			name:     "provider defaults",
+			defaults: &OpenRouterRouting{Order: []string{"Anthropic", "Together"}, AllowFallbacks: &deny},
+			want: map[string]interface{}{
+				"order":           []interface{}{"Anthropic", "Together"},
+				"allow_fallbacks": false,
+			},
+		},
+		{
+			name:    "request only",
+			request: &OpenRouterRouting{DataCollection: "deny"},
+			want:    map[string]interface{}{"data_collection": "deny"},
+		},
+		{
+			name:     "request overrides defaults field by field",
+			defaults: &OpenRouterRouting{Order: []string{"Anthropic"}, AllowFallbacks: &deny, DataCollection: "deny"},
+			request:  &OpenRouterRouting{Order: []string{"Together"}, AllowFallbacks: &allow},
+			want: map[string]interface{}{
+				"order":           []interface{}{"Together"},
+				"allow_fallbacks": true,
+				"data_collection": "deny",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var opts []OpenRouterOption
+			if tt.defaults != nil {
+				opts = append(opts, WithOpenRouterRouting(*tt.defaults))
+			}
+			o := NewOpenRouter("test-key", "", "", "", opts...)
+
+			body := o.convertRequest(&ChatRequest{
+				Model:      "anthropic/claude-3.5-sonnet",
+				Messages:   []Message{{Role: "user", Content: "Hello"}},
+				OpenRouter: tt.request,
+			})
+
+			// Round-trip through JSON to compare what's actually sent
+			raw, _ := json.Marshal(body)
+			var sent map[string]interface{}
+			json.Unmarshal(raw, &sent)
+
+			got, ok := sent["provider"]
+			if tt.want == nil {
+				if ok {
+					t.Errorf("Expected no provider routing, got %v", got)
+				}
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected provider %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestOpenRouter_ServedModelAndAttribution(t *testing.T) {
+	var headers http.Header
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header.Clone()
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":       "gen-123",
+			"model":    "anthropic/claude-3-haiku",
+			"provider": "Anthropic",
+			"choices": []map[string]interface{}{
+				{"message": map[string]string{"role": "assistant", "content": "OK"}, "finish_reason": "stop"},
+			},
+			"usage": map[string]int{"prompt_tokens": 3, "completion_tokens": 1, "total_tokens": 4},
+		})
+	}))
+	defer server.Close()
+
+	o := NewOpenRouter("test-key", server.URL, "", "")
+
+	resp, err := o.Chat(context.Background(), &ChatRequest{
+		Model:    "anthropic/claude-3.5-sonnet",
+		Messages: []Message{{Role: "user", Content: "Hello"}},
+	})
+	if err != nil {
+		t.Fatalf("Chat failed: %v", err)
+	}
+
+	if resp.Model != "anthropic/claude-3-haiku" || resp.ServedBy != "Anthropic" {
+		t.Errorf("Expected served model and upstream provider, got %q from %q", resp.Model, resp.ServedBy)
+	}
+	if resp.Usage.TotalTokens != 4 {
+		t.Errorf("Expected usage to carry through, got %+v", resp.Usage)
+	}
+
+	if headers.Get("X-Title") != "Ugudu" || headers.Get("HTTP-Referer") != openrouterSiteURL {
+		t.Errorf("Expected default attribution headers, got X-Title=%q HTTP-Referer=%q",
+			headers.Get("X-Title"), headers.Get("HTTP-Referer"))
+	}
+}
+
+func TestOpenRouterRoutingFromEnv(t *testing.T) {
+	t.Setenv("OPENROUTER_PROVIDER_ORDER", "Anthropic, Together,")
+	t.Setenv("OPENROUTER_ALLOW_FALLBACKS", "false")
+	t.Setenv("OPENROUTER_DATA_COLLECTION", "deny")
+
+	routing := openRouterRoutingFromEnv()
+	if !reflect.DeepEqual(routing.Order, []string{"Anthropic", "Together"}) {
+		t.Errorf("Expected trimmed provider order, got %v", routing.Order)
+	}
+	if routing.AllowFallbacks == nil || *routing.AllowFallbacks {
+		t.Errorf("Expected allow_fallbacks false, got %v", routing.AllowFallbacks)
+	}
+	if routing.DataCollection != "deny" {
+		t.Errorf("Expected data_collection deny, got %q", routing.DataCollection)
+	}
+}
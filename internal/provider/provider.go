@@ -3,8 +3,14 @@ package provider
 
 import (
 	"context"
+	"time"
 )
 
+// DefaultRequestTimeout bounds a model call whose context has no deadline of
+// its own. Providers don't set an HTTP client timeout; the context is the
+// only limit, so a caller with a longer deadline gets the full wait.
+const DefaultRequestTimeout = 10 * time.Minute
+
 // Provider is the interface that all LLM providers must implement
 type Provider interface {
 	// ID returns the unique identifier for this provider
@@ -33,7 +39,15 @@ type ChatRequest struct {
 	Tools       []Tool    `json:"tools,omitempty"`
 	Temperature *float64  `json:"temperature,omitempty"`
 	MaxTokens   *int      `json:"max_tokens,omitempty"`
+	TopP        *float64  `json:"top_p,omitempty"`
 	Stop        []string  `json:"stop,omitempty"`
+
+	// Ask providers that support it to cache the system prompt and tools;
+	// nil leaves it to the provider's default
+	PromptCaching *bool `json:"prompt_caching,omitempty"`
+
+	// Upstream provider routing for OpenRouter; ignored by other providers
+	OpenRouter *OpenRouterRouting `json:"openrouter,omitempty"`
 }
 
 // Message represents a chat message
@@ -42,6 +56,29 @@ type Message struct {
 	Content    string     `json:"content"`
 	ToolCalls  []ToolCall `json:"tool_calls,omitempty"`
 	ToolCallID string     `json:"tool_call_id,omitempty"`
+
+	// Images a tool returned, on a tool message. Only providers that are
+	// ImageToolResulters are sent them.
+	Images []Image `json:"images,omitempty"`
+}
+
+// Image is an image in a message
+type Image struct {
+	MediaType string `json:"media_type"` // e.g. image/png
+	Data      string `json:"data"`       // Base64
+}
+
+// ImageToolResulter is implemented by providers that can show a model the
+// images a tool returned
+type ImageToolResulter interface {
+	ImageToolResults() bool
+}
+
+// TakesImageToolResults reports whether p can show a model the images a tool
+// returned
+func TakesImageToolResults(p Provider) bool {
+	i, ok := p.(ImageToolResulter)
+	return ok && i.ImageToolResults()
 }
 
 // Tool represents a tool/function that can be called
@@ -66,6 +103,9 @@ type ChatResponse struct {
 	Provider     string     `json:"provider"`
 	Usage        Usage      `json:"usage"`
 	FinishReason string     `json:"finish_reason"`
+
+	// Upstream provider that served the request, for routers like OpenRouter
+	ServedBy string `json:"served_by,omitempty"`
 }
 
 // StreamChunk represents a chunk of streamed response
@@ -82,15 +122,26 @@ type Usage struct {
 	PromptTokens     int `json:"prompt_tokens"`
 	CompletionTokens int `json:"completion_tokens"`
 	TotalTokens      int `json:"total_tokens"`
+
+	// Portions of PromptTokens served from or written to a prompt cache.
+	// Cache reads are billed at a fraction of the normal input rate.
+	CacheReadTokens  int `json:"cache_read_tokens,omitempty"`
+	CacheWriteTokens int `json:"cache_write_tokens,omitempty"`
+}
+
+// UncachedPromptTokens returns the prompt tokens billed at the full input rate
+func (u Usage) UncachedPromptTokens() int {
+	return u.PromptTokens - u.CacheReadTokens - u.CacheWriteTokens
 }
 
 // ModelInfo represents information about a model
 type ModelInfo struct {
-	ID          string `json:"id"`
-	Name        string `json:"name"`
-	Provider    string `json:"provider"`
-	MaxTokens   int    `json:"max_tokens,omitempty"`
-	Description string `json:"description,omitempty"`
+	ID              string `json:"id"`
+	Name            string `json:"name"`
+	Provider        string `json:"provider"`
+	MaxTokens       int    `json:"max_tokens,omitempty"`        // Context window
+	MaxOutputTokens int    `json:"max_output_tokens,omitempty"` // Most max_tokens a request may ask for
+	Description     string `json:"description,omitempty"`
 }
 
 // Config holds provider configuration
@@ -100,3 +151,27 @@ type Config struct {
 	APIKey   string `yaml:"api_key,omitempty"`
 	BaseURL  string `yaml:"base_url,omitempty"`
 }
+
+// MaxTokensLimit returns the most max_tokens model takes as models list it,
+// or 0 if it isn't listed with an output limit
+func MaxTokensLimit(models []ModelInfo, model string) int {
+	for _, m := range models {
+		if m.ID == model {
+			return m.MaxOutputTokens
+		}
+	}
+	return 0
+}
+
+// ClampMaxTokens lowers req.MaxTokens to limit when it asks for more, and
+// returns what it asked for, or 0 if it was left alone. A limit of 0 means
+// none is known. The pointer is replaced rather than written through, since
+// it's often shared with the config it came from.
+func ClampMaxTokens(req *ChatRequest, limit int) int {
+	if limit <= 0 || req.MaxTokens == nil || *req.MaxTokens <= limit {
+		return 0
+	}
+	asked := *req.MaxTokens
+	req.MaxTokens = &limit
+	return asked
+}
@@ -0,0 +1,73 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestProviders_NoClientTimeout(t *testing.T) {
+	// Calls are bounded by their context; a client timeout would cut off
+	// long completions whatever deadline the caller set
+	tests := []struct {
+		name   string
+		client *http.Client
+	}{
+		{"anthropic", NewAnthropic("key", "").client},
+		{"openai", NewOpenAI("key", "").client},
+		{"groq", NewGroq("key", "").client},
+		{"ollama", NewOllama("").client},
+		{"openrouter", NewOpenRouter("key", "", "", "").client},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.client.Timeout != 0 {
+				t.Errorf("Expected no client timeout, got %v", tt.client.Timeout)
+			}
+		})
+	}
+}
+
+func TestClampMaxTokens(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name  string
+		p     Provider
+		model string
+		asked int
+		want  int
+	}{
+		{"anthropic over limit", NewAnthropic("key", ""), "claude-sonnet-4-20250514", 100000, 64000},
+		{"anthropic within limit", NewAnthropic("key", ""), "claude-sonnet-4-20250514", 32000, 32000},
+		{"groq over limit", NewGroq("key", ""), "llama-3.1-8b-instant", 100000, 8192},
+		{"openai over limit", NewOpenAI("key", ""), "gpt-4o", 32000, 16384},
+		{"context window isn't an output limit", NewOpenRouter("key", "", "", ""), "openai/gpt-4o", 100000, 100000},
+		{"unknown model", NewAnthropic("key", ""), "claude-next", 100000, 100000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			models, err := tt.p.ListModels(ctx)
+			if err != nil {
+				t.Fatalf("ListModels failed: %v", err)
+			}
+			configured := tt.asked
+			req := &ChatRequest{Model: tt.model, MaxTokens: &configured}
+
+			asked := ClampMaxTokens(req, MaxTokensLimit(models, tt.model))
+			if *req.MaxTokens != tt.want {
+				t.Errorf("Expected max_tokens %d, got %d", tt.want, *req.MaxTokens)
+			}
+			wantAsked := 0
+			if tt.want != tt.asked {
+				wantAsked = tt.asked
+			}
+			if asked != wantAsked {
+				t.Errorf("Expected ClampMaxTokens to return %d, got %d", wantAsked, asked)
+			}
+			if configured != tt.asked {
+				t.Errorf("Expected the configured value to be left alone, got %d", configured)
+			}
+		})
+	}
+}
@@ -0,0 +1,278 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Built-in redaction patterns
+const (
+	RedactEmail = "email"
+	RedactCard  = "card"
+	RedactSSN   = "ssn"
+)
+
+// BuiltinRedactions lists the built-in patterns in the order they apply
+var BuiltinRedactions = []string{RedactEmail, RedactCard, RedactSSN}
+
+var builtinPatterns = map[string]string{
+	RedactEmail: `[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`,
+	RedactCard:  `\b(?:\d[ -]?){12,18}\d\b`,
+	RedactSSN:   `\b\d{3}-\d{2}-\d{4}\b`,
+}
+
+// maxPlaceholderLen bounds how much streamed text is held back waiting for
+// the rest of a placeholder split across chunks
+const maxPlaceholderLen = 64
+
+// RedactionPattern is a named regular expression whose matches are redacted
+type RedactionPattern struct {
+	Name    string `yaml:"name" json:"name"`
+	Pattern string `yaml:"pattern" json:"pattern"`
+}
+
+type redactionRule struct {
+	label string // Placeholder prefix, e.g. EMAIL
+	re    *regexp.Regexp
+	valid func(string) bool // Optional check that a match is really sensitive
+}
+
+// Redactor replaces sensitive values in outgoing messages with placeholders
+// such as [EMAIL_1] before they reach a provider. With restore on, the
+// placeholders are swapped back in the provider's reply, so members and
+// tools see the real values while the provider never does.
+type Redactor struct {
+	rules   []redactionRule
+	restore bool
+}
+
+// NewRedactor builds a redactor from built-in pattern names and custom
+// patterns
+func NewRedactor(builtins []string, custom []RedactionPattern, restore bool) (*Redactor, error) {
+	r := &Redactor{restore: restore}
+
+	for _, name := range builtins {
+		pattern, ok := builtinPatterns[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown redaction pattern %q (built-in patterns: %s)", name, strings.Join(BuiltinRedactions, ", "))
+		}
+		rule := redactionRule{label: strings.ToUpper(name), re: regexp.MustCompile(pattern)}
+		if name == RedactCard {
+			rule.valid = luhnValid
+		}
+		r.rules = append(r.rules, rule)
+	}
+
+	for _, p := range custom {
+		if p.Name == "" {
+			return nil, fmt.Errorf("redaction pattern %q needs a name", p.Pattern)
+		}
+		re, err := regexp.Compile(p.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("redaction pattern %s: %w", p.Name, err)
+		}
+		r.rules = append(r.rules, redactionRule{label: strings.ToUpper(p.Name), re: re})
+	}
+
+	return r, nil
+}
+
+// Wrap returns p with redaction applied to every request it sends
+func (r *Redactor) Wrap(p Provider) Provider {
+	if rp, ok := p.(*redactingProvider); ok {
+		p = rp.Provider
+	}
+	return &redactingProvider{Provider: p, redactor: r}
+}
+
+// redaction tracks the placeholders handed out for one request, so a value
+// repeated across messages gets the same placeholder and can be restored
+type redaction struct {
+	rules []redactionRule
+
+	mu       sync.Mutex
+	values   map[string]string // value -> placeholder
+	counts   map[string]int    // placeholders handed out per label
+	pairs    []string          // placeholder, value, ... for restoring
+	restorer *strings.Replacer // Built from pairs on first restore
+}
+
+func (r *Redactor) newRedaction() *redaction {
+	return &redaction{
+		rules:  r.rules,
+		values: make(map[string]string),
+		counts: make(map[string]int),
+	}
+}
+
+// redact replaces every sensitive value in text with its placeholder
+func (s *redaction) redact(text string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, rule := range s.rules {
+		text = rule.re.ReplaceAllStringFunc(text, func(match string) string {
+			if rule.valid != nil && !rule.valid(match) {
+				return match
+			}
+			if placeholder, ok := s.values[match]; ok {
+				return placeholder
+			}
+			s.counts[rule.label]++
+			placeholder := fmt.Sprintf("[%s_%d]", rule.label, s.counts[rule.label])
+			s.values[match] = placeholder
+			s.pairs = append(s.pairs, placeholder, match)
+			s.restorer = nil
+			return placeholder
+		})
+	}
+	return text
+}
+
+// restore swaps placeholders in text back to the values they replaced
+func (s *redaction) restore(text string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.pairs) == 0 {
+		return text
+	}
+	if s.restorer == nil {
+		s.restorer = strings.NewReplacer(s.pairs...)
+	}
+	return s.restorer.Replace(text)
+}
+
+func (s *redaction) redactRequest(req *ChatRequest) *ChatRequest {
+	redacted := *req
+	redacted.Messages = make([]Message, len(req.Messages))
+	for i, msg := range req.Messages {
+		msg.Content = s.redact(msg.Content)
+		msg.ToolCalls = s.mapToolCalls(msg.ToolCalls, s.redact)
+		redacted.Messages[i] = msg
+	}
+	return &redacted
+}
+
+func (s *redaction) mapToolCalls(calls []ToolCall, fn func(string) string) []ToolCall {
+	if len(calls) == 0 {
+		return calls
+	}
+	mapped := make([]ToolCall, len(calls))
+	for i, tc := range calls {
+		tc.Arguments = fn(tc.Arguments)
+		mapped[i] = tc
+	}
+	return mapped
+}
+
+// redactingProvider applies a Redactor to the provider it wraps
+type redactingProvider struct {
+	Provider
+	redactor *Redactor
+}
+
+// ImageToolResults reports whether the wrapped provider takes images
+func (p *redactingProvider) ImageToolResults() bool {
+	return TakesImageToolResults(p.Provider)
+}
+
+// Chat redacts the request and, if configured, restores the reply
+func (p *redactingProvider) Chat(ctx context.Context, req *ChatRequest) (*ChatResponse, error) {
+	s := p.redactor.newRedaction()
+	resp, err := p.Provider.Chat(ctx, s.redactRequest(req))
+	if err != nil || resp == nil || !p.redactor.restore {
+		return resp, err
+	}
+
+	resp.Content = s.restore(resp.Content)
+	resp.ToolCalls = s.mapToolCalls(resp.ToolCalls, s.restore)
+	return resp, nil
+}
+
+// Stream redacts the request and, if configured, restores streamed chunks.
+// Text that may be the start of a placeholder is held back until the chunk
+// that completes it arrives.
+func (p *redactingProvider) Stream(ctx context.Context, req *ChatRequest) (<-chan StreamChunk, error) {
+	s := p.redactor.newRedaction()
+	chunks, err := p.Provider.Stream(ctx, s.redactRequest(req))
+	if err != nil || !p.redactor.restore {
+		return chunks, err
+	}
+
+	out := make(chan StreamChunk)
+	go func() {
+		defer close(out)
+
+		send := func(chunk StreamChunk) bool {
+			select {
+			case out <- chunk:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		var held string
+		for chunk := range chunks {
+			text := held + chunk.Content
+			held = ""
+			if !chunk.Done {
+				if i := strings.LastIndex(text, "["); i >= 0 && !strings.Contains(text[i:], "]") && len(text)-i <= maxPlaceholderLen {
+					text, held = text[:i], text[i:]
+				}
+			}
+			chunk.Content = s.restore(text)
+			chunk.ToolCalls = s.mapToolCalls(chunk.ToolCalls, s.restore)
+			if !send(chunk) {
+				return
+			}
+		}
+		if held != "" {
+			send(StreamChunk{Content: s.restore(held)})
+		}
+	}()
+	return out, nil
+}
+
+// Concurrency reports the wrapped provider's limit, if it has one
+func (p *redactingProvider) Concurrency() (inUse, limit int) {
+	if c, ok := p.Provider.(ConcurrencyReporter); ok {
+		return c.Concurrency()
+	}
+	return 0, 0
+}
+
+// QueueState reports the wrapped provider's queue, if it has one
+func (p *redactingProvider) QueueState() (pending int, resumeIn time.Duration) {
+	if q, ok := p.Provider.(QueueReporter); ok {
+		return q.QueueState()
+	}
+	return 0, 0
+}
+
+// luhnValid reports whether the digits in s pass the Luhn checksum used by
+// card numbers, which keeps order numbers and timestamps from being redacted
+func luhnValid(s string) bool {
+	sum, n := 0, 0
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if n%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		n++
+	}
+	return n >= 13 && sum%10 == 0
+}
@@ -0,0 +1,184 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// echoProvider records the request it was sent and replies with canned chunks
+type echoProvider struct {
+	got    *ChatRequest
+	reply  func(req *ChatRequest) string
+	chunks []string
+}
+
+func (p *echoProvider) ID() string                                      { return "echo" }
+func (p *echoProvider) Name() string                                    { return "Echo" }
+func (p *echoProvider) Ping(context.Context) error                      { return nil }
+func (p *echoProvider) ListModels(context.Context) ([]ModelInfo, error) { return nil, nil }
+func (p *echoProvider) Chat(_ context.Context, req *ChatRequest) (*ChatResponse, error) {
+	p.got = req
+	return &ChatResponse{Content: p.reply(req)}, nil
+}
+func (p *echoProvider) Stream(_ context.Context, req *ChatRequest) (<-chan StreamChunk, error) {
+	p.got = req
+	ch := make(chan StreamChunk, len(p.chunks)+1)
+	for _, c := range p.chunks {
+		ch <- StreamChunk{Content: c}
+	}
+	ch <- StreamChunk{Done: true}
+	close(ch)
+	return ch, nil
+}
+
+func TestRedactor_BuiltinPatterns(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "email",
+			in:   "Contact jane.doe+billing@example.co.uk today",
+			want: "Contact [EMAIL_1] today",
+		},
+		{
+			name: "card with spaces",
+			in:   "Card 4111 1111 1111 1111 was declined",
+			want: "Card [CARD_1] was declined",
+		},
+		{
+			name: "card digits only",
+			in:   "Card 5500005555555559 on file",
+			want: "Card [CARD_1] on file",
+		},
+		{
+			name: "long number failing Luhn is kept",
+			in:   "Order 1234567890123456 shipped",
+			want: "Order 1234567890123456 shipped",
+		},
+		{
+			name: "ssn",
+			in:   "SSN 123-45-6789 on record",
+			want: "SSN [SSN_1] on record",
+		},
+		{
+			name: "repeated values share a placeholder",
+			in:   "a@example.com, b@example.com, a@example.com",
+			want: "[EMAIL_1], [EMAIL_2], [EMAIL_1]",
+		},
+	}
+
+	r, err := NewRedactor(BuiltinRedactions, nil, false)
+	if err != nil {
+		t.Fatalf("NewRedactor failed: %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := r.newRedaction().redact(tt.in); got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRedactor_InvalidConfig(t *testing.T) {
+	if _, err := NewRedactor([]string{"phone"}, nil, false); err == nil {
+		t.Error("Expected an error for an unknown built-in pattern")
+	}
+	if _, err := NewRedactor(nil, []RedactionPattern{{Name: "bad", Pattern: "("}}, false); err == nil {
+		t.Error("Expected an error for an invalid custom pattern")
+	}
+}
+
+func TestRedactor_ChatRoundTrip(t *testing.T) {
+	r, err := NewRedactor(BuiltinRedactions, []RedactionPattern{{Name: "employee_id", Pattern: `EMP-\d{5}`}}, true)
+	if err != nil {
+		t.Fatalf("NewRedactor failed: %v", err)
+	}
+
+	inner := &echoProvider{reply: func(req *ChatRequest) string {
+		// The model refers to the values by placeholder
+		return "Emailed [EMAIL_1] about [EMPLOYEE_ID_1]."
+	}}
+	p := r.Wrap(inner)
+
+	req := &ChatRequest{Messages: []Message{
+		{Role: "system", Content: "You are support."},
+		{Role: "user", Content: "Email bob@example.com about EMP-12345, SSN 123-45-6789"},
+	}}
+	resp, err := p.Chat(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Chat failed: %v", err)
+	}
+
+	sent := inner.got.Messages[1].Content
+	for _, secret := range []string{"bob@example.com", "EMP-12345", "123-45-6789"} {
+		if strings.Contains(sent, secret) {
+			t.Errorf("Expected %q to be redacted, provider got %q", secret, sent)
+		}
+	}
+	if sent != "Email [EMAIL_1] about [EMPLOYEE_ID_1], SSN [SSN_1]" {
+		t.Errorf("Unexpected redacted request %q", sent)
+	}
+	if req.Messages[1].Content != "Email bob@example.com about EMP-12345, SSN 123-45-6789" {
+		t.Errorf("Expected the caller's request to be left alone, got %q", req.Messages[1].Content)
+	}
+	if resp.Content != "Emailed bob@example.com about EMP-12345." {
+		t.Errorf("Expected placeholders restored in the reply, got %q", resp.Content)
+	}
+}
+
+func TestRedactor_PassesImageSupportThrough(t *testing.T) {
+	r, _ := NewRedactor(BuiltinRedactions, nil, false)
+	if !TakesImageToolResults(r.Wrap(NewAnthropic("test-key", ""))) {
+		t.Error("Expected a redacted Anthropic provider to still take images in tool results")
+	}
+	if TakesImageToolResults(r.Wrap(&echoProvider{})) {
+		t.Error("Expected a redacted provider without image support not to take them")
+	}
+}
+
+func TestRedactor_StreamRestoresSplitPlaceholders(t *testing.T) {
+	r, _ := NewRedactor([]string{RedactEmail}, nil, true)
+	inner := &echoProvider{chunks: []string{"Sent to [EM", "AIL_1] and [", "done]"}}
+	p := r.Wrap(inner)
+
+	chunks, err := p.Stream(context.Background(), &ChatRequest{Messages: []Message{
+		{Role: "user", Content: "Send it to bob@example.com"},
+	}})
+	if err != nil {
+		t.Fatalf("Stream failed: %v", err)
+	}
+
+	var sb strings.Builder
+	for c := range chunks {
+		sb.WriteString(c.Content)
+	}
+	if got := sb.String(); got != "Sent to bob@example.com and [done]" {
+		t.Errorf("Expected restored stream, got %q", got)
+	}
+}
+
+func TestRegistry_SetRedactor(t *testing.T) {
+	registry := NewRegistry()
+	inner := &echoProvider{reply: func(req *ChatRequest) string { return req.Messages[0].Content }}
+	registry.Register(inner)
+
+	r, _ := NewRedactor(BuiltinRedactions, nil, false)
+	registry.SetRedactor(r)
+
+	p, _ := registry.Get("echo")
+	resp, _ := p.Chat(context.Background(), &ChatRequest{Messages: []Message{{Role: "user", Content: "hi bob@example.com"}}})
+	if resp.Content != "hi [EMAIL_1]" {
+		t.Errorf("Expected providers registered earlier to be redacted, got %q", resp.Content)
+	}
+
+	registry.SetRedactor(nil)
+	p, _ = registry.Get("echo")
+	if p != Provider(inner) {
+		t.Errorf("Expected redaction to be removed, got %T", p)
+	}
+}
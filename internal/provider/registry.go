@@ -1,14 +1,37 @@
 package provider
 
 import (
-	"fmt"
-	"os"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
+
+	"github.com/arcslash/ugudu/internal/config"
+)
+
+// Provider health states
+const (
+	HealthUnknown   = "unknown"
+	HealthOK        = "ok"
+	HealthUnhealthy = "unhealthy"
 )
 
+// Health is the last known state of a provider
+type Health struct {
+	Status    string    `json:"status"`
+	Reason    string    `json:"reason,omitempty"`
+	CheckedAt time.Time `json:"checked_at,omitempty"`
+	Failures  int       `json:"failures,omitempty"` // Health checks failed in a row
+}
+
 // Registry manages available providers
 type Registry struct {
 	providers map[string]Provider
+	health    map[string]Health
+	calls     map[string][]callRecord // Recent calls, for Stats
+	redactor  *Redactor               // Applied to every registered provider when set
+	cassette  *Cassette               // Records or replays every registered provider when set
+	cache     *ResponseCache          // Answers repeated requests for every registered provider when set
 	mu        sync.RWMutex
 }
 
@@ -16,6 +39,8 @@ type Registry struct {
 func NewRegistry() *Registry {
 	return &Registry{
 		providers: make(map[string]Provider),
+		health:    make(map[string]Health),
+		calls:     make(map[string][]callRecord),
 	}
 }
 
@@ -23,7 +48,86 @@ func NewRegistry() *Registry {
 func (r *Registry) Register(p Provider) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	r.providers[p.ID()] = p
+	r.providers[p.ID()] = r.decorate(bare(p))
+}
+
+// SetRedactor redacts sensitive content from every request sent through the
+// registry's providers, including ones registered later. nil turns redaction
+// off.
+func (r *Registry) SetRedactor(redactor *Redactor) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.redactor = redactor
+	for id, p := range r.providers {
+		r.providers[id] = r.decorate(bare(p))
+	}
+}
+
+// SetCassette records or replays every request sent through the registry's
+// providers, including ones registered later. In replay mode, providers the
+// cassette has exchanges for are registered even if they aren't configured,
+// so no API keys are needed. nil turns it off.
+func (r *Registry) SetCassette(cassette *Cassette) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cassette = cassette
+	for id, p := range r.providers {
+		p = bare(p)
+		if _, standIn := p.(*replayOnlyProvider); standIn && (cassette == nil || cassette.Mode() != CassetteReplay) {
+			delete(r.providers, id)
+			continue
+		}
+		r.providers[id] = r.decorate(p)
+	}
+
+	if cassette != nil && cassette.Mode() == CassetteReplay {
+		for _, id := range cassette.Providers() {
+			if _, ok := r.providers[id]; !ok {
+				r.providers[id] = r.decorate(&replayOnlyProvider{id: id})
+			}
+		}
+	}
+}
+
+// SetResponseCache answers repeated requests sent through the registry's
+// providers from cache, including ones registered later. nil turns it off.
+func (r *Registry) SetResponseCache(cache *ResponseCache) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cache = cache
+	for id, p := range r.providers {
+		r.providers[id] = r.decorate(bare(p))
+	}
+}
+
+// decorate wraps a bare provider in the registry's cassette, response cache
+// and redactor. The redactor goes outside so neither holds unredacted
+// content.
+func (r *Registry) decorate(p Provider) Provider {
+	if r.cassette != nil {
+		p = r.cassette.Wrap(p)
+	}
+	if r.cache != nil {
+		p = r.cache.Wrap(p)
+	}
+	if r.redactor != nil {
+		p = r.redactor.Wrap(p)
+	}
+	return p
+}
+
+// bare strips the wrappers added by decorate
+func bare(p Provider) Provider {
+	if rp, ok := p.(*redactingProvider); ok {
+		p = rp.Provider
+	}
+	if cp, ok := p.(*cachingProvider); ok {
+		p = cp.Provider
+	}
+	if cp, ok := p.(*cassetteProvider); ok {
+		p = cp.Provider
+	}
+	return p
 }
 
 // Get returns a provider by ID
@@ -34,7 +138,11 @@ func (r *Registry) Get(id string) (Provider, error) {
 	if p, ok := r.providers[id]; ok {
 		return p, nil
 	}
-	return nil, fmt.Errorf("provider not found: %s", id)
+	available := make([]string, 0, len(r.providers))
+	for pid := range r.providers {
+		available = append(available, pid)
+	}
+	return nil, newNotFoundError(id, available)
 }
 
 // List returns all registered providers
@@ -57,34 +165,121 @@ func (r *Registry) Has(id string) bool {
 	return ok
 }
 
-// AutoDiscover registers providers based on environment variables
+// Health returns the last known health of a provider
+func (r *Registry) Health(id string) Health {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if h, ok := r.health[id]; ok {
+		return h
+	}
+	return Health{Status: HealthUnknown}
+}
+
+// RecordResult updates a provider's health from the outcome of a call.
+// Success marks it healthy and rejected credentials mark it unhealthy; other
+// errors are usually transient and leave the status unchanged.
+func (r *Registry) RecordResult(id string, err error) {
+	switch {
+	case err == nil:
+		r.setHealth(id, Health{Status: HealthOK, CheckedAt: time.Now()})
+	case IsAuthError(err):
+		r.setHealth(id, Health{Status: HealthUnhealthy, Reason: "bad credentials", CheckedAt: time.Now()})
+	}
+}
+
+func (r *Registry) setHealth(id string, h Health) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.health[id] = h
+}
+
+// smallModels are each provider's cheap, fast model, in order of preference,
+// for housekeeping calls such as summaries
+var smallModels = []struct{ provider, model string }{
+	{"anthropic", "claude-3-5-haiku-20241022"},
+	{"openai", "gpt-4o-mini"},
+	{"groq", "llama-3.1-8b-instant"},
+	{"openrouter", "openai/gpt-4o-mini"},
+	{"ollama", "llama3.2"},
+}
+
+// SmallModel returns the first registered, healthy provider with a cheap
+// model for housekeeping calls, and that model. ok is false if none is
+// registered.
+func (r *Registry) SmallModel() (p Provider, model string, ok bool) {
+	for _, sm := range smallModels {
+		if r.Health(sm.provider).Status == HealthUnhealthy {
+			continue
+		}
+		r.mu.RLock()
+		p, ok = r.providers[sm.provider]
+		r.mu.RUnlock()
+		if ok {
+			return p, sm.model, true
+		}
+	}
+	return nil, "", false
+}
+
+// AutoDiscover registers providers based on environment variables, and
+// keys the config resolved from secret references
 func (r *Registry) AutoDiscover() {
 	// Anthropic
-	if key := os.Getenv("ANTHROPIC_API_KEY"); key != "" {
-		r.Register(NewAnthropic(key, ""))
+	if key := config.Getenv("ANTHROPIC_API_KEY"); key != "" {
+		var opts []AnthropicOption
+		if n, err := strconv.Atoi(config.Getenv("ANTHROPIC_MAX_CONCURRENCY")); err == nil && n > 0 {
+			opts = append(opts, WithMaxConcurrency(n))
+		}
+		if caching, err := strconv.ParseBool(config.Getenv("ANTHROPIC_PROMPT_CACHING")); err == nil {
+			opts = append(opts, WithPromptCaching(caching))
+		}
+		r.Register(NewAnthropic(key, anthropicBaseURL(config.Getenv("ANTHROPIC_BASE_URL")), opts...))
 	}
 
 	// OpenAI
-	if key := os.Getenv("OPENAI_API_KEY"); key != "" {
-		r.Register(NewOpenAI(key, ""))
+	if key := config.Getenv("OPENAI_API_KEY"); key != "" {
+		r.Register(NewOpenAI(key, config.Getenv("OPENAI_BASE_URL")))
 	}
 
 	// Ollama (local, no key needed)
-	ollamaURL := os.Getenv("OLLAMA_URL")
+	ollamaURL := config.Getenv("OLLAMA_URL")
 	if ollamaURL == "" {
 		ollamaURL = "http://localhost:11434"
 	}
 	r.Register(NewOllama(ollamaURL))
 
 	// Groq
-	if key := os.Getenv("GROQ_API_KEY"); key != "" {
-		r.Register(NewGroq(key))
+	if key := config.Getenv("GROQ_API_KEY"); key != "" {
+		r.Register(NewGroq(key, config.Getenv("GROQ_BASE_URL")))
 	}
 
 	// OpenRouter (access to many models: Claude, GPT, Gemini, Mistral, DeepSeek, etc.)
-	if key := os.Getenv("OPENROUTER_API_KEY"); key != "" {
-		siteName := os.Getenv("OPENROUTER_SITE_NAME")
-		siteURL := os.Getenv("OPENROUTER_SITE_URL")
-		r.Register(NewOpenRouter(key, siteName, siteURL))
+	if key := config.Getenv("OPENROUTER_API_KEY"); key != "" {
+		siteName := config.Getenv("OPENROUTER_SITE_NAME")
+		siteURL := config.Getenv("OPENROUTER_SITE_URL")
+		var opts []OpenRouterOption
+		if routing := openRouterRoutingFromEnv(); !routing.isZero() {
+			opts = append(opts, WithOpenRouterRouting(*routing))
+		}
+		r.Register(NewOpenRouter(key, config.Getenv("OPENROUTER_BASE_URL"), siteName, siteURL, opts...))
+	}
+}
+
+// openRouterRoutingFromEnv reads default OpenRouter provider routing
+func openRouterRoutingFromEnv() *OpenRouterRouting {
+	routing := &OpenRouterRouting{
+		DataCollection: config.Getenv("OPENROUTER_DATA_COLLECTION"),
+	}
+	if order := config.Getenv("OPENROUTER_PROVIDER_ORDER"); order != "" {
+		for _, p := range strings.Split(order, ",") {
+			if p = strings.TrimSpace(p); p != "" {
+				routing.Order = append(routing.Order, p)
+			}
+		}
+	}
+	if allow, err := strconv.ParseBool(config.Getenv("OPENROUTER_ALLOW_FALLBACKS")); err == nil {
+		routing.AllowFallbacks = &allow
 	}
+	return routing
 }
@@ -2,8 +2,13 @@ package provider
 
 import (
 	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"sync"
 	"testing"
+	"time"
 )
 
 func TestNewRegistry(t *testing.T) {
@@ -155,6 +160,64 @@ func TestAutoDiscoverWithOllamaURL(t *testing.T) {
 	}
 }
 
+func TestAutoDiscoverBaseURLs(t *testing.T) {
+	var mu sync.Mutex
+	paths := make(map[string]bool)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths[r.URL.Path] = true
+		mu.Unlock()
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		id, keyVar, urlVar, path string
+	}{
+		{"anthropic", "ANTHROPIC_API_KEY", "ANTHROPIC_BASE_URL", "/anthropic/v1/messages"},
+		{"openai", "OPENAI_API_KEY", "OPENAI_BASE_URL", "/openai/chat/completions"},
+		{"groq", "GROQ_API_KEY", "GROQ_BASE_URL", "/groq/chat/completions"},
+		{"openrouter", "OPENROUTER_API_KEY", "OPENROUTER_BASE_URL", "/openrouter/chat/completions"},
+	}
+	for _, tt := range tests {
+		t.Setenv(tt.keyVar, "test-key")
+		t.Setenv(tt.urlVar, server.URL+"/"+tt.id)
+	}
+
+	reg := NewRegistry()
+	reg.AutoDiscover()
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			p, err := reg.Get(tt.id)
+			if err != nil {
+				t.Fatalf("Expected %s registered: %v", tt.id, err)
+			}
+			p.Chat(context.Background(), &ChatRequest{Model: "test-model", Messages: []Message{{Role: "user", Content: "Hello"}}})
+
+			mu.Lock()
+			defer mu.Unlock()
+			if !paths[tt.path] {
+				t.Errorf("Expected a request to %s, got %v", tt.path, paths)
+			}
+		})
+	}
+}
+
+func TestAnthropicBaseURL(t *testing.T) {
+	tests := []struct{ base, want string }{
+		{"", ""},
+		{"https://gateway.example.com", "https://gateway.example.com/v1"},
+		{"https://gateway.example.com/anthropic/", "https://gateway.example.com/anthropic/v1"},
+		{"https://api.anthropic.com/v1", "https://api.anthropic.com/v1"},
+	}
+	for _, tt := range tests {
+		if got := anthropicBaseURL(tt.base); got != tt.want {
+			t.Errorf("anthropicBaseURL(%q) = %q, want %q", tt.base, got, tt.want)
+		}
+	}
+}
+
 // mockProvider is a simple mock for testing
 type mockProvider struct {
 	id   string
@@ -175,3 +238,116 @@ func (m *mockProvider) ListModels(ctx context.Context) ([]ModelInfo, error) {
 	return []ModelInfo{{ID: "mock-model", Name: "Mock Model"}}, nil
 }
 func (m *mockProvider) Ping(ctx context.Context) error { return nil }
+
+// flakyProvider fails its health checks while pingErr is set
+type flakyProvider struct {
+	mockProvider
+	mu      sync.Mutex
+	pingErr error
+}
+
+func (f *flakyProvider) setPingErr(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.pingErr = err
+}
+
+func (f *flakyProvider) Ping(ctx context.Context) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.pingErr
+}
+
+func TestRegistryMonitorHealth(t *testing.T) {
+	reg := NewRegistry()
+	flaky := &flakyProvider{mockProvider: mockProvider{id: "flaky", name: "Flaky"}}
+	reg.Register(flaky)
+
+	changes := make(chan Health, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	flaky.setPingErr(errors.New("connection refused"))
+	go reg.MonitorHealth(ctx, time.Millisecond, func(id string, h Health) { changes <- h })
+
+	waitFor := func(status string) Health {
+		t.Helper()
+		for {
+			select {
+			case h := <-changes:
+				if h.Status == status {
+					return h
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatalf("Timed out waiting for %s, health is %+v", status, reg.Health("flaky"))
+			}
+		}
+	}
+
+	h := waitFor(HealthUnhealthy)
+	if h.Failures != UnhealthyAfter || h.Reason != "connection refused" {
+		t.Errorf("Expected unhealthy after %d failures, got %+v", UnhealthyAfter, h)
+	}
+	if reg.Healthy("flaky") {
+		t.Error("Expected the provider to be skipped while unhealthy")
+	}
+
+	flaky.setPingErr(nil)
+	waitFor(HealthOK)
+	if !reg.Healthy("flaky") || reg.Health("flaky").Failures != 0 {
+		t.Errorf("Expected the provider to recover, got %+v", reg.Health("flaky"))
+	}
+}
+
+// busyProvider reports a fixed queue and concurrency
+type busyProvider struct {
+	mockProvider
+	pending  int
+	resumeIn time.Duration
+	inUse    int
+	limit    int
+}
+
+func (b *busyProvider) QueueState() (int, time.Duration) { return b.pending, b.resumeIn }
+func (b *busyProvider) Concurrency() (int, int)          { return b.inUse, b.limit }
+
+func TestRegistryStats(t *testing.T) {
+	reg := NewRegistry()
+	reg.Register(&busyProvider{mockProvider: mockProvider{id: "busy"}, pending: 3, resumeIn: 10 * time.Second, inUse: 2, limit: 2})
+	reg.Register(&mockProvider{id: "plain"})
+
+	reg.RecordCall("busy", 2*time.Second, nil)
+	reg.RecordCall("busy", 4*time.Second, errors.New("connection reset"))
+
+	stats, err := reg.Stats("busy")
+	if err != nil {
+		t.Fatalf("Stats failed: %v", err)
+	}
+	if stats.PendingRequests != 3 || !stats.RateLimited || stats.TimeUntilResume != 10 {
+		t.Errorf("Expected 3 pending and 10s until resume, got %+v", stats)
+	}
+	if stats.Concurrency != (ConcurrencyStats{InUse: 2, Limit: 2}) {
+		t.Errorf("Expected 2/2 in flight, got %+v", stats.Concurrency)
+	}
+	if stats.RecentCalls != 2 || stats.ErrorRate != 0.5 || stats.AvgLatencyMS != 3000 {
+		t.Errorf("Expected 2 calls, half failed, 3s average, got %+v", stats)
+	}
+	// 10s rate limit, then 4 ahead (3 queued plus a full slot) over 2 slots
+	// at 3s each, then the call itself
+	if stats.EstimatedServe != 19 {
+		t.Errorf("Expected 19s to serve, got %v", stats.EstimatedServe)
+	}
+
+	// Providers without a queue or calls report zeros
+	plain, err := reg.Stats("plain")
+	if err != nil {
+		t.Fatalf("Stats failed: %v", err)
+	}
+	if plain != (Stats{ID: "plain"}) {
+		t.Errorf("Expected zero stats, got %+v", plain)
+	}
+
+	if _, err := reg.Stats("missing"); err == nil {
+		t.Error("Expected an error for an unknown provider")
+	}
+}
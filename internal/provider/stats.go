@@ -0,0 +1,148 @@
+package provider
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// StatsWindow is how far back Stats looks for error rate and latency
+	StatsWindow = 5 * time.Minute
+
+	// maxCallRecords bounds the calls kept per provider within the window
+	maxCallRecords = 500
+)
+
+// QueueReporter is implemented by providers that hold requests back while
+// rate limited
+type QueueReporter interface {
+	// QueueState returns how many requests are queued and how long until the
+	// rate limit lifts (0 when not limited)
+	QueueState() (pending int, resumeIn time.Duration)
+}
+
+// ConcurrencyStats is how many requests a provider has in flight against its
+// limit. Limit is 0 when unlimited.
+type ConcurrencyStats struct {
+	InUse int `json:"in_use"`
+	Limit int `json:"limit"`
+}
+
+// Stats is how busy a provider is right now, for deciding whether to wait on
+// it or switch. Providers that don't queue or cap requests report zeros for
+// those fields.
+type Stats struct {
+	ID              string           `json:"id"`
+	PendingRequests int              `json:"pending_requests"`
+	RateLimited     bool             `json:"rate_limited"`
+	TimeUntilResume float64          `json:"time_until_resume_seconds"`
+	Concurrency     ConcurrencyStats `json:"concurrency"`
+
+	// Calls made through teams in the last StatsWindow
+	RecentCalls  int     `json:"recent_calls"`
+	ErrorRate    float64 `json:"error_rate"`
+	AvgLatencyMS int64   `json:"avg_latency_ms"`
+
+	// Requests answered from the response cache, when it's on
+	Cache *CacheStats `json:"cache,omitempty"`
+
+	// Roughly how long a request sent now would take to be answered:
+	// waiting out the rate limit and the queue, then one average call
+	EstimatedServe float64 `json:"estimated_serve_seconds"`
+}
+
+// callRecord is the outcome of one call, kept for Stats
+type callRecord struct {
+	at     time.Time
+	took   time.Duration
+	failed bool
+}
+
+// RecordCall records how long a call took and whether it failed, then
+// updates health as RecordResult does
+func (r *Registry) RecordCall(id string, took time.Duration, err error) {
+	now := time.Now()
+
+	r.mu.Lock()
+	calls := append(r.calls[id], callRecord{at: now, took: took, failed: err != nil})
+	r.calls[id] = recentCalls(calls, now)
+	r.mu.Unlock()
+
+	r.RecordResult(id, err)
+}
+
+// recentCalls drops calls older than StatsWindow and keeps at most
+// maxCallRecords
+func recentCalls(calls []callRecord, now time.Time) []callRecord {
+	i := 0
+	for i < len(calls) && now.Sub(calls[i].at) > StatsWindow {
+		i++
+	}
+	if n := len(calls) - i; n > maxCallRecords {
+		i += n - maxCallRecords
+	}
+	return calls[i:]
+}
+
+// Stats reports a provider's queue, rate limit, concurrency and recent call
+// history
+func (r *Registry) Stats(id string) (Stats, error) {
+	p, err := r.Get(id)
+	if err != nil {
+		return Stats{}, err
+	}
+
+	stats := Stats{ID: id}
+	var resumeIn time.Duration
+	if q, ok := p.(QueueReporter); ok {
+		stats.PendingRequests, resumeIn = q.QueueState()
+		stats.RateLimited = resumeIn > 0
+		stats.TimeUntilResume = resumeIn.Seconds()
+	}
+	if c, ok := p.(ConcurrencyReporter); ok {
+		stats.Concurrency.InUse, stats.Concurrency.Limit = c.Concurrency()
+	}
+
+	r.mu.Lock()
+	if r.cache != nil {
+		cache := r.cache.Stats(id)
+		stats.Cache = &cache
+	}
+	calls := recentCalls(r.calls[id], time.Now())
+	r.calls[id] = calls
+	var failed int
+	var total time.Duration
+	for _, c := range calls {
+		if c.failed {
+			failed++
+		}
+		total += c.took
+	}
+	r.mu.Unlock()
+
+	var avg time.Duration
+	if len(calls) > 0 {
+		stats.RecentCalls = len(calls)
+		stats.ErrorRate = float64(failed) / float64(len(calls))
+		avg = total / time.Duration(len(calls))
+		stats.AvgLatencyMS = avg.Milliseconds()
+	}
+	stats.EstimatedServe = estimateServe(stats.PendingRequests, stats.Concurrency, resumeIn, avg).Seconds()
+	return stats, nil
+}
+
+// estimateServe guesses how long a new request waits and runs: the rate
+// limit first, then the requests ahead of it spread across the provider's
+// slots, then its own call
+func estimateServe(pending int, c ConcurrencyStats, resumeIn, avg time.Duration) time.Duration {
+	ahead := pending
+	if c.Limit > 0 && c.InUse >= c.Limit {
+		ahead++ // Wait for a slot to free up
+	}
+	slots := 1
+	if c.Limit > 0 {
+		slots = c.Limit
+	}
+	rounds := int(math.Ceil(float64(ahead) / float64(slots)))
+	return resumeIn + time.Duration(rounds)*avg + avg
+}
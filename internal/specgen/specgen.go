@@ -5,15 +5,23 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/arcslash/ugudu/internal/provider"
+	"github.com/arcslash/ugudu/internal/team"
+	"gopkg.in/yaml.v3"
 )
 
+// DefaultMaxTurns is how many replies the interview takes before the
+// generator stops asking and generates a spec with what it has
+const DefaultMaxTurns = 6
+
 // Generator uses an LLM to generate team specifications
 type Generator struct {
 	provider provider.Provider
 	model    string
+	maxTurns int
 }
 
 // NewGenerator creates a new spec generator
@@ -24,9 +32,16 @@ func NewGenerator(p provider.Provider, model string) *Generator {
 	return &Generator{
 		provider: p,
 		model:    model,
+		maxTurns: DefaultMaxTurns,
 	}
 }
 
+// SetMaxTurns changes how many replies the interview takes before a spec is
+// generated regardless; 0 lets it run until the user says they're done
+func (g *Generator) SetMaxTurns(n int) {
+	g.maxTurns = n
+}
+
 // ConversationState tracks the interview progress
 type ConversationState struct {
 	Messages     []provider.Message
@@ -36,6 +51,13 @@ type ConversationState struct {
 	Complexity   string
 	Features     []string
 	IsComplete   bool
+
+	// Replies the user has given since the conversation started
+	Turns int
+
+	// Set when the spec was generated because the interview hit the turn
+	// cap rather than because the model had what it needed
+	AutoGenerated bool
 }
 
 // TeamSpec represents the generated specification
@@ -44,6 +66,12 @@ type TeamSpec struct {
 	Description  string     `json:"description"`
 	Roles        []RoleSpec `json:"roles"`
 	ClientFacing []string   `json:"client_facing"`
+
+	// Set on specs generated before the interview finished: how sure the
+	// model is that the team fits ("high", "medium" or "low"), and what it
+	// had to assume
+	Confidence  string   `json:"confidence,omitempty"`
+	Assumptions []string `json:"assumptions,omitempty"`
 }
 
 // RoleSpec represents a role in the team
@@ -154,8 +182,21 @@ func (g *Generator) StartConversation(ctx context.Context, initialInput string)
 	return state, resp, nil
 }
 
-// ContinueConversation continues the interview
+// ContinueConversation continues the interview. Once the user has replied
+// maxTurns times, the spec is generated with what the model has instead of
+// letting it ask again.
 func (g *Generator) ContinueConversation(ctx context.Context, state *ConversationState, userInput string) (string, error) {
+	state.Turns++
+	if g.maxTurns > 0 && state.Turns >= g.maxTurns {
+		state.Messages = append(state.Messages, provider.Message{Role: "user", Content: userInput + "\n\n" + forcePrompt})
+		spec, err := g.generate(ctx, state)
+		if err != nil {
+			return "", fmt.Errorf("generate spec after %d replies: %w", state.Turns, err)
+		}
+		state.AutoGenerated = true
+		return fmt.Sprintf("\nThat's %d replies, so here's my best guess at a team from what you've told me.\n", state.Turns) + g.formatSpecPreview(spec), nil
+	}
+
 	state.Messages = append(state.Messages, provider.Message{Role: "user", Content: userInput})
 
 	resp, err := g.chat(ctx, state.Messages)
@@ -174,6 +215,19 @@ func (g *Generator) ContinueConversation(ctx context.Context, state *Conversatio
 	return resp, nil
 }
 
+// Generate designs a team for description in one shot, without asking any
+// follow-up questions; whatever isn't said is assumed, and listed in the
+// spec's assumptions
+func (g *Generator) Generate(ctx context.Context, description string) (*TeamSpec, error) {
+	state := &ConversationState{
+		Messages: []provider.Message{
+			{Role: "system", Content: systemPrompt},
+			{Role: "user", Content: description + "\n\n" + forcePrompt},
+		},
+	}
+	return g.generate(ctx, state)
+}
+
 // GetGeneratedSpec extracts the final spec from a completed conversation
 func (g *Generator) GetGeneratedSpec(state *ConversationState) (*TeamSpec, error) {
 	if !state.IsComplete {
@@ -192,27 +246,50 @@ func (g *Generator) GetGeneratedSpec(state *ConversationState) (*TeamSpec, error
 	return nil, fmt.Errorf("no spec found in conversation")
 }
 
-// ForceGenerate asks the LLM to generate a spec with current information
-func (g *Generator) ForceGenerate(ctx context.Context, state *ConversationState) (*TeamSpec, error) {
-	prompt := `Based on our conversation so far, please generate the team specification now.
+// forcePrompt asks for a spec before the interview has finished
+const forcePrompt = `Based on our conversation so far, please generate the team specification now.
 If you don't have enough information, make reasonable assumptions based on what was discussed.
-Respond with the JSON spec block as described in your instructions.`
+Respond with the JSON spec block as described in your instructions, and add to the spec object:
+- "confidence": "high", "medium" or "low", for how well you expect this team fits
+- "assumptions": a list of the things you assumed rather than were told`
 
-	state.Messages = append(state.Messages, provider.Message{Role: "user", Content: prompt})
+// fixPrompt asks for a spec again after one that couldn't be used
+const fixPrompt = `That spec can't be used: %v
 
-	resp, err := g.chat(ctx, state.Messages)
-	if err != nil {
-		return nil, err
-	}
+Respond again with the complete, corrected JSON spec block.`
 
-	state.Messages = append(state.Messages, provider.Message{Role: "assistant", Content: resp})
+// ForceGenerate asks the LLM to generate a spec with current information
+func (g *Generator) ForceGenerate(ctx context.Context, state *ConversationState) (*TeamSpec, error) {
+	state.Messages = append(state.Messages, provider.Message{Role: "user", Content: forcePrompt})
+	return g.generate(ctx, state)
+}
 
-	if spec, ok := g.extractSpec(resp); ok {
-		state.IsComplete = true
-		return spec, nil
-	}
+// generate gets a spec from the model's reply to the last message. A reply
+// without a spec, or with one that wouldn't load, is sent back once with
+// what's wrong.
+func (g *Generator) generate(ctx context.Context, state *ConversationState) (*TeamSpec, error) {
+	for attempt := 1; ; attempt++ {
+		resp, err := g.chat(ctx, state.Messages)
+		if err != nil {
+			return nil, err
+		}
+		state.Messages = append(state.Messages, provider.Message{Role: "assistant", Content: resp})
 
-	return nil, fmt.Errorf("failed to generate spec")
+		spec, ok := g.extractSpec(resp)
+		if !ok {
+			err = fmt.Errorf("no JSON spec block in the reply")
+		} else {
+			err = team.ValidateSpec([]byte(spec.ToYAML(g.provider.ID(), g.model)))
+		}
+		if err == nil {
+			state.IsComplete = true
+			return spec, nil
+		}
+		if attempt == 2 {
+			return nil, fmt.Errorf("failed to generate spec: %w", err)
+		}
+		state.Messages = append(state.Messages, provider.Message{Role: "user", Content: fmt.Sprintf(fixPrompt, err)})
+	}
 }
 
 func (g *Generator) chat(ctx context.Context, messages []provider.Message) (string, error) {
@@ -302,6 +379,16 @@ func (g *Generator) formatSpecPreview(spec *TeamSpec) string {
 
 	sb.WriteString("\n👤 = client-facing, 🔒 = internal\n")
 
+	if spec.Confidence != "" {
+		sb.WriteString(fmt.Sprintf("\n**Confidence:** %s\n", spec.Confidence))
+	}
+	if len(spec.Assumptions) > 0 {
+		sb.WriteString("**Assumed:**\n")
+		for _, a := range spec.Assumptions {
+			sb.WriteString(fmt.Sprintf("  - %s\n", a))
+		}
+	}
+
 	return sb.String()
 }
 
@@ -319,8 +406,8 @@ func (spec *TeamSpec) ToYAML(providerName, model string) string {
 	sb.WriteString("apiVersion: ugudu/v1\n")
 	sb.WriteString("kind: Team\n")
 	sb.WriteString("metadata:\n")
-	sb.WriteString(fmt.Sprintf("  name: %s\n", spec.Name))
-	sb.WriteString(fmt.Sprintf("  description: %s\n", spec.Description))
+	sb.WriteString(fmt.Sprintf("  name: %s\n", yamlScalar(spec.Name)))
+	sb.WriteString(fmt.Sprintf("  description: %s\n", yamlScalar(spec.Description)))
 	sb.WriteString("\n")
 
 	sb.WriteString("client_facing:\n")
@@ -332,16 +419,16 @@ func (spec *TeamSpec) ToYAML(providerName, model string) string {
 	sb.WriteString("roles:\n")
 	for _, role := range spec.Roles {
 		sb.WriteString(fmt.Sprintf("  %s:\n", role.ID))
-		sb.WriteString(fmt.Sprintf("    title: %s\n", role.Title))
+		sb.WriteString(fmt.Sprintf("    title: %s\n", yamlScalar(role.Title)))
 
 		// Add name(s) for personality
 		if role.Count > 1 && len(role.Names) > 0 {
 			sb.WriteString("    names:\n")
 			for _, name := range role.Names {
-				sb.WriteString(fmt.Sprintf("      - %s\n", name))
+				sb.WriteString(fmt.Sprintf("      - %s\n", yamlScalar(name)))
 			}
 		} else if role.Name != "" {
-			sb.WriteString(fmt.Sprintf("    name: %s\n", role.Name))
+			sb.WriteString(fmt.Sprintf("    name: %s\n", yamlScalar(role.Name)))
 		}
 
 		sb.WriteString(fmt.Sprintf("    visibility: %s\n", role.Visibility))
@@ -377,3 +464,16 @@ func (spec *TeamSpec) ToYAML(providerName, model string) string {
 
 	return sb.String()
 }
+
+// yamlScalar writes a value on one line, quoted when it would otherwise
+// break the YAML around it, e.g. a description containing ": "
+func yamlScalar(s string) string {
+	if strings.ContainsAny(s, "\r\n") {
+		return strconv.Quote(s)
+	}
+	out, err := yaml.Marshal(s)
+	if err != nil {
+		return strconv.Quote(s)
+	}
+	return strings.TrimSuffix(string(out), "\n")
+}
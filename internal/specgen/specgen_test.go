@@ -1,8 +1,11 @@
 package specgen
 
 import (
+	"context"
 	"strings"
 	"testing"
+
+	"github.com/arcslash/ugudu/internal/provider"
 )
 
 func TestExtractSpec(t *testing.T) {
@@ -146,3 +149,117 @@ func TestTruncate(t *testing.T) {
 		}
 	}
 }
+
+// stubProvider answers every request with reply
+type stubProvider struct {
+	reply func(req *provider.ChatRequest) string
+}
+
+func (p *stubProvider) ID() string                   { return "stub" }
+func (p *stubProvider) Name() string                 { return "Stub" }
+func (p *stubProvider) Ping(_ context.Context) error { return nil }
+func (p *stubProvider) ListModels(_ context.Context) ([]provider.ModelInfo, error) {
+	return nil, nil
+}
+func (p *stubProvider) Stream(_ context.Context, _ *provider.ChatRequest) (<-chan provider.StreamChunk, error) {
+	ch := make(chan provider.StreamChunk)
+	close(ch)
+	return ch, nil
+}
+func (p *stubProvider) Chat(_ context.Context, req *provider.ChatRequest) (*provider.ChatResponse, error) {
+	return &provider.ChatResponse{Content: p.reply(req)}, nil
+}
+
+const stubSpec = "```json\n" + `{"ready": true, "spec": {
+  "name": "shop-team",
+  "description": "Builds the shop: storefront and checkout",
+  "confidence": "low",
+  "assumptions": ["A web storefront"],
+  "roles": [
+    {"id": "pm", "title": "PM", "visibility": "client", "persona": "You are the PM.", "can_delegate": ["%s"]},
+    {"id": "engineer", "title": "Engineer", "visibility": "internal", "persona": "You are an engineer.", "reports_to": "pm"}
+  ],
+  "client_facing": ["pm"]
+}}` + "\n```"
+
+func TestContinueConversation_AutoGeneratesAtTurnCap(t *testing.T) {
+	forced := 0
+	g := NewGenerator(&stubProvider{reply: func(req *provider.ChatRequest) string {
+		last := req.Messages[len(req.Messages)-1].Content
+		switch {
+		case strings.Contains(last, "generate the team specification now"):
+			forced++
+			return strings.Replace(stubSpec, "%s", "engineer", 1)
+		default:
+			// Never has enough to go on
+			return "Interesting! What else should I know?"
+		}
+	}}, "stub-model")
+	g.SetMaxTurns(3)
+
+	ctx := context.Background()
+	state, _, err := g.StartConversation(ctx, "An online shop")
+	if err != nil {
+		t.Fatalf("StartConversation failed: %v", err)
+	}
+	for i := 1; i < 3; i++ {
+		if _, err := g.ContinueConversation(ctx, state, "Not sure"); err != nil {
+			t.Fatalf("ContinueConversation failed: %v", err)
+		}
+		if state.IsComplete {
+			t.Fatalf("Expected the interview to continue after %d replies", i)
+		}
+	}
+
+	preview, err := g.ContinueConversation(ctx, state, "Still not sure")
+	if err != nil {
+		t.Fatalf("ContinueConversation failed: %v", err)
+	}
+	if !state.IsComplete || !state.AutoGenerated || forced != 1 {
+		t.Fatalf("Expected a spec generated at the cap, complete=%v auto=%v forced=%d", state.IsComplete, state.AutoGenerated, forced)
+	}
+	if !strings.Contains(preview, "best guess") || !strings.Contains(preview, "Confidence:** low") {
+		t.Errorf("Expected the preview to say the spec is best-effort, got:\n%s", preview)
+	}
+
+	spec, err := g.GetGeneratedSpec(state)
+	if err != nil {
+		t.Fatalf("GetGeneratedSpec failed: %v", err)
+	}
+	if spec.Name != "shop-team" || len(spec.Assumptions) != 1 {
+		t.Errorf("Unexpected spec: %+v", spec)
+	}
+}
+
+func TestForceGenerate_RepromptsInvalidSpec(t *testing.T) {
+	var fixes []string
+	g := NewGenerator(&stubProvider{reply: func(req *provider.ChatRequest) string {
+		last := req.Messages[len(req.Messages)-1].Content
+		if strings.HasPrefix(last, "That spec can't be used") {
+			fixes = append(fixes, last)
+			return strings.Replace(stubSpec, "%s", "engineer", 1)
+		}
+		// Delegates to a role the spec doesn't define
+		return strings.Replace(stubSpec, "%s", "designer", 1)
+	}}, "stub-model")
+
+	state := &ConversationState{}
+	spec, err := g.ForceGenerate(context.Background(), state)
+	if err != nil {
+		t.Fatalf("ForceGenerate failed: %v", err)
+	}
+	if len(fixes) != 1 || !strings.Contains(fixes[0], `can_delegate names role "designer"`) {
+		t.Errorf("Expected one re-prompt naming the problem, got %q", fixes)
+	}
+	if spec.Roles[0].CanDelegate[0] != "engineer" || !state.IsComplete {
+		t.Errorf("Expected the corrected spec, got %+v", spec)
+	}
+
+	// A second bad spec gives up
+	g = NewGenerator(&stubProvider{reply: func(req *provider.ChatRequest) string {
+		return "I'd rather keep chatting."
+	}}, "stub-model")
+	if _, err := g.ForceGenerate(context.Background(), &ConversationState{}); err == nil || !strings.Contains(err.Error(), "no JSON spec block") {
+		t.Errorf("Expected ForceGenerate to fail after one retry, got %v", err)
+	}
+}
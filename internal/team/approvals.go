@@ -0,0 +1,202 @@
+package team
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrApprovalNotFound is returned when deciding an unknown or decided approval
+var ErrApprovalNotFound = errors.New("approval not found")
+
+// Approval statuses
+const (
+	ApprovalPending  = "pending"
+	ApprovalApproved = "approved"
+	ApprovalDenied   = "denied"
+	ApprovalCanceled = "canceled" // The member stopped waiting, e.g. its team was stopped
+)
+
+// Approval is a tool call waiting on, or decided by, a human
+type Approval struct {
+	ID        string                 `json:"id"`
+	Member    string                 `json:"member"`
+	Tool      string                 `json:"tool"`
+	Args      map[string]interface{} `json:"args"`
+	TaskID    string                 `json:"task_id,omitempty"`
+	Status    string                 `json:"status"`
+	Reason    string                 `json:"reason,omitempty"`
+	CreatedAt time.Time              `json:"created_at"`
+	DecidedAt time.Time              `json:"decided_at,omitempty"`
+}
+
+// approvalDecision is a human's answer to a pending approval
+type approvalDecision struct {
+	approved bool
+	reason   string
+}
+
+// pendingApproval is a tool call a member is blocked on
+type pendingApproval struct {
+	Approval
+	decision chan approvalDecision
+}
+
+// requiresApproval reports whether the spec makes a human approve every call
+// to a tool
+func (t *Team) requiresApproval(tool string) bool {
+	for _, name := range t.Spec.Settings.RequiresApproval {
+		if name == tool {
+			return true
+		}
+	}
+	return false
+}
+
+// awaitApproval queues a member's tool call for a human and blocks until it's
+// approved or denied, ctx is cancelled or the spec's approval_timeout passes,
+// which denies it. The member is shown as waiting in the meantime.
+func (t *Team) awaitApproval(ctx context.Context, member *Member, tool string, args map[string]interface{}) (approved bool, reason string, err error) {
+	a := &pendingApproval{
+		Approval: Approval{
+			ID:        uuid.New().String()[:8],
+			Member:    member.ID,
+			Tool:      tool,
+			Args:      args,
+			Status:    ApprovalPending,
+			CreatedAt: time.Now(),
+		},
+		decision: make(chan approvalDecision, 1),
+	}
+	if task := member.GetCurrentTask(); task != nil {
+		a.TaskID = task.ID
+	}
+
+	t.approvalMu.Lock()
+	if t.approvals == nil {
+		t.approvals = make(map[string]*pendingApproval)
+	}
+	t.approvals[a.ID] = a
+	t.approvalMu.Unlock()
+	t.saveApproval(a.Approval)
+
+	defer func() {
+		t.approvalMu.Lock()
+		delete(t.approvals, a.ID)
+		t.approvalMu.Unlock()
+	}()
+
+	previous := member.GetStatus()
+	member.setStatus(ctx, MemberWaiting)
+	defer member.setStatus(ctx, previous)
+
+	t.logger.Info("waiting for approval", "member", member.ID, "tool", tool, "approval_id", a.ID)
+	t.notifyActivity(ctx, member.ID, "approval_requested", fmt.Sprintf("Waiting for approval to use %s", tool),
+		map[string]interface{}{"approval_id": a.ID, "tool": tool, "args": args})
+	t.shareProgress(ctx, member.ID, fmt.Sprintf("%s needs approval to use %s (approve with: ugudu team approvals %s --approve %s)",
+		member.DisplayName(), tool, t.Name, a.ID))
+
+	var timeout <-chan time.Time
+	if d := t.Spec.Settings.ApprovalTimeout; d > 0 {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	var decision approvalDecision
+	select {
+	case decision = <-a.decision:
+	case <-timeout:
+		// Unless it was decided just now, the timeout denies it
+		t.approvalMu.Lock()
+		_, pending := t.approvals[a.ID]
+		delete(t.approvals, a.ID)
+		t.approvalMu.Unlock()
+		if !pending {
+			decision = <-a.decision
+			break
+		}
+		decision = approvalDecision{reason: fmt.Sprintf("not approved within %s", t.Spec.Settings.ApprovalTimeout)}
+		a.Status = ApprovalDenied
+		a.Reason = decision.reason
+		a.DecidedAt = time.Now()
+		t.saveApproval(a.Approval)
+	case <-ctx.Done():
+		// Record that no one will act on it, unless it was decided just now
+		t.approvalMu.Lock()
+		_, pending := t.approvals[a.ID]
+		delete(t.approvals, a.ID)
+		t.approvalMu.Unlock()
+		if pending {
+			a.Status = ApprovalCanceled
+			a.Reason = "the request was canceled before a decision"
+			a.DecidedAt = time.Now()
+			t.saveApproval(a.Approval)
+		}
+		return false, "", ctx.Err()
+	}
+
+	activity, verb := "approval_denied", "Denied"
+	if decision.approved {
+		activity, verb = "approval_granted", "Approved"
+	}
+	t.notifyActivity(ctx, member.ID, activity, fmt.Sprintf("%s: %s", verb, tool),
+		map[string]interface{}{"approval_id": a.ID, "tool": tool})
+	return decision.approved, decision.reason, nil
+}
+
+// PendingApprovals returns the tool calls waiting on a human, oldest first
+func (t *Team) PendingApprovals() []Approval {
+	t.approvalMu.Lock()
+	approvals := make([]Approval, 0, len(t.approvals))
+	for _, a := range t.approvals {
+		approvals = append(approvals, a.Approval)
+	}
+	t.approvalMu.Unlock()
+
+	sort.Slice(approvals, func(i, j int) bool {
+		return approvals[i].CreatedAt.Before(approvals[j].CreatedAt)
+	})
+	return approvals
+}
+
+// DecideApproval approves or denies a pending tool call, resuming the member
+// waiting on it. A denial's reason is passed back to the member's model.
+func (t *Team) DecideApproval(id string, approved bool, reason string) (Approval, error) {
+	t.approvalMu.Lock()
+	a, ok := t.approvals[id]
+	if ok {
+		delete(t.approvals, id)
+	}
+	t.approvalMu.Unlock()
+
+	if !ok {
+		return Approval{}, fmt.Errorf("%w: %s", ErrApprovalNotFound, id)
+	}
+
+	a.Status = ApprovalDenied
+	if approved {
+		a.Status = ApprovalApproved
+	}
+	a.Reason = reason
+	a.DecidedAt = time.Now()
+	t.saveApproval(a.Approval)
+
+	a.decision <- approvalDecision{approved: approved, reason: reason}
+	t.logger.Info("approval decided", "member", a.Member, "tool", a.Tool, "approval_id", id, "status", a.Status)
+	return a.Approval, nil
+}
+
+// saveApproval records an approval and its decision
+func (t *Team) saveApproval(a Approval) {
+	if t.persistence == nil || t.persistence.SaveApproval == nil {
+		return
+	}
+	if err := t.persistence.SaveApproval(t.Name, a); err != nil {
+		t.logger.Warn("failed to save approval", "approval_id", a.ID, "error", err)
+	}
+}
@@ -0,0 +1,162 @@
+package team
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/arcslash/ugudu/internal/logger"
+	"github.com/arcslash/ugudu/internal/provider"
+)
+
+func TestTeam_ApprovalRequired(t *testing.T) {
+	tests := []struct {
+		name    string
+		approve bool
+		reason  string
+		want    string
+	}{
+		{"approved", true, "", "approved-run"},
+		{"denied", false, "not on a Friday", "Denied: a human didn't approve this call: not on a Friday"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The engineer runs a command, then reports what the tool returned
+			mockProv := &MockProvider{ChatFunc: func(req *provider.ChatRequest) (*provider.ChatResponse, error) {
+				last := req.Messages[len(req.Messages)-1]
+				if last.ToolCallID == "call-1" {
+					return &provider.ChatResponse{Content: "Tool said: " + last.Content}, nil
+				}
+				return &provider.ChatResponse{ToolCalls: []provider.ToolCall{
+					{ID: "call-1", Name: "run_command", Arguments: `{"command":"echo approved-run"}`},
+				}}, nil
+			}}
+
+			spec := limitsSpec(1)
+			spec.Settings.RequiresApproval = []string{"run_command"}
+			registry := provider.NewRegistry()
+			registry.Register(mockProv)
+
+			var mu sync.Mutex
+			var saved []string
+			tm, err := NewTeamWithPersistence(spec, registry, logger.New("error"), &PersistenceCallbacks{
+				SaveApproval: func(_ string, a Approval) error {
+					mu.Lock()
+					saved = append(saved, a.Status)
+					mu.Unlock()
+					return nil
+				},
+			})
+			if err != nil {
+				t.Fatalf("NewTeam failed: %v", err)
+			}
+			if err := tm.Start(context.Background()); err != nil {
+				t.Fatalf("Start failed: %v", err)
+			}
+			t.Cleanup(tm.Stop)
+
+			task := assignTask(tm, "Run the build")
+
+			// The call is queued and the engineer blocks on it
+			var pending []Approval
+			deadline := time.Now().Add(5 * time.Second)
+			for len(pending) == 0 && time.Now().Before(deadline) {
+				time.Sleep(10 * time.Millisecond)
+				pending = tm.PendingApprovals()
+			}
+			if len(pending) != 1 {
+				t.Fatalf("Expected 1 pending approval, got %d", len(pending))
+			}
+			a := pending[0]
+			if a.Tool != "run_command" || a.Member != "engineer" || a.Args["command"] != "echo approved-run" || a.TaskID != task.ID {
+				t.Errorf("Unexpected approval: %+v", a)
+			}
+			if status := tm.GetMemberByRole("engineer").GetStatus(); status != MemberWaiting {
+				t.Errorf("Expected engineer to be waiting, got %s", status)
+			}
+
+			decided, err := tm.DecideApproval(a.ID, tt.approve, tt.reason)
+			if err != nil {
+				t.Fatalf("DecideApproval failed: %v", err)
+			}
+			if (decided.Status == ApprovalApproved) != tt.approve || decided.DecidedAt.IsZero() {
+				t.Errorf("Unexpected decision: %+v", decided)
+			}
+
+			select {
+			case result := <-task.ResultChan:
+				if !result.Success || !strings.Contains(result.Content, tt.want) {
+					t.Errorf("Expected the result to contain %q, got %+v", tt.want, result)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("Timed out waiting for the task to resume")
+			}
+
+			mu.Lock()
+			got := strings.Join(saved, ",")
+			mu.Unlock()
+			if want := "pending," + decided.Status; got != want {
+				t.Errorf("Expected saved statuses %s, got %s", want, got)
+			}
+			if _, err := tm.DecideApproval(a.ID, true, ""); !errors.Is(err, ErrApprovalNotFound) {
+				t.Errorf("Expected ErrApprovalNotFound deciding twice, got %v", err)
+			}
+		})
+	}
+}
+
+func TestTeam_ApprovalCanceled(t *testing.T) {
+	mockProv := &MockProvider{ChatFunc: func(req *provider.ChatRequest) (*provider.ChatResponse, error) {
+		return &provider.ChatResponse{ToolCalls: []provider.ToolCall{
+			{ID: "call-1", Name: "run_command", Arguments: `{"command":"echo never"}`},
+		}}, nil
+	}}
+
+	spec := limitsSpec(1)
+	spec.Settings.RequiresApproval = []string{"run_command"}
+	registry := provider.NewRegistry()
+	registry.Register(mockProv)
+
+	var mu sync.Mutex
+	var saved []Approval
+	tm, err := NewTeamWithPersistence(spec, registry, logger.New("error"), &PersistenceCallbacks{
+		SaveApproval: func(_ string, a Approval) error {
+			mu.Lock()
+			saved = append(saved, a)
+			mu.Unlock()
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewTeam failed: %v", err)
+	}
+	if err := tm.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	assignTask(tm, "Run the build")
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(tm.PendingApprovals()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if len(tm.PendingApprovals()) != 1 {
+		t.Fatal("Expected a pending approval")
+	}
+
+	// Stopping the team cancels the wait; the approval isn't left pending
+	tm.Stop()
+	if err := tm.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait failed: %v", err)
+	}
+	if pending := tm.PendingApprovals(); len(pending) != 0 {
+		t.Errorf("Expected no pending approvals after stopping, got %+v", pending)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(saved) != 2 || saved[1].Status != ApprovalCanceled || saved[1].DecidedAt.IsZero() {
+		t.Errorf("Expected the approval saved as canceled, got %+v", saved)
+	}
+}
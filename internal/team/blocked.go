@@ -0,0 +1,152 @@
+package team
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/arcslash/ugudu/internal/provider"
+)
+
+// What a project does when the client doesn't answer in time
+const (
+	BlockProceed = "proceed"
+	BlockFail    = "fail"
+)
+
+// validateBlockSettings checks the spec's blocked-project settings
+func validateBlockSettings(s TeamSettings) error {
+	switch s.OnBlockTimeout {
+	case "", BlockProceed, BlockFail:
+	default:
+		return fmt.Errorf("settings.on_block_timeout must be %q or %q, got %q", BlockProceed, BlockFail, s.OnBlockTimeout)
+	}
+	if s.BlockTimeout < 0 || s.BlockWarning < 0 {
+		return fmt.Errorf("settings.block_timeout and settings.block_warning can't be negative")
+	}
+	if s.DelegationStagger < 0 || s.MaxParallelDelegation < 0 {
+		return fmt.Errorf("settings.delegation_stagger and settings.max_parallel_delegation can't be negative")
+	}
+	if s.MaxParallelStories < 0 {
+		return fmt.Errorf("settings.max_parallel_stories can't be negative")
+	}
+	if s.ApprovalTimeout < 0 {
+		return fmt.Errorf("settings.approval_timeout can't be negative")
+	}
+	return nil
+}
+
+// watchBlocked waits on a project blocked on client questions. Once it has
+// been blocked for the spec's block_warning it notifies the team, and after
+// block_timeout it either proceeds on the PM's assumptions or fails. It
+// returns as soon as the project is unblocked some other way.
+func (o *Orchestrator) watchBlocked(ctx context.Context, project *Project) {
+	settings := o.team.Spec.Settings
+	if settings.BlockTimeout <= 0 && settings.BlockWarning <= 0 {
+		return
+	}
+
+	var warning, timeout <-chan time.Time
+	if settings.BlockWarning > 0 {
+		t := time.NewTimer(settings.BlockWarning)
+		defer t.Stop()
+		warning = t.C
+	}
+	if settings.BlockTimeout > 0 {
+		t := time.NewTimer(settings.BlockTimeout)
+		defer t.Stop()
+		timeout = t.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-warning:
+			warning = nil
+			if project.GetPhase() != PhaseBlocked {
+				return
+			}
+			pending := len(project.Unanswered())
+			o.logger.Warn("project still blocked on client questions", "project", project.ID, "blocked_for", settings.BlockWarning, "questions", pending)
+			o.team.notifyActivity(ctx, "", "project_blocked",
+				fmt.Sprintf("Project %s has been waiting on %d client question(s) for %s", project.ID, pending, settings.BlockWarning),
+				map[string]interface{}{"project": project.ID, "questions": pending, "blocked_for": settings.BlockWarning.String()})
+		case <-timeout:
+			o.resolveBlocked(ctx, project)
+			return
+		}
+	}
+}
+
+// resolveBlocked applies the spec's on_block_timeout to a project that's
+// still blocked
+func (o *Orchestrator) resolveBlocked(ctx context.Context, project *Project) {
+	settings := o.team.Spec.Settings
+
+	if settings.OnBlockTimeout == BlockFail {
+		if !project.SetPhaseFrom(PhaseBlocked, PhaseFailed) {
+			return
+		}
+		msg := fmt.Sprintf("Project %s failed: the client didn't answer within %s", project.ID, settings.BlockTimeout)
+		o.logger.Warn("blocked project failed", "project", project.ID, "timeout", settings.BlockTimeout)
+		project.AddCommunication("status_update", "system", "client", msg, nil)
+		o.team.notifyActivity(ctx, "", "project_failed", msg, map[string]interface{}{"project": project.ID})
+		return
+	}
+
+	if !project.SetPhaseFrom(PhaseBlocked, PhaseTaskBreakdown) {
+		return
+	}
+	o.logger.Info("client didn't answer, proceeding on assumptions", "project", project.ID, "timeout", settings.BlockTimeout)
+	o.assumeAnswers(ctx, project)
+	o.team.notifyActivity(ctx, "", "project_unblocked",
+		fmt.Sprintf("Project %s is proceeding on the PM's assumptions after %s without an answer", project.ID, settings.BlockTimeout),
+		map[string]interface{}{"project": project.ID})
+	o.runTaskBreakdownPhase(ctx, project)
+}
+
+// assumeAnswers has the PM answer the client's unanswered questions with
+// reasonable assumptions, so task breakdown can go ahead without them
+func (o *Orchestrator) assumeAnswers(ctx context.Context, project *Project) {
+	pending := project.Unanswered()
+	if len(pending) == 0 {
+		return
+	}
+
+	assumption := "No answer from the client; use your best judgment."
+	if pm := o.phaseLead(PhasePlanning); pm != nil {
+		var questions strings.Builder
+		for _, q := range pending {
+			questions.WriteString("- " + q.Content + "\n")
+		}
+		prompt := fmt.Sprintf(`The client hasn't answered these questions about their request:
+
+"%s"
+
+%s
+Work can't wait any longer. For each question, state the most reasonable assumption to proceed on, as a short bullet list.`,
+			project.Description, questions.String())
+
+		resp, err := pm.chat(ctx, &provider.ChatRequest{
+			Model:       pm.getEffectiveModel(),
+			Temperature: pm.Role.Model.Temperature,
+			MaxTokens:   pm.getEffectiveMaxTokens(),
+			Messages: []provider.Message{
+				{Role: "system", Content: pm.buildSystemPrompt()},
+				{Role: "user", Content: prompt},
+			},
+		})
+		if err != nil {
+			o.logger.Error("PM assumptions failed", "error", err)
+		} else if content := strings.TrimSpace(resp.Content); content != "" {
+			assumption = content
+		}
+		project.AddCommunication("message", pm.ID, "all", "Proceeding on these assumptions:\n"+assumption, nil)
+	}
+
+	for _, q := range pending {
+		project.AssumeAnswer(q.ID, assumption)
+	}
+}
@@ -0,0 +1,103 @@
+package team
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/arcslash/ugudu/internal/logger"
+	"github.com/arcslash/ugudu/internal/provider"
+)
+
+// blockingPM answers the PM's analysis with a question for the client and
+// every later planning prompt with nothing to do
+func blockingPM(req *provider.ChatRequest) (*provider.ChatResponse, error) {
+	prompt := req.Messages[len(req.Messages)-1].Content
+	switch {
+	case strings.Contains(prompt, "leading this project"):
+		return &provider.ChatResponse{Content: "## Summary\nA login page\n\n## Questions for Client (if any)\n- Which identity provider?\n\n## Team Roles Needed\n- engineer"}, nil
+	case strings.Contains(prompt, "hasn't answered"):
+		return &provider.ChatResponse{Content: "- Assume email and password."}, nil
+	}
+	return &provider.ChatResponse{Content: "[]"}, nil
+}
+
+func TestOrchestrator_BlockTimeout(t *testing.T) {
+	tests := []struct {
+		onTimeout string
+		phase     ProjectPhase
+		activity  string
+	}{
+		{BlockProceed, PhaseComplete, "project_unblocked"},
+		{BlockFail, PhaseFailed, "project_failed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.onTimeout, func(t *testing.T) {
+			spec := limitsSpec(1)
+			spec.Settings.BlockWarning = 10 * time.Millisecond
+			spec.Settings.BlockTimeout = 50 * time.Millisecond
+			spec.Settings.OnBlockTimeout = tt.onTimeout
+
+			registry := provider.NewRegistry()
+			registry.Register(&MockProvider{ChatFunc: blockingPM})
+			tm, err := NewTeam(spec, registry, logger.New("error"))
+			if err != nil {
+				t.Fatalf("NewTeam failed: %v", err)
+			}
+
+			var mu sync.Mutex
+			var activities []string
+			tm.persistence = &PersistenceCallbacks{
+				OnActivity: func(_, _, activityType, _, _ string, _ map[string]interface{}) {
+					mu.Lock()
+					activities = append(activities, activityType)
+					mu.Unlock()
+				},
+			}
+			if err := tm.Start(context.Background()); err != nil {
+				t.Fatalf("Start failed: %v", err)
+			}
+			t.Cleanup(tm.Stop)
+
+			if _, err := tm.StartProject("Build a login page"); err != nil {
+				t.Fatalf("StartProject failed: %v", err)
+			}
+			project := tm.Orchestrator().activeProject
+
+			deadline := time.Now().Add(5 * time.Second)
+			for project.GetPhase() != tt.phase {
+				if time.Now().After(deadline) {
+					t.Fatalf("Expected phase %s, still %s", tt.phase, project.GetPhase())
+				}
+				time.Sleep(5 * time.Millisecond)
+			}
+
+			mu.Lock()
+			got := strings.Join(activities, ",")
+			mu.Unlock()
+			if !strings.Contains(got, "project_blocked") || !strings.Contains(got, tt.activity) {
+				t.Errorf("Expected project_blocked and %s activities, got %s", tt.activity, got)
+			}
+
+			if tt.onTimeout == BlockProceed {
+				q := project.PendingQuestions[0]
+				if q.Status != "dismissed" || q.Answer != "- Assume email and password." {
+					t.Errorf("Expected the PM's assumption to close the question, got %+v", q)
+				}
+			}
+		})
+	}
+}
+
+func TestNewTeam_InvalidBlockSettings(t *testing.T) {
+	registry := provider.NewRegistry()
+	registry.Register(&MockProvider{})
+
+	spec := limitsSpec(1)
+	spec.Settings.OnBlockTimeout = "wait"
+	if _, err := NewTeam(spec, registry, logger.New("error")); err == nil || !strings.Contains(err.Error(), "on_block_timeout") {
+		t.Errorf("Expected an on_block_timeout error, got %v", err)
+	}
+}
@@ -0,0 +1,80 @@
+package team
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/arcslash/ugudu/internal/logger"
+	"github.com/arcslash/ugudu/internal/provider"
+)
+
+// cassetteSpec is the team recorded in testdata/delegation.cassette.json.
+// Changing it, or the prompts members build, changes the requests and the
+// cassette has to be recorded again: register real providers, open the
+// cassette with provider.CassetteRecord and run the same ask.
+func cassetteSpec() *TeamSpec {
+	model := ModelConfig{Provider: "anthropic", Model: "claude-sonnet-4-20250514"}
+	return &TeamSpec{
+		Metadata:     Metadata{Name: "replay-team"},
+		ClientFacing: []string{"pm"},
+		Settings:     TeamSettings{Timezone: "UTC"},
+		Roles: map[string]Role{
+			"pm": {
+				Title:       "PM",
+				Count:       1,
+				Visibility:  "client",
+				Model:       model,
+				Persona:     "You are a PM. Delegate engineering work.",
+				CanDelegate: []string{"engineer"},
+			},
+			"engineer": {
+				Title:   "Engineer",
+				Count:   1,
+				Model:   model,
+				Persona: "You are an engineer.",
+			},
+		},
+	}
+}
+
+// cassetteClock is the time the cassette was recorded at; members' prompts
+// carry the date, so replaying needs the same one
+func cassetteClock() time.Time {
+	return time.Date(2026, time.October, 16, 9, 0, 0, 0, time.UTC)
+}
+
+func TestTeam_ReplayFromCassette(t *testing.T) {
+	// No API keys: the cassette stands in for the Anthropic provider
+	cassette, err := provider.OpenCassette("testdata/delegation.cassette.json", provider.CassetteReplay)
+	if err != nil {
+		t.Fatalf("OpenCassette failed: %v", err)
+	}
+	registry := provider.NewRegistry()
+	registry.SetCassette(cassette)
+
+	tm, err := NewTeam(cassetteSpec(), registry, logger.New("error"), WithClock(cassetteClock))
+	if err != nil {
+		t.Fatalf("NewTeam failed: %v", err)
+	}
+	if err := tm.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer tm.Stop()
+
+	// The PM delegates to the engineer and reports back, as recorded
+	select {
+	case msg := <-tm.AskMember("pm", "Add a health check endpoint", WithVerbosity(VerbosityQuiet)):
+		if msg.Content != "The health check endpoint is live at /health." {
+			t.Errorf("Unexpected response: %v", msg.Content)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for response")
+	}
+
+	engineer := tm.GetMemberByRole("engineer")
+	history := engineer.getContextMessages()
+	if len(history) == 0 || history[len(history)-1].Content != "Added a /health endpoint that returns 200 with the build version." {
+		t.Errorf("Expected the engineer's recorded answer in its context, got %v", history)
+	}
+}
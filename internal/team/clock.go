@@ -0,0 +1,90 @@
+package team
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// WithClock sets the clock members read the time from, e.g. the manager's
+func WithClock(now func() time.Time) TeamOption {
+	return func(t *Team) {
+		if now != nil {
+			t.now = now
+		}
+	}
+}
+
+// WithDefaultTimezone sets the timezone members work in when the spec
+// doesn't give one. Empty uses the daemon's local time.
+func WithDefaultTimezone(name string) TeamOption {
+	return func(t *Team) {
+		t.defaultTimezone = name
+	}
+}
+
+// validateTimezone checks the spec's timezone is one Go knows
+func validateTimezone(settings TeamSettings) error {
+	if settings.Timezone == "" {
+		return nil
+	}
+	if _, err := time.LoadLocation(settings.Timezone); err != nil {
+		return fmt.Errorf("settings.timezone: unknown timezone %q, use an IANA name such as Europe/London", settings.Timezone)
+	}
+	return nil
+}
+
+// setupClock resolves the timezone members work in: the spec's, else the
+// daemon default, else local time
+func (t *Team) setupClock() error {
+	if err := validateTimezone(t.Spec.Settings); err != nil {
+		return err
+	}
+	t.location = time.Local
+	name := t.Spec.Settings.Timezone
+	if name == "" {
+		name = t.defaultTimezone
+	}
+	if name == "" {
+		return nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return fmt.Errorf("defaults.timezone: unknown timezone %q, use an IANA name such as Europe/London", name)
+	}
+	t.location = loc
+	return nil
+}
+
+// Now returns the current time in the team's timezone
+func (t *Team) Now() time.Time {
+	now, loc := t.now, t.location
+	if now == nil {
+		now = time.Now
+	}
+	if loc == nil {
+		loc = time.Local
+	}
+	return now().In(loc)
+}
+
+// clockLine grounds a member's system prompt in today's date. The time is
+// left to get_current_time: a prompt that changed every minute would defeat
+// prompt and response caching.
+func clockLine(now time.Time) string {
+	return fmt.Sprintf("Today is %s (%s).\n", now.Format("Monday, 2 January 2006"), now.Location())
+}
+
+// renderClock fills a persona's time placeholders: {{date}}, {{time}},
+// {{weekday}} and {{timezone}}
+func renderClock(persona string, now time.Time) string {
+	if !strings.Contains(persona, "{{") {
+		return persona
+	}
+	return strings.NewReplacer(
+		"{{date}}", now.Format("2006-01-02"),
+		"{{time}}", now.Format("15:04"),
+		"{{weekday}}", now.Weekday().String(),
+		"{{timezone}}", now.Location().String(),
+	).Replace(persona)
+}
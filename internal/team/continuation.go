@@ -0,0 +1,119 @@
+package team
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SuspendedAskTTL is how long a suspended ask can be continued
+const SuspendedAskTTL = 30 * time.Minute
+
+// suspendedAsk holds the responses of an ask whose caller stopped listening,
+// e.g. because its HTTP request timed out. The team keeps working; responses
+// collect here until the ask is continued.
+type suspendedAsk struct {
+	to        string
+	suspended time.Time
+
+	mu       sync.Mutex
+	pending  []Message
+	finished bool
+	wake     chan struct{} // poked whenever pending or finished changes
+}
+
+// collect drains responses so the ask never blocks on a caller that left
+func (s *suspendedAsk) collect(responses <-chan Message) {
+	for msg := range responses {
+		s.mu.Lock()
+		s.pending = append(s.pending, msg)
+		s.mu.Unlock()
+		s.poke()
+	}
+
+	s.mu.Lock()
+	s.finished = true
+	s.mu.Unlock()
+	s.poke()
+}
+
+func (s *suspendedAsk) poke() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// replay returns a channel that yields the collected responses and then any
+// that arrive later, closing when the ask finishes
+func (s *suspendedAsk) replay() <-chan Message {
+	out := make(chan Message, 10)
+
+	go func() {
+		defer close(out)
+		for {
+			s.mu.Lock()
+			batch := s.pending
+			s.pending = nil
+			finished := s.finished
+			s.mu.Unlock()
+
+			for _, msg := range batch {
+				out <- msg
+			}
+			// Nothing is appended once finished is set
+			if finished {
+				return
+			}
+			<-s.wake
+		}
+	}()
+
+	return out
+}
+
+// SuspendAsk parks the responses of an ask whose caller stopped listening
+// and returns a token that ContinueAsk accepts. to is the role the ask was
+// sent to, empty for the client-facing member.
+func (t *Team) SuspendAsk(responses <-chan Message, to string) string {
+	s := &suspendedAsk{
+		to:        to,
+		suspended: time.Now(),
+		wake:      make(chan struct{}, 1),
+	}
+	go s.collect(responses)
+
+	token := uuid.New().String()
+
+	t.suspendMu.Lock()
+	defer t.suspendMu.Unlock()
+	if t.suspended == nil {
+		t.suspended = make(map[string]*suspendedAsk)
+	}
+	for tok, old := range t.suspended {
+		if time.Since(old.suspended) > SuspendedAskTTL {
+			delete(t.suspended, tok)
+		}
+	}
+	t.suspended[token] = s
+
+	t.logger.Debug("ask suspended", "token", token)
+	return token
+}
+
+// ContinueAsk reattaches to a suspended ask, returning its outstanding
+// responses followed by the rest as they arrive, and the role it was sent
+// to. A token can be continued once; ok is false for unknown or expired
+// tokens.
+func (t *Team) ContinueAsk(token string) (responses <-chan Message, to string, ok bool) {
+	t.suspendMu.Lock()
+	s, found := t.suspended[token]
+	delete(t.suspended, token)
+	t.suspendMu.Unlock()
+
+	if !found || time.Since(s.suspended) > SuspendedAskTTL {
+		return nil, "", false
+	}
+	return s.replay(), s.to, true
+}
@@ -0,0 +1,92 @@
+package team
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SetRoleCounts overrides the count of roles in the spec at specPath and
+// saves it, so one spec can back teams of different sizes. Roles are
+// checked against the effective spec; an inherited role gets an override
+// entry holding only its count.
+func SetRoleCounts(specPath string, counts map[string]int) error {
+	if len(counts) == 0 {
+		return nil
+	}
+
+	spec, err := LoadSpec(specPath)
+	if err != nil {
+		return err
+	}
+	roles := make([]string, 0, len(counts))
+	for role, n := range counts {
+		if _, ok := spec.Roles[role]; !ok {
+			return fmt.Errorf("spec has no role %q (roles: %s)", role, strings.Join(sortedRoleIDs(spec), ", "))
+		}
+		if n < 1 {
+			return fmt.Errorf("role %s: count must be at least 1, got %d", role, n)
+		}
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+
+	raw, err := os.ReadFile(specPath)
+	if err != nil {
+		return fmt.Errorf("read spec file: %w", err)
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(raw, &doc); err != nil {
+		return fmt.Errorf("parse spec: %w", err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return fmt.Errorf("parse spec: expected a mapping at the top level")
+	}
+	root := doc.Content[0]
+
+	rolesNode := mappingValue(root, "roles")
+	if rolesNode == nil || rolesNode.Kind != yaml.MappingNode {
+		rolesNode = &yaml.Node{Kind: yaml.MappingNode}
+		setMappingValue(root, "roles", rolesNode)
+	}
+	for _, role := range roles {
+		roleNode := mappingValue(rolesNode, role)
+		if roleNode == nil || roleNode.Kind != yaml.MappingNode {
+			roleNode = &yaml.Node{Kind: yaml.MappingNode}
+			setMappingValue(rolesNode, role, roleNode)
+		}
+		setMappingValue(roleNode, "count", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: strconv.Itoa(counts[role])})
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return fmt.Errorf("encode spec: %w", err)
+	}
+	enc.Close()
+
+	info, err := os.Stat(specPath)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(specPath, buf.Bytes(), info.Mode().Perm()); err != nil {
+		return fmt.Errorf("save spec: %w", err)
+	}
+	return nil
+}
+
+// sortedRoleIDs returns the spec's role IDs in order
+func sortedRoleIDs(spec *TeamSpec) []string {
+	ids := make([]string, 0, len(spec.Roles))
+	for id := range spec.Roles {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
@@ -0,0 +1,77 @@
+package team
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/arcslash/ugudu/internal/logger"
+	"github.com/arcslash/ugudu/internal/provider"
+)
+
+func TestSetRoleCounts(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "base.yaml"), []byte(`metadata:
+  name: base
+client_facing: [pm]
+roles:
+  pm:
+    title: PM
+    model: {provider: mock, model: mock-model}
+  qa:
+    title: QA
+    count: 2
+    model: {provider: mock, model: mock-model}
+`), 0644)
+	specPath := filepath.Join(dir, "alpha.yaml")
+	os.WriteFile(specPath, []byte(`extends: base
+metadata:
+  name: alpha
+roles:
+  engineer:
+    title: Engineer # Builds things
+    model: {provider: mock, model: mock-model}
+`), 0644)
+
+	if err := SetRoleCounts(specPath, map[string]int{"engineer": 3, "qa": 1}); err != nil {
+		t.Fatalf("SetRoleCounts failed: %v", err)
+	}
+
+	raw, _ := os.ReadFile(specPath)
+	if !strings.Contains(string(raw), "# Builds things") {
+		t.Errorf("Expected comments kept, got:\n%s", raw)
+	}
+
+	spec, err := LoadSpec(specPath)
+	if err != nil {
+		t.Fatalf("LoadSpec failed: %v", err)
+	}
+	if spec.Roles["qa"].Title != "QA" {
+		t.Errorf("Expected the inherited role kept whole, got %+v", spec.Roles["qa"])
+	}
+
+	registry := provider.NewRegistry()
+	registry.Register(&MockProvider{})
+	tm, err := NewTeam(spec, registry, logger.New("error"))
+	if err != nil {
+		t.Fatalf("NewTeam failed: %v", err)
+	}
+	for role, want := range map[string]int{"pm": 1, "engineer": 3, "qa": 1} {
+		if got := len(tm.MembersByRole[role]); got != want {
+			t.Errorf("Expected %d %s members, got %d", want, role, got)
+		}
+	}
+
+	for _, tt := range []struct {
+		counts map[string]int
+		want   string
+	}{
+		{map[string]int{"designer": 2}, `no role "designer" (roles: engineer, pm, qa)`},
+		{map[string]int{"engineer": 0}, "at least 1"},
+	} {
+		if err := SetRoleCounts(specPath, tt.counts); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Expected an error containing %q, got %v", tt.want, err)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package team
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FieldChange is one spec field that differs between two specs. Field is
+// the dotted YAML path, e.g. "model.model". From or To is empty when the
+// field is only set on one side.
+type FieldChange struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+// RoleChange lists the fields that differ in a role both specs have
+type RoleChange struct {
+	Role   string        `json:"role"`
+	Fields []FieldChange `json:"fields"`
+}
+
+// SpecDiff is how spec b differs from spec a
+type SpecDiff struct {
+	AddedRoles   []string      `json:"added_roles,omitempty"`   // Only in b
+	RemovedRoles []string      `json:"removed_roles,omitempty"` // Only in a
+	ChangedRoles []RoleChange  `json:"changed_roles,omitempty"`
+	Team         []FieldChange `json:"team,omitempty"` // Team-level fields: client_facing, workflow, settings, ...
+}
+
+// Empty reports whether the specs are the same
+func (d SpecDiff) Empty() bool {
+	return len(d.AddedRoles) == 0 && len(d.RemovedRoles) == 0 && len(d.ChangedRoles) == 0 && len(d.Team) == 0
+}
+
+// DiffSpecs compares two loaded specs role by role. Team names and
+// inheritance (extends, includes) are ignored since both specs are already
+// resolved. Everything is sorted so the result is stable.
+func DiffSpecs(a, b *TeamSpec) SpecDiff {
+	var diff SpecDiff
+
+	for id := range b.Roles {
+		if _, ok := a.Roles[id]; !ok {
+			diff.AddedRoles = append(diff.AddedRoles, id)
+		}
+	}
+	for id, role := range a.Roles {
+		other, ok := b.Roles[id]
+		if !ok {
+			diff.RemovedRoles = append(diff.RemovedRoles, id)
+			continue
+		}
+		if fields := diffFields(role, other); len(fields) > 0 {
+			diff.ChangedRoles = append(diff.ChangedRoles, RoleChange{Role: id, Fields: fields})
+		}
+	}
+	sort.Strings(diff.AddedRoles)
+	sort.Strings(diff.RemovedRoles)
+	sort.Slice(diff.ChangedRoles, func(i, j int) bool {
+		return diff.ChangedRoles[i].Role < diff.ChangedRoles[j].Role
+	})
+
+	diff.Team = diffFields(teamFields(a), teamFields(b))
+	return diff
+}
+
+// teamFields is the part of a spec that isn't roles or identity
+func teamFields(spec *TeamSpec) TeamSpec {
+	s := *spec
+	s.APIVersion, s.Kind, s.Extends, s.Includes = "", "", "", nil
+	s.Metadata.Name = ""
+	s.Roles = nil
+	return s
+}
+
+// diffFields compares two values field by field using their YAML form
+func diffFields(a, b interface{}) []FieldChange {
+	from, to := flattenYAML(a), flattenYAML(b)
+
+	var changes []FieldChange
+	for field, value := range from {
+		if to[field] != value {
+			changes = append(changes, FieldChange{Field: field, From: value, To: to[field]})
+		}
+	}
+	for field, value := range to {
+		if _, ok := from[field]; !ok {
+			changes = append(changes, FieldChange{Field: field, To: value})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return changes
+}
+
+// flattenYAML turns a value into dotted YAML paths and their values. Nested
+// mappings are flattened; lists are kept whole.
+func flattenYAML(v interface{}) map[string]string {
+	fields := make(map[string]string)
+
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return fields
+	}
+	var tree map[string]interface{}
+	if err := yaml.Unmarshal(data, &tree); err != nil {
+		return fields
+	}
+
+	var walk func(prefix string, node map[string]interface{})
+	walk = func(prefix string, node map[string]interface{}) {
+		for key, value := range node {
+			if nested, ok := value.(map[string]interface{}); ok {
+				walk(prefix+key+".", nested)
+				continue
+			}
+			fields[prefix+key] = formatField(value)
+		}
+	}
+	walk("", tree)
+	return fields
+}
+
+// formatField renders a field value on one line
+func formatField(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case []interface{}:
+		items := make([]string, len(v))
+		for i, item := range v {
+			if s, ok := item.(string); ok {
+				items[i] = s
+				continue
+			}
+			data, _ := json.Marshal(item)
+			items[i] = string(data)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,48 @@
+package team
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffSpecs(t *testing.T) {
+	a := &TeamSpec{
+		Metadata:     Metadata{Name: "alpha"},
+		ClientFacing: []string{"pm"},
+		Roles: map[string]Role{
+			"pm":       {Title: "PM", Model: ModelConfig{Provider: "anthropic", Model: "claude-sonnet-4-20250514"}, CanDelegate: []string{"engineer"}},
+			"engineer": {Title: "Engineer", Model: ModelConfig{Provider: "anthropic", Model: "claude-sonnet-4-20250514"}},
+			"qa":       {Title: "QA"},
+		},
+	}
+	b := &TeamSpec{
+		Metadata:     Metadata{Name: "beta"},
+		ClientFacing: []string{"pm"},
+		Roles: map[string]Role{
+			"pm":       {Title: "PM", Model: ModelConfig{Provider: "anthropic", Model: "claude-sonnet-4-20250514"}, CanDelegate: []string{"engineer"}},
+			"engineer": {Title: "Engineer", Model: ModelConfig{Provider: "openai", Model: "gpt-4o"}},
+			"designer": {Title: "Designer"},
+		},
+	}
+
+	diff := DiffSpecs(a, b)
+
+	want := SpecDiff{
+		AddedRoles:   []string{"designer"},
+		RemovedRoles: []string{"qa"},
+		ChangedRoles: []RoleChange{{
+			Role: "engineer",
+			Fields: []FieldChange{
+				{Field: "model.model", From: "claude-sonnet-4-20250514", To: "gpt-4o"},
+				{Field: "model.provider", From: "anthropic", To: "openai"},
+			},
+		}},
+	}
+	if !reflect.DeepEqual(diff, want) {
+		t.Errorf("Unexpected diff:\n got %+v\nwant %+v", diff, want)
+	}
+
+	if d := DiffSpecs(a, a); !d.Empty() {
+		t.Errorf("Expected no differences comparing a spec with itself, got %+v", d)
+	}
+}
@@ -0,0 +1,80 @@
+package team
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/arcslash/ugudu/internal/provider"
+)
+
+// defaultEditorInstructions is the editor's system prompt unless the spec
+// gives its own
+const defaultEditorInstructions = `You edit a team's reply before it reaches their client. Rewrite it for the client: plain language, without internal jargon, team role names, raw tool output or stack traces unless the client asked for them. Keep every fact, decision, question and next step, and any code or commands the client needs. Add nothing new. Reply with the edited message only.`
+
+// editorMaxTokens bounds the editor's rewrite of one reply
+const editorMaxTokens = 4096
+
+// editForClient runs a reply through the team's editor when the spec turns
+// it on. Minimal token mode skips it, and if the edit fails the reply is
+// sent as written.
+func (m *Member) editForClient(ctx context.Context, content string) string {
+	if m.Team == nil || m.Team.Spec == nil || strings.TrimSpace(content) == "" {
+		return content
+	}
+	editor := m.Team.Spec.Settings.Editor
+	if !editor.Enabled || m.Team.GetTokenSettings().Mode == TokenModeMinimal {
+		return content
+	}
+
+	prov, model := m.Provider, m.getEffectiveModel()
+	if editor.Model.Provider != "" {
+		p, err := m.Team.providers.Get(editor.Model.Provider)
+		if err != nil {
+			m.log(ctx).Warn("editor provider unavailable, sending reply unedited", "provider", editor.Model.Provider, "error", err)
+			return content
+		}
+		prov = p
+	}
+	if editor.Model.Model != "" {
+		model = editor.Model.Model
+	}
+	instructions := editor.Instructions
+	if instructions == "" {
+		instructions = defaultEditorInstructions
+	}
+
+	maxTokens := editorMaxTokens
+	if editor.Model.MaxTokens != nil {
+		maxTokens = *editor.Model.MaxTokens
+	}
+	ctx, cancel := context.WithTimeout(ctx, m.requestTimeout())
+	defer cancel()
+
+	req := &provider.ChatRequest{
+		Model:       model,
+		MaxTokens:   &maxTokens,
+		Temperature: editor.Model.Temperature,
+		Messages: []provider.Message{
+			{Role: "system", Content: instructions},
+			{Role: "user", Content: content},
+		},
+	}
+	m.clampMaxTokens(ctx, prov, req)
+	start := time.Now()
+	resp, err := prov.Chat(ctx, req)
+	if m.Team.providers != nil {
+		m.Team.providers.RecordCall(prov.ID(), time.Since(start), err)
+	}
+	m.Team.recordCall(ctx, m.ID, prov, req, resp, err, start)
+	if err != nil {
+		m.log(ctx).Warn("editor failed, sending reply unedited", "error", err)
+		return content
+	}
+	edited := strings.TrimSpace(resp.Content)
+	if edited == "" {
+		return content
+	}
+	m.log(ctx).Debug("reply edited for client", "before_len", len(content), "after_len", len(edited))
+	return edited
+}
@@ -0,0 +1,59 @@
+package team
+
+import "github.com/arcslash/ugudu/internal/tools"
+
+// WithRawSpec keeps the team's spec as written, before LoadSpec expanded its
+// environment variables and secret references, so the spec can be shown or
+// saved without the values they stand for
+func WithRawSpec(spec *TeamSpec) TeamOption {
+	return func(t *Team) {
+		t.rawSpec = spec
+	}
+}
+
+// ExportSpec returns the spec to show or save for the team. That's the raw
+// spec if the team was given one; otherwise it's the running spec with the
+// values of secret-looking env vars and MCP headers redacted.
+func (t *Team) ExportSpec() *TeamSpec {
+	if t.rawSpec != nil {
+		return t.rawSpec
+	}
+	return redactSpec(t.Spec)
+}
+
+// redactSpec returns a copy of spec whose secret env vars and MCP headers are
+// replaced with placeholders. spec itself is left alone.
+func redactSpec(spec *TeamSpec) *TeamSpec {
+	out := *spec
+	out.Settings.Env = redactValues(spec.Settings.Env)
+
+	out.Roles = make(map[string]Role, len(spec.Roles))
+	for name, role := range spec.Roles {
+		role.Env = redactValues(role.Env)
+		out.Roles[name] = role
+	}
+
+	if spec.Shared.MCPServers != nil {
+		out.Shared.MCPServers = make(map[string]tools.MCPServerConfig, len(spec.Shared.MCPServers))
+		for name, server := range spec.Shared.MCPServers {
+			server.Env = redactValues(server.Env)
+			server.Headers = redactValues(server.Headers)
+			out.Shared.MCPServers[name] = server
+		}
+	}
+	return &out
+}
+
+func redactValues(values map[string]string) map[string]string {
+	if values == nil {
+		return nil
+	}
+	out := make(map[string]string, len(values))
+	for name, value := range values {
+		if value != "" && tools.IsSecretEnv(name) {
+			value = "[REDACTED:" + name + "]"
+		}
+		out[name] = value
+	}
+	return out
+}
@@ -0,0 +1,43 @@
+package team
+
+import (
+	"testing"
+
+	"github.com/arcslash/ugudu/internal/tools"
+)
+
+func TestExportSpecRedactsSecrets(t *testing.T) {
+	spec := &TeamSpec{
+		Roles: map[string]Role{
+			"dev": {Title: "Developer", Env: map[string]string{"DEPLOY_TOKEN": "s3cret", "REGION": "eu-west-1"}},
+		},
+		Shared: SharedResources{MCPServers: map[string]tools.MCPServerConfig{
+			"tracker": {URL: "https://tracker.example.com/mcp", Headers: map[string]string{"Authorization": "Bearer s3cret"}},
+		}},
+		Settings: TeamSettings{Env: map[string]string{"DB_PASSWORD": "s3cret"}},
+	}
+	team := &Team{Spec: spec}
+
+	exported := team.ExportSpec()
+	if got := exported.Roles["dev"].Env["DEPLOY_TOKEN"]; got != "[REDACTED:DEPLOY_TOKEN]" {
+		t.Errorf("Expected the role's token redacted, got %q", got)
+	}
+	if got := exported.Roles["dev"].Env["REGION"]; got != "eu-west-1" {
+		t.Errorf("Expected other env vars kept, got %q", got)
+	}
+	if got := exported.Shared.MCPServers["tracker"].Headers["Authorization"]; got != "[REDACTED:Authorization]" {
+		t.Errorf("Expected the MCP header redacted, got %q", got)
+	}
+	if got := exported.Settings.Env["DB_PASSWORD"]; got != "[REDACTED:DB_PASSWORD]" {
+		t.Errorf("Expected the team env var redacted, got %q", got)
+	}
+	if spec.Roles["dev"].Env["DEPLOY_TOKEN"] != "s3cret" || spec.Settings.Env["DB_PASSWORD"] != "s3cret" {
+		t.Error("Expected the running spec left alone")
+	}
+
+	raw := &TeamSpec{Settings: TeamSettings{Env: map[string]string{"DB_PASSWORD": "${DB_PASSWORD}"}}}
+	team.rawSpec = raw
+	if team.ExportSpec() != raw {
+		t.Error("Expected the raw spec exported when the team has one")
+	}
+}
@@ -0,0 +1,165 @@
+package team
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// handoffTranscriptMessages is how much of the conversation a member hands
+// over along with it
+const handoffTranscriptMessages = 10
+
+// handoffAck is what a member handed a conversation "said" on taking it, so
+// its context alternates user and assistant turns
+const handoffAck = "Understood, I'll take the conversation from here."
+
+// clientMember returns the member that takes the client's requests in the
+// current conversation: whoever it was last handed off to, or else the
+// primary client-facing member
+func (t *Team) clientMember() *Member {
+	if id := t.ClientMemberID(); id != "" {
+		if m := t.GetMember(id); m != nil {
+			return m
+		}
+	}
+	return t.primaryMember()
+}
+
+// ClientMemberID returns the ID of the member the current conversation was
+// last handed off to, or "" if it hasn't been
+func (t *Team) ClientMemberID() string {
+	t.handoffMu.Lock()
+	defer t.handoffMu.Unlock()
+	return t.clientMembers[t.conversationID]
+}
+
+// setClientMember routes the current conversation's client requests to m,
+// and records it with the conversation so it survives a restart
+func (t *Team) setClientMember(m *Member) {
+	t.handoffMu.Lock()
+	if t.clientMembers == nil {
+		t.clientMembers = make(map[string]string)
+	}
+	t.clientMembers[t.conversationID] = m.ID
+	t.handoffMu.Unlock()
+
+	if t.persistence != nil && t.persistence.SaveClientMember != nil && t.conversationID != "" {
+		if err := t.persistence.SaveClientMember(t.Name, t.conversationID, m.ID); err != nil {
+			t.logger.Warn("failed to save client member", "member", m.ID, "error", err)
+		}
+	}
+}
+
+// restoreClientMember picks up the member the current conversation was
+// handed off to before the team was last stopped
+func (t *Team) restoreClientMember() {
+	if t.persistence == nil || t.persistence.LoadClientMember == nil || t.conversationID == "" {
+		return
+	}
+	id, err := t.persistence.LoadClientMember(t.Name, t.conversationID)
+	if err != nil {
+		t.logger.Warn("failed to load client member", "error", err)
+		return
+	}
+	if id == "" {
+		return
+	}
+
+	t.handoffMu.Lock()
+	defer t.handoffMu.Unlock()
+	if t.clientMembers == nil {
+		t.clientMembers = make(map[string]string)
+	}
+	t.clientMembers[t.conversationID] = id
+}
+
+// canHandoffTo reports whether the member's role may hand the client to role
+func (m *Member) canHandoffTo(role string) bool {
+	for _, r := range m.Role.CanHandoff {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// handleHandoff hands the client conversation to another member, who answers
+// the client's request and takes the ones after it until it hands the
+// conversation on. A target the member can't hand off to is ignored and the
+// note sent to the client as the answer.
+func (m *Member) handleHandoff(ctx context.Context, action responseAction, originalMsg Message) {
+	target := m.Team.memberFor(action.Target)
+	if target == nil || target == m || !m.canHandoffTo(target.RoleName) {
+		m.log(ctx).Warn("handoff target not found or not allowed", "target", action.Target)
+		m.respondToClient(ctx, action.Content)
+		return
+	}
+
+	// The target takes the handoff into its context when it handles the
+	// request, so a request that can't be delivered leaves it untouched
+	request := originalMsg
+	request.ID = uuid.New().String()
+	request.To = target.ID
+	request.Timestamp = time.Now()
+	request.handoff = &handoff{from: m, note: action.Content, transcript: m.handoffTranscript()}
+	if err := target.Send(request); err != nil {
+		m.log(ctx).Warn("handoff target too busy", "target", target.ID, "error", err)
+		m.respondToClient(ctx, fmt.Sprintf("%s is too busy to take over: %v", target.DisplayName(), err))
+		return
+	}
+	m.Team.setClientMember(target)
+
+	m.log(ctx).Info("handed off client conversation", "to", target.ID)
+	m.Team.audit(ctx, m.ID, AuditHandoff, map[string]interface{}{"to": target.ID, "note": action.Content})
+	m.Team.NotifyActivity(ctx, m.ID, "handoff", fmt.Sprintf("Handed the client over to %s", target.DisplayName()))
+	m.Team.shareInternal(ctx, m.ID, fmt.Sprintf("Handed the client over to %s: %s", target.DisplayName(), action.Content))
+}
+
+// handoffTranscript renders the member's recent conversation for the member
+// taking it over, leaving out the turn that decided on the handoff, whose
+// request is passed on as it is
+func (m *Member) handoffTranscript() string {
+	history := m.getContextMessages()
+	if len(history) >= 2 {
+		history = history[:len(history)-2]
+	}
+	if len(history) > handoffTranscriptMessages {
+		history = history[len(history)-handoffTranscriptMessages:]
+	}
+
+	var sb strings.Builder
+	for _, msg := range history {
+		speaker := "client"
+		if msg.Role == "assistant" {
+			speaker = m.DisplayName()
+		}
+		fmt.Fprintf(&sb, "\n%s: %s\n", speaker, truncateMessage(msg.Content, 1000))
+	}
+	return sb.String()
+}
+
+// handoff is what a member handing over the client conversation passes on
+// with the client's request
+type handoff struct {
+	from       *Member
+	note       string
+	transcript string
+}
+
+// acceptHandoff puts what the member handing over the conversation passed on
+// into this member's context
+func (m *Member) acceptHandoff(h *handoff) {
+	content := fmt.Sprintf("%s handed the conversation with the client over to you. You now answer the client directly.", h.from.DisplayName())
+	if h.note != "" {
+		content += "\n\nTheir note: " + h.note
+	}
+	if h.transcript != "" {
+		content += "\n\nThe conversation so far:\n" + h.transcript
+	}
+	m.addToContext("user", content)
+	m.addToContext("assistant", handoffAck)
+}
@@ -0,0 +1,236 @@
+package team
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/arcslash/ugudu/internal/logger"
+	"github.com/arcslash/ugudu/internal/provider"
+)
+
+func TestTeam_Handoff(t *testing.T) {
+	spec := &TeamSpec{
+		Metadata:     Metadata{Name: "support"},
+		ClientFacing: []string{"triage"},
+		Roles: map[string]Role{
+			"triage": {
+				Title:      "Triage",
+				Count:      1,
+				Visibility: "client",
+				Model:      ModelConfig{Provider: "mock", Model: "mock-model"},
+				Persona:    "You triage support requests.",
+				CanHandoff: []string{"security"},
+			},
+			"security": {
+				Title:   "Security",
+				Count:   1,
+				Model:   ModelConfig{Provider: "mock", Model: "mock-model"},
+				Persona: "You handle security incidents.",
+			},
+		},
+	}
+
+	var mu sync.Mutex
+	calls := map[string][]*provider.ChatRequest{}
+	registry := provider.NewRegistry()
+	registry.Register(&MockProvider{ChatFunc: func(req *provider.ChatRequest) (*provider.ChatResponse, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if strings.Contains(req.Messages[0].Content, "You triage") {
+			calls["triage"] = append(calls["triage"], req)
+			return &provider.ChatResponse{Content: "HANDOFF TO security: The client leaked an API key."}, nil
+		}
+		calls["security"] = append(calls["security"], req)
+		return &provider.ChatResponse{Content: "Security here: rotate the key now."}, nil
+	}})
+
+	tm, err := NewTeam(spec, registry, logger.New("error"))
+	if err != nil {
+		t.Fatalf("NewTeam failed: %v", err)
+	}
+	if err := tm.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer tm.Stop()
+
+	ask := func(content string) string {
+		t.Helper()
+		var answer string
+		done := time.After(5 * time.Second)
+		responses := tm.Ask(content)
+		for {
+			select {
+			case msg, ok := <-responses:
+				if !ok {
+					return answer
+				}
+				if msg.Type == MsgClientResponse {
+					answer = msg.Content.(string)
+				}
+			case <-done:
+				t.Fatal("Timed out waiting for response")
+			}
+		}
+	}
+
+	// Triage hands off, and security answers the request it was handed
+	if got := ask("I pushed our API key to GitHub"); got != "Security here: rotate the key now." {
+		t.Errorf("Expected security's answer to the handed-off request, got %q", got)
+	}
+	security := tm.GetMemberByRole("security")
+	if tm.ClientMemberID() != security.ID || tm.Status()["client_member"] != security.ID {
+		t.Errorf("Expected the conversation to be with %s, got %q", security.ID, tm.ClientMemberID())
+	}
+
+	// The next ask goes straight to security
+	if got := ask("Done, what next?"); got != "Security here: rotate the key now." {
+		t.Errorf("Expected security to answer, got %q", got)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(calls["triage"]) != 1 {
+		t.Errorf("Expected triage to be asked once, got %d", len(calls["triage"]))
+	}
+	if len(calls["security"]) != 2 {
+		t.Fatalf("Expected security to be asked twice, got %d", len(calls["security"]))
+	}
+	var handedOver string
+	for _, msg := range calls["security"][0].Messages {
+		handedOver += msg.Content + "\n"
+	}
+	if !strings.Contains(handedOver, "Their note: The client leaked an API key.") || !strings.Contains(handedOver, "I pushed our API key to GitHub") {
+		t.Errorf("Expected the handoff note and the client's request, got %q", handedOver)
+	}
+}
+
+func TestMember_HandoffNotAllowed(t *testing.T) {
+	spec := &TeamSpec{
+		Metadata:     Metadata{Name: "support"},
+		ClientFacing: []string{"triage"},
+		Roles: map[string]Role{
+			"triage":   {Title: "Triage", Count: 1, Visibility: "client", Model: ModelConfig{Provider: "mock"}},
+			"security": {Title: "Security", Count: 1, Model: ModelConfig{Provider: "mock"}},
+		},
+	}
+	registry := provider.NewRegistry()
+	registry.Register(&MockProvider{ChatFunc: func(req *provider.ChatRequest) (*provider.ChatResponse, error) {
+		return &provider.ChatResponse{Content: "HANDOFF TO security: Over to you."}, nil
+	}})
+	tm, err := NewTeam(spec, registry, logger.New("error"))
+	if err != nil {
+		t.Fatalf("NewTeam failed: %v", err)
+	}
+	if err := tm.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer tm.Stop()
+
+	// Triage has no can_handoff, so the conversation stays with it
+	select {
+	case <-tm.Ask("Help"):
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for response")
+	}
+	if id := tm.ClientMemberID(); id != "" {
+		t.Errorf("Expected no handoff, got the conversation with %q", id)
+	}
+}
+
+func TestMember_HandoffUndelivered(t *testing.T) {
+	spec := &TeamSpec{
+		Metadata:     Metadata{Name: "support"},
+		ClientFacing: []string{"triage"},
+		Roles: map[string]Role{
+			"triage":   {Title: "Triage", Count: 1, Visibility: "client", Model: ModelConfig{Provider: "mock"}, CanHandoff: []string{"security"}},
+			"security": {Title: "Security", Count: 1, Model: ModelConfig{Provider: "mock"}},
+		},
+	}
+	registry := provider.NewRegistry()
+	registry.Register(&MockProvider{})
+	tm, err := NewTeam(spec, registry, logger.New("error"), WithLimits(Limits{InboxSize: 1}))
+	if err != nil {
+		t.Fatalf("NewTeam failed: %v", err)
+	}
+
+	// Not started, so security's inbox stays full
+	triage, security := tm.GetMemberByRole("triage"), tm.GetMemberByRole("security")
+	if err := security.Send(Message{Type: MsgTaskUpdate}); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	triage.handleHandoff(context.Background(), responseAction{Type: "handoff", Target: "security", Content: "Over to you."},
+		Message{Type: MsgClientRequest, Content: "Help"})
+
+	if id := tm.ClientMemberID(); id != "" {
+		t.Errorf("Expected no handoff, got the conversation with %q", id)
+	}
+	if history := security.getContextMessages(); len(history) != 0 {
+		t.Errorf("Expected security's context left alone, got %v", history)
+	}
+}
+
+func TestMember_HandoffAfterReport(t *testing.T) {
+	spec := &TeamSpec{
+		Metadata:     Metadata{Name: "support"},
+		ClientFacing: []string{"triage"},
+		Roles: map[string]Role{
+			"triage": {
+				Title: "Triage", Count: 1, Visibility: "client", Persona: "You triage support requests.",
+				Model:       ModelConfig{Provider: "mock"},
+				CanDelegate: []string{"engineer"},
+				CanHandoff:  []string{"security"},
+			},
+			"engineer": {Title: "Engineer", Count: 1, Persona: "You read logs.", Model: ModelConfig{Provider: "mock"}},
+			"security": {Title: "Security", Count: 1, Persona: "You handle security incidents.", Model: ModelConfig{Provider: "mock"}},
+		},
+	}
+	registry := provider.NewRegistry()
+	registry.Register(&MockProvider{ChatFunc: func(req *provider.ChatRequest) (*provider.ChatResponse, error) {
+		last := req.Messages[len(req.Messages)-1].Content
+		switch {
+		case strings.Contains(req.Messages[0].Content, "You triage") && strings.Contains(last, "completed their task"):
+			return &provider.ChatResponse{Content: "HANDOFF TO security: The logs show a leaked key."}, nil
+		case strings.Contains(req.Messages[0].Content, "You triage"):
+			return &provider.ChatResponse{Content: "DELEGATE TO engineer: Check the logs."}, nil
+		case strings.Contains(req.Messages[0].Content, "You read logs"):
+			return &provider.ChatResponse{Content: "A key was pushed at 10:02."}, nil
+		}
+		return &provider.ChatResponse{Content: "Security here: rotate the key now."}, nil
+	}})
+	tm, err := NewTeam(spec, registry, logger.New("error"))
+	if err != nil {
+		t.Fatalf("NewTeam failed: %v", err)
+	}
+	if err := tm.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer tm.Stop()
+
+	var answers []string
+	responses := tm.Ask("Something looks wrong with our key")
+	timeout := time.After(5 * time.Second)
+	for done := false; !done; {
+		select {
+		case msg, ok := <-responses:
+			if !ok {
+				done = true
+			} else if msg.Type == MsgClientResponse {
+				answers = append(answers, msg.Content.(string))
+			}
+		case <-timeout:
+			t.Fatal("Timed out waiting for response")
+		}
+	}
+
+	// Security answers rather than the client getting triage's note
+	if len(answers) != 1 || answers[0] != "Security here: rotate the key now." {
+		t.Errorf("Expected security's answer only, got %q", answers)
+	}
+	if id := tm.ClientMemberID(); id != tm.GetMemberByRole("security").ID {
+		t.Errorf("Expected the conversation with security, got %q", id)
+	}
+}
@@ -0,0 +1,38 @@
+package team
+
+// AutoStop stops a team that has sat idle, to free its goroutines, and marks
+// it auto-stopped so it's told apart from one a user stopped. Members'
+// context is saved as it changes, and Start reloads it, so the conversation
+// carries on when the team is started again.
+func (t *Team) AutoStop() {
+	if ctx := t.runContext(); ctx != nil && ctx.Err() == nil {
+		t.Stop()
+	}
+	t.autoStopped.Store(true)
+}
+
+// Busy reports whether stopping the team now would cut work short: a member
+// is working or waiting, or a project hasn't finished
+func (t *Team) Busy() bool {
+	for _, m := range t.ListMembers() {
+		if m.GetStatus() != MemberIdle {
+			return true
+		}
+	}
+
+	t.mu.RLock()
+	o := t.orchestrator
+	t.mu.RUnlock()
+	if o == nil {
+		return false
+	}
+	project := o.project()
+	if project == nil {
+		return false
+	}
+	switch project.GetPhase() {
+	case PhaseComplete, PhaseFailed:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,385 @@
+package team
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/arcslash/ugudu/internal/config"
+	"github.com/arcslash/ugudu/internal/tools"
+)
+
+// Spec inheritance
+//
+// A spec may name a parent with `extends: base-team` and pull in fragments
+// with `includes: [roles/engineer.yaml]`. Parents are applied in order -
+// extends first, then each include - and the child is merged on top.
+//
+// Merge semantics (child wins field-by-field):
+//   - Roles are matched by role ID. A role only in the parent is inherited
+//     as-is, a role only in the child is added, and a role in both is merged
+//     so that every field the child sets replaces the parent's value.
+//   - Scalars override when non-empty; lists replace the parent's list
+//     wholesale when the child sets one.
+//   - Metadata labels, token settings and shared MCP servers are merged
+//     key-by-key.
+//
+// References are resolved relative to the including file first, then the
+// specs directory. A ".yaml" extension is added when omitted.
+
+// loadSpecFile parses a single spec file without resolving parents or applying
+// defaults. Environment variables and secret references are expanded unless
+// expand is false.
+func loadSpecFile(path string, expand bool) (*TeamSpec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read spec file: %w", err)
+	}
+	if !expand {
+		return decodeSpec(data)
+	}
+
+	// Expand environment variables and secret references
+	expanded, err := config.Expand(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("spec %s: %w", filepath.Base(path), err)
+	}
+
+	return decodeSpec([]byte(expanded))
+}
+
+// resolveSpec loads a spec and recursively merges in everything it extends or includes.
+// chain holds the files currently being resolved and is used for cycle detection.
+func resolveSpec(path string, chain []string, expand bool) (*TeamSpec, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("resolve spec path: %w", err)
+	}
+
+	for i, p := range chain {
+		if p == absPath {
+			cycle := append(append([]string{}, chain[i:]...), absPath)
+			for j := range cycle {
+				cycle[j] = filepath.Base(cycle[j])
+			}
+			return nil, fmt.Errorf("spec inheritance cycle: %s", strings.Join(cycle, " -> "))
+		}
+	}
+	chain = append(chain, absPath)
+
+	spec, err := loadSpecFile(absPath, expand)
+	if err != nil {
+		return nil, err
+	}
+
+	parents := make([]string, 0, len(spec.Includes)+1)
+	if spec.Extends != "" {
+		parents = append(parents, spec.Extends)
+	}
+	parents = append(parents, spec.Includes...)
+	if len(parents) == 0 {
+		return spec, nil
+	}
+
+	merged := &TeamSpec{}
+	for _, ref := range parents {
+		parentPath, err := resolveSpecRef(ref, filepath.Dir(absPath))
+		if err != nil {
+			return nil, err
+		}
+		parent, err := resolveSpec(parentPath, chain, expand)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", ref, err)
+		}
+		merged = mergeSpecs(merged, parent)
+	}
+
+	merged = mergeSpecs(merged, spec)
+	merged.Extends = ""
+	merged.Includes = nil
+	return merged, nil
+}
+
+// resolveSpecRef finds the file for an extends/includes reference
+func resolveSpecRef(ref, baseDir string) (string, error) {
+	candidates := []string{ref}
+	if filepath.Ext(ref) == "" {
+		candidates = []string{ref + ".yaml", ref + ".yml"}
+	}
+
+	dirs := []string{baseDir}
+	if specsDir := config.SpecsDir(); specsDir != baseDir {
+		dirs = append(dirs, specsDir)
+	}
+
+	for _, c := range candidates {
+		if filepath.IsAbs(c) {
+			if _, err := os.Stat(c); err == nil {
+				return c, nil
+			}
+			continue
+		}
+		for _, dir := range dirs {
+			p := filepath.Join(dir, c)
+			if _, err := os.Stat(p); err == nil {
+				return p, nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("spec not found: %s", ref)
+}
+
+// mergeSpecs returns parent overlaid with child; neither input is modified
+func mergeSpecs(parent, child *TeamSpec) *TeamSpec {
+	out := *parent
+
+	if child.APIVersion != "" {
+		out.APIVersion = child.APIVersion
+	}
+	if child.Kind != "" {
+		out.Kind = child.Kind
+	}
+
+	if child.Metadata.Name != "" {
+		out.Metadata.Name = child.Metadata.Name
+	}
+	if child.Metadata.Description != "" {
+		out.Metadata.Description = child.Metadata.Description
+	}
+	if len(parent.Metadata.Labels) > 0 || len(child.Metadata.Labels) > 0 {
+		labels := make(map[string]string, len(parent.Metadata.Labels)+len(child.Metadata.Labels))
+		for k, v := range parent.Metadata.Labels {
+			labels[k] = v
+		}
+		for k, v := range child.Metadata.Labels {
+			labels[k] = v
+		}
+		out.Metadata.Labels = labels
+	}
+
+	if len(child.ClientFacing) > 0 {
+		out.ClientFacing = child.ClientFacing
+	}
+
+	out.Roles = make(map[string]Role, len(parent.Roles)+len(child.Roles))
+	for id, role := range parent.Roles {
+		out.Roles[id] = role
+	}
+	for id, role := range child.Roles {
+		if base, ok := out.Roles[id]; ok {
+			out.Roles[id] = mergeRole(base, role)
+		} else {
+			out.Roles[id] = role
+		}
+	}
+
+	if child.Workflow.Pattern != "" || len(child.Workflow.Stages) > 0 || child.Workflow.AutoAssign ||
+		child.Workflow.Name != "" || len(child.Workflow.Phases) > 0 {
+		out.Workflow = child.Workflow
+	}
+	if child.Shared.Memory != (MemoryConfig{}) {
+		out.Shared.Memory = child.Shared.Memory
+	}
+	if len(child.Shared.Tools) > 0 {
+		out.Shared.Tools = child.Shared.Tools
+	}
+	if len(child.Shared.MCPServers) > 0 {
+		servers := make(map[string]tools.MCPServerConfig, len(out.Shared.MCPServers)+len(child.Shared.MCPServers))
+		for name, server := range out.Shared.MCPServers {
+			servers[name] = server
+		}
+		for name, server := range child.Shared.MCPServers {
+			servers[name] = server
+		}
+		out.Shared.MCPServers = servers
+	}
+
+	if child.Settings.Token.Mode != "" {
+		out.Settings.Token.Mode = child.Settings.Token.Mode
+	}
+	if child.Settings.Token.MaxTokens != 0 {
+		out.Settings.Token.MaxTokens = child.Settings.Token.MaxTokens
+	}
+	if child.Settings.Token.ContextHistory != 0 {
+		out.Settings.Token.ContextHistory = child.Settings.Token.ContextHistory
+	}
+	if child.Settings.Token.ContextBytes != 0 {
+		out.Settings.Token.ContextBytes = child.Settings.Token.ContextBytes
+	}
+	if child.Settings.SystemPrefix != "" {
+		out.Settings.SystemPrefix = child.Settings.SystemPrefix
+	}
+	if child.Settings.SystemSuffix != "" {
+		out.Settings.SystemSuffix = child.Settings.SystemSuffix
+	}
+	if child.Settings.IgnoreGlobalPrompt {
+		out.Settings.IgnoreGlobalPrompt = true
+	}
+	if child.Settings.PromptCaching != nil {
+		out.Settings.PromptCaching = child.Settings.PromptCaching
+	}
+	if child.Settings.Sandbox != "" {
+		out.Settings.Sandbox = child.Settings.Sandbox
+	}
+	if child.Settings.SandboxImage != "" {
+		out.Settings.SandboxImage = child.Settings.SandboxImage
+	}
+	if child.Settings.SandboxFallback {
+		out.Settings.SandboxFallback = true
+	}
+	if child.Settings.BlockTimeout != 0 {
+		out.Settings.BlockTimeout = child.Settings.BlockTimeout
+	}
+	if child.Settings.OnBlockTimeout != "" {
+		out.Settings.OnBlockTimeout = child.Settings.OnBlockTimeout
+	}
+	if child.Settings.BlockWarning != 0 {
+		out.Settings.BlockWarning = child.Settings.BlockWarning
+	}
+	if child.Settings.DelegationStagger != 0 {
+		out.Settings.DelegationStagger = child.Settings.DelegationStagger
+	}
+	if child.Settings.MaxParallelDelegation != 0 {
+		out.Settings.MaxParallelDelegation = child.Settings.MaxParallelDelegation
+	}
+	if child.Settings.MaxParallelStories != 0 {
+		out.Settings.MaxParallelStories = child.Settings.MaxParallelStories
+	}
+	if child.Settings.RequiresApproval != nil {
+		out.Settings.RequiresApproval = child.Settings.RequiresApproval
+	}
+	if child.Settings.ApprovalTimeout != 0 {
+		out.Settings.ApprovalTimeout = child.Settings.ApprovalTimeout
+	}
+	if child.Settings.IdleStop != 0 {
+		out.Settings.IdleStop = child.Settings.IdleStop
+	}
+	if child.Settings.RequestTimeout != 0 {
+		out.Settings.RequestTimeout = child.Settings.RequestTimeout
+	}
+	out.Settings.Env = mergeEnv(parent.Settings.Env, child.Settings.Env)
+	if child.Settings.Editor.Enabled || child.Settings.Editor.Instructions != "" || child.Settings.Editor.Model.Provider != "" {
+		out.Settings.Editor = child.Settings.Editor
+	}
+	if child.Settings.KeepToolResults {
+		out.Settings.KeepToolResults = true
+	}
+	if child.Settings.Timezone != "" {
+		out.Settings.Timezone = child.Settings.Timezone
+	}
+
+	return &out
+}
+
+// mergeRole overlays the fields set on child onto parent
+func mergeRole(parent, child Role) Role {
+	out := parent
+
+	if child.Title != "" {
+		out.Title = child.Title
+	}
+	if child.Name != "" {
+		out.Name = child.Name
+	}
+	if child.Names != nil {
+		out.Names = child.Names
+	}
+	if child.Count != 0 {
+		out.Count = child.Count
+	}
+	if child.Visibility != "" {
+		out.Visibility = child.Visibility
+	}
+	out.Model = mergeModel(parent.Model, child.Model)
+	if child.Persona != "" {
+		out.Persona = child.Persona
+	}
+	if child.PersonaCondensed != "" {
+		out.PersonaCondensed = child.PersonaCondensed
+	}
+	if child.Responsibilities != nil {
+		out.Responsibilities = child.Responsibilities
+	}
+	if child.Skills != nil {
+		out.Skills = child.Skills
+	}
+	if child.Tools != nil {
+		out.Tools = child.Tools
+	}
+	if child.ReportsTo != "" {
+		out.ReportsTo = child.ReportsTo
+	}
+	if child.CanDelegate != nil {
+		out.CanDelegate = child.CanDelegate
+	}
+	if child.CanHandoff != nil {
+		out.CanHandoff = child.CanHandoff
+	}
+	out.Env = mergeEnv(parent.Env, child.Env)
+
+	return out
+}
+
+// mergeEnv returns parent's variables overlaid with child's
+func mergeEnv(parent, child map[string]string) map[string]string {
+	if len(parent) == 0 || len(child) == 0 {
+		if len(child) > 0 {
+			return child
+		}
+		return parent
+	}
+	env := make(map[string]string, len(parent)+len(child))
+	for k, v := range parent {
+		env[k] = v
+	}
+	for k, v := range child {
+		env[k] = v
+	}
+	return env
+}
+
+// mergeModel overlays the model settings set on child onto parent
+func mergeModel(parent, child ModelConfig) ModelConfig {
+	out := parent
+
+	if child.Provider != "" {
+		out.Provider = child.Provider
+	}
+	if child.Model != "" {
+		out.Model = child.Model
+	}
+	if child.Temperature != nil {
+		out.Temperature = child.Temperature
+	}
+	if child.MaxTokens != nil {
+		out.MaxTokens = child.MaxTokens
+	}
+	if child.TopP != nil {
+		out.TopP = child.TopP
+	}
+	if child.Stop != nil {
+		out.Stop = child.Stop
+	}
+	if child.Fallback != nil {
+		out.Fallback = child.Fallback
+	}
+	if child.LowTokenModel != "" {
+		out.LowTokenModel = child.LowTokenModel
+	}
+	if child.Models != nil {
+		out.Models = make(map[TokenMode]string, len(parent.Models)+len(child.Models))
+		for mode, model := range parent.Models {
+			out.Models[mode] = model
+		}
+		for mode, model := range child.Models {
+			out.Models[mode] = model
+		}
+	}
+	if child.OpenRouter != nil {
+		out.OpenRouter = child.OpenRouter
+	}
+
+	return out
+}
@@ -0,0 +1,222 @@
+package team
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeSpecFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	return dir
+}
+
+const baseTeamSpec = `
+metadata:
+  name: base-team
+  description: Shared base
+  labels:
+    tier: base
+client_facing:
+  - lead
+settings:
+  token:
+    mode: low
+    context_history: 20
+roles:
+  lead:
+    title: Team Lead
+    visibility: client
+    model:
+      provider: anthropic
+      model: claude-sonnet-4-20250514
+    persona: You are the lead.
+    can_delegate:
+      - engineer
+  engineer:
+    title: Engineer
+    count: 2
+    model:
+      provider: anthropic
+      model: claude-sonnet-4-20250514
+    persona: You are an engineer.
+    skills:
+      - go
+`
+
+func TestLoadSpecExtendsOverride(t *testing.T) {
+	dir := writeSpecFiles(t, map[string]string{
+		"base-team.yaml": baseTeamSpec,
+		"child.yaml": `
+extends: base-team
+metadata:
+  name: child-team
+  labels:
+    owner: web
+settings:
+  token:
+    max_tokens: 2048
+roles:
+  engineer:
+    model:
+      model: gpt-4o
+      provider: openai
+    count: 3
+`,
+	})
+
+	spec, err := LoadSpec(filepath.Join(dir, "child.yaml"))
+	if err != nil {
+		t.Fatalf("LoadSpec failed: %v", err)
+	}
+
+	if spec.Metadata.Name != "child-team" {
+		t.Errorf("Expected name 'child-team', got '%s'", spec.Metadata.Name)
+	}
+	if spec.Metadata.Description != "Shared base" {
+		t.Errorf("Expected inherited description, got '%s'", spec.Metadata.Description)
+	}
+	if spec.Metadata.Labels["tier"] != "base" || spec.Metadata.Labels["owner"] != "web" {
+		t.Errorf("Expected merged labels, got %v", spec.Metadata.Labels)
+	}
+	if spec.Extends != "" {
+		t.Errorf("Expected extends to be cleared on resolved spec, got '%s'", spec.Extends)
+	}
+
+	eng := spec.Roles["engineer"]
+	if eng.Count != 3 {
+		t.Errorf("Expected engineer count 3, got %d", eng.Count)
+	}
+	if eng.Model.Provider != "openai" || eng.Model.Model != "gpt-4o" {
+		t.Errorf("Expected overridden model openai/gpt-4o, got %s/%s", eng.Model.Provider, eng.Model.Model)
+	}
+	if eng.Persona != "You are an engineer." {
+		t.Errorf("Expected inherited persona, got '%s'", eng.Persona)
+	}
+	if eng.Title != "Engineer" || len(eng.Skills) != 1 {
+		t.Errorf("Expected inherited title and skills, got '%s' %v", eng.Title, eng.Skills)
+	}
+
+	lead, ok := spec.Roles["lead"]
+	if !ok {
+		t.Fatal("Expected inherited 'lead' role")
+	}
+	if lead.Visibility != "client" {
+		t.Errorf("Expected lead visibility 'client', got '%s'", lead.Visibility)
+	}
+
+	if spec.Settings.Token.Mode != TokenModeLow || spec.Settings.Token.ContextHistory != 20 || spec.Settings.Token.MaxTokens != 2048 {
+		t.Errorf("Expected merged token settings, got %+v", spec.Settings.Token)
+	}
+}
+
+func TestLoadSpecIncludesAddRoles(t *testing.T) {
+	dir := writeSpecFiles(t, map[string]string{
+		"roles/qa.yaml": `
+roles:
+  qa:
+    title: QA Engineer
+    model:
+      provider: anthropic
+      model: claude-sonnet-4-20250514
+    persona: You test things.
+`,
+		"team.yaml": `
+includes:
+  - roles/qa.yaml
+metadata:
+  name: team
+roles:
+  lead:
+    title: Lead
+    visibility: client
+    model:
+      provider: anthropic
+      model: claude-sonnet-4-20250514
+    persona: You lead.
+`,
+	})
+
+	spec, err := LoadSpec(filepath.Join(dir, "team.yaml"))
+	if err != nil {
+		t.Fatalf("LoadSpec failed: %v", err)
+	}
+
+	if len(spec.Roles) != 2 {
+		t.Fatalf("Expected 2 roles, got %d", len(spec.Roles))
+	}
+	qa, ok := spec.Roles["qa"]
+	if !ok {
+		t.Fatal("Expected included 'qa' role")
+	}
+	// Defaults apply after the merge
+	if qa.Count != 1 || qa.Visibility != "internal" {
+		t.Errorf("Expected defaults on included role, got count=%d visibility=%s", qa.Count, qa.Visibility)
+	}
+}
+
+func TestLoadSpecInheritanceCycle(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		entry string
+	}{
+		{
+			name: "self reference",
+			files: map[string]string{
+				"a.yaml": "extends: a\nmetadata:\n  name: a\n",
+			},
+			entry: "a.yaml",
+		},
+		{
+			name: "two spec cycle",
+			files: map[string]string{
+				"a.yaml": "extends: b\nmetadata:\n  name: a\n",
+				"b.yaml": "extends: a\nmetadata:\n  name: b\n",
+			},
+			entry: "a.yaml",
+		},
+		{
+			name: "cycle through includes",
+			files: map[string]string{
+				"a.yaml":       "includes: [parts/b.yaml]\nmetadata:\n  name: a\n",
+				"parts/b.yaml": "includes: [../a.yaml]\n",
+			},
+			entry: "a.yaml",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := writeSpecFiles(t, tt.files)
+			_, err := LoadSpec(filepath.Join(dir, tt.entry))
+			if err == nil {
+				t.Fatal("Expected cycle error, got nil")
+			}
+			if !strings.Contains(err.Error(), "cycle") {
+				t.Errorf("Expected cycle error, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestLoadSpecMissingParent(t *testing.T) {
+	dir := writeSpecFiles(t, map[string]string{
+		"child.yaml": "extends: does-not-exist-anywhere\nmetadata:\n  name: child\n",
+	})
+
+	if _, err := LoadSpec(filepath.Join(dir, "child.yaml")); err == nil {
+		t.Fatal("Expected error for missing parent spec")
+	}
+}
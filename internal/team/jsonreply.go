@@ -0,0 +1,125 @@
+package team
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/arcslash/ugudu/internal/provider"
+)
+
+// errNoJSONArray is returned when a reply has no JSON array at all
+var errNoJSONArray = errors.New("no JSON array in the reply")
+
+// codeFence matches a fenced code block, capturing its contents
+var codeFence = regexp.MustCompile("(?s)```[a-zA-Z]*\\s*\\n(.*?)```")
+
+// jsonRetryPrompt asks a member to resend a reply that didn't parse
+const jsonRetryPrompt = `Your reply couldn't be read as JSON (%v).
+
+Reply again with only the JSON array: no prose, no code fences, nothing before the opening [ or after the closing ].`
+
+// decodeJSONArray decodes the first JSON array in a model's reply into v.
+// Fenced code blocks are tried before the rest of the reply, and each
+// balanced [...] is tried in turn, so prose with stray brackets around the
+// array doesn't hide it.
+func decodeJSONArray(content string, v interface{}) error {
+	texts := make([]string, 0, 2)
+	for _, m := range codeFence.FindAllStringSubmatch(content, -1) {
+		texts = append(texts, m[1])
+	}
+	texts = append(texts, content)
+
+	var lastErr error
+	for _, text := range texts {
+		for i := 0; i < len(text); i++ {
+			if text[i] != '[' {
+				continue
+			}
+			end := matchingBracket(text, i)
+			if end < 0 {
+				continue
+			}
+			err := json.Unmarshal([]byte(text[i:end+1]), v)
+			if err == nil {
+				return nil
+			}
+			lastErr = err
+		}
+	}
+	if lastErr != nil {
+		return fmt.Errorf("invalid JSON array: %w", lastErr)
+	}
+	return errNoJSONArray
+}
+
+// matchingBracket returns the index of the ] closing the [ at start,
+// skipping brackets inside JSON strings, or -1 if it isn't closed
+func matchingBracket(text string, start int) int {
+	depth := 0
+	inString, escaped := false, false
+	for i := start; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case escaped:
+			escaped = false
+		case inString && c == '\\':
+			escaped = true
+		case c == '"':
+			inString = !inString
+		case inString:
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// chatJSON sends a member a prompt whose reply should hold a JSON array and
+// hands the reply to decode. A reply that doesn't decode gets one retry
+// asking for the JSON alone; if that fails too, the failure is posted to the
+// project rather than leaving it to stall quietly. It returns an error only
+// when the chat itself fails.
+func (o *Orchestrator) chatJSON(ctx context.Context, m *Member, project *Project, what, prompt string, decode func(string) error) error {
+	messages := []provider.Message{
+		{Role: "system", Content: m.buildSystemPrompt()},
+		{Role: "user", Content: prompt},
+	}
+
+	for attempt := 1; ; attempt++ {
+		resp, err := m.chat(ctx, &provider.ChatRequest{
+			Model:       m.getEffectiveModel(),
+			Temperature: m.Role.Model.Temperature,
+			MaxTokens:   m.getEffectiveMaxTokens(),
+			Messages:    messages,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = decode(resp.Content)
+		if err == nil {
+			return nil
+		}
+		if attempt == 2 {
+			o.logger.Error("couldn't parse reply", "project", project.ID, "member", m.ID, "what", what, "error", err)
+			project.AddCommunication("status_update", "system", "all",
+				fmt.Sprintf("Couldn't read the %s from %s: %v", what, m.ID, err),
+				map[string]interface{}{"member": m.ID, "reply": resp.Content})
+			return nil
+		}
+
+		o.logger.Warn("reply wasn't valid JSON, asking again", "project", project.ID, "member", m.ID, "what", what, "error", err)
+		messages = append(messages,
+			provider.Message{Role: "assistant", Content: resp.Content},
+			provider.Message{Role: "user", Content: fmt.Sprintf(jsonRetryPrompt, err)},
+		)
+	}
+}
@@ -0,0 +1,194 @@
+package team
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arcslash/ugudu/internal/logger"
+	"github.com/arcslash/ugudu/internal/provider"
+)
+
+func TestParseRequirements(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string // Titles
+		wantErr bool
+	}{
+		{
+			name:    "fenced",
+			content: "Here you go:\n```json\n[{\"title\": \"Login\", \"priority\": \"must\"}]\n```\nLet me know [if] anything's missing.",
+			want:    []string{"Login"},
+		},
+		{
+			name:    "prose with stray brackets",
+			content: "Requirements [draft]:\n[{\"title\": \"Login\"}, {\"title\": \"Logout [optional]\"}]\nSee [1] for details.",
+			want:    []string{"Login", "Logout [optional]"},
+		},
+		{
+			name:    "bare",
+			content: `[{"title": "Login", "description": "Email and password"}]`,
+			want:    []string{"Login"},
+		},
+		{
+			name:    "truncated",
+			content: `[{"title": "Login"}, {"title": "Log`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid",
+			content: `[{title: Login}]`,
+			wantErr: true,
+		},
+		{
+			name:    "no JSON",
+			content: "I need more details before writing requirements.",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reqs, err := parseRequirements(tt.content, "ba-1")
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Expected an error, got %v", reqs)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseRequirements failed: %v", err)
+			}
+			var titles []string
+			for _, r := range reqs {
+				titles = append(titles, r.Title)
+			}
+			if strings.Join(titles, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("Expected %v, got %v", tt.want, titles)
+			}
+		})
+	}
+
+	if _, err := parseRequirements("no brackets here", "ba-1"); !errors.Is(err, errNoJSONArray) {
+		t.Errorf("Expected errNoJSONArray, got %v", err)
+	}
+}
+
+func TestParseStories_Fenced(t *testing.T) {
+	stories, err := parseStories("```\n[{\"title\": \"Form\", \"acceptance_criteria\": [\"Validates email\"]}]\n```")
+	if err != nil {
+		t.Fatalf("parseStories failed: %v", err)
+	}
+	if len(stories) != 1 || len(stories[0].AcceptanceCriteria) != 1 || stories[0].Status != StoryBacklog {
+		t.Errorf("Unexpected stories: %+v", stories)
+	}
+}
+
+func TestParseStories_DependsOn(t *testing.T) {
+	stories, err := parseStories(`[
+		{"title": "Schema", "depends_on": ["API"]},
+		{"title": "API", "depends_on": ["Schema", "Unknown"]},
+		{"title": "UI", "depends_on": ["Schema", "API", "UI"]}
+	]`)
+	if err != nil {
+		t.Fatalf("parseStories failed: %v", err)
+	}
+	if len(stories) != 3 {
+		t.Fatalf("Expected 3 stories, got %d", len(stories))
+	}
+
+	// Only earlier stories count, so later, unknown and self references are dropped
+	schema, api, ui := stories[0], stories[1], stories[2]
+	if len(schema.DependsOn) != 0 {
+		t.Errorf("Expected Schema to depend on nothing, got %v", schema.DependsOn)
+	}
+	if !reflect.DeepEqual(api.DependsOn, []string{schema.ID}) {
+		t.Errorf("Expected API to depend on Schema, got %v", api.DependsOn)
+	}
+	if !reflect.DeepEqual(ui.DependsOn, []string{schema.ID, api.ID}) {
+		t.Errorf("Expected UI to depend on Schema and API, got %v", ui.DependsOn)
+	}
+}
+
+// runTradingProject runs tradingSpec's workflow with the researcher's
+// requirements replies coming from reply, and waits for it to complete
+func runTradingProject(t *testing.T, reply func(attempt int) string) *Project {
+	t.Helper()
+
+	attempts := 0
+	registry := provider.NewRegistry()
+	registry.Register(&MockProvider{ChatFunc: func(req *provider.ChatRequest) (*provider.ChatResponse, error) {
+		prompt := req.Messages[len(req.Messages)-1].Content
+		switch {
+		case strings.Contains(prompt, "leading this project"):
+			return &provider.ChatResponse{Content: "## Summary\nRebalance"}, nil
+		case strings.Contains(prompt, "create detailed requirements"), strings.Contains(prompt, "couldn't be read as JSON"):
+			attempts++
+			return &provider.ChatResponse{Content: reply(attempts)}, nil
+		}
+		return &provider.ChatResponse{Content: "Orders placed."}, nil
+	}})
+
+	tm, err := NewTeam(tradingSpec(), registry, logger.New("error"))
+	if err != nil {
+		t.Fatalf("NewTeam failed: %v", err)
+	}
+	if err := tm.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	t.Cleanup(tm.Stop)
+
+	if _, err := tm.StartProject("Move the portfolio to 60/40"); err != nil {
+		t.Fatalf("StartProject failed: %v", err)
+	}
+	project := tm.Orchestrator().activeProject
+
+	deadline := time.Now().Add(5 * time.Second)
+	for project.GetPhase() != PhaseComplete {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the project to complete, still %s", project.GetPhase())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	return project
+}
+
+func TestOrchestrator_RetriesInvalidJSON(t *testing.T) {
+	project := runTradingProject(t, func(attempt int) string {
+		if attempt == 1 {
+			return "Sure! The requirements are: sell equities, buy bonds."
+		}
+		return `[{"title": "Sell equities", "priority": "must"}, {"title": "Buy bonds", "priority": "must"}]`
+	})
+
+	if len(project.Requirements) != 2 {
+		t.Errorf("Expected the retry's 2 requirements, got %d", len(project.Requirements))
+	}
+	for _, c := range project.Communications {
+		if strings.Contains(c.Content, "Couldn't read") {
+			t.Errorf("Expected no parse failure after a good retry, got %q", c.Content)
+		}
+	}
+}
+
+func TestOrchestrator_ReportsInvalidJSON(t *testing.T) {
+	project := runTradingProject(t, func(attempt int) string {
+		return "[sell equities, buy bonds]"
+	})
+
+	if len(project.Requirements) != 0 {
+		t.Errorf("Expected no requirements, got %d", len(project.Requirements))
+	}
+	found := false
+	for _, c := range project.Communications {
+		if c.Type == "status_update" && strings.Contains(c.Content, "Couldn't read the requirements") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected the parse failure posted to the project, got %+v", project.Communications)
+	}
+}
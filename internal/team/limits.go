@@ -0,0 +1,107 @@
+package team
+
+import (
+	"errors"
+	"time"
+	"unsafe"
+)
+
+// Default resource limits for a team
+const (
+	DefaultMaxMembers = 25
+	DefaultInboxSize  = 100
+	DefaultOutboxSize = 100
+
+	// DefaultMaxToolResult is the size in bytes a single tool result may take
+	// up in a member's context
+	DefaultMaxToolResult = 64 * 1024
+
+	DefaultClientChanSize   = 100
+	DefaultInternalChanSize = 1000
+
+	// DefaultOverflowTimeout is how long the block policy waits for room in
+	// a full channel before giving up on the message
+	DefaultOverflowTimeout = 30 * time.Second
+)
+
+// ErrTooManyMembers is returned when a spec asks for more members than the
+// team limit allows
+var ErrTooManyMembers = errors.New("too many team members")
+
+// Limits caps the resources a single team may use. Zero fields use the
+// defaults.
+type Limits struct {
+	MaxMembers int // Members across all roles
+	InboxSize  int // Buffered messages per member inbox
+	OutboxSize int // Buffered messages per member outbox
+
+	MaxToolResult int // Bytes of a tool result kept in a member's context
+
+	ClientChanSize   int // Buffered replies waiting for the client
+	InternalChanSize int // Buffered messages between members
+
+	// What to do when a member inbox or the internal channel is full
+	// (default drop) and when the client channel is full (default block, so
+	// replies aren't lost)
+	Overflow        OverflowPolicy
+	ClientOverflow  OverflowPolicy
+	OverflowTimeout time.Duration // How long the block policy waits
+}
+
+func (l Limits) withDefaults() Limits {
+	if l.MaxMembers <= 0 {
+		l.MaxMembers = DefaultMaxMembers
+	}
+	if l.InboxSize <= 0 {
+		l.InboxSize = DefaultInboxSize
+	}
+	if l.OutboxSize <= 0 {
+		l.OutboxSize = DefaultOutboxSize
+	}
+	if l.MaxToolResult <= 0 {
+		l.MaxToolResult = DefaultMaxToolResult
+	}
+	if l.ClientChanSize <= 0 {
+		l.ClientChanSize = DefaultClientChanSize
+	}
+	if l.InternalChanSize <= 0 {
+		l.InternalChanSize = DefaultInternalChanSize
+	}
+	if l.Overflow == "" {
+		l.Overflow = OverflowDrop
+	}
+	if l.ClientOverflow == "" {
+		l.ClientOverflow = OverflowBlock
+	}
+	if l.OverflowTimeout <= 0 {
+		l.OverflowTimeout = DefaultOverflowTimeout
+	}
+	return l
+}
+
+// TeamOption configures a team at creation
+type TeamOption func(*Team)
+
+// WithLimits sets the team's resource limits
+func WithLimits(limits Limits) TeamOption {
+	return func(t *Team) {
+		t.limits = limits.withDefaults()
+	}
+}
+
+// specMemberCount returns how many members a spec creates
+func specMemberCount(spec *TeamSpec) int {
+	total := 0
+	for _, role := range spec.Roles {
+		total += role.Count
+	}
+	return total
+}
+
+// channelBufferBytes estimates the memory preallocated for the team's
+// message channels. It counts buffer slots only, not message contents.
+func (t *Team) channelBufferBytes(members int) int {
+	limits := t.limits.withDefaults()
+	slots := members*(limits.InboxSize+limits.OutboxSize) + cap(t.clientChan) + cap(t.internalChan)
+	return slots * int(unsafe.Sizeof(Message{}))
+}
@@ -0,0 +1,255 @@
+package team
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/arcslash/ugudu/internal/logger"
+	"github.com/arcslash/ugudu/internal/provider"
+)
+
+func limitsSpec(engineers int) *TeamSpec {
+	return &TeamSpec{
+		Metadata:     Metadata{Name: "big-team"},
+		ClientFacing: []string{"pm"},
+		Roles: map[string]Role{
+			"pm":       {Title: "PM", Count: 1, Model: ModelConfig{Provider: "mock"}},
+			"engineer": {Title: "Engineer", Count: engineers, Model: ModelConfig{Provider: "mock"}},
+		},
+	}
+}
+
+func TestNewTeam_MaxMembers(t *testing.T) {
+	registry := provider.NewRegistry()
+	registry.Register(&MockProvider{})
+	log := logger.New("error")
+
+	// 1 PM + 50 engineers is over the default limit
+	_, err := NewTeam(limitsSpec(50), registry, log)
+	if !errors.Is(err, ErrTooManyMembers) {
+		t.Fatalf("Expected ErrTooManyMembers, got %v", err)
+	}
+
+	// Exactly at a custom limit is fine
+	tm, err := NewTeam(limitsSpec(4), registry, log, WithLimits(Limits{MaxMembers: 5}))
+	if err != nil {
+		t.Fatalf("Expected team at the limit to be created, got %v", err)
+	}
+	if len(tm.ListMembers()) != 5 {
+		t.Errorf("Expected 5 members, got %d", len(tm.ListMembers()))
+	}
+
+	if _, err := NewTeam(limitsSpec(5), registry, log, WithLimits(Limits{MaxMembers: 5})); !errors.Is(err, ErrTooManyMembers) {
+		t.Errorf("Expected ErrTooManyMembers over a custom limit, got %v", err)
+	}
+}
+
+func TestNewTeam_ChannelSizes(t *testing.T) {
+	registry := provider.NewRegistry()
+	registry.Register(&MockProvider{})
+
+	tm, err := NewTeam(limitsSpec(1), registry, logger.New("error"), WithLimits(Limits{InboxSize: 8, OutboxSize: 4}))
+	if err != nil {
+		t.Fatalf("NewTeam failed: %v", err)
+	}
+
+	member := tm.GetMember("pm")
+	if cap(member.inbox) != 8 || cap(member.outbox) != 4 {
+		t.Errorf("Expected inbox 8 and outbox 4, got %d and %d", cap(member.inbox), cap(member.outbox))
+	}
+
+	status := tm.Status()
+	if status["member_count"] != 2 || status["max_members"] != DefaultMaxMembers {
+		t.Errorf("Expected member count and default limit in status, got %v and %v", status["member_count"], status["max_members"])
+	}
+
+	// Two members with 12 slots each, plus the team's shared channels
+	smaller := status["channel_buffer_bytes"].(int)
+	tm2, _ := NewTeam(limitsSpec(1), registry, logger.New("error"))
+	if larger := tm2.Status()["channel_buffer_bytes"].(int); smaller <= 0 || smaller >= larger {
+		t.Errorf("Expected smaller buffers to report less memory, got %d vs %d", smaller, larger)
+	}
+}
+
+func TestRouteMessage_OverflowPolicy(t *testing.T) {
+	registry := provider.NewRegistry()
+	registry.Register(&MockProvider{})
+
+	tests := []struct {
+		policy    OverflowPolicy
+		drain     bool // Free up room while the sender waits
+		wantErr   bool
+		wantDrops int64
+	}{
+		{policy: OverflowDrop, wantDrops: 1},
+		{policy: OverflowError, wantErr: true, wantDrops: 1},
+		{policy: OverflowBlock, drain: true},
+		{policy: OverflowBlock, wantErr: true, wantDrops: 1},
+	}
+
+	for _, tt := range tests {
+		name := string(tt.policy)
+		if tt.drain {
+			name += " drained"
+		}
+		t.Run(name, func(t *testing.T) {
+			tm, err := NewTeam(limitsSpec(1), registry, logger.New("error"), WithLimits(Limits{
+				ClientChanSize:  1,
+				ClientOverflow:  tt.policy,
+				OverflowTimeout: 50 * time.Millisecond,
+			}))
+			if err != nil {
+				t.Fatalf("NewTeam failed: %v", err)
+			}
+
+			// Nothing is reading, so the first reply fills the channel
+			if err := tm.RouteMessage(Message{To: "client", Content: "first"}); err != nil {
+				t.Fatalf("Expected the first reply to fit, got %v", err)
+			}
+			if tt.drain {
+				go func() {
+					time.Sleep(10 * time.Millisecond)
+					<-tm.clientChan
+				}()
+			}
+
+			err = tm.RouteMessage(Message{To: "client", Content: "second"})
+			if gotErr := errors.Is(err, ErrChannelFull); gotErr != tt.wantErr {
+				t.Errorf("Expected ErrChannelFull %v, got %v", tt.wantErr, err)
+			}
+			if drops := tm.DroppedMessages()["client"]; drops != tt.wantDrops {
+				t.Errorf("Expected %d dropped, got %d", tt.wantDrops, drops)
+			}
+		})
+	}
+
+	// Member inboxes follow the team's general policy
+	tm, _ := NewTeam(limitsSpec(1), registry, logger.New("error"), WithLimits(Limits{InboxSize: 1, Overflow: OverflowError}))
+	pm := tm.GetMember("pm")
+	if err := pm.Send(Message{Content: "first"}); err != nil {
+		t.Fatalf("Expected the first message to fit, got %v", err)
+	}
+	if err := pm.Send(Message{Content: "second"}); !errors.Is(err, ErrChannelFull) {
+		t.Errorf("Expected ErrChannelFull from a full inbox, got %v", err)
+	}
+	if drops := tm.Status()["dropped_messages"].(map[string]int64)["inbox"]; drops != 1 {
+		t.Errorf("Expected 1 inbox drop in status, got %d", drops)
+	}
+}
+
+func TestMember_ToolResultTruncated(t *testing.T) {
+	registry := provider.NewRegistry()
+	registry.Register(&MockProvider{})
+
+	tm, err := NewTeam(limitsSpec(1), registry, logger.New("error"), WithLimits(Limits{MaxToolResult: 1000}))
+	if err != nil {
+		t.Fatalf("NewTeam failed: %v", err)
+	}
+	engineer := tm.MembersByRole["engineer"][0]
+
+	path := filepath.Join(t.TempDir(), "big.log")
+	var lines []string
+	for i := 1; i <= 2000; i++ {
+		lines = append(lines, "log line "+strings.Repeat("x", 40))
+	}
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatalf("Write file failed: %v", err)
+	}
+
+	read := func(args map[string]interface{}) string {
+		t.Helper()
+		raw, _ := json.Marshal(args)
+		results, err := engineer.executeToolCalls(context.Background(), []provider.ToolCall{
+			{ID: "call-1", Name: "read_file", Arguments: string(raw)},
+		})
+		if err != nil || len(results) != 1 {
+			t.Fatalf("executeToolCalls failed: %v %v", results, err)
+		}
+		return results[0].Content
+	}
+
+	whole := read(map[string]interface{}{"path": path})
+	if len(whole) > 1100 {
+		t.Errorf("Expected the result cut to about 1000 bytes, got %d", len(whole))
+	}
+	if !strings.Contains(whole, "[output truncated, ") || !strings.HasPrefix(whole, "{") || !strings.HasSuffix(whole, "}") {
+		t.Errorf("Expected the head, a truncation marker and the tail, got %q", whole)
+	}
+
+	// A page of the file fits and says where it is
+	var page map[string]interface{}
+	if err := json.Unmarshal([]byte(read(map[string]interface{}{"path": path, "offset": 11, "limit": 5})), &page); err != nil {
+		t.Fatalf("Expected an untruncated page: %v", err)
+	}
+	if page["start_line"] != float64(11) || page["end_line"] != float64(15) || page["total_lines"] != float64(2000) {
+		t.Errorf("Expected lines 11-15 of 2000, got %v", page)
+	}
+	if n := strings.Count(page["content"].(string), "\n"); n != 5 {
+		t.Errorf("Expected 5 lines, got %d", n)
+	}
+}
+
+func TestDelegation_FullInboxFails(t *testing.T) {
+	registry := provider.NewRegistry()
+	registry.Register(&MockProvider{})
+
+	// The default drop policy, with nothing draining the engineer's inbox
+	tm, err := NewTeam(limitsSpec(1), registry, logger.New("error"), WithLimits(Limits{InboxSize: 1}))
+	if err != nil {
+		t.Fatalf("NewTeam failed: %v", err)
+	}
+	var mu sync.Mutex
+	var activities []string
+	tm.persistence = &PersistenceCallbacks{
+		OnActivity: func(_, _, activityType, _, _ string, _ map[string]interface{}) {
+			mu.Lock()
+			activities = append(activities, activityType)
+			mu.Unlock()
+		},
+	}
+	engineer := tm.GetMemberByRole("engineer")
+	if err := engineer.Send(Message{Type: MsgClientRequest, Content: "filler"}); err != nil {
+		t.Fatalf("Expected the first message to fit, got %v", err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		tm.GetMember("pm").handleDelegation(context.Background(), responseAction{Type: "delegate", Target: "engineer", Content: "Build the login page"}, Message{})
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Delegation to a full inbox is still waiting for a result")
+	}
+
+	tasks := tm.ListTasks()
+	if len(tasks) != 1 || tasks[0].Status != TaskFailed || !strings.Contains(tasks[0].Result.Error, ErrChannelFull.Error()) {
+		t.Fatalf("Expected the undelivered task to fail with a full inbox, got %+v", tasks)
+	}
+	select {
+	case msg := <-tm.clientChan:
+		if content, _ := msg.Content.(string); !strings.HasPrefix(content, "Task failed:") {
+			t.Errorf("Expected the client told the task failed, got %q", content)
+		}
+	default:
+		t.Error("Expected the client told the task failed")
+	}
+
+	// Nothing claims the engineer got the task
+	mu.Lock()
+	defer mu.Unlock()
+	for _, a := range activities {
+		if a == "delegation" || a == "task_received" {
+			t.Errorf("Expected no delegation activity for an undelivered task, got %v", activities)
+			break
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package team
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Lint rules. These are stable identifiers; messages may change.
+const (
+	LintNoDelegation     = "no-delegation"     // Client-facing role can't hand work to anyone
+	LintSelfReport       = "self-report"       // Role reports to itself
+	LintDuplicatePersona = "duplicate-persona" // Two roles share the same persona
+	LintNoInstruction    = "no-instruction"    // Persona describes a role but never says what to do
+)
+
+// LintWarning is a spec quality issue. Unlike load errors, warnings are
+// heuristics and never stop a team from being created.
+type LintWarning struct {
+	Rule    string
+	Role    string
+	Message string
+}
+
+func (w LintWarning) String() string {
+	return fmt.Sprintf("%s: %s (%s)", w.Role, w.Message, w.Rule)
+}
+
+// Phrases that tell a member how to behave rather than who they are
+var instructionMarkers = []string{
+	"always", "never", "must", "should", "make sure", "ensure", "do not",
+	"don't", "avoid", "focus on", "when ", "your job", "your role is to",
+	"you will", "responsible for", "prefer",
+}
+
+// LintSpec checks a loaded spec for roles that are likely to behave badly:
+// leads that can't delegate, circular reporting, copy-pasted personas and
+// personas with no instructions. Warnings are sorted by role.
+func LintSpec(spec *TeamSpec) []LintWarning {
+	var warnings []LintWarning
+
+	roleIDs := make([]string, 0, len(spec.Roles))
+	for id := range spec.Roles {
+		roleIDs = append(roleIDs, id)
+	}
+	sort.Strings(roleIDs)
+
+	clientFacing := make(map[string]bool)
+	for _, id := range spec.ClientFacing {
+		clientFacing[id] = true
+	}
+	if len(spec.ClientFacing) == 0 {
+		for id, role := range spec.Roles {
+			if role.Visibility == "client" {
+				clientFacing[id] = true
+			}
+		}
+	}
+
+	personas := make(map[string]string) // normalized persona -> first role using it
+
+	for _, id := range roleIDs {
+		role := spec.Roles[id]
+
+		if clientFacing[id] && len(spec.Roles) > 1 && len(role.CanDelegate) == 0 {
+			warnings = append(warnings, LintWarning{
+				Rule:    LintNoDelegation,
+				Role:    id,
+				Message: "is client-facing but has no can_delegate, so it must do all the work itself",
+			})
+		}
+
+		if role.ReportsTo == id {
+			warnings = append(warnings, LintWarning{
+				Rule:    LintSelfReport,
+				Role:    id,
+				Message: "reports_to itself",
+			})
+		}
+
+		persona := normalizePersona(role.Persona)
+		if persona == "" {
+			warnings = append(warnings, LintWarning{
+				Rule:    LintNoInstruction,
+				Role:    id,
+				Message: "has no persona",
+			})
+			continue
+		}
+
+		if first, ok := personas[persona]; ok {
+			warnings = append(warnings, LintWarning{
+				Rule:    LintDuplicatePersona,
+				Role:    id,
+				Message: fmt.Sprintf("has the same persona as %s", first),
+			})
+		} else {
+			personas[persona] = id
+		}
+
+		if !hasInstruction(role.Persona) {
+			warnings = append(warnings, LintWarning{
+				Rule:    LintNoInstruction,
+				Role:    id,
+				Message: "persona doesn't tell the member what to do (add a list of duties or rules like \"always\"/\"never\")",
+			})
+		}
+	}
+
+	return warnings
+}
+
+// normalizePersona collapses case and whitespace so reformatted copies compare equal
+func normalizePersona(persona string) string {
+	return strings.ToLower(strings.Join(strings.Fields(persona), " "))
+}
+
+// hasInstruction reports whether a persona contains a list item or a
+// directive phrase
+func hasInstruction(persona string) bool {
+	for _, line := range strings.Split(persona, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "- ") || strings.HasPrefix(line, "* ") {
+			return true
+		}
+		if len(line) > 2 && line[0] >= '0' && line[0] <= '9' && (line[1] == '.' || line[1] == ')') {
+			return true
+		}
+	}
+
+	lower := strings.ToLower(persona)
+	for _, marker := range instructionMarkers {
+		if strings.Contains(lower, marker) {
+			return true
+		}
+	}
+	return false
+}
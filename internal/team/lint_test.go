@@ -0,0 +1,134 @@
+package team
+
+import (
+	"testing"
+)
+
+const lintPersona = `You are an engineer.
+- Write tests first
+- Keep changes small`
+
+func TestLintSpec(t *testing.T) {
+	tests := []struct {
+		name  string
+		spec  TeamSpec
+		rule  string
+		role  string
+		clean bool
+	}{
+		{
+			name: "clean spec",
+			spec: TeamSpec{
+				ClientFacing: []string{"pm"},
+				Roles: map[string]Role{
+					"pm":       {Persona: "You are a PM. Always break work into tasks.", CanDelegate: []string{"engineer"}},
+					"engineer": {Persona: lintPersona, ReportsTo: "pm"},
+				},
+			},
+			clean: true,
+		},
+		{
+			name: "client-facing role without delegation",
+			spec: TeamSpec{
+				ClientFacing: []string{"pm"},
+				Roles: map[string]Role{
+					"pm":       {Persona: "You are a PM. Always break work into tasks."},
+					"engineer": {Persona: lintPersona},
+				},
+			},
+			rule: LintNoDelegation,
+			role: "pm",
+		},
+		{
+			name: "client-facing from visibility",
+			spec: TeamSpec{
+				Roles: map[string]Role{
+					"lead":     {Persona: "You are a lead. Never skip review.", Visibility: "client"},
+					"engineer": {Persona: lintPersona},
+				},
+			},
+			rule: LintNoDelegation,
+			role: "lead",
+		},
+		{
+			name: "single role team needs no delegation",
+			spec: TeamSpec{
+				ClientFacing: []string{"solo"},
+				Roles: map[string]Role{
+					"solo": {Persona: lintPersona},
+				},
+			},
+			clean: true,
+		},
+		{
+			name: "reports to itself",
+			spec: TeamSpec{
+				Roles: map[string]Role{
+					"engineer": {Persona: lintPersona, ReportsTo: "engineer"},
+				},
+			},
+			rule: LintSelfReport,
+			role: "engineer",
+		},
+		{
+			name: "identical personas",
+			spec: TeamSpec{
+				Roles: map[string]Role{
+					"backend":  {Persona: lintPersona},
+					"frontend": {Persona: "  You are an engineer.\n- Write tests first\n-   Keep changes small\n"},
+				},
+			},
+			rule: LintDuplicatePersona,
+			role: "frontend",
+		},
+		{
+			name: "persona without instructions",
+			spec: TeamSpec{
+				Roles: map[string]Role{
+					"engineer": {Persona: "You are a senior engineer with ten years of experience."},
+				},
+			},
+			rule: LintNoInstruction,
+			role: "engineer",
+		},
+		{
+			name: "missing persona",
+			spec: TeamSpec{
+				Roles: map[string]Role{
+					"engineer": {},
+				},
+			},
+			rule: LintNoInstruction,
+			role: "engineer",
+		},
+		{
+			name: "numbered list counts as instructions",
+			spec: TeamSpec{
+				Roles: map[string]Role{
+					"engineer": {Persona: "You are an engineer.\n1. Read the ticket\n2. Ship it"},
+				},
+			},
+			clean: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warnings := LintSpec(&tt.spec)
+
+			if tt.clean {
+				if len(warnings) != 0 {
+					t.Errorf("Expected no warnings, got %v", warnings)
+				}
+				return
+			}
+
+			if len(warnings) != 1 {
+				t.Fatalf("Expected 1 warning, got %v", warnings)
+			}
+			if warnings[0].Rule != tt.rule || warnings[0].Role != tt.role {
+				t.Errorf("Expected %s on %s, got %s on %s", tt.rule, tt.role, warnings[0].Rule, warnings[0].Role)
+			}
+		})
+	}
+}
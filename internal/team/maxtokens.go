@@ -0,0 +1,44 @@
+package team
+
+import (
+	"context"
+
+	"github.com/arcslash/ugudu/internal/provider"
+)
+
+// maxTokensLimit returns the most max_tokens prov's model takes, as the
+// provider lists it, or 0 if it isn't known. Each provider's list is fetched
+// once per team; a failed fetch is kept as an empty list, so a provider that
+// can't list its models doesn't add a timeout to every call.
+func (t *Team) maxTokensLimit(ctx context.Context, prov provider.Provider, model string) int {
+	t.modelLimitMu.Lock()
+	models, ok := t.modelLimits[prov.ID()]
+	t.modelLimitMu.Unlock()
+	if !ok {
+		ctx, cancel := context.WithTimeout(ctx, modelListTimeout)
+		defer cancel()
+		var err error
+		if models, err = prov.ListModels(ctx); err != nil {
+			models = nil
+		}
+		t.modelLimitMu.Lock()
+		if t.modelLimits == nil {
+			t.modelLimits = make(map[string][]provider.ModelInfo)
+		}
+		t.modelLimits[prov.ID()] = models
+		t.modelLimitMu.Unlock()
+	}
+	return provider.MaxTokensLimit(models, model)
+}
+
+// clampMaxTokens keeps a request's max_tokens within what its model takes,
+// so a spec that asks for too many degrades instead of failing the call
+func (m *Member) clampMaxTokens(ctx context.Context, prov provider.Provider, req *provider.ChatRequest) {
+	if m.Team == nil {
+		return
+	}
+	if asked := provider.ClampMaxTokens(req, m.Team.maxTokensLimit(ctx, prov, req.Model)); asked > 0 {
+		m.log(ctx).Warn("max_tokens is over the model's limit, using the limit",
+			"provider", prov.ID(), "model", req.Model, "max_tokens", asked, "limit", *req.MaxTokens)
+	}
+}
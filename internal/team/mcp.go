@@ -0,0 +1,115 @@
+package team
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/arcslash/ugudu/internal/tools"
+)
+
+// mcpConnectTimeout bounds starting an MCP server and listing its tools
+const mcpConnectTimeout = 30 * time.Second
+
+// WithMCPServers makes external MCP servers available to the team's roles.
+// Servers the spec defines under shared.mcp_servers take precedence.
+func WithMCPServers(servers map[string]tools.MCPServerConfig) TeamOption {
+	return func(t *Team) {
+		t.mcpServers = servers
+	}
+}
+
+// roleMCPServers returns the MCP servers a role lists as tools
+func (t *Team) roleMCPServers(role string) []string {
+	var names []string
+	for _, tool := range t.Spec.Roles[role].Tools {
+		if tool.Type == "mcp" {
+			names = append(names, tool.Name)
+		}
+	}
+	return names
+}
+
+// usedMCPServers returns the config of every MCP server some role uses, by
+// name. Servers no role uses aren't started.
+func (t *Team) usedMCPServers() map[string]tools.MCPServerConfig {
+	used := make(map[string]tools.MCPServerConfig)
+	for role := range t.Spec.Roles {
+		for _, name := range t.roleMCPServers(role) {
+			if cfg, ok := t.Spec.Shared.MCPServers[name]; ok {
+				used[name] = cfg
+			} else if cfg, ok := t.mcpServers[name]; ok {
+				used[name] = cfg
+			} else {
+				t.logger.Warn("role uses an undefined mcp server", "role", role, "server", name)
+			}
+		}
+	}
+	return used
+}
+
+// connectMCPServers starts the MCP servers the team's roles use and
+// registers their tools. A server that can't be reached is logged and
+// skipped; the team runs without its tools until it's restarted. Starting a
+// running team again doesn't reconnect.
+func (t *Team) connectMCPServers(ctx context.Context) {
+	t.mu.Lock()
+	if t.mcpClients != nil {
+		t.mu.Unlock()
+		return
+	}
+	t.mcpClients = make(map[string]*tools.MCPClient)
+	t.mu.Unlock()
+
+	used := t.usedMCPServers()
+	names := make([]string, 0, len(used))
+	for name := range used {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		connectCtx, cancel := context.WithTimeout(ctx, mcpConnectTimeout)
+		client, err := tools.ConnectMCP(connectCtx, name, used[name])
+		if err != nil {
+			cancel()
+			t.logger.Warn("failed to connect to mcp server", "server", name, "error", err)
+			continue
+		}
+		serverTools, err := client.ListTools(connectCtx)
+		cancel()
+		if err != nil {
+			client.Close()
+			t.logger.Warn("failed to list mcp server tools", "server", name, "error", err)
+			continue
+		}
+
+		for _, tool := range serverTools {
+			t.toolRegistry.Register(tool)
+		}
+		t.mu.Lock()
+		t.mcpClients[name] = client
+		t.mu.Unlock()
+		t.logger.Info("connected to mcp server", "server", name, "tools", len(serverTools))
+	}
+}
+
+// closeMCPServers disconnects from the team's MCP servers and removes their
+// tools
+func (t *Team) closeMCPServers() {
+	t.mu.Lock()
+	clients := t.mcpClients
+	t.mcpClients = nil
+	t.mu.Unlock()
+
+	for _, tool := range t.toolRegistry.List() {
+		if _, ok := tool.(*tools.MCPTool); ok {
+			t.toolRegistry.Unregister(tool.Name())
+		}
+	}
+	for _, client := range clients {
+		if err := client.Close(); err != nil {
+			t.logger.Warn("failed to close mcp server", "server", client.Name(), "error", err)
+		}
+	}
+}
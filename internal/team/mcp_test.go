@@ -0,0 +1,134 @@
+package team
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arcslash/ugudu/internal/logger"
+	"github.com/arcslash/ugudu/internal/provider"
+	"github.com/arcslash/ugudu/internal/tools"
+)
+
+// stubMCPServer serves one tool, shout, that upper-cases its text argument
+func stubMCPServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+			Params struct {
+				Name      string                 `json:"name"`
+				Arguments map[string]interface{} `json:"arguments"`
+			} `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var result interface{}
+		switch req.Method {
+		case "initialize":
+			w.Header().Set("Mcp-Session-Id", "session-1")
+			result = map[string]interface{}{"protocolVersion": "2024-11-05", "capabilities": map[string]interface{}{"tools": map[string]interface{}{}}}
+		case "notifications/initialized":
+			w.WriteHeader(http.StatusAccepted)
+			return
+		case "tools/list":
+			result = map[string]interface{}{"tools": []map[string]interface{}{{
+				"name":        "shout",
+				"description": "Upper-case some text",
+				"inputSchema": map[string]interface{}{
+					"type":       "object",
+					"properties": map[string]interface{}{"text": map[string]interface{}{"type": "string"}},
+					"required":   []string{"text"},
+				},
+			}}}
+		case "tools/call":
+			if r.Header.Get("Mcp-Session-Id") != "session-1" {
+				http.Error(w, "missing session", http.StatusBadRequest)
+				return
+			}
+			text, _ := req.Params.Arguments["text"].(string)
+			result = map[string]interface{}{"content": []map[string]interface{}{{"type": "text", "text": strings.ToUpper(text)}}}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": result})
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func TestTeam_MCPServerTools(t *testing.T) {
+	server := stubMCPServer(t)
+
+	var toolResult string
+	var advertised *provider.Tool
+	mockProv := &MockProvider{ChatFunc: func(req *provider.ChatRequest) (*provider.ChatResponse, error) {
+		last := req.Messages[len(req.Messages)-1]
+		if last.ToolCallID == "call-1" {
+			toolResult = last.Content
+			return &provider.ChatResponse{Content: "The server said " + last.Content}, nil
+		}
+		for i := range req.Tools {
+			if req.Tools[i].Name == "loud__shout" {
+				advertised = &req.Tools[i]
+			}
+		}
+		return &provider.ChatResponse{ToolCalls: []provider.ToolCall{
+			{ID: "call-1", Name: "loud__shout", Arguments: `{"text":"ship it"}`},
+		}}, nil
+	}}
+
+	spec := limitsSpec(1)
+	engineer := spec.Roles["engineer"]
+	engineer.Tools = []ToolConfig{{Name: "loud", Type: "mcp"}}
+	spec.Roles["engineer"] = engineer
+	spec.Shared.MCPServers = map[string]tools.MCPServerConfig{"loud": {URL: server.URL}}
+
+	registry := provider.NewRegistry()
+	registry.Register(mockProv)
+	tm, err := NewTeam(spec, registry, logger.New("error"))
+	if err != nil {
+		t.Fatalf("NewTeam failed: %v", err)
+	}
+	if err := tm.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	t.Cleanup(tm.Stop)
+
+	// Only roles that list the server get its tools
+	if _, ok := tm.GetMemberByRole("engineer").tools().Get("loud__shout"); !ok {
+		t.Error("Expected the engineer to have loud__shout")
+	}
+	if _, ok := tm.GetMemberByRole("pm").tools().Get("loud__shout"); ok {
+		t.Error("Expected loud__shout hidden from the pm")
+	}
+
+	task := assignTask(tm, "Announce the release")
+	select {
+	case result := <-task.ResultChan:
+		if !result.Success || result.Content != `The server said "SHIP IT"` {
+			t.Errorf("Unexpected result: %+v", result)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Task never finished")
+	}
+
+	if toolResult != `"SHIP IT"` {
+		t.Errorf("Expected the tool's text content, got %q", toolResult)
+	}
+	if advertised == nil {
+		t.Fatal("Expected loud__shout offered to the model")
+	}
+	if props, _ := advertised.Parameters["properties"].(map[string]interface{}); props["text"] == nil {
+		t.Errorf("Expected the server's input schema, got %v", advertised.Parameters)
+	}
+}
@@ -3,9 +3,14 @@ package team
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
+	"sort"
+	"strings"
 	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/arcslash/ugudu/internal/logger"
 	"github.com/arcslash/ugudu/internal/provider"
@@ -27,6 +32,16 @@ type Member struct {
 	Status   MemberStatus
 	Task     *Task
 
+	statusSince time.Time // When Status last changed
+
+	// Provider and model behind the last model call, reported with client
+	// responses. Guarded by mu.
+	servedProvider string
+	servedModel    string
+
+	// Last report_progress call on the current work. Guarded by mu.
+	progress *Progress
+
 	// Tool execution
 	toolRegistry *tools.SandboxedRegistry
 
@@ -34,6 +49,7 @@ type Member struct {
 	outbox          chan Message
 	ctx             context.Context
 	cancel          context.CancelFunc
+	done            chan struct{} // Closed when the run loop returns
 	logger          *logger.Logger
 	mu              sync.RWMutex
 	conversationMu  sync.RWMutex
@@ -47,6 +63,12 @@ func NewMember(id, name, roleName string, role Role, team *Team, prov provider.P
 	if displayName == "" {
 		displayName = role.Title
 	}
+	var limits Limits
+	if team != nil {
+		limits = team.limits
+	}
+	limits = limits.withDefaults()
+
 	return &Member{
 		ID:              id,
 		Name:            displayName,
@@ -55,8 +77,9 @@ func NewMember(id, name, roleName string, role Role, team *Team, prov provider.P
 		Team:            team,
 		Provider:        prov,
 		Status:          MemberIdle,
-		inbox:           make(chan Message, 100),
-		outbox:          make(chan Message, 100),
+		statusSince:     time.Now(),
+		inbox:           make(chan Message, limits.InboxSize),
+		outbox:          make(chan Message, limits.OutboxSize),
 		logger:          log.With("member", id, "name", displayName, "role", roleName),
 		conversationCtx: make([]provider.Message, 0),
 		contextSequence: 0,
@@ -73,218 +96,44 @@ func (m *Member) DisplayName() string {
 
 // SetToolRegistry sets the tool registry for this member
 func (m *Member) SetToolRegistry(registry *tools.SandboxedRegistry) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.toolRegistry = registry
 }
 
+// tools returns the member's tool registry, which SetWorkspace may swap
+// while the member is running
+func (m *Member) tools() *tools.SandboxedRegistry {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.toolRegistry
+}
+
 // getProviderTools converts the tool registry to provider.Tool format
 func (m *Member) getProviderTools() []provider.Tool {
-	if m.toolRegistry == nil {
+	registry := m.tools()
+	if registry == nil {
 		return nil
 	}
 
-	registryTools := m.toolRegistry.List()
+	registryTools := registry.List()
 	providerTools := make([]provider.Tool, 0, len(registryTools))
 
 	for _, t := range registryTools {
 		providerTools = append(providerTools, provider.Tool{
 			Name:        t.Name(),
 			Description: t.Description(),
-			Parameters:  m.getToolParameters(t.Name()),
+			Parameters:  tools.Schema(t),
 		})
 	}
 
 	return providerTools
 }
 
-// getToolParameters returns JSON schema parameters for a tool
-func (m *Member) getToolParameters(toolName string) map[string]interface{} {
-	// Define parameter schemas for each tool
-	schemas := map[string]map[string]interface{}{
-		"read_file": {
-			"type": "object",
-			"properties": map[string]interface{}{
-				"path": map[string]interface{}{
-					"type":        "string",
-					"description": "Path to the file to read",
-				},
-			},
-			"required": []string{"path"},
-		},
-		"write_file": {
-			"type": "object",
-			"properties": map[string]interface{}{
-				"path": map[string]interface{}{
-					"type":        "string",
-					"description": "Path to the file to write",
-				},
-				"content": map[string]interface{}{
-					"type":        "string",
-					"description": "Content to write to the file",
-				},
-			},
-			"required": []string{"path", "content"},
-		},
-		"edit_file": {
-			"type": "object",
-			"properties": map[string]interface{}{
-				"path": map[string]interface{}{
-					"type":        "string",
-					"description": "Path to the file to edit",
-				},
-				"old_text": map[string]interface{}{
-					"type":        "string",
-					"description": "Text to find and replace",
-				},
-				"new_text": map[string]interface{}{
-					"type":        "string",
-					"description": "Text to replace with",
-				},
-			},
-			"required": []string{"path", "old_text", "new_text"},
-		},
-		"list_files": {
-			"type": "object",
-			"properties": map[string]interface{}{
-				"path": map[string]interface{}{
-					"type":        "string",
-					"description": "Directory path to list (default: current directory)",
-				},
-			},
-		},
-		"search_files": {
-			"type": "object",
-			"properties": map[string]interface{}{
-				"pattern": map[string]interface{}{
-					"type":        "string",
-					"description": "Glob pattern to match files (e.g., *.go)",
-				},
-				"root": map[string]interface{}{
-					"type":        "string",
-					"description": "Root directory to search from",
-				},
-			},
-			"required": []string{"pattern"},
-		},
-		"run_command": {
-			"type": "object",
-			"properties": map[string]interface{}{
-				"command": map[string]interface{}{
-					"type":        "string",
-					"description": "Shell command to execute",
-				},
-				"directory": map[string]interface{}{
-					"type":        "string",
-					"description": "Working directory for the command",
-				},
-				"timeout": map[string]interface{}{
-					"type":        "number",
-					"description": "Timeout in seconds (default: 60)",
-				},
-			},
-			"required": []string{"command"},
-		},
-		"git_status": {
-			"type":       "object",
-			"properties": map[string]interface{}{},
-		},
-		"git_diff": {
-			"type": "object",
-			"properties": map[string]interface{}{
-				"staged": map[string]interface{}{
-					"type":        "boolean",
-					"description": "Show staged changes only",
-				},
-			},
-		},
-		"git_commit": {
-			"type": "object",
-			"properties": map[string]interface{}{
-				"message": map[string]interface{}{
-					"type":        "string",
-					"description": "Commit message",
-				},
-				"files": map[string]interface{}{
-					"type":        "array",
-					"items":       map[string]interface{}{"type": "string"},
-					"description": "Files to stage and commit",
-				},
-			},
-			"required": []string{"message"},
-		},
-		"create_task": {
-			"type": "object",
-			"properties": map[string]interface{}{
-				"title": map[string]interface{}{
-					"type":        "string",
-					"description": "Task title",
-				},
-				"description": map[string]interface{}{
-					"type":        "string",
-					"description": "Task description",
-				},
-				"priority": map[string]interface{}{
-					"type":        "string",
-					"enum":        []string{"low", "medium", "high", "critical"},
-					"description": "Task priority",
-				},
-				"assignee": map[string]interface{}{
-					"type":        "string",
-					"description": "Role to assign the task to",
-				},
-			},
-			"required": []string{"title", "description"},
-		},
-		"run_tests": {
-			"type": "object",
-			"properties": map[string]interface{}{
-				"pattern": map[string]interface{}{
-					"type":        "string",
-					"description": "Test pattern to run (e.g., ./... or specific path)",
-				},
-				"verbose": map[string]interface{}{
-					"type":        "boolean",
-					"description": "Enable verbose output",
-				},
-			},
-		},
-		"http_request": {
-			"type": "object",
-			"properties": map[string]interface{}{
-				"url": map[string]interface{}{
-					"type":        "string",
-					"description": "URL to request",
-				},
-				"method": map[string]interface{}{
-					"type":        "string",
-					"enum":        []string{"GET", "POST", "PUT", "DELETE", "PATCH"},
-					"description": "HTTP method (default: GET)",
-				},
-				"body": map[string]interface{}{
-					"type":        "string",
-					"description": "Request body",
-				},
-				"headers": map[string]interface{}{
-					"type":        "object",
-					"description": "Request headers",
-				},
-			},
-			"required": []string{"url"},
-		},
-	}
-
-	if schema, ok := schemas[toolName]; ok {
-		return schema
-	}
-
-	// Default schema for unknown tools
-	return map[string]interface{}{
-		"type":       "object",
-		"properties": map[string]interface{}{},
-	}
-}
-
-// executeToolCalls executes tool calls and returns tool result messages
-func (m *Member) executeToolCalls(ctx context.Context, toolCalls []provider.ToolCall) []provider.Message {
+// executeToolCalls executes tool calls and returns tool result messages.
+// Tool errors are passed back to the model, except for a question the client
+// never answered, which stops the work and is returned.
+func (m *Member) executeToolCalls(ctx context.Context, toolCalls []provider.ToolCall) ([]provider.Message, error) {
 	results := make([]provider.Message, 0, len(toolCalls))
 
 	for _, tc := range toolCalls {
@@ -298,15 +147,62 @@ func (m *Member) executeToolCalls(ctx context.Context, toolCalls []provider.Tool
 			continue
 		}
 
-		m.logger.Info("executing tool", "tool", tc.Name, "args", args)
+		// Catch wrongly typed or missing arguments before the tool runs, and
+		// tell the model exactly what to fix
+		if tool, ok := m.tools().Get(tc.Name); ok {
+			if err := tools.ValidateArgs(tool, args); err != nil {
+				m.log(ctx).Warn("invalid tool arguments", "tool", tc.Name, "error", err)
+				content, _ := json.Marshal(map[string]interface{}{
+					"error":    "invalid arguments, correct them and call the tool again",
+					"tool":     tc.Name,
+					"problems": err.(*tools.ArgumentError).Problems,
+				})
+				results = append(results, provider.Message{
+					Role:       "tool",
+					Content:    string(content),
+					ToolCallID: tc.ID,
+				})
+				continue
+			}
+		}
+
+		// Some tools wait for a human to approve each call
+		if m.Team.requiresApproval(tc.Name) {
+			approved, reason, err := m.Team.awaitApproval(ctx, m, tc.Name, args)
+			if err != nil {
+				return results, err
+			}
+			if !approved {
+				m.log(ctx).Info("tool call denied", "tool", tc.Name, "reason", reason)
+				m.Team.audit(ctx, m.ID, AuditToolDenied, map[string]interface{}{"tool": tc.Name, "args": auditArgs(args), "reason": reason})
+				content := "Denied: a human didn't approve this call"
+				if reason != "" {
+					content += ": " + reason
+				}
+				results = append(results, provider.Message{
+					Role:       "tool",
+					Content:    content,
+					ToolCallID: tc.ID,
+				})
+				continue
+			}
+		}
+
+		m.log(ctx).Info("executing tool", "tool", tc.Name, "args", args)
 
 		// Notify activity about tool execution
-		m.Team.NotifyActivity(m.ID, "tool_call", fmt.Sprintf("Using tool: %s", tc.Name))
+		m.Team.NotifyActivity(ctx, m.ID, "tool_call", fmt.Sprintf("Using tool: %s", tc.Name))
 
-		result, err := m.toolRegistry.Execute(ctx, tc.Name, args)
+		result, err := m.tools().Execute(ctx, tc.Name, args)
+		if errors.Is(err, ErrQuestionUnanswered) {
+			m.log(ctx).Warn("client did not answer question", "tool", tc.Name, "error", err)
+			return results, err
+		}
 		if err != nil {
-			m.logger.Error("tool execution failed", "tool", tc.Name, "error", err)
-			m.Team.NotifyActivity(m.ID, "tool_error", fmt.Sprintf("Tool %s failed: %s", tc.Name, truncateMessage(err.Error(), 50)))
+			m.log(ctx).Error("tool execution failed", "tool", tc.Name, "error", err)
+			m.Team.audit(ctx, m.ID, AuditToolExecute, map[string]interface{}{"tool": tc.Name, "args": auditArgs(args), "error": err.Error()})
+			m.Team.NotifyActivity(ctx, m.ID, "tool_error", fmt.Sprintf("Tool %s failed: %s", tc.Name, truncateMessage(err.Error(), 50)))
+			m.Team.shareInternal(ctx, m.ID, fmt.Sprintf("Tool %s failed: %v", tc.Name, err))
 			results = append(results, provider.Message{
 				Role:       "tool",
 				Content:    fmt.Sprintf("Error: %v", err),
@@ -315,18 +211,34 @@ func (m *Member) executeToolCalls(ctx context.Context, toolCalls []provider.Tool
 			continue
 		}
 
-		// Format result as JSON
-		resultJSON, _ := json.MarshalIndent(result, "", "  ")
-		m.logger.Debug("tool result", "tool", tc.Name, "result", string(resultJSON))
+		// Format result as JSON, or as text and images for a tool that
+		// returns content
+		var content string
+		var images []provider.Image
+		if c, ok := toolContent(result); ok {
+			content, images = m.contentForModel(c)
+		} else {
+			resultJSON, _ := json.MarshalIndent(result, "", "  ")
+			content = string(resultJSON)
+		}
+		m.Team.audit(ctx, m.ID, AuditToolExecute, map[string]interface{}{"tool": tc.Name, "args": auditArgs(args), "result_bytes": len(content), "images": len(images)})
+		m.log(ctx).Debug("tool result", "tool", tc.Name, "result", content, "images", len(images))
+		m.Team.shareInternal(ctx, m.ID, fmt.Sprintf("Tool %s: %s", tc.Name, truncateMessage(content, 200)))
+
+		if max := m.Team.limits.MaxToolResult; len(content) > max {
+			m.log(ctx).Warn("tool result truncated", "tool", tc.Name, "size", len(content), "max", max)
+			content = truncateToolResult(content, max)
+		}
 
 		results = append(results, provider.Message{
 			Role:       "tool",
-			Content:    string(resultJSON),
+			Content:    content,
 			ToolCallID: tc.ID,
+			Images:     images,
 		})
 	}
 
-	return results
+	return results, nil
 }
 
 // RestoreContext restores conversation context from persistence
@@ -342,6 +254,7 @@ func (m *Member) RestoreContext(history []ContextMessage) {
 		})
 	}
 	m.contextSequence = len(history)
+	m.trimContext()
 	m.logger.Debug("context restored", "messages", len(history))
 }
 
@@ -359,10 +272,35 @@ func (m *Member) addToContext(role, content string) {
 	// Persist to store
 	m.Team.SaveMemberContext(m.ID, role, content, m.contextSequence)
 
-	// Trim context based on token mode (fewer messages in low token mode)
-	contextLimit := m.getContextLimit()
-	if len(m.conversationCtx) > contextLimit {
-		m.conversationCtx = m.conversationCtx[len(m.conversationCtx)-contextLimit:]
+	m.trimContext()
+}
+
+// trimContext drops the oldest messages until the context is within both
+// the token mode's message count and its byte budget, so a few huge
+// messages can't overflow the model's context window. The newest message
+// is always kept. The caller holds conversationMu.
+func (m *Member) trimContext() {
+	settings := m.Team.GetTokenSettings()
+
+	drop := 0
+	if limit := settings.ContextHistory; limit > 0 && len(m.conversationCtx) > limit {
+		drop = len(m.conversationCtx) - limit
+	}
+
+	if budget := settings.ContextBytes; budget > 0 {
+		size := 0
+		for _, msg := range m.conversationCtx[drop:] {
+			size += len(msg.Content)
+		}
+		for size > budget && drop < len(m.conversationCtx)-1 {
+			size -= len(m.conversationCtx[drop].Content)
+			drop++
+		}
+	}
+
+	if drop > 0 {
+		m.conversationCtx = m.conversationCtx[drop:]
+		m.logger.Debug("context trimmed", "dropped", drop, "kept", len(m.conversationCtx))
 	}
 }
 
@@ -377,39 +315,95 @@ func (m *Member) getContextMessages() []provider.Message {
 	return ctx
 }
 
-// ClearContext clears the conversation context (for new conversations)
+// ClearContext clears the conversation context (for new conversations).
+// A seeded team's seed is put back, so the fresh conversation starts with it.
 func (m *Member) ClearContext() {
 	m.conversationMu.Lock()
-	defer m.conversationMu.Unlock()
-
 	m.conversationCtx = make([]provider.Message, 0)
 	m.contextSequence = 0
+	m.conversationMu.Unlock()
+
+	if m.Team != nil {
+		m.seedContext(m.Team.seed)
+	}
 }
 
 // Start begins the member's processing loop
 func (m *Member) Start(ctx context.Context) {
-	m.ctx, m.cancel = context.WithCancel(ctx)
-	go m.run()
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	m.mu.Lock()
+	m.ctx, m.cancel, m.done = ctx, cancel, done
+	m.mu.Unlock()
+
+	go func() {
+		defer close(done)
+		m.run(ctx)
+	}()
 	m.logger.Info("member started")
 }
 
 // Stop halts the member
 func (m *Member) Stop() {
-	if m.cancel != nil {
-		m.cancel()
+	m.mu.RLock()
+	cancel := m.cancel
+	m.mu.RUnlock()
+
+	if cancel != nil {
+		cancel()
 	}
 	m.logger.Info("member stopped")
 }
 
-// Send sends a message to this member
-func (m *Member) Send(msg Message) {
+// wait blocks until the member's run loop has returned after Stop, finishing
+// the message it was handling, or until ctx is done
+func (m *Member) wait(ctx context.Context) error {
+	m.mu.RLock()
+	done := m.done
+	m.mu.RUnlock()
+	if done == nil {
+		return nil
+	}
 	select {
-	case m.inbox <- msg:
-	default:
-		m.logger.Warn("inbox full, dropping message", "type", msg.Type)
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("member %s still busy: %w", m.ID, ctx.Err())
 	}
 }
 
+// runContext returns the context of the current run, which is replaced each
+// time the member is restarted
+func (m *Member) runContext() context.Context {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.ctx
+}
+
+// Send sends a message to this member. If the inbox is full the team's
+// overflow policy applies; the error is ErrChannelFull if the message was
+// dropped and the policy reports drops. A dropped task assignment or handed
+// over client request is always reported, since its sender is waiting on it.
+func (m *Member) Send(msg Message) error {
+	var limits Limits
+	if m.Team != nil {
+		limits = m.Team.limits
+	}
+	limits = limits.withDefaults()
+
+	if deliver(m.inbox, msg, limits.Overflow, limits.OverflowTimeout) {
+		return nil
+	}
+	if m.Team != nil {
+		m.Team.dropped.inbox.Add(1)
+	}
+	m.logger.Warn("inbox full, dropping message", "type", msg.Type, "policy", limits.Overflow)
+	if msg.Type == MsgTaskAssignment || msg.handoff != nil {
+		return fmt.Errorf("%w: %s", ErrChannelFull, m.ID+" inbox")
+	}
+	return overflowError(limits.Overflow, m.ID+" inbox")
+}
+
 // GetStatus returns current status
 func (m *Member) GetStatus() MemberStatus {
 	m.mu.RLock()
@@ -417,6 +411,13 @@ func (m *Member) GetStatus() MemberStatus {
 	return m.Status
 }
 
+// StatusSince returns when the member entered its current status
+func (m *Member) StatusSince() time.Time {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.statusSince
+}
+
 // GetCurrentTask returns the current task if any
 func (m *Member) GetCurrentTask() *Task {
 	m.mu.RLock()
@@ -424,10 +425,10 @@ func (m *Member) GetCurrentTask() *Task {
 	return m.Task
 }
 
-func (m *Member) run() {
+func (m *Member) run(ctx context.Context) {
 	for {
 		select {
-		case <-m.ctx.Done():
+		case <-ctx.Done():
 			return
 
 		case msg := <-m.inbox:
@@ -437,35 +438,39 @@ func (m *Member) run() {
 }
 
 func (m *Member) handleMessage(msg Message) {
-	m.logger.Debug("received message", "type", msg.Type, "from", msg.From)
+	ctx := logger.ContextWithRequestID(m.runContext(), msg.RequestID)
+	m.log(ctx).Debug("received message", "type", msg.Type, "from", msg.From)
 
 	switch msg.Type {
 	case MsgClientRequest:
-		m.handleClientRequest(msg)
+		m.handleClientRequest(ctx, msg)
 	case MsgTaskAssignment:
-		m.handleTaskAssignment(msg)
+		m.handleTaskAssignment(ctx, msg)
 	case MsgQuestion:
-		m.handleQuestion(msg)
+		m.handleQuestion(ctx, msg)
 	case MsgReport:
-		m.handleReport(msg)
+		m.handleReport(ctx, msg)
 	case MsgAnswer:
-		m.handleAnswer(msg)
+		m.handleAnswer(ctx, msg)
 	}
 }
 
-func (m *Member) handleClientRequest(msg Message) {
-	m.setStatus(MemberWorking)
-	defer m.setStatus(MemberIdle)
+func (m *Member) handleClientRequest(ctx context.Context, msg Message) {
+	m.setStatus(ctx, MemberWorking)
+	defer m.setStatus(ctx, MemberIdle)
 
 	content, ok := msg.Content.(string)
 	if !ok {
-		m.logger.Error("invalid client request content")
+		m.log(ctx).Error("invalid client request content")
 		return
 	}
+	if msg.handoff != nil {
+		m.acceptHandoff(msg.handoff)
+	}
 
 	// Build the prompt with persona and conversation history
 	messages := []provider.Message{
-		{Role: "system", Content: m.buildSystemPrompt()},
+		{Role: "system", Content: m.systemPrompt(!msg.NoTools)},
 	}
 
 	// Add conversation history for context continuity
@@ -477,14 +482,19 @@ func (m *Member) handleClientRequest(msg Message) {
 	// Persist user message to context
 	m.addToContext("user", content)
 
-	// Get tools if available
-	providerTools := m.getProviderTools()
+	// Get tools if available, unless the client asked for a plain answer
+	var providerTools []provider.Tool
+	if !msg.NoTools {
+		providerTools = m.getProviderTools()
+	}
 
 	// Tool execution loop
+	start := time.Now()
+	turnStart := len(messages)
 	var finalContent string
 	for iteration := 0; iteration < MaxToolIterations; iteration++ {
 		// Call the model with token mode settings
-		resp, err := m.Provider.Chat(m.ctx, &provider.ChatRequest{
+		resp, err := m.chat(ctx, &provider.ChatRequest{
 			Model:       m.getEffectiveModel(),
 			Messages:    messages,
 			Tools:       providerTools,
@@ -493,21 +503,13 @@ func (m *Member) handleClientRequest(msg Message) {
 		})
 
 		if err != nil {
-			m.logger.Error("model call failed", "error", err)
-			m.sendToTeam(Message{
-				ID:        uuid.New().String(),
-				Type:      MsgClientResponse,
-				From:      m.ID,
-				To:        "client",
-				Content:   fmt.Sprintf("I encountered an error: %v", err),
-				Timestamp: time.Now(),
-			})
+			m.replyWithError(ctx, err, time.Since(start))
 			return
 		}
 
 		// Check for tool calls
-		if len(resp.ToolCalls) > 0 && m.toolRegistry != nil {
-			m.logger.Debug("processing tool calls", "count", len(resp.ToolCalls))
+		if len(resp.ToolCalls) > 0 && m.tools() != nil && !msg.NoTools {
+			m.log(ctx).Debug("processing tool calls", "count", len(resp.ToolCalls))
 
 			// Add assistant message with tool calls to history
 			messages = append(messages, provider.Message{
@@ -517,7 +519,18 @@ func (m *Member) handleClientRequest(msg Message) {
 			})
 
 			// Execute tools and add results
-			toolResults := m.executeToolCalls(m.ctx, resp.ToolCalls)
+			toolResults, err := m.executeToolCalls(ctx, resp.ToolCalls)
+			if err != nil {
+				m.sendToTeam(ctx, Message{
+					ID:        uuid.New().String(),
+					Type:      MsgClientResponse,
+					From:      m.ID,
+					To:        "client",
+					Content:   fmt.Sprintf("I stopped waiting for your answer: %v", err),
+					Timestamp: time.Now(),
+				})
+				return
+			}
 			messages = append(messages, toolResults...)
 
 			// Continue loop to let model process tool results
@@ -530,39 +543,44 @@ func (m *Member) handleClientRequest(msg Message) {
 	}
 
 	// Persist assistant response to context
-	m.addToContext("assistant", finalContent)
+	m.addToContext("assistant", m.withToolNotes(messages[turnStart:], finalContent))
 
 	// Parse the response for potential delegations or direct response
 	action := m.parseResponse(finalContent, content)
 
 	switch action.Type {
 	case "delegate":
-		m.handleDelegation(action, msg)
+		m.handleDelegation(ctx, action, msg)
 	case "parallel_delegate":
-		m.handleParallelDelegation(action, msg)
+		m.handleParallelDelegation(ctx, action, msg)
+	case "handoff":
+		m.handleHandoff(ctx, action, msg)
 	case "question":
-		m.askClient(action.Content)
+		m.askClient(ctx, action.Content)
 	case "respond":
-		m.respondToClient(action.Content)
+		m.respondToClient(ctx, action.Content)
 	}
 }
 
-func (m *Member) handleTaskAssignment(msg Message) {
+func (m *Member) handleTaskAssignment(ctx context.Context, msg Message) {
 	task, ok := msg.Content.(*Task)
 	if !ok {
-		m.logger.Error("invalid task assignment content")
+		m.log(ctx).Error("invalid task assignment content")
 		return
 	}
 
 	m.mu.Lock()
 	m.Task = task
+	if m.Status != MemberWorking {
+		m.statusSince = time.Now()
+	}
 	m.Status = MemberWorking
 	m.mu.Unlock()
 
-	m.logger.Info("working on task", "task_id", task.ID)
+	m.log(ctx).Info("working on task", "task_id", task.ID)
 
 	// Notify activity
-	m.Team.NotifyActivity(m.ID, "task_started", fmt.Sprintf("Started working on: %s", truncateMessage(task.Content, 100)))
+	m.Team.NotifyActivity(ctx, m.ID, "task_started", fmt.Sprintf("Started working on: %s", truncateMessage(task.Content, 100)))
 
 	// Build context with task details and history
 	messages := []provider.Message{
@@ -585,10 +603,11 @@ func (m *Member) handleTaskAssignment(msg Message) {
 	providerTools := m.getProviderTools()
 
 	// Tool execution loop
+	turnStart := len(messages)
 	var finalContent string
 	for iteration := 0; iteration < MaxToolIterations; iteration++ {
 		// Execute the task with token mode settings
-		resp, err := m.Provider.Chat(m.ctx, &provider.ChatRequest{
+		resp, err := m.chat(ctx, &provider.ChatRequest{
 			Model:       m.getEffectiveModel(),
 			Messages:    messages,
 			Tools:       providerTools,
@@ -597,13 +616,13 @@ func (m *Member) handleTaskAssignment(msg Message) {
 		})
 
 		if err != nil {
-			m.reportTaskFailure(task, err)
+			m.reportTaskFailure(ctx, task, err)
 			return
 		}
 
 		// Check for tool calls
-		if len(resp.ToolCalls) > 0 && m.toolRegistry != nil {
-			m.logger.Debug("processing tool calls", "count", len(resp.ToolCalls))
+		if len(resp.ToolCalls) > 0 && m.tools() != nil {
+			m.log(ctx).Debug("processing tool calls", "count", len(resp.ToolCalls))
 
 			// Add assistant message with tool calls to history
 			messages = append(messages, provider.Message{
@@ -613,7 +632,11 @@ func (m *Member) handleTaskAssignment(msg Message) {
 			})
 
 			// Execute tools and add results
-			toolResults := m.executeToolCalls(m.ctx, resp.ToolCalls)
+			toolResults, err := m.executeToolCalls(ctx, resp.ToolCalls)
+			if err != nil {
+				m.reportTaskFailure(ctx, task, err)
+				return
+			}
 			messages = append(messages, toolResults...)
 
 			// Continue loop to let model process tool results
@@ -626,7 +649,7 @@ func (m *Member) handleTaskAssignment(msg Message) {
 	}
 
 	// Persist assistant response to context
-	m.addToContext("assistant", finalContent)
+	m.addToContext("assistant", m.withToolNotes(messages[turnStart:], finalContent))
 
 	// Check if needs to delegate further or complete
 	action := m.parseResponse(finalContent, task.Content)
@@ -635,47 +658,47 @@ func (m *Member) handleTaskAssignment(msg Message) {
 	case "delegate":
 		// Can this role delegate?
 		if len(m.Role.CanDelegate) > 0 {
-			m.delegateToRole(action.Target, action.Content, task)
+			m.delegateToRole(ctx, action.Target, action.Content, task)
 		} else {
 			// Complete with current result
-			m.completeTask(task, finalContent)
+			m.completeTask(ctx, task, finalContent)
 		}
 	case "complete":
-		m.completeTask(task, action.Content)
+		m.completeTask(ctx, task, action.Content)
 	default:
-		m.completeTask(task, finalContent)
+		m.completeTask(ctx, task, finalContent)
 	}
 }
 
-func (m *Member) handleQuestion(msg Message) {
+func (m *Member) handleQuestion(ctx context.Context, msg Message) {
 	// Another member is asking us a question
 	content, ok := msg.Content.(string)
 	if !ok {
 		return
 	}
 
-	m.setStatus(MemberWorking)
-	defer m.setStatus(MemberIdle)
+	m.setStatus(ctx, MemberWorking)
+	defer m.setStatus(ctx, MemberIdle)
 
 	messages := []provider.Message{
 		{Role: "system", Content: m.buildSystemPrompt()},
 		{Role: "user", Content: fmt.Sprintf("A colleague asks: %s", content)},
 	}
 
-	resp, err := m.Provider.Chat(m.ctx, &provider.ChatRequest{
-		Model:       m.Role.Model.Model,
+	resp, err := m.chat(ctx, &provider.ChatRequest{
+		Model:       m.getEffectiveModel(),
 		Messages:    messages,
 		Temperature: m.Role.Model.Temperature,
-		MaxTokens:   m.Role.Model.MaxTokens,
+		MaxTokens:   m.getEffectiveMaxTokens(),
 	})
 
 	if err != nil {
-		m.logger.Error("failed to answer question", "error", err)
+		m.log(ctx).Error("failed to answer question", "error", err)
 		return
 	}
 
 	// Send answer back
-	m.sendToTeam(Message{
+	m.sendToTeam(ctx, Message{
 		ID:        uuid.New().String(),
 		Type:      MsgAnswer,
 		From:      m.ID,
@@ -686,18 +709,24 @@ func (m *Member) handleQuestion(msg Message) {
 	})
 }
 
-func (m *Member) handleReport(msg Message) {
+func (m *Member) handleReport(ctx context.Context, msg Message) {
 	// A subordinate is reporting back
-	m.logger.Debug("received report", "from", msg.From)
+	m.log(ctx).Debug("received report", "from", msg.From)
 	// This will be picked up by pending task handlers
 }
 
-func (m *Member) handleAnswer(msg Message) {
+func (m *Member) handleAnswer(ctx context.Context, msg Message) {
 	// Received answer to a question we asked
-	m.logger.Debug("received answer", "from", msg.From)
+	m.log(ctx).Debug("received answer", "from", msg.From)
 }
 
 func (m *Member) buildSystemPrompt() string {
+	return m.systemPrompt(true)
+}
+
+// systemPrompt builds the member's system prompt, describing its tools only
+// if withTools is set
+func (m *Member) systemPrompt(withTools bool) string {
 	tokenMode := m.Team.GetTokenMode()
 
 	// Use condensed persona in low/minimal token mode if available
@@ -716,7 +745,8 @@ func (m *Member) buildSystemPrompt() string {
 		persona = m.Role.Persona
 	}
 
-	prompt := persona + "\n\n"
+	now := m.Team.Now()
+	prompt := renderClock(persona, now) + "\n\n"
 
 	// Use name if available
 	if m.Name != "" && m.Name != m.Role.Title {
@@ -724,6 +754,7 @@ func (m *Member) buildSystemPrompt() string {
 	} else {
 		prompt += fmt.Sprintf("You are the %s on team '%s'.\n", m.Role.Title, m.Team.Name)
 	}
+	prompt += clockLine(now)
 
 	// Only include responsibilities in normal mode
 	if tokenMode == TokenModeNormal && len(m.Role.Responsibilities) > 0 {
@@ -734,14 +765,14 @@ func (m *Member) buildSystemPrompt() string {
 	}
 
 	// Add tool information (condensed in low token mode)
-	if m.toolRegistry != nil {
-		toolsInfo := m.toolRegistry.FormatToolsForPrompt()
+	if registry := m.tools(); registry != nil && withTools {
+		toolsInfo := registry.FormatToolsForPrompt()
 		if toolsInfo != "" && toolsInfo != "No tools available." {
 			if tokenMode == TokenModeNormal {
 				prompt += "\n" + toolsInfo + "\n"
 				prompt += "Use these tools to accomplish your tasks. You can read files, write code, run commands, and interact with the codebase.\n"
 			} else {
-				prompt += "\nTools available: " + m.toolRegistry.ListToolNames() + "\n"
+				prompt += "\nTools available: " + registry.ListToolNames() + "\n"
 			}
 		}
 	}
@@ -760,6 +791,15 @@ func (m *Member) buildSystemPrompt() string {
 		}
 	}
 
+	if len(m.Role.CanHandoff) > 0 {
+		prompt += "\nYou can hand the client over to: " + fmt.Sprintf("%v", m.Role.CanHandoff) + "\n"
+		if tokenMode == TokenModeNormal {
+			prompt += "When one of them should take over the conversation with the client, rather than do a task and report back to you: HANDOFF TO [role]: [what they need to know]\n"
+		} else {
+			prompt += "HANDOFF TO [role]: [note]\n"
+		}
+	}
+
 	if tokenMode == TokenModeNormal {
 		if m.Role.Visibility == "client" {
 			prompt += "\nYou interact directly with clients. Be professional and clear.\n"
@@ -774,9 +814,209 @@ func (m *Member) buildSystemPrompt() string {
 
 	prompt += "\nCOMPLETE: [response] | ASK CLIENT: [question]\n"
 
+	// Mandated prefix/suffix wrap the role prompt untouched by token mode
+	settings := m.Team.Spec.Settings
+	if settings.SystemPrefix != "" {
+		prompt = strings.TrimRight(settings.SystemPrefix, "\n") + "\n\n" + prompt
+	}
+	if settings.SystemSuffix != "" {
+		prompt += "\n" + strings.TrimRight(settings.SystemSuffix, "\n") + "\n"
+	}
+
 	return prompt
 }
 
+// MemberPrompt is what a member gives the model on every call: its system
+// prompt and the tools offered with it
+type MemberPrompt struct {
+	Member    string    `json:"member"`
+	TokenMode TokenMode `json:"token_mode"`
+	System    string    `json:"system"`
+	Tools     []string  `json:"tools"`
+}
+
+// Prompt renders the member's system prompt and tools as they'd be sent
+// now, under the team's current token mode
+func (m *Member) Prompt() MemberPrompt {
+	p := MemberPrompt{
+		Member:    m.ID,
+		TokenMode: m.Team.GetTokenMode(),
+		System:    m.buildSystemPrompt(),
+		Tools:     []string{},
+	}
+	for _, t := range m.getProviderTools() {
+		p.Tools = append(p.Tools, t.Name)
+	}
+	return p
+}
+
+// chat sends a request to the member's provider and records the outcome in
+// the provider registry so credential problems show up in provider status.
+// While the provider is unhealthy the role's first healthy fallback is used.
+func (m *Member) chat(ctx context.Context, req *provider.ChatRequest) (*provider.ChatResponse, error) {
+	if m.Team != nil && m.Team.Spec != nil && req.PromptCaching == nil {
+		req.PromptCaching = m.Team.Spec.Settings.PromptCaching
+	}
+	if req.OpenRouter == nil {
+		req.OpenRouter = m.Role.Model.OpenRouter
+	}
+	m.applySampling(req)
+	// Members run on the team's context, which has no deadline
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, m.requestTimeout())
+		defer cancel()
+	}
+	prov := m.Provider
+	if fb, model := m.fallback(); fb != nil {
+		m.log(ctx).Warn("provider unhealthy, using fallback", "provider", prov.ID(), "fallback", fb.ID(), "model", model)
+		prov = fb
+		if model != "" {
+			fbReq := *req
+			fbReq.Model = model
+			req = &fbReq
+		}
+	}
+
+	m.clampMaxTokens(ctx, prov, req)
+
+	m.log(ctx).Debug("calling model", "provider", prov.ID(), "model", req.Model)
+	start := time.Now()
+	resp, err := prov.Chat(ctx, req)
+	m.recordServed(prov, req, resp)
+	if m.Team != nil && m.Team.providers != nil {
+		m.Team.providers.RecordCall(prov.ID(), time.Since(start), err)
+	}
+	if m.Team != nil {
+		m.Team.recordCall(ctx, m.ID, prov, req, resp, err, start)
+	}
+	// Not every provider wraps the context's error; make sure callers can
+	// tell a timeout or cancellation from the model failing
+	if err != nil && ctx.Err() != nil && !errors.Is(err, ctx.Err()) {
+		err = fmt.Errorf("%w: %v", ctx.Err(), err)
+	}
+	return resp, err
+}
+
+// requestTimeout returns how long a single model call may take
+func (m *Member) requestTimeout() time.Duration {
+	if m.Team != nil && m.Team.Spec != nil && m.Team.Spec.Settings.RequestTimeout > 0 {
+		return m.Team.Spec.Settings.RequestTimeout
+	}
+	return provider.DefaultRequestTimeout
+}
+
+// applySampling fills the sampling settings a request leaves unset from the
+// role's model config, so every call a member makes honors the spec
+func (m *Member) applySampling(req *provider.ChatRequest) {
+	if req.Temperature == nil {
+		req.Temperature = m.Role.Model.Temperature
+	}
+	if req.MaxTokens == nil {
+		if m.Team != nil {
+			req.MaxTokens = m.getEffectiveMaxTokens()
+		} else {
+			req.MaxTokens = m.Role.Model.MaxTokens
+		}
+	}
+	if req.TopP == nil {
+		req.TopP = m.Role.Model.TopP
+	}
+	if req.Stop == nil {
+		req.Stop = m.Role.Model.Stop
+	}
+}
+
+// fallback returns the role's first healthy fallback provider and model when
+// the member's own provider is unhealthy, or nil to use the member's provider
+func (m *Member) fallback() (provider.Provider, string) {
+	if m.Team == nil || m.Team.providers == nil || m.Team.providers.Healthy(m.Provider.ID()) {
+		return nil, ""
+	}
+	for _, fb := range m.Role.Model.Fallback {
+		if !m.Team.providers.Healthy(fb.Provider) {
+			continue
+		}
+		if p, err := m.Team.providers.Get(fb.Provider); err == nil {
+			return p, fb.Model
+		}
+	}
+	return nil, ""
+}
+
+// recordServed remembers which model answered a call. Providers report the
+// model that actually served the request, which can differ from the one asked
+// for (e.g. OpenRouter fallbacks); the requested one is kept otherwise.
+func (m *Member) recordServed(p provider.Provider, req *provider.ChatRequest, resp *provider.ChatResponse) {
+	prov, model := p.ID(), req.Model
+	if resp != nil {
+		if resp.Provider != "" {
+			prov = resp.Provider
+		}
+		if resp.Model != "" {
+			model = resp.Model
+		}
+	}
+	m.mu.Lock()
+	m.servedProvider, m.servedModel = prov, model
+	m.mu.Unlock()
+}
+
+// replyWithError tells the client its request failed. A timeout or
+// cancellation gets its own message and activity event, since the raw
+// context error means nothing to users.
+func (m *Member) replyWithError(ctx context.Context, err error, elapsed time.Duration) {
+	content := clientErrorMessage(err)
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		// Report the limit rather than elapsed, which also counts earlier
+		// tool rounds and says nothing about what to raise
+		seconds := int(math.Ceil(m.requestTimeout().Seconds()))
+		content = fmt.Sprintf("The request timed out: the model didn't answer within %s (settings.request_timeout). Try again, or split it into smaller asks.", formatSeconds(seconds))
+		m.log(ctx).Warn("model call timed out", "elapsed", elapsed.Round(time.Millisecond), "request_timeout", m.requestTimeout(), "error", err)
+		m.Team.notifyActivity(ctx, m.ID, "request_timeout", content, map[string]interface{}{"seconds": seconds})
+	case errors.Is(err, context.Canceled):
+		content = "The request was canceled before it finished."
+		m.log(ctx).Info("model call canceled")
+		m.Team.notifyActivity(ctx, m.ID, "request_canceled", content, nil)
+	default:
+		m.log(ctx).Error("model call failed", "error", err)
+	}
+
+	m.sendToTeam(ctx, Message{
+		ID:        uuid.New().String(),
+		Type:      MsgClientResponse,
+		From:      m.ID,
+		To:        "client",
+		Content:   content,
+		Timestamp: time.Now(),
+	})
+}
+
+// formatSeconds spells out a whole number of seconds, in minutes when it's
+// a whole number of them
+func formatSeconds(seconds int) string {
+	switch {
+	case seconds >= 60 && seconds%60 == 0:
+		if seconds == 60 {
+			return "1 minute"
+		}
+		return fmt.Sprintf("%d minutes", seconds/60)
+	case seconds == 1:
+		return "1 second"
+	}
+	return fmt.Sprintf("%d seconds", seconds)
+}
+
+// clientErrorMessage turns a model error into something the client can act on
+func clientErrorMessage(err error) string {
+	var authErr *provider.AuthError
+	if errors.As(err, &authErr) {
+		return fmt.Sprintf("%s rejected the API key. Run `ugudu config init` to update your credentials, then restart the daemon.", authErr.Provider)
+	}
+	return fmt.Sprintf("I encountered an error: %v", err)
+}
+
 // getEffectiveMaxTokens returns max tokens based on token mode
 func (m *Member) getEffectiveMaxTokens() *int {
 	settings := m.Team.GetTokenSettings()
@@ -793,24 +1033,11 @@ func (m *Member) getEffectiveMaxTokens() *int {
 
 // getEffectiveModel returns model based on token mode
 func (m *Member) getEffectiveModel() string {
-	settings := m.Team.GetTokenSettings()
-
-	// Use low token model if available and in low/minimal mode
-	if (settings.Mode == TokenModeLow || settings.Mode == TokenModeMinimal) && m.Role.Model.LowTokenModel != "" {
-		return m.Role.Model.LowTokenModel
-	}
-
-	return m.Role.Model.Model
-}
-
-// getContextLimit returns context history limit based on token mode
-func (m *Member) getContextLimit() int {
-	settings := m.Team.GetTokenSettings()
-	return settings.ContextHistory
+	return m.Role.Model.ModelFor(m.Team.GetTokenSettings().Mode)
 }
 
 type responseAction struct {
-	Type    string // "delegate", "parallel_delegate", "question", "respond", "complete"
+	Type    string // "delegate", "parallel_delegate", "handoff", "question", "respond", "complete"
 	Target  string // For delegation - which role
 	Content string
 	// For parallel delegation
@@ -825,6 +1052,15 @@ type parallelTask struct {
 func (m *Member) parseResponse(response, originalRequest string) responseAction {
 	// Simple parsing for delegation/question/response patterns
 
+	// Check for a handoff of the client conversation
+	if idx := indexOf(response, "HANDOFF TO "); idx >= 0 {
+		rest := response[idx+11:]
+		if colonIdx := indexOf(rest, ":"); colonIdx > 0 {
+			target := cleanRoleName(rest[:colonIdx])
+			return responseAction{Type: "handoff", Target: target, Content: trim(rest[colonIdx+1:])}
+		}
+	}
+
 	// Check for parallel delegation first (higher priority)
 	if idx := indexOf(response, "DELEGATE PARALLEL:"); idx >= 0 {
 		rest := response[idx+18:]
@@ -919,12 +1155,12 @@ func splitLines(text string) []string {
 	return lines
 }
 
-func (m *Member) handleDelegation(action responseAction, originalMsg Message) {
+func (m *Member) handleDelegation(ctx context.Context, action responseAction, originalMsg Message) {
 	// Find the target role member
 	target := m.Team.GetMemberByRole(action.Target)
 	if target == nil {
-		m.logger.Warn("delegation target not found", "target", action.Target)
-		m.respondToClient(action.Content)
+		m.log(ctx).Warn("delegation target not found", "target", action.Target)
+		m.respondToClient(ctx, action.Content)
 		return
 	}
 
@@ -943,46 +1179,53 @@ func (m *Member) handleDelegation(action responseAction, originalMsg Message) {
 	m.Team.AddTask(task)
 
 	// Send task assignment
-	target.Send(Message{
+	if err := target.Send(Message{
 		ID:        uuid.New().String(),
 		Type:      MsgTaskAssignment,
 		From:      m.ID,
 		To:        target.ID,
 		Content:   task,
 		TaskID:    task.ID,
+		RequestID: logger.RequestID(ctx),
 		Timestamp: time.Now(),
-	})
-
-	m.logger.Info("delegated task, waiting for result", "to", action.Target, "task_id", task.ID)
+	}); err != nil {
+		m.failUndelivered(ctx, task, err)
+	} else {
+		m.log(ctx).Info("delegated task, waiting for result", "to", action.Target, "task_id", task.ID)
 
-	// Notify about the delegation
-	m.Team.NotifyActivity(m.ID, "delegation", fmt.Sprintf("Delegated to %s: %s", target.DisplayName(), truncateMessage(action.Content, 100)))
-	m.Team.NotifyActivity(target.ID, "task_received", fmt.Sprintf("Received task from %s", m.DisplayName()))
+		// Notify about the delegation
+		m.Team.NotifyActivity(ctx, m.ID, "delegation", fmt.Sprintf("Delegated to %s: %s", target.DisplayName(), truncateMessage(action.Content, 100)))
+		m.Team.NotifyActivity(ctx, target.ID, "task_received", fmt.Sprintf("Received task from %s", m.DisplayName()))
+		m.Team.shareInternal(ctx, m.ID, fmt.Sprintf("Delegated to %s: %s", target.RoleName, action.Content))
+		m.Team.shareProgress(ctx, m.ID, fmt.Sprintf("Delegated to %s, waiting...", target.DisplayName()))
+	}
 
 	// Wait for the delegated task to complete
 	select {
-	case <-m.ctx.Done():
-		m.logger.Warn("context cancelled while waiting for delegation")
+	case <-ctx.Done():
+		m.log(ctx).Warn("context cancelled while waiting for delegation")
 		return
 	case result := <-task.ResultChan:
 		if result != nil {
 			if result.Success {
+				m.Team.shareInternal(ctx, target.ID, fmt.Sprintf("Result from %s: %s", target.RoleName, result.Content))
+				m.Team.shareProgress(ctx, m.ID, fmt.Sprintf("%s finished, reviewing the result...", target.DisplayName()))
 				// Process the result - let the member decide what to do next
-				m.processTaskResult(result.Content, target.RoleName, originalMsg)
+				m.processTaskResult(ctx, result.Content, target.RoleName, originalMsg)
 			} else {
-				m.respondToClient(fmt.Sprintf("Task failed: %s", result.Error))
+				m.respondToClient(ctx, fmt.Sprintf("Task failed: %s", result.Error))
 			}
 		} else {
-			m.respondToClient("Task completed without result")
+			m.sendStatusToClient(ctx, "Task completed without result")
 		}
 	}
 }
 
 // processTaskResult handles the result from a delegated task
 // The member can decide to delegate further, respond to client, etc.
-func (m *Member) processTaskResult(result, fromRole string, originalMsg Message) {
-	m.setStatus(MemberWorking)
-	defer m.setStatus(MemberIdle)
+func (m *Member) processTaskResult(ctx context.Context, result, fromRole string, originalMsg Message) {
+	m.setStatus(ctx, MemberWorking)
+	defer m.setStatus(ctx, MemberIdle)
 
 	// Build context with the delegation result
 	messages := []provider.Message{
@@ -993,18 +1236,24 @@ func (m *Member) processTaskResult(result, fromRole string, originalMsg Message)
 	messages = append(messages, m.getContextMessages()...)
 
 	// Add the result as context
-	prompt := fmt.Sprintf("The %s completed their task.\n\nResult: %s\n\nChoose ONE action (output ONLY that action, no preamble):\n1. DELEGATE TO [role]: [task] - if more work needed\n2. [short client message] - if all done, just write the message directly\n\nIMPORTANT: Never write 'Let me...' or explain yourself. Just output the action.", fromRole, result)
+	options := "1. DELEGATE TO [role]: [task] - if more work needed\n2. [short client message] - if all done, just write the message directly\n"
+	if len(m.Role.CanHandoff) > 0 {
+		options += fmt.Sprintf("3. HANDOFF TO [role]: [what they need to know, including this result] - if one of %s should take over the conversation with the client\n", strings.Join(m.Role.CanHandoff, ", "))
+	}
+	prompt := fmt.Sprintf("The %s completed their task.\n\nResult: %s\n\nChoose ONE action (output ONLY that action, no preamble):\n%s\nIMPORTANT: Never write 'Let me...' or explain yourself. Just output the action.", fromRole, result, options)
 	messages = append(messages, provider.Message{Role: "user", Content: prompt})
 
 	// Get response from LLM
-	resp, err := m.Provider.Chat(m.ctx, &provider.ChatRequest{
-		Model:    m.Role.Model.Model,
-		Messages: messages,
+	resp, err := m.chat(ctx, &provider.ChatRequest{
+		Model:       m.getEffectiveModel(),
+		Messages:    messages,
+		Temperature: m.Role.Model.Temperature,
+		MaxTokens:   m.getEffectiveMaxTokens(),
 	})
 
 	if err != nil {
-		m.logger.Error("failed to process task result", "error", err)
-		m.respondToClient("Working on it!")
+		m.log(ctx).Error("failed to process task result", "error", err)
+		m.sendStatusToClient(ctx, "Working on it!")
 		return
 	}
 
@@ -1013,24 +1262,26 @@ func (m *Member) processTaskResult(result, fromRole string, originalMsg Message)
 
 	switch action.Type {
 	case "delegate":
-		m.handleDelegation(action, originalMsg)
+		m.handleDelegation(ctx, action, originalMsg)
 	case "parallel_delegate":
-		m.handleParallelDelegation(action, originalMsg)
+		m.handleParallelDelegation(ctx, action, originalMsg)
+	case "handoff":
+		m.handleHandoff(ctx, action, originalMsg)
 	case "question":
-		m.askClient(action.Content)
+		m.askClient(ctx, action.Content)
 	default:
-		m.respondToClient(action.Content)
+		m.respondToClient(ctx, action.Content)
 	}
 }
 
 // handleParallelDelegation delegates to multiple team members simultaneously
-func (m *Member) handleParallelDelegation(action responseAction, originalMsg Message) {
+func (m *Member) handleParallelDelegation(ctx context.Context, action responseAction, originalMsg Message) {
 	if len(action.ParallelTasks) == 0 {
-		m.respondToClient("No tasks to delegate")
+		m.sendStatusToClient(ctx, "No tasks to delegate")
 		return
 	}
 
-	m.logger.Info("starting parallel delegation", "count", len(action.ParallelTasks))
+	m.log(ctx).Info("starting parallel delegation", "count", len(action.ParallelTasks))
 
 	// Create tasks and result channels for all targets
 	type taskInfo struct {
@@ -1043,7 +1294,7 @@ func (m *Member) handleParallelDelegation(action responseAction, originalMsg Mes
 	for _, pt := range action.ParallelTasks {
 		target := m.Team.GetMemberByRole(pt.Role)
 		if target == nil {
-			m.logger.Warn("parallel delegation target not found", "target", pt.Role)
+			m.log(ctx).Warn("parallel delegation target not found", "target", pt.Role)
 			continue
 		}
 
@@ -1063,79 +1314,131 @@ func (m *Member) handleParallelDelegation(action responseAction, originalMsg Mes
 	}
 
 	if len(tasks) == 0 {
-		m.respondToClient("No valid delegation targets found")
+		m.sendStatusToClient(ctx, "No valid delegation targets found")
 		return
 	}
 
-	// Send all task assignments simultaneously
-	for _, ti := range tasks {
-		ti.target.Send(Message{
-			ID:        uuid.New().String(),
-			Type:      MsgTaskAssignment,
-			From:      m.ID,
-			To:        ti.target.ID,
-			Content:   ti.task,
-			TaskID:    ti.task.ID,
-			Timestamp: time.Now(),
-		})
-		m.logger.Info("parallel task sent", "to", ti.role, "task_id", ti.task.ID)
+	names := make([]string, len(tasks))
+	for i, ti := range tasks {
+		names[i] = ti.target.DisplayName()
 	}
+	m.Team.shareProgress(ctx, m.ID, fmt.Sprintf("Delegated to %s in parallel, waiting...", strings.Join(names, ", ")))
 
 	// Collect results from all tasks in parallel
 	type resultInfo struct {
+		index  int // Of the task in tasks
 		role   string
+		name   string
+		member string
 		result *TaskResult
 	}
 	resultsChan := make(chan resultInfo, len(tasks))
 
-	// Start goroutines to wait for each result
-	for _, ti := range tasks {
-		go func(role string, task *Task) {
+	// dispatch sends the next task and waits for its result in the background.
+	// The spec's delegation_stagger spaces out dispatches after the first.
+	settings := m.Team.Spec.Settings
+	next := 0
+	dispatch := func() {
+		ti := tasks[next]
+		if next > 0 && settings.DelegationStagger > 0 {
+			select {
+			case <-ctx.Done():
+			case <-time.After(settings.DelegationStagger):
+			}
+		}
+		next++
+
+		if err := ti.target.Send(Message{
+			ID:        uuid.New().String(),
+			Type:      MsgTaskAssignment,
+			From:      m.ID,
+			To:        ti.target.ID,
+			Content:   ti.task,
+			TaskID:    ti.task.ID,
+			RequestID: logger.RequestID(ctx),
+			Timestamp: time.Now(),
+		}); err != nil {
+			m.failUndelivered(ctx, ti.task, err)
+		} else {
+			m.log(ctx).Info("parallel task sent", "to", ti.role, "task_id", ti.task.ID)
+			m.Team.shareInternal(ctx, m.ID, fmt.Sprintf("Delegated to %s: %s", ti.role, ti.task.Content))
+		}
+
+		go func(r resultInfo, task *Task) {
 			select {
-			case <-m.ctx.Done():
-				resultsChan <- resultInfo{role: role, result: &TaskResult{Success: false, Error: "context cancelled"}}
-			case result := <-task.ResultChan:
-				resultsChan <- resultInfo{role: role, result: result}
+			case <-ctx.Done():
+				r.result = &TaskResult{Success: false, Error: "context cancelled"}
+			case r.result = <-task.ResultChan:
 			}
-		}(ti.role, ti.task)
+			if r.result == nil {
+				r.result = &TaskResult{Success: false, Error: "no result"}
+			}
+			resultsChan <- r
+		}(resultInfo{index: next - 1, role: ti.role, name: ti.target.DisplayName(), member: ti.target.ID}, ti.task)
+	}
+
+	// Send up to max_parallel_delegation tasks now, and each of the rest as
+	// an earlier one finishes
+	inFlight := len(tasks)
+	if settings.MaxParallelDelegation > 0 && settings.MaxParallelDelegation < inFlight {
+		inFlight = settings.MaxParallelDelegation
+	}
+	for next < inFlight {
+		dispatch()
 	}
 
 	// Collect all results
 	var results []resultInfo
 	for i := 0; i < len(tasks); i++ {
 		select {
-		case <-m.ctx.Done():
-			m.logger.Warn("context cancelled while waiting for parallel results")
-			m.respondToClient("Parallel tasks cancelled")
+		case <-ctx.Done():
+			m.log(ctx).Warn("context cancelled while waiting for parallel results")
+			m.sendStatusToClient(ctx, "Parallel tasks cancelled")
 			return
 		case r := <-resultsChan:
 			results = append(results, r)
-			m.logger.Info("parallel result received", "role", r.role, "success", r.result != nil && r.result.Success)
+			m.log(ctx).Info("parallel result received", "role", r.role, "success", r.result.Success)
+			if r.result.Success {
+				m.Team.shareInternal(ctx, m.ID, fmt.Sprintf("Result from %s: %s", r.role, r.result.Content))
+			} else {
+				m.Team.notifyActivity(ctx, m.ID, "delegation_failed",
+					fmt.Sprintf("%s failed: %s", r.name, r.result.Error),
+					map[string]interface{}{"role": r.role, "member": r.member, "task_id": tasks[r.index].task.ID, "error": r.result.Error})
+			}
+			m.Team.shareProgress(ctx, m.ID, fmt.Sprintf("%s finished (%d of %d)", r.name, len(results), len(tasks)))
+			if next < len(tasks) {
+				dispatch()
+			}
 		}
 	}
 
 	// Process results through LLM to generate a friendly client response
-	m.logger.Info("all parallel tasks complete, processing results")
+	m.log(ctx).Info("all parallel tasks complete, processing results")
 
+	// Report the branches in the order they were delegated
+	sort.Slice(results, func(i, j int) bool { return results[i].index < results[j].index })
+	delegation := make([]DelegationResult, len(results))
 	var resultSummary string
 	resultSummary = "Results from team:\n"
-	for _, r := range results {
-		if r.result != nil && r.result.Success {
+	for i, r := range results {
+		delegation[i] = DelegationResult{Role: r.role, Member: r.member, Success: r.result.Success, Content: r.result.Content, Error: r.result.Error}
+		if r.result.Success {
 			resultSummary += fmt.Sprintf("- %s: completed\n", r.role)
-		} else if r.result != nil {
+		} else {
 			resultSummary += fmt.Sprintf("- %s: failed - %s\n", r.role, r.result.Error)
 		}
 	}
 
-	// Let PM decide how to respond to client
-	m.processTaskResult(resultSummary, "team", originalMsg)
+	// Let PM decide how to respond to client. The response carries each
+	// branch's outcome for callers that need more than the PM's prose.
+	m.processTaskResult(withDelegation(ctx, delegation), resultSummary, "team", originalMsg)
 }
 
-func (m *Member) delegateToRole(roleName, content string, parentTask *Task) {
+func (m *Member) delegateToRole(ctx context.Context, roleName, content string, parentTask *Task) {
 	target := m.Team.GetMemberByRole(roleName)
 	if target == nil {
-		m.logger.Warn("delegation target not found", "target", roleName)
-		m.completeTask(parentTask, content)
+		m.log(ctx).Warn("delegation target not found", "target", roleName)
+		m.completeTask(ctx, parentTask, content)
 		return
 	}
 
@@ -1153,57 +1456,82 @@ func (m *Member) delegateToRole(roleName, content string, parentTask *Task) {
 
 	m.Team.AddTask(task)
 
-	target.Send(Message{
+	if err := target.Send(Message{
 		ID:        uuid.New().String(),
 		Type:      MsgTaskAssignment,
 		From:      m.ID,
 		To:        target.ID,
 		Content:   task,
 		TaskID:    task.ID,
+		RequestID: logger.RequestID(ctx),
 		Timestamp: time.Now(),
-	})
-
-	m.logger.Info("delegated subtask, waiting for result", "to", roleName, "task_id", task.ID)
+	}); err != nil {
+		m.failUndelivered(ctx, task, err)
+	} else {
+		m.log(ctx).Info("delegated subtask, waiting for result", "to", roleName, "task_id", task.ID)
+	}
 
 	// Wait for the delegated task to complete
 	select {
-	case <-m.ctx.Done():
-		m.logger.Warn("context cancelled while waiting for delegation")
+	case <-ctx.Done():
+		m.log(ctx).Warn("context cancelled while waiting for delegation")
 		return
 	case result := <-task.ResultChan:
 		if result != nil {
 			// Complete parent task with the delegated result
-			m.completeTask(parentTask, result.Content)
+			m.completeTask(ctx, parentTask, result.Content)
 		} else {
-			m.completeTask(parentTask, "Subtask completed without result")
+			m.completeTask(ctx, parentTask, "Subtask completed without result")
 		}
 	}
 }
 
-func (m *Member) askClient(question string) {
-	m.sendToTeam(Message{
-		ID:        uuid.New().String(),
-		Type:      MsgClientResponse,
-		From:      m.ID,
-		To:        "client",
-		Content:   question,
-		Timestamp: time.Now(),
+func (m *Member) askClient(ctx context.Context, question string) {
+	m.sendToTeam(ctx, Message{
+		ID:         uuid.New().String(),
+		Type:       MsgClientResponse,
+		From:       m.ID,
+		To:         "client",
+		Content:    question,
+		Delegation: delegationResults(ctx),
+		Timestamp:  time.Now(),
 	})
 }
 
-func (m *Member) respondToClient(content string) {
-	m.logger.Info("sending response to client", "content_len", len(content))
-	m.sendToTeam(Message{
-		ID:        uuid.New().String(),
-		Type:      MsgClientResponse,
-		From:      m.ID,
-		To:        "client",
-		Content:   content,
-		Timestamp: time.Now(),
+func (m *Member) respondToClient(ctx context.Context, content string) {
+	m.sendStatusToClient(ctx, m.editForClient(ctx, content))
+}
+
+// sendStatusToClient sends content to the client as written, for fixed
+// status lines that the editor has nothing to improve on
+func (m *Member) sendStatusToClient(ctx context.Context, content string) {
+	m.log(ctx).Info("sending response to client", "content_len", len(content))
+	m.sendToTeam(ctx, Message{
+		ID:         uuid.New().String(),
+		Type:       MsgClientResponse,
+		From:       m.ID,
+		To:         "client",
+		Content:    content,
+		Delegation: delegationResults(ctx),
+		Timestamp:  time.Now(),
 	})
 }
 
-func (m *Member) completeTask(task *Task, result string) {
+type delegationKey struct{}
+
+// withDelegation returns ctx carrying the results of a parallel delegation,
+// for the client response it leads to
+func withDelegation(ctx context.Context, results []DelegationResult) context.Context {
+	return context.WithValue(ctx, delegationKey{}, results)
+}
+
+// delegationResults returns the parallel delegation results ctx carries
+func delegationResults(ctx context.Context) []DelegationResult {
+	results, _ := ctx.Value(delegationKey{}).([]DelegationResult)
+	return results
+}
+
+func (m *Member) completeTask(ctx context.Context, task *Task, result string) {
 	task.Status = TaskCompleted
 	now := time.Now()
 	task.CompletedAt = &now
@@ -1214,6 +1542,9 @@ func (m *Member) completeTask(task *Task, result string) {
 
 	m.mu.Lock()
 	m.Task = nil
+	if m.Status != MemberIdle {
+		m.statusSince = time.Now()
+	}
 	m.Status = MemberIdle
 	m.mu.Unlock()
 
@@ -1222,12 +1553,12 @@ func (m *Member) completeTask(task *Task, result string) {
 		select {
 		case task.ResultChan <- task.Result:
 		default:
-			m.logger.Warn("result channel full or closed", "task_id", task.ID)
+			m.log(ctx).Warn("result channel full or closed", "task_id", task.ID)
 		}
 	}
 
 	// Report to who assigned the task
-	m.sendToTeam(Message{
+	m.sendToTeam(ctx, Message{
 		ID:        uuid.New().String(),
 		Type:      MsgTaskComplete,
 		From:      m.ID,
@@ -1238,12 +1569,27 @@ func (m *Member) completeTask(task *Task, result string) {
 	})
 
 	// Notify activity
-	m.Team.NotifyActivity(m.ID, "task_completed", fmt.Sprintf("Completed task: %s", truncateMessage(result, 100)))
+	m.Team.NotifyActivity(ctx, m.ID, "task_completed", fmt.Sprintf("Completed task: %s", truncateMessage(result, 100)))
+
+	m.log(ctx).Info("task completed", "task_id", task.ID)
+}
+
+// failUndelivered fails a task whose assignment never reached its assignee,
+// so the delegator gets a result instead of waiting forever
+func (m *Member) failUndelivered(ctx context.Context, task *Task, err error) {
+	m.log(ctx).Warn("task assignment not delivered", "task_id", task.ID, "to", task.To, "error", err)
 
-	m.logger.Info("task completed", "task_id", task.ID)
+	now := time.Now()
+	task.Status = TaskFailed
+	task.CompletedAt = &now
+	task.Result = &TaskResult{
+		Content: fmt.Sprintf("Task failed: %v", err),
+		Error:   err.Error(),
+	}
+	task.ResultChan <- task.Result
 }
 
-func (m *Member) reportTaskFailure(task *Task, err error) {
+func (m *Member) reportTaskFailure(ctx context.Context, task *Task, err error) {
 	task.Status = TaskFailed
 	now := time.Now()
 	task.CompletedAt = &now
@@ -1254,6 +1600,9 @@ func (m *Member) reportTaskFailure(task *Task, err error) {
 
 	m.mu.Lock()
 	m.Task = nil
+	if m.Status != MemberIdle {
+		m.statusSince = time.Now()
+	}
 	m.Status = MemberIdle
 	m.mu.Unlock()
 
@@ -1262,11 +1611,11 @@ func (m *Member) reportTaskFailure(task *Task, err error) {
 		select {
 		case task.ResultChan <- task.Result:
 		default:
-			m.logger.Warn("result channel full or closed", "task_id", task.ID)
+			m.log(ctx).Warn("result channel full or closed", "task_id", task.ID)
 		}
 	}
 
-	m.sendToTeam(Message{
+	m.sendToTeam(ctx, Message{
 		ID:        uuid.New().String(),
 		Type:      MsgTaskComplete,
 		From:      m.ID,
@@ -1276,22 +1625,41 @@ func (m *Member) reportTaskFailure(task *Task, err error) {
 		Timestamp: time.Now(),
 	})
 
-	m.logger.Error("task failed", "task_id", task.ID, "error", err)
+	m.log(ctx).Error("task failed", "task_id", task.ID, "error", err)
 }
 
-func (m *Member) setStatus(status MemberStatus) {
+func (m *Member) setStatus(ctx context.Context, status MemberStatus) {
 	m.mu.Lock()
+	if m.Status != status {
+		m.statusSince = time.Now()
+	}
 	m.Status = status
+	if status == MemberIdle {
+		m.progress = nil
+	}
 	m.mu.Unlock()
 
 	// Notify status change
-	m.Team.NotifyActivity(m.ID, "status_change", string(status))
+	m.Team.NotifyActivity(ctx, m.ID, "status_change", string(status))
 }
 
-func (m *Member) sendToTeam(msg Message) {
+// sendToTeam routes a message, tagging it with the request being handled.
+// Client responses also carry the model that produced them.
+func (m *Member) sendToTeam(ctx context.Context, msg Message) {
+	msg.RequestID = logger.RequestID(ctx)
+	if msg.Type == MsgClientResponse && msg.Model == "" {
+		m.mu.RLock()
+		msg.Provider, msg.Model = m.servedProvider, m.servedModel
+		m.mu.RUnlock()
+	}
 	m.Team.RouteMessage(msg)
 }
 
+// log returns the member's logger tagged with the request being handled
+func (m *Member) log(ctx context.Context) *logger.Logger {
+	return m.logger.WithContext(ctx)
+}
+
 // MarshalJSON for API responses
 func (m *Member) MarshalJSON() ([]byte, error) {
 	return json.Marshal(map[string]interface{}{
@@ -1334,6 +1702,23 @@ func truncateMessage(s string, maxLen int) string {
 	return s[:maxLen] + "..."
 }
 
+// truncateToolResult cuts s down to about max bytes, keeping its start and
+// end, which are usually the most useful parts of long output
+func truncateToolResult(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	head := max / 2
+	tail := max - head
+	for head > 0 && !utf8.RuneStart(s[head]) {
+		head--
+	}
+	for tail > 0 && !utf8.RuneStart(s[len(s)-tail]) {
+		tail--
+	}
+	return fmt.Sprintf("%s\n[output truncated, %d bytes omitted]\n%s", s[:head], len(s)-head-tail, s[len(s)-tail:])
+}
+
 func cleanRoleName(s string) string {
 	// Remove brackets, quotes, and extra whitespace
 	s = trim(s)
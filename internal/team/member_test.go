@@ -2,10 +2,22 @@ package team
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"time"
+	"unicode/utf8"
 
 	"github.com/arcslash/ugudu/internal/logger"
 	"github.com/arcslash/ugudu/internal/provider"
+	"github.com/arcslash/ugudu/internal/tools"
+	"github.com/arcslash/ugudu/internal/workspace"
 )
 
 // MockProvider implements provider.Provider for testing
@@ -154,6 +166,66 @@ func TestMember_RestoreContext(t *testing.T) {
 	}
 }
 
+func TestMember_ContextByteBudget(t *testing.T) {
+	log := logger.New("error")
+
+	spec := &TeamSpec{
+		Metadata: Metadata{Name: "test-team"},
+		Roles: map[string]Role{
+			"pm": {
+				Title: "PM",
+				Count: 1,
+				Model: ModelConfig{Provider: "mock", Model: "mock-model"},
+			},
+		},
+		Settings: TeamSettings{Token: TokenSettings{Mode: TokenModeNormal, ContextHistory: 40, ContextBytes: 10000}},
+	}
+
+	team := &Team{
+		Name:          "test-team",
+		Spec:          spec,
+		Members:       make(map[string]*Member),
+		MembersByRole: make(map[string][]*Member),
+		logger:        log,
+	}
+	member := NewMember("pm", "Sarah", "pm", spec.Roles["pm"], team, &MockProvider{}, log)
+
+	// Five 4KB messages are well under the count limit but over the budget
+	for i := 0; i < 5; i++ {
+		member.addToContext("user", fmt.Sprintf("%d%s", i, strings.Repeat("x", 4000)))
+	}
+	ctx := member.getContextMessages()
+	if len(ctx) != 2 {
+		t.Fatalf("Expected the byte budget to keep the newest 2 messages, got %d", len(ctx))
+	}
+	if ctx[0].Content[0] != '3' || ctx[1].Content[0] != '4' {
+		t.Errorf("Expected the oldest messages dropped, kept %c and %c", ctx[0].Content[0], ctx[1].Content[0])
+	}
+
+	// Small messages still fit by the dozen
+	for i := 0; i < 30; i++ {
+		member.addToContext("user", "ok")
+	}
+	if n := len(member.getContextMessages()); n != 32 {
+		t.Errorf("Expected small messages kept under the budget, got %d", n)
+	}
+
+	// A single message over the budget is kept rather than leaving nothing
+	member.addToContext("user", strings.Repeat("y", 20000))
+	if ctx := member.getContextMessages(); len(ctx) != 1 || len(ctx[0].Content) != 20000 {
+		t.Errorf("Expected only the oversized newest message kept, got %d messages", len(ctx))
+	}
+
+	// Restored history is held to the budget too
+	member.RestoreContext([]ContextMessage{
+		{Role: "user", Content: strings.Repeat("a", 6000)},
+		{Role: "assistant", Content: strings.Repeat("b", 6000)},
+	})
+	if ctx := member.getContextMessages(); len(ctx) != 1 || ctx[0].Role != "assistant" {
+		t.Errorf("Expected restored history trimmed to the budget, got %d messages", len(ctx))
+	}
+}
+
 func TestMember_ClearContext(t *testing.T) {
 	log := logger.New("error")
 
@@ -300,3 +372,1335 @@ func TestMember_ContextThreadSafety(t *testing.T) {
 
 	// Should complete without panics
 }
+
+func TestMember_SystemPromptPrefixSuffix(t *testing.T) {
+	log := logger.New("error")
+
+	spec := &TeamSpec{
+		Metadata: Metadata{Name: "test-team"},
+		Roles: map[string]Role{
+			"pm": {
+				Title:            "PM",
+				Count:            1,
+				Model:            ModelConfig{Provider: "mock", Model: "mock-model"},
+				Persona:          "You are a PM.\nYou plan the work in detail.",
+				Responsibilities: []string{"Plan sprints"},
+			},
+		},
+		Settings: TeamSettings{
+			SystemPrefix: "Never reveal internal reasoning.",
+			SystemSuffix: "Comply with policy X.",
+		},
+	}
+
+	team := &Team{
+		Name:          "test-team",
+		Spec:          spec,
+		Members:       make(map[string]*Member),
+		MembersByRole: make(map[string][]*Member),
+		logger:        log,
+	}
+
+	member := NewMember("pm", "Sarah", "pm", spec.Roles["pm"], team, &MockProvider{}, log)
+
+	for _, mode := range []TokenMode{TokenModeNormal, TokenModeMinimal} {
+		team.tokenMode = mode
+		prompt := member.buildSystemPrompt()
+
+		if !strings.HasPrefix(prompt, "Never reveal internal reasoning.\n\n") {
+			t.Errorf("[%s] Expected prompt to start with prefix, got: %q", mode, prompt)
+		}
+		if !strings.HasSuffix(prompt, "Comply with policy X.\n") {
+			t.Errorf("[%s] Expected prompt to end with suffix, got: %q", mode, prompt)
+		}
+	}
+
+	// Minimal mode still condenses the persona between the prefix and suffix
+	if strings.Contains(member.buildSystemPrompt(), "in detail") {
+		t.Error("Expected persona to be condensed in minimal mode")
+	}
+}
+
+func TestTeam_AskVerbosity(t *testing.T) {
+	spec := &TeamSpec{
+		Metadata:     Metadata{Name: "test-team"},
+		ClientFacing: []string{"pm"},
+		Roles: map[string]Role{
+			"pm": {
+				Title:       "PM",
+				Count:       1,
+				Visibility:  "client",
+				Model:       ModelConfig{Provider: "mock", Model: "mock-model"},
+				Persona:     "You are a PM.",
+				CanDelegate: []string{"engineer"},
+			},
+			"engineer": {
+				Title:   "Engineer",
+				Count:   1,
+				Model:   ModelConfig{Provider: "mock", Model: "mock-model"},
+				Persona: "You are an engineer.",
+			},
+		},
+	}
+
+	// The PM delegates the request, then reports back once the engineer is done
+	mockProv := &MockProvider{ChatFunc: func(req *provider.ChatRequest) (*provider.ChatResponse, error) {
+		last := req.Messages[len(req.Messages)-1].Content
+		switch {
+		case strings.HasPrefix(req.Messages[0].Content, "You are an engineer."):
+			return &provider.ChatResponse{Content: "Built the login page."}, nil
+		case strings.HasPrefix(last, "The engineer completed"):
+			return &provider.ChatResponse{Content: "All done."}, nil
+		default:
+			return &provider.ChatResponse{Content: "DELEGATE TO engineer: build the login page"}, nil
+		}
+	}}
+
+	// ask runs one request on a fresh team and collects messages up to the final response
+	ask := func(opts ...AskOption) []Message {
+		registry := provider.NewRegistry()
+		registry.Register(mockProv)
+
+		tm, err := NewTeam(spec, registry, logger.New("error"))
+		if err != nil {
+			t.Fatalf("NewTeam failed: %v", err)
+		}
+		if err := tm.Start(context.Background()); err != nil {
+			t.Fatalf("Start failed: %v", err)
+		}
+		defer tm.Stop()
+
+		var messages []Message
+		responses := tm.Ask("Build a login page", opts...)
+		for {
+			select {
+			case msg := <-responses:
+				messages = append(messages, msg)
+				if msg.Type == MsgClientResponse {
+					return messages
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatalf("Timed out waiting for response, got %v", messages)
+			}
+		}
+	}
+
+	quiet := ask(WithVerbosity(VerbosityQuiet))
+	if len(quiet) != 1 || quiet[0].Content != "All done." {
+		t.Errorf("Expected only the final response in quiet mode, got %v", quiet)
+	}
+
+	// Progress updates come first, and only the last message is the answer
+	summary := ask()
+	var progress []string
+	for _, msg := range summary[:len(summary)-1] {
+		if msg.Type != MsgProgress {
+			t.Errorf("Expected only progress before the answer in summary mode, got %s: %v", msg.Type, msg.Content)
+			continue
+		}
+		progress = append(progress, msg.Content.(string))
+	}
+	want := []string{"Delegated to Engineer, waiting...", "Engineer finished, reviewing the result..."}
+	if strings.Join(progress, "|") != strings.Join(want, "|") {
+		t.Errorf("Expected progress %q, got %q", want, progress)
+	}
+	if final := summary[len(summary)-1]; final.Type != MsgClientResponse || final.Content != "All done." {
+		t.Errorf("Expected the final response last, got %s: %v", final.Type, final.Content)
+	}
+
+	full := ask(WithVerbosity(VerbosityFull))
+	var internal []string
+	for _, msg := range full[:len(full)-1] {
+		switch msg.Type {
+		case MsgInternal:
+			internal = append(internal, msg.Content.(string))
+		case MsgProgress:
+		default:
+			t.Errorf("Expected intermediate messages to be internal or progress, got %s", msg.Type)
+		}
+	}
+	joined := strings.Join(internal, "\n")
+	if !strings.Contains(joined, "Delegated to engineer: build the login page") {
+		t.Errorf("Expected delegation in internal messages, got %q", joined)
+	}
+	if !strings.Contains(joined, "Result from engineer: Built the login page.") {
+		t.Errorf("Expected delegation result in internal messages, got %q", joined)
+	}
+	if final := full[len(full)-1]; final.Content != "All done." {
+		t.Errorf("Expected final response last, got %v", final.Content)
+	}
+}
+
+func TestTeam_AskMemberWaitsForDelegatedAnswer(t *testing.T) {
+	spec := limitsSpec(1)
+	pm := spec.Roles["pm"]
+	pm.CanDelegate = []string{"engineer"}
+	spec.Roles["pm"] = pm
+
+	// The PM delegates, then answers once the engineer is done
+	registry := provider.NewRegistry()
+	registry.Register(&MockProvider{ChatFunc: func(req *provider.ChatRequest) (*provider.ChatResponse, error) {
+		last := req.Messages[len(req.Messages)-1].Content
+		switch {
+		case strings.Contains(req.Messages[0].Content, "Engineer"):
+			return &provider.ChatResponse{Content: "Built the login page."}, nil
+		case strings.HasPrefix(last, "The engineer completed"):
+			return &provider.ChatResponse{Content: "The login page is ready."}, nil
+		case last == "Status?":
+			return &provider.ChatResponse{Content: "Everything's shipped."}, nil
+		default:
+			return &provider.ChatResponse{Content: "DELEGATE TO engineer: build the login page"}, nil
+		}
+	}})
+
+	tm, err := NewTeam(spec, registry, logger.New("error"))
+	if err != nil {
+		t.Fatalf("NewTeam failed: %v", err)
+	}
+	if err := tm.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer tm.Stop()
+
+	// collect gathers one ask's messages until its channel closes
+	collect := func(responses <-chan Message) []Message {
+		var messages []Message
+		for {
+			select {
+			case msg, ok := <-responses:
+				if !ok {
+					return messages
+				}
+				messages = append(messages, msg)
+			case <-time.After(5 * time.Second):
+				t.Fatalf("Timed out waiting for the response to finish, got %v", messages)
+			}
+		}
+	}
+
+	messages := collect(tm.AskMember("pm", "Build a login page"))
+	if len(messages) == 0 {
+		t.Fatal("Expected a response")
+	}
+	if final := messages[len(messages)-1]; final.Type != MsgClientResponse || final.Content != "The login page is ready." {
+		t.Errorf("Expected the delegated answer last, got %s: %v", final.Type, final.Content)
+	}
+	for _, msg := range messages[:len(messages)-1] {
+		if msg.Type != MsgProgress {
+			t.Errorf("Expected only progress before the answer, got %s: %v", msg.Type, msg.Content)
+		}
+	}
+
+	// The next ask gets its own answer, not anything left from the first
+	messages = collect(tm.AskMember("pm", "Status?", WithVerbosity(VerbosityQuiet)))
+	if len(messages) != 1 || messages[0].Content != "Everything's shipped." {
+		t.Errorf("Expected only the second ask's answer, got %v", messages)
+	}
+}
+
+func TestMember_TaskBoardTools(t *testing.T) {
+	t.Setenv("UGUDU_HOME", t.TempDir())
+	t.Setenv("UGUDU_PROJECTS", t.TempDir())
+
+	ws, err := workspace.Init("board-test", t.TempDir(), "test-team")
+	if err != nil {
+		t.Fatalf("Init workspace failed: %v", err)
+	}
+	story := &workspace.Task{Title: "Build login page", AssignedTo: "engineer", CreatedBy: "pm"}
+	if err := ws.Tasks().Create(story); err != nil {
+		t.Fatalf("Create task failed: %v", err)
+	}
+
+	spec := &TeamSpec{
+		Metadata:     Metadata{Name: "test-team"},
+		ClientFacing: []string{"engineer"},
+		Roles: map[string]Role{
+			"engineer": {
+				Title:      "Engineer",
+				Count:      1,
+				Visibility: "client",
+				Model:      ModelConfig{Provider: "mock", Model: "mock-model"},
+				Persona:    "You are an engineer.",
+			},
+		},
+	}
+
+	// The engineer lists its tasks, marks the story done, then reports back
+	var listed string
+	mockProv := &MockProvider{ChatFunc: func(req *provider.ChatRequest) (*provider.ChatResponse, error) {
+		last := req.Messages[len(req.Messages)-1]
+		switch {
+		case last.Role == "user":
+			return &provider.ChatResponse{ToolCalls: []provider.ToolCall{
+				{ID: "call-1", Name: "list_tasks", Arguments: `{"assigned_to":"engineer"}`},
+			}}, nil
+		case last.ToolCallID == "call-1":
+			listed = last.Content
+			args, _ := json.Marshal(map[string]string{"id": story.ID, "status": "done", "note": "Login page shipped"})
+			return &provider.ChatResponse{ToolCalls: []provider.ToolCall{
+				{ID: "call-2", Name: "update_task_status", Arguments: string(args)},
+			}}, nil
+		default:
+			return &provider.ChatResponse{Content: "Login page is done."}, nil
+		}
+	}}
+
+	registry := provider.NewRegistry()
+	registry.Register(mockProv)
+	tm, err := NewTeam(spec, registry, logger.New("error"))
+	if err != nil {
+		t.Fatalf("NewTeam failed: %v", err)
+	}
+	tm.SetWorkspace(ws)
+	if err := tm.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer tm.Stop()
+
+	select {
+	case msg := <-tm.AskMember("engineer", "Finish your story"):
+		if msg.Content != "Login page is done." {
+			t.Errorf("Unexpected response: %v", msg.Content)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for response")
+	}
+
+	if !strings.Contains(listed, story.ID) || !strings.Contains(listed, "Build login page") {
+		t.Errorf("Expected list_tasks to return the story, got %s", listed)
+	}
+
+	// A fresh store reads the file, so this is what the board sees
+	task, err := workspace.NewTaskStore(ws).Get(story.ID)
+	if err != nil {
+		t.Fatalf("Get task failed: %v", err)
+	}
+	if task.Status != "completed" {
+		t.Errorf("Expected task to be completed, got %s", task.Status)
+	}
+	if len(task.Comments) != 1 || task.Comments[0].Content != "Login page shipped" {
+		t.Errorf("Expected the note as a comment, got %v", task.Comments)
+	}
+}
+
+func TestMember_GetStandupTool(t *testing.T) {
+	t.Setenv("UGUDU_HOME", t.TempDir())
+	t.Setenv("UGUDU_PROJECTS", t.TempDir())
+
+	ws, err := workspace.Init("standup-test", t.TempDir(), "test-team")
+	if err != nil {
+		t.Fatalf("Init workspace failed: %v", err)
+	}
+	done := &workspace.Task{Title: "Build login page", Status: "completed", AssignedTo: "engineer", CreatedBy: "pm"}
+	if err := ws.Tasks().Create(done); err != nil {
+		t.Fatalf("Create task failed: %v", err)
+	}
+
+	// The PM reads the standup before answering
+	var standup string
+	registry := provider.NewRegistry()
+	registry.Register(&MockProvider{ChatFunc: func(req *provider.ChatRequest) (*provider.ChatResponse, error) {
+		last := req.Messages[len(req.Messages)-1]
+		if last.Role == "user" {
+			return &provider.ChatResponse{ToolCalls: []provider.ToolCall{
+				{ID: "call-1", Name: "get_standup", Arguments: `{"period":"daily"}`},
+			}}, nil
+		}
+		standup = last.Content
+		return &provider.ChatResponse{Content: "One task finished yesterday."}, nil
+	}})
+
+	tm, err := NewTeam(limitsSpec(1), registry, logger.New("error"))
+	if err != nil {
+		t.Fatalf("NewTeam failed: %v", err)
+	}
+	tm.SetWorkspace(ws)
+	if err := tm.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer tm.Stop()
+
+	select {
+	case msg := <-tm.AskMember("pm", "What happened yesterday?"):
+		if msg.Content != "One task finished yesterday." {
+			t.Errorf("Unexpected response: %v", msg.Content)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for response")
+	}
+
+	if !strings.Contains(standup, "Standup Report: standup-test") || !strings.Contains(standup, "1 task(s) completed") {
+		t.Errorf("Expected get_standup to return the project's standup, got %s", standup)
+	}
+}
+
+// stallingProvider never answers, and reports the context's error without
+// wrapping it, as some providers do
+type stallingProvider struct{ MockProvider }
+
+func (p *stallingProvider) Chat(ctx context.Context, _ *provider.ChatRequest) (*provider.ChatResponse, error) {
+	<-ctx.Done()
+	return nil, fmt.Errorf("mock: request failed: %v", ctx.Err())
+}
+
+func TestMember_RequestTimeout(t *testing.T) {
+	spec := limitsSpec(1)
+	spec.Settings.RequestTimeout = 50 * time.Millisecond
+
+	registry := provider.NewRegistry()
+	registry.Register(&stallingProvider{})
+	tm, err := NewTeam(spec, registry, logger.New("error"))
+	if err != nil {
+		t.Fatalf("NewTeam failed: %v", err)
+	}
+	var mu sync.Mutex
+	var activities []string
+	tm.persistence = &PersistenceCallbacks{
+		OnActivity: func(_, _, activityType, _, _ string, _ map[string]interface{}) {
+			mu.Lock()
+			activities = append(activities, activityType)
+			mu.Unlock()
+		},
+	}
+	if err := tm.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer tm.Stop()
+
+	select {
+	case msg := <-tm.AskMember("pm", "Plan the launch"):
+		content, _ := msg.Content.(string)
+		if !strings.Contains(content, "within 1 second (settings.request_timeout)") || strings.Contains(content, "deadline exceeded") {
+			t.Errorf("Expected a timeout message, got %q", content)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for response")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	found := false
+	for _, a := range activities {
+		if a == "request_timeout" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected a request_timeout activity, got %v", activities)
+	}
+
+	// A canceled call reads as a cancellation, not a timeout
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = tm.MembersWithRole("pm")[0].chat(ctx, &provider.ChatRequest{})
+	if !errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestMember_AskWithoutTools(t *testing.T) {
+	var mu sync.Mutex
+	var requests []*provider.ChatRequest
+	registry := provider.NewRegistry()
+	registry.Register(&MockProvider{ChatFunc: func(req *provider.ChatRequest) (*provider.ChatResponse, error) {
+		mu.Lock()
+		requests = append(requests, req)
+		mu.Unlock()
+		return &provider.ChatResponse{Content: "Hi!"}, nil
+	}})
+
+	tm, err := NewTeam(limitsSpec(1), registry, logger.New("error"))
+	if err != nil {
+		t.Fatalf("NewTeam failed: %v", err)
+	}
+	if err := tm.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer tm.Stop()
+
+	// Wait on the model calls rather than the replies: both asks listen on
+	// the team's client channel, so either may receive the other's reply
+	for i, opts := range [][]AskOption{nil, {WithoutTools()}} {
+		tm.AskMember("engineer", "Hello", opts...)
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			mu.Lock()
+			n := len(requests)
+			mu.Unlock()
+			if n > i {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatal("Timed out waiting for the model call")
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	withTools, withoutTools := requests[0], requests[1]
+	if len(withTools.Tools) == 0 || !strings.Contains(withTools.Messages[0].Content, "Available tools:") {
+		t.Fatalf("Expected a normal ask to offer the engineer's tools")
+	}
+	if len(withoutTools.Tools) != 0 {
+		t.Errorf("Expected no tools sent, got %d", len(withoutTools.Tools))
+	}
+	if strings.Contains(withoutTools.Messages[0].Content, "Available tools:") || strings.Contains(withoutTools.Messages[0].Content, "read_file") {
+		t.Errorf("Expected no tool section in the system prompt, got:\n%s", withoutTools.Messages[0].Content)
+	}
+}
+
+// backupProvider is a second mock registered under its own ID
+type backupProvider struct{ MockProvider }
+
+func (b *backupProvider) ID() string { return "backup" }
+
+func TestMember_FallsBackWhileProviderUnhealthy(t *testing.T) {
+	spec := &TeamSpec{
+		Metadata:     Metadata{Name: "test-team"},
+		ClientFacing: []string{"pm"},
+		Roles: map[string]Role{
+			"pm": {
+				Title:      "PM",
+				Count:      1,
+				Visibility: "client",
+				Model: ModelConfig{
+					Provider: "mock",
+					Model:    "mock-model",
+					Fallback: []ModelConfig{{Provider: "backup", Model: "backup-model"}},
+				},
+			},
+		},
+	}
+
+	reply := func(content string) func(*provider.ChatRequest) (*provider.ChatResponse, error) {
+		return func(req *provider.ChatRequest) (*provider.ChatResponse, error) {
+			return &provider.ChatResponse{Content: content, Model: req.Model}, nil
+		}
+	}
+	registry := provider.NewRegistry()
+	registry.Register(&MockProvider{ChatFunc: reply("from primary")})
+	registry.Register(&backupProvider{MockProvider{ChatFunc: reply("from backup")}})
+
+	tm, err := NewTeam(spec, registry, logger.New("error"))
+	if err != nil {
+		t.Fatalf("NewTeam failed: %v", err)
+	}
+	if err := tm.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer tm.Stop()
+
+	ask := func() Message {
+		t.Helper()
+		select {
+		case msg := <-tm.AskMember("pm", "Status?"):
+			return msg
+		case <-time.After(5 * time.Second):
+			t.Fatal("Timed out waiting for response")
+			return Message{}
+		}
+	}
+
+	registry.RecordResult("mock", &provider.AuthError{Provider: "Mock", StatusCode: 401})
+	if msg := ask(); msg.Content != "from backup" || msg.Provider != "backup" || msg.Model != "backup-model" {
+		t.Errorf("Expected the fallback to answer, got %q from %s/%s", msg.Content, msg.Provider, msg.Model)
+	}
+
+	// Once the primary recovers it is used again
+	registry.RecordResult("mock", nil)
+	if msg := ask(); msg.Content != "from primary" {
+		t.Errorf("Expected the primary to answer after recovering, got %q", msg.Content)
+	}
+}
+
+func TestMember_QuestionUsesRoleSampling(t *testing.T) {
+	temperature, topP, maxTokens := 0.2, 0.9, 512
+	spec := limitsSpec(1)
+	engineer := spec.Roles["engineer"]
+	engineer.Model = ModelConfig{
+		Provider:    "mock",
+		Model:       "mock-model",
+		Temperature: &temperature,
+		TopP:        &topP,
+		MaxTokens:   &maxTokens,
+		Stop:        []string{"END"},
+	}
+	spec.Roles["engineer"] = engineer
+
+	requests := make(chan *provider.ChatRequest, 1)
+	registry := provider.NewRegistry()
+	registry.Register(&MockProvider{ChatFunc: func(req *provider.ChatRequest) (*provider.ChatResponse, error) {
+		requests <- req
+		return &provider.ChatResponse{Content: "Yes."}, nil
+	}})
+	tm, err := NewTeam(spec, registry, logger.New("error"))
+	if err != nil {
+		t.Fatalf("NewTeam failed: %v", err)
+	}
+
+	tm.GetMemberByRole("engineer").handleQuestion(context.Background(), Message{From: "pm", Content: "Is the build green?"})
+
+	req := <-requests
+	if req.Temperature == nil || *req.Temperature != temperature {
+		t.Errorf("Expected temperature %v, got %v", temperature, req.Temperature)
+	}
+	if req.TopP == nil || *req.TopP != topP {
+		t.Errorf("Expected top_p %v, got %v", topP, req.TopP)
+	}
+	if req.MaxTokens == nil || *req.MaxTokens != maxTokens {
+		t.Errorf("Expected max tokens %d, got %v", maxTokens, req.MaxTokens)
+	}
+	if len(req.Stop) != 1 || req.Stop[0] != "END" {
+		t.Errorf("Expected stop sequences [END], got %v", req.Stop)
+	}
+}
+
+func TestTeam_ConcurrentMemberAccess(t *testing.T) {
+	t.Setenv("UGUDU_HOME", t.TempDir())
+	t.Setenv("UGUDU_PROJECTS", t.TempDir())
+
+	ws, err := workspace.Init("race-test", t.TempDir(), "test-team")
+	if err != nil {
+		t.Fatalf("Init workspace failed: %v", err)
+	}
+
+	spec := &TeamSpec{
+		Metadata:     Metadata{Name: "test-team"},
+		ClientFacing: []string{"pm"},
+		Roles: map[string]Role{
+			"pm":       {Title: "PM", Count: 1, Visibility: "client", Model: ModelConfig{Provider: "mock", Model: "mock-model"}},
+			"engineer": {Title: "Engineer", Count: 3, Model: ModelConfig{Provider: "mock", Model: "mock-model"}},
+		},
+	}
+
+	registry := provider.NewRegistry()
+	registry.Register(&MockProvider{})
+	tm, err := NewTeam(spec, registry, logger.New("error"))
+	if err != nil {
+		t.Fatalf("NewTeam failed: %v", err)
+	}
+
+	// Readers hammer the member accessors while the team is started,
+	// stopped and re-pointed at a workspace. Run with -race.
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				for _, m := range tm.ListMembers() {
+					tm.GetMember(m.ID)
+				}
+				tm.GetMemberByRole("engineer")
+				tm.MembersWithRole("engineer")
+				tm.Status()
+			}
+		}()
+	}
+
+	for i := 0; i < 10; i++ {
+		if err := tm.Start(context.Background()); err != nil {
+			t.Fatalf("Start failed: %v", err)
+		}
+		tm.SetWorkspace(ws)
+		tm.Stop()
+	}
+	close(done)
+	wg.Wait()
+
+	if n := len(tm.ListMembers()); n != 4 {
+		t.Errorf("Expected 4 members, got %d", n)
+	}
+}
+
+// lockedBuffer collects log output written from member goroutines
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf strings.Builder
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestTeam_RequestIDThreadedThroughLogs(t *testing.T) {
+	t.Setenv("UGUDU_HOME", t.TempDir())
+	t.Setenv("UGUDU_PROJECTS", t.TempDir())
+
+	ws, err := workspace.Init("request-id-test", t.TempDir(), "test-team")
+	if err != nil {
+		t.Fatalf("Init workspace failed: %v", err)
+	}
+
+	spec := &TeamSpec{
+		Metadata:     Metadata{Name: "test-team"},
+		ClientFacing: []string{"pm"},
+		Roles: map[string]Role{
+			"pm": {
+				Title:       "PM",
+				Count:       1,
+				Visibility:  "client",
+				Model:       ModelConfig{Provider: "mock", Model: "mock-model"},
+				Persona:     "You are a PM.",
+				CanDelegate: []string{"engineer"},
+			},
+			"engineer": {
+				Title:   "Engineer",
+				Count:   1,
+				Model:   ModelConfig{Provider: "mock", Model: "mock-model"},
+				Persona: "You are an engineer.",
+			},
+		},
+	}
+
+	// The PM delegates, the engineer uses a tool before answering
+	mockProv := &MockProvider{ChatFunc: func(req *provider.ChatRequest) (*provider.ChatResponse, error) {
+		last := req.Messages[len(req.Messages)-1]
+		switch {
+		case strings.HasPrefix(req.Messages[0].Content, "You are an engineer.") && last.Role == "user":
+			return &provider.ChatResponse{ToolCalls: []provider.ToolCall{
+				{ID: "call-1", Name: "list_tasks", Arguments: `{}`},
+			}}, nil
+		case strings.HasPrefix(req.Messages[0].Content, "You are an engineer."):
+			return &provider.ChatResponse{Content: "Built the login page."}, nil
+		case strings.HasPrefix(last.Content, "The engineer completed"):
+			return &provider.ChatResponse{Content: "All done."}, nil
+		default:
+			return &provider.ChatResponse{Content: "DELEGATE TO engineer: build the login page"}, nil
+		}
+	}}
+
+	var mu sync.Mutex
+	activity := map[string]string{} // activity type -> request ID
+	persistence := &PersistenceCallbacks{
+		OnActivity: func(teamName, memberID, activityType, message, requestID string, data map[string]interface{}) {
+			mu.Lock()
+			activity[activityType] = requestID
+			mu.Unlock()
+		},
+	}
+
+	var logs lockedBuffer
+	registry := provider.NewRegistry()
+	registry.Register(mockProv)
+	tm, err := NewTeamWithPersistence(spec, registry, logger.New("debug", &logs), persistence)
+	if err != nil {
+		t.Fatalf("NewTeam failed: %v", err)
+	}
+	tm.SetWorkspace(ws)
+	if err := tm.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer tm.Stop()
+
+	responses := tm.Ask("Build a login page", WithRequestID("req-123"))
+	for done := false; !done; {
+		select {
+		case msg := <-responses:
+			if msg.RequestID != "req-123" {
+				t.Errorf("Expected response tagged with the request ID, got %q", msg.RequestID)
+			}
+			done = msg.Type == MsgClientResponse
+		case <-time.After(5 * time.Second):
+			t.Fatal("Timed out waiting for response")
+		}
+	}
+
+	for _, want := range []string{"delegated task, waiting for result", "executing tool", "calling model"} {
+		found := false
+		for _, line := range strings.Split(logs.String(), "\n") {
+			if !strings.Contains(line, want) {
+				continue
+			}
+			found = true
+			if !strings.Contains(line, "request_id=req-123") {
+				t.Errorf("Expected request ID on %q line, got %s", want, line)
+			}
+		}
+		if !found {
+			t.Errorf("Expected a %q log line, got:\n%s", want, logs.String())
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, activityType := range []string{"delegation", "tool_call"} {
+		if activity[activityType] != "req-123" {
+			t.Errorf("Expected %s activity tagged with the request ID, got %q", activityType, activity[activityType])
+		}
+	}
+}
+
+func TestMember_InvalidToolArgs(t *testing.T) {
+	registry := provider.NewRegistry()
+	registry.Register(&MockProvider{})
+	tm, err := NewTeam(limitsSpec(1), registry, logger.New("error"))
+	if err != nil {
+		t.Fatalf("NewTeam failed: %v", err)
+	}
+	engineer := tm.MembersByRole["engineer"][0]
+	marker := filepath.Join(t.TempDir(), "ran")
+
+	tests := []struct {
+		args    string
+		problem string
+	}{
+		{`{"timeout":30}`, `missing required field \"command\"`},
+		{`{"command":"touch ` + marker + `","timeout":"30"}`, `\"timeout\": expected number, got string`},
+	}
+	for _, tt := range tests {
+		results, err := engineer.executeToolCalls(context.Background(), []provider.ToolCall{
+			{ID: "call-1", Name: "run_command", Arguments: tt.args},
+		})
+		if err != nil || len(results) != 1 {
+			t.Fatalf("executeToolCalls failed: %v %v", results, err)
+		}
+		if !strings.Contains(results[0].Content, `"problems"`) || !strings.Contains(results[0].Content, tt.problem) {
+			t.Errorf("Expected a validation error naming %s, got %s", tt.problem, results[0].Content)
+		}
+	}
+	if _, err := os.Stat(marker); err == nil {
+		t.Error("Expected the command not to run with invalid arguments")
+	}
+}
+
+func TestMember_ParallelDelegationStagger(t *testing.T) {
+	const stagger = 30 * time.Millisecond
+
+	var mu sync.Mutex
+	var starts []time.Time
+	inFlight, maxInFlight := 0, 0
+	mockProv := &MockProvider{ChatFunc: func(req *provider.ChatRequest) (*provider.ChatResponse, error) {
+		if !strings.HasPrefix(req.Messages[len(req.Messages)-1].Content, "Task for") {
+			return &provider.ChatResponse{Content: "All done."}, nil
+		}
+		mu.Lock()
+		starts = append(starts, time.Now())
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		mu.Unlock()
+
+		time.Sleep(3 * stagger)
+
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		return &provider.ChatResponse{Content: "Finished."}, nil
+	}}
+	registry := provider.NewRegistry()
+	registry.Register(mockProv)
+
+	spec := &TeamSpec{
+		Metadata:     Metadata{Name: "stagger-team"},
+		ClientFacing: []string{"pm"},
+		Settings:     TeamSettings{DelegationStagger: stagger, MaxParallelDelegation: 2},
+		Roles: map[string]Role{
+			"pm":       {Title: "PM", Count: 1, Model: ModelConfig{Provider: "mock"}},
+			"frontend": {Title: "Frontend", Count: 1, Model: ModelConfig{Provider: "mock"}},
+			"backend":  {Title: "Backend", Count: 1, Model: ModelConfig{Provider: "mock"}},
+			"infra":    {Title: "Infra", Count: 1, Model: ModelConfig{Provider: "mock"}},
+		},
+	}
+	tm, err := NewTeam(spec, registry, logger.New("error"))
+	if err != nil {
+		t.Fatalf("NewTeam failed: %v", err)
+	}
+	if err := tm.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	t.Cleanup(tm.Stop)
+
+	tm.GetMemberByRole("pm").handleParallelDelegation(context.Background(), responseAction{
+		Type: "parallel_delegate",
+		ParallelTasks: []parallelTask{
+			{Role: "frontend", Content: "Task for frontend"},
+			{Role: "backend", Content: "Task for backend"},
+			{Role: "infra", Content: "Task for infra"},
+		},
+	}, Message{})
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(starts) != 3 {
+		t.Fatalf("Expected 3 delegated tasks to run, got %d", len(starts))
+	}
+	if maxInFlight != 2 {
+		t.Errorf("Expected at most 2 tasks in flight at once, got %d", maxInFlight)
+	}
+	// Allow for scheduling jitter between dispatch and the member's call
+	if gap := starts[1].Sub(starts[0]); gap < stagger*2/3 {
+		t.Errorf("Expected dispatches staggered by %s, second started after %s", stagger, gap)
+	}
+	// The third waits for a slot, not just the stagger
+	if gap := starts[2].Sub(starts[0]); gap < 3*stagger {
+		t.Errorf("Expected the third task to wait for a slot, started after %s", gap)
+	}
+}
+
+func TestMember_KeepToolResults(t *testing.T) {
+	notes := filepath.Join(t.TempDir(), "notes.txt")
+	if err := os.WriteFile(notes, []byte("The launch date is May 4."), 0644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	for _, keep := range []bool{true, false} {
+		t.Run(fmt.Sprintf("keep=%v", keep), func(t *testing.T) {
+			spec := &TeamSpec{
+				Metadata:     Metadata{Name: "test-team"},
+				ClientFacing: []string{"engineer"},
+				Settings:     TeamSettings{KeepToolResults: keep},
+				Roles: map[string]Role{
+					"engineer": {
+						Title:      "Engineer",
+						Count:      1,
+						Visibility: "client",
+						Model:      ModelConfig{Provider: "mock", Model: "mock-model"},
+						Persona:    "You are an engineer.",
+					},
+				},
+			}
+
+			// The engineer reads the notes unless what they say is already
+			// in its context
+			var mu sync.Mutex
+			reads := 0
+			mockProv := &MockProvider{ChatFunc: func(req *provider.ChatRequest) (*provider.ChatResponse, error) {
+				last := req.Messages[len(req.Messages)-1]
+				if last.Role == "tool" {
+					return &provider.ChatResponse{Content: "Read the notes."}, nil
+				}
+				for _, msg := range req.Messages[1:] {
+					if strings.Contains(msg.Content, "May 4") {
+						return &provider.ChatResponse{Content: "The launch is May 4."}, nil
+					}
+				}
+				mu.Lock()
+				reads++
+				id := fmt.Sprintf("call-%d", reads)
+				mu.Unlock()
+				args, _ := json.Marshal(map[string]string{"path": notes})
+				return &provider.ChatResponse{ToolCalls: []provider.ToolCall{
+					{ID: id, Name: "read_file", Arguments: string(args)},
+				}}, nil
+			}}
+
+			registry := provider.NewRegistry()
+			registry.Register(mockProv)
+			tm, err := NewTeam(spec, registry, logger.New("error"))
+			if err != nil {
+				t.Fatalf("NewTeam failed: %v", err)
+			}
+			if err := tm.Start(context.Background()); err != nil {
+				t.Fatalf("Start failed: %v", err)
+			}
+			defer tm.Stop()
+
+			for _, ask := range []string{"Read the notes", "When is the launch?"} {
+				select {
+				case <-tm.AskMember("engineer", ask):
+				case <-time.After(5 * time.Second):
+					t.Fatal("Timed out waiting for response")
+				}
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			want := 2
+			if keep {
+				want = 1
+			}
+			if reads != want {
+				t.Errorf("Expected %d reads of the notes, got %d", want, reads)
+			}
+		})
+	}
+}
+
+func TestToolNotes_Bounded(t *testing.T) {
+	var messages []provider.Message
+	for i := 0; i < 50; i++ {
+		id := fmt.Sprintf("call-%d", i)
+		messages = append(messages,
+			provider.Message{Role: "assistant", ToolCalls: []provider.ToolCall{{ID: id, Name: "read_file", Arguments: `{"path": "big.txt"}`}}},
+			provider.Message{Role: "tool", ToolCallID: id, Content: strings.Repeat("x", 5000)},
+		)
+	}
+
+	notes := toolNotes(messages)
+	if len(notes) > toolNotesBytes+200 {
+		t.Errorf("Expected notes within about %d bytes, got %d", toolNotesBytes, len(notes))
+	}
+	if !strings.Contains(notes, "earlier calls left out") {
+		t.Errorf("Expected dropped calls to be noted, got %q", notes[:200])
+	}
+	if !strings.Contains(notes, `read_file {"path":"big.txt"}`) {
+		t.Errorf("Expected compacted arguments, got %q", notes[:200])
+	}
+}
+
+func TestMember_ParallelDelegationResults(t *testing.T) {
+	mockProv := &MockProvider{ChatFunc: func(req *provider.ChatRequest) (*provider.ChatResponse, error) {
+		last := req.Messages[len(req.Messages)-1].Content
+		switch {
+		case last == "Build the site":
+			return &provider.ChatResponse{Content: "DELEGATE PARALLEL:\n- frontend: Task for frontend\n- backend: Task for backend"}, nil
+		case last == "Task for frontend":
+			return &provider.ChatResponse{Content: "Pages built."}, nil
+		case last == "Task for backend":
+			return nil, errors.New("database unreachable")
+		}
+		return &provider.ChatResponse{Content: "The pages are built, but the backend failed."}, nil
+	}}
+	registry := provider.NewRegistry()
+	registry.Register(mockProv)
+
+	spec := &TeamSpec{
+		Metadata:     Metadata{Name: "parallel-team"},
+		ClientFacing: []string{"pm"},
+		Roles: map[string]Role{
+			"pm":       {Title: "PM", Count: 1, Visibility: "client", Model: ModelConfig{Provider: "mock"}},
+			"frontend": {Title: "Frontend", Count: 1, Model: ModelConfig{Provider: "mock"}},
+			"backend":  {Title: "Backend", Count: 1, Model: ModelConfig{Provider: "mock"}},
+		},
+	}
+
+	var mu sync.Mutex
+	failures := map[string]map[string]interface{}{} // role -> event data
+	persistence := &PersistenceCallbacks{
+		OnActivity: func(_, _, activityType, _, _ string, data map[string]interface{}) {
+			if activityType == "delegation_failed" {
+				mu.Lock()
+				failures[data["role"].(string)] = data
+				mu.Unlock()
+			}
+		},
+	}
+	tm, err := NewTeamWithPersistence(spec, registry, logger.New("error"), persistence)
+	if err != nil {
+		t.Fatalf("NewTeam failed: %v", err)
+	}
+	if err := tm.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	t.Cleanup(tm.Stop)
+
+	var reply Message
+	responses := tm.Ask("Build the site")
+	for reply.Type != MsgClientResponse {
+		select {
+		case reply = <-responses:
+		case <-time.After(5 * time.Second):
+			t.Fatal("Timed out waiting for response")
+		}
+	}
+
+	want := []DelegationResult{
+		{Role: "frontend", Member: "frontend", Success: true, Content: "Pages built."},
+		{Role: "backend", Member: "backend", Success: false},
+	}
+	if len(reply.Delegation) != len(want) {
+		t.Fatalf("Expected %d delegation results, got %+v", len(want), reply.Delegation)
+	}
+	for i, w := range want {
+		got := reply.Delegation[i]
+		if got.Role != w.Role || got.Member != w.Member || got.Success != w.Success {
+			t.Errorf("Result %d: expected %+v, got %+v", i, w, got)
+		}
+	}
+	if reply.Delegation[0].Content != "Pages built." {
+		t.Errorf("Expected the frontend's result, got %q", reply.Delegation[0].Content)
+	}
+	if !strings.Contains(reply.Delegation[1].Error, "database unreachable") {
+		t.Errorf("Expected the backend's error, got %q", reply.Delegation[1].Error)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(failures) != 1 || failures["backend"] == nil {
+		t.Errorf("Expected one delegation_failed event, for backend, got %v", failures)
+	}
+}
+
+func TestMember_SystemPromptClock(t *testing.T) {
+	spec := &TeamSpec{
+		Metadata:     Metadata{Name: "test-team"},
+		ClientFacing: []string{"engineer"},
+		Settings:     TeamSettings{Timezone: "Asia/Tokyo"},
+		Roles: map[string]Role{
+			"engineer": {
+				Title:      "Engineer",
+				Count:      1,
+				Visibility: "client",
+				Model:      ModelConfig{Provider: "mock", Model: "mock-model"},
+				Persona:    "Your shift started {{weekday}} at {{time}} {{timezone}}.",
+			},
+		},
+	}
+
+	var mu sync.Mutex
+	var system string
+	mockProv := &MockProvider{ChatFunc: func(req *provider.ChatRequest) (*provider.ChatResponse, error) {
+		mu.Lock()
+		system = req.Messages[0].Content
+		mu.Unlock()
+		return &provider.ChatResponse{Content: "Done."}, nil
+	}}
+
+	registry := provider.NewRegistry()
+	registry.Register(mockProv)
+	// Late on Friday in UTC is already Saturday in Tokyo
+	clock := func() time.Time { return time.Date(2026, time.October, 16, 23, 30, 0, 0, time.UTC) }
+	tm, err := NewTeam(spec, registry, logger.New("error"), WithClock(clock))
+	if err != nil {
+		t.Fatalf("NewTeam failed: %v", err)
+	}
+	if err := tm.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer tm.Stop()
+
+	select {
+	case <-tm.AskMember("engineer", "What day is it?"):
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for response")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, want := range []string{
+		"Your shift started Saturday at 08:30 Asia/Tokyo.",
+		"Today is Saturday, 17 October 2026 (Asia/Tokyo).",
+		"- get_current_time:",
+	} {
+		if !strings.Contains(system, want) {
+			t.Errorf("Expected %q in the system prompt, got:\n%s", want, system)
+		}
+	}
+
+	spec.Settings.Timezone = "Mars/Olympus"
+	if _, err := NewTeam(spec, registry, logger.New("error")); err == nil || !strings.Contains(err.Error(), "settings.timezone") {
+		t.Errorf("Expected an unknown timezone error, got %v", err)
+	}
+}
+
+// limitedProvider is a MockProvider whose model has a max_tokens limit
+type limitedProvider struct {
+	MockProvider
+}
+
+func (p *limitedProvider) ListModels(_ context.Context) ([]provider.ModelInfo, error) {
+	return []provider.ModelInfo{{ID: "mock-model", Name: "Mock Model", MaxTokens: 200000, MaxOutputTokens: 8192}}, nil
+}
+
+func TestMember_ClampsMaxTokens(t *testing.T) {
+	maxTokens := 100000
+	spec := &TeamSpec{
+		Metadata:     Metadata{Name: "test-team"},
+		ClientFacing: []string{"engineer"},
+		Roles: map[string]Role{
+			"engineer": {
+				Title:      "Engineer",
+				Count:      1,
+				Visibility: "client",
+				Model:      ModelConfig{Provider: "mock", Model: "mock-model", MaxTokens: &maxTokens},
+				Persona:    "You are an engineer.",
+			},
+		},
+	}
+
+	var mu sync.Mutex
+	var sent int
+	prov := &limitedProvider{MockProvider{ChatFunc: func(req *provider.ChatRequest) (*provider.ChatResponse, error) {
+		mu.Lock()
+		sent = *req.MaxTokens
+		mu.Unlock()
+		return &provider.ChatResponse{Content: "Done."}, nil
+	}}}
+
+	registry := provider.NewRegistry()
+	registry.Register(prov)
+	tm, err := NewTeam(spec, registry, logger.New("error"))
+	if err != nil {
+		t.Fatalf("NewTeam failed: %v", err)
+	}
+	if err := tm.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer tm.Stop()
+
+	select {
+	case <-tm.AskMember("engineer", "Write the report"):
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for response")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if sent != 8192 {
+		t.Errorf("Expected max_tokens clamped to the model's 8192, got %d", sent)
+	}
+	if maxTokens != 100000 {
+		t.Errorf("Expected the spec's max_tokens to be left alone, got %d", maxTokens)
+	}
+}
+
+// unlistedProvider is a MockProvider that fails to list its models
+type unlistedProvider struct {
+	MockProvider
+	mu    sync.Mutex
+	lists int
+}
+
+func (p *unlistedProvider) ListModels(_ context.Context) ([]provider.ModelInfo, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.lists++
+	return nil, errors.New("listing not supported")
+}
+
+func TestTeam_MaxTokensLimitCachesListFailures(t *testing.T) {
+	prov := &unlistedProvider{}
+	tm := &Team{}
+	for i := 0; i < 3; i++ {
+		if limit := tm.maxTokensLimit(context.Background(), prov, "mock-model"); limit != 0 {
+			t.Errorf("Expected no limit for an unlisted model, got %d", limit)
+		}
+	}
+	if prov.lists != 1 {
+		t.Errorf("Expected a failed listing to be asked once, got %d", prov.lists)
+	}
+}
+
+// visionProvider is a MockProvider that takes images in tool results
+type visionProvider struct {
+	MockProvider
+}
+
+func (p *visionProvider) ImageToolResults() bool { return true }
+
+// screenshotTool returns a page's title and a screenshot of it
+type screenshotTool struct{}
+
+func (t *screenshotTool) Name() string        { return "take_screenshot" }
+func (t *screenshotTool) Description() string { return "Screenshot the app" }
+func (t *screenshotTool) Execute(_ context.Context, _ map[string]interface{}) (interface{}, error) {
+	return &tools.Content{
+		Text: "Login page",
+		Attachments: []tools.Attachment{
+			tools.NewAttachment("image/png", []byte("\x89PNG fake")),
+			tools.NewAttachment("application/pdf", []byte("%PDF fake")),
+		},
+	}, nil
+}
+
+func TestMember_ImageToolResults(t *testing.T) {
+	spec := &TeamSpec{
+		Metadata:     Metadata{Name: "test-team"},
+		ClientFacing: []string{"engineer"},
+		Roles: map[string]Role{
+			"engineer": {
+				Title:      "Engineer",
+				Count:      1,
+				Visibility: "client",
+				Model:      ModelConfig{Provider: "mock", Model: "mock-model"},
+				Persona:    "You are an engineer.",
+			},
+		},
+	}
+
+	for _, tc := range []struct {
+		name     string
+		vision   bool
+		images   int
+		contains []string
+	}{
+		{"vision provider", true, 1, []string{"Login page", "[application/pdf, 9 bytes, not shown]"}},
+		{"text provider", false, 0, []string{"Login page", "[image/png, 9 bytes, not shown]", "[application/pdf, 9 bytes, not shown]"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var result provider.Message
+			mock := MockProvider{ChatFunc: func(req *provider.ChatRequest) (*provider.ChatResponse, error) {
+				last := req.Messages[len(req.Messages)-1]
+				if last.ToolCallID == "call-1" {
+					mu.Lock()
+					result = last
+					mu.Unlock()
+					return &provider.ChatResponse{Content: "The page looks right."}, nil
+				}
+				return &provider.ChatResponse{ToolCalls: []provider.ToolCall{{ID: "call-1", Name: "take_screenshot", Arguments: "{}"}}}, nil
+			}}
+
+			registry := provider.NewRegistry()
+			if tc.vision {
+				registry.Register(&visionProvider{mock})
+			} else {
+				registry.Register(&mock)
+			}
+			tm, err := NewTeam(spec, registry, logger.New("error"))
+			if err != nil {
+				t.Fatalf("NewTeam failed: %v", err)
+			}
+			tm.toolRegistry.Register(&screenshotTool{})
+			if err := tm.Start(context.Background()); err != nil {
+				t.Fatalf("Start failed: %v", err)
+			}
+			defer tm.Stop()
+
+			select {
+			case <-tm.AskMember("engineer", "Check the login page"):
+			case <-time.After(5 * time.Second):
+				t.Fatal("Timed out waiting for response")
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if len(result.Images) != tc.images {
+				t.Fatalf("Expected %d images sent, got %+v", tc.images, result.Images)
+			}
+			if tc.images > 0 && (result.Images[0].MediaType != "image/png" || result.Images[0].Data != base64.StdEncoding.EncodeToString([]byte("\x89PNG fake"))) {
+				t.Errorf("Unexpected image: %+v", result.Images[0])
+			}
+			for _, want := range tc.contains {
+				if !strings.Contains(result.Content, want) {
+					t.Errorf("Expected %q in the tool result, got %q", want, result.Content)
+				}
+			}
+		})
+	}
+}
+
+func TestAuditArgs(t *testing.T) {
+	content := strings.Repeat("é", 1000)
+	args := map[string]interface{}{"path": "docs/README.md", "content": content, "append": true}
+
+	got := auditArgs(args)
+	if got["path"] != "docs/README.md" || got["append"] != true {
+		t.Errorf("Expected short arguments kept as they are, got %v", got)
+	}
+	kept, _ := got["content"].(string)
+	if len(kept) > auditArgMax+32 || !utf8.ValidString(kept) || !strings.HasSuffix(kept, "[2000 bytes]") {
+		t.Errorf("Expected the file content cut down with its size, got %q", kept)
+	}
+	if args["content"] != content {
+		t.Error("Expected the call's own arguments left untouched")
+	}
+}
@@ -19,7 +19,11 @@ type Orchestrator struct {
 	projectManager *ProjectManager
 	logger         *logger.Logger
 
-	// Active project being worked on
+	// Phases projects run through, from the spec's workflow
+	phases []WorkflowPhase
+
+	// Active project being worked on. Guarded by mu; the project's own
+	// fields are guarded by its mu.
 	activeProject *Project
 
 	// Channels for coordination
@@ -29,6 +33,10 @@ type Orchestrator struct {
 	mu sync.RWMutex
 }
 
+// DefaultMaxParallelStories is how many stories the execution phase works on
+// at once when the spec doesn't say
+const DefaultMaxParallelStories = 3
+
 // QuestionAnswer pairs a question with its answer
 type QuestionAnswer struct {
 	QuestionID string
@@ -41,6 +49,7 @@ func NewOrchestrator(team *Team, log *logger.Logger) *Orchestrator {
 		team:            team,
 		projectManager:  NewProjectManager(team),
 		logger:          log,
+		phases:          team.Spec.Workflow.projectPhases(),
 		clientQuestions: make(chan Question, 10),
 		clientAnswers:   make(chan QuestionAnswer, 10),
 	}
@@ -67,31 +76,31 @@ func (o *Orchestrator) StartProject(ctx context.Context, clientRequest string) (
 	return project, nil
 }
 
-// runPlanningPhase coordinates PM and BA to create requirements
+// runPlanningPhase has the planning phase's roles create requirements: its
+// first role (the PM by default) analyzes the request, and its second (the
+// BA), if the team has one, writes the requirements
 func (o *Orchestrator) runPlanningPhase(ctx context.Context, project *Project) {
 	project.SetPhase(PhasePlanning)
-	o.logger.Info("starting planning phase", "project", project.ID)
-
-	// Get PM and BA
-	pm := o.team.GetMemberByRole("pm")
-	ba := o.team.GetMemberByRole("ba")
+	phase, _ := phaseOf(o.phases, PhasePlanning)
+	o.logger.Info("starting planning phase", "project", project.ID, "phase", phase.Name)
 
-	if pm == nil {
-		o.logger.Error("no PM found in team")
+	planners := o.phaseMembers(phase)
+	if len(planners) == 0 {
+		o.logger.Error("no member for the planning phase", "roles", phase.Roles)
 		return
 	}
+	lead := planners[0]
 
-	// Step 1: PM analyzes the request and identifies what's needed
-	pmAnalysis := o.getPMAnalysis(ctx, pm, project)
-	project.AddCommunication("message", pm.ID, "all", pmAnalysis, nil)
+	// Step 1: The lead analyzes the request and identifies what's needed
+	analysis := o.getPMAnalysis(ctx, lead, project)
+	project.AddCommunication("message", lead.ID, "all", analysis, nil)
 
-	// Step 2: If BA exists, have them create detailed requirements
+	// Step 2: The next planner, or else the lead, writes the requirements
 	var requirements []Requirement
-	if ba != nil {
-		requirements = o.getBARequirements(ctx, ba, project, pmAnalysis)
+	if len(planners) > 1 {
+		requirements = o.getBARequirements(ctx, planners[1], project, analysis)
 	} else {
-		// PM creates requirements if no BA
-		requirements = o.getPMRequirements(ctx, pm, project, pmAnalysis)
+		requirements = o.getPMRequirements(ctx, lead, project, analysis)
 	}
 
 	// Add requirements to project
@@ -99,10 +108,11 @@ func (o *Orchestrator) runPlanningPhase(ctx context.Context, project *Project) {
 		project.AddRequirement(req.Title, req.Description, req.Priority, req.CreatedBy)
 	}
 
-	// Step 3: Check if we need more info from client
-	if len(project.PendingQuestions) > 0 {
-		project.SetPhase(PhaseBlocked)
-		o.logger.Info("project blocked - waiting for client answers", "questions", len(project.PendingQuestions))
+	// Step 3: Check if we need more info from client. Questions the client
+	// has already answered don't hold the project up.
+	if pending := project.BlockOnQuestions(); pending > 0 {
+		o.logger.Info("project blocked - waiting for client answers", "questions", pending)
+		o.watchBlocked(ctx, project)
 		return
 	}
 
@@ -110,21 +120,26 @@ func (o *Orchestrator) runPlanningPhase(ctx context.Context, project *Project) {
 	o.runTaskBreakdownPhase(ctx, project)
 }
 
-// runTaskBreakdownPhase creates stories from requirements
+// runTaskBreakdownPhase creates stories from requirements. A workflow
+// without a task breakdown phase works each requirement as a story.
 func (o *Orchestrator) runTaskBreakdownPhase(ctx context.Context, project *Project) {
 	project.SetPhase(PhaseTaskBreakdown)
 	o.logger.Info("starting task breakdown phase", "project", project.ID)
 
-	pm := o.team.GetMemberByRole("pm")
-	if pm == nil {
-		return
+	var stories []*Story
+	if _, ok := phaseOf(o.phases, PhaseTaskBreakdown); ok {
+		lead := o.phaseLead(PhaseTaskBreakdown)
+		if lead == nil {
+			return
+		}
+		stories = o.createStoriesFromRequirements(ctx, lead, project)
+	} else {
+		stories = storiesFromRequirements(project)
 	}
 
-	// Have PM/BA break requirements into stories
-	stories := o.createStoriesFromRequirements(ctx, pm, project)
-
+	created := make(map[string]*Story, len(stories))
 	for _, story := range stories {
-		project.CreateStory(
+		created[story.ID] = project.CreateStory(
 			story.RequirementID,
 			story.Title,
 			story.Description,
@@ -134,67 +149,99 @@ func (o *Orchestrator) runTaskBreakdownPhase(ctx context.Context, project *Proje
 		)
 	}
 
+	// Point dependencies at the project's stories, which have their own IDs
+	for _, story := range stories {
+		s := created[story.ID]
+		s.mu.Lock()
+		for _, dep := range story.DependsOn {
+			if c, ok := created[dep]; ok {
+				s.DependsOn = append(s.DependsOn, c.ID)
+			}
+		}
+		s.mu.Unlock()
+	}
+
 	o.logger.Info("stories created", "count", len(stories))
 
 	// Move to execution
 	o.runExecutionPhase(ctx, project)
 }
 
-// runExecutionPhase assigns stories to engineers and coordinates work
+// runExecutionPhase assigns stories to the execution phase's members and
+// coordinates work
 func (o *Orchestrator) runExecutionPhase(ctx context.Context, project *Project) {
 	project.SetPhase(PhaseExecution)
-	o.logger.Info("starting execution phase", "project", project.ID)
+	phase, _ := phaseOf(o.phases, PhaseExecution)
+	o.logger.Info("starting execution phase", "project", project.ID, "phase", phase.Name)
 
-	// Get available engineers
-	backendEngineers := o.team.MembersByRole["backend"]
-	frontendEngineers := o.team.MembersByRole["frontend"]
-	engineers := o.team.MembersByRole["engineer"]
-
-	// Combine all engineers
+	// Get available members, by role and altogether
+	byRole := make(map[string][]*Member, len(phase.Roles))
 	allEngineers := make([]*Member, 0)
-	allEngineers = append(allEngineers, backendEngineers...)
-	allEngineers = append(allEngineers, frontendEngineers...)
-	allEngineers = append(allEngineers, engineers...)
+	for _, role := range phase.Roles {
+		byRole[role] = o.team.MembersWithRole(role)
+		allEngineers = append(allEngineers, byRole[role]...)
+	}
 
 	if len(allEngineers) == 0 {
-		o.logger.Warn("no engineers available")
+		o.logger.Warn("no member for the execution phase", "roles", phase.Roles)
 		return
 	}
 
-	// Distribute stories to engineers
+	limit := o.team.Spec.Settings.MaxParallelStories
+	if limit <= 0 {
+		limit = DefaultMaxParallelStories
+	}
+	slots := make(chan struct{}, limit)
+
+	// Closed as each story finishes, for the stories that depend on it
+	stories := project.ListStories()
+	done := make(map[string]chan struct{}, len(stories))
+	for _, story := range stories {
+		done[story.ID] = make(chan struct{})
+	}
+
+	// Distribute stories, to a member of the story's role if there is one.
+	// Each starts once the stories it depends on have finished, up to limit
+	// at once.
 	var wg sync.WaitGroup
-	for i, story := range project.Stories {
-		// Assign to appropriate engineer based on role
-		var engineer *Member
-		switch story.AssignedRole {
-		case "backend":
-			if len(backendEngineers) > 0 {
-				engineer = backendEngineers[i%len(backendEngineers)]
-			}
-		case "frontend":
-			if len(frontendEngineers) > 0 {
-				engineer = frontendEngineers[i%len(frontendEngineers)]
-			}
-		default:
-			if len(allEngineers) > 0 {
-				engineer = allEngineers[i%len(allEngineers)]
-			}
+	for i, story := range stories {
+		candidates := byRole[story.AssignedRole]
+		if len(candidates) == 0 {
+			candidates = allEngineers
 		}
+		engineer := candidates[i%len(candidates)]
 
-		if engineer != nil {
-			story.AssignedMember = engineer.ID
-			story.UpdateStatus(StoryReady)
+		story.Assign(engineer.ID)
 
-			wg.Add(1)
-			go func(s *Story, e *Member) {
-				defer wg.Done()
-				o.executeStory(ctx, project, s, e)
-			}(story, engineer)
-		}
+		wg.Add(1)
+		go func(s *Story, e *Member) {
+			defer wg.Done()
+			defer close(done[s.ID])
+
+			for _, dep := range s.DependsOn {
+				if ch, ok := done[dep]; ok {
+					select {
+					case <-ctx.Done():
+						return
+					case <-ch:
+					}
+				}
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case slots <- struct{}{}:
+			}
+			defer func() { <-slots }()
+			o.executeStory(ctx, project, s, e)
+		}(story, engineer)
 	}
 
 	// Wait for all stories to complete
 	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		o.logger.Warn("execution phase canceled", "project", project.ID, "error", err)
+	}
 
 	// Move to review
 	o.runReviewPhase(ctx, project)
@@ -220,12 +267,12 @@ func (o *Orchestrator) executeStory(ctx context.Context, project *Project, story
 	// Execute with tool loop
 	var finalContent string
 	for iteration := 0; iteration < MaxToolIterations; iteration++ {
-		resp, err := engineer.Provider.Chat(ctx, &provider.ChatRequest{
-			Model:       engineer.Role.Model.Model,
+		resp, err := engineer.chat(ctx, &provider.ChatRequest{
+			Model:       engineer.getEffectiveModel(),
 			Messages:    messages,
 			Tools:       providerTools,
 			Temperature: engineer.Role.Model.Temperature,
-			MaxTokens:   engineer.Role.Model.MaxTokens,
+			MaxTokens:   engineer.getEffectiveMaxTokens(),
 		})
 
 		if err != nil {
@@ -235,14 +282,19 @@ func (o *Orchestrator) executeStory(ctx context.Context, project *Project, story
 		}
 
 		// Handle tool calls
-		if len(resp.ToolCalls) > 0 && engineer.toolRegistry != nil {
+		if len(resp.ToolCalls) > 0 && engineer.tools() != nil {
 			messages = append(messages, provider.Message{
 				Role:      "assistant",
 				Content:   resp.Content,
 				ToolCalls: resp.ToolCalls,
 			})
 
-			toolResults := engineer.executeToolCalls(ctx, resp.ToolCalls)
+			toolResults, err := engineer.executeToolCalls(ctx, resp.ToolCalls)
+			if err != nil {
+				o.logger.Error("engineer stopped waiting for client", "error", err)
+				story.UpdateStatus(StoryBlocked)
+				return
+			}
 			messages = append(messages, toolResults...)
 
 			// Log artifacts from tool calls
@@ -271,7 +323,7 @@ func (o *Orchestrator) executeStory(ctx context.Context, project *Project, story
 	}
 
 	// Log the work summary
-	project.AddCommunication("message", engineer.ID, "pm",
+	project.AddCommunication("message", engineer.ID, o.leadRole(),
 		fmt.Sprintf("Completed story: %s\n\n%s", story.Title, finalContent),
 		map[string]interface{}{"story_id": story.ID})
 
@@ -279,21 +331,22 @@ func (o *Orchestrator) executeStory(ctx context.Context, project *Project, story
 	o.logger.Info("engineer completed story", "engineer", engineer.ID, "story", story.ID)
 }
 
-// runReviewPhase has QA review the completed work
+// runReviewPhase has the review phase's member (QA by default) review the
+// completed work
 func (o *Orchestrator) runReviewPhase(ctx context.Context, project *Project) {
 	project.SetPhase(PhaseReview)
 	o.logger.Info("starting review phase", "project", project.ID)
 
-	qa := o.team.GetMemberByRole("qa")
+	qa := o.phaseLead(PhaseReview)
 	if qa == nil {
-		// No QA, skip to complete
+		// No reviewer, skip to complete
 		project.SetPhase(PhaseComplete)
 		return
 	}
 
 	// Have QA review each story
-	for _, story := range project.Stories {
-		if story.Status == StoryReview {
+	for _, story := range project.ListStories() {
+		if story.GetStatus() == StoryReview {
 			o.reviewStory(ctx, project, story, qa)
 		}
 	}
@@ -322,8 +375,10 @@ Please verify the work meets the acceptance criteria.`,
 		formatArtifacts(story.Artifacts),
 	)
 
-	resp, err := qa.Provider.Chat(ctx, &provider.ChatRequest{
-		Model:    qa.Role.Model.Model,
+	resp, err := qa.chat(ctx, &provider.ChatRequest{
+		Model:       qa.getEffectiveModel(),
+		Temperature: qa.Role.Model.Temperature,
+		MaxTokens:   qa.getEffectiveMaxTokens(),
 		Messages: []provider.Message{
 			{Role: "system", Content: qa.buildSystemPrompt()},
 			{Role: "user", Content: prompt},
@@ -335,7 +390,7 @@ Please verify the work meets the acceptance criteria.`,
 		return
 	}
 
-	project.AddCommunication("message", qa.ID, "pm",
+	project.AddCommunication("message", qa.ID, o.leadRole(),
 		fmt.Sprintf("Review of '%s': %s", story.Title, resp.Content),
 		map[string]interface{}{"story_id": story.ID})
 
@@ -348,7 +403,7 @@ func (o *Orchestrator) getPMAnalysis(ctx context.Context, pm *Member, project *P
 
 "%s"
 
-As the Project Manager, please:
+As the %s leading this project, please:
 1. Summarize what the client wants
 2. Identify the main components/features needed
 3. List any clarifying questions we need to ask the client
@@ -368,10 +423,13 @@ Format your response as:
 ## Team Roles Needed
 - [Role 1]: [What they'll do]`,
 		project.Description,
+		roleLabel(pm),
 	)
 
-	resp, err := pm.Provider.Chat(ctx, &provider.ChatRequest{
-		Model:    pm.Role.Model.Model,
+	resp, err := pm.chat(ctx, &provider.ChatRequest{
+		Model:       pm.getEffectiveModel(),
+		Temperature: pm.Role.Model.Temperature,
+		MaxTokens:   pm.getEffectiveMaxTokens(),
 		Messages: []provider.Message{
 			{Role: "system", Content: pm.buildSystemPrompt()},
 			{Role: "user", Content: prompt},
@@ -400,7 +458,7 @@ func (o *Orchestrator) getBARequirements(ctx context.Context, ba *Member, projec
 Original client request:
 "%s"
 
-As the Business Analyst, create detailed requirements. For each requirement:
+As the %s, create detailed requirements. For each requirement:
 1. Give it a clear title
 2. Write a detailed description
 3. Assign priority (must, should, could)
@@ -415,22 +473,20 @@ Format as JSON array:
 ]`,
 		pmAnalysis,
 		project.Description,
+		roleLabel(ba),
 	)
 
-	resp, err := ba.Provider.Chat(ctx, &provider.ChatRequest{
-		Model:    ba.Role.Model.Model,
-		Messages: []provider.Message{
-			{Role: "system", Content: ba.buildSystemPrompt()},
-			{Role: "user", Content: prompt},
-		},
+	var reqs []Requirement
+	err := o.chatJSON(ctx, ba, project, "requirements", prompt, func(reply string) (err error) {
+		reqs, err = parseRequirements(reply, ba.ID)
+		return err
 	})
-
 	if err != nil {
 		o.logger.Error("BA requirements failed", "error", err)
 		return nil
 	}
 
-	return parseRequirements(resp.Content, ba.ID)
+	return reqs
 }
 
 // getPMRequirements has PM create requirements when no BA is available
@@ -442,11 +498,23 @@ func (o *Orchestrator) getPMRequirements(ctx context.Context, pm *Member, projec
 // createStoriesFromRequirements breaks requirements into stories
 func (o *Orchestrator) createStoriesFromRequirements(ctx context.Context, pm *Member, project *Project) []*Story {
 	reqSummary := ""
+	project.mu.RLock()
 	for _, req := range project.Requirements {
 		reqSummary += fmt.Sprintf("- %s: %s (Priority: %s)\n", req.Title, req.Description, req.Priority)
 	}
+	for _, q := range project.PendingQuestions {
+		if q.Answer != "" {
+			reqSummary += fmt.Sprintf("\nClient question: %s\nAnswer: %s\n", q.Content, q.Answer)
+		}
+	}
+	project.mu.RUnlock()
 
-	prompt := fmt.Sprintf(`Break down these requirements into user stories for the engineering team:
+	roles := []string{"engineer"}
+	if phase, ok := phaseOf(o.phases, PhaseExecution); ok {
+		roles = phase.Roles
+	}
+
+	prompt := fmt.Sprintf(`Break down these requirements into user stories for the team:
 
 %s
 
@@ -454,9 +522,10 @@ For each story, specify:
 1. Title
 2. Description
 3. Type (feature, task, spike)
-4. Assigned role (backend, frontend, or engineer for full-stack)
+4. Assigned role (one of: %s)
 5. Acceptance criteria (list)
 6. Estimated effort (small, medium, large)
+7. Depends on (titles of earlier stories that must be finished first, if any)
 
 Format as JSON array:
 [
@@ -464,29 +533,29 @@ Format as JSON array:
     "title": "Story title",
     "description": "What needs to be done",
     "type": "feature",
-    "assigned_role": "backend",
+    "assigned_role": "%s",
     "acceptance_criteria": ["Criterion 1", "Criterion 2"],
     "estimated_effort": "medium",
-    "requirement_id": "requirement_id_if_known"
+    "requirement_id": "requirement_id_if_known",
+    "depends_on": ["Title of an earlier story"]
   }
 ]`,
 		reqSummary,
+		strings.Join(roles, ", "),
+		roles[0],
 	)
 
-	resp, err := pm.Provider.Chat(ctx, &provider.ChatRequest{
-		Model:    pm.Role.Model.Model,
-		Messages: []provider.Message{
-			{Role: "system", Content: pm.buildSystemPrompt()},
-			{Role: "user", Content: prompt},
-		},
+	var stories []*Story
+	err := o.chatJSON(ctx, pm, project, "stories", prompt, func(reply string) (err error) {
+		stories, err = parseStories(reply)
+		return err
 	})
-
 	if err != nil {
 		o.logger.Error("story creation failed", "error", err)
 		return nil
 	}
 
-	return parseStories(resp.Content)
+	return stories
 }
 
 // buildStoryPrompt creates the prompt for an engineer to work on a story
@@ -526,9 +595,10 @@ If you have questions or need clarification, ask them clearly.`,
 
 // handleEngineerQuestions processes questions from engineers
 func (o *Orchestrator) handleEngineerQuestions(project *Project, story *Story, engineer *Member, response string) {
-	// For now, route to PM
-	q := story.AskQuestion(response, engineer.RoleName, engineer.ID, "pm")
-	project.AddCommunication("question", engineer.ID, "pm", response,
+	// For now, route to the lead
+	lead := o.leadRole()
+	q := story.AskQuestion(response, engineer.RoleName, engineer.ID, lead)
+	project.AddCommunication("question", engineer.ID, lead, response,
 		map[string]interface{}{"question_id": q.ID, "story_id": story.ID})
 }
 
@@ -548,7 +618,7 @@ func (o *Orchestrator) parseAndAddQuestions(project *Project, pm *Member, conten
 			question := strings.TrimPrefix(line, "- ")
 			question = strings.TrimPrefix(question, "* ")
 			if question != "" {
-				project.AskQuestion(question, "pm", pm.ID, "client", project.ID)
+				project.AskQuestion(question, pm.RoleName, pm.ID, "client", project.ID)
 			}
 		}
 		if inQuestions && strings.HasPrefix(line, "##") {
@@ -557,29 +627,30 @@ func (o *Orchestrator) parseAndAddQuestions(project *Project, pm *Member, conten
 	}
 }
 
+// project returns the active project, or nil before one is started
+func (o *Orchestrator) project() *Project {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.activeProject
+}
+
 // ProvideAnswer allows client to answer a pending question
 func (o *Orchestrator) ProvideAnswer(questionID, answer string) error {
-	if o.activeProject == nil {
+	project := o.project()
+	if project == nil {
 		return fmt.Errorf("no active project")
 	}
 
-	err := o.activeProject.AnswerQuestion(questionID, answer, "client")
+	err := project.AnswerQuestion(questionID, answer, "client")
 	if err != nil {
 		return err
 	}
 
-	// Check if all questions are answered
-	allAnswered := true
-	for _, q := range o.activeProject.PendingQuestions {
-		if q.Status == "pending" {
-			allAnswered = false
-			break
-		}
-	}
-
-	// If all answered and we were blocked, resume
-	if allAnswered && o.activeProject.Phase == PhaseBlocked {
-		go o.runTaskBreakdownPhase(context.Background(), o.activeProject)
+	// If all answered and we were blocked, resume. The block timeout may
+	// have resumed it already, and if planning is still running it won't
+	// block at all.
+	if len(project.Unanswered()) == 0 && project.SetPhaseFrom(PhaseBlocked, PhaseTaskBreakdown) {
+		go o.runTaskBreakdownPhase(context.Background(), project)
 	}
 
 	return nil
@@ -587,37 +658,45 @@ func (o *Orchestrator) ProvideAnswer(questionID, answer string) error {
 
 // GetPendingQuestions returns questions waiting for client answers
 func (o *Orchestrator) GetPendingQuestions() []Question {
-	if o.activeProject == nil {
+	project := o.project()
+	if project == nil {
 		return nil
 	}
 
-	pending := make([]Question, 0)
-	for _, q := range o.activeProject.PendingQuestions {
-		if q.Status == "pending" {
-			pending = append(pending, q)
-		}
+	pending := project.Unanswered()
+	if pending == nil {
+		pending = make([]Question, 0)
 	}
 	return pending
 }
 
 // GetProjectStatus returns the current project status
 func (o *Orchestrator) GetProjectStatus() map[string]interface{} {
-	if o.activeProject == nil {
+	p := o.project()
+	if p == nil {
 		return nil
 	}
 
-	p := o.activeProject
 	p.mu.RLock()
 	defer p.mu.RUnlock()
 
 	storySummary := make([]map[string]interface{}, 0)
 	for _, s := range p.Stories {
+		s.mu.RLock()
 		storySummary = append(storySummary, map[string]interface{}{
 			"id":       s.ID,
 			"title":    s.Title,
 			"status":   s.Status,
 			"assigned": s.AssignedMember,
 		})
+		s.mu.RUnlock()
+	}
+
+	pending := 0
+	for _, q := range p.PendingQuestions {
+		if q.Status == "pending" {
+			pending++
+		}
 	}
 
 	return map[string]interface{}{
@@ -625,79 +704,102 @@ func (o *Orchestrator) GetProjectStatus() map[string]interface{} {
 		"phase":             p.Phase,
 		"requirements":      len(p.Requirements),
 		"stories":           storySummary,
-		"pending_questions": len(o.GetPendingQuestions()),
+		"pending_questions": pending,
 		"communications":    len(p.Communications),
 	}
 }
 
 // Helper functions
 
-func parseRequirements(content, createdBy string) []Requirement {
-	var reqs []Requirement
-
-	// Find JSON in the content
-	start := strings.Index(content, "[")
-	end := strings.LastIndex(content, "]")
+// parseRequirements reads the requirements from a planner's reply
+func parseRequirements(content, createdBy string) ([]Requirement, error) {
+	var parsed []struct {
+		Title       string `json:"title"`
+		Description string `json:"description"`
+		Priority    string `json:"priority"`
+	}
+	if err := decodeJSONArray(content, &parsed); err != nil {
+		return nil, err
+	}
+
+	reqs := make([]Requirement, 0, len(parsed))
+	for _, r := range parsed {
+		reqs = append(reqs, Requirement{
+			ID:          uuid.New().String(),
+			Title:       r.Title,
+			Description: r.Description,
+			Priority:    r.Priority,
+			CreatedBy:   createdBy,
+		})
+	}
+	return reqs, nil
+}
 
-	if start >= 0 && end > start {
-		jsonStr := content[start : end+1]
-		var parsed []struct {
-			Title       string `json:"title"`
-			Description string `json:"description"`
-			Priority    string `json:"priority"`
+// parseStories reads the stories from a task breakdown reply
+func parseStories(content string) ([]*Story, error) {
+	var parsed []struct {
+		Title              string   `json:"title"`
+		Description        string   `json:"description"`
+		Type               string   `json:"type"`
+		AssignedRole       string   `json:"assigned_role"`
+		AcceptanceCriteria []string `json:"acceptance_criteria"`
+		EstimatedEffort    string   `json:"estimated_effort"`
+		RequirementID      string   `json:"requirement_id"`
+		DependsOn          []string `json:"depends_on"`
+	}
+	if err := decodeJSONArray(content, &parsed); err != nil {
+		return nil, err
+	}
+
+	// Dependencies name earlier stories by title. Others are dropped, so
+	// stories can't wait on each other in a cycle.
+	ids := make(map[string]string, len(parsed))
+	stories := make([]*Story, 0, len(parsed))
+	for _, s := range parsed {
+		var dependsOn []string
+		for _, title := range s.DependsOn {
+			if id, ok := ids[title]; ok {
+				dependsOn = append(dependsOn, id)
+			}
 		}
 
-		if err := json.Unmarshal([]byte(jsonStr), &parsed); err == nil {
-			for _, r := range parsed {
-				reqs = append(reqs, Requirement{
-					ID:          uuid.New().String(),
-					Title:       r.Title,
-					Description: r.Description,
-					Priority:    r.Priority,
-					CreatedBy:   createdBy,
-				})
-			}
+		story := &Story{
+			ID:                 uuid.New().String(),
+			Title:              s.Title,
+			Description:        s.Description,
+			Type:               s.Type,
+			AssignedRole:       s.AssignedRole,
+			AcceptanceCriteria: s.AcceptanceCriteria,
+			EstimatedEffort:    s.EstimatedEffort,
+			RequirementID:      s.RequirementID,
+			DependsOn:          dependsOn,
+			Status:             StoryBacklog,
+		}
+		if _, ok := ids[s.Title]; !ok {
+			ids[s.Title] = story.ID
 		}
+		stories = append(stories, story)
 	}
-
-	return reqs
+	return stories, nil
 }
 
-func parseStories(content string) []*Story {
-	var stories []*Story
-
-	start := strings.Index(content, "[")
-	end := strings.LastIndex(content, "]")
-
-	if start >= 0 && end > start {
-		jsonStr := content[start : end+1]
-		var parsed []struct {
-			Title              string   `json:"title"`
-			Description        string   `json:"description"`
-			Type               string   `json:"type"`
-			AssignedRole       string   `json:"assigned_role"`
-			AcceptanceCriteria []string `json:"acceptance_criteria"`
-			EstimatedEffort    string   `json:"estimated_effort"`
-			RequirementID      string   `json:"requirement_id"`
-		}
+// storiesFromRequirements makes a story of each of the project's requirements,
+// for workflows without a task breakdown phase
+func storiesFromRequirements(project *Project) []*Story {
+	project.mu.RLock()
+	defer project.mu.RUnlock()
 
-		if err := json.Unmarshal([]byte(jsonStr), &parsed); err == nil {
-			for _, s := range parsed {
-				stories = append(stories, &Story{
-					ID:                 uuid.New().String(),
-					Title:              s.Title,
-					Description:        s.Description,
-					Type:               s.Type,
-					AssignedRole:       s.AssignedRole,
-					AcceptanceCriteria: s.AcceptanceCriteria,
-					EstimatedEffort:    s.EstimatedEffort,
-					RequirementID:      s.RequirementID,
-					Status:             StoryBacklog,
-				})
-			}
-		}
+	stories := make([]*Story, 0, len(project.Requirements))
+	for _, req := range project.Requirements {
+		stories = append(stories, &Story{
+			ID:            uuid.New().String(),
+			Title:         req.Title,
+			Description:   req.Description,
+			Type:          "task",
+			RequirementID: req.ID,
+			Status:        StoryBacklog,
+		})
 	}
-
 	return stories
 }
 
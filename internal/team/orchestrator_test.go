@@ -0,0 +1,194 @@
+package team
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/arcslash/ugudu/internal/logger"
+	"github.com/arcslash/ugudu/internal/provider"
+)
+
+// Run with -race: the client answers and polls the project while its
+// planning phase is still writing to it
+func TestOrchestrator_AnswerDuringPlanning(t *testing.T) {
+	release := make(chan struct{})
+	registry := provider.NewRegistry()
+	registry.Register(&MockProvider{ChatFunc: func(req *provider.ChatRequest) (*provider.ChatResponse, error) {
+		prompt := req.Messages[len(req.Messages)-1].Content
+		switch {
+		case strings.Contains(prompt, "leading this project"):
+			return &provider.ChatResponse{Content: "## Summary\nA login page\n\n## Questions for Client (if any)\n- Which identity provider?\n\n## Team Roles Needed\n- engineer"}, nil
+		case strings.Contains(prompt, "create detailed requirements"):
+			// Planning carries on while the client answers
+			<-release
+			return &provider.ChatResponse{Content: `[{"title": "Login", "description": "Sign in with Google", "priority": "must"}]`}, nil
+		}
+		return &provider.ChatResponse{Content: "Done."}, nil
+	}})
+
+	tm, err := NewTeam(limitsSpec(1), registry, logger.New("error"))
+	if err != nil {
+		t.Fatalf("NewTeam failed: %v", err)
+	}
+	if err := tm.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	t.Cleanup(tm.Stop)
+
+	if _, err := tm.StartProject("Build a login page"); err != nil {
+		t.Fatalf("StartProject failed: %v", err)
+	}
+	o := tm.Orchestrator()
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				o.GetProjectStatus()
+				tm.Busy()
+			}
+		}
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	var pending []Question
+	for pending = o.GetPendingQuestions(); len(pending) == 0; pending = o.GetPendingQuestions() {
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for the PM's question")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if err := o.ProvideAnswer(pending[0].ID, "Google"); err != nil {
+		t.Fatalf("ProvideAnswer failed: %v", err)
+	}
+	close(release)
+
+	// Answered before planning finished, so the project doesn't block
+	project := o.project()
+	for project.GetPhase() != PhaseComplete {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the project to complete, still %s", project.GetPhase())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	close(stop)
+	wg.Wait()
+
+	status := o.GetProjectStatus()
+	if status["requirements"] != 1 || status["pending_questions"] != 0 {
+		t.Errorf("Expected 1 requirement and no pending questions, got %v", status)
+	}
+}
+
+func TestOrchestrator_MaxParallelStories(t *testing.T) {
+	var mu sync.Mutex
+	running, most := 0, 0
+	registry := provider.NewRegistry()
+	registry.Register(&MockProvider{ChatFunc: func(req *provider.ChatRequest) (*provider.ChatResponse, error) {
+		if !strings.Contains(req.Messages[len(req.Messages)-1].Content, "assigned the following story") {
+			return &provider.ChatResponse{Content: "Done."}, nil
+		}
+		mu.Lock()
+		running++
+		if running > most {
+			most = running
+		}
+		mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mu.Lock()
+		running--
+		mu.Unlock()
+		return &provider.ChatResponse{Content: "Implemented."}, nil
+	}})
+
+	spec := limitsSpec(8)
+	spec.Settings.MaxParallelStories = 2
+	tm, err := NewTeam(spec, registry, logger.New("error"))
+	if err != nil {
+		t.Fatalf("NewTeam failed: %v", err)
+	}
+	if err := tm.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	t.Cleanup(tm.Stop)
+
+	o := tm.Orchestrator()
+	project := o.projectManager.CreateProject("many-stories", "Many stories", "Build it")
+	for i := 0; i < 12; i++ {
+		project.CreateStory("", fmt.Sprintf("Story %d", i), "Do a part", "task", "engineer", nil)
+	}
+
+	o.runExecutionPhase(context.Background(), project)
+
+	if most != 2 {
+		t.Errorf("Expected at most 2 stories at once, and 2 at some point, got %d", most)
+	}
+	for _, story := range project.ListStories() {
+		if story.GetStatus() != StoryReview {
+			t.Errorf("Expected %s worked to review, got %s", story.Title, story.GetStatus())
+		}
+	}
+}
+
+func TestOrchestrator_StoryDependencies(t *testing.T) {
+	var mu sync.Mutex
+	var finished []string
+	registry := provider.NewRegistry()
+	registry.Register(&MockProvider{ChatFunc: func(req *provider.ChatRequest) (*provider.ChatResponse, error) {
+		prompt := req.Messages[len(req.Messages)-1].Content
+		if !strings.Contains(prompt, "assigned the following story") {
+			return &provider.ChatResponse{Content: "Done."}, nil
+		}
+
+		// The schema takes longest, so anything not waiting on it finishes first
+		delay := 5 * time.Millisecond
+		if strings.Contains(prompt, "Schema") {
+			delay = 30 * time.Millisecond
+		}
+		time.Sleep(delay)
+
+		mu.Lock()
+		for _, title := range []string{"Schema", "API", "Docs"} {
+			if strings.Contains(prompt, "**Title:** "+title) {
+				finished = append(finished, title)
+			}
+		}
+		mu.Unlock()
+		return &provider.ChatResponse{Content: "Implemented."}, nil
+	}})
+
+	tm, err := NewTeam(limitsSpec(3), registry, logger.New("error"))
+	if err != nil {
+		t.Fatalf("NewTeam failed: %v", err)
+	}
+	if err := tm.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	t.Cleanup(tm.Stop)
+
+	o := tm.Orchestrator()
+	project := o.projectManager.CreateProject("ordered", "Ordered stories", "Build it")
+	schema := project.CreateStory("", "Schema", "Design the tables", "task", "engineer", nil)
+	api := project.CreateStory("", "API", "Serve the tables", "task", "engineer", nil)
+	api.DependsOn = []string{schema.ID}
+	project.CreateStory("", "Docs", "Describe the service", "task", "engineer", nil)
+
+	o.runExecutionPhase(context.Background(), project)
+
+	if !reflect.DeepEqual(finished, []string{"Docs", "Schema", "API"}) {
+		t.Errorf("Expected Docs first and API only after Schema, got %v", finished)
+	}
+}
@@ -0,0 +1,79 @@
+package team
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// OverflowPolicy decides what happens to a message sent to a full channel
+type OverflowPolicy string
+
+const (
+	OverflowDrop  OverflowPolicy = "drop"  // Drop it and log a warning
+	OverflowBlock OverflowPolicy = "block" // Wait for room up to the overflow timeout, then drop it
+	OverflowError OverflowPolicy = "error" // Drop it and return ErrChannelFull to the sender
+)
+
+// ErrChannelFull is returned when a message couldn't be delivered because the
+// channel was full
+var ErrChannelFull = errors.New("message channel full")
+
+// ParseOverflowPolicy checks a policy name from configuration. Empty means
+// the default.
+func ParseOverflowPolicy(s string) (OverflowPolicy, error) {
+	switch p := OverflowPolicy(s); p {
+	case "", OverflowDrop, OverflowBlock, OverflowError:
+		return p, nil
+	}
+	return "", fmt.Errorf("unknown overflow policy %q (valid: drop, block, error)", s)
+}
+
+// droppedMessages counts messages lost to full channels, for team status
+type droppedMessages struct {
+	client   atomic.Int64
+	internal atomic.Int64
+	inbox    atomic.Int64
+}
+
+// DroppedMessages returns how many messages each kind of channel has dropped
+// because it was full
+func (t *Team) DroppedMessages() map[string]int64 {
+	return map[string]int64{
+		"client":   t.dropped.client.Load(),
+		"internal": t.dropped.internal.Load(),
+		"inbox":    t.dropped.inbox.Load(),
+	}
+}
+
+// deliver puts msg on ch, applying policy if it's full. It reports whether
+// the message was delivered.
+func deliver(ch chan<- Message, msg Message, policy OverflowPolicy, timeout time.Duration) bool {
+	select {
+	case ch <- msg:
+		return true
+	default:
+	}
+	if policy != OverflowBlock {
+		return false
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case ch <- msg:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+// overflowError is what a sender is told about a dropped message: nothing
+// under the drop policy, ErrChannelFull otherwise
+func overflowError(policy OverflowPolicy, channel string) error {
+	if policy == OverflowDrop {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrChannelFull, channel)
+}
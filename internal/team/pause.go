@@ -0,0 +1,70 @@
+package team
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrTeamPaused is returned for work sent to a paused team
+var ErrTeamPaused = errors.New("team paused")
+
+// Team run states reported by Status
+const (
+	StateRunning = "running"
+	StatePaused  = "paused"
+	StateStopped = "stopped"
+
+	// Stopped by the daemon after sitting idle; the next ask starts it again
+	StateAutoStopped = "auto_stopped"
+)
+
+// Pause stops the team accepting new asks. Members keep running, so work
+// already in progress finishes and their context is kept for when the team
+// is resumed.
+func (t *Team) Pause() {
+	if t.paused.CompareAndSwap(false, true) {
+		t.logger.Info("team paused")
+	}
+}
+
+// Resume lets a paused team accept asks again
+func (t *Team) Resume() {
+	if t.paused.CompareAndSwap(true, false) {
+		t.logger.Info("team resumed")
+	}
+}
+
+// Paused reports whether the team is paused
+func (t *Team) Paused() bool {
+	return t.paused.Load()
+}
+
+// State returns whether the team is running, paused, stopped or auto-stopped
+func (t *Team) State() string {
+	if t.Paused() {
+		return StatePaused
+	}
+	t.mu.RLock()
+	ctx := t.ctx
+	t.mu.RUnlock()
+	if ctx == nil || ctx.Err() != nil {
+		if t.autoStopped.Load() {
+			return StateAutoStopped
+		}
+		return StateStopped
+	}
+	return StateRunning
+}
+
+// pausedResponse answers an ask sent while the team is paused
+func (t *Team) pausedResponse() <-chan Message {
+	responseChan := make(chan Message, 1)
+	responseChan <- Message{
+		Type:    MsgClientResponse,
+		From:    "system",
+		To:      "client",
+		Content: fmt.Sprintf("Team %s is paused. Resume it with: ugudu team resume %s", t.Name, t.Name),
+	}
+	close(responseChan)
+	return responseChan
+}
@@ -0,0 +1,65 @@
+package team
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arcslash/ugudu/internal/logger"
+	"github.com/arcslash/ugudu/internal/provider"
+)
+
+func TestTeam_PauseRejectsAsks(t *testing.T) {
+	calls := 0
+	registry := provider.NewRegistry()
+	registry.Register(&MockProvider{ChatFunc: func(req *provider.ChatRequest) (*provider.ChatResponse, error) {
+		calls++
+		return &provider.ChatResponse{Content: "On it."}, nil
+	}})
+
+	tm, err := NewTeam(limitsSpec(1), registry, logger.New("error"))
+	if err != nil {
+		t.Fatalf("NewTeam failed: %v", err)
+	}
+	if err := tm.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer tm.Stop()
+
+	ask := func() Message {
+		t.Helper()
+		select {
+		case msg := <-tm.AskMember("pm", "Plan the sprint"):
+			return msg
+		case <-time.After(5 * time.Second):
+			t.Fatal("Timed out waiting for response")
+		}
+		return Message{}
+	}
+
+	tm.Pause()
+	if tm.State() != StatePaused || tm.Status()["paused"] != true {
+		t.Errorf("Expected paused status, got %v", tm.Status()["status"])
+	}
+
+	if msg := ask(); msg.From != "system" || !strings.Contains(fmt.Sprint(msg.Content), "paused") {
+		t.Errorf("Expected a paused rejection, got %q from %s", msg.Content, msg.From)
+	}
+	if msg, ok := <-tm.Ask("Plan the sprint"); !ok || !strings.Contains(fmt.Sprint(msg.Content), "paused") {
+		t.Errorf("Expected Ask to be rejected too, got %q", msg.Content)
+	}
+	if calls != 0 {
+		t.Errorf("Expected no model calls while paused, got %d", calls)
+	}
+
+	// Members stayed up, so the team picks up where it left off
+	tm.Resume()
+	if tm.State() != StateRunning {
+		t.Errorf("Expected running after resume, got %s", tm.State())
+	}
+	if msg := ask(); msg.Content != "On it." {
+		t.Errorf("Expected the member to answer after resume, got %q", msg.Content)
+	}
+}
@@ -0,0 +1,152 @@
+package team
+
+import (
+	"fmt"
+	"strings"
+)
+
+// WorkflowSoftwareDev is the built-in workflow projects follow when a spec
+// doesn't declare phases: the PM (and BA, if there is one) plan, the PM
+// breaks the plan into stories, engineers work them and QA reviews
+const WorkflowSoftwareDev = "software-dev"
+
+// builtinWorkflows are the workflows a spec can name instead of declaring
+// its own phases
+var builtinWorkflows = map[string][]WorkflowPhase{
+	WorkflowSoftwareDev: {
+		{Name: "planning", Kind: PhasePlanning, Roles: []string{"pm", "ba"}},
+		{Name: "task breakdown", Kind: PhaseTaskBreakdown, Roles: []string{"pm"}},
+		{Name: "engineering", Kind: PhaseExecution, Roles: []string{"backend", "frontend", "engineer"}},
+		{Name: "qa", Kind: PhaseReview, Roles: []string{"qa"}},
+	},
+}
+
+// phaseOrder is the order a workflow's phases run in. Each kind appears at
+// most once; planning and execution are required.
+var phaseOrder = []ProjectPhase{PhasePlanning, PhaseTaskBreakdown, PhaseExecution, PhaseReview}
+
+// projectPhases returns the phases projects run through: the spec's own, or
+// those of the built-in workflow it names, software-dev by default
+func (w WorkflowSpec) projectPhases() []WorkflowPhase {
+	if len(w.Phases) > 0 {
+		return w.Phases
+	}
+	if phases, ok := builtinWorkflows[w.Name]; ok {
+		return phases
+	}
+	return builtinWorkflows[WorkflowSoftwareDev]
+}
+
+// validateWorkflow checks the spec's workflow names a built-in or declares
+// phases its roles can run
+func validateWorkflow(spec *TeamSpec) error {
+	w := spec.Workflow
+	if len(w.Phases) == 0 {
+		if _, ok := builtinWorkflows[w.Name]; w.Name != "" && !ok {
+			return fmt.Errorf("workflow.name %q isn't a built-in workflow (have: %s)", w.Name, WorkflowSoftwareDev)
+		}
+		return nil
+	}
+
+	next := 0
+	for _, phase := range w.Phases {
+		pos := -1
+		for i, kind := range phaseOrder {
+			if kind == phase.Kind {
+				pos = i
+			}
+		}
+		if pos < 0 {
+			return fmt.Errorf("workflow phase %q: kind must be one of %s, got %q", phase.Name, joinPhases(phaseOrder), phase.Kind)
+		}
+		if pos < next {
+			return fmt.Errorf("workflow phase %q: phases must run in the order %s, each at most once", phase.Name, joinPhases(phaseOrder))
+		}
+		next = pos + 1
+
+		staffed := false
+		for _, role := range phase.Roles {
+			if _, ok := spec.Roles[role]; ok {
+				staffed = true
+			}
+		}
+		if !staffed {
+			return fmt.Errorf("workflow phase %q: none of its roles %v are in the spec", phase.Name, phase.Roles)
+		}
+	}
+
+	if _, ok := phaseOf(w.Phases, PhasePlanning); !ok {
+		return fmt.Errorf("workflow needs a %s phase", PhasePlanning)
+	}
+	if _, ok := phaseOf(w.Phases, PhaseExecution); !ok {
+		return fmt.Errorf("workflow needs an %s phase", PhaseExecution)
+	}
+	return nil
+}
+
+// phaseOf returns the workflow's phase of the given kind
+func phaseOf(phases []WorkflowPhase, kind ProjectPhase) (WorkflowPhase, bool) {
+	for _, phase := range phases {
+		if phase.Kind == kind {
+			return phase, true
+		}
+	}
+	return WorkflowPhase{}, false
+}
+
+func joinPhases(phases []ProjectPhase) string {
+	names := make([]string, len(phases))
+	for i, p := range phases {
+		names[i] = string(p)
+	}
+	return strings.Join(names, ", ")
+}
+
+// phaseMembers returns a member for each of the phase's roles the team has,
+// in the phase's order. GetMemberByRole picks an idle one of a role's
+// members when there is one.
+func (o *Orchestrator) phaseMembers(phase WorkflowPhase) []*Member {
+	var members []*Member
+	for _, role := range phase.Roles {
+		if m := o.team.GetMemberByRole(role); m != nil {
+			members = append(members, m)
+		}
+	}
+	return members
+}
+
+// phaseLead returns the member leading a phase: one with its first role the
+// team has, or nil if the workflow has no such phase or nobody to run it
+func (o *Orchestrator) phaseLead(kind ProjectPhase) *Member {
+	phase, ok := phaseOf(o.phases, kind)
+	if !ok {
+		return nil
+	}
+	if members := o.phaseMembers(phase); len(members) > 0 {
+		return members[0]
+	}
+	return nil
+}
+
+// leadRole is the role questions and reports are routed to: the planning
+// phase's first role the team has, else its first client-facing role, as
+// a team running the built-in workflow may have no PM
+func (o *Orchestrator) leadRole() string {
+	if lead := o.phaseLead(PhasePlanning); lead != nil {
+		return lead.RoleName
+	}
+	for _, role := range o.team.Spec.ClientFacing {
+		if o.team.GetMemberByRole(role) != nil {
+			return role
+		}
+	}
+	return "pm"
+}
+
+// roleLabel names a member's role in prompts
+func roleLabel(m *Member) string {
+	if m.Role.Title != "" {
+		return m.Role.Title
+	}
+	return m.RoleName
+}
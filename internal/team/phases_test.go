@@ -0,0 +1,148 @@
+package team
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arcslash/ugudu/internal/logger"
+	"github.com/arcslash/ugudu/internal/provider"
+)
+
+// tradingSpec is a team with no dev roles: a researcher plans and traders
+// act on the plan
+func tradingSpec() *TeamSpec {
+	return &TeamSpec{
+		Metadata:     Metadata{Name: "desk"},
+		ClientFacing: []string{"researcher"},
+		Roles: map[string]Role{
+			"researcher": {Title: "Market Researcher", Count: 1, Model: ModelConfig{Provider: "mock"}},
+			"trader":     {Title: "Trader", Count: 2, Model: ModelConfig{Provider: "mock"}},
+		},
+		Workflow: WorkflowSpec{
+			Phases: []WorkflowPhase{
+				{Name: "research", Kind: PhasePlanning, Roles: []string{"researcher"}},
+				{Name: "trading", Kind: PhaseExecution, Roles: []string{"trader"}},
+			},
+		},
+	}
+}
+
+func TestOrchestrator_CustomWorkflow(t *testing.T) {
+	registry := provider.NewRegistry()
+	registry.Register(&MockProvider{ChatFunc: func(req *provider.ChatRequest) (*provider.ChatResponse, error) {
+		prompt := req.Messages[len(req.Messages)-1].Content
+		switch {
+		case strings.Contains(prompt, "As the Market Researcher leading this project"):
+			return &provider.ChatResponse{Content: "## Summary\nRebalance into bonds"}, nil
+		case strings.Contains(prompt, "As the Market Researcher, create detailed requirements"):
+			return &provider.ChatResponse{Content: `[{"title": "Sell equities", "description": "Trim to 40%", "priority": "must"},
+				{"title": "Buy bonds", "description": "Add short duration", "priority": "must"}]`}, nil
+		case strings.Contains(prompt, "You have been assigned the following story"):
+			return &provider.ChatResponse{Content: "Orders placed."}, nil
+		}
+		t.Errorf("Unexpected prompt: %s", prompt)
+		return &provider.ChatResponse{Content: "[]"}, nil
+	}})
+
+	tm, err := NewTeam(tradingSpec(), registry, logger.New("error"))
+	if err != nil {
+		t.Fatalf("NewTeam failed: %v", err)
+	}
+	if err := tm.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	t.Cleanup(tm.Stop)
+
+	if _, err := tm.StartProject("Move the portfolio to 60/40"); err != nil {
+		t.Fatalf("StartProject failed: %v", err)
+	}
+	project := tm.Orchestrator().activeProject
+
+	deadline := time.Now().Add(5 * time.Second)
+	for project.GetPhase() != PhaseComplete {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the project to complete, still %s", project.GetPhase())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	traders := make(map[string]bool)
+	for _, m := range tm.MembersWithRole("trader") {
+		traders[m.ID] = true
+	}
+	if len(project.Stories) != 2 {
+		t.Fatalf("Expected a story per requirement, got %d", len(project.Stories))
+	}
+	for _, s := range project.Stories {
+		if !traders[s.AssignedMember] || s.Status != StoryReview {
+			t.Errorf("Expected %q worked by a trader, got member %q status %s", s.Title, s.AssignedMember, s.Status)
+		}
+	}
+	if project.Stories[0].AssignedMember == project.Stories[1].AssignedMember {
+		t.Errorf("Expected the stories spread across both traders")
+	}
+}
+
+func TestOrchestrator_LeadRole(t *testing.T) {
+	registry := provider.NewRegistry()
+	registry.Register(&MockProvider{})
+
+	// The planning phase's first role the team has leads
+	spec := tradingSpec()
+	spec.Workflow.Phases[0].Roles = []string{"analyst", "researcher"}
+	tm, err := NewTeam(spec, registry, logger.New("error"))
+	if err != nil {
+		t.Fatalf("NewTeam failed: %v", err)
+	}
+	if lead := tm.Orchestrator().leadRole(); lead != "researcher" {
+		t.Errorf("Expected the researcher to lead, got %s", lead)
+	}
+
+	// The built-in workflow plans with a PM; a team without one falls back
+	// to its client-facing role
+	spec = tradingSpec()
+	spec.Workflow = WorkflowSpec{}
+	tm, err = NewTeam(spec, registry, logger.New("error"))
+	if err != nil {
+		t.Fatalf("NewTeam failed: %v", err)
+	}
+	if lead := tm.Orchestrator().leadRole(); lead != "researcher" {
+		t.Errorf("Expected the client-facing researcher to lead, got %s", lead)
+	}
+}
+
+func TestNewTeam_InvalidWorkflow(t *testing.T) {
+	registry := provider.NewRegistry()
+	registry.Register(&MockProvider{})
+
+	tests := []struct {
+		name     string
+		workflow WorkflowSpec
+		want     string
+	}{
+		{"unknown built-in", WorkflowSpec{Name: "support"}, "isn't a built-in"},
+		{"unknown kind", WorkflowSpec{Phases: []WorkflowPhase{{Name: "x", Kind: "trading", Roles: []string{"trader"}}}}, "kind must be"},
+		{"out of order", WorkflowSpec{Phases: []WorkflowPhase{
+			{Name: "trading", Kind: PhaseExecution, Roles: []string{"trader"}},
+			{Name: "research", Kind: PhasePlanning, Roles: []string{"researcher"}},
+		}}, "must run in the order"},
+		{"unstaffed", WorkflowSpec{Phases: []WorkflowPhase{
+			{Name: "research", Kind: PhasePlanning, Roles: []string{"analyst"}},
+			{Name: "trading", Kind: PhaseExecution, Roles: []string{"trader"}},
+		}}, "none of its roles"},
+		{"no execution", WorkflowSpec{Phases: []WorkflowPhase{
+			{Name: "research", Kind: PhasePlanning, Roles: []string{"researcher"}},
+		}}, "needs an execution phase"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := tradingSpec()
+			spec.Workflow = tt.workflow
+			if _, err := NewTeam(spec, registry, logger.New("error")); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected an error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package team
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/arcslash/ugudu/internal/tools"
+	"github.com/arcslash/ugudu/internal/workspace"
+)
+
+// Progress is what a member last reported about its current work with the
+// report_progress tool. It's cleared when the member goes idle.
+type Progress struct {
+	Percent   int       `json:"percent"`
+	Status    string    `json:"status"` // in_progress, blocked, completed, ...
+	Message   string    `json:"message,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Progress returns the member's last progress report, or nil if it hasn't
+// reported on its current work
+func (m *Member) Progress() *Progress {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.progress == nil {
+		return nil
+	}
+	p := *m.progress
+	return &p
+}
+
+// reportProgress records a progress report from the member running the tool
+// and broadcasts it as a "progress" activity event carrying the percent. With
+// a workspace, it's also appended to the member's activity log.
+func (t *Team) reportProgress(ctx context.Context, status string, details map[string]interface{}) error {
+	member := t.GetMember(tools.CallerID(ctx))
+	if member == nil {
+		return fmt.Errorf("unknown member %q", tools.CallerID(ctx))
+	}
+
+	percent, _ := details["percent_complete"].(float64)
+	message, _ := details["message"].(string)
+	p := Progress{
+		Percent:   clampPercent(percent),
+		Status:    status,
+		Message:   message,
+		UpdatedAt: time.Now(),
+	}
+
+	member.mu.Lock()
+	member.progress = &p
+	member.mu.Unlock()
+
+	line := message
+	if line == "" {
+		line = status
+	}
+	t.notifyActivity(ctx, member.ID, "progress", line, map[string]interface{}{
+		"percent": p.Percent,
+		"status":  p.Status,
+	})
+
+	t.mu.RLock()
+	ws := t.workspace
+	t.mu.RUnlock()
+	if ws != nil {
+		t.logProgress(ws, member, p)
+	}
+	return nil
+}
+
+// logProgress appends a progress report to the member's activity log
+func (t *Team) logProgress(ws *workspace.Workspace, member *Member, p Progress) {
+	activity, err := workspace.NewActivityLogger(ws, member.RoleName)
+	if err != nil {
+		t.logger.Warn("failed to open activity log", "member", member.ID, "error", err)
+		return
+	}
+	defer activity.Close()
+
+	var taskID string
+	if task := member.GetCurrentTask(); task != nil {
+		taskID = task.ID
+	}
+	entry := workspace.ProgressActivity(member.ID, member.RoleName, taskID, p.Status, float64(p.Percent))
+	if p.Message != "" {
+		entry = entry.WithData("message", p.Message)
+	}
+	if err := activity.Log(entry); err != nil {
+		t.logger.Warn("failed to log progress", "member", member.ID, "error", err)
+	}
+}
+
+// clampPercent rounds a reported percentage into 0-100
+func clampPercent(pct float64) int {
+	switch {
+	case pct < 0:
+		return 0
+	case pct > 100:
+		return 100
+	}
+	return int(pct + 0.5)
+}
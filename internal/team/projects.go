@@ -0,0 +1,68 @@
+package team
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNoProject is returned for project requests to a team that hasn't
+// started one
+var ErrNoProject = errors.New("no active project")
+
+// Orchestrator returns the orchestrator that runs the team's projects,
+// creating it on first use
+func (t *Team) Orchestrator() *Orchestrator {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.orchestrator == nil {
+		t.orchestrator = NewOrchestrator(t, t.logger)
+	}
+	return t.orchestrator
+}
+
+// StartProject starts a project for a client request and returns its
+// status. The project's phases run in the background on the team's context,
+// so the team has to be running.
+func (t *Team) StartProject(request string) (map[string]interface{}, error) {
+	if t.Paused() {
+		return nil, fmt.Errorf("%w: %s", ErrTeamPaused, t.Name)
+	}
+	ctx := t.runContext()
+	if ctx == nil || ctx.Err() != nil {
+		return nil, fmt.Errorf("team %s is not running", t.Name)
+	}
+
+	o := t.Orchestrator()
+	if _, err := o.StartProject(ctx, request); err != nil {
+		return nil, err
+	}
+	return o.GetProjectStatus(), nil
+}
+
+// ProjectStatus returns the status of the team's active project
+func (t *Team) ProjectStatus() (map[string]interface{}, error) {
+	t.mu.RLock()
+	o := t.orchestrator
+	t.mu.RUnlock()
+
+	if o == nil {
+		return nil, ErrNoProject
+	}
+	status := o.GetProjectStatus()
+	if status == nil {
+		return nil, ErrNoProject
+	}
+	return status, nil
+}
+
+// projectQuestions returns the active project's unanswered questions
+func (t *Team) projectQuestions() []Question {
+	t.mu.RLock()
+	o := t.orchestrator
+	t.mu.RUnlock()
+
+	if o == nil {
+		return nil
+	}
+	return o.GetPendingQuestions()
+}
@@ -0,0 +1,140 @@
+package team
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/arcslash/ugudu/internal/tools"
+	"github.com/google/uuid"
+)
+
+// DefaultQuestionTimeout is how long a member waits for the client to answer
+// an ask_client question before its task fails
+const DefaultQuestionTimeout = 15 * time.Minute
+
+var (
+	// ErrQuestionUnanswered is returned when a question times out
+	ErrQuestionUnanswered = errors.New("question not answered")
+	// ErrQuestionNotFound is returned when answering an unknown question
+	ErrQuestionNotFound = errors.New("question not found")
+)
+
+// WithQuestionTimeout sets how long members wait for the client to answer
+func WithQuestionTimeout(d time.Duration) TeamOption {
+	return func(t *Team) {
+		t.questionTimeout = d
+	}
+}
+
+// pendingQuestion is a question a member is blocked on
+type pendingQuestion struct {
+	Question
+	answer chan string
+}
+
+// askClient registers a question from a member and blocks until the client
+// answers it, ctx is cancelled or the question times out. The member is shown
+// as waiting in the meantime.
+func (t *Team) askClient(ctx context.Context, question string) (string, error) {
+	member := t.GetMember(tools.CallerID(ctx))
+	if member == nil {
+		return "", fmt.Errorf("unknown member %q", tools.CallerID(ctx))
+	}
+
+	q := &pendingQuestion{
+		Question: Question{
+			ID:         uuid.New().String()[:8],
+			Content:    question,
+			FromRole:   member.RoleName,
+			FromMember: member.ID,
+			ToRole:     "client",
+			Status:     "pending",
+			CreatedAt:  time.Now(),
+		},
+		answer: make(chan string, 1),
+	}
+	if task := member.GetCurrentTask(); task != nil {
+		q.Context = task.ID
+	}
+
+	t.questionMu.Lock()
+	if t.questions == nil {
+		t.questions = make(map[string]*pendingQuestion)
+	}
+	t.questions[q.ID] = q
+	t.questionMu.Unlock()
+
+	defer func() {
+		t.questionMu.Lock()
+		delete(t.questions, q.ID)
+		t.questionMu.Unlock()
+	}()
+
+	previous := member.GetStatus()
+	member.setStatus(ctx, MemberWaiting)
+	defer member.setStatus(ctx, previous)
+
+	t.logger.Info("waiting for client answer", "member", member.ID, "question_id", q.ID)
+	t.NotifyActivity(ctx, member.ID, "question", fmt.Sprintf("Asked the client: %s", truncateMessage(question, 100)))
+	t.shareProgress(ctx, member.ID, fmt.Sprintf("%s asks: %s (answer with: ugudu team answer %s %s \"...\")",
+		member.DisplayName(), question, t.Name, q.ID))
+
+	timeout := t.questionTimeout
+	if timeout <= 0 {
+		timeout = DefaultQuestionTimeout
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case answer := <-q.answer:
+		return answer, nil
+	case <-timer.C:
+		return "", fmt.Errorf("%w after %s", ErrQuestionUnanswered, timeout)
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// PendingQuestions returns the questions members are waiting on, including
+// the active project's, oldest first
+func (t *Team) PendingQuestions() []Question {
+	t.questionMu.Lock()
+	questions := make([]Question, 0, len(t.questions))
+	for _, q := range t.questions {
+		questions = append(questions, q.Question)
+	}
+	t.questionMu.Unlock()
+
+	questions = append(questions, t.projectQuestions()...)
+	sort.Slice(questions, func(i, j int) bool {
+		return questions[i].CreatedAt.Before(questions[j].CreatedAt)
+	})
+	return questions
+}
+
+// AnswerQuestion answers a pending question, resuming the member or project
+// waiting on it
+func (t *Team) AnswerQuestion(id, answer string) error {
+	t.questionMu.Lock()
+	q, ok := t.questions[id]
+	if ok {
+		delete(t.questions, id)
+	}
+	t.questionMu.Unlock()
+
+	if !ok {
+		for _, pq := range t.projectQuestions() {
+			if pq.ID == id {
+				return t.Orchestrator().ProvideAnswer(id, answer)
+			}
+		}
+		return fmt.Errorf("%w: %s", ErrQuestionNotFound, id)
+	}
+	q.answer <- answer
+	t.logger.Info("client answered question", "member", q.FromMember, "question_id", id)
+	return nil
+}
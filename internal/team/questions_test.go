@@ -0,0 +1,130 @@
+package team
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arcslash/ugudu/internal/logger"
+	"github.com/arcslash/ugudu/internal/provider"
+	"github.com/google/uuid"
+)
+
+// askClientTeam starts a team whose engineer asks the client which port to
+// use, then reports the answer back as its result
+func askClientTeam(t *testing.T, opts ...TeamOption) *Team {
+	t.Helper()
+
+	mockProv := &MockProvider{ChatFunc: func(req *provider.ChatRequest) (*provider.ChatResponse, error) {
+		last := req.Messages[len(req.Messages)-1]
+		if last.ToolCallID == "call-1" {
+			return &provider.ChatResponse{Content: "Using the port from the client: " + last.Content}, nil
+		}
+		return &provider.ChatResponse{ToolCalls: []provider.ToolCall{
+			{ID: "call-1", Name: "ask_client", Arguments: `{"question":"Which port should the server listen on?"}`},
+		}}, nil
+	}}
+
+	registry := provider.NewRegistry()
+	registry.Register(mockProv)
+	tm, err := NewTeam(limitsSpec(1), registry, logger.New("error"), opts...)
+	if err != nil {
+		t.Fatalf("NewTeam failed: %v", err)
+	}
+	if err := tm.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	t.Cleanup(tm.Stop)
+	return tm
+}
+
+// assignTask hands the engineer a task the way delegation does
+func assignTask(tm *Team, content string) *Task {
+	engineer := tm.GetMemberByRole("engineer")
+	task := &Task{
+		ID:         uuid.New().String(),
+		Content:    content,
+		From:       "pm",
+		To:         engineer.ID,
+		Status:     TaskAssigned,
+		CreatedAt:  time.Now(),
+		ResultChan: make(chan *TaskResult, 1),
+	}
+	tm.AddTask(task)
+	engineer.Send(Message{
+		ID:        uuid.New().String(),
+		Type:      MsgTaskAssignment,
+		From:      "pm",
+		To:        engineer.ID,
+		Content:   task,
+		TaskID:    task.ID,
+		Timestamp: time.Now(),
+	})
+	return task
+}
+
+func TestTeam_AskClientBlocksUntilAnswered(t *testing.T) {
+	tm := askClientTeam(t)
+	task := assignTask(tm, "Add a health check server")
+
+	// The question shows up once the engineer blocks on it
+	var pending []Question
+	deadline := time.Now().Add(5 * time.Second)
+	for len(pending) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+		pending = tm.PendingQuestions()
+	}
+	if len(pending) != 1 {
+		t.Fatalf("Expected 1 pending question, got %d", len(pending))
+	}
+	q := pending[0]
+	if q.Content != "Which port should the server listen on?" || q.FromMember != "engineer" || q.Context != task.ID {
+		t.Errorf("Unexpected question: %+v", q)
+	}
+	if status := tm.GetMemberByRole("engineer").GetStatus(); status != MemberWaiting {
+		t.Errorf("Expected engineer to be waiting, got %s", status)
+	}
+	if listed, _ := tm.Status()["pending_questions"].([]Question); len(listed) != 1 {
+		t.Errorf("Expected the question in team status, got %v", tm.Status()["pending_questions"])
+	}
+
+	if err := tm.AnswerQuestion(q.ID, "8081"); err != nil {
+		t.Fatalf("AnswerQuestion failed: %v", err)
+	}
+
+	select {
+	case result := <-task.ResultChan:
+		if !result.Success || !strings.Contains(result.Content, "8081") {
+			t.Errorf("Expected the task to finish with the answer, got %+v", result)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for the task to resume")
+	}
+
+	if len(tm.PendingQuestions()) != 0 {
+		t.Errorf("Expected no pending questions after answering, got %v", tm.PendingQuestions())
+	}
+	if err := tm.AnswerQuestion(q.ID, "again"); !errors.Is(err, ErrQuestionNotFound) {
+		t.Errorf("Expected ErrQuestionNotFound answering twice, got %v", err)
+	}
+}
+
+func TestTeam_AskClientTimeoutFailsTask(t *testing.T) {
+	tm := askClientTeam(t, WithQuestionTimeout(50*time.Millisecond))
+	task := assignTask(tm, "Add a health check server")
+
+	select {
+	case result := <-task.ResultChan:
+		if result.Success || !strings.Contains(result.Error, ErrQuestionUnanswered.Error()) {
+			t.Errorf("Expected the task to fail unanswered, got %+v", result)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for the task to fail")
+	}
+
+	if len(tm.PendingQuestions()) != 0 {
+		t.Errorf("Expected the timed out question to be dropped, got %v", tm.PendingQuestions())
+	}
+}
@@ -0,0 +1,43 @@
+package team
+
+import (
+	"bytes"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RenameSpec returns spec YAML with metadata.name set to name. Only that
+// field changes: roles with a name of their own, comments and key order are
+// left as they were.
+func RenameSpec(content []byte, name string) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, fmt.Errorf("parse spec: %w", err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("parse spec: expected a mapping at the top level")
+	}
+	root := doc.Content[0]
+
+	metadata := mappingValue(root, "metadata")
+	if metadata == nil || metadata.Kind != yaml.MappingNode {
+		metadata = &yaml.Node{Kind: yaml.MappingNode}
+		setMappingValue(root, "metadata", metadata)
+	}
+	if node := mappingValue(metadata, "name"); node != nil && node.Kind == yaml.ScalarNode {
+		// Keep the node, and with it any comment on the line
+		node.Value, node.Tag, node.Style = name, "!!str", 0
+	} else {
+		setMappingValue(metadata, "name", scalarNode(name))
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, fmt.Errorf("encode spec: %w", err)
+	}
+	enc.Close()
+	return buf.Bytes(), nil
+}
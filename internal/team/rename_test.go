@@ -0,0 +1,64 @@
+package team
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestRenameSpec(t *testing.T) {
+	// The first name: line belongs to a role, before the metadata block
+	content := []byte(`apiVersion: ugudu/v1
+kind: Team
+roles:
+  pm:
+    name: Sarah # Keeps her name
+    title: PM
+metadata:
+    name: dev-team   # The spec's name
+    description: Team with a named PM
+`)
+
+	out, err := RenameSpec(content, "alpha")
+	if err != nil {
+		t.Fatalf("RenameSpec failed: %v", err)
+	}
+
+	var spec struct {
+		Metadata Metadata
+		Roles    map[string]struct{ Name, Title string }
+	}
+	if err := yaml.Unmarshal(out, &spec); err != nil {
+		t.Fatalf("Renamed spec isn't YAML: %v", err)
+	}
+	if spec.Metadata.Name != "alpha" {
+		t.Errorf("Expected metadata.name alpha, got %q", spec.Metadata.Name)
+	}
+	if spec.Metadata.Description != "Team with a named PM" {
+		t.Errorf("Expected the description unchanged, got %q", spec.Metadata.Description)
+	}
+	if spec.Roles["pm"].Name != "Sarah" || spec.Roles["pm"].Title != "PM" {
+		t.Errorf("Expected the role unchanged, got %+v", spec.Roles["pm"])
+	}
+	if !strings.Contains(string(out), "# Keeps her name") {
+		t.Errorf("Expected comments kept, got:\n%s", out)
+	}
+	if strings.Index(string(out), "roles:") > strings.Index(string(out), "metadata:") {
+		t.Errorf("Expected key order kept, got:\n%s", out)
+	}
+}
+
+func TestRenameSpec_NoMetadata(t *testing.T) {
+	out, err := RenameSpec([]byte("extends: dev-team\n"), "alpha")
+	if err != nil {
+		t.Fatalf("RenameSpec failed: %v", err)
+	}
+	spec, err := decodeSpec(out)
+	if err != nil {
+		t.Fatalf("Renamed spec doesn't decode: %v", err)
+	}
+	if spec.Metadata.Name != "alpha" || spec.Extends != "dev-team" {
+		t.Errorf("Expected metadata.name added, got %+v", spec)
+	}
+}
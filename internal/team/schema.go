@@ -0,0 +1,97 @@
+package team
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Spec schema identifiers
+const (
+	APIVersionV1 = "ugudu/v1"
+	KindTeam     = "Team"
+)
+
+// specDecoders turn each supported apiVersion into the current TeamSpec.
+// When the schema changes incompatibly, add a decoder for the new version
+// and have the old one migrate its fields.
+var specDecoders = map[string]func(data []byte) (*TeamSpec, error){
+	APIVersionV1: decodeSpecV1,
+}
+
+// decodeSpec parses a spec document with the decoder for its apiVersion.
+// A missing apiVersion or kind means ugudu/v1 Team.
+func decodeSpec(data []byte) (*TeamSpec, error) {
+	var header struct {
+		APIVersion string `yaml:"apiVersion"`
+		Kind       string `yaml:"kind"`
+	}
+	if err := yaml.Unmarshal(data, &header); err != nil {
+		return nil, fmt.Errorf("parse spec: %w", err)
+	}
+
+	version := header.APIVersion
+	if version == "" {
+		version = APIVersionV1
+	}
+	decode, ok := specDecoders[version]
+	if !ok {
+		versions := make(map[string]bool, len(specDecoders))
+		for v := range specDecoders {
+			versions[v] = true
+		}
+		return nil, fmt.Errorf("unsupported apiVersion %q (supported: %s)", header.APIVersion, strings.Join(sortedKeys(versions), ", "))
+	}
+	if header.Kind != "" && header.Kind != KindTeam {
+		return nil, fmt.Errorf("unsupported kind %q (expected %s)", header.Kind, KindTeam)
+	}
+
+	return decode(data)
+}
+
+// decodeSpecV1 decodes a ugudu/v1 spec, rejecting unknown top-level keys
+func decodeSpecV1(data []byte) (*TeamSpec, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parse spec: %w", err)
+	}
+	if len(doc.Content) > 0 && doc.Content[0].Kind == yaml.MappingNode {
+		valid := specKeys()
+		fields := doc.Content[0].Content
+		for i := 0; i < len(fields); i += 2 {
+			if !valid[fields[i].Value] {
+				return nil, fmt.Errorf("unknown key %q on line %d (valid keys: %s)", fields[i].Value, fields[i].Line, strings.Join(sortedKeys(valid), ", "))
+			}
+		}
+	}
+
+	var spec TeamSpec
+	if err := yaml.Unmarshal(data, &spec); err != nil {
+		return nil, fmt.Errorf("parse spec: %w", err)
+	}
+	return &spec, nil
+}
+
+// specKeys returns the top-level keys a TeamSpec accepts
+func specKeys() map[string]bool {
+	t := reflect.TypeOf(TeamSpec{})
+	keys := make(map[string]bool, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		if name := strings.Split(t.Field(i).Tag.Get("yaml"), ",")[0]; name != "" && name != "-" {
+			keys[name] = true
+		}
+	}
+	return keys
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
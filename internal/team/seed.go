@@ -0,0 +1,41 @@
+package team
+
+import "github.com/arcslash/ugudu/internal/provider"
+
+// seedAck is the reply recorded after the seed, so the seeded context reads
+// as a finished exchange before the first real request
+const seedAck = "Understood. I'll keep this background in mind."
+
+// WithSeed primes every member's context with background, e.g. project notes
+// or a prior conversation, so a new team starts aware of earlier decisions
+func WithSeed(content string) TeamOption {
+	return func(t *Team) {
+		t.seed = content
+	}
+}
+
+// Seed returns the background the team's members start with
+func (t *Team) Seed() string {
+	return t.seed
+}
+
+// seedContext adds the team's seed to a member's context if it's empty. A
+// member with history, e.g. restored from a previous run, is left alone; the
+// seed is already part of it.
+func (m *Member) seedContext(seed string) {
+	m.conversationMu.Lock()
+	defer m.conversationMu.Unlock()
+
+	if seed == "" || len(m.conversationCtx) > 0 {
+		return
+	}
+
+	for _, msg := range []provider.Message{
+		{Role: "user", Content: "Background for your work on this team:\n\n" + seed},
+		{Role: "assistant", Content: seedAck},
+	} {
+		m.conversationCtx = append(m.conversationCtx, msg)
+		m.contextSequence++
+		m.Team.SaveMemberContext(m.ID, msg.Role, msg.Content, m.contextSequence)
+	}
+}
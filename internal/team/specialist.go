@@ -0,0 +1,235 @@
+package team
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/arcslash/ugudu/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+// SpecialistResult describes a specialist role added to a spec
+type SpecialistResult struct {
+	RoleID string `json:"role_id"`
+	Title  string `json:"title"`
+	Lead   string `json:"lead,omitempty"` // Role that can now delegate to the specialist
+}
+
+// ValidateSpecialistName checks a specialist name is a plain file name, so it
+// can't reach outside the specialists directory
+func ValidateSpecialistName(name string) error {
+	if name == "" || strings.HasPrefix(name, ".") || strings.ContainsAny(name, `/\`) || name != filepath.Base(name) {
+		return fmt.Errorf("invalid specialist name %q: use the name of a file in the specialists directory, without .yaml", name)
+	}
+	return nil
+}
+
+// AddSpecialist merges a specialist template from the specialists directory
+// into the spec at specPath and saves it. The team lead (first client-facing
+// role) is given can_delegate to the new role.
+//
+// A specialist file either holds a single role under `roles:` or is itself a
+// role definition, in which case the specialist name becomes the role ID.
+func AddSpecialist(specPath, specialist string) (*SpecialistResult, error) {
+	if err := ValidateSpecialistName(specialist); err != nil {
+		return nil, err
+	}
+	specialistPath := filepath.Join(config.SpecialistsDir(), specialist+".yaml")
+	roleID, roleNode, err := loadSpecialistRole(specialistPath, specialist)
+	if err != nil {
+		return nil, err
+	}
+
+	var role Role
+	if err := roleNode.Decode(&role); err != nil {
+		return nil, fmt.Errorf("parse specialist %s: %w", specialist, err)
+	}
+	if role.Title == "" || role.Persona == "" {
+		return nil, fmt.Errorf("specialist %s must define a title and persona", specialist)
+	}
+
+	// Validate against the effective spec so inherited roles count too
+	spec, err := LoadSpec(specPath)
+	if err != nil {
+		return nil, err
+	}
+	if _, exists := spec.Roles[roleID]; exists {
+		return nil, fmt.Errorf("role %s already exists in spec %s", roleID, spec.Metadata.Name)
+	}
+	lead := specLead(spec)
+
+	raw, err := os.ReadFile(specPath)
+	if err != nil {
+		return nil, fmt.Errorf("read spec file: %w", err)
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("parse spec: %w", err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("parse spec: expected a mapping at the top level")
+	}
+	root := doc.Content[0]
+
+	rolesNode := mappingValue(root, "roles")
+	if rolesNode == nil {
+		rolesNode = &yaml.Node{Kind: yaml.MappingNode}
+		setMappingValue(root, "roles", rolesNode)
+	}
+
+	if lead != "" {
+		leadRole := spec.Roles[lead]
+
+		// Specialists without a model run on the lead's
+		if role.Model.Provider == "" {
+			modelNode := &yaml.Node{}
+			if err := modelNode.Encode(leadRole.Model); err != nil {
+				return nil, fmt.Errorf("encode model: %w", err)
+			}
+			setMappingValue(roleNode, "model", modelNode)
+		}
+		if role.ReportsTo == "" {
+			setMappingValue(roleNode, "reports_to", scalarNode(lead))
+		}
+
+		// can_delegate lists replace the parent's when merged, so write the full list
+		delegates := &yaml.Node{Kind: yaml.SequenceNode}
+		for _, d := range leadRole.CanDelegate {
+			delegates.Content = append(delegates.Content, scalarNode(d))
+		}
+		delegates.Content = append(delegates.Content, scalarNode(roleID))
+
+		leadNode := mappingValue(rolesNode, lead)
+		if leadNode == nil {
+			// Lead is inherited from a parent spec; override only its delegation
+			leadNode = &yaml.Node{Kind: yaml.MappingNode}
+			setMappingValue(rolesNode, lead, leadNode)
+		}
+		setMappingValue(leadNode, "can_delegate", delegates)
+	}
+
+	setMappingValue(rolesNode, roleID, roleNode)
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, fmt.Errorf("encode spec: %w", err)
+	}
+	enc.Close()
+
+	// Write next to the original so relative extends/includes still resolve,
+	// and only replace the spec once the result loads cleanly
+	tmp, err := os.CreateTemp(filepath.Dir(specPath), ".specialist-*.yaml")
+	if err != nil {
+		return nil, fmt.Errorf("create temp spec: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		tmp.Close()
+		return nil, fmt.Errorf("write spec: %w", err)
+	}
+	tmp.Close()
+
+	merged, err := LoadSpec(tmpPath)
+	if err != nil {
+		return nil, fmt.Errorf("validate merged spec: %w", err)
+	}
+	if _, ok := merged.Roles[roleID]; !ok {
+		return nil, fmt.Errorf("validate merged spec: role %s missing after merge", roleID)
+	}
+
+	if err := os.Rename(tmpPath, specPath); err != nil {
+		return nil, fmt.Errorf("save spec: %w", err)
+	}
+
+	return &SpecialistResult{
+		RoleID: roleID,
+		Title:  role.Title,
+		Lead:   lead,
+	}, nil
+}
+
+// loadSpecialistRole reads a specialist file and returns its role ID and definition
+func loadSpecialistRole(path, name string) (string, *yaml.Node, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil, fmt.Errorf("specialist not found: %s", name)
+		}
+		return "", nil, fmt.Errorf("read specialist: %w", err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return "", nil, fmt.Errorf("parse specialist %s: %w", name, err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return "", nil, fmt.Errorf("parse specialist %s: expected a mapping", name)
+	}
+	root := doc.Content[0]
+
+	roles := mappingValue(root, "roles")
+	if roles == nil {
+		return name, root, nil
+	}
+	if roles.Kind != yaml.MappingNode || len(roles.Content) != 2 {
+		return "", nil, fmt.Errorf("specialist %s must define exactly one role", name)
+	}
+	return roles.Content[0].Value, roles.Content[1], nil
+}
+
+// specLead returns the role that coordinates the team, or "" if there is none
+func specLead(spec *TeamSpec) string {
+	for _, id := range spec.ClientFacing {
+		if _, ok := spec.Roles[id]; ok {
+			return id
+		}
+	}
+
+	ids := make([]string, 0, len(spec.Roles))
+	for id, role := range spec.Roles {
+		if role.Visibility == "client" {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return ""
+	}
+	sort.Strings(ids)
+	return ids[0]
+}
+
+// mappingValue returns the value node for key in a YAML mapping, or nil
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// setMappingValue replaces or appends key in a YAML mapping
+func setMappingValue(node *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			node.Content[i+1] = value
+			return
+		}
+	}
+	node.Content = append(node.Content, scalarNode(key), value)
+}
+
+func scalarNode(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
@@ -0,0 +1,114 @@
+package team
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAddSpecialist(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("UGUDU_HOME", home)
+
+	specsDir := filepath.Join(home, "specs")
+	specialistsDir := filepath.Join(specsDir, "specialists")
+	if err := os.MkdirAll(specialistsDir, 0755); err != nil {
+		t.Fatalf("Failed to create dirs: %v", err)
+	}
+
+	specPath := filepath.Join(specsDir, "my-team.yaml")
+	specContent := `apiVersion: ugudu/v1
+kind: Team
+metadata:
+  name: my-team
+client_facing:
+  - pm
+roles:
+  # The lead
+  pm:
+    title: Product Manager
+    visibility: client
+    model:
+      provider: anthropic
+      model: claude-sonnet-4-20250514
+    persona: You are the PM.
+    can_delegate:
+      - engineer
+  engineer:
+    title: Engineer
+    model:
+      provider: anthropic
+      model: claude-sonnet-4-20250514
+    persona: You are an engineer.
+`
+	if err := os.WriteFile(specPath, []byte(specContent), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+
+	specialist := `title: DevOps Engineer
+persona: |
+  You are the DevOps engineer.
+skills:
+  - kubernetes
+`
+	if err := os.WriteFile(filepath.Join(specialistsDir, "devops.yaml"), []byte(specialist), 0644); err != nil {
+		t.Fatalf("Failed to write specialist: %v", err)
+	}
+
+	result, err := AddSpecialist(specPath, "devops")
+	if err != nil {
+		t.Fatalf("AddSpecialist failed: %v", err)
+	}
+	if result.RoleID != "devops" || result.Lead != "pm" {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+
+	spec, err := LoadSpec(specPath)
+	if err != nil {
+		t.Fatalf("LoadSpec after merge failed: %v", err)
+	}
+
+	devops, ok := spec.Roles["devops"]
+	if !ok {
+		t.Fatal("Expected 'devops' role in spec")
+	}
+	if devops.Title != "DevOps Engineer" {
+		t.Errorf("Expected title 'DevOps Engineer', got '%s'", devops.Title)
+	}
+	if devops.ReportsTo != "pm" {
+		t.Errorf("Expected reports_to 'pm', got '%s'", devops.ReportsTo)
+	}
+	if devops.Model.Provider != "anthropic" {
+		t.Errorf("Expected model inherited from lead, got '%s'", devops.Model.Provider)
+	}
+
+	pm := spec.Roles["pm"]
+	if strings.Join(pm.CanDelegate, ",") != "engineer,devops" {
+		t.Errorf("Expected pm can_delegate [engineer devops], got %v", pm.CanDelegate)
+	}
+
+	// Comments in the original spec survive the rewrite
+	data, _ := os.ReadFile(specPath)
+	if !strings.Contains(string(data), "# The lead") {
+		t.Error("Expected spec comments to be preserved")
+	}
+
+	// Adding the same specialist again is rejected
+	if _, err := AddSpecialist(specPath, "devops"); err == nil {
+		t.Error("Expected error adding duplicate specialist")
+	}
+
+	if _, err := AddSpecialist(specPath, "missing"); err == nil {
+		t.Error("Expected error for unknown specialist")
+	}
+
+	// Names can't reach outside the specialists directory
+	outside := filepath.Join(home, "outside.yaml")
+	os.WriteFile(outside, []byte(specialist), 0644)
+	for _, name := range []string{"../../outside", "../outside", "..", ".hidden", "sub/devops", `sub\devops`, outside} {
+		if _, err := AddSpecialist(specPath, name); err == nil || !strings.Contains(err.Error(), "invalid specialist name") {
+			t.Errorf("Expected %q rejected as a specialist name, got %v", name, err)
+		}
+	}
+}
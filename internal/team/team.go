@@ -2,17 +2,19 @@ package team
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"os"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
+	"unicode/utf8"
 
 	"github.com/arcslash/ugudu/internal/logger"
 	"github.com/arcslash/ugudu/internal/provider"
 	"github.com/arcslash/ugudu/internal/tools"
 	"github.com/arcslash/ugudu/internal/workspace"
 	"github.com/google/uuid"
-	"gopkg.in/yaml.v3"
 )
 
 // PersistenceCallbacks allows teams to persist state without direct store dependency
@@ -25,8 +27,22 @@ type PersistenceCallbacks struct {
 	CreateConversation func(teamName string) (string, error)
 	// GetActiveConversation returns the active conversation ID
 	GetActiveConversation func(teamName string) (string, error)
-	// OnActivity is called when there's team activity (delegation, task updates, etc.)
-	OnActivity func(teamName, memberID, activityType, message string)
+	// OnActivity is called when there's team activity (delegation, task updates, etc.).
+	// data carries extra fields for some activity types, e.g. a progress
+	// report's percent, and is nil otherwise.
+	OnActivity func(teamName, memberID, activityType, message, requestID string, data map[string]interface{})
+	// SaveClientMember records the member a conversation was handed off to
+	SaveClientMember func(teamName, conversationID, memberID string) error
+	// LoadClientMember returns the member a conversation was handed off to, if any
+	LoadClientMember func(teamName, conversationID string) (string, error)
+	// SaveMessage records a client-visible message in the conversation transcript
+	SaveMessage func(teamName, conversationID string, msg Message) error
+	// SaveApproval records a tool call awaiting approval, and its decision
+	SaveApproval func(teamName string, approval Approval) error
+	// Audit appends to the audit log, e.g. a member's tool call
+	Audit func(teamName, memberID, action, requestID string, details map[string]interface{})
+	// RecordCall records the timing and outcome of a member's model call
+	RecordCall func(teamName string, call ProviderCall)
 }
 
 // ContextMessage represents a message in conversation context
@@ -36,6 +52,12 @@ type ContextMessage struct {
 }
 
 // Team represents a group of AI agents working together
+//
+// Locking: mu guards Members, MembersByRole, workspace, tokenMode and the
+// run context. Outside NewTeam (before the team is shared), never touch the
+// member maps directly; use GetMember, GetMemberByRole, MembersWithRole or
+// ListMembers, and iterate over the snapshot they return rather than holding
+// mu while calling into members, since members call back into the team.
 type Team struct {
 	Name          string
 	Spec          *TeamSpec
@@ -52,16 +74,82 @@ type Team struct {
 
 	// Tool execution
 	workspace    *workspace.Workspace
-	toolRegistry *tools.Registry // Base tool registry
+	toolRegistry *tools.Registry        // Base tool registry
+	container    *tools.ContainerConfig // Runs members' commands in containers when set
+
+	mcpServers map[string]tools.MCPServerConfig // Daemon-level MCP servers
+	mcpClients map[string]*tools.MCPClient      // Non-nil while running; guarded by mu
 
 	// Token management
 	tokenMode TokenMode // Current token consumption mode
 
-	ctx    context.Context
-	cancel context.CancelFunc
-	logger *logger.Logger
-	mu     sync.RWMutex
-	taskMu sync.RWMutex
+	limits Limits
+
+	// Asks whose callers timed out, by continuation token
+	suspended map[string]*suspendedAsk
+	suspendMu sync.Mutex
+
+	// Channels of the asks waiting on replies, by request ID, so each ask
+	// only relays its own request's messages
+	asks  map[string]chan Message
+	askMu sync.Mutex
+
+	// Member taking the client's requests, by conversation ID, once one has
+	// been handed the conversation
+	clientMembers map[string]string
+	handoffMu     sync.Mutex
+
+	// Requests currently asking for full verbosity; internal work is only
+	// shared while at least one is active
+	verboseAsks atomic.Int32
+
+	// Requests that want progress updates; none are sent without one
+	progressAsks atomic.Int32
+
+	// Set while the team is paused and rejecting new asks
+	paused atomic.Bool
+
+	// Set while the team is stopped for being idle, until it's started again
+	autoStopped atomic.Bool
+
+	// Messages lost to full channels
+	dropped droppedMessages
+
+	// Questions members are blocked on until the client answers, by ID
+	questions       map[string]*pendingQuestion
+	questionMu      sync.Mutex
+	questionTimeout time.Duration
+
+	// Tool calls members are blocked on until a human approves them, by ID
+	approvals  map[string]*pendingApproval
+	approvalMu sync.Mutex
+
+	// Runs projects; created on first use. Guarded by mu.
+	orchestrator *Orchestrator
+
+	// Clock members read the time from, and the timezone they work in
+	now             func() time.Time
+	location        *time.Location
+	defaultTimezone string
+
+	// Background every member's context starts with
+	seed string
+
+	// The spec as written, before environment variables and secret
+	// references were expanded; nil if the team wasn't given one
+	rawSpec *TeamSpec
+
+	// Models each provider lists, by provider ID, for their max_tokens
+	// limits; filled as members first call them
+	modelLimits  map[string][]provider.ModelInfo
+	modelLimitMu sync.Mutex
+
+	ctx        context.Context
+	cancel     context.CancelFunc
+	routerDone chan struct{} // Closed when the internal router returns
+	logger     *logger.Logger
+	mu         sync.RWMutex
+	taskMu     sync.RWMutex
 }
 
 // SetTokenMode sets the token consumption mode for the team
@@ -86,55 +174,77 @@ func (t *Team) GetTokenMode() TokenMode {
 func (t *Team) GetTokenSettings() TokenSettings {
 	mode := t.GetTokenMode()
 
-	// If spec has explicit settings, use those
+	// If spec has explicit settings, use those, with the mode's defaults
+	// for any it leaves out
 	if t.Spec != nil && t.Spec.Settings.Token.Mode != "" {
-		return t.Spec.Settings.Token
+		settings := t.Spec.Settings.Token
+		defaults := defaultTokenSettings(settings.Mode)
+		if settings.MaxTokens == 0 {
+			settings.MaxTokens = defaults.MaxTokens
+		}
+		if settings.ContextHistory == 0 {
+			settings.ContextHistory = defaults.ContextHistory
+		}
+		if settings.ContextBytes == 0 {
+			settings.ContextBytes = defaults.ContextBytes
+		}
+		return settings
 	}
 
-	// Default settings based on mode
+	return defaultTokenSettings(mode)
+}
+
+// defaultTokenSettings returns the settings a token mode uses by default
+func defaultTokenSettings(mode TokenMode) TokenSettings {
 	switch mode {
 	case TokenModeLow:
 		return TokenSettings{
 			Mode:           TokenModeLow,
 			MaxTokens:      1024,
 			ContextHistory: 10,
+			ContextBytes:   64 * 1024,
 		}
 	case TokenModeMinimal:
 		return TokenSettings{
 			Mode:           TokenModeMinimal,
 			MaxTokens:      512,
 			ContextHistory: 5,
+			ContextBytes:   16 * 1024,
 		}
 	default:
 		return TokenSettings{
 			Mode:           TokenModeNormal,
 			MaxTokens:      4096,
 			ContextHistory: 40,
+			ContextBytes:   256 * 1024,
 		}
 	}
 }
 
-// LoadSpec loads a team specification from YAML file
+// LoadSpec loads a team specification from YAML file, resolving any extends/includes
 func LoadSpec(path string) (*TeamSpec, error) {
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return nil, fmt.Errorf("read spec file: %w", err)
-	}
+	return loadSpec(path, true)
+}
 
-	// Expand environment variables
-	expanded := os.ExpandEnv(string(data))
+// LoadRawSpec loads a team specification like LoadSpec but leaves environment
+// variables and secret references unexpanded, for writing the spec back out
+// without the values they stand for
+func LoadRawSpec(path string) (*TeamSpec, error) {
+	return loadSpec(path, false)
+}
 
-	var spec TeamSpec
-	if err := yaml.Unmarshal([]byte(expanded), &spec); err != nil {
-		return nil, fmt.Errorf("parse spec: %w", err)
+func loadSpec(path string, expand bool) (*TeamSpec, error) {
+	spec, err := resolveSpec(path, nil, expand)
+	if err != nil {
+		return nil, err
 	}
 
 	// Set defaults
 	if spec.APIVersion == "" {
-		spec.APIVersion = "ugudu/v1"
+		spec.APIVersion = APIVersionV1
 	}
 	if spec.Kind == "" {
-		spec.Kind = "Team"
+		spec.Kind = KindTeam
 	}
 
 	// Default count to 1 for each role
@@ -148,16 +258,16 @@ func LoadSpec(path string) (*TeamSpec, error) {
 		spec.Roles[name] = role
 	}
 
-	return &spec, nil
+	return spec, nil
 }
 
 // NewTeam creates a new team from a specification
-func NewTeam(spec *TeamSpec, providers *provider.Registry, log *logger.Logger) (*Team, error) {
-	return NewTeamWithPersistence(spec, providers, log, nil)
+func NewTeam(spec *TeamSpec, providers *provider.Registry, log *logger.Logger, opts ...TeamOption) (*Team, error) {
+	return NewTeamWithPersistence(spec, providers, log, nil, opts...)
 }
 
 // NewTeamWithPersistence creates a new team with persistence callbacks
-func NewTeamWithPersistence(spec *TeamSpec, providers *provider.Registry, log *logger.Logger, persistence *PersistenceCallbacks) (*Team, error) {
+func NewTeamWithPersistence(spec *TeamSpec, providers *provider.Registry, log *logger.Logger, persistence *PersistenceCallbacks, opts ...TeamOption) (*Team, error) {
 	// Create base tool registry
 	baseRegistry := tools.NewRegistry()
 
@@ -169,26 +279,75 @@ func NewTeamWithPersistence(spec *TeamSpec, providers *provider.Registry, log *l
 		ClientFacing:  spec.ClientFacing,
 		providers:     providers,
 		tasks:         make(map[string]*Task),
-		clientChan:    make(chan Message, 100),
-		internalChan:  make(chan Message, 1000),
 		persistence:   persistence,
 		toolRegistry:  baseRegistry,
+		limits:        Limits{}.withDefaults(),
+		now:           time.Now,
 		logger:        log.With("team", spec.Metadata.Name),
 	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	t.clientChan = make(chan Message, t.limits.ClientChanSize)
+	t.internalChan = make(chan Message, t.limits.InternalChanSize)
+
+	// Lets any member stop and ask the client, not only client-facing ones
+	baseRegistry.Register(&tools.AskClientTool{AskFunc: t.askClient})
+	baseRegistry.Register(&tools.ReportProgressTool{ReportFunc: t.reportProgress})
+	baseRegistry.Register(&tools.CurrentTimeTool{Now: t.Now})
+
+	if err := t.setupContainer(); err != nil {
+		return nil, err
+	}
+	if err := validateBlockSettings(spec.Settings); err != nil {
+		return nil, err
+	}
+	if err := validateWorkflow(spec); err != nil {
+		return nil, err
+	}
+	if err := validateEnv(spec); err != nil {
+		return nil, err
+	}
+	if err := validateModeModels(spec); err != nil {
+		return nil, err
+	}
+	if err := t.setupClock(); err != nil {
+		return nil, err
+	}
+
+	// Every member runs its own goroutine with buffered channels, so refuse
+	// specs that would spawn an unreasonable number of them
+	if total := specMemberCount(spec); total > t.limits.MaxMembers {
+		return nil, fmt.Errorf("%w: spec has %d members, limit is %d (lower role counts or raise daemon.max_members_per_team)",
+			ErrTooManyMembers, total, t.limits.MaxMembers)
+	}
+
+	if editor := spec.Settings.Editor; editor.Enabled && editor.Model.Provider != "" {
+		if _, err := providers.Get(editor.Model.Provider); err != nil {
+			return nil, fmt.Errorf("settings.editor: %w", err)
+		}
+	}
 
 	// Create members for each role
 	for roleName, role := range spec.Roles {
 		// Get provider for this role
 		prov, err := providers.Get(role.Model.Provider)
 		if err != nil {
-			return nil, fmt.Errorf("provider %s not found for role %s: %w", role.Model.Provider, roleName, err)
+			return nil, fmt.Errorf("role %s: %w", roleName, err)
 		}
+		// Listing models can take a network round trip, and some providers
+		// only know a fixed subset, so an unlisted model is only warned about
+		go func(prov provider.Provider, roleName string, cfg ModelConfig) {
+			if err := checkModeModels(context.Background(), prov, roleName, cfg); err != nil {
+				t.logger.Warn("model may not be available", "error", err)
+			}
+		}(prov, roleName, role.Model)
 
 		// Create the specified number of members for this role
 		for i := 0; i < role.Count; i++ {
-			memberID := fmt.Sprintf("%s-%s", roleName, uuid.New().String()[:8])
-			if role.Count == 1 {
-				memberID = roleName // Simpler ID for single-instance roles
+			memberID := MemberID(roleName, i, role.Count)
+			if _, clash := spec.Roles[memberID]; clash && memberID != roleName {
+				return nil, fmt.Errorf("member %s of role %s has the same ID as role %s; rename one of the roles", memberID, roleName, memberID)
 			}
 
 			// Determine name for this member
@@ -205,7 +364,7 @@ func NewTeamWithPersistence(spec *TeamSpec, providers *provider.Registry, log *l
 			member := NewMember(memberID, memberName, roleName, role, t, prov, log)
 
 			// Create sandboxed tool registry for this member
-			sandboxedRegistry := tools.NewSandboxedRegistry(baseRegistry, t.workspace, roleName, memberID)
+			sandboxedRegistry := t.newToolRegistry(t.workspace, roleName, memberID)
 
 			// Set up activity logging
 			sandboxedRegistry.OnToolExecute = func(toolName string, args map[string]interface{}, result interface{}, err error) {
@@ -235,13 +394,62 @@ func NewTeamWithPersistence(spec *TeamSpec, providers *provider.Registry, log *l
 	return t, nil
 }
 
+// MemberID returns the ID of the i-th (0-based) member of a role with count
+// members: the role name itself for a single member, otherwise the role name
+// and a 1-based index, e.g. engineer-2. IDs depend only on the spec, so
+// they're the same after a restart and can be used to address one instance.
+func MemberID(roleName string, i, count int) string {
+	if count == 1 {
+		return roleName
+	}
+	return fmt.Sprintf("%s-%d", roleName, i+1)
+}
+
+// setupContainer checks the runtime named by the spec's sandbox setting.
+// Without it the team isn't created, unless the spec allows falling back to
+// running tools on the host.
+func (t *Team) setupContainer() error {
+	settings := t.Spec.Settings
+	if settings.Sandbox == "" || settings.Sandbox == "none" {
+		return nil
+	}
+
+	err := tools.CheckContainerRuntime(context.Background(), settings.Sandbox)
+	switch {
+	case err == nil:
+		t.container = &tools.ContainerConfig{Runtime: settings.Sandbox, Image: settings.SandboxImage}
+		return nil
+	case errors.Is(err, tools.ErrContainerRuntimeUnavailable) && settings.SandboxFallback:
+		t.logger.Warn("container runtime unavailable, running tools on the host", "sandbox", settings.Sandbox, "error", err)
+		return nil
+	case errors.Is(err, tools.ErrContainerRuntimeUnavailable):
+		return fmt.Errorf("sandbox %s: %w (install it, or set settings.sandbox_fallback: true to run tools on the host)", settings.Sandbox, err)
+	default:
+		return err
+	}
+}
+
+// newToolRegistry creates a member's tool registry, running its commands in
+// the team's container sandbox if it has one
+func (t *Team) newToolRegistry(ws *workspace.Workspace, role, memberID string) *tools.SandboxedRegistry {
+	registry := tools.NewSandboxedRegistry(t.toolRegistry, ws, role, memberID)
+	registry.AllowMCPServers(t.roleMCPServers(role))
+	registry.SetEnv(mergeEnv(t.Spec.Settings.Env, t.Spec.Roles[role].Env))
+	if t.container != nil {
+		registry.SetContainer(*t.container)
+	}
+	return registry
+}
+
 // SetWorkspace sets the workspace for this team (enables sandboxed tool execution)
 func (t *Team) SetWorkspace(ws *workspace.Workspace) {
+	t.mu.Lock()
 	t.workspace = ws
+	t.mu.Unlock()
 
 	// Update all member registries with the workspace
-	for _, member := range t.Members {
-		sandboxedRegistry := tools.NewSandboxedRegistry(t.toolRegistry, ws, member.RoleName, member.ID)
+	for _, member := range t.ListMembers() {
+		sandboxedRegistry := t.newToolRegistry(ws, member.RoleName, member.ID)
 		sandboxedRegistry.RegisterRoleTools()
 		member.SetToolRegistry(sandboxedRegistry)
 	}
@@ -249,7 +457,13 @@ func (t *Team) SetWorkspace(ws *workspace.Workspace) {
 
 // Start begins all team members
 func (t *Team) Start(ctx context.Context) error {
-	t.ctx, t.cancel = context.WithCancel(ctx)
+	t.autoStopped.Store(false)
+	runCtx, cancel := context.WithCancel(ctx)
+	t.mu.Lock()
+	t.ctx, t.cancel = runCtx, cancel
+	t.mu.Unlock()
+
+	members := t.ListMembers()
 
 	// Create or get active conversation for persistence
 	if t.persistence != nil && t.persistence.GetActiveConversation != nil {
@@ -266,10 +480,11 @@ func (t *Team) Start(ctx context.Context) error {
 		t.conversationID = convID
 		t.logger.Debug("conversation context", "id", convID)
 	}
+	t.restoreClientMember()
 
 	// Load persisted context for each member
 	if t.persistence != nil && t.persistence.LoadContext != nil {
-		for _, member := range t.Members {
+		for _, member := range members {
 			history, err := t.persistence.LoadContext(t.Name, member.ID, 50) // Load last 50 messages
 			if err != nil {
 				t.logger.Warn("failed to load member context", "member", member.ID, "error", err)
@@ -281,56 +496,101 @@ func (t *Team) Start(ctx context.Context) error {
 			}
 		}
 	}
+	for _, member := range members {
+		member.seedContext(t.seed)
+	}
+
+	t.connectMCPServers(runCtx)
 
 	// Start internal message router
-	go t.routeInternal()
+	routerDone := make(chan struct{})
+	t.mu.Lock()
+	t.routerDone = routerDone
+	t.mu.Unlock()
+	go func() {
+		defer close(routerDone)
+		t.routeInternal(runCtx)
+	}()
 
 	// Start all members
-	for _, member := range t.Members {
-		member.Start(t.ctx)
+	for _, member := range members {
+		member.Start(runCtx)
 	}
 
-	t.logger.Info("team started", "members", len(t.Members), "conversation", t.conversationID)
+	t.logger.Info("team started", "members", len(members), "conversation", t.conversationID)
 	return nil
 }
 
 // Stop halts all team members
 func (t *Team) Stop() {
-	if t.cancel != nil {
-		t.cancel()
+	t.autoStopped.Store(false)
+
+	t.mu.RLock()
+	cancel := t.cancel
+	t.mu.RUnlock()
+
+	if cancel != nil {
+		cancel()
 	}
 
-	for _, member := range t.Members {
+	for _, member := range t.ListMembers() {
 		member.Stop()
 	}
+	t.closeMCPServers()
 
 	t.logger.Info("team stopped")
 }
 
+// Wait blocks until a stopped team's goroutines have returned: the internal
+// router, and each member once it has given up the message it was handling.
+// It returns early with an error if ctx is done first.
+func (t *Team) Wait(ctx context.Context) error {
+	t.mu.RLock()
+	routerDone := t.routerDone
+	t.mu.RUnlock()
+	if routerDone != nil {
+		select {
+		case <-routerDone:
+		case <-ctx.Done():
+			return fmt.Errorf("team %s: internal router still running: %w", t.Name, ctx.Err())
+		}
+	}
+	for _, member := range t.ListMembers() {
+		if err := member.wait(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Ask sends a request to the team (goes to client-facing member)
-func (t *Team) Ask(content string) <-chan Message {
+func (t *Team) Ask(content string, opts ...AskOption) <-chan Message {
+	if t.Paused() {
+		return t.pausedResponse()
+	}
+	o := applyAskOptions(opts)
+	if o.requestID == "" {
+		o.requestID = uuid.New().String()
+	}
+	log := t.logger.With("request_id", o.requestID)
 	responseChan := make(chan Message, 10)
 
 	go func() {
 		defer close(responseChan)
 
-		// Find the primary client-facing member
-		var target *Member
-		if len(t.ClientFacing) > 0 {
-			// Use first client-facing role
-			if members, ok := t.MembersByRole[t.ClientFacing[0]]; ok && len(members) > 0 {
-				target = members[0]
-			}
+		if o.verbosity == VerbosityFull {
+			t.verboseAsks.Add(1)
+			defer t.verboseAsks.Add(-1)
 		}
-
-		if target == nil {
-			// Fallback: use first available member
-			for _, member := range t.Members {
-				target = member
-				break
-			}
+		if o.verbosity != VerbosityQuiet {
+			t.progressAsks.Add(1)
+			defer t.progressAsks.Add(-1)
 		}
 
+		// Find the client-facing member, or the one the conversation was
+		// handed off to
+		target := t.clientMember()
+
 		if target == nil {
 			responseChan <- Message{
 				Type:    MsgClientResponse,
@@ -341,78 +601,191 @@ func (t *Team) Ask(content string) <-chan Message {
 			return
 		}
 
+		replies := t.subscribeAsk(o.requestID)
+		defer t.unsubscribeAsk(o.requestID)
+
 		// Send request to target
-		target.Send(Message{
-			ID:      uuid.New().String(),
-			Type:    MsgClientRequest,
-			From:    "client",
-			To:      target.ID,
-			Content: content,
-		})
+		request := Message{
+			ID:        uuid.New().String(),
+			Type:      MsgClientRequest,
+			From:      "client",
+			To:        target.ID,
+			Content:   content,
+			RequestID: o.requestID,
+			NoTools:   o.noTools,
+			Timestamp: time.Now(),
+		}
+		t.recordClientMessage(request)
+		if err := target.Send(request); err != nil {
+			responseChan <- Message{
+				Type:    MsgClientResponse,
+				From:    "system",
+				To:      "client",
+				Content: fmt.Sprintf("%s is too busy to take the request: %v", target.DisplayName(), err),
+			}
+			return
+		}
+
+		t.relayResponses(replies, responseChan, target, o, log)
+	}()
+
+	return responseChan
+}
 
-		// Wait for responses - keep listening for all messages
-		// Use a timeout to detect when work is complete
-		timeout := time.After(10 * time.Minute)
-		lastActivity := time.Now()
-		idleTimeout := 30 * time.Second // Consider done if no activity for 30s
+// How long a request's responses are relayed: until the member asked has
+// answered and gone idle, checked every askDoneCheck, or else until nothing
+// has arrived for askIdleTimeout, or at most askTimeout
+const (
+	askDoneCheck   = 20 * time.Millisecond
+	askIdleTimeout = 30 * time.Second
+	askTimeout     = 10 * time.Minute
+)
+
+// subscribeAsk registers an ask's request ID, returning the channel its
+// replies are routed to
+func (t *Team) subscribeAsk(requestID string) chan Message {
+	replies := make(chan Message, t.limits.ClientChanSize)
+	t.askMu.Lock()
+	defer t.askMu.Unlock()
+	if t.asks == nil {
+		t.asks = make(map[string]chan Message)
+	}
+	t.asks[requestID] = replies
+	return replies
+}
+
+func (t *Team) unsubscribeAsk(requestID string) {
+	t.askMu.Lock()
+	defer t.askMu.Unlock()
+	delete(t.asks, requestID)
+}
 
-		for {
-			select {
-			case <-t.ctx.Done():
+// clientChanFor returns the channel a client message goes to: its ask's, or
+// the shared client channel for messages no ask is waiting on
+func (t *Team) clientChanFor(requestID string) chan Message {
+	if requestID != "" {
+		t.askMu.Lock()
+		defer t.askMu.Unlock()
+		if replies, ok := t.asks[requestID]; ok {
+			return replies
+		}
+	}
+	return t.clientChan
+}
+
+// relayResponses passes the client messages a request causes to
+// responseChan: any progress and internal work the request wants, and the
+// answer. A member that delegates first answers once the delegated work is
+// back, so the answer is only complete when the member is idle again.
+// Messages without a request ID arrive on the shared client channel and go
+// to whichever ask is listening.
+func (t *Team) relayResponses(replies <-chan Message, responseChan chan<- Message, target *Member, o askOptions, log *logger.Logger) {
+	timeout := time.After(askTimeout)
+	check := time.NewTicker(askDoneCheck)
+	defer check.Stop()
+	lastActivity := time.Now()
+	answered := false
+	answerer := target // Differs from target after a handoff
+	handle := func(msg Message) {
+		lastActivity = time.Now()
+		if msg.Type != MsgInternal && msg.Type != MsgProgress {
+			answered = true
+			if from := t.GetMember(msg.From); from != nil {
+				answerer = from
+			}
+		}
+		if !o.wants(msg.Type) {
+			return
+		}
+		responseChan <- msg
+		log.Debug("client message sent", "from", msg.From, "type", msg.Type)
+	}
+
+	for {
+		select {
+		case <-t.runContext().Done():
+			return
+		case msg := <-replies:
+			handle(msg)
+		case msg := <-t.clientChan:
+			handle(msg)
+		case <-check.C:
+			if answered && target.GetStatus() == MemberIdle && answerer.GetStatus() == MemberIdle {
+				log.Debug("response complete")
 				return
-			case msg := <-t.clientChan:
-				responseChan <- msg
-				lastActivity = time.Now()
-				t.logger.Debug("client message sent", "from", msg.From, "type", msg.Type)
-			case <-time.After(idleTimeout):
-				// Check if we've been idle long enough to consider done
-				if time.Since(lastActivity) >= idleTimeout {
-					t.logger.Debug("response complete - idle timeout")
-					return
-				}
-			case <-timeout:
-				t.logger.Warn("response timeout")
+			}
+			if time.Since(lastActivity) >= askIdleTimeout {
+				log.Debug("response complete - idle timeout")
 				return
 			}
+		case <-timeout:
+			log.Warn("response timeout")
+			return
 		}
-	}()
-
-	return responseChan
+	}
 }
 
-// AskMember sends a request to a specific member by role
-func (t *Team) AskMember(roleName, content string) <-chan Message {
+// AskMember sends a request to a specific member, addressed by role or by
+// member ID (e.g. engineer-2)
+func (t *Team) AskMember(to, content string, opts ...AskOption) <-chan Message {
+	if t.Paused() {
+		return t.pausedResponse()
+	}
+	o := applyAskOptions(opts)
+	if o.requestID == "" {
+		o.requestID = uuid.New().String()
+	}
+	log := t.logger.With("request_id", o.requestID)
 	responseChan := make(chan Message, 10)
 
 	go func() {
 		defer close(responseChan)
 
-		target := t.GetMemberByRole(roleName)
+		if o.verbosity == VerbosityFull {
+			t.verboseAsks.Add(1)
+			defer t.verboseAsks.Add(-1)
+		}
+		if o.verbosity != VerbosityQuiet {
+			t.progressAsks.Add(1)
+			defer t.progressAsks.Add(-1)
+		}
+
+		target := t.memberFor(to)
 		if target == nil {
 			responseChan <- Message{
 				Type:    MsgClientResponse,
 				From:    "system",
 				To:      "client",
-				Content: fmt.Sprintf("No member found with role: %s", roleName),
+				Content: fmt.Sprintf("No member found with role or ID: %s", to),
 			}
 			return
 		}
 
-		target.Send(Message{
-			ID:      uuid.New().String(),
-			Type:    MsgClientRequest,
-			From:    "client",
-			To:      target.ID,
-			Content: content,
-		})
-
-		// Wait for response
-		select {
-		case <-t.ctx.Done():
+		replies := t.subscribeAsk(o.requestID)
+		defer t.unsubscribeAsk(o.requestID)
+
+		request := Message{
+			ID:        uuid.New().String(),
+			Type:      MsgClientRequest,
+			From:      "client",
+			To:        target.ID,
+			Content:   content,
+			RequestID: o.requestID,
+			NoTools:   o.noTools,
+			Timestamp: time.Now(),
+		}
+		t.recordClientMessage(request)
+		if err := target.Send(request); err != nil {
+			responseChan <- Message{
+				Type:    MsgClientResponse,
+				From:    "system",
+				To:      "client",
+				Content: fmt.Sprintf("%s is too busy to take the request: %v", target.DisplayName(), err),
+			}
 			return
-		case msg := <-t.clientChan:
-			responseChan <- msg
 		}
+
+		t.relayResponses(replies, responseChan, target, o, log)
 	}()
 
 	return responseChan
@@ -436,6 +809,50 @@ func (t *Team) GetMemberByRole(roleName string) *Member {
 	return nil
 }
 
+// memberFor resolves who a message addressed to "to" goes to: a member of
+// that role, as GetMemberByRole picks, or else the member with that ID
+func (t *Team) memberFor(to string) *Member {
+	if m := t.GetMemberByRole(to); m != nil {
+		return m
+	}
+	return t.GetMember(to)
+}
+
+// MembersWithRole returns all members with the given role
+func (t *Team) MembersWithRole(roleName string) []*Member {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	members := make([]*Member, len(t.MembersByRole[roleName]))
+	copy(members, t.MembersByRole[roleName])
+	return members
+}
+
+// primaryMember returns the member that takes client requests: the first
+// member of the first client-facing role, or any member if there's none
+func (t *Team) primaryMember() *Member {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if len(t.ClientFacing) > 0 {
+		if members := t.MembersByRole[t.ClientFacing[0]]; len(members) > 0 {
+			return members[0]
+		}
+	}
+	for _, member := range t.Members {
+		return member
+	}
+	return nil
+}
+
+// runContext returns the context of the current run, which is replaced each
+// time the team is started
+func (t *Team) runContext() context.Context {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.ctx
+}
+
 // GetMember returns a member by ID
 func (t *Team) GetMember(id string) *Member {
 	t.mu.RLock()
@@ -443,14 +860,20 @@ func (t *Team) GetMember(id string) *Member {
 	return t.Members[id]
 }
 
-// ListMembers returns all members
+// ListMembers returns all members, ordered by role and then instance
 func (t *Team) ListMembers() []*Member {
 	t.mu.RLock()
 	defer t.mu.RUnlock()
 
+	roles := make([]string, 0, len(t.MembersByRole))
+	for role := range t.MembersByRole {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+
 	members := make([]*Member, 0, len(t.Members))
-	for _, m := range t.Members {
-		members = append(members, m)
+	for _, role := range roles {
+		members = append(members, t.MembersByRole[role]...)
 	}
 	return members
 }
@@ -481,33 +904,37 @@ func (t *Team) ListTasks() []*Task {
 	return tasks
 }
 
-// RouteMessage routes a message to the appropriate destination
-func (t *Team) RouteMessage(msg Message) {
+// RouteMessage routes a message to the appropriate destination. If the
+// channel is full the team's overflow policy applies; the error is
+// ErrChannelFull if the message was dropped and the policy reports drops.
+func (t *Team) RouteMessage(msg Message) error {
 	if msg.To == "client" {
-		select {
-		case t.clientChan <- msg:
-		default:
-			t.logger.Warn("client channel full, dropping message")
+		t.recordClientMessage(msg)
+		if !deliver(t.clientChanFor(msg.RequestID), msg, t.limits.ClientOverflow, t.limits.OverflowTimeout) {
+			t.dropped.client.Add(1)
+			t.logger.Warn("client channel full, dropping message", "type", msg.Type, "policy", t.limits.ClientOverflow)
+			return overflowError(t.limits.ClientOverflow, "client channel")
 		}
-		return
+		return nil
 	}
 
 	// Route to internal
-	select {
-	case t.internalChan <- msg:
-	default:
-		t.logger.Warn("internal channel full, dropping message")
+	if !deliver(t.internalChan, msg, t.limits.Overflow, t.limits.OverflowTimeout) {
+		t.dropped.internal.Add(1)
+		t.logger.Warn("internal channel full, dropping message", "type", msg.Type, "policy", t.limits.Overflow)
+		return overflowError(t.limits.Overflow, "internal channel")
 	}
+	return nil
 }
 
-func (t *Team) routeInternal() {
+func (t *Team) routeInternal(ctx context.Context) {
 	for {
 		select {
-		case <-t.ctx.Done():
+		case <-ctx.Done():
 			return
 		case msg := <-t.internalChan:
 			// Find target member
-			if member, ok := t.Members[msg.To]; ok {
+			if member := t.GetMember(msg.To); member != nil {
 				member.Send(msg)
 			} else {
 				t.logger.Warn("unknown message target", "to", msg.To)
@@ -532,11 +959,141 @@ func (t *Team) SaveMemberContext(memberID, role, content string, sequence int) {
 	}
 }
 
-// NotifyActivity broadcasts an activity event
-func (t *Team) NotifyActivity(memberID, activityType, message string) {
+// recordClientMessage persists a message to or from the client under the
+// current conversation. Internal work and progress updates are not part of
+// the transcript.
+func (t *Team) recordClientMessage(msg Message) {
+	if t.persistence == nil || t.persistence.SaveMessage == nil || msg.Type == MsgInternal || msg.Type == MsgProgress {
+		return
+	}
+
+	if err := t.persistence.SaveMessage(t.Name, t.conversationID, msg); err != nil {
+		t.logger.Warn("failed to save message", "id", msg.ID, "error", err)
+	}
+}
+
+// shareInternal sends intermediate work (delegation results, tool summaries)
+// to the client while a request with full verbosity is active
+func (t *Team) shareInternal(ctx context.Context, from, content string) {
+	if t.verboseAsks.Load() == 0 {
+		return
+	}
+	t.RouteMessage(Message{
+		ID:        uuid.New().String(),
+		Type:      MsgInternal,
+		From:      from,
+		To:        "client",
+		Content:   content,
+		RequestID: logger.RequestID(ctx),
+		Timestamp: time.Now(),
+	})
+}
+
+// shareProgress tells the client what the team is doing while a request waits
+// on delegated work. It is sent while any request wants progress updates.
+func (t *Team) shareProgress(ctx context.Context, from, content string) {
+	if t.progressAsks.Load() == 0 {
+		return
+	}
+	t.RouteMessage(Message{
+		ID:        uuid.New().String(),
+		Type:      MsgProgress,
+		From:      from,
+		To:        "client",
+		Content:   content,
+		RequestID: logger.RequestID(ctx),
+		Timestamp: time.Now(),
+	})
+}
+
+// NotifyActivity broadcasts an activity event, tagged with the request ID
+// carried by ctx
+func (t *Team) NotifyActivity(ctx context.Context, memberID, activityType, message string) {
+	t.notifyActivity(ctx, memberID, activityType, message, nil)
+}
+
+// notifyActivity is NotifyActivity with extra fields for the event
+func (t *Team) notifyActivity(ctx context.Context, memberID, activityType, message string, data map[string]interface{}) {
 	if t.persistence != nil && t.persistence.OnActivity != nil {
-		t.persistence.OnActivity(t.Name, memberID, activityType, message)
+		t.persistence.OnActivity(t.Name, memberID, activityType, message, logger.RequestID(ctx), data)
+	}
+}
+
+// Audit log actions recorded by the team; the manager records the rest
+const (
+	AuditToolExecute = "tool.execute"         // A member ran a tool
+	AuditToolDenied  = "tool.denied"          // A human denied a member's tool call
+	AuditHandoff     = "conversation.handoff" // A member handed the client to another
+)
+
+// auditArgMax caps each string argument kept in the audit log, so a
+// write_file call records which file was written rather than all of it
+const auditArgMax = 256
+
+// auditArgs returns a copy of a tool call's arguments for the audit log,
+// with long strings cut down to auditArgMax bytes and their full size
+func auditArgs(args map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(args))
+	for k, v := range args {
+		if s, ok := v.(string); ok && len(s) > auditArgMax {
+			cut := auditArgMax
+			for cut > 0 && !utf8.RuneStart(s[cut]) {
+				cut--
+			}
+			v = fmt.Sprintf("%s... [%d bytes]", s[:cut], len(s))
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// audit records an action in the audit log, tagged with the request ID
+// carried by ctx
+func (t *Team) audit(ctx context.Context, memberID, action string, details map[string]interface{}) {
+	if t.persistence != nil && t.persistence.Audit != nil {
+		t.persistence.Audit(t.Name, memberID, action, logger.RequestID(ctx), details)
+	}
+}
+
+// recordCall records a member's model call that started at start
+func (t *Team) recordCall(ctx context.Context, memberID string, p provider.Provider, req *provider.ChatRequest, resp *provider.ChatResponse, err error, start time.Time) {
+	if t.persistence == nil || t.persistence.RecordCall == nil {
+		return
+	}
+	call := ProviderCall{
+		MemberID:  memberID,
+		Provider:  p.ID(),
+		Model:     req.Model,
+		StartedAt: start,
+		Duration:  time.Since(start),
+		Outcome:   callOutcome(err),
+		RequestID: logger.RequestID(ctx),
+	}
+	if resp != nil {
+		if resp.Model != "" {
+			call.Model = resp.Model
+		}
+		call.PromptTokens = resp.Usage.PromptTokens
+		call.CompletionTokens = resp.Usage.CompletionTokens
+	}
+	t.persistence.RecordCall(t.Name, call)
+}
+
+// callOutcome classifies a model call's error
+func callOutcome(err error) string {
+	if err == nil {
+		return CallOK
+	}
+	if _, ok := provider.IsRateLimitError(err); ok {
+		return CallRateLimited
+	}
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return CallTimeout
+	case errors.Is(err, context.Canceled):
+		return CallCanceled
 	}
+	return CallError
 }
 
 // GetConversationID returns the current conversation ID
@@ -547,7 +1104,8 @@ func (t *Team) GetConversationID() string {
 // Status returns the team's current status
 func (t *Team) Status() map[string]interface{} {
 	members := make([]map[string]interface{}, 0)
-	for _, m := range t.ListMembers() {
+	memberList := t.ListMembers()
+	for _, m := range memberList {
 		members = append(members, map[string]interface{}{
 			"id":           m.ID,
 			"name":         m.Name,
@@ -556,6 +1114,7 @@ func (t *Team) Status() map[string]interface{} {
 			"title":        m.Role.Title,
 			"status":       m.GetStatus(),
 			"task":         m.GetCurrentTask(),
+			"progress":     m.Progress(),
 		})
 	}
 
@@ -577,14 +1136,23 @@ func (t *Team) Status() map[string]interface{} {
 	return map[string]interface{}{
 		"name":         t.Name,
 		"description":  t.Spec.Metadata.Description,
+		"status":       t.State(),
+		"paused":       t.Paused(),
+		"token_mode":   t.GetTokenMode(),
 		"members":      members,
-		"member_count": len(t.Members),
+		"member_count": len(memberList),
+		"max_members":  t.limits.withDefaults().MaxMembers,
+		// Preallocated message buffer slots, not counting message contents
+		"channel_buffer_bytes": t.channelBufferBytes(len(memberList)),
+		"dropped_messages":     t.DroppedMessages(),
 		"tasks": map[string]int{
 			"pending":     pending,
 			"in_progress": inProgress,
 			"completed":   completed,
 			"total":       len(tasks),
 		},
-		"client_facing": t.ClientFacing,
+		"client_facing":     t.ClientFacing,
+		"client_member":     t.ClientMemberID(), // Takes the client's requests, after any handoff
+		"pending_questions": t.PendingQuestions(),
 	}
 }
@@ -0,0 +1,345 @@
+package team
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/arcslash/ugudu/internal/logger"
+	"github.com/arcslash/ugudu/internal/provider"
+)
+
+func memberIDs(tm *Team) []string {
+	var ids []string
+	for _, m := range tm.ListMembers() {
+		ids = append(ids, m.ID)
+	}
+	return ids
+}
+
+func TestNewTeam_MemberIDs(t *testing.T) {
+	registry := provider.NewRegistry()
+	registry.Register(&MockProvider{})
+	log := logger.New("error")
+
+	want := []string{"engineer-1", "engineer-2", "engineer-3", "pm"}
+	for i := 0; i < 2; i++ {
+		tm, err := NewTeam(limitsSpec(3), registry, log)
+		if err != nil {
+			t.Fatalf("NewTeam failed: %v", err)
+		}
+		if got := memberIDs(tm); !reflect.DeepEqual(got, want) {
+			t.Errorf("Attempt %d: expected IDs %v, got %v", i+1, want, got)
+		}
+	}
+
+	// A generated ID can't shadow another role
+	spec := limitsSpec(2)
+	spec.Roles["engineer-2"] = Role{Title: "Impostor", Count: 1, Model: ModelConfig{Provider: "mock"}}
+	if _, err := NewTeam(spec, registry, log); err == nil || !strings.Contains(err.Error(), "same ID") {
+		t.Errorf("Expected a clashing ID error, got %v", err)
+	}
+}
+
+func TestNewTeam_ProviderNotFound(t *testing.T) {
+	registry := provider.NewRegistry()
+	registry.Register(&MockProvider{})
+
+	tests := []struct {
+		provider string
+		want     []string
+	}{
+		{"groq", []string{"role engineer", "provider not found: groq", "configured: mock", "GROQ_API_KEY", "providers.groq.api_key"}},
+		{"acme", []string{"provider not found: acme", "configured: mock", "supported providers are anthropic, groq, ollama, openai, openrouter"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.provider, func(t *testing.T) {
+			spec := limitsSpec(1)
+			engineer := spec.Roles["engineer"]
+			engineer.Model.Provider = tt.provider
+			spec.Roles["engineer"] = engineer
+
+			_, err := NewTeam(spec, registry, logger.New("error"))
+			if !errors.Is(err, provider.ErrProviderNotFound) {
+				t.Fatalf("Expected ErrProviderNotFound, got %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Expected %q in %q", want, err.Error())
+				}
+			}
+		})
+	}
+}
+
+// tieredProvider offers a model per price tier
+type tieredProvider struct{ MockProvider }
+
+func (p *tieredProvider) ListModels(_ context.Context) ([]provider.ModelInfo, error) {
+	return []provider.ModelInfo{{ID: "opus"}, {ID: "sonnet"}, {ID: "haiku"}}, nil
+}
+
+func TestMember_ModelPerTokenMode(t *testing.T) {
+	registry := provider.NewRegistry()
+	registry.Register(&tieredProvider{})
+
+	spec := limitsSpec(1)
+	pm := spec.Roles["pm"]
+	pm.Model = ModelConfig{Provider: "mock", Model: "opus", Models: map[TokenMode]string{
+		TokenModeNormal: "opus", TokenModeLow: "sonnet", TokenModeMinimal: "haiku",
+	}}
+	spec.Roles["pm"] = pm
+	engineer := spec.Roles["engineer"]
+	engineer.Model = ModelConfig{Provider: "mock", Model: "opus", LowTokenModel: "haiku"}
+	spec.Roles["engineer"] = engineer
+
+	tm, err := NewTeam(spec, registry, logger.New("error"))
+	if err != nil {
+		t.Fatalf("NewTeam failed: %v", err)
+	}
+
+	tests := []struct {
+		mode     TokenMode
+		pm       string
+		engineer string // low_token_model covers both low and minimal
+	}{
+		{TokenModeNormal, "opus", "opus"},
+		{TokenModeLow, "sonnet", "haiku"},
+		{TokenModeMinimal, "haiku", "haiku"},
+	}
+	for _, tt := range tests {
+		tm.SetTokenMode(tt.mode)
+		if got := tm.GetMember("pm").getEffectiveModel(); got != tt.pm {
+			t.Errorf("%s mode: expected pm on %s, got %s", tt.mode, tt.pm, got)
+		}
+		if got := tm.GetMember("engineer").getEffectiveModel(); got != tt.engineer {
+			t.Errorf("%s mode: expected engineer on %s, got %s", tt.mode, tt.engineer, got)
+		}
+	}
+
+	// A model the provider doesn't list is warned about, not refused, since
+	// the list may be fixed and out of date
+	pm.Model.Models[TokenModeLow] = "sonnet-9"
+	spec.Roles["pm"] = pm
+	var logs lockedBuffer
+	if _, err := NewTeam(spec, registry, logger.New("warn", &logs)); err != nil {
+		t.Fatalf("Expected an unlisted model to be allowed, got %v", err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for !strings.Contains(logs.String(), `model.models.low is "sonnet-9"`) {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected a warning about the unlisted model, got logs: %s", logs.String())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestTeam_AskMemberByID(t *testing.T) {
+	mockProv := &MockProvider{ChatFunc: func(req *provider.ChatRequest) (*provider.ChatResponse, error) {
+		return &provider.ChatResponse{Content: "On it."}, nil
+	}}
+	registry := provider.NewRegistry()
+	registry.Register(mockProv)
+
+	tm, err := NewTeam(limitsSpec(3), registry, logger.New("error"))
+	if err != nil {
+		t.Fatalf("NewTeam failed: %v", err)
+	}
+	if err := tm.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	t.Cleanup(tm.Stop)
+
+	tests := []struct {
+		to   string
+		want string // Member expected to answer; empty means any engineer
+	}{
+		{to: "engineer-2", want: "engineer-2"},
+		{to: "engineer-3", want: "engineer-3"},
+		{to: "engineer"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.to, func(t *testing.T) {
+			var from []string
+			timeout := time.After(5 * time.Second)
+			responses := tm.AskMember(tt.to, "Status?")
+		collect:
+			for {
+				select {
+				case msg, ok := <-responses:
+					if !ok {
+						break collect
+					}
+					from = append(from, msg.From)
+				case <-timeout:
+					t.Fatal("No response")
+				}
+			}
+
+			if len(from) == 0 {
+				t.Fatal("Expected a response")
+			}
+			for _, f := range from {
+				if tt.want != "" && f != tt.want {
+					t.Errorf("Expected a reply from %s, got one from %s", tt.want, f)
+				}
+				if tt.want == "" && !strings.HasPrefix(f, "engineer-") {
+					t.Errorf("Expected a reply from an engineer, got one from %s", f)
+				}
+			}
+		})
+	}
+}
+
+func TestTeam_EditorPass(t *testing.T) {
+	mockProv := &MockProvider{ChatFunc: func(req *provider.ChatRequest) (*provider.ChatResponse, error) {
+		if req.Messages[0].Content == defaultEditorInstructions {
+			return &provider.ChatResponse{Content: "Polished: " + req.Messages[1].Content}, nil
+		}
+		return &provider.ChatResponse{Content: "grep shows 3 hits in pkg/auth; engineer-2 owns the fix"}, nil
+	}}
+	registry := provider.NewRegistry()
+	registry.Register(mockProv)
+
+	tests := []struct {
+		name    string
+		enabled bool
+		mode    TokenMode
+		edited  bool
+	}{
+		{"enabled", true, TokenModeNormal, true},
+		{"disabled", false, TokenModeNormal, false},
+		{"minimal mode", true, TokenModeMinimal, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := limitsSpec(1)
+			spec.Settings.Editor = EditorSettings{Enabled: tt.enabled}
+			tm, err := NewTeam(spec, registry, logger.New("error"))
+			if err != nil {
+				t.Fatalf("NewTeam failed: %v", err)
+			}
+			tm.SetTokenMode(tt.mode)
+			if err := tm.Start(context.Background()); err != nil {
+				t.Fatalf("Start failed: %v", err)
+			}
+			defer tm.Stop()
+
+			var reply string
+			select {
+			case msg := <-tm.Ask("Where are we on the login bug?"):
+				reply, _ = msg.Content.(string)
+			case <-time.After(5 * time.Second):
+				t.Fatal("No response")
+			}
+
+			if edited := strings.HasPrefix(reply, "Polished: "); edited != tt.edited {
+				t.Errorf("Expected edited=%v, got %q", tt.edited, reply)
+			}
+			if !strings.Contains(reply, "grep shows 3 hits") {
+				t.Errorf("Expected the member's reply carried through, got %q", reply)
+			}
+		})
+	}
+}
+
+func TestTeam_EditorSkipsStatusLines(t *testing.T) {
+	var edits atomic.Int32
+	mockProv := &MockProvider{ChatFunc: func(req *provider.ChatRequest) (*provider.ChatResponse, error) {
+		if req.Messages[0].Content == defaultEditorInstructions {
+			edits.Add(1)
+			return &provider.ChatResponse{Content: "Polished: " + req.Messages[1].Content}, nil
+		}
+		return &provider.ChatResponse{Content: "DELEGATE PARALLEL:\n- designer: Mock up the login page"}, nil
+	}}
+	registry := provider.NewRegistry()
+	registry.Register(mockProv)
+
+	spec := limitsSpec(1)
+	spec.Settings.Editor = EditorSettings{Enabled: true}
+	tm, err := NewTeam(spec, registry, logger.New("error"))
+	if err != nil {
+		t.Fatalf("NewTeam failed: %v", err)
+	}
+	if err := tm.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer tm.Stop()
+
+	select {
+	case msg := <-tm.Ask("Get the login page designed"):
+		if msg.Content != "No valid delegation targets found" {
+			t.Errorf("Expected the status line as written, got %q", msg.Content)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("No response")
+	}
+	if n := edits.Load(); n != 0 {
+		t.Errorf("Expected no editor calls for a status line, got %d", n)
+	}
+}
+
+func TestTeam_SeededContext(t *testing.T) {
+	var first *provider.ChatRequest
+	mockProv := &MockProvider{ChatFunc: func(req *provider.ChatRequest) (*provider.ChatResponse, error) {
+		if first == nil {
+			first = req
+		}
+		return &provider.ChatResponse{Content: "Will do."}, nil
+	}}
+	registry := provider.NewRegistry()
+	registry.Register(mockProv)
+
+	seed := "We chose Postgres over MySQL last quarter."
+	tm, err := NewTeam(limitsSpec(1), registry, logger.New("error"), WithSeed(seed))
+	if err != nil {
+		t.Fatalf("NewTeam failed: %v", err)
+	}
+	if err := tm.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	t.Cleanup(tm.Stop)
+
+	select {
+	case <-tm.AskMember("pm", "Which database should we use?"):
+	case <-time.After(5 * time.Second):
+		t.Fatal("No response")
+	}
+
+	// The seed comes right after the system prompt, before the request
+	if len(first.Messages) < 4 {
+		t.Fatalf("Expected the seeded exchange before the request, got %+v", first.Messages)
+	}
+	if first.Messages[0].Role != "system" || first.Messages[1].Role != "user" || !strings.Contains(first.Messages[1].Content, seed) {
+		t.Errorf("Expected the seed after the system prompt, got %+v", first.Messages[:2])
+	}
+	if first.Messages[2].Role != "assistant" || first.Messages[len(first.Messages)-1].Content != "Which database should we use?" {
+		t.Errorf("Expected the seed acknowledged before the request, got %+v", first.Messages)
+	}
+
+	// Starting again doesn't seed a member twice
+	if err := tm.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	seeded := 0
+	for _, msg := range tm.GetMemberByRole("engineer").getContextMessages() {
+		if strings.Contains(msg.Content, seed) {
+			seeded++
+		}
+	}
+	if seeded != 1 {
+		t.Errorf("Expected the engineer seeded once, got %d", seeded)
+	}
+
+	// Clearing the context for a new conversation keeps the seed
+	pm := tm.GetMember("pm")
+	pm.ClearContext()
+	history := pm.getContextMessages()
+	if len(history) != 2 || !strings.Contains(history[0].Content, seed) {
+		t.Errorf("Expected only the seed left after clearing, got %+v", history)
+	}
+}
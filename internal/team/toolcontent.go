@@ -0,0 +1,43 @@
+package team
+
+import (
+	"strings"
+
+	"github.com/arcslash/ugudu/internal/provider"
+	"github.com/arcslash/ugudu/internal/tools"
+)
+
+// toolContent returns the content of a tool result that has more than text
+// in it
+func toolContent(result interface{}) (*tools.Content, bool) {
+	switch c := result.(type) {
+	case *tools.Content:
+		return c, c != nil
+	case tools.Content:
+		return &c, true
+	}
+	return nil, false
+}
+
+// contentForModel splits a tool's content into the text and images the
+// member's model is sent. Images go to providers that take them; anything
+// else is described in the text.
+func (m *Member) contentForModel(c *tools.Content) (string, []provider.Image) {
+	if !provider.TakesImageToolResults(m.Provider) {
+		return c.Describe(), nil
+	}
+
+	var images []provider.Image
+	var parts []string
+	if c.Text != "" {
+		parts = append(parts, c.Text)
+	}
+	for _, a := range c.Attachments {
+		if a.IsImage() {
+			images = append(images, provider.Image{MediaType: a.MimeType, Data: a.Data})
+			continue
+		}
+		parts = append(parts, a.Describe())
+	}
+	return strings.Join(parts, "\n"), images
+}
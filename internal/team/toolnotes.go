@@ -0,0 +1,81 @@
+package team
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/arcslash/ugudu/internal/provider"
+)
+
+// Bounds on the tool record kept in context with keep_tool_results
+const (
+	toolNoteBytes  = 1000 // Of each call's arguments, and of its result
+	toolNotesBytes = 6000 // Of a turn's record; the oldest calls are dropped first
+)
+
+// withToolNotes puts an abridged record of the tool calls in turn, the
+// messages a member's tool loop added, in front of its reply, for keeping in
+// context. The reply is returned as is unless the team keeps tool results.
+func (m *Member) withToolNotes(turn []provider.Message, reply string) string {
+	if m.Team == nil || m.Team.Spec == nil || !m.Team.Spec.Settings.KeepToolResults {
+		return reply
+	}
+	notes := toolNotes(turn)
+	if notes == "" {
+		return reply
+	}
+	return notes + "\n" + reply
+}
+
+// toolNotes summarizes the tool calls in messages and their results, one
+// line each, within toolNotesBytes
+func toolNotes(messages []provider.Message) string {
+	results := make(map[string]string)
+	for _, msg := range messages {
+		if msg.Role == "tool" {
+			results[msg.ToolCallID] = msg.Content
+		}
+	}
+
+	var lines []string
+	for _, msg := range messages {
+		for _, tc := range msg.ToolCalls {
+			result, ok := results[tc.ID]
+			if !ok {
+				result = "(no result)"
+			}
+			lines = append(lines, fmt.Sprintf("- %s %s: %s\n",
+				tc.Name, truncateMessage(compactJSON(tc.Arguments), toolNoteBytes), truncateMessage(compactJSON(result), toolNoteBytes)))
+		}
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+
+	size, start := 0, len(lines)
+	for start > 0 && size+len(lines[start-1]) <= toolNotesBytes {
+		start--
+		size += len(lines[start])
+	}
+
+	var sb strings.Builder
+	sb.WriteString("[Tools I used for this, results abridged]\n")
+	if start > 0 {
+		fmt.Fprintf(&sb, "(%d earlier calls left out)\n", start)
+	}
+	for _, line := range lines[start:] {
+		sb.WriteString(line)
+	}
+	return sb.String()
+}
+
+// compactJSON removes the indentation from s if it's JSON
+func compactJSON(s string) string {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, []byte(s)); err != nil {
+		return strings.TrimSpace(s)
+	}
+	return buf.String()
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/arcslash/ugudu/internal/provider"
+	"github.com/arcslash/ugudu/internal/tools"
 )
 
 // TokenMode controls token consumption level
@@ -21,12 +22,15 @@ type TokenSettings struct {
 	Mode           TokenMode `yaml:"mode,omitempty"`            // normal, low, minimal
 	MaxTokens      int       `yaml:"max_tokens,omitempty"`      // Override max tokens (0 = use default)
 	ContextHistory int       `yaml:"context_history,omitempty"` // Number of messages to keep (0 = use default)
+	ContextBytes   int       `yaml:"context_bytes,omitempty"`   // Bytes of message content to keep (0 = use default)
 }
 
 // TeamSpec defines a team from YAML configuration
 type TeamSpec struct {
 	APIVersion   string            `yaml:"apiVersion"`
 	Kind         string            `yaml:"kind"`
+	Extends      string            `yaml:"extends,omitempty"`  // Parent spec to inherit from
+	Includes     []string          `yaml:"includes,omitempty"` // Spec fragments merged before this spec
 	Metadata     Metadata          `yaml:"metadata"`
 	ClientFacing []string          `yaml:"client_facing,omitempty"`
 	Roles        map[string]Role   `yaml:"roles"`
@@ -38,6 +42,76 @@ type TeamSpec struct {
 // TeamSettings contains runtime settings for the team
 type TeamSettings struct {
 	Token TokenSettings `yaml:"token,omitempty"`
+
+	// Text wrapped around every member's system prompt, e.g. policy guardrails.
+	// Never condensed by token modes.
+	SystemPrefix string `yaml:"system_prefix,omitempty"`
+	SystemSuffix string `yaml:"system_suffix,omitempty"`
+
+	// Don't apply the daemon's global prefix/suffix to this team
+	IgnoreGlobalPrompt bool `yaml:"ignore_global_prompt,omitempty"`
+
+	// Cache system prompts and tool definitions with providers that support
+	// it. Unset uses the provider's default.
+	PromptCaching *bool `yaml:"prompt_caching,omitempty"`
+
+	// Run members' commands in a container: docker, podman or none (default)
+	Sandbox         string `yaml:"sandbox,omitempty"`
+	SandboxImage    string `yaml:"sandbox_image,omitempty"`    // Image to run them in
+	SandboxFallback bool   `yaml:"sandbox_fallback,omitempty"` // Run on the host if the runtime is unavailable
+
+	// How long a project waits for the client to answer the PM's questions
+	// before OnBlockTimeout applies. Zero waits indefinitely.
+	BlockTimeout   time.Duration `yaml:"block_timeout,omitempty"`
+	OnBlockTimeout string        `yaml:"on_block_timeout,omitempty"` // "proceed" on the PM's assumptions (default) or "fail"
+	BlockWarning   time.Duration `yaml:"block_warning,omitempty"`    // Notify once a project has been blocked this long
+
+	// Spread out parallel delegations so members sharing a provider don't
+	// all call it at once. Zero sends them all at once (default).
+	DelegationStagger     time.Duration `yaml:"delegation_stagger,omitempty"`      // Wait between dispatches
+	MaxParallelDelegation int           `yaml:"max_parallel_delegation,omitempty"` // Tasks in flight at once
+
+	// Stories a project's execution phase works on at once (default
+	// DefaultMaxParallelStories). The rest wait for one to finish.
+	MaxParallelStories int `yaml:"max_parallel_stories,omitempty"`
+
+	// Tools a human must approve before each call, e.g. run_command or
+	// git_commit. Unapproved calls are denied after ApprovalTimeout; zero
+	// waits indefinitely.
+	RequiresApproval []string      `yaml:"requires_approval,omitempty"`
+	ApprovalTimeout  time.Duration `yaml:"approval_timeout,omitempty"`
+
+	// Stop the team after this long without asks or activity, overriding the
+	// daemon's idle_stop_seconds; its next ask starts it again. Negative
+	// keeps it running regardless.
+	IdleStop time.Duration `yaml:"idle_stop,omitempty"`
+
+	// How long a member waits on a single model call before giving up
+	// (default 10m)
+	RequestTimeout time.Duration `yaml:"request_timeout,omitempty"`
+
+	// Environment variables added to every member's run_command calls. A
+	// role's own env wins; PATH is put in front of the existing PATH.
+	Env map[string]string `yaml:"env,omitempty"`
+
+	// Rewrite replies for the client before they're sent
+	Editor EditorSettings `yaml:"editor,omitempty"`
+
+	// Keep an abridged record of each turn's tool calls and results in
+	// members' context, so later turns don't repeat them. Costs tokens.
+	KeepToolResults bool `yaml:"keep_tool_results,omitempty"`
+
+	// IANA timezone members are told the time in, e.g. Europe/London.
+	// Defaults to the daemon's defaults.timezone, else its local time.
+	Timezone string `yaml:"timezone,omitempty"`
+}
+
+// EditorSettings configure the editor pass over client-facing replies,
+// which keeps internal jargon and raw tool output away from the client
+type EditorSettings struct {
+	Enabled      bool        `yaml:"enabled,omitempty"`
+	Model        ModelConfig `yaml:"model,omitempty"`        // Defaults to the replying member's provider and model
+	Instructions string      `yaml:"instructions,omitempty"` // Replaces the default editing instructions, e.g. for house style
 }
 
 // Metadata contains team metadata
@@ -49,35 +123,55 @@ type Metadata struct {
 
 // Role defines a team member role
 type Role struct {
-	Title            string         `yaml:"title"`
-	Name             string         `yaml:"name,omitempty"`             // Personal name (e.g., "Alice")
-	Names            []string       `yaml:"names,omitempty"`            // Names for multiple instances (e.g., ["Alice", "Bob"])
-	Count            int            `yaml:"count,omitempty"`            // Number of members with this role (default 1)
-	Visibility       string         `yaml:"visibility,omitempty"`       // "client" or "internal"
-	Model            ModelConfig    `yaml:"model"`
-	Persona          string         `yaml:"persona"`
-	PersonaCondensed string         `yaml:"persona_condensed,omitempty"` // Shorter persona for low token mode
-	Responsibilities []string       `yaml:"responsibilities,omitempty"`
-	Skills           []string       `yaml:"skills,omitempty"`
-	Tools            []ToolConfig   `yaml:"tools,omitempty"`
-	ReportsTo        string         `yaml:"reports_to,omitempty"`
-	CanDelegate      []string       `yaml:"can_delegate,omitempty"` // Roles this role can delegate to
+	Title            string            `yaml:"title"`
+	Name             string            `yaml:"name,omitempty"`       // Personal name (e.g., "Alice")
+	Names            []string          `yaml:"names,omitempty"`      // Names for multiple instances (e.g., ["Alice", "Bob"])
+	Count            int               `yaml:"count,omitempty"`      // Number of members with this role (default 1)
+	Visibility       string            `yaml:"visibility,omitempty"` // "client" or "internal"
+	Model            ModelConfig       `yaml:"model"`
+	Persona          string            `yaml:"persona"`
+	PersonaCondensed string            `yaml:"persona_condensed,omitempty"` // Shorter persona for low token mode
+	Responsibilities []string          `yaml:"responsibilities,omitempty"`
+	Skills           []string          `yaml:"skills,omitempty"`
+	Tools            []ToolConfig      `yaml:"tools,omitempty"`
+	ReportsTo        string            `yaml:"reports_to,omitempty"`
+	CanDelegate      []string          `yaml:"can_delegate,omitempty"` // Roles this role can delegate to
+	CanHandoff       []string          `yaml:"can_handoff,omitempty"`  // Roles this role can hand the client conversation to
+	Env              map[string]string `yaml:"env,omitempty"`          // Environment for this role's run_command calls only
 }
 
 // ModelConfig specifies which model to use
 type ModelConfig struct {
-	Provider      string        `yaml:"provider"`
-	Model         string        `yaml:"model"`
-	Temperature   *float64      `yaml:"temperature,omitempty"`
-	MaxTokens     *int          `yaml:"max_tokens,omitempty"`
-	Fallback      []ModelConfig `yaml:"fallback,omitempty"`
-	LowTokenModel string        `yaml:"low_token_model,omitempty"` // Cheaper model for low token mode
+	Provider      string               `yaml:"provider"`
+	Model         string               `yaml:"model"`
+	Temperature   *float64             `yaml:"temperature,omitempty"`
+	MaxTokens     *int                 `yaml:"max_tokens,omitempty"`
+	TopP          *float64             `yaml:"top_p,omitempty"`
+	Stop          []string             `yaml:"stop,omitempty"` // Stop sequences
+	Fallback      []ModelConfig        `yaml:"fallback,omitempty"`
+	LowTokenModel string               `yaml:"low_token_model,omitempty"` // Cheaper model for low and minimal token modes
+	Models        map[TokenMode]string `yaml:"models,omitempty"`          // Model per token mode, overriding Model and LowTokenModel
+
+	// Upstream provider routing when Provider is openrouter
+	OpenRouter *provider.OpenRouterRouting `yaml:"openrouter,omitempty"`
+}
+
+// ModelFor returns the model to use in a token mode: the one models names
+// for it, else low_token_model in low and minimal mode, else model
+func (c ModelConfig) ModelFor(mode TokenMode) string {
+	if model := c.Models[mode]; model != "" {
+		return model
+	}
+	if (mode == TokenModeLow || mode == TokenModeMinimal) && c.LowTokenModel != "" {
+		return c.LowTokenModel
+	}
+	return c.Model
 }
 
 // ToolConfig defines a tool available to a role
 type ToolConfig struct {
 	Name        string                 `yaml:"name"`
-	Type        string                 `yaml:"type,omitempty"`        // "builtin", "http", "command"
+	Type        string                 `yaml:"type,omitempty"`        // "builtin", "http", "command", "mcp" (Name is the MCP server)
 	Description string                 `yaml:"description,omitempty"`
 	Endpoint    string                 `yaml:"endpoint,omitempty"`
 	Command     string                 `yaml:"command,omitempty"`
@@ -89,6 +183,19 @@ type WorkflowSpec struct {
 	Pattern     string        `yaml:"pattern,omitempty"`    // "hub-spoke", "pipeline", "collaborative"
 	Stages      []StageSpec   `yaml:"stages,omitempty"`
 	AutoAssign  bool          `yaml:"auto_assign,omitempty"`
+
+	// Projects run through Phases if set, or else the built-in workflow
+	// Name names; software-dev by default
+	Name   string          `yaml:"name,omitempty"`
+	Phases []WorkflowPhase `yaml:"phases,omitempty"`
+}
+
+// WorkflowPhase is a step of a project and the roles that handle it, in
+// order of preference
+type WorkflowPhase struct {
+	Name  string       `yaml:"name"`
+	Kind  ProjectPhase `yaml:"kind"` // planning, task_breakdown, execution or review
+	Roles []string     `yaml:"roles"`
 }
 
 // StageSpec defines a workflow stage
@@ -103,6 +210,10 @@ type StageSpec struct {
 type SharedResources struct {
 	Memory MemoryConfig `yaml:"memory,omitempty"`
 	Tools  []ToolConfig `yaml:"tools,omitempty"`
+
+	// External MCP servers by name. Roles list the ones they may use as
+	// tools of type mcp. Overrides daemon-level servers of the same name.
+	MCPServers map[string]tools.MCPServerConfig `yaml:"mcp_servers,omitempty"`
 }
 
 // MemoryConfig defines team memory/state storage
@@ -148,6 +259,38 @@ type TaskResult struct {
 	Artifacts map[string]interface{} `json:"artifacts,omitempty"`
 }
 
+// DelegationResult is how one branch of a parallel delegation ended, sent
+// with the client response that follows it
+type DelegationResult struct {
+	Role    string `json:"role"`
+	Member  string `json:"member"`
+	Success bool   `json:"success"`
+	Content string `json:"content,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Outcomes of a model call
+const (
+	CallOK          = "ok"
+	CallRateLimited = "rate_limited"
+	CallTimeout     = "timeout"
+	CallCanceled    = "canceled"
+	CallError       = "error"
+)
+
+// ProviderCall is the timing and outcome of one model call a member made
+type ProviderCall struct {
+	MemberID         string
+	Provider         string
+	Model            string
+	StartedAt        time.Time
+	Duration         time.Duration
+	PromptTokens     int
+	CompletionTokens int
+	Outcome          string // CallOK, CallRateLimited, CallTimeout, CallCanceled or CallError
+	RequestID        string
+}
+
 // Message represents internal team communication
 type Message struct {
 	ID        string         `json:"id"`
@@ -156,6 +299,12 @@ type Message struct {
 	To        string         `json:"to"`
 	Content   interface{}    `json:"content"`
 	TaskID    string         `json:"task_id,omitempty"`
+	RequestID string         `json:"request_id,omitempty"` // Correlates messages caused by one client request
+	Model     string         `json:"model,omitempty"`      // Model that produced a client response
+	Provider  string         `json:"provider,omitempty"`   // Provider that served Model
+	NoTools   bool           `json:"no_tools,omitempty"`   // Answer a client request without tools
+	Delegation []DelegationResult `json:"delegation,omitempty"` // Parallel delegation behind a client response
+	handoff   *handoff       // Set on a client request another member handed over
 	Timestamp time.Time      `json:"timestamp"`
 }
 
@@ -173,8 +322,71 @@ const (
 	MsgDelegation      MessageType = "delegation"
 	MsgReport          MessageType = "report"
 	MsgHeartbeat       MessageType = "heartbeat"
+	MsgInternal        MessageType = "internal" // Intermediate work shown to the client in full verbosity
+	MsgProgress        MessageType = "progress" // Interim status while work is delegated, never the answer
 )
 
+// Verbosity controls how much of the team's internal work a request returns
+type Verbosity string
+
+const (
+	VerbosityQuiet   Verbosity = "quiet"   // Only client-facing messages
+	VerbositySummary Verbosity = "summary" // Also progress updates while work is delegated (default)
+	VerbosityFull    Verbosity = "full"    // Also delegation results and tool summaries
+)
+
+// AskOption configures a single request to the team
+type AskOption func(*askOptions)
+
+type askOptions struct {
+	verbosity Verbosity
+	requestID string
+	noTools   bool
+}
+
+// WithVerbosity sets how much internal work is returned for the request
+func WithVerbosity(v Verbosity) AskOption {
+	return func(o *askOptions) {
+		o.verbosity = v
+	}
+}
+
+// WithRequestID tags the request and everything it causes (delegations, tool
+// calls, responses, log lines) with a correlation ID
+func WithRequestID(id string) AskOption {
+	return func(o *askOptions) {
+		o.requestID = id
+	}
+}
+
+// WithoutTools has the member answer from the model alone: no tools are
+// offered or described, which saves a turn and prompt tokens on chit-chat
+func WithoutTools() AskOption {
+	return func(o *askOptions) {
+		o.noTools = true
+	}
+}
+
+// wants reports whether a message of type typ is returned at the request's
+// verbosity
+func (o askOptions) wants(typ MessageType) bool {
+	switch typ {
+	case MsgInternal:
+		return o.verbosity == VerbosityFull
+	case MsgProgress:
+		return o.verbosity != VerbosityQuiet
+	}
+	return true
+}
+
+func applyAskOptions(opts []AskOption) askOptions {
+	o := askOptions{verbosity: VerbositySummary}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
 // MemberStatus represents the current state of a team member
 type MemberStatus string
 
@@ -3,6 +3,7 @@ package team
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -124,6 +125,53 @@ func TestLoadSpecInvalidYAML(t *testing.T) {
 	}
 }
 
+func TestLoadSpecSchema(t *testing.T) {
+	const roles = `
+metadata:
+  name: schema-team
+roles:
+  lead:
+    title: Lead
+    model:
+      provider: mock
+      model: mock-model
+`
+	tests := []struct {
+		name    string
+		header  string
+		wantErr string // Empty when the spec should load
+	}{
+		{"v1", "apiVersion: ugudu/v1\nkind: Team\n", ""},
+		{"defaults to v1", "", ""},
+		{"unknown apiVersion", "apiVersion: ugudu/v9\nkind: Team\n", `unsupported apiVersion "ugudu/v9" (supported: ugudu/v1)`},
+		{"unknown kind", "apiVersion: ugudu/v1\nkind: Pipeline\n", `unsupported kind "Pipeline"`},
+		{"unknown key", "apiVersion: ugudu/v1\nroals:\n  lead: {}\n", `unknown key "roals" on line 2 (valid keys: apiVersion, client_facing, extends,`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			specPath := filepath.Join(t.TempDir(), "team.yaml")
+			if err := os.WriteFile(specPath, []byte(tt.header+roles), 0644); err != nil {
+				t.Fatalf("Failed to write test file: %v", err)
+			}
+
+			spec, err := LoadSpec(specPath)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("LoadSpec failed: %v", err)
+				}
+				if spec.APIVersion != APIVersionV1 || spec.Kind != KindTeam || spec.Roles["lead"].Title != "Lead" {
+					t.Errorf("Expected a v1 Team with a lead, got %s %s %v", spec.APIVersion, spec.Kind, spec.Roles)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
 func TestRoleDefaults(t *testing.T) {
 	tmpDir := t.TempDir()
 	specPath := filepath.Join(tmpDir, "minimal.yaml")
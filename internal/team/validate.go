@@ -0,0 +1,161 @@
+package team
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/arcslash/ugudu/internal/provider"
+	"github.com/arcslash/ugudu/internal/tools"
+)
+
+// modelListTimeout bounds asking a provider which models it offers
+const modelListTimeout = 5 * time.Second
+
+// ValidateSpec checks a spec document the way loading it and creating its
+// team would, short of looking up providers: it must decode, have roles,
+// have a client-facing role, and only refer to roles it defines. Unlike
+// LintSpec, anything it reports would stop the team from working.
+func ValidateSpec(data []byte) error {
+	spec, err := decodeSpec(data)
+	if err != nil {
+		return err
+	}
+	if spec.Extends != "" || len(spec.Includes) > 0 {
+		// Roles may come from the parents, which aren't resolved here
+		return nil
+	}
+	if len(spec.Roles) == 0 {
+		return fmt.Errorf("spec has no roles")
+	}
+
+	roleIDs := make([]string, 0, len(spec.Roles))
+	for id := range spec.Roles {
+		roleIDs = append(roleIDs, id)
+	}
+	sort.Strings(roleIDs)
+
+	clientFacing := len(spec.ClientFacing) > 0
+	for _, id := range spec.ClientFacing {
+		if _, ok := spec.Roles[id]; !ok {
+			return fmt.Errorf("client_facing names role %q, which isn't defined", id)
+		}
+	}
+
+	for _, id := range roleIDs {
+		role := spec.Roles[id]
+		if role.Visibility == "client" {
+			clientFacing = true
+		}
+		if role.Model.Provider == "" {
+			return fmt.Errorf("role %s: model.provider is required", id)
+		}
+		if role.Count < 0 {
+			return fmt.Errorf("role %s: count can't be negative", id)
+		}
+		for _, target := range role.CanDelegate {
+			if _, ok := spec.Roles[target]; !ok {
+				return fmt.Errorf("role %s: can_delegate names role %q, which isn't defined", id, target)
+			}
+		}
+		for _, target := range role.CanHandoff {
+			if _, ok := spec.Roles[target]; !ok {
+				return fmt.Errorf("role %s: can_handoff names role %q, which isn't defined", id, target)
+			}
+		}
+		if _, ok := spec.Roles[role.ReportsTo]; role.ReportsTo != "" && !ok {
+			return fmt.Errorf("role %s: reports_to names role %q, which isn't defined", id, role.ReportsTo)
+		}
+	}
+	if !clientFacing {
+		return fmt.Errorf("spec has no client-facing role (set client_facing or a role's visibility: client)")
+	}
+
+	if err := validateBlockSettings(spec.Settings); err != nil {
+		return err
+	}
+	if err := validateEnv(spec); err != nil {
+		return err
+	}
+	if err := validateModeModels(spec); err != nil {
+		return err
+	}
+	if editor := spec.Settings.Editor.Model; editor.Provider != "" && editor.Model == "" {
+		return fmt.Errorf("settings.editor: model.model is required with model.provider")
+	}
+	if err := validateTimezone(spec.Settings); err != nil {
+		return err
+	}
+	return validateWorkflow(spec)
+}
+
+// validateEnv checks the variables the spec adds to members' commands
+func validateEnv(spec *TeamSpec) error {
+	if err := tools.ValidateEnv(spec.Settings.Env); err != nil {
+		return fmt.Errorf("settings.%w", err)
+	}
+	roleIDs := make([]string, 0, len(spec.Roles))
+	for id := range spec.Roles {
+		roleIDs = append(roleIDs, id)
+	}
+	sort.Strings(roleIDs)
+	for _, id := range roleIDs {
+		if err := tools.ValidateEnv(spec.Roles[id].Env); err != nil {
+			return fmt.Errorf("role %s: %w", id, err)
+		}
+	}
+	return nil
+}
+
+// validateModeModels checks each role's models map names only token modes,
+// and a model for each
+func validateModeModels(spec *TeamSpec) error {
+	roleIDs := make([]string, 0, len(spec.Roles))
+	for id := range spec.Roles {
+		roleIDs = append(roleIDs, id)
+	}
+	sort.Strings(roleIDs)
+	for _, id := range roleIDs {
+		for mode, model := range spec.Roles[id].Model.Models {
+			if mode != TokenModeNormal && mode != TokenModeLow && mode != TokenModeMinimal {
+				return fmt.Errorf("role %s: model.models has %q, which isn't a token mode (normal, low or minimal)", id, mode)
+			}
+			if model == "" {
+				return fmt.Errorf("role %s: model.models.%s is empty", id, mode)
+			}
+		}
+	}
+	return nil
+}
+
+// checkModeModels checks a role's per-mode models are among those its
+// provider lists. Providers that can't list their models are trusted, and
+// since some lists are fixed rather than fetched, callers should treat an
+// error as a warning rather than refuse the role.
+func checkModeModels(ctx context.Context, prov provider.Provider, roleName string, cfg ModelConfig) error {
+	if len(cfg.Models) == 0 {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, modelListTimeout)
+	defer cancel()
+	models, err := prov.ListModels(ctx)
+	if err != nil || len(models) == 0 {
+		return nil
+	}
+
+	offered := make(map[string]bool, len(models))
+	ids := make([]string, len(models))
+	for i, m := range models {
+		offered[m.ID] = true
+		ids[i] = m.ID
+	}
+	for _, mode := range []TokenMode{TokenModeNormal, TokenModeLow, TokenModeMinimal} {
+		if model, ok := cfg.Models[mode]; ok && !offered[model] {
+			return fmt.Errorf("role %s: model.models.%s is %q, which %s doesn't list (has: %s)",
+				roleName, mode, model, prov.ID(), strings.Join(ids, ", "))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package team
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateSpec(t *testing.T) {
+	valid := `metadata:
+  name: shop
+client_facing: [pm]
+roles:
+  pm:
+    title: PM
+    model: {provider: anthropic}
+    can_delegate: [engineer]
+  engineer:
+    title: Engineer
+    model: {provider: anthropic}
+    reports_to: pm
+`
+	if err := ValidateSpec([]byte(valid)); err != nil {
+		t.Fatalf("Expected a valid spec, got %v", err)
+	}
+
+	tests := []struct {
+		name string
+		old  string
+		new  string
+		want string
+	}{
+		{"unknown delegate", "can_delegate: [engineer]", "can_delegate: [designer]", `can_delegate names role "designer"`},
+		{"unknown handoff", "can_delegate: [engineer]", "can_handoff: [designer]", `can_handoff names role "designer"`},
+		{"unknown manager", "reports_to: pm", "reports_to: cto", `reports_to names role "cto"`},
+		{"unknown client-facing", "client_facing: [pm]", "client_facing: [lead]", `client_facing names role "lead"`},
+		{"no client-facing", "client_facing: [pm]\n", "", "no client-facing role"},
+		{"no provider", "    model: {provider: anthropic}\n    reports_to", "    reports_to", "model.provider is required"},
+		{"unknown key", "metadata:", "roels: {}\nmetadata:", `unknown key "roels"`},
+		{"reserved env", "    reports_to: pm", "    reports_to: pm\n    env: {HOME: /tmp}", "role engineer: env: HOME is reserved"},
+		{"unknown token mode", "    model: {provider: anthropic}\n    reports_to", "    model: {provider: anthropic, models: {cheap: claude-3-5-haiku-20241022}}\n    reports_to", `model.models has "cheap"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := strings.Replace(valid, tt.old, tt.new, 1)
+			if err := ValidateSpec([]byte(spec)); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected an error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package team
+
+import "sort"
+
+// SpecView is the JSON form of a spec shown by the API and `spec show`
+type SpecView struct {
+	Name         string              `json:"name"`
+	Description  string              `json:"description"`
+	Roles        map[string]RoleView `json:"roles"`
+	ClientFacing []string            `json:"client_facing"`
+	Provider     string              `json:"provider"` // The first role's (by ID) that sets one
+	Model        string              `json:"model"`
+}
+
+// RoleView is the JSON form of one role in a SpecView
+type RoleView struct {
+	Title    string   `json:"title"`
+	Name     string   `json:"name"`
+	Names    []string `json:"names"`
+	Count    int      `json:"count"`
+	Persona  string   `json:"persona"`
+	Provider string   `json:"provider"`
+	Model    string   `json:"model"`
+}
+
+// NewSpecView converts a loaded spec to its JSON form
+func NewSpecView(spec *TeamSpec) SpecView {
+	view := SpecView{
+		Name:         spec.Metadata.Name,
+		Description:  spec.Metadata.Description,
+		Roles:        make(map[string]RoleView, len(spec.Roles)),
+		ClientFacing: spec.ClientFacing,
+	}
+
+	ids := make([]string, 0, len(spec.Roles))
+	for id := range spec.Roles {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		role := spec.Roles[id]
+		// The first role's model config stands for the team's
+		if view.Provider == "" && role.Model.Provider != "" {
+			view.Provider = role.Model.Provider
+			view.Model = role.Model.Model
+		}
+		view.Roles[id] = RoleView{
+			Title:    role.Title,
+			Name:     role.Name,
+			Names:    role.Names,
+			Count:    role.Count,
+			Persona:  role.Persona,
+			Provider: role.Model.Provider,
+			Model:    role.Model.Model,
+		}
+	}
+	return view
+}
@@ -21,6 +21,7 @@ const (
 	PhaseReview         ProjectPhase = "review"
 	PhaseComplete       ProjectPhase = "complete"
 	PhaseBlocked        ProjectPhase = "blocked"
+	PhaseFailed         ProjectPhase = "failed"
 )
 
 // Project represents a client request that requires multi-agent collaboration
@@ -73,6 +74,9 @@ type Story struct {
 	Priority           int               `json:"priority"`
 	RequirementID      string            `json:"requirement_id"`
 
+	// Stories that must finish before this one starts, by ID
+	DependsOn []string `json:"depends_on,omitempty"`
+
 	// Assignment
 	AssignedRole   string `json:"assigned_role"`   // e.g., "backend", "frontend"
 	AssignedMember string `json:"assigned_member"` // Specific member ID
@@ -340,10 +344,88 @@ func (p *Project) AnswerQuestion(questionID, answer, answeredBy string) error {
 	return fmt.Errorf("question not found: %s", questionID)
 }
 
+// AssumeAnswer closes a client question that went unanswered, recording the
+// assumption the team proceeds on instead
+func (p *Project) AssumeAnswer(questionID, assumption string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for i := range p.PendingQuestions {
+		if p.PendingQuestions[i].ID == questionID && p.PendingQuestions[i].Status == "pending" {
+			p.PendingQuestions[i].Status = "dismissed"
+			p.PendingQuestions[i].Answer = assumption
+			p.PendingQuestions[i].AnsweredBy = "pm"
+			p.PendingQuestions[i].AnsweredAt = time.Now()
+			p.UpdatedAt = time.Now()
+		}
+	}
+}
+
+// Unanswered returns the client questions still waiting for an answer
+func (p *Project) Unanswered() []Question {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	var pending []Question
+	for _, q := range p.PendingQuestions {
+		if q.Status == "pending" {
+			pending = append(pending, q)
+		}
+	}
+	return pending
+}
+
+// BlockOnQuestions moves the project to blocked if any client questions
+// are unanswered, and returns how many. Checking and blocking together
+// means an answer can't slip in between and leave the project blocked with
+// nothing to wait for.
+func (p *Project) BlockOnQuestions() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	pending := 0
+	for _, q := range p.PendingQuestions {
+		if q.Status == "pending" {
+			pending++
+		}
+	}
+	if pending > 0 {
+		p.setPhase(PhaseBlocked)
+	}
+	return pending
+}
+
 // SetPhase updates the project phase
 func (p *Project) SetPhase(phase ProjectPhase) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
+	p.setPhase(phase)
+}
+
+// SetPhaseFrom moves the project to phase only if it's still in from, and
+// reports whether it did. It settles races such as a client answer and a
+// block timeout both trying to resume a blocked project.
+func (p *Project) SetPhaseFrom(from, phase ProjectPhase) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.Phase != from {
+		return false
+	}
+	p.setPhase(phase)
+	return true
+}
+
+// GetPhase returns the project's current phase
+func (p *Project) GetPhase() ProjectPhase {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.Phase
+}
+
+func (p *Project) setPhase(phase ProjectPhase) {
+	if p.Phase == phase {
+		return
+	}
 	p.Phase = phase
 	p.UpdatedAt = time.Now()
 
@@ -375,6 +457,16 @@ func (p *Project) AddCommunication(msgType, from, to, content string, metadata m
 	p.UpdatedAt = time.Now()
 }
 
+// ListStories returns the project's stories
+func (p *Project) ListStories() []*Story {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	stories := make([]*Story, len(p.Stories))
+	copy(stories, p.Stories)
+	return stories
+}
+
 // GetStoriesForRole returns stories assigned to a role
 func (p *Project) GetStoriesForRole(role string) []*Story {
 	p.mu.RLock()
@@ -403,6 +495,21 @@ func (p *Project) GetReadyStories() []*Story {
 	return stories
 }
 
+// Assign gives the story to a member and marks it ready to work on
+func (s *Story) Assign(memberID string) {
+	s.mu.Lock()
+	s.AssignedMember = memberID
+	s.mu.Unlock()
+	s.UpdateStatus(StoryReady)
+}
+
+// GetStatus returns the story's status
+func (s *Story) GetStatus() StoryStatus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.Status
+}
+
 // UpdateStoryStatus updates a story's status
 func (s *Story) UpdateStatus(status StoryStatus) {
 	s.mu.Lock()
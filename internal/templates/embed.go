@@ -27,12 +27,14 @@ func List() ([]string, error) {
 	return names, nil
 }
 
-// Get returns the content of a default template by name
+// Get returns a default template by name, rendered with its variables'
+// defaults
 func Get(name string) ([]byte, error) {
-	return fs.ReadFile(defaultsFS, "defaults/"+name+".yaml")
+	return Render(name, nil)
 }
 
-// GetFS returns the embedded filesystem for advanced usage
+// GetFS returns the embedded filesystem for advanced usage. Its templates
+// are as written, with their placeholders.
 func GetFS() fs.FS {
 	sub, err := fs.Sub(defaultsFS, "defaults")
 	if err != nil {
@@ -43,6 +45,6 @@ func GetFS() fs.FS {
 
 // Exists checks if a default template exists
 func Exists(name string) bool {
-	_, err := Get(name)
+	_, err := Source(name)
 	return err == nil
 }
@@ -0,0 +1,138 @@
+package templates
+
+import (
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Variable is a placeholder a template declares, filled in when a team is
+// created from it
+type Variable struct {
+	Name        string
+	Description string
+	Default     string // Used when no value is given; empty means required
+}
+
+// varDecl matches a variable declaration, one per line at the top of a
+// template: "# var NAME=default: description"
+var varDecl = regexp.MustCompile(`^#\s*var\s+([A-Z][A-Z0-9_]*)(?:=([^:]*))?:\s*(.*)$`)
+
+// placeholder matches ${NAME}. Only declared names are substituted, so
+// environment references like ${OPENAI_API_KEY} are left for the spec loader.
+var placeholder = regexp.MustCompile(`\$\{([A-Z][A-Z0-9_]*)\}`)
+
+// Source returns a template as written, with its variable declarations and
+// placeholders
+func Source(name string) ([]byte, error) {
+	return fs.ReadFile(defaultsFS, "defaults/"+name+".yaml")
+}
+
+// Variables returns the variables a template declares
+func Variables(name string) ([]Variable, error) {
+	content, err := Source(name)
+	if err != nil {
+		return nil, err
+	}
+	vars, _ := parseVariables(content)
+	return vars, nil
+}
+
+// Render fills a template's placeholders from values, using each variable's
+// default for any not given. Naming a variable the template doesn't declare,
+// or leaving a required one out, is an error.
+func Render(name string, values map[string]string) ([]byte, error) {
+	content, err := Source(name)
+	if err != nil {
+		return nil, err
+	}
+	out, err := render(content, values)
+	if err != nil {
+		return nil, fmt.Errorf("template %s: %w", name, err)
+	}
+	return out, nil
+}
+
+func render(content []byte, values map[string]string) ([]byte, error) {
+	vars, body := parseVariables(content)
+
+	resolved := make(map[string]string, len(vars))
+	var missing []string
+	for _, v := range vars {
+		value, ok := values[v.Name]
+		if !ok {
+			value = v.Default
+		}
+		if value == "" {
+			missing = append(missing, v.Name)
+		}
+		if strings.ContainsAny(value, "\r\n") {
+			return nil, fmt.Errorf("%s must be a single line", v.Name)
+		}
+		resolved[v.Name] = value
+	}
+
+	var unknown []string
+	for key := range values {
+		if _, ok := resolved[key]; !ok {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("no variable %s (has: %s)", strings.Join(unknown, ", "), variableNames(vars))
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%s required", strings.Join(missing, ", "))
+	}
+
+	out := placeholder.ReplaceAllStringFunc(body, func(match string) string {
+		if value, ok := resolved[match[2:len(match)-1]]; ok {
+			return value
+		}
+		return match
+	})
+
+	// A value can still break the YAML around it, e.g. one starting with a
+	// quote; catch that here rather than when the team is created
+	var check map[string]interface{}
+	if err := yaml.Unmarshal([]byte(out), &check); err != nil {
+		return nil, fmt.Errorf("values make invalid YAML: %w", err)
+	}
+	return []byte(out), nil
+}
+
+// parseVariables splits a template into its variable declarations and the
+// spec that follows them
+func parseVariables(content []byte) ([]Variable, string) {
+	var vars []Variable
+	lines := strings.SplitAfter(string(content), "\n")
+	i := 0
+	for ; i < len(lines); i++ {
+		m := varDecl.FindStringSubmatch(strings.TrimSpace(lines[i]))
+		if m == nil {
+			break
+		}
+		vars = append(vars, Variable{
+			Name:        m[1],
+			Default:     strings.TrimSpace(m[2]),
+			Description: strings.TrimSpace(m[3]),
+		})
+	}
+	return vars, strings.Join(lines[i:], "")
+}
+
+func variableNames(vars []Variable) string {
+	if len(vars) == 0 {
+		return "none"
+	}
+	names := make([]string, len(vars))
+	for i, v := range vars {
+		names[i] = v.Name
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,108 @@
+package templates
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestRender_OnlyDeclaredPlaceholders(t *testing.T) {
+	// A role with its own name: line comes before the metadata, which a
+	// first-name:-line rewrite would clobber
+	content := []byte(`# var TEAM_NAME=demo: Name of the team
+# var PROVIDER=anthropic: Provider
+roles:
+  lead:
+    name: Sarah
+    model:
+      provider: ${PROVIDER}
+      api_key: ${ANTHROPIC_API_KEY}
+metadata:
+  name: ${TEAM_NAME}
+`)
+
+	out, err := render(content, map[string]string{"TEAM_NAME": "alpha"})
+	if err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+
+	var spec struct {
+		Metadata struct{ Name string }
+		Roles    map[string]struct {
+			Name  string
+			Model map[string]string
+		}
+	}
+	if err := yaml.Unmarshal(out, &spec); err != nil {
+		t.Fatalf("Rendered template isn't YAML: %v", err)
+	}
+	if spec.Metadata.Name != "alpha" {
+		t.Errorf("Expected metadata.name alpha, got %q", spec.Metadata.Name)
+	}
+	lead := spec.Roles["lead"]
+	if lead.Name != "Sarah" {
+		t.Errorf("Expected the role's name left alone, got %q", lead.Name)
+	}
+	if lead.Model["provider"] != "anthropic" {
+		t.Errorf("Expected the default provider, got %q", lead.Model["provider"])
+	}
+	if lead.Model["api_key"] != "${ANTHROPIC_API_KEY}" {
+		t.Errorf("Expected the undeclared placeholder left for the spec loader, got %q", lead.Model["api_key"])
+	}
+	if strings.Contains(string(out), "# var") {
+		t.Errorf("Expected the declarations stripped, got:\n%s", out)
+	}
+}
+
+func TestRender_Errors(t *testing.T) {
+	content := []byte("# var TEAM_NAME: Name of the team\nmetadata:\n  name: ${TEAM_NAME}\n")
+
+	tests := []struct {
+		name   string
+		values map[string]string
+		want   string
+	}{
+		{"required", nil, "TEAM_NAME required"},
+		{"unknown", map[string]string{"TEAM_NAME": "a", "MODLE": "x"}, "no variable MODLE"},
+		{"multiline", map[string]string{"TEAM_NAME": "a\nb"}, "single line"},
+		{"breaks YAML", map[string]string{"TEAM_NAME": `"unterminated`}, "invalid YAML"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := render(content, tt.values); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected an error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestRender_BuiltinTemplates(t *testing.T) {
+	names, err := List()
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	for _, name := range names {
+		vars, err := Variables(name)
+		if err != nil {
+			t.Fatalf("Variables(%s) failed: %v", name, err)
+		}
+		if len(vars) == 0 {
+			t.Errorf("Expected %s to declare variables", name)
+		}
+
+		out, err := Render(name, map[string]string{"TEAM_NAME": "alpha", "PROVIDER": "ollama", "MODEL": "llama3", "DOMAIN": "biotech"})
+		if err != nil {
+			t.Fatalf("Render(%s) failed: %v", name, err)
+		}
+		s := string(out)
+		for _, want := range []string{"name: alpha", "provider: ollama", "model: llama3", "biotech"} {
+			if !strings.Contains(s, want) {
+				t.Errorf("Expected %s rendered with %q", name, want)
+			}
+		}
+		if strings.Contains(s, "${") {
+			t.Errorf("Expected every placeholder in %s filled", name)
+		}
+	}
+}
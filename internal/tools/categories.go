@@ -20,6 +20,8 @@ const (
 	CategoryCommunication ToolCategory = "communication"
 	// CategoryHTTP includes HTTP/API operations
 	CategoryHTTP ToolCategory = "http"
+	// CategoryTaskBoard includes viewing the project board and moving tasks
+	CategoryTaskBoard ToolCategory = "taskboard"
 )
 
 // RoleToolMapping defines which tool categories each role can access
@@ -29,19 +31,23 @@ var RoleToolMapping = map[string][]ToolCategory{
 		CategoryCommand,
 		CategoryGit,
 		CategoryCommunication,
+		CategoryTaskBoard,
 	},
 	"pm": {
 		CategoryPlanning,
 		CategoryCommunication,
+		CategoryTaskBoard,
 	},
 	"qa": {
 		CategoryTesting,
 		CategoryCommand,
 		CategoryCommunication,
+		CategoryTaskBoard,
 	},
 	"ba": {
 		CategoryDocumentation,
 		CategoryCommunication,
+		CategoryTaskBoard,
 	},
 	// Default allows all categories
 	"default": {
@@ -53,6 +59,7 @@ var RoleToolMapping = map[string][]ToolCategory{
 		CategoryDocumentation,
 		CategoryCommunication,
 		CategoryHTTP,
+		CategoryTaskBoard,
 	},
 }
 
@@ -78,7 +85,6 @@ var ToolCategoryMapping = map[string]ToolCategory{
 	// Planning tools
 	"create_task":   CategoryPlanning,
 	"update_task":   CategoryPlanning,
-	"list_tasks":    CategoryPlanning,
 	"assign_task":   CategoryPlanning,
 	"create_report": CategoryPlanning,
 	"delegate_task": CategoryPlanning,
@@ -96,8 +102,15 @@ var ToolCategoryMapping = map[string]ToolCategory{
 
 	// Communication tools
 	"ask_colleague":    CategoryCommunication,
+	"ask_client":       CategoryCommunication,
 	"report_progress":  CategoryCommunication,
 
+	// Task board tools
+	"list_tasks":         CategoryTaskBoard,
+	"update_task_status": CategoryTaskBoard,
+	"get_project_tasks":  CategoryTaskBoard,
+	"get_standup":        CategoryTaskBoard,
+
 	// HTTP tools
 	"http_request": CategoryHTTP,
 }
@@ -0,0 +1,40 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// CurrentTimeTool tells a member the time, in the team's timezone or one it
+// asks for
+type CurrentTimeTool struct {
+	Now func() time.Time // The team's clock, in its timezone
+}
+
+func (t *CurrentTimeTool) Name() string { return "get_current_time" }
+func (t *CurrentTimeTool) Description() string {
+	return "Get the current date and time, in the team's timezone or another"
+}
+
+func (t *CurrentTimeTool) Execute(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	now := t.Now()
+	if name, ok := args["timezone"].(string); ok && name != "" {
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			return nil, fmt.Errorf("unknown timezone %q, use an IANA name such as Europe/London", name)
+		}
+		now = now.In(loc)
+	}
+
+	zone, _ := now.Zone()
+	return map[string]interface{}{
+		"time":       now.Format(time.RFC3339),
+		"date":       now.Format("2006-01-02"),
+		"weekday":    now.Weekday().String(),
+		"timezone":   now.Location().String(),
+		"zone":       zone,
+		"utc_offset": now.Format("-07:00"),
+		"unix":       now.Unix(),
+	}, nil
+}
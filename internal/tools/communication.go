@@ -12,6 +12,9 @@ type ColleagueFunc func(ctx context.Context, role string, message string) (strin
 // ProgressFunc is a function type for reporting progress
 type ProgressFunc func(ctx context.Context, status string, details map[string]interface{}) error
 
+// ClientQuestionFunc asks the client a question and waits for the answer
+type ClientQuestionFunc func(ctx context.Context, question string) (string, error)
+
 // ============================================================================
 // Communication Tools
 // ============================================================================
@@ -54,6 +57,37 @@ func (t *AskColleagueTool) Execute(ctx context.Context, args map[string]interfac
 	}, nil
 }
 
+// AskClientTool asks the client a question and blocks until it is answered
+type AskClientTool struct {
+	AskFunc ClientQuestionFunc
+}
+
+func (t *AskClientTool) Name() string { return "ask_client" }
+func (t *AskClientTool) Description() string {
+	return "Ask the client a question and wait for their answer. Use it only when you can't continue without their input."
+}
+
+func (t *AskClientTool) Execute(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	question, ok := args["question"].(string)
+	if !ok || question == "" {
+		return nil, fmt.Errorf("question is required")
+	}
+
+	if t.AskFunc == nil {
+		return nil, fmt.Errorf("ask function not configured")
+	}
+
+	answer, err := t.AskFunc(ctx, question)
+	if err != nil {
+		return nil, fmt.Errorf("ask client: %w", err)
+	}
+
+	return map[string]interface{}{
+		"question": question,
+		"answer":   answer,
+	}, nil
+}
+
 // ReportProgressTool reports progress on current work
 type ReportProgressTool struct {
 	FromRole   string
@@ -0,0 +1,122 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// DefaultContainerImage is used when a container sandbox doesn't name one
+const DefaultContainerImage = "alpine:3"
+
+// ErrContainerRuntimeUnavailable is returned when the configured container
+// runtime isn't installed or its daemon doesn't answer
+var ErrContainerRuntimeUnavailable = errors.New("container runtime not available")
+
+// ContainerConfig selects a container runtime for running agents' commands
+type ContainerConfig struct {
+	Runtime string // docker or podman
+	Image   string // Image commands run in (default DefaultContainerImage)
+}
+
+// CheckContainerRuntime reports whether runtime is installed and answering
+func CheckContainerRuntime(ctx context.Context, runtime string) error {
+	if runtime != "docker" && runtime != "podman" {
+		return fmt.Errorf("unknown sandbox %q (use docker or podman)", runtime)
+	}
+	if _, err := exec.LookPath(runtime); err != nil {
+		return fmt.Errorf("%w: %s not found in PATH", ErrContainerRuntimeUnavailable, runtime)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	if out, err := exec.CommandContext(ctx, runtime, "version").CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s version failed: %s", ErrContainerRuntimeUnavailable, runtime, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// containerSandbox runs each command in a throwaway container. Only dirs are
+// mounted, at the same paths as on the host, so paths mean the same thing
+// inside and out.
+type containerSandbox struct {
+	ContainerConfig
+	dirs []string
+}
+
+type containerKey struct{}
+
+// contains reports whether path is inside one of the mounted directories
+func (c *containerSandbox) contains(path string) bool {
+	for _, dir := range c.dirs {
+		if rel, err := filepath.Rel(dir, path); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *containerSandbox) run(ctx context.Context, dir, command string, env map[string]string, stdout, stderr io.Writer) error {
+	name := fmt.Sprintf("ugudu-%d", time.Now().UnixNano())
+	args := []string{"run", "--rm", "--name", name}
+	if uid, gid := os.Getuid(), os.Getgid(); uid >= 0 {
+		// Files the command creates belong to the daemon's user, not root
+		args = append(args, "--user", fmt.Sprintf("%d:%d", uid, gid))
+	}
+	for _, d := range c.dirs {
+		args = append(args, "-v", d+":"+d)
+	}
+	if dir != "" {
+		args = append(args, "-w", dir)
+	}
+	// Values are passed through the runtime's environment rather than its
+	// arguments, where anyone listing processes could read them. The image
+	// has its own PATH, so the spec's is put in front of that one.
+	for _, key := range sortedEnvNames(env) {
+		if key == "PATH" {
+			command = "PATH='" + strings.ReplaceAll(env[key], "'", `'\''`) + "':\"$PATH\"; " + command
+			continue
+		}
+		args = append(args, "-e", key)
+	}
+	image := c.Image
+	if image == "" {
+		image = DefaultContainerImage
+	}
+	args = append(args, image, "sh", "-c", command)
+
+	cmd := exec.CommandContext(ctx, c.Runtime, args...)
+	if len(env) > 0 {
+		cmd.Env = commandEnv(os.Environ(), env)
+	}
+	cmd.Stdout, cmd.Stderr = stdout, stderr
+	err := cmd.Run()
+	if ctx.Err() != nil {
+		// Killing the client on timeout leaves the container running
+		exec.Command(c.Runtime, "rm", "-f", name).Run()
+	}
+	return err
+}
+
+// runShell runs command with sh in dir, inside the caller's container
+// sandbox when its registry has one
+func runShell(ctx context.Context, dir, command string, stdout, stderr io.Writer) error {
+	env, _ := ctx.Value(envKey{}).(map[string]string)
+	if c, ok := ctx.Value(containerKey{}).(*containerSandbox); ok {
+		return c.run(ctx, dir, command, env, stdout, stderr)
+	}
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Dir = dir
+	if len(env) > 0 {
+		cmd.Env = commandEnv(os.Environ(), env)
+	}
+	cmd.Stdout, cmd.Stderr = stdout, stderr
+	return cmd.Run()
+}
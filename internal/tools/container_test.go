@@ -0,0 +1,80 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/arcslash/ugudu/internal/workspace"
+)
+
+func containerRegistry(t *testing.T, runtime string) (*SandboxedRegistry, string) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	r := NewSandboxedRegistry(NewRegistry(), nil, "engineer", "engineer")
+	r.SetContainer(ContainerConfig{Runtime: runtime})
+	return r, filepath.Join(home, "ugudu_projects")
+}
+
+func TestCheckContainerRuntime_Missing(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+
+	if err := CheckContainerRuntime(context.Background(), "docker"); !errors.Is(err, ErrContainerRuntimeUnavailable) {
+		t.Errorf("Expected ErrContainerRuntimeUnavailable, got %v", err)
+	}
+	if err := CheckContainerRuntime(context.Background(), "lxc"); err == nil || errors.Is(err, ErrContainerRuntimeUnavailable) {
+		t.Errorf("Expected an unknown sandbox error, got %v", err)
+	}
+}
+
+func TestSandboxedRegistry_ContainerConfinesFileTools(t *testing.T) {
+	r, projects := containerRegistry(t, "docker")
+	ctx := context.Background()
+
+	// Relative paths land in the mounted directory
+	if _, err := r.Execute(ctx, "write_file", map[string]interface{}{"path": "notes.txt", "content": "hi"}); err != nil {
+		t.Fatalf("write_file failed: %v", err)
+	}
+	result, err := r.Execute(ctx, "read_file", map[string]interface{}{"path": "notes.txt"})
+	if err != nil {
+		t.Fatalf("read_file failed: %v", err)
+	}
+	if got := result.(map[string]interface{})["path"]; got != filepath.Join(projects, "notes.txt") {
+		t.Errorf("Expected the file in %s, got %v", projects, got)
+	}
+
+	for _, path := range []string{"/etc/passwd", "../../etc/passwd"} {
+		if _, err := r.Execute(ctx, "read_file", map[string]interface{}{"path": path}); !errors.Is(err, workspace.ErrOutsideSandbox) {
+			t.Errorf("Expected %s to be outside the sandbox, got %v", path, err)
+		}
+	}
+}
+
+func TestSandboxedRegistry_ContainerRunCommand(t *testing.T) {
+	if err := CheckContainerRuntime(context.Background(), "docker"); err != nil {
+		t.Skipf("docker not available: %v", err)
+	}
+	r, projects := containerRegistry(t, "docker")
+
+	result, err := r.Execute(context.Background(), "run_command", map[string]interface{}{
+		"command": "echo hello > out.txt && cat /etc/os-release",
+	})
+	if err != nil {
+		t.Fatalf("run_command failed: %v", err)
+	}
+	out := result.(map[string]interface{})
+	if out["exitCode"] != 0 || !strings.Contains(strings.ToLower(out["stdout"].(string)), "alpine") {
+		t.Fatalf("Expected the command to run in an alpine container, got %v", out)
+	}
+
+	// The mounted directory is shared with the host
+	data, err := os.ReadFile(filepath.Join(projects, "out.txt"))
+	if err != nil || string(data) != "hello\n" {
+		t.Errorf("Expected out.txt on the host, got %q (%v)", data, err)
+	}
+}
@@ -0,0 +1,58 @@
+package tools
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// Content is a tool result with more than text in it, e.g. a screenshot.
+// Tools return it from Execute in place of a value to marshal as JSON.
+type Content struct {
+	Text        string       // Shown to every model
+	Attachments []Attachment // Images reach models whose provider takes them; the rest are described
+}
+
+// Attachment is binary content a tool returns
+type Attachment struct {
+	MimeType string // e.g. image/png
+	Data     string // Base64
+}
+
+// NewAttachment encodes data as an attachment
+func NewAttachment(mimeType string, data []byte) Attachment {
+	return Attachment{MimeType: mimeType, Data: base64.StdEncoding.EncodeToString(data)}
+}
+
+// IsImage reports whether the attachment is an image a vision model can be
+// shown: PNG, JPEG, GIF or WebP
+func (a Attachment) IsImage() bool {
+	switch a.MimeType {
+	case "image/png", "image/jpeg", "image/gif", "image/webp":
+		return true
+	}
+	return false
+}
+
+// Size returns the attachment's decoded size in bytes
+func (a Attachment) Size() int {
+	padding := len(a.Data) - len(strings.TrimRight(a.Data, "="))
+	return base64.StdEncoding.DecodedLen(len(a.Data)) - padding
+}
+
+// Describe renders the attachment as text, for models that can't be shown it
+func (a Attachment) Describe() string {
+	return fmt.Sprintf("[%s, %d bytes, not shown]", a.MimeType, a.Size())
+}
+
+// Describe renders the content as text alone, describing its attachments
+func (c *Content) Describe() string {
+	parts := make([]string, 0, len(c.Attachments)+1)
+	if c.Text != "" {
+		parts = append(parts, c.Text)
+	}
+	for _, a := range c.Attachments {
+		parts = append(parts, a.Describe())
+	}
+	return strings.Join(parts, "\n")
+}
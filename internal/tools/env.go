@@ -0,0 +1,89 @@
+package tools
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+type envKey struct{}
+
+// reservedEnv are variables a command can't run properly without the
+// daemon's own values, so a spec may not set them. PATH is the exception:
+// it's put in front of the existing PATH rather than replacing it.
+var reservedEnv = map[string]bool{"HOME": true, "PWD": true, "SHELL": true, "USER": true}
+
+var envName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// secretEnvWords mark a variable whose value is kept out of command output
+var secretEnvWords = []string{"TOKEN", "SECRET", "PASSWORD", "PASSWD", "KEY", "CREDENTIAL", "AUTH"}
+
+// ValidateEnv checks variables a spec adds to members' commands
+func ValidateEnv(env map[string]string) error {
+	for _, name := range sortedEnvNames(env) {
+		if !envName.MatchString(name) {
+			return fmt.Errorf("env: %q isn't a valid variable name", name)
+		}
+		if reservedEnv[name] {
+			return fmt.Errorf("env: %s is reserved and can't be set", name)
+		}
+	}
+	return nil
+}
+
+// commandEnv returns environ with env added, env winning. PATH is put in
+// front of environ's PATH so the usual commands are still found.
+func commandEnv(environ []string, env map[string]string) []string {
+	out := make([]string, 0, len(environ)+len(env))
+	path := ""
+	for _, kv := range environ {
+		name := strings.SplitN(kv, "=", 2)[0]
+		if name == "PATH" {
+			path = strings.TrimPrefix(kv, "PATH=")
+		}
+		if _, ok := env[name]; !ok {
+			out = append(out, kv)
+		}
+	}
+	for _, name := range sortedEnvNames(env) {
+		value := env[name]
+		if name == "PATH" && path != "" {
+			value += ":" + path
+		}
+		out = append(out, name+"="+value)
+	}
+	return out
+}
+
+// redactEnv replaces the values of env's secret variables in s, so a command
+// that prints one doesn't put it in the member's context or the logs
+func redactEnv(s string, env map[string]string) string {
+	for _, name := range sortedEnvNames(env) {
+		if value := env[name]; value != "" && IsSecretEnv(name) {
+			s = strings.ReplaceAll(s, value, "[REDACTED:"+name+"]")
+		}
+	}
+	return s
+}
+
+// IsSecretEnv reports whether a variable's name marks its value as a secret,
+// e.g. DEPLOY_TOKEN or DB_PASSWORD
+func IsSecretEnv(name string) bool {
+	upper := strings.ToUpper(name)
+	for _, word := range secretEnvWords {
+		if strings.Contains(upper, word) {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedEnvNames(env map[string]string) []string {
+	names := make([]string, 0, len(env))
+	for name := range env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
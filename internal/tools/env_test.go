@@ -0,0 +1,58 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestSandboxedRegistry_RunCommandEnv(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	r := NewSandboxedRegistry(NewRegistry(), nil, "engineer", "engineer")
+	r.SetEnv(map[string]string{
+		"API_BASE":     "https://staging.example.com",
+		"DEPLOY_TOKEN": "tok-5up3r-s3cr3t",
+		"PATH":         "/opt/deploy/bin",
+	})
+
+	result, err := r.Execute(context.Background(), "run_command", map[string]interface{}{
+		"command": `echo "$API_BASE"; echo "$DEPLOY_TOKEN"; echo "$PATH"`,
+	})
+	if err != nil {
+		t.Fatalf("run_command failed: %v", err)
+	}
+	out := result.(map[string]interface{})
+	lines := strings.Split(strings.TrimSpace(out["stdout"].(string)), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 lines, got %v", out)
+	}
+	if lines[0] != "https://staging.example.com" {
+		t.Errorf("Expected API_BASE in the command's environment, got %q", lines[0])
+	}
+	if lines[1] != "[REDACTED:DEPLOY_TOKEN]" {
+		t.Errorf("Expected DEPLOY_TOKEN redacted from the output, got %q", lines[1])
+	}
+	if want := "/opt/deploy/bin:" + os.Getenv("PATH"); lines[2] != want {
+		t.Errorf("Expected PATH %q, got %q", want, lines[2])
+	}
+
+	// Other registries don't get it
+	other := NewSandboxedRegistry(NewRegistry(), nil, "engineer", "engineer-2")
+	result, _ = other.Execute(context.Background(), "run_command", map[string]interface{}{"command": `echo "$API_BASE"`})
+	if got := strings.TrimSpace(result.(map[string]interface{})["stdout"].(string)); got != "" {
+		t.Errorf("Expected API_BASE unset for another agent, got %q", got)
+	}
+}
+
+func TestValidateEnv(t *testing.T) {
+	if err := ValidateEnv(map[string]string{"API_BASE": "x", "PATH": "/opt/bin"}); err != nil {
+		t.Errorf("Expected valid env, got %v", err)
+	}
+	if err := ValidateEnv(map[string]string{"HOME": "/tmp"}); err == nil || !strings.Contains(err.Error(), "HOME is reserved") {
+		t.Errorf("Expected HOME rejected, got %v", err)
+	}
+	if err := ValidateEnv(map[string]string{"API-BASE": "x"}); err == nil {
+		t.Error("Expected an invalid name rejected")
+	}
+}
@@ -4,7 +4,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
-	"os/exec"
+	"io"
 	"strings"
 	"time"
 )
@@ -13,6 +13,32 @@ import (
 // Git Tools
 // ============================================================================
 
+// gitSafeConfig overrides settings a repository's own config could use to
+// run a program: hooks, fsmonitor, pagers and commit signing
+var gitSafeConfig = []string{
+	"-c", "core.hooksPath=/dev/null",
+	"-c", "core.fsmonitor=false",
+	"-c", "core.pager=cat",
+	"-c", "commit.gpgSign=false",
+	"-c", "tag.gpgSign=false",
+}
+
+// runGit runs git with args in dir. Like run_command, it runs in the
+// caller's container sandbox when its registry has one, since members can
+// write to the repository and plant hooks or config in it.
+func runGit(ctx context.Context, dir string, stdout, stderr io.Writer, args ...string) error {
+	words := append(append([]string{"git", "--no-pager"}, gitSafeConfig...), args...)
+	for i, w := range words {
+		words[i] = shellQuote(w)
+	}
+	return runShell(ctx, dir, strings.Join(words, " "), stdout, stderr)
+}
+
+// shellQuote quotes s as a single sh word
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
 // GitStatusTool shows git status
 type GitStatusTool struct {
 	WorkingDir string
@@ -22,14 +48,8 @@ func (t *GitStatusTool) Name() string        { return "git_status" }
 func (t *GitStatusTool) Description() string { return "Show the working tree status" }
 
 func (t *GitStatusTool) Execute(ctx context.Context, args map[string]interface{}) (interface{}, error) {
-	cmd := exec.CommandContext(ctx, "git", "status", "--porcelain", "-b")
-	cmd.Dir = t.WorkingDir
-
 	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
-	if err := cmd.Run(); err != nil {
+	if err := runGit(ctx, t.WorkingDir, &stdout, &stderr, "status", "--porcelain", "-b"); err != nil {
 		return nil, fmt.Errorf("git status: %s", stderr.String())
 	}
 
@@ -93,7 +113,7 @@ func (t *GitDiffTool) Name() string        { return "git_diff" }
 func (t *GitDiffTool) Description() string { return "Show changes between commits, commit and working tree, etc" }
 
 func (t *GitDiffTool) Execute(ctx context.Context, args map[string]interface{}) (interface{}, error) {
-	cmdArgs := []string{"diff"}
+	cmdArgs := []string{"diff", "--no-ext-diff", "--no-textconv"}
 
 	// Add optional arguments
 	if staged, ok := args["staged"].(bool); ok && staged {
@@ -106,25 +126,16 @@ func (t *GitDiffTool) Execute(ctx context.Context, args map[string]interface{})
 		cmdArgs = append(cmdArgs, commit)
 	}
 
-	cmd := exec.CommandContext(ctx, "git", cmdArgs...)
-	cmd.Dir = t.WorkingDir
-
 	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
-	if err := cmd.Run(); err != nil {
+	if err := runGit(ctx, t.WorkingDir, &stdout, &stderr, cmdArgs...); err != nil {
 		return nil, fmt.Errorf("git diff: %s", stderr.String())
 	}
 
 	diff := stdout.String()
 
 	// Parse diff stats
-	statsCmd := exec.CommandContext(ctx, "git", append(cmdArgs, "--stat")...)
-	statsCmd.Dir = t.WorkingDir
 	var statsOut bytes.Buffer
-	statsCmd.Stdout = &statsOut
-	statsCmd.Run()
+	runGit(ctx, t.WorkingDir, &statsOut, nil, append(cmdArgs, "--stat")...)
 
 	return map[string]interface{}{
 		"diff":  diff,
@@ -156,41 +167,27 @@ func (t *GitCommitTool) Execute(ctx context.Context, args map[string]interface{}
 				addArgs = append(addArgs, s)
 			}
 		}
-		addCmd := exec.CommandContext(ctx, "git", addArgs...)
-		addCmd.Dir = t.WorkingDir
-		if err := addCmd.Run(); err != nil {
+		if err := runGit(ctx, t.WorkingDir, nil, nil, addArgs...); err != nil {
 			return nil, fmt.Errorf("git add: %w", err)
 		}
 	}
 
 	// Stage all if requested
 	if all, ok := args["all"].(bool); ok && all {
-		addCmd := exec.CommandContext(ctx, "git", "add", "-A")
-		addCmd.Dir = t.WorkingDir
-		if err := addCmd.Run(); err != nil {
+		if err := runGit(ctx, t.WorkingDir, nil, nil, "add", "-A"); err != nil {
 			return nil, fmt.Errorf("git add: %w", err)
 		}
 	}
 
 	// Create commit
-	commitArgs := []string{"commit", "-m", message}
-	cmd := exec.CommandContext(ctx, "git", commitArgs...)
-	cmd.Dir = t.WorkingDir
-
 	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
-	if err := cmd.Run(); err != nil {
+	if err := runGit(ctx, t.WorkingDir, &stdout, &stderr, "commit", "-m", message); err != nil {
 		return nil, fmt.Errorf("git commit: %s", stderr.String())
 	}
 
 	// Get commit hash
-	hashCmd := exec.CommandContext(ctx, "git", "rev-parse", "HEAD")
-	hashCmd.Dir = t.WorkingDir
 	var hashOut bytes.Buffer
-	hashCmd.Stdout = &hashOut
-	hashCmd.Run()
+	runGit(ctx, t.WorkingDir, &hashOut, nil, "rev-parse", "HEAD")
 
 	return map[string]interface{}{
 		"message":    message,
@@ -221,14 +218,8 @@ func (t *GitLogTool) Execute(ctx context.Context, args map[string]interface{}) (
 		cmdArgs = append(cmdArgs, "--", file)
 	}
 
-	cmd := exec.CommandContext(ctx, "git", cmdArgs...)
-	cmd.Dir = t.WorkingDir
-
 	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
-	if err := cmd.Run(); err != nil {
+	if err := runGit(ctx, t.WorkingDir, &stdout, &stderr, cmdArgs...); err != nil {
 		return nil, fmt.Errorf("git log: %s", stderr.String())
 	}
 
@@ -272,11 +263,8 @@ func (t *GitBranchTool) Execute(ctx context.Context, args map[string]interface{}
 
 	switch action {
 	case "list":
-		cmd := exec.CommandContext(ctx, "git", "branch", "-a")
-		cmd.Dir = t.WorkingDir
 		var stdout bytes.Buffer
-		cmd.Stdout = &stdout
-		if err := cmd.Run(); err != nil {
+		if err := runGit(ctx, t.WorkingDir, &stdout, nil, "branch", "-a"); err != nil {
 			return nil, fmt.Errorf("git branch: %w", err)
 		}
 
@@ -307,9 +295,7 @@ func (t *GitBranchTool) Execute(ctx context.Context, args map[string]interface{}
 			return nil, fmt.Errorf("name is required for create action")
 		}
 
-		cmd := exec.CommandContext(ctx, "git", "branch", name)
-		cmd.Dir = t.WorkingDir
-		if err := cmd.Run(); err != nil {
+		if err := runGit(ctx, t.WorkingDir, nil, nil, "branch", name); err != nil {
 			return nil, fmt.Errorf("git branch create: %w", err)
 		}
 
@@ -323,11 +309,8 @@ func (t *GitBranchTool) Execute(ctx context.Context, args map[string]interface{}
 			return nil, fmt.Errorf("name is required for checkout action")
 		}
 
-		cmd := exec.CommandContext(ctx, "git", "checkout", name)
-		cmd.Dir = t.WorkingDir
 		var stderr bytes.Buffer
-		cmd.Stderr = &stderr
-		if err := cmd.Run(); err != nil {
+		if err := runGit(ctx, t.WorkingDir, nil, &stderr, "checkout", name); err != nil {
 			return nil, fmt.Errorf("git checkout: %s", stderr.String())
 		}
 
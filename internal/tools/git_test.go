@@ -0,0 +1,74 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGitTools_IgnoreRepositoryHooks(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	repo := t.TempDir()
+	marker := filepath.Join(t.TempDir(), "ran")
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"config", "user.name", "Test"},
+		{"config", "user.email", "test@example.com"},
+	} {
+		if out, err := exec.Command("git", append([]string{"-C", repo}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v: %s", args, err, out)
+		}
+	}
+
+	// A member that can write to the repository plants a hook and an
+	// fsmonitor, both of which would otherwise run on the host
+	script := "#!/bin/sh\ntouch " + marker + "\n"
+	hook := filepath.Join(repo, ".git", "hooks", "pre-commit")
+	if err := os.WriteFile(hook, []byte(script), 0755); err != nil {
+		t.Fatalf("write hook: %v", err)
+	}
+	monitor := filepath.Join(repo, "monitor.sh")
+	os.WriteFile(monitor, []byte(script), 0755)
+	exec.Command("git", "-C", repo, "config", "core.fsmonitor", monitor).Run()
+	os.WriteFile(filepath.Join(repo, "main.go"), []byte("package main\n"), 0644)
+
+	ctx := context.Background()
+	if _, err := (&GitStatusTool{WorkingDir: repo}).Execute(ctx, nil); err != nil {
+		t.Fatalf("git_status failed: %v", err)
+	}
+	result, err := (&GitCommitTool{WorkingDir: repo}).Execute(ctx, map[string]interface{}{"message": "Add main", "all": true})
+	if err != nil {
+		t.Fatalf("git_commit failed: %v", err)
+	}
+	if commit, _ := result.(map[string]interface{})["commit"].(string); len(commit) != 40 {
+		t.Errorf("Expected a commit hash, got %v", result)
+	}
+
+	if _, err := os.Stat(marker); err == nil {
+		t.Error("Expected the planted hook and fsmonitor not to run")
+	}
+}
+
+func TestGitTools_RunInContainer(t *testing.T) {
+	// A fake runtime records how it was called
+	bin := t.TempDir()
+	calls := filepath.Join(t.TempDir(), "calls")
+	os.WriteFile(filepath.Join(bin, "docker"), []byte("#!/bin/sh\necho \"$@\" >> "+calls+"\n"), 0755)
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	r, projects := containerRegistry(t, "docker")
+	r.base.Register(&GitStatusTool{WorkingDir: projects})
+	if _, err := r.Execute(context.Background(), "git_status", nil); err != nil {
+		t.Fatalf("git_status failed: %v", err)
+	}
+
+	data, _ := os.ReadFile(calls)
+	if !strings.Contains(string(data), "run --rm") || !strings.Contains(string(data), "'git' '--no-pager'") || !strings.Contains(string(data), "core.hooksPath=/dev/null") {
+		t.Errorf("Expected git to run in the container with hooks off, got %q", data)
+	}
+}
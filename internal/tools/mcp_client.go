@@ -0,0 +1,584 @@
+package tools
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/arcslash/ugudu/internal/version"
+)
+
+// mcpProtocolVersion is the MCP revision the client speaks
+const mcpProtocolVersion = "2024-11-05"
+
+// MCPServerConfig is an external MCP server whose tools members can call.
+// Set Command to run it as a subprocess speaking MCP over stdio, or URL to
+// reach it over streamable HTTP.
+type MCPServerConfig struct {
+	Command []string          `yaml:"command,omitempty" json:"command,omitempty"` // Program and arguments
+	Env     map[string]string `yaml:"env,omitempty" json:"env,omitempty"`         // Added to the daemon's environment
+	URL     string            `yaml:"url,omitempty" json:"url,omitempty"`
+	Headers map[string]string `yaml:"headers,omitempty" json:"headers,omitempty"` // Sent with every HTTP request, e.g. Authorization
+}
+
+// Validate checks that exactly one way of reaching the server is set
+func (c MCPServerConfig) Validate() error {
+	switch {
+	case len(c.Command) > 0 && c.URL != "":
+		return errors.New("set command or url, not both")
+	case len(c.Command) == 0 && c.URL == "":
+		return errors.New("command or url required")
+	}
+	return nil
+}
+
+// rpcMessage is a JSON-RPC 2.0 request, notification or response
+type rpcMessage struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  interface{}     `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) Error() string {
+	return fmt.Sprintf("%s (code %d)", e.Message, e.Code)
+}
+
+// mcpTransport carries JSON-RPC messages to a server
+type mcpTransport interface {
+	// call sends a request and waits for its response
+	call(ctx context.Context, msg rpcMessage) (rpcMessage, error)
+	// notify sends a notification, which gets no response
+	notify(ctx context.Context, msg rpcMessage) error
+	close() error
+}
+
+// MCPClient is a connection to an external MCP server
+type MCPClient struct {
+	name      string
+	transport mcpTransport
+	nextID    atomic.Int64
+}
+
+// ConnectMCP starts or dials the server and performs the MCP handshake
+func ConnectMCP(ctx context.Context, name string, cfg MCPServerConfig) (*MCPClient, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("mcp server %s: %w", name, err)
+	}
+
+	var transport mcpTransport
+	if cfg.URL != "" {
+		transport = newHTTPTransport(cfg.URL, cfg.Headers)
+	} else {
+		t, err := startStdioTransport(cfg.Command, cfg.Env)
+		if err != nil {
+			return nil, fmt.Errorf("mcp server %s: %w", name, err)
+		}
+		transport = t
+	}
+
+	c := &MCPClient{name: name, transport: transport}
+	if err := c.initialize(ctx); err != nil {
+		transport.close()
+		return nil, fmt.Errorf("mcp server %s: initialize: %w", name, err)
+	}
+	return c, nil
+}
+
+// Name returns the name the server was configured under
+func (c *MCPClient) Name() string {
+	return c.name
+}
+
+func (c *MCPClient) initialize(ctx context.Context) error {
+	_, err := c.request(ctx, "initialize", map[string]interface{}{
+		"protocolVersion": mcpProtocolVersion,
+		"capabilities":    map[string]interface{}{},
+		"clientInfo": map[string]interface{}{
+			"name":    "ugudu",
+			"version": version.Version,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return c.transport.notify(ctx, rpcMessage{JSONRPC: "2.0", Method: "notifications/initialized"})
+}
+
+// request sends a request and returns its result
+func (c *MCPClient) request(ctx context.Context, method string, params interface{}) (json.RawMessage, error) {
+	id := json.RawMessage(fmt.Sprint(c.nextID.Add(1)))
+	resp, err := c.transport.call(ctx, rpcMessage{JSONRPC: "2.0", ID: id, Method: method, Params: params})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Error != nil {
+		return nil, resp.Error
+	}
+	return resp.Result, nil
+}
+
+// ListTools returns the server's tools, following pagination
+func (c *MCPClient) ListTools(ctx context.Context) ([]*MCPTool, error) {
+	var tools []*MCPTool
+	cursor := ""
+	for {
+		params := map[string]interface{}{}
+		if cursor != "" {
+			params["cursor"] = cursor
+		}
+		raw, err := c.request(ctx, "tools/list", params)
+		if err != nil {
+			return nil, fmt.Errorf("mcp server %s: list tools: %w", c.name, err)
+		}
+
+		var page struct {
+			Tools []struct {
+				Name        string                 `json:"name"`
+				Description string                 `json:"description"`
+				InputSchema map[string]interface{} `json:"inputSchema"`
+			} `json:"tools"`
+			NextCursor string `json:"nextCursor"`
+		}
+		if err := json.Unmarshal(raw, &page); err != nil {
+			return nil, fmt.Errorf("mcp server %s: list tools: %w", c.name, err)
+		}
+		for _, t := range page.Tools {
+			tools = append(tools, &MCPTool{
+				Server:      c.name,
+				Tool:        t.Name,
+				description: t.Description,
+				schema:      t.InputSchema,
+				client:      c,
+			})
+		}
+
+		if page.NextCursor == "" || page.NextCursor == cursor {
+			return tools, nil
+		}
+		cursor = page.NextCursor
+	}
+}
+
+// CallTool runs a tool on the server and returns its content: its text, and
+// any images it sent. A tool that reports an error is returned as one.
+func (c *MCPClient) CallTool(ctx context.Context, name string, args map[string]interface{}) (*Content, error) {
+	if args == nil {
+		args = map[string]interface{}{}
+	}
+	raw, err := c.request(ctx, "tools/call", map[string]interface{}{
+		"name":      name,
+		"arguments": args,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("mcp server %s: %s: %w", c.name, name, err)
+	}
+
+	var result struct {
+		Content []mcpContent `json:"content"`
+		IsError bool         `json:"isError"`
+	}
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, fmt.Errorf("mcp server %s: %s: %w", c.name, name, err)
+	}
+
+	content := &Content{}
+	parts := make([]string, 0, len(result.Content))
+	for _, item := range result.Content {
+		if item.Type == "image" && item.Data != "" {
+			content.Attachments = append(content.Attachments, Attachment{MimeType: item.MimeType, Data: item.Data})
+			continue
+		}
+		parts = append(parts, item.text())
+	}
+	content.Text = strings.Join(parts, "\n")
+	if result.IsError {
+		return nil, fmt.Errorf("%s: %s", name, content.Text)
+	}
+	return content, nil
+}
+
+// Close ends the session and stops the server if the client started it
+func (c *MCPClient) Close() error {
+	return c.transport.close()
+}
+
+// mcpContent is one item of a tool result
+type mcpContent struct {
+	Type     string `json:"type"`
+	Text     string `json:"text"`
+	Data     string `json:"data"` // Base64, for images
+	MimeType string `json:"mimeType"`
+	Resource *struct {
+		URI  string `json:"uri"`
+		Text string `json:"text"`
+	} `json:"resource"`
+}
+
+// text renders a content item for a model. Only text is passed through;
+// other kinds are described.
+func (c mcpContent) text() string {
+	switch c.Type {
+	case "text":
+		return c.Text
+	case "resource":
+		if c.Resource != nil {
+			if c.Resource.Text != "" {
+				return c.Resource.Text
+			}
+			return fmt.Sprintf("[resource: %s]", c.Resource.URI)
+		}
+	}
+	if c.MimeType != "" {
+		return fmt.Sprintf("[%s: %s]", c.Type, c.MimeType)
+	}
+	return fmt.Sprintf("[%s]", c.Type)
+}
+
+// MCPTool is a tool served by an external MCP server. Members see it as
+// <server>__<tool>.
+type MCPTool struct {
+	Server string // Name the server was configured under
+	Tool   string // Name on the server
+
+	description string
+	schema      map[string]interface{}
+	client      *MCPClient
+}
+
+var unsafeToolChars = regexp.MustCompile(`[^a-zA-Z0-9_-]`)
+
+// Name returns the tool's name for members, limited to what providers accept
+func (t *MCPTool) Name() string {
+	name := unsafeToolChars.ReplaceAllString(t.Server+"__"+t.Tool, "_")
+	if len(name) > 64 {
+		name = name[:64]
+	}
+	return name
+}
+
+func (t *MCPTool) Description() string {
+	if t.description == "" {
+		return fmt.Sprintf("%s (from MCP server %s)", t.Tool, t.Server)
+	}
+	return t.description
+}
+
+// Parameters returns the input schema the server advertised
+func (t *MCPTool) Parameters() map[string]interface{} {
+	if t.schema == nil {
+		return map[string]interface{}{"type": "object", "properties": map[string]interface{}{}}
+	}
+	return t.schema
+}
+
+func (t *MCPTool) Execute(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	content, err := t.client.CallTool(ctx, t.Tool, args)
+	if err != nil {
+		return nil, err
+	}
+	if len(content.Attachments) == 0 {
+		return content.Text, nil
+	}
+	return content, nil
+}
+
+// stdioTransport talks to a server subprocess over its stdin and stdout, one
+// JSON message per line
+type stdioTransport struct {
+	cmd     *exec.Cmd
+	stdin   io.WriteCloser
+	writeMu sync.Mutex
+
+	mu      sync.Mutex
+	pending map[string]chan rpcMessage
+	done    chan struct{} // Closed when the server's output ends
+	err     error         // Why it ended
+}
+
+func startStdioTransport(command []string, env map[string]string) (*stdioTransport, error) {
+	cmd := exec.Command(command[0], command[1:]...)
+	cmd.Env = os.Environ()
+	for k, v := range env {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("start %s: %w", command[0], err)
+	}
+
+	t := &stdioTransport{
+		cmd:     cmd,
+		stdin:   stdin,
+		pending: make(map[string]chan rpcMessage),
+		done:    make(chan struct{}),
+	}
+	go t.read(stdout)
+	return t, nil
+}
+
+// read delivers responses to their callers until the server's output ends
+func (t *stdioTransport) read(stdout io.Reader) {
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var msg rpcMessage
+		if err := json.Unmarshal(scanner.Bytes(), &msg); err != nil {
+			continue // Not a message, e.g. stray logging
+		}
+		if msg.Method != "" {
+			if len(msg.ID) > 0 {
+				t.answer(msg)
+			}
+			continue
+		}
+
+		t.mu.Lock()
+		ch, ok := t.pending[string(msg.ID)]
+		delete(t.pending, string(msg.ID))
+		t.mu.Unlock()
+		if ok {
+			ch <- msg
+		}
+	}
+
+	t.mu.Lock()
+	t.err = scanner.Err()
+	if t.err == nil {
+		t.err = errors.New("server exited")
+	}
+	t.mu.Unlock()
+	close(t.done)
+}
+
+// answer replies to a request from the server. Only ping is supported.
+func (t *stdioTransport) answer(req rpcMessage) {
+	resp := rpcMessage{JSONRPC: "2.0", ID: req.ID}
+	if req.Method == "ping" {
+		resp.Result = json.RawMessage("{}")
+	} else {
+		resp.Error = &rpcError{Code: -32601, Message: "Method not found"}
+	}
+	t.write(resp)
+}
+
+func (t *stdioTransport) write(msg rpcMessage) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	t.writeMu.Lock()
+	defer t.writeMu.Unlock()
+	_, err = t.stdin.Write(append(data, '\n'))
+	return err
+}
+
+func (t *stdioTransport) call(ctx context.Context, msg rpcMessage) (rpcMessage, error) {
+	ch := make(chan rpcMessage, 1)
+	t.mu.Lock()
+	t.pending[string(msg.ID)] = ch
+	t.mu.Unlock()
+	defer func() {
+		t.mu.Lock()
+		delete(t.pending, string(msg.ID))
+		t.mu.Unlock()
+	}()
+
+	if err := t.write(msg); err != nil {
+		return rpcMessage{}, err
+	}
+
+	select {
+	case resp := <-ch:
+		return resp, nil
+	case <-t.done:
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		return rpcMessage{}, t.err
+	case <-ctx.Done():
+		return rpcMessage{}, ctx.Err()
+	}
+}
+
+func (t *stdioTransport) notify(_ context.Context, msg rpcMessage) error {
+	return t.write(msg)
+}
+
+// close ends the server's input, which tells it to exit, and kills it if it
+// hasn't within a few seconds
+func (t *stdioTransport) close() error {
+	t.stdin.Close()
+	select {
+	case <-t.done:
+	case <-time.After(3 * time.Second):
+		t.cmd.Process.Kill()
+	}
+	t.cmd.Wait()
+	return nil
+}
+
+// httpTransport talks to a server over MCP's streamable HTTP transport
+type httpTransport struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+
+	mu      sync.Mutex
+	session string // Mcp-Session-Id assigned by the server
+}
+
+func newHTTPTransport(url string, headers map[string]string) *httpTransport {
+	return &httpTransport{url: url, headers: headers, client: &http.Client{}}
+}
+
+func (t *httpTransport) newRequest(ctx context.Context, method string, body []byte) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, t.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	for k, v := range t.headers {
+		req.Header.Set(k, v)
+	}
+	t.mu.Lock()
+	if t.session != "" {
+		req.Header.Set("Mcp-Session-Id", t.session)
+	}
+	t.mu.Unlock()
+	return req, nil
+}
+
+// post sends a message and returns the HTTP response, keeping any session ID
+// the server assigns
+func (t *httpTransport) post(ctx context.Context, msg rpcMessage) (*http.Response, error) {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	req, err := t.newRequest(ctx, http.MethodPost, body)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if id := resp.Header.Get("Mcp-Session-Id"); id != "" {
+		t.mu.Lock()
+		t.session = id
+		t.mu.Unlock()
+	}
+	if resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		resp.Body.Close()
+		return nil, fmt.Errorf("http %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	return resp, nil
+}
+
+func (t *httpTransport) call(ctx context.Context, msg rpcMessage) (rpcMessage, error) {
+	resp, err := t.post(ctx, msg)
+	if err != nil {
+		return rpcMessage{}, err
+	}
+	defer resp.Body.Close()
+
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		return readEventStream(resp.Body, msg.ID)
+	}
+	var reply rpcMessage
+	if err := json.NewDecoder(resp.Body).Decode(&reply); err != nil {
+		return rpcMessage{}, fmt.Errorf("invalid response: %w", err)
+	}
+	return reply, nil
+}
+
+// readEventStream reads server-sent events until the response to the
+// request with the given ID arrives
+func readEventStream(r io.Reader, id json.RawMessage) (rpcMessage, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	var data strings.Builder
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "data:") {
+			data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+			continue
+		}
+		if line != "" || data.Len() == 0 {
+			continue
+		}
+
+		// A blank line ends the event
+		var msg rpcMessage
+		err := json.Unmarshal([]byte(data.String()), &msg)
+		data.Reset()
+		if err == nil && msg.Method == "" && string(msg.ID) == string(id) {
+			return msg, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return rpcMessage{}, err
+	}
+	return rpcMessage{}, errors.New("event stream ended without a response")
+}
+
+func (t *httpTransport) notify(ctx context.Context, msg rpcMessage) error {
+	resp, err := t.post(ctx, msg)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// close ends the session on the server, if it assigned one
+func (t *httpTransport) close() error {
+	t.mu.Lock()
+	session := t.session
+	t.mu.Unlock()
+	if session == "" {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, err := t.newRequest(ctx, http.MethodDelete, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/arcslash/ugudu/internal/workspace"
 	"github.com/google/uuid"
 )
 
@@ -143,6 +144,102 @@ func (s *FileTaskStore) Delete(id string) error {
 	return fmt.Errorf("task not found: %s", id)
 }
 
+// WorkspaceTaskStore adapts a project's workspace.TaskStore to TaskStore, so
+// agent tools read and write the same board the API and standups use
+type WorkspaceTaskStore struct {
+	Tasks *workspace.TaskStore
+}
+
+func (s *WorkspaceTaskStore) List() ([]Task, error) {
+	tasks, err := s.Tasks.List()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]Task, len(tasks))
+	for i := range tasks {
+		result[i] = fromWorkspaceTask(&tasks[i])
+	}
+	return result, nil
+}
+
+func (s *WorkspaceTaskStore) Get(id string) (*Task, error) {
+	wt, err := s.Tasks.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	task := fromWorkspaceTask(wt)
+	return &task, nil
+}
+
+func (s *WorkspaceTaskStore) Create(task *Task) error {
+	wt := toWorkspaceTask(task, nil)
+	if err := s.Tasks.Create(wt); err != nil {
+		return err
+	}
+	*task = fromWorkspaceTask(wt)
+	return nil
+}
+
+func (s *WorkspaceTaskStore) Update(task *Task) error {
+	// Start from the stored task so fields tools don't know about, like
+	// comments, survive the update
+	existing, err := s.Tasks.Get(task.ID)
+	if err != nil {
+		return err
+	}
+	wt := toWorkspaceTask(task, existing)
+	if err := s.Tasks.Update(wt); err != nil {
+		return err
+	}
+	task.UpdatedAt = wt.UpdatedAt
+	return nil
+}
+
+func (s *WorkspaceTaskStore) Delete(id string) error {
+	return s.Tasks.Delete(id)
+}
+
+// AddComment records a note on a task
+func (s *WorkspaceTaskStore) AddComment(taskID, author, content string) error {
+	return s.Tasks.AddComment(taskID, author, content)
+}
+
+func fromWorkspaceTask(wt *workspace.Task) Task {
+	return Task{
+		ID:          wt.ID,
+		Title:       wt.Title,
+		Description: wt.Description,
+		Status:      wt.Status,
+		Priority:    wt.Priority,
+		AssignedTo:  wt.AssignedTo,
+		CreatedBy:   wt.CreatedBy,
+		CreatedAt:   wt.CreatedAt,
+		UpdatedAt:   wt.UpdatedAt,
+		DueDate:     wt.DueDate,
+		Tags:        wt.Tags,
+		Metadata:    wt.Metadata,
+	}
+}
+
+// toWorkspaceTask copies task onto base (or a new task if base is nil)
+func toWorkspaceTask(task *Task, base *workspace.Task) *workspace.Task {
+	wt := base
+	if wt == nil {
+		wt = &workspace.Task{}
+	}
+	wt.ID = task.ID
+	wt.Title = task.Title
+	wt.Description = task.Description
+	wt.Status = task.Status
+	wt.Priority = task.Priority
+	wt.AssignedTo = task.AssignedTo
+	wt.CreatedBy = task.CreatedBy
+	wt.DueDate = task.DueDate
+	wt.Tags = task.Tags
+	wt.Metadata = task.Metadata
+	return wt
+}
+
 // ============================================================================
 // Planning Tools
 // ============================================================================
@@ -262,6 +359,9 @@ func (t *ListTasksTool) Execute(ctx context.Context, args map[string]interface{}
 	// Apply filters
 	status, hasStatus := args["status"].(string)
 	assignee, hasAssignee := args["assigned_to"].(string)
+	if canonical, ok := taskStatuses[status]; ok {
+		status = canonical
+	}
 
 	var filtered []Task
 	for _, task := range tasks {
@@ -280,6 +380,67 @@ func (t *ListTasksTool) Execute(ctx context.Context, args map[string]interface{}
 	}, nil
 }
 
+// Task statuses an agent may set with update_task_status
+var taskStatuses = map[string]string{
+	"pending":     "pending",
+	"in_progress": "in_progress",
+	"blocked":     "blocked",
+	"completed":   "completed",
+	"done":        "completed", // Agents often say "done"; the board calls it completed
+}
+
+// UpdateTaskStatusTool moves a task across the board
+type UpdateTaskStatusTool struct {
+	Store     TaskStore
+	UpdatedBy string
+}
+
+func (t *UpdateTaskStatusTool) Name() string { return "update_task_status" }
+func (t *UpdateTaskStatusTool) Description() string {
+	return "Move a task on the project board to pending, in_progress, blocked or completed, with an optional note"
+}
+
+func (t *UpdateTaskStatusTool) Execute(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	id, ok := args["id"].(string)
+	if !ok || id == "" {
+		return nil, fmt.Errorf("id is required")
+	}
+
+	requested, _ := args["status"].(string)
+	status, ok := taskStatuses[requested]
+	if !ok {
+		return nil, fmt.Errorf("status must be one of pending, in_progress, blocked, completed")
+	}
+
+	task, err := t.Store.Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	oldStatus := task.Status
+	task.Status = status
+	if err := t.Store.Update(task); err != nil {
+		return nil, fmt.Errorf("update task status: %w", err)
+	}
+
+	if note, ok := args["note"].(string); ok && note != "" {
+		if commenter, ok := t.Store.(interface {
+			AddComment(taskID, author, content string) error
+		}); ok {
+			if err := commenter.AddComment(id, t.UpdatedBy, note); err != nil {
+				return nil, fmt.Errorf("add note: %w", err)
+			}
+		}
+	}
+
+	return map[string]interface{}{
+		"id":         task.ID,
+		"title":      task.Title,
+		"old_status": oldStatus,
+		"status":     task.Status,
+	}, nil
+}
+
 // AssignTaskTool assigns a task to a team member
 type AssignTaskTool struct {
 	Store      TaskStore
@@ -0,0 +1,76 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/arcslash/ugudu/internal/workspace"
+)
+
+// GetStandupTool reports on recent work in the team's project, from the same
+// activity and task board as `ugudu project standup`
+type GetStandupTool struct {
+	Workspace *workspace.Workspace
+}
+
+func (t *GetStandupTool) Name() string { return "get_standup" }
+func (t *GetStandupTool) Description() string {
+	return "Get the project's standup: what the team did, what's blocked and what's next, for the last day or week"
+}
+
+func (t *GetStandupTool) Execute(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	period := workspace.PeriodDaily
+	if p, ok := args["period"].(string); ok && p != "" {
+		period = workspace.StandupPeriod(p)
+	}
+	if period != workspace.PeriodDaily && period != workspace.PeriodWeekly {
+		return nil, fmt.Errorf("period must be daily or weekly, got %q", period)
+	}
+
+	generator := workspace.NewStandupGenerator(t.Workspace)
+	report, err := generator.Generate(period)
+	if err != nil {
+		return nil, fmt.Errorf("generate standup: %w", err)
+	}
+
+	return map[string]interface{}{
+		"project": t.Workspace.Name,
+		"period":  period,
+		"standup": generator.FormatReport(report, true),
+	}, nil
+}
+
+// GetProjectTasksTool shows the team's project board: every task grouped by
+// status, with counts
+type GetProjectTasksTool struct {
+	Workspace *workspace.Workspace
+}
+
+func (t *GetProjectTasksTool) Name() string { return "get_project_tasks" }
+func (t *GetProjectTasksTool) Description() string {
+	return "Get the project's task board: tasks grouped by status, with counts by status, priority and assignee"
+}
+
+func (t *GetProjectTasksTool) Execute(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	store := t.Workspace.Tasks()
+	tasks, err := store.List()
+	if err != nil {
+		return nil, fmt.Errorf("list tasks: %w", err)
+	}
+	stats, err := store.Stats()
+	if err != nil {
+		return nil, fmt.Errorf("task stats: %w", err)
+	}
+
+	board := make(map[string][]Task)
+	for i := range tasks {
+		task := fromWorkspaceTask(&tasks[i])
+		board[task.Status] = append(board[task.Status], task)
+	}
+
+	return map[string]interface{}{
+		"project": t.Workspace.Name,
+		"board":   board,
+		"stats":   stats,
+	}, nil
+}
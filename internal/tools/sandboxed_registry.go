@@ -16,6 +16,10 @@ type SandboxedRegistry struct {
 	role      string
 	agentID   string
 	workspace *workspace.Workspace
+	container *containerSandbox // Runs commands in a container when set
+	env       map[string]string // Added to the environment of the agent's commands
+
+	mcpServers map[string]bool // External MCP servers whose tools the role may call
 
 	// Activity logging callback
 	OnToolExecute func(toolName string, args map[string]interface{}, result interface{}, err error)
@@ -37,14 +41,57 @@ func NewSandboxedRegistry(base *Registry, ws *workspace.Workspace, role, agentID
 	}
 }
 
+// SetContainer runs the agent's commands in containers from cfg. Only the
+// agent's source, sandbox and artifact directories are mounted, and file
+// tools are confined to them as well.
+func (r *SandboxedRegistry) SetContainer(cfg ContainerConfig) {
+	var dirs []string
+	if r.sandbox != nil {
+		if src := r.sandbox.SourcePath(); src != "" {
+			dirs = append(dirs, src)
+		}
+		dirs = append(dirs, r.sandbox.SandboxPath())
+	}
+	if r.workspace != nil {
+		dirs = append(dirs, r.workspace.ArtifactPath(""))
+	}
+	if len(dirs) == 0 {
+		dirs = append(dirs, resolveSafePath("."))
+	}
+	r.container = &containerSandbox{ContainerConfig: cfg, dirs: dirs}
+}
+
+// SetEnv adds env to the environment of the agent's commands. Values of
+// secret-looking variables are redacted from the commands' output.
+func (r *SandboxedRegistry) SetEnv(env map[string]string) {
+	r.env = env
+}
+
+// AllowMCPServers lets the role call the tools of the named MCP servers.
+// Tools from any other server are hidden from it.
+func (r *SandboxedRegistry) AllowMCPServers(names []string) {
+	r.mcpServers = make(map[string]bool, len(names))
+	for _, name := range names {
+		r.mcpServers[name] = true
+	}
+}
+
+// allowed reports whether the role may use a tool. MCP tools are allowed
+// per server, everything else by category.
+func (r *SandboxedRegistry) allowed(tool Tool) bool {
+	if mcp, ok := tool.(*MCPTool); ok {
+		return r.mcpServers[mcp.Server]
+	}
+	return IsToolAllowedForRole(tool.Name(), r.role)
+}
+
 // Get returns a tool by name if the role has access
 func (r *SandboxedRegistry) Get(name string) (Tool, bool) {
-	// Check role permission
-	if !IsToolAllowedForRole(name, r.role) {
+	tool, ok := r.base.Get(name)
+	if !ok || !r.allowed(tool) {
 		return nil, false
 	}
-
-	return r.base.Get(name)
+	return tool, true
 }
 
 // List returns all tools available to the role
@@ -53,7 +100,7 @@ func (r *SandboxedRegistry) List() []Tool {
 	var allowed []Tool
 
 	for _, tool := range allTools {
-		if IsToolAllowedForRole(tool.Name(), r.role) {
+		if r.allowed(tool) {
 			allowed = append(allowed, tool)
 		}
 	}
@@ -61,10 +108,25 @@ func (r *SandboxedRegistry) List() []Tool {
 	return allowed
 }
 
+type callerKey struct{}
+
+// CallerID returns the ID of the agent running a tool. Tools are shared by
+// every member of a team, so this is how one tells who called it.
+func CallerID(ctx context.Context) string {
+	id, _ := ctx.Value(callerKey{}).(string)
+	return id
+}
+
 // Execute runs a tool with sandbox enforcement
 func (r *SandboxedRegistry) Execute(ctx context.Context, name string, args map[string]interface{}) (interface{}, error) {
+	ctx = context.WithValue(ctx, callerKey{}, r.agentID)
+
 	// Check role permission
-	if !IsToolAllowedForRole(name, r.role) {
+	allowed := IsToolAllowedForRole(name, r.role)
+	if tool, ok := r.base.Get(name); ok {
+		allowed = r.allowed(tool)
+	}
+	if !allowed {
 		err := fmt.Errorf("tool %s is not available for role %s", name, r.role)
 		if r.OnToolExecute != nil {
 			r.OnToolExecute(name, args, nil, err)
@@ -77,8 +139,27 @@ func (r *SandboxedRegistry) Execute(ctx context.Context, name string, args map[s
 		args = r.sandboxArgs(name, args)
 	}
 
+	if r.container != nil {
+		confined, err := r.confine(name, args)
+		if err != nil {
+			if r.OnToolExecute != nil {
+				r.OnToolExecute(name, args, nil, err)
+			}
+			return nil, err
+		}
+		args = confined
+		ctx = context.WithValue(ctx, containerKey{}, r.container)
+	}
+
+	if len(r.env) > 0 {
+		ctx = context.WithValue(ctx, envKey{}, r.env)
+	}
+
 	// Execute the tool
 	result, err := r.base.Execute(ctx, name, args)
+	if err == nil {
+		r.recordArtifact(name, args, result)
+	}
 
 	// Log the execution
 	if r.OnToolExecute != nil {
@@ -113,6 +194,51 @@ func (r *SandboxedRegistry) sandboxArgs(toolName string, args map[string]interfa
 	return newArgs
 }
 
+// confine resolves path arguments against the container's first directory
+// and rejects any outside the mounted directories
+func (r *SandboxedRegistry) confine(toolName string, args map[string]interface{}) (map[string]interface{}, error) {
+	newArgs := make(map[string]interface{})
+	for k, v := range args {
+		newArgs[k] = v
+	}
+	if _, ok := newArgs["directory"]; !ok && toolName == "run_command" {
+		newArgs["directory"] = r.container.dirs[0]
+	}
+
+	for _, key := range []string{"path", "file", "directory", "root", "target"} {
+		path, ok := newArgs[key].(string)
+		if !ok || path == "" {
+			continue
+		}
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(r.container.dirs[0], path)
+		}
+		path = filepath.Clean(path)
+		if !r.container.contains(path) {
+			return nil, fmt.Errorf("%w: %s", workspace.ErrOutsideSandbox, path)
+		}
+		newArgs[key] = path
+	}
+	return newArgs, nil
+}
+
+// recordArtifact notes a file the agent wrote in the workspace, so it can be
+// downloaded after the run. It's best effort: a failure doesn't fail the tool.
+func (r *SandboxedRegistry) recordArtifact(toolName string, args map[string]interface{}, result interface{}) {
+	if r.workspace == nil || (toolName != "write_file" && toolName != "edit_file") {
+		return
+	}
+	path, _ := args["path"].(string)
+	if out, ok := result.(map[string]interface{}); ok {
+		if written, ok := out["path"].(string); ok {
+			path = written
+		}
+	}
+	if path != "" {
+		r.workspace.RecordArtifact(path, r.agentID)
+	}
+}
+
 // getOperationType determines if a tool performs read or write operations
 func (r *SandboxedRegistry) getOperationType(toolName string) string {
 	writeTools := map[string]bool{
@@ -121,6 +247,7 @@ func (r *SandboxedRegistry) getOperationType(toolName string) string {
 		"git_commit":         true,
 		"create_task":        true,
 		"update_task":        true,
+		"update_task_status": true,
 		"create_report":      true,
 		"create_doc":         true,
 		"create_requirement": true,
@@ -155,22 +282,26 @@ func (r *SandboxedRegistry) RegisterRoleTools() {
 	}
 
 	artifactPath := r.workspace.ArtifactPath("")
-	tasksPath := r.workspace.TasksPath()
 	sourcePath := ""
 	if r.workspace.Config != nil {
 		sourcePath = r.workspace.Config.Source.Path
 	}
 
-	taskStore := NewFileTaskStore(tasksPath)
+	taskStore := &WorkspaceTaskStore{Tasks: r.workspace.Tasks()}
 
 	// Register planning tools
 	r.base.Register(&CreateTaskTool{Store: taskStore, CreatedBy: r.agentID})
 	r.base.Register(&UpdateTaskTool{Store: taskStore, UpdatedBy: r.agentID})
 	r.base.Register(&ListTasksTool{Store: taskStore})
+	r.base.Register(&UpdateTaskStatusTool{Store: taskStore, UpdatedBy: r.agentID})
 	r.base.Register(&AssignTaskTool{Store: taskStore, AssignedBy: r.agentID})
 	r.base.Register(&CreateReportTool{ArtifactPath: artifactPath, CreatedBy: r.agentID})
 	r.base.Register(&DelegateTaskTool{Store: taskStore, DelegatedBy: r.agentID})
 
+	// Register project views, scoped to the team's project
+	r.base.Register(&GetStandupTool{Workspace: r.workspace})
+	r.base.Register(&GetProjectTasksTool{Workspace: r.workspace})
+
 	// Register testing tools
 	r.base.Register(&RunTestsTool{WorkingDir: sourcePath, ArtifactPath: artifactPath})
 	r.base.Register(&CreateBugReportTool{ArtifactPath: artifactPath, ReportedBy: r.agentID})
@@ -0,0 +1,283 @@
+package tools
+
+// builtinSchemas are the JSON schemas of the built-in tools' arguments, by
+// tool name. They're offered to the model and checked by ValidateArgs.
+var builtinSchemas = map[string]map[string]interface{}{
+	"read_file": {
+		"type": "object",
+		"properties": map[string]interface{}{
+			"path": map[string]interface{}{
+				"type":        "string",
+				"description": "Path to the file to read",
+			},
+			"offset": map[string]interface{}{
+				"type":        "integer",
+				"description": "Line to start reading from, counting from 1 (default 1)",
+			},
+			"limit": map[string]interface{}{
+				"type":        "integer",
+				"description": "Maximum number of lines to read (default: the rest of the file). Use with offset to page through large files.",
+			},
+		},
+		"required": []string{"path"},
+	},
+	"write_file": {
+		"type": "object",
+		"properties": map[string]interface{}{
+			"path": map[string]interface{}{
+				"type":        "string",
+				"description": "Path to the file to write",
+			},
+			"content": map[string]interface{}{
+				"type":        "string",
+				"description": "Content to write to the file",
+			},
+		},
+		"required": []string{"path", "content"},
+	},
+	"edit_file": {
+		"type": "object",
+		"properties": map[string]interface{}{
+			"path": map[string]interface{}{
+				"type":        "string",
+				"description": "Path to the file to edit",
+			},
+			"old_text": map[string]interface{}{
+				"type":        "string",
+				"description": "Text to find and replace",
+			},
+			"new_text": map[string]interface{}{
+				"type":        "string",
+				"description": "Text to replace with",
+			},
+		},
+		"required": []string{"path", "old_text", "new_text"},
+	},
+	"list_files": {
+		"type": "object",
+		"properties": map[string]interface{}{
+			"path": map[string]interface{}{
+				"type":        "string",
+				"description": "Directory path to list (default: current directory)",
+			},
+		},
+	},
+	"search_files": {
+		"type": "object",
+		"properties": map[string]interface{}{
+			"pattern": map[string]interface{}{
+				"type":        "string",
+				"description": "Glob pattern to match files (e.g., *.go)",
+			},
+			"root": map[string]interface{}{
+				"type":        "string",
+				"description": "Root directory to search from",
+			},
+		},
+		"required": []string{"pattern"},
+	},
+	"run_command": {
+		"type": "object",
+		"properties": map[string]interface{}{
+			"command": map[string]interface{}{
+				"type":        "string",
+				"description": "Shell command to execute",
+			},
+			"directory": map[string]interface{}{
+				"type":        "string",
+				"description": "Working directory for the command",
+			},
+			"timeout": map[string]interface{}{
+				"type":        "number",
+				"description": "Timeout in seconds (default: 60)",
+			},
+		},
+		"required": []string{"command"},
+	},
+	"git_status": {
+		"type":       "object",
+		"properties": map[string]interface{}{},
+	},
+	"git_diff": {
+		"type": "object",
+		"properties": map[string]interface{}{
+			"staged": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Show staged changes only",
+			},
+		},
+	},
+	"git_commit": {
+		"type": "object",
+		"properties": map[string]interface{}{
+			"message": map[string]interface{}{
+				"type":        "string",
+				"description": "Commit message",
+			},
+			"files": map[string]interface{}{
+				"type":        "array",
+				"items":       map[string]interface{}{"type": "string"},
+				"description": "Files to stage and commit",
+			},
+		},
+		"required": []string{"message"},
+	},
+	"create_task": {
+		"type": "object",
+		"properties": map[string]interface{}{
+			"title": map[string]interface{}{
+				"type":        "string",
+				"description": "Task title",
+			},
+			"description": map[string]interface{}{
+				"type":        "string",
+				"description": "Task description",
+			},
+			"priority": map[string]interface{}{
+				"type":        "string",
+				"enum":        []string{"low", "medium", "high", "critical"},
+				"description": "Task priority",
+			},
+			"assignee": map[string]interface{}{
+				"type":        "string",
+				"description": "Role to assign the task to",
+			},
+		},
+		"required": []string{"title", "description"},
+	},
+	"list_tasks": {
+		"type": "object",
+		"properties": map[string]interface{}{
+			"status": map[string]interface{}{
+				"type":        "string",
+				"enum":        []string{"pending", "in_progress", "blocked", "completed"},
+				"description": "Only list tasks with this status",
+			},
+			"assigned_to": map[string]interface{}{
+				"type":        "string",
+				"description": "Only list tasks assigned to this role",
+			},
+		},
+	},
+	"update_task_status": {
+		"type": "object",
+		"properties": map[string]interface{}{
+			"id": map[string]interface{}{
+				"type":        "string",
+				"description": "ID of the task, from list_tasks",
+			},
+			"status": map[string]interface{}{
+				"type":        "string",
+				"enum":        []string{"pending", "in_progress", "blocked", "completed"},
+				"description": "New status; use completed when the work is done",
+			},
+			"note": map[string]interface{}{
+				"type":        "string",
+				"description": "Optional note to add to the task, e.g. what was done",
+			},
+		},
+		"required": []string{"id", "status"},
+	},
+	"get_standup": {
+		"type": "object",
+		"properties": map[string]interface{}{
+			"period": map[string]interface{}{
+				"type":        "string",
+				"enum":        []string{"daily", "weekly"},
+				"description": "Report on the last day or the last week (default daily)",
+			},
+		},
+	},
+	"get_project_tasks": {
+		"type":       "object",
+		"properties": map[string]interface{}{},
+	},
+	"run_tests": {
+		"type": "object",
+		"properties": map[string]interface{}{
+			"pattern": map[string]interface{}{
+				"type":        "string",
+				"description": "Test pattern to run (e.g., ./... or specific path)",
+			},
+			"verbose": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Enable verbose output",
+			},
+		},
+	},
+	"http_request": {
+		"type": "object",
+		"properties": map[string]interface{}{
+			"url": map[string]interface{}{
+				"type":        "string",
+				"description": "URL to request",
+			},
+			"method": map[string]interface{}{
+				"type":        "string",
+				"enum":        []string{"GET", "POST", "PUT", "DELETE", "PATCH"},
+				"description": "HTTP method (default: GET)",
+			},
+			"body": map[string]interface{}{
+				"type":        "string",
+				"description": "Request body",
+			},
+			"headers": map[string]interface{}{
+				"type":        "object",
+				"description": "Request headers",
+			},
+		},
+		"required": []string{"url"},
+	},
+	"ask_client": {
+		"type": "object",
+		"properties": map[string]interface{}{
+			"question": map[string]interface{}{
+				"type":        "string",
+				"description": "Question for the client; you wait until they answer it",
+			},
+		},
+		"required": []string{"question"},
+	},
+	"get_current_time": {
+		"type": "object",
+		"properties": map[string]interface{}{
+			"timezone": map[string]interface{}{
+				"type":        "string",
+				"description": "IANA timezone to give the time in, e.g. America/New_York (default: the team's)",
+			},
+		},
+	},
+	"report_progress": {
+		"type": "object",
+		"properties": map[string]interface{}{
+			"status": map[string]interface{}{
+				"type":        "string",
+				"description": "Where the work stands: in_progress, blocked or completed",
+			},
+			"percent_complete": map[string]interface{}{
+				"type":        "number",
+				"description": "How much of the current task is done, 0-100",
+			},
+			"message": map[string]interface{}{
+				"type":        "string",
+				"description": "Short status line shown to the client",
+			},
+		},
+		"required": []string{"status"},
+	},
+}
+
+// Schema returns the JSON schema of a tool's arguments: its own for a
+// SchemaTool, the built-in one for a built-in tool, or an open object
+func Schema(t Tool) map[string]interface{} {
+	if st, ok := t.(SchemaTool); ok {
+		return st.Parameters()
+	}
+	if schema, ok := builtinSchemas[t.Name()]; ok {
+		return schema
+	}
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": map[string]interface{}{},
+	}
+}
@@ -83,15 +83,9 @@ func (t *RunTestsTool) Execute(ctx context.Context, args map[string]interface{})
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
-	cmd := exec.CommandContext(ctx, "sh", "-c", command)
-	cmd.Dir = t.WorkingDir
-
 	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
 	startTime := time.Now()
-	err := cmd.Run()
+	err := runShell(ctx, t.WorkingDir, command, &stdout, &stderr)
 	duration := time.Since(startTime).Milliseconds()
 
 	// Parse output for results
@@ -11,7 +11,9 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -22,8 +24,17 @@ type Tool interface {
 	Execute(ctx context.Context, args map[string]interface{}) (interface{}, error)
 }
 
+// SchemaTool is implemented by tools that describe their own arguments, such
+// as those from external MCP servers
+type SchemaTool interface {
+	Tool
+	// Parameters returns the JSON schema of the tool's arguments
+	Parameters() map[string]interface{}
+}
+
 // Registry holds available tools
 type Registry struct {
+	mu    sync.RWMutex
 	tools map[string]Tool
 }
 
@@ -47,27 +58,44 @@ func NewRegistry() *Registry {
 
 // Register adds a tool to the registry
 func (r *Registry) Register(t Tool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	r.tools[t.Name()] = t
 }
 
+// Unregister removes a tool from the registry
+func (r *Registry) Unregister(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.tools, name)
+}
+
 // Get returns a tool by name
 func (r *Registry) Get(name string) (Tool, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 	t, ok := r.tools[name]
 	return t, ok
 }
 
-// List returns all registered tools
+// List returns all registered tools, sorted by name so requests that
+// include them are the same every time
 func (r *Registry) List() []Tool {
+	r.mu.RLock()
 	tools := make([]Tool, 0, len(r.tools))
 	for _, t := range r.tools {
 		tools = append(tools, t)
 	}
+	r.mu.RUnlock()
+	sort.Slice(tools, func(i, j int) bool {
+		return tools[i].Name() < tools[j].Name()
+	})
 	return tools
 }
 
 // Execute runs a tool by name
 func (r *Registry) Execute(ctx context.Context, name string, args map[string]interface{}) (interface{}, error) {
-	t, ok := r.tools[name]
+	t, ok := r.Get(name)
 	if !ok {
 		return nil, fmt.Errorf("tool not found: %s", name)
 	}
@@ -101,10 +129,41 @@ func (t *ReadFileTool) Execute(ctx context.Context, args map[string]interface{})
 		return nil, fmt.Errorf("read file: %w", err)
 	}
 
+	_, hasOffset := args["offset"].(float64)
+	_, hasLimit := args["limit"].(float64)
+	if !hasOffset && !hasLimit {
+		return map[string]interface{}{
+			"path":    absPath,
+			"content": string(content),
+			"size":    len(content),
+		}, nil
+	}
+
+	// Page through the file by line
+	lines := strings.SplitAfter(string(content), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	start := 1
+	if o, ok := args["offset"].(float64); ok && o > 1 {
+		start = int(o)
+	}
+	if start > len(lines)+1 {
+		start = len(lines) + 1
+	}
+	end := len(lines)
+	if l, ok := args["limit"].(float64); ok && l > 0 && start-1+int(l) < end {
+		end = start - 1 + int(l)
+	}
+
 	return map[string]interface{}{
-		"path":    absPath,
-		"content": string(content),
-		"size":    len(content),
+		"path":        absPath,
+		"content":     strings.Join(lines[start-1:end], ""),
+		"size":        len(content),
+		"start_line":  start,
+		"end_line":    end,
+		"total_lines": len(lines),
 	}, nil
 }
 
@@ -284,29 +343,26 @@ func (t *RunCommandTool) Execute(ctx context.Context, args map[string]interface{
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
-	cmd := exec.CommandContext(ctx, "sh", "-c", command)
-
 	// Set working directory - use safe path resolution
+	var workDir string
 	if dir, ok := args["directory"].(string); ok {
 		if dir == "." || !filepath.IsAbs(dir) {
-			cmd.Dir = resolveSafePath(dir)
+			workDir = resolveSafePath(dir)
 		} else {
-			cmd.Dir = dir
+			workDir = dir
 		}
 	} else {
 		// Default to projects directory
-		cmd.Dir = resolveSafePath(".")
+		workDir = resolveSafePath(".")
 	}
 
 	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
-	err := cmd.Run()
+	err := runShell(ctx, workDir, command, &stdout, &stderr)
 
+	env, _ := ctx.Value(envKey{}).(map[string]string)
 	result := map[string]interface{}{
-		"stdout":   stdout.String(),
-		"stderr":   stderr.String(),
+		"stdout":   redactEnv(stdout.String(), env),
+		"stderr":   redactEnv(stderr.String(), env),
 		"exitCode": 0,
 	}
 
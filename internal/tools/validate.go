@@ -0,0 +1,161 @@
+package tools
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// ArgumentError reports tool arguments that don't match the tool's schema.
+// It's passed back to the model so it can correct the call.
+type ArgumentError struct {
+	Tool     string   `json:"tool"`
+	Problems []string `json:"problems"`
+}
+
+func (e *ArgumentError) Error() string {
+	return fmt.Sprintf("invalid arguments for %s: %s", e.Tool, strings.Join(e.Problems, "; "))
+}
+
+// ValidateArgs checks a tool call's arguments against the tool's schema and
+// returns an *ArgumentError listing every problem. It checks type, required,
+// properties and items. Allowed values are left to the tools, some of which
+// accept aliases, and arguments the schema doesn't mention are allowed.
+func ValidateArgs(t Tool, args map[string]interface{}) error {
+	var problems []string
+	validateValue("", args, Schema(t), &problems)
+	if len(problems) > 0 {
+		return &ArgumentError{Tool: t.Name(), Problems: problems}
+	}
+	return nil
+}
+
+func validateValue(path string, value interface{}, schema map[string]interface{}, problems *[]string) {
+	if types := schemaTypes(schema["type"]); len(types) > 0 {
+		matched := false
+		for _, typ := range types {
+			if hasType(value, typ) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			*problems = append(*problems, fmt.Sprintf("%s: expected %s, got %s", describePath(path), strings.Join(types, " or "), jsonType(value)))
+			return
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, name := range schemaStrings(schema["required"]) {
+			if _, ok := v[name]; !ok {
+				*problems = append(*problems, fmt.Sprintf("missing required field %q", joinPath(path, name)))
+			}
+		}
+		props, _ := schema["properties"].(map[string]interface{})
+		names := make([]string, 0, len(props))
+		for name := range props {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			propSchema, _ := props[name].(map[string]interface{})
+			if propValue, ok := v[name]; ok && propSchema != nil {
+				validateValue(joinPath(path, name), propValue, propSchema, problems)
+			}
+		}
+	case []interface{}:
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				validateValue(fmt.Sprintf("%s[%d]", path, i), item, items, problems)
+			}
+		}
+	}
+}
+
+// hasType reports whether a decoded JSON value is of a JSON schema type
+func hasType(value interface{}, typ string) bool {
+	switch typ {
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		n, ok := value.(float64)
+		return ok && n == math.Trunc(n)
+	case "null":
+		return value == nil
+	}
+	return true // Types we don't know aren't checked
+}
+
+// jsonType names the JSON type of a decoded value
+func jsonType(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// schemaTypes reads a schema's type, which may be one type or a list
+func schemaTypes(v interface{}) []string {
+	if s, ok := v.(string); ok {
+		return []string{s}
+	}
+	return schemaStrings(v)
+}
+
+// schemaStrings reads a list of strings from a schema, whether it was
+// written in Go or decoded from JSON
+func schemaStrings(v interface{}) []string {
+	switch list := v.(type) {
+	case []string:
+		return list
+	case []interface{}:
+		out := make([]string, 0, len(list))
+		for _, item := range list {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func describePath(path string) string {
+	if path == "" {
+		return "arguments"
+	}
+	return fmt.Sprintf("%q", path)
+}
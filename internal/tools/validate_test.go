@@ -0,0 +1,70 @@
+package tools
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// schemaStub is a tool with its own schema, as decoded from an MCP server
+type schemaStub struct {
+	ReadFileTool
+	schema string
+}
+
+func (s *schemaStub) Parameters() map[string]interface{} {
+	var schema map[string]interface{}
+	json.Unmarshal([]byte(s.schema), &schema)
+	return schema
+}
+
+func TestValidateArgs(t *testing.T) {
+	mcp := &schemaStub{schema: `{
+		"type": "object",
+		"properties": {
+			"tags": {"type": "array", "items": {"type": "string"}},
+			"limit": {"type": ["integer", "null"]}
+		},
+		"required": ["tags"]
+	}`}
+
+	tests := []struct {
+		name     string
+		tool     Tool
+		args     string
+		problems []string
+	}{
+		{"valid", &RunCommandTool{}, `{"command":"ls","timeout":30}`, nil},
+		{"extra args allowed", &RunCommandTool{}, `{"command":"ls","verbose":true}`, nil},
+		{"missing required", &RunCommandTool{}, `{"timeout":30}`, []string{`missing required field "command"`}},
+		{"wrong type", &RunCommandTool{}, `{"command":"ls","timeout":"30"}`, []string{`"timeout": expected number, got string`}},
+		{"several problems", &ReadFileTool{}, `{"offset":1.5}`, []string{`missing required field "path"`, `"offset": expected integer, got number`}},
+		{"own schema", mcp, `{"tags":["a",2],"limit":null}`, []string{`"tags[1]": expected string, got number`}},
+		{"type list", mcp, `{"tags":[],"limit":"10"}`, []string{`"limit": expected integer or null, got string`}},
+		{"no schema", &CreateDocTool{}, `{"anything":1}`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var args map[string]interface{}
+			if err := json.Unmarshal([]byte(tt.args), &args); err != nil {
+				t.Fatal(err)
+			}
+
+			err := ValidateArgs(tt.tool, args)
+			if tt.problems == nil {
+				if err != nil {
+					t.Errorf("Expected valid arguments, got %v", err)
+				}
+				return
+			}
+			var argErr *ArgumentError
+			if !errors.As(err, &argErr) {
+				t.Fatalf("Expected an ArgumentError, got %v", err)
+			}
+			if argErr.Tool != tt.tool.Name() || !reflect.DeepEqual(argErr.Problems, tt.problems) {
+				t.Errorf("Expected problems %q with %s, got %q with %s", tt.problems, tt.tool.Name(), argErr.Problems, argErr.Tool)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+// Package version holds the Ugudu release version shared by the CLI and
+// daemon
+package version
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// Set at build time with -ldflags "-X github.com/arcslash/ugudu/internal/version.Version=...",
+// likewise Commit and Date. Builds without them report the fallbacks below.
+var (
+	// Version is the Ugudu release version, e.g. v0.2.0
+	Version = "dev"
+	// Commit is the git commit the binary was built from
+	Commit = ""
+	// Date is when the binary was built, in RFC 3339
+	Date = ""
+)
+
+// Unknown stands in for build details nobody recorded
+const Unknown = "unknown"
+
+// Info describes the running binary
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	Date      string `json:"date"`
+	GoVersion string `json:"go_version"`
+}
+
+// Get returns the build details. Commit and date not set at build time come
+// from the Go toolchain's VCS stamp, if the binary has one, or are Unknown.
+func Get() Info {
+	info := Info{
+		Version:   Version,
+		Commit:    Commit,
+		Date:      Date,
+		GoVersion: runtime.Version(),
+	}
+	if info.Version == "" {
+		info.Version = "dev"
+	}
+
+	if build, ok := debug.ReadBuildInfo(); ok {
+		for _, s := range build.Settings {
+			switch {
+			case s.Key == "vcs.revision" && info.Commit == "":
+				info.Commit = s.Value
+			case s.Key == "vcs.time" && info.Date == "":
+				info.Date = s.Value
+			}
+		}
+	}
+	if info.Commit == "" {
+		info.Commit = Unknown
+	}
+	if info.Date == "" {
+		info.Date = Unknown
+	}
+	return info
+}
+
+// Mismatch reports whether two binaries' builds differ: a different version,
+// or a different commit when both are known
+func Mismatch(a, b Info) bool {
+	if a.Version != b.Version {
+		return true
+	}
+	return a.Commit != Unknown && b.Commit != Unknown && a.Commit != b.Commit
+}
+
+// ShortCommit abbreviates the commit hash for display
+func (i Info) ShortCommit() string {
+	if len(i.Commit) > 12 {
+		return i.Commit[:12]
+	}
+	return i.Commit
+}
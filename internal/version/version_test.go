@@ -0,0 +1,42 @@
+package version
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestGet_Fallbacks(t *testing.T) {
+	// Test binaries carry no ldflags or VCS stamp
+	info := Get()
+	if info.Version != "dev" || info.Commit != Unknown || info.Date != Unknown {
+		t.Errorf("Expected dev/unknown fallbacks, got %+v", info)
+	}
+	if info.GoVersion != runtime.Version() {
+		t.Errorf("Expected Go version %s, got %s", runtime.Version(), info.GoVersion)
+	}
+
+	// Values set at build time win
+	defer func(v, c, d string) { Version, Commit, Date = v, c, d }(Version, Commit, Date)
+	Version, Commit, Date = "v1.2.3", "0123456789abcdef", "2026-01-02T03:04:05Z"
+	info = Get()
+	if info.Version != "v1.2.3" || info.ShortCommit() != "0123456789ab" || info.Date != "2026-01-02T03:04:05Z" {
+		t.Errorf("Expected the build-time values, got %+v", info)
+	}
+}
+
+func TestMismatch(t *testing.T) {
+	tests := []struct {
+		a, b Info
+		want bool
+	}{
+		{Info{Version: "v1.0.0", Commit: "abc"}, Info{Version: "v1.0.0", Commit: "abc"}, false},
+		{Info{Version: "v1.0.0", Commit: "abc"}, Info{Version: "v1.1.0", Commit: "abc"}, true},
+		{Info{Version: "dev", Commit: "abc"}, Info{Version: "dev", Commit: "def"}, true},
+		{Info{Version: "dev", Commit: "abc"}, Info{Version: "dev", Commit: Unknown}, false},
+	}
+	for _, tt := range tests {
+		if got := Mismatch(tt.a, tt.b); got != tt.want {
+			t.Errorf("Mismatch(%+v, %+v) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
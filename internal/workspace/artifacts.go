@@ -0,0 +1,233 @@
+package workspace
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ArtifactsFile, in a project's directory, records the files agents wrote
+const ArtifactsFile = "artifacts.json"
+
+// ErrArtifactNotFound is returned when opening an artifact the project
+// doesn't have, or whose file is gone
+var ErrArtifactNotFound = errors.New("artifact not found")
+
+// artifactsMu serializes updates to every project's artifacts file; agents
+// record files from their own goroutines
+var artifactsMu sync.Mutex
+
+// Artifact is a file a team produced in a project: one an agent wrote, or a
+// report, spec or test result in the project's artifacts directory
+type Artifact struct {
+	ID        string    `json:"id"`
+	Path      string    `json:"path"` // Relative to its project, sandbox or source directory
+	Type      string    `json:"type"` // "file", or the artifacts subdirectory, e.g. reports
+	CreatedBy string    `json:"created_by,omitempty"`
+	Size      int64     `json:"size"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Stale     bool      `json:"stale,omitempty"` // Recorded, but since deleted
+
+	file string // Absolute path on disk
+}
+
+// artifactRecord is an entry in ArtifactsFile
+type artifactRecord struct {
+	Path       string    `json:"path"`
+	CreatedBy  string    `json:"created_by"`
+	RecordedAt time.Time `json:"recorded_at"`
+}
+
+// RecordArtifact notes a file an agent wrote so it can be listed and
+// downloaded after the run. Writing the same file again updates its author.
+func (w *Workspace) RecordArtifact(path, createdBy string) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+
+	artifactsMu.Lock()
+	defer artifactsMu.Unlock()
+
+	records, err := w.loadArtifactRecords()
+	if err != nil {
+		return err
+	}
+	record := artifactRecord{Path: abs, CreatedBy: createdBy, RecordedAt: time.Now()}
+	found := false
+	for i := range records {
+		if records[i].Path == abs {
+			records[i] = record
+			found = true
+		}
+	}
+	if !found {
+		records = append(records, record)
+	}
+
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal artifacts: %w", err)
+	}
+	return os.WriteFile(filepath.Join(w.Path, ArtifactsFile), data, 0644)
+}
+
+func (w *Workspace) loadArtifactRecords() ([]artifactRecord, error) {
+	data, err := os.ReadFile(filepath.Join(w.Path, ArtifactsFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read artifacts: %w", err)
+	}
+	var records []artifactRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("parse artifacts: %w", err)
+	}
+	return records, nil
+}
+
+// Artifacts lists the files agents wrote, marking any since deleted as stale,
+// followed by everything in the project's artifacts directory. Files the
+// project can't serve, e.g. outside its directories or in .ugudu-ignore, are
+// left out.
+func (w *Workspace) Artifacts() ([]Artifact, error) {
+	artifactsMu.Lock()
+	records, err := w.loadArtifactRecords()
+	artifactsMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	artifacts := make([]Artifact, 0, len(records))
+	seen := make(map[string]bool)
+	for _, r := range records {
+		rel, ok := w.servable(r.Path)
+		if !ok {
+			continue
+		}
+		a := Artifact{
+			ID:        artifactID(r.Path),
+			Path:      rel,
+			Type:      "file",
+			CreatedBy: r.CreatedBy,
+			UpdatedAt: r.RecordedAt,
+			file:      r.Path,
+		}
+		if info, err := os.Stat(r.Path); err == nil && info.Mode().IsRegular() {
+			a.Size = info.Size()
+			a.UpdatedAt = info.ModTime()
+		} else {
+			a.Stale = true
+		}
+		artifacts = append(artifacts, a)
+		seen[r.Path] = true
+	}
+
+	root := w.ArtifactPath("")
+	var generated []Artifact
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || seen[path] {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil || !info.Mode().IsRegular() {
+			return nil
+		}
+		rel, _ := filepath.Rel(root, path)
+		artifactType := "file"
+		if dir, _, ok := strings.Cut(filepath.ToSlash(rel), "/"); ok {
+			artifactType = dir
+		}
+		generated = append(generated, Artifact{
+			ID:        artifactID(path),
+			Path:      filepath.ToSlash(filepath.Join("artifacts", rel)),
+			Type:      artifactType,
+			Size:      info.Size(),
+			UpdatedAt: info.ModTime(),
+			file:      path,
+		})
+		return nil
+	})
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("list artifacts: %w", err)
+	}
+	sort.Slice(generated, func(i, j int) bool { return generated[i].Path < generated[j].Path })
+
+	return append(artifacts, generated...), nil
+}
+
+// OpenArtifact opens an artifact's file for download. The caller closes it.
+func (w *Workspace) OpenArtifact(id string) (*os.File, Artifact, error) {
+	artifacts, err := w.Artifacts()
+	if err != nil {
+		return nil, Artifact{}, err
+	}
+	for _, a := range artifacts {
+		if a.ID != id {
+			continue
+		}
+		if a.Stale {
+			return nil, a, fmt.Errorf("%w: %s was deleted", ErrArtifactNotFound, a.Path)
+		}
+		// Resolve links again at open time, so one swapped in since listing
+		// can't point outside the project
+		real, err := filepath.EvalSymlinks(a.file)
+		if err != nil {
+			return nil, a, fmt.Errorf("%w: %s", ErrArtifactNotFound, a.Path)
+		}
+		if _, ok := w.servable(real); !ok {
+			return nil, a, fmt.Errorf("%w: %s", ErrAccessDenied, a.Path)
+		}
+		f, err := os.Open(real)
+		if err != nil {
+			return nil, a, fmt.Errorf("%w: %s", ErrArtifactNotFound, a.Path)
+		}
+		return f, a, nil
+	}
+	return nil, Artifact{}, fmt.Errorf("%w: %s", ErrArtifactNotFound, id)
+}
+
+// servable reports whether a file is in the project's directories, its
+// source or shared paths, and not ignored, returning its path relative to
+// the directory it's in
+func (w *Workspace) servable(path string) (string, bool) {
+	var roots []string
+	for _, root := range append([]string{w.Path, w.Config.Source.Path}, w.Config.Source.SharedPaths...) {
+		if root == "" {
+			continue
+		}
+		roots = append(roots, root)
+		if real, err := filepath.EvalSymlinks(root); err == nil && real != root {
+			roots = append(roots, real)
+		}
+	}
+
+	for _, root := range roots {
+		rel, err := filepath.Rel(root, path)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		if ignored(LoadIgnore(w.Config.Source.Path), rel) {
+			return "", false
+		}
+		return filepath.ToSlash(rel), true
+	}
+	return "", false
+}
+
+// artifactID identifies an artifact in download links without exposing its
+// path on disk
+func artifactID(path string) string {
+	sum := sha256.Sum256([]byte(path))
+	return hex.EncodeToString(sum[:6])
+}
@@ -0,0 +1,74 @@
+package workspace
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// IgnoreFile lists paths in a project's source that agents may not read or
+// write, one pattern per line
+const IgnoreFile = ".ugudu-ignore"
+
+// DefaultIgnore is written by `ugudu init` for new projects
+const DefaultIgnore = `# Paths agents may not read or write, relative to the project root.
+# One pattern per line. A pattern naming a directory covers everything in it;
+# patterns without a / match a file or directory name at any depth.
+.git/
+.env
+.env.*
+*.pem
+*.key
+id_rsa*
+.ugudu-ignore
+`
+
+// LoadIgnore reads the ignore patterns in dir's .ugudu-ignore. A missing
+// file means nothing is ignored.
+func LoadIgnore(dir string) []string {
+	f, err := os.Open(filepath.Join(dir, IgnoreFile))
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var patterns []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, line)
+	}
+	return patterns
+}
+
+// ignored reports whether a clean path relative to the project root matches
+// any of the patterns. A pattern matching a directory covers everything in it.
+func ignored(patterns []string, path string) bool {
+	parts := strings.Split(filepath.ToSlash(path), "/")
+
+	for _, pattern := range patterns {
+		pattern = strings.TrimSuffix(pattern, "/")
+
+		if strings.Contains(pattern, "/") {
+			// Anchored at the root: match the path or one of its parents
+			for i := range parts {
+				if ok, _ := filepath.Match(pattern, strings.Join(parts[:i+1], "/")); ok {
+					return true
+				}
+			}
+			continue
+		}
+
+		// A bare name matches at any depth
+		for _, part := range parts {
+			if ok, _ := filepath.Match(pattern, part); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -21,6 +21,7 @@ type Sandbox struct {
 	sourcePath  string   // Primary source code location (read-only for agents)
 	sharedPaths []string // Additional readable paths
 	isolation   string   // Isolation mode
+	ignore      []string // Patterns from the source's .ugudu-ignore
 }
 
 // NewSandbox creates a new sandbox instance
@@ -47,6 +48,10 @@ func (s *Sandbox) SourcePath() string {
 // For write operations: only sandbox is writable
 // For read operations: checks sandbox first, then source, then shared paths
 func (s *Sandbox) ResolvePath(op, path string) (string, error) {
+	if s.isIgnored(path) {
+		return "", ErrAccessDenied
+	}
+
 	// If isolation is none, allow direct access
 	if s.isolation == IsolationNone {
 		return path, nil
@@ -112,6 +117,10 @@ func (s *Sandbox) resolveReadPath(path string) (string, error) {
 
 // ResolveAbsolutePath resolves an absolute path, checking if it's within allowed paths
 func (s *Sandbox) ResolveAbsolutePath(op, absPath string) (string, error) {
+	if s.isIgnored(absPath) {
+		return "", ErrAccessDenied
+	}
+
 	// If isolation is none, allow direct access
 	if s.isolation == IsolationNone {
 		return absPath, nil
@@ -198,6 +207,20 @@ func (s *Sandbox) toRelativePath(absPath string) string {
 	return absPath
 }
 
+// isIgnored reports whether a path is listed in the project's .ugudu-ignore
+func (s *Sandbox) isIgnored(path string) bool {
+	if len(s.ignore) == 0 {
+		return false
+	}
+	if filepath.IsAbs(path) {
+		path = s.toRelativePath(path)
+		if filepath.IsAbs(path) {
+			return false
+		}
+	}
+	return ignored(s.ignore, filepath.Clean(path))
+}
+
 // exists checks if a path exists
 func exists(path string) bool {
 	_, err := os.Stat(path)
@@ -25,6 +25,10 @@ type StandupReport struct {
 	Highlights    []string        `json:"highlights"`
 	Blockers      []string        `json:"blockers"`
 	NextSteps     []string        `json:"next_steps"`
+
+	// Prose summary of the period written by a model, when one was asked for
+	Summary      string `json:"summary,omitempty"`
+	SummaryError string `json:"summary_error,omitempty"` // Why the summary is missing
 }
 
 // TeamSummary provides an overview of team activity
@@ -1,20 +1,29 @@
 package workspace
 
 import (
+	"context"
 	"fmt"
 	"sort"
 	"strings"
 	"time"
+
+	"github.com/arcslash/ugudu/internal/provider"
 )
 
 // StandupGenerator generates standup reports from activity data
 type StandupGenerator struct {
-	workspace *Workspace
+	workspace    *Workspace
+	summarizer   provider.Provider
+	summaryModel string
 }
 
 // NewStandupGenerator creates a new standup generator
-func NewStandupGenerator(ws *Workspace) *StandupGenerator {
-	return &StandupGenerator{workspace: ws}
+func NewStandupGenerator(ws *Workspace, opts ...StandupOption) *StandupGenerator {
+	g := &StandupGenerator{workspace: ws}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g
 }
 
 // Generate creates a standup report for the given period
@@ -54,6 +63,17 @@ func (g *StandupGenerator) Generate(period StandupPeriod) (*StandupReport, error
 	report.Blockers = g.extractBlockers(activities, tasks)
 	report.NextSteps = g.suggestNextSteps(tasks)
 
+	if g.summarizer != nil && len(activities) > 0 {
+		ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultRequestTimeout)
+		summary, err := g.summarize(ctx, report, activities)
+		cancel()
+		if err != nil {
+			report.SummaryError = err.Error()
+		} else {
+			report.Summary = summary
+		}
+	}
+
 	return report, nil
 }
 
@@ -357,6 +377,12 @@ func (g *StandupGenerator) FormatReport(report *StandupReport, detailed bool) st
 		report.PeriodEnd.Format("2006-01-02 15:04")))
 	sb.WriteString(fmt.Sprintf("**Generated:** %s\n\n", report.GeneratedAt.Format(time.RFC3339)))
 
+	if report.Summary != "" {
+		sb.WriteString("## Summary\n\n")
+		sb.WriteString(report.Summary)
+		sb.WriteString("\n\n")
+	}
+
 	// Team Summary
 	sb.WriteString("## Team Summary\n\n")
 	sb.WriteString(fmt.Sprintf("- Active Members: %d\n", report.TeamSummary.ActiveMembers))
@@ -0,0 +1,105 @@
+package workspace
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/arcslash/ugudu/internal/provider"
+)
+
+// stubProvider answers every chat with reply, or fails with err
+type stubProvider struct {
+	reply    string
+	err      error
+	requests []*provider.ChatRequest
+}
+
+func (p *stubProvider) ID() string                   { return "stub" }
+func (p *stubProvider) Name() string                 { return "Stub" }
+func (p *stubProvider) Ping(_ context.Context) error { return nil }
+func (p *stubProvider) ListModels(_ context.Context) ([]provider.ModelInfo, error) {
+	return nil, nil
+}
+func (p *stubProvider) Stream(_ context.Context, _ *provider.ChatRequest) (<-chan provider.StreamChunk, error) {
+	return nil, errors.New("not supported")
+}
+func (p *stubProvider) Chat(_ context.Context, req *provider.ChatRequest) (*provider.ChatResponse, error) {
+	p.requests = append(p.requests, req)
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &provider.ChatResponse{Content: p.reply}, nil
+}
+
+func newTestWorkspace(t *testing.T) *Workspace {
+	t.Helper()
+	t.Setenv("UGUDU_HOME", t.TempDir())
+	t.Setenv("UGUDU_PROJECTS", t.TempDir())
+
+	// The project index is process-wide, so each test needs its own name
+	ws, err := Init(t.Name(), t.TempDir(), "dev-team")
+	if err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+
+	activity, err := NewActivityLogger(ws, "pm")
+	if err != nil {
+		t.Fatalf("NewActivityLogger failed: %v", err)
+	}
+	defer activity.Close()
+	if err := activity.Log(DelegationActivity("pm", "pm", "engineer", "task-1", "Implement login")); err != nil {
+		t.Fatalf("Log failed: %v", err)
+	}
+	return ws
+}
+
+func TestStandupGenerator_Summary(t *testing.T) {
+	ws := newTestWorkspace(t)
+	stub := &stubProvider{reply: "  The team started on login.  "}
+
+	report, err := NewStandupGenerator(ws, WithSummarizer(stub, "stub-small")).Generate(PeriodDaily)
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if report.Summary != "The team started on login." {
+		t.Errorf("Expected the model's summary, got %q", report.Summary)
+	}
+	if len(stub.requests) != 1 {
+		t.Fatalf("Expected 1 model call, got %d", len(stub.requests))
+	}
+	req := stub.requests[0]
+	if req.Model != "stub-small" {
+		t.Errorf("Expected model stub-small, got %s", req.Model)
+	}
+	if prompt := req.Messages[len(req.Messages)-1].Content; !strings.Contains(prompt, "delegated to engineer: Implement login") {
+		t.Errorf("Expected the activity in the prompt, got %q", prompt)
+	}
+	if !strings.Contains(NewStandupGenerator(ws).FormatReport(report, false), "## Summary") {
+		t.Error("Expected the summary in the formatted report")
+	}
+}
+
+func TestStandupGenerator_SummaryFallback(t *testing.T) {
+	ws := newTestWorkspace(t)
+
+	// Without a summarizer the report is structured only
+	report, err := NewStandupGenerator(ws).Generate(PeriodDaily)
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if report.Summary != "" || report.TeamSummary.TotalDelegations != 1 {
+		t.Errorf("Expected a structured report only, got %+v", report)
+	}
+
+	// A failed model call still returns the structured report
+	stub := &stubProvider{err: errors.New("rate limited")}
+	report, err = NewStandupGenerator(ws, WithSummarizer(stub, "stub-small")).Generate(PeriodDaily)
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if report.Summary != "" || report.SummaryError != "rate limited" || report.TeamSummary.TotalDelegations != 1 {
+		t.Errorf("Expected the structured report with the summary error, got %+v", report)
+	}
+}
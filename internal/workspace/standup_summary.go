@@ -0,0 +1,92 @@
+package workspace
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/arcslash/ugudu/internal/provider"
+)
+
+// maxSummaryActivities caps how many activity entries are sent to the model,
+// keeping the most recent
+const maxSummaryActivities = 200
+
+// summaryMaxTokens bounds the length of a generated standup summary
+const summaryMaxTokens = 600
+
+// StandupOption configures a StandupGenerator
+type StandupOption func(*StandupGenerator)
+
+// WithSummarizer has the generator add a prose summary of the period's work,
+// written by the given model, to each report. Without one, or if the model
+// call fails, reports carry only the structured data.
+func WithSummarizer(p provider.Provider, model string) StandupOption {
+	return func(g *StandupGenerator) {
+		g.summarizer = p
+		g.summaryModel = model
+	}
+}
+
+// summarize asks the summarizer for a narrative standup covering the period's
+// activity
+func (g *StandupGenerator) summarize(ctx context.Context, report *StandupReport, activities []ActivityEntry) (string, error) {
+	if len(activities) > maxSummaryActivities {
+		activities = activities[len(activities)-maxSummaryActivities:]
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Project: %s\nPeriod: %s (%s - %s)\n\n",
+		report.ProjectName,
+		report.Period,
+		report.PeriodStart.Format("2006-01-02 15:04"),
+		report.PeriodEnd.Format("2006-01-02 15:04")))
+	sb.WriteString(fmt.Sprintf("Tasks: %d completed this period, %d in progress, %d pending, %d blocked\n\n",
+		report.TaskSummary.CompletedPeriod,
+		report.TaskSummary.InProgress,
+		report.TaskSummary.Pending,
+		report.TaskSummary.Blocked))
+	sb.WriteString("Activity:\n")
+	for _, a := range activities {
+		sb.WriteString(fmt.Sprintf("- %s %s %s\n", a.Timestamp.Format("01-02 15:04"), a.AgentRole, describeActivity(a)))
+	}
+
+	maxTokens := summaryMaxTokens
+	resp, err := g.summarizer.Chat(ctx, &provider.ChatRequest{
+		Model: g.summaryModel,
+		Messages: []provider.Message{
+			{Role: "system", Content: "You write standup summaries for a software team. Given the team's activity log, write a short narrative of what the team got done, what is in progress, and anything blocking it, in plain prose addressed to the team. Mention roles, not IDs. Don't invent work that isn't in the log."},
+			{Role: "user", Content: sb.String()},
+		},
+		MaxTokens: &maxTokens,
+	})
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(resp.Content), nil
+}
+
+// describeActivity renders an activity entry as one line of the summary prompt
+func describeActivity(a ActivityEntry) string {
+	var desc string
+	switch a.Type {
+	case ActivityToolCall:
+		desc = fmt.Sprintf("used %v", a.Data["tool"])
+	case ActivityDelegation:
+		desc = fmt.Sprintf("delegated to %v: %v", a.Data["to_role"], a.Data["message"])
+	case ActivityTaskUpdate:
+		desc = fmt.Sprintf("moved task %s from %v to %v", a.TaskID, a.Data["old_status"], a.Data["new_status"])
+	case ActivityMessage:
+		desc = fmt.Sprintf("%v message (%v): %v", a.Data["direction"], a.Data["to_from"], a.Data["content"])
+	case ActivityProgress:
+		desc = fmt.Sprintf("reported %v%% (%v)", a.Data["percent_complete"], a.Data["status"])
+	case ActivityError:
+		desc = fmt.Sprintf("hit an error in %v", a.Data["context"])
+	default:
+		desc = string(a.Type)
+	}
+	if a.Error != "" {
+		desc += ": " + a.Error
+	}
+	return desc
+}
@@ -37,9 +37,10 @@ type TaskComment struct {
 // TaskStore provides access to task storage
 type TaskStore struct {
 	path     string
-	mu       sync.RWMutex
+	mu       sync.Mutex
 	tasks    []Task
 	loaded   bool
+	modTime  time.Time // File mtime when last read, to notice outside writes
 	logger   *ActivityLogger
 }
 
@@ -58,9 +59,11 @@ func NewTaskStoreWithLogger(ws *Workspace, logger *ActivityLogger) *TaskStore {
 	}
 }
 
-// load reads tasks from disk
+// load reads tasks from disk, rereading if another store has written the
+// file since. Callers must hold the write lock.
 func (s *TaskStore) load() error {
-	if s.loaded {
+	info, err := os.Stat(s.path)
+	if s.loaded && (err != nil || info.ModTime().Equal(s.modTime)) {
 		return nil
 	}
 
@@ -74,11 +77,16 @@ func (s *TaskStore) load() error {
 		return fmt.Errorf("read tasks: %w", err)
 	}
 
-	if err := json.Unmarshal(data, &s.tasks); err != nil {
+	var tasks []Task
+	if err := json.Unmarshal(data, &tasks); err != nil {
 		return fmt.Errorf("parse tasks: %w", err)
 	}
 
+	s.tasks = tasks
 	s.loaded = true
+	if info != nil {
+		s.modTime = info.ModTime()
+	}
 	return nil
 }
 
@@ -93,13 +101,19 @@ func (s *TaskStore) save() error {
 		return fmt.Errorf("marshal tasks: %w", err)
 	}
 
-	return os.WriteFile(s.path, data, 0644)
+	if err := os.WriteFile(s.path, data, 0644); err != nil {
+		return err
+	}
+	if info, err := os.Stat(s.path); err == nil {
+		s.modTime = info.ModTime()
+	}
+	return nil
 }
 
 // List returns all tasks
 func (s *TaskStore) List() ([]Task, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
 	if err := s.load(); err != nil {
 		return nil, err
@@ -112,8 +126,8 @@ func (s *TaskStore) List() ([]Task, error) {
 
 // ListByStatus returns tasks with the given status
 func (s *TaskStore) ListByStatus(status string) ([]Task, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
 	if err := s.load(); err != nil {
 		return nil, err
@@ -130,8 +144,8 @@ func (s *TaskStore) ListByStatus(status string) ([]Task, error) {
 
 // ListByAssignee returns tasks assigned to the given role
 func (s *TaskStore) ListByAssignee(assignee string) ([]Task, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
 	if err := s.load(); err != nil {
 		return nil, err
@@ -148,8 +162,8 @@ func (s *TaskStore) ListByAssignee(assignee string) ([]Task, error) {
 
 // Get returns a task by ID
 func (s *TaskStore) Get(id string) (*Task, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
 	if err := s.load(); err != nil {
 		return nil, err
@@ -290,8 +304,8 @@ type TaskStats struct {
 
 // Stats returns statistics about tasks
 func (s *TaskStore) Stats() (*TaskStats, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
 	if err := s.load(); err != nil {
 		return nil, err
@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/arcslash/ugudu/internal/config"
 )
@@ -15,6 +16,9 @@ type Workspace struct {
 	Path       string
 	Config     *ProjectConfig
 	sandboxes  map[string]*Sandbox // role -> sandbox
+
+	tasks     *TaskStore
+	tasksOnce sync.Once
 }
 
 // New creates a new workspace for an existing project
@@ -150,6 +154,7 @@ func (w *Workspace) GetSandbox(role string) *Sandbox {
 		w.Config.Source.SharedPaths,
 		w.Config.Workspace.Isolation,
 	)
+	sandbox.ignore = LoadIgnore(w.Config.Source.Path)
 	w.sandboxes[role] = sandbox
 
 	// Ensure sandbox directory exists
@@ -168,6 +173,15 @@ func (w *Workspace) TasksPath() string {
 	return filepath.Join(w.Path, "tasks", "tasks.json")
 }
 
+// Tasks returns the project's task board. Everything working in this
+// workspace shares the one store, so agents and the board see the same tasks.
+func (w *Workspace) Tasks() *TaskStore {
+	w.tasksOnce.Do(func() {
+		w.tasks = NewTaskStore(w)
+	})
+	return w.tasks
+}
+
 // ActivityPath returns the path to the activity log for a role
 func (w *Workspace) ActivityPath(role string) string {
 	return filepath.Join(w.Path, "activity", role+".log")
@@ -12,7 +12,9 @@ async function request<T>(endpoint: string, options: RequestInit = {}): Promise<
 
   if (!res.ok) {
     const err = await res.json().catch(() => ({ error: res.statusText }));
-    throw new Error(err.error || res.statusText);
+    // Errors are {error: {code, message, details}}; older daemons sent a string
+    const message = typeof err.error === 'string' ? err.error : err.error?.message;
+    throw new Error(message || res.statusText);
   }
 
   return res.json();
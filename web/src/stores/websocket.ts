@@ -5,7 +5,9 @@ import { teamMembers, addMessage, currentTeamName, wasRecentlyAdded, teams, spec
 import { getTeams, getTeamMembers, getSpecs } from '../lib/api';
 
 export interface WSEvent {
-  type: 'member_status' | 'activity' | 'task_update' | 'chat' | 'team_update' | 'spec_update' | 'settings_update';
+  seq: number; // 0 for resync, which isn't part of the sequence
+  epoch: string; // Changes when the daemon restarts and seq starts again
+  type: 'member_status' | 'activity' | 'task_update' | 'chat' | 'team_update' | 'spec_update' | 'settings_update' | 'resync';
   team: string;
   member_id?: string;
   status?: string;
@@ -27,6 +29,10 @@ let reconnectAttempts = 0;
 const MAX_RECONNECT_ATTEMPTS = 10;
 const RECONNECT_DELAY_BASE = 1000;
 
+// Seq of the last event handled; a reconnect asks the daemon for anything after it
+let lastSeq = 0;
+let lastEpoch = '';
+
 export function connectWebSocket() {
   if (ws && ws.readyState === WebSocket.OPEN) {
     return;
@@ -34,7 +40,8 @@ export function connectWebSocket() {
 
   // Determine WebSocket URL based on current location
   const protocol = window.location.protocol === 'https:' ? 'wss:' : 'ws:';
-  const wsUrl = `${protocol}//${window.location.host}/api/ws`;
+  const cursor = lastSeq > 0 ? `?since=${lastSeq}&epoch=${encodeURIComponent(lastEpoch)}` : '';
+  const wsUrl = `${protocol}//${window.location.host}/api/ws${cursor}`;
 
   try {
     ws = new WebSocket(wsUrl);
@@ -90,6 +97,15 @@ function scheduleReconnect() {
 }
 
 function handleWSEvent(event: WSEvent) {
+  if (event.epoch && event.epoch !== lastEpoch) {
+    // A restarted daemon counts seq from 1 again
+    lastEpoch = event.epoch;
+    lastSeq = 0;
+  }
+  if (event.seq) {
+    if (event.seq <= lastSeq) return; // Already handled before a reconnect
+    lastSeq = event.seq;
+  }
   wsLastEvent.set(event);
   console.log('[WS] Event received:', event.type, event.team || '', event.message || '');
 
@@ -116,6 +132,27 @@ function handleWSEvent(event: WSEvent) {
       // Could refresh settings here if needed
       console.log('[WS] Settings updated:', event.message);
       break;
+    case 'resync':
+      handleResync();
+      break;
+  }
+}
+
+// handleResync reloads state after a gap the daemon could no longer replay,
+// e.g. a long disconnect or a daemon restart
+async function handleResync() {
+  lastSeq = 0;
+  try {
+    const [teamList, specList] = await Promise.all([getTeams(), getSpecs()]);
+    teams.set(teamList);
+    specs.set(specList);
+    const teamName = get(currentTeamName);
+    if (teamName) {
+      const data = await getTeamMembers(teamName);
+      teamMembers.set(data.members || []);
+    }
+  } catch (e) {
+    console.error('[WS] Failed to resync:', e);
   }
 }
 
@@ -3,7 +3,7 @@
 export interface Team {
   name: string;
   description?: string;
-  status: 'running' | 'stopped';
+  status: 'running' | 'stopped' | 'paused' | 'auto_stopped';
   member_count: number;
   members: Member[];
   client_facing: string[];
@@ -63,9 +63,19 @@ export interface ChatResponse {
     content: string;
     type: string;
     role?: string;
+    internal?: boolean;
+    progress?: boolean;
+    model?: string;
+    provider?: string;
   }>;
   response?: string;
-  error?: string;
+  error?: ApiError;
+}
+
+export interface ApiError {
+  code: 'NOT_FOUND' | 'VALIDATION' | 'PROVIDER_ERROR' | 'RATE_LIMITED' | 'CONFLICT' | 'INTERNAL';
+  message: string;
+  details?: Record<string, unknown>;
 }
 
 export interface Provider {